@@ -178,7 +178,7 @@ func Test_ResolveCodeAction_UnknownCommandIsReported(t *testing.T) {
 	testutil.UnitTest(t)
 	// Arrange
 	service := setupService()
-	command.SetService(command.NewService(nil, nil, nil, nil, nil))
+	command.SetService(command.NewService(nil, nil, nil, nil, nil, nil))
 
 	id := lsp.CodeActionData(uuid.New())
 	c := &sglsp.Command{
@@ -32,6 +32,8 @@ const (
 	SendErrorReportsKey      = "SEND_ERROR_REPORTS"
 	Organization             = "VULNMAP_CFG_ORG"
 	EnableTelemetry          = "VULNMAP_CFG_DISABLE_ANALYTICS"
+	// DoNotTrack is the conventional environment variable to opt out of all telemetry, e.g. DO_NOT_TRACK=1
+	DoNotTrack = "DO_NOT_TRACK"
 )
 
 func (c *Config) clientSettingsFromEnv() {
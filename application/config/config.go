@@ -17,6 +17,8 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -63,8 +65,279 @@ const (
 	DefaultDeeproxyApiUrl = "https://deeproxy.vulnmap.khulnasoft.com"
 	pathListSeparator     = string(os.PathListSeparator)
 	windows               = "windows"
+	// DefaultLearnLessonLookupConcurrency is the default number of parallel learn lesson lookups
+	DefaultLearnLessonLookupConcurrency = 8
+	// DefaultDiagnosticsPublishConcurrency is the default number of files whose diagnostics are published in parallel
+	DefaultDiagnosticsPublishConcurrency = 4
+	// DiagnosticsCloseGracePeriodNever keeps the diagnostics of closed files until the file is rescanned or deleted
+	DiagnosticsCloseGracePeriodNever = time.Duration(-1)
+	// DefaultDiagnosticsCloseGracePeriod is the default time the diagnostics of a closed file are kept
+	DefaultDiagnosticsCloseGracePeriod = DiagnosticsCloseGracePeriodNever
+	// recentLogLinesBufferSize is the number of log lines retained in memory when not logging to a file
+	recentLogLinesBufferSize = 1000
 )
 
+// Subsystems with their own log level, see SetSubsystemLogLevels
+const (
+	LogSubsystemCli  = "cli"
+	LogSubsystemAuth = "auth"
+	LogSubsystemScan = "scan"
+)
+
+// NotificationLevel determines which ShowMessage notifications are shown to the user
+type NotificationLevel string
+
+const (
+	// NotificationLevelSilent shows no messages
+	NotificationLevelSilent NotificationLevel = "silent"
+	// NotificationLevelErrors shows error messages only
+	NotificationLevelErrors NotificationLevel = "errors"
+	// NotificationLevelWarnings shows error and warning messages
+	NotificationLevelWarnings NotificationLevel = "warnings"
+	// NotificationLevelAll shows all messages
+	NotificationLevelAll NotificationLevel = "all"
+)
+
+// ParseNotificationLevel returns the notification level with the given (case-insensitive) name
+func ParseNotificationLevel(level string) (NotificationLevel, bool) {
+	switch notificationLevel := NotificationLevel(strings.ToLower(level)); notificationLevel {
+	case NotificationLevelSilent, NotificationLevelErrors, NotificationLevelWarnings, NotificationLevelAll:
+		return notificationLevel, true
+	default:
+		return "", false
+	}
+}
+
+// LinkStyle determines how links, e.g. to CVEs and CWEs, are rendered in issue messages
+type LinkStyle string
+
+const (
+	// LinkStyleMarkdown renders markdown links
+	LinkStyleMarkdown LinkStyle = "markdown"
+	// LinkStyleUrl renders the link text followed by the bare URL, for clients that don't render markdown links
+	LinkStyleUrl LinkStyle = "url"
+	// LinkStyleText renders the link text only
+	LinkStyleText LinkStyle = "text"
+)
+
+// ParseLinkStyle returns the link style with the given (case-insensitive) name
+func ParseLinkStyle(style string) (LinkStyle, bool) {
+	switch linkStyle := LinkStyle(strings.ToLower(style)); linkStyle {
+	case LinkStyleMarkdown, LinkStyleUrl, LinkStyleText:
+		return linkStyle, true
+	default:
+		return "", false
+	}
+}
+
+// UnknownSeverityPolicy determines the severity of open source issues with a severity that is not in the severity
+// mapping
+type UnknownSeverityPolicy string
+
+const (
+	// UnknownSeverityAsLow reports unrecognized severities as low
+	UnknownSeverityAsLow UnknownSeverityPolicy = "low"
+	// UnknownSeverityAsMedium reports unrecognized severities as medium
+	UnknownSeverityAsMedium UnknownSeverityPolicy = "medium"
+	// UnknownSeverityAsIs reports unrecognized severities as unknown and logs a warning
+	UnknownSeverityAsIs UnknownSeverityPolicy = "unknown"
+)
+
+// ParseUnknownSeverityPolicy returns the unknown severity policy with the given (case-insensitive) name
+func ParseUnknownSeverityPolicy(policy string) (UnknownSeverityPolicy, bool) {
+	switch parsed := UnknownSeverityPolicy(strings.ToLower(strings.TrimSpace(policy))); parsed {
+	case UnknownSeverityAsLow, UnknownSeverityAsMedium, UnknownSeverityAsIs:
+		return parsed, true
+	default:
+		return "", false
+	}
+}
+
+// RenderContext is where rendered issue content is shown, each context can use its own output format
+type RenderContext string
+
+const (
+	// RenderContextHover is the extended message of issues shown in hovers and details panels
+	RenderContextHover RenderContext = "hover"
+	// RenderContextDiagnostic is the message of issues shown in diagnostics
+	RenderContextDiagnostic RenderContext = "diagnostic"
+	// RenderContextExport is the issue content written to exported reports
+	RenderContextExport RenderContext = "export"
+)
+
+// RenderContexts returns all rendering contexts
+func RenderContexts() []RenderContext {
+	return []RenderContext{RenderContextHover, RenderContextDiagnostic, RenderContextExport}
+}
+
+// DevDependencyIssues determines how issues in development dependencies, e.g. npm devDependencies, are treated
+type DevDependencyIssues string
+
+const (
+	// DevDependencyIssuesShow shows them like all other issues
+	DevDependencyIssuesShow DevDependencyIssues = "show"
+	// DevDependencyIssuesTag marks them as dev dependency issues in their messages
+	DevDependencyIssuesTag DevDependencyIssues = "tag"
+	// DevDependencyIssuesDowngrade lowers their severity by one level, low severities are kept
+	DevDependencyIssuesDowngrade DevDependencyIssues = "downgrade"
+	// DevDependencyIssuesHide filters them out
+	DevDependencyIssuesHide DevDependencyIssues = "hide"
+)
+
+// ParseDevDependencyIssues returns the dev dependency issue treatment with the given (case-insensitive) name
+func ParseDevDependencyIssues(treatment string) (DevDependencyIssues, bool) {
+	switch devDependencyIssues := DevDependencyIssues(strings.ToLower(treatment)); devDependencyIssues {
+	case DevDependencyIssuesShow, DevDependencyIssuesTag, DevDependencyIssuesDowngrade, DevDependencyIssuesHide:
+		return devDependencyIssues, true
+	default:
+		return "", false
+	}
+}
+
+// UnknownProductCounting determines how issues without a product are counted in the severity counts of a scan
+type UnknownProductCounting string
+
+const (
+	// UnknownProductCountingKeep counts them in a separate "unknown" product
+	UnknownProductCountingKeep UnknownProductCounting = "keep"
+	// UnknownProductCountingDrop leaves them out of the severity counts and logs a warning
+	UnknownProductCountingDrop UnknownProductCounting = "drop"
+	// UnknownProductCountingInfer counts them for the product that reports their issue type, issue types that
+	// can't be attributed to a product are counted as "unknown"
+	UnknownProductCountingInfer UnknownProductCounting = "infer"
+)
+
+// ParseUnknownProductCounting returns the unknown product counting with the given (case-insensitive) name
+func ParseUnknownProductCounting(counting string) (UnknownProductCounting, bool) {
+	switch unknownProductCounting := UnknownProductCounting(strings.ToLower(counting)); unknownProductCounting {
+	case UnknownProductCountingKeep, UnknownProductCountingDrop, UnknownProductCountingInfer:
+		return unknownProductCounting, true
+	default:
+		return "", false
+	}
+}
+
+// IssueIdentifier determines which identifier is displayed as the id of open source issues
+type IssueIdentifier string
+
+const (
+	// IssueIdentifierVulnmap uses the Vulnmap vulnerability id
+	IssueIdentifierVulnmap IssueIdentifier = "vulnmap"
+	// IssueIdentifierCve uses the first CVE of the vulnerability, or the Vulnmap id if it has no CVE
+	IssueIdentifierCve IssueIdentifier = "cve"
+)
+
+// ParseIssueIdentifier returns the issue identifier with the given (case-insensitive) name
+func ParseIssueIdentifier(identifier string) (IssueIdentifier, bool) {
+	switch issueIdentifier := IssueIdentifier(strings.ToLower(identifier)); issueIdentifier {
+	case IssueIdentifierVulnmap, IssueIdentifierCve:
+		return issueIdentifier, true
+	default:
+		return "", false
+	}
+}
+
+// AdvisoryIdentifierPreference determines which advisory identifiers (CVE or GHSA) of open source issues are
+// rendered, and in which order
+type AdvisoryIdentifierPreference string
+
+const (
+	// AdvisoryIdentifierPreferenceCve renders the CVEs, GHSA identifiers only if there is no CVE
+	AdvisoryIdentifierPreferenceCve AdvisoryIdentifierPreference = "cve"
+	// AdvisoryIdentifierPreferenceGhsa renders the GHSA identifiers, CVEs only if there is no GHSA identifier
+	AdvisoryIdentifierPreferenceGhsa AdvisoryIdentifierPreference = "ghsa"
+	// AdvisoryIdentifierPreferenceAll renders the CVEs followed by the GHSA identifiers
+	AdvisoryIdentifierPreferenceAll AdvisoryIdentifierPreference = "all"
+)
+
+// ParseAdvisoryIdentifierPreference returns the advisory identifier preference with the given (case-insensitive) name
+func ParseAdvisoryIdentifierPreference(preference string) (AdvisoryIdentifierPreference, bool) {
+	switch advisoryIdentifierPreference := AdvisoryIdentifierPreference(strings.ToLower(preference)); advisoryIdentifierPreference {
+	case AdvisoryIdentifierPreferenceCve, AdvisoryIdentifierPreferenceGhsa, AdvisoryIdentifierPreferenceAll:
+		return advisoryIdentifierPreference, true
+	default:
+		return "", false
+	}
+}
+
+// ManifestLockfileDuplicates determines how an open source issue is reported that is found in both a manifest and
+// its lockfile, e.g. in package.json and package-lock.json
+type ManifestLockfileDuplicates string
+
+const (
+	// ManifestLockfileDuplicatesKeep reports the issue for the manifest and the lockfile
+	ManifestLockfileDuplicatesKeep ManifestLockfileDuplicates = "keep"
+	// ManifestLockfileDuplicatesCollapse only reports the issue for the manifest, as it's fixed there
+	ManifestLockfileDuplicatesCollapse ManifestLockfileDuplicates = "collapse"
+)
+
+// ParseManifestLockfileDuplicates returns the manifest lockfile duplicate handling with the given (case-insensitive)
+// name
+func ParseManifestLockfileDuplicates(duplicates string) (ManifestLockfileDuplicates, bool) {
+	switch lockfileDuplicates := ManifestLockfileDuplicates(strings.ToLower(duplicates)); lockfileDuplicates {
+	case ManifestLockfileDuplicatesKeep, ManifestLockfileDuplicatesCollapse:
+		return lockfileDuplicates, true
+	default:
+		return "", false
+	}
+}
+
+// PathPrivacy determines how file paths are included in outbound telemetry, e.g. analytics events
+type PathPrivacy string
+
+const (
+	// PathPrivacyFull includes the absolute path
+	PathPrivacyFull PathPrivacy = "full"
+	// PathPrivacyRelative includes the path relative to the workspace folder
+	PathPrivacyRelative PathPrivacy = "relative"
+	// PathPrivacyHashed includes a hash of the path, so that events about the same file can be correlated
+	PathPrivacyHashed PathPrivacy = "hashed"
+	// PathPrivacyOmitted includes no path at all
+	PathPrivacyOmitted PathPrivacy = "omitted"
+)
+
+// ParsePathPrivacy returns the path privacy level with the given (case-insensitive) name
+func ParsePathPrivacy(level string) (PathPrivacy, bool) {
+	switch pathPrivacy := PathPrivacy(strings.ToLower(level)); pathPrivacy {
+	case PathPrivacyFull, PathPrivacyRelative, PathPrivacyHashed, PathPrivacyOmitted:
+		return pathPrivacy, true
+	default:
+		return "", false
+	}
+}
+
+// UntrustedFolderBehavior determines what happens when a scan of an untrusted folder is skipped
+type UntrustedFolderBehavior string
+
+const (
+	// UntrustedFolderBehaviorSkip skips the scan silently
+	UntrustedFolderBehaviorSkip UntrustedFolderBehavior = "skip"
+	// UntrustedFolderBehaviorPrompt asks the user to trust the folder, once per folder and session
+	UntrustedFolderBehaviorPrompt UntrustedFolderBehavior = "prompt"
+)
+
+// ParseUntrustedFolderBehavior returns the untrusted folder behavior with the given (case-insensitive) name
+func ParseUntrustedFolderBehavior(behavior string) (UntrustedFolderBehavior, bool) {
+	switch untrustedFolderBehavior := UntrustedFolderBehavior(strings.ToLower(behavior)); untrustedFolderBehavior {
+	case UntrustedFolderBehaviorSkip, UntrustedFolderBehaviorPrompt:
+		return untrustedFolderBehavior, true
+	default:
+		return "", false
+	}
+}
+
+// IssueURLIdPlaceholder is replaced by the issue id in issue URL templates
+const IssueURLIdPlaceholder = "{id}"
+
+// DefaultIssueURLTemplates returns the web pages of issues by product. Code has no page per rule by default, its
+// issues link to the list of rules.
+func DefaultIssueURLTemplates() map[product.Product]string {
+	return map[product.Product]string{
+		product.ProductOpenSource:           "https://vulnmap.khulnasoft.com/vuln/" + IssueURLIdPlaceholder,
+		product.ProductInfrastructureAsCode: "https://security.vulnmap.khulnasoft.com/rules/cloud/" + IssueURLIdPlaceholder,
+	}
+}
+
 var (
 	Version            = "SNAPSHOT"
 	LsProtocolVersion  = "development"
@@ -79,6 +352,8 @@ type CliSettings struct {
 	AdditionalOssParameters []string
 	cliPath                 string
 	cliPathAccessMutex      sync.Mutex
+	// version is the version printed by the installed CLI, empty until it was determined
+	version string
 }
 
 func NewCliSettings() *CliSettings {
@@ -126,6 +401,19 @@ func (c *CliSettings) SetPath(path string) {
 	c.cliPath = path
 }
 
+// Version returns the version of the installed CLI, e.g. "1.1234.0 (standalone)", empty if it's not known
+func (c *CliSettings) Version() string {
+	c.cliPathAccessMutex.Lock()
+	defer c.cliPathAccessMutex.Unlock()
+	return c.version
+}
+
+func (c *CliSettings) SetVersion(version string) {
+	c.cliPathAccessMutex.Lock()
+	defer c.cliPathAccessMutex.Unlock()
+	c.version = version
+}
+
 func (c *CliSettings) DefaultBinaryInstallPath() string {
 	lsPath := filepath.Join(xdg.DataHome, "vulnmap-ls")
 	err := os.MkdirAll(lsPath, 0755)
@@ -140,8 +428,10 @@ type Config struct {
 	scrubDict                    map[string]bool
 	configLoaded                 concurrency.AtomicBool
 	cliSettings                  *CliSettings
-	configFile                   string
+	customConfigFiles            []string
 	format                       string
+	// contextFormats contains the output formats of the rendering contexts that don't use the global format
+	contextFormats map[RenderContext]string
 	isErrorReportingEnabled      concurrency.AtomicBool
 	isVulnmapCodeEnabled            concurrency.AtomicBool
 	isVulnmapOssEnabled             concurrency.AtomicBool
@@ -152,6 +442,9 @@ type Config struct {
 	manageBinariesAutomatically  concurrency.AtomicBool
 	logPath                      string
 	logFile                      *os.File
+	recentLogLines               *logging.RingBuffer
+	logLevel                     zerolog.Level
+	subsystemLogLevels           map[string]zerolog.Level
 	vulnmapCodeAnalysisTimeout      time.Duration
 	vulnmapApiUrl                   string
 	vulnmapCodeApiUrl               string
@@ -179,6 +472,114 @@ type Config struct {
 	storage                      StorageWithCallbacks
 	m                            sync.Mutex
 	analyticsEnabled             bool
+	anonymizeAnalytics           bool
+	analyticsSessionId           string
+	persistIssueCache            bool
+	scanChangedFilesOnly         bool
+	changedFilesBaseRef          string
+	productDisplayOrder          []product.Product
+	suppressionPolicyPath        string
+	remapLockfileIssues          bool
+	learnLessonLookupConcurrency int
+	includeIssueFingerprints     bool
+	scanFileMappings             map[string][]product.Product
+	ossOutputVersion             string
+	notificationLevel            NotificationLevel
+	customHttpHeaders            map[string]string
+	// maxIssuesPerScan caps the number of issues a single scan reports, 0 means unlimited
+	maxIssuesPerScan int
+	// showOnlyFixable hides issues that can't be fixed by an upgrade, a patch or an automatic fix
+	showOnlyFixable bool
+	// includeMajorUpgradeFixes considers issues fixable that require a major version upgrade
+	includeMajorUpgradeFixes bool
+	// fileDeletionWatcherEnabled watches trusted folders to clear the diagnostics of externally deleted files
+	fileDeletionWatcherEnabled bool
+	// idleLogoutTimeout is the time without activity after which the authentication is cleared, 0 disables it
+	idleLogoutTimeout time.Duration
+	// inlineValueKinds is the allowlist of inline value kinds to show, empty allows all kinds
+	inlineValueKinds []string
+	// baselineEnabled hides the issues accepted by the baseline
+	baselineEnabled bool
+	// baselinePath is the file the baseline of each folder is persisted to, empty means the default location
+	baselinePath string
+	// acceptedIssuesPath is the file the accepted issues are persisted to, empty means the default location
+	acceptedIssuesPath string
+	// issueTagsPath is the file the issue tags are persisted to, empty means the default location
+	issueTagsPath string
+	// issueTagFilter contains the tags of which an issue needs at least one to be shown, empty shows all issues
+	issueTagFilter []string
+	// relativeFilePathDisplay shows file paths relative to the folder root in messages and reports
+	relativeFilePathDisplay bool
+	// licenseSeverities maps lower case license identifiers to the severity of license issues with that license
+	licenseSeverities map[string]string
+	// registryCertificates maps lower case package managers to the CA certificate file of their private registry
+	registryCertificates map[string]string
+	// linkStyle determines how links are rendered in issue messages
+	linkStyle LinkStyle
+	// severityMapping maps lower case severities reported by the CLI to severity names, nil for the default mapping
+	severityMapping map[string]string
+	// unknownSeverityPolicy determines the severity of issues with a severity that is not in the severity mapping
+	unknownSeverityPolicy UnknownSeverityPolicy
+	// doNotTrack opts out of all telemetry and analytics, like the DO_NOT_TRACK environment variable
+	doNotTrack bool
+	// maxMessageLength caps the length of diagnostic messages, 0 means no truncation
+	maxMessageLength int
+	// projectIssuesTargetFile is the file issues without a file are reported on, empty means the primary manifest
+	projectIssuesTargetFile string
+	// issueIdentifier determines the primary id of open source issues
+	issueIdentifier IssueIdentifier
+	// issueURLTemplates contains the configured web page templates of issues by product
+	issueURLTemplates map[product.Product]string
+	// sequentialProductScans runs the products of a scan one after another instead of in parallel
+	sequentialProductScans bool
+	// productScanOrder is the order in which the products of a scan are started
+	productScanOrder []product.Product
+	// includeLineOwners annotates issues with the last commit of their affected line
+	includeLineOwners bool
+	// scanDebounce is the time triggered scans of a folder wait for further triggers, 0 scans immediately
+	scanDebounce time.Duration
+	// allProjects lets the CLI discover all projects of a folder during Open Source scans
+	allProjects bool
+	// allProjectsDetectionDepth limits how deep projects are discovered, 0 uses the CLI default
+	allProjectsDetectionDepth int
+	// allProjectsExclude contains the directory and file names skipped during project discovery
+	allProjectsExclude []string
+	// manifestSummary publishes a diagnostic counting the vulnerabilities at the top of each manifest
+	manifestSummary bool
+	// pathPrivacy determines how file paths are included in outbound telemetry
+	pathPrivacy PathPrivacy
+	// untrustedFolderBehavior determines what happens when a scan of an untrusted folder is skipped
+	untrustedFolderBehavior UntrustedFolderBehavior
+	// maxResultAge is the age after which published results are marked as possibly outdated, 0 never marks them
+	maxResultAge time.Duration
+	// analyticsEndpoint is the base URL all telemetry is sent to, empty for the default endpoints
+	analyticsEndpoint string
+	// minEpssScore hides Open Source issues with a lower EPSS score, 0 shows all issues
+	minEpssScore float64
+	// analyticsDeduplicationWindow is the time in which at most one scan done event per folder and product is sent
+	analyticsDeduplicationWindow time.Duration
+	// diagnosticsPublishConcurrency is the number of files whose diagnostics are published in parallel
+	diagnosticsPublishConcurrency int
+	// cliMaxMemoryMB is the maximum heap size of the CLI in megabytes, 0 uses the CLI's default
+	cliMaxMemoryMB int
+	// diagnosticsCloseGracePeriod is the time the diagnostics of a closed file are kept, 0 clears them right away
+	diagnosticsCloseGracePeriod time.Duration
+	// devDependencyIssues determines how issues in development dependencies are treated
+	devDependencyIssues DevDependencyIssues
+	// unknownProductCounting determines how issues without a product are counted in the severity counts
+	unknownProductCounting UnknownProductCounting
+	// advisoryIdentifierPreference determines which advisory identifiers of open source issues are rendered
+	advisoryIdentifierPreference AdvisoryIdentifierPreference
+	// diagnosticsPublishThrottle is the minimum time between two diagnostics publishes of a folder, 0 disables it
+	diagnosticsPublishThrottle time.Duration
+	// attestationSigningKeyPath is the PEM file of the private key exported attestations are signed with
+	attestationSigningKeyPath string
+	// manifestLockfileDuplicates determines how issues found in both a manifest and its lockfile are reported
+	manifestLockfileDuplicates ManifestLockfileDuplicates
+	// scanOnStartup determines if the workspace folders are scanned automatically when the language server starts
+	scanOnStartup bool
+	// exploitSeverityEscalation raises the severity of open source issues with a mature exploit
+	exploitSeverityEscalation bool
 }
 
 func CurrentConfig() *Config {
@@ -206,9 +607,10 @@ func New() *Config {
 	c := &Config{}
 	c.scrubDict = make(map[string]bool)
 	c.logger = &log.Logger
+	c.logLevel = zerolog.GlobalLevel()
 	c.cliSettings = NewCliSettings()
 	c.automaticAuthentication = true
-	c.configFile = ""
+	c.customConfigFiles = nil
 	c.format = "md"
 	c.isErrorReportingEnabled.Set(true)
 	c.isVulnmapOssEnabled.Set(true)
@@ -218,9 +620,28 @@ func New() *Config {
 	c.vulnmapCodeAnalysisTimeout = vulnmapCodeAnalysisTimeoutFromEnv()
 	c.token = ""
 	c.trustedFoldersFeatureEnabled = true
+	c.productDisplayOrder = DefaultProductDisplayOrder()
+	c.productScanOrder = DefaultProductDisplayOrder()
+	c.remapLockfileIssues = true
+	c.learnLessonLookupConcurrency = DefaultLearnLessonLookupConcurrency
+	c.diagnosticsPublishConcurrency = DefaultDiagnosticsPublishConcurrency
+	c.diagnosticsCloseGracePeriod = DefaultDiagnosticsCloseGracePeriod
+	c.devDependencyIssues = DevDependencyIssuesShow
+	c.unknownProductCounting = UnknownProductCountingKeep
+	c.advisoryIdentifierPreference = AdvisoryIdentifierPreferenceCve
+	c.manifestLockfileDuplicates = ManifestLockfileDuplicatesKeep
+	c.notificationLevel = NotificationLevelAll
+	c.linkStyle = LinkStyleMarkdown
+	c.unknownSeverityPolicy = UnknownSeverityAsLow
+	c.pathPrivacy = PathPrivacyOmitted
+	c.untrustedFolderBehavior = UntrustedFolderBehaviorSkip
+	c.issueIdentifier = IssueIdentifierVulnmap
+	c.includeMajorUpgradeFixes = true
 	c.automaticScanning = true
+	c.scanOnStartup = true
 	c.authenticationMethod = lsp.TokenAuthentication
 	c.deviceId = c.determineDeviceId()
+	c.analyticsSessionId = uuid.NewRandom().String()
 	c.addDefaults()
 	c.filterSeverity = lsp.DefaultSeverityFilter()
 	initWorkFlowEngine(c)
@@ -228,6 +649,9 @@ func New() *Config {
 	if err != nil {
 		log.Warn().Err(err).Msg("unable to initialize workflow engine")
 	}
+	if err = c.RegisterReportAnalyticsWorkflow(); err != nil {
+		log.Err(err).Msg("unable to register reportAnalytics workflow")
+	}
 	c.UpdateApiEndpoints(DefaultVulnmapApiUrl)
 	c.enableVulnmapLearnCodeActions = true
 	c.SetTelemetryEnabled(true)
@@ -277,20 +701,29 @@ func (c *Config) SetTrustedFolderFeatureEnabled(enabled bool) {
 	c.trustedFoldersFeatureEnabled = enabled
 }
 
+// Load merges the config files into the environment. Variables that are already set in the environment are not
+// overridden. Of the custom config files, later files override earlier ones, and all custom config files override
+// the standard config files. Missing custom config files are skipped with a warning.
 func (c *Config) Load() {
-	files := c.configFiles()
-	for _, fileName := range files {
-		c.loadFile(fileName)
+	custom := map[string]bool{}
+	for _, fileName := range c.CustomConfigFiles() {
+		custom[fileName] = true
+	}
+	for _, fileName := range c.configFiles() {
+		err := c.loadFile(fileName)
+		if err != nil && custom[fileName] {
+			log.Warn().Err(err).Str("method", "Load").Msg("skipping config file " + fileName)
+		}
 	}
 
 	c.configLoaded.Set(true)
 }
 
-func (c *Config) loadFile(fileName string) {
+func (c *Config) loadFile(fileName string) error {
 	file, err := os.Open(fileName)
 	if err != nil {
 		log.Info().Str("method", "loadFile").Msg("Couldn't load " + fileName)
-		return
+		return err
 	}
 	defer func(file *os.File) { _ = file.Close() }(file)
 	env := gotenv.Parse(file)
@@ -300,7 +733,9 @@ func (c *Config) loadFile(fileName string) {
 			err := os.Setenv(k, v)
 			if err != nil {
 				log.Warn().Str("method", "loadFile").Msg("Couldn't set environment variable " + k)
+				continue
 			}
+			log.Debug().Str("method", "loadFile").Str("fileName", fileName).Msg("set " + k)
 		} else {
 			// add to path, don't ignore additional paths
 			if k == "PATH" {
@@ -310,6 +745,7 @@ func (c *Config) loadFile(fileName string) {
 	}
 	c.updatePath(".")
 	log.Debug().Str("fileName", fileName).Msg("loaded.")
+	return nil
 }
 
 func (c *Config) NonEmptyToken() bool {
@@ -338,6 +774,23 @@ func (c *Config) LogPath() string {
 	defer c.m.Unlock()
 	return c.logPath
 }
+
+// IsLoggingToFile returns true if the log file at the log path was opened and the logs are written to it
+func (c *Config) IsLoggingToFile() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.logPath != "" && c.logFile != nil
+}
+
+// RecentLogLines returns the most recent log lines retained in memory, which is only done when not logging to a file
+func (c *Config) RecentLogLines() []string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.recentLogLines == nil {
+		return nil
+	}
+	return c.recentLogLines.Lines()
+}
 func (c *Config) VulnmapApi() string                        { return c.vulnmapApiUrl }
 func (c *Config) VulnmapCodeApi() string                    { return c.vulnmapCodeApiUrl }
 func (c *Config) VulnmapCodeAnalysisTimeout() time.Duration { return c.vulnmapCodeAnalysisTimeout }
@@ -477,6 +930,30 @@ func (c *Config) SetFormat(format string) {
 	c.format = format
 }
 
+// FormatFor returns the output format of the rendering context, by default the global format is used
+func (c *Config) FormatFor(renderContext RenderContext) string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if format, ok := c.contextFormats[renderContext]; ok {
+		return format
+	}
+	return c.format
+}
+
+// SetFormatFor sets the output format of the rendering context, an empty format restores the global format
+func (c *Config) SetFormatFor(renderContext RenderContext, format string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if format == "" {
+		delete(c.contextFormats, renderContext)
+		return
+	}
+	if c.contextFormats == nil {
+		c.contextFormats = map[RenderContext]string{}
+	}
+	c.contextFormats[renderContext] = format
+}
+
 func (c *Config) SetLogPath(logPath string) {
 	c.m.Lock()
 	defer c.m.Unlock()
@@ -509,6 +986,7 @@ func (c *Config) ConfigureLogging(server lsp.Server) {
 
 	levelWriter := logging.New(server)
 	writers := []io.Writer{levelWriter}
+	loggingToFile := false
 
 	if c.LogPath() != "" {
 		c.logFile, err = os.OpenFile(c.LogPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
@@ -517,9 +995,17 @@ func (c *Config) ConfigureLogging(server lsp.Server) {
 		} else {
 			_, _ = fmt.Fprintln(os.Stderr, fmt.Sprint("adding file logger to file ", c.logPath))
 			writers = append(writers, c.logFile)
+			loggingToFile = true
 		}
 	}
 
+	// without a log file, keep the recent log lines in memory so that users can still retrieve them
+	var recentLogLines *logging.RingBuffer
+	if !loggingToFile {
+		recentLogLines = logging.NewRingBuffer(recentLogLinesBufferSize)
+		writers = append(writers, recentLogLines)
+	}
+
 	scrubbingMultilevelWriter := logging.NewScrubbingWriter(zerolog.MultiLevelWriter(writers...), c.scrubDict)
 	writer := zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
 		w.Out = scrubbingMultilevelWriter
@@ -538,22 +1024,50 @@ func (c *Config) ConfigureLogging(server lsp.Server) {
 	})
 	c.m.Lock()
 	defer c.m.Unlock()
-	log.Logger = zerolog.New(writer).With().Timestamp().Str("separator", "-").Str("method", "").Str("ext", "").Logger()
+	log.Logger = zerolog.New(writer).Level(logLevel).With().Timestamp().Str("separator", "-").Str("method", "").Str("ext", "").Logger()
 	c.engine.SetLogger(&log.Logger)
 	c.logger = &log.Logger
+	c.recentLogLines = recentLogLines
 }
 
 // DisableLoggingToFile closes the open log file and sets the global logger back to it's default
 func (c *Config) DisableLoggingToFile() {
 	log.Info().Msgf("Disabling file logging to %v", c.logPath)
 	c.logPath = ""
-	log.Logger = zerolog.New(os.Stderr).With().Timestamp().Logger()
+	logLevel, _ := zerolog.ParseLevel(c.LogLevel())
+	log.Logger = zerolog.New(os.Stderr).Level(logLevel).With().Timestamp().Logger()
 	if c.logFile != nil {
 		_ = c.logFile.Close()
 	}
 }
 
-func (c *Config) SetConfigFile(configFile string) { c.configFile = configFile }
+// SetConfigFile sets a single custom config file, an empty file name means no custom config file
+func (c *Config) SetConfigFile(configFile string) {
+	if configFile == "" {
+		c.SetConfigFiles(nil)
+		return
+	}
+	c.SetConfigFiles([]string{configFile})
+}
+
+// SetConfigFiles sets the custom config files. Later files override earlier ones, e.g. a personal config file
+// following a shared team config file.
+func (c *Config) SetConfigFiles(configFiles []string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.customConfigFiles = nil
+	for _, configFile := range configFiles {
+		if configFile != "" {
+			c.customConfigFiles = append(c.customConfigFiles, configFile)
+		}
+	}
+}
+
+func (c *Config) CustomConfigFiles() []string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return append([]string(nil), c.customConfigFiles...)
+}
 
 func getCodeApiUrlFromCustomEndpoint(endpoint string) (string, error) {
 	// Code API endpoint can be set via env variable for debugging using local API instance
@@ -607,12 +1121,13 @@ func (c *Config) updatePath(pathExtension string) {
 	}
 }
 
-// The order of the files is important - first file variable definitions win!
+// The order of the files is important - first file variable definitions win! Custom config files are therefore
+// returned in reverse order, so that later custom files override earlier ones.
 func (c *Config) configFiles() []string {
 	var files []string
-	configFile := c.configFile
-	if configFile != "" {
-		files = append(files, configFile)
+	customConfigFiles := c.CustomConfigFiles()
+	for i := len(customConfigFiles) - 1; i >= 0; i-- {
+		files = append(files, customConfigFiles[i])
 	}
 	home := os.Getenv("HOME")
 	if home == "" {
@@ -648,13 +1163,34 @@ func (c *Config) ManageCliBinariesAutomatically() bool {
 	return c.ManageBinariesAutomatically()
 }
 
+// IsTelemetryEnabled returns whether telemetry is enabled and not opted out of with do not track
 func (c *Config) IsTelemetryEnabled() bool {
-	return c.isTelemetryEnabled.Get()
+	return c.isTelemetryEnabled.Get() && !c.IsDoNotTrack()
 }
 
 func (c *Config) SetTelemetryEnabled(enabled bool) {
 	c.isTelemetryEnabled.Set(enabled)
-	c.engine.GetConfiguration().Set(configuration.ANALYTICS_DISABLED, !enabled)
+	c.engine.GetConfiguration().Set(configuration.ANALYTICS_DISABLED, !c.IsTelemetryEnabled())
+}
+
+// IsDoNotTrack returns whether all telemetry and analytics are opted out of, either by the DO_NOT_TRACK environment
+// variable or by the configuration, regardless of whether they are enabled
+func (c *Config) IsDoNotTrack() bool {
+	c.m.Lock()
+	doNotTrack := c.doNotTrack
+	c.m.Unlock()
+	if doNotTrack {
+		return true
+	}
+	value, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(DoNotTrack)))
+	return err == nil && value
+}
+
+func (c *Config) SetDoNotTrack(doNotTrack bool) {
+	c.m.Lock()
+	c.doNotTrack = doNotTrack
+	c.m.Unlock()
+	c.engine.GetConfiguration().Set(configuration.ANALYTICS_DISABLED, !c.IsTelemetryEnabled())
 }
 
 func (c *Config) telemetryEnablementFromEnv() {
@@ -799,6 +1335,32 @@ func (c *Config) IsAutoScanEnabled() bool {
 	return c.automaticScanning
 }
 
+// IsScanChangedFilesOnlyEnabled returns true if scan results should be restricted to files that changed
+// relative to the ChangedFilesBaseRef
+func (c *Config) IsScanChangedFilesOnlyEnabled() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.scanChangedFilesOnly
+}
+
+func (c *Config) SetScanChangedFilesOnly(enabled bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.scanChangedFilesOnly = enabled
+}
+
+func (c *Config) ChangedFilesBaseRef() string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.changedFilesBaseRef
+}
+
+func (c *Config) SetChangedFilesBaseRef(baseRef string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.changedFilesBaseRef = baseRef
+}
+
 func (c *Config) SetAuthenticationMethod(method lsp.AuthenticationMethod) {
 	c.authenticationMethod = method
 }
@@ -828,14 +1390,73 @@ func (c *Config) SetLogLevel(level string) {
 	defer c.m.Unlock()
 	parseLevel, err := zerolog.ParseLevel(level)
 	if err == nil {
-		zerolog.SetGlobalLevel(parseLevel)
+		c.logLevel = parseLevel
+		c.applyLogLevels()
 	}
 }
 
+// LogLevel returns the global log level, subsystems without an override log at this level
 func (c *Config) LogLevel() string {
 	c.m.Lock()
 	defer c.m.Unlock()
-	return zerolog.GlobalLevel().String()
+	return c.logLevel.String()
+}
+
+// SetSubsystemLogLevels sets the log level overrides of subsystems, e.g. "cli=debug,auth=trace". The global log
+// level applies to all other subsystems. Invalid overrides are rejected and the previous overrides are kept.
+func (c *Config) SetSubsystemLogLevels(overrides string) error {
+	subsystemLogLevels := map[string]zerolog.Level{}
+	for _, override := range strings.Split(overrides, ",") {
+		override = strings.TrimSpace(override)
+		if override == "" {
+			continue
+		}
+		subsystem, level, found := strings.Cut(override, "=")
+		subsystem = strings.ToLower(strings.TrimSpace(subsystem))
+		if !found || subsystem == "" {
+			return fmt.Errorf("invalid subsystem log level %s, expected <subsystem>=<level>", override)
+		}
+		parsedLevel, err := zerolog.ParseLevel(strings.ToLower(strings.TrimSpace(level)))
+		if err != nil {
+			return fmt.Errorf("invalid log level of subsystem %s: %w", subsystem, err)
+		}
+		subsystemLogLevels[subsystem] = parsedLevel
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.subsystemLogLevels = subsystemLogLevels
+	c.applyLogLevels()
+	return nil
+}
+
+// SubsystemLogLevel returns the log level of the subsystem, falling back to the global log level
+func (c *Config) SubsystemLogLevel(subsystem string) zerolog.Level {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if level, ok := c.subsystemLogLevels[subsystem]; ok {
+		return level
+	}
+	return c.logLevel
+}
+
+// SubsystemLogger returns a logger for the subsystem that logs at the subsystem's log level
+func (c *Config) SubsystemLogger(subsystem string) *zerolog.Logger {
+	level := c.SubsystemLogLevel(subsystem)
+	logger := c.Logger().Level(level).With().Str("subsystem", subsystem).Logger()
+	return &logger
+}
+
+// applyLogLevels lowers the zerolog global level to the most verbose configured level, so that subsystem loggers can
+// log below the global log level. The default logger is restricted to the global log level in ConfigureLogging.
+func (c *Config) applyLogLevels() {
+	minLevel := c.logLevel
+	for _, level := range c.subsystemLogLevels {
+		if level < minLevel {
+			minLevel = level
+		}
+	}
+	zerolog.SetGlobalLevel(minLevel)
 }
 
 func (c *Config) Logger() *zerolog.Logger {
@@ -874,9 +1495,854 @@ func (c *Config) IsFedramp() bool {
 }
 
 func (c *Config) IsAnalyticsEnabled() bool {
-	return c.analyticsEnabled
+	return c.analyticsEnabled && !c.IsDoNotTrack()
 }
 
 func (c *Config) SetAnalyticsEnabled(enableAnalytics bool) {
 	c.analyticsEnabled = enableAnalytics
 }
+
+func (c *Config) IsAnalyticsAnonymized() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.anonymizeAnalytics
+}
+
+func (c *Config) SetAnonymizeAnalytics(anonymize bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.anonymizeAnalytics = anonymize
+}
+
+func (c *Config) IsIssueCachePersistenceEnabled() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.persistIssueCache
+}
+
+func (c *Config) SetIssueCachePersistence(enabled bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.persistIssueCache = enabled
+}
+
+// DefaultProductDisplayOrder returns the order in which product results are displayed if none is configured
+func DefaultProductDisplayOrder() []product.Product {
+	return []product.Product{product.ProductOpenSource, product.ProductCode, product.ProductInfrastructureAsCode}
+}
+
+// ProductDisplayOrder returns the order in which product results are aggregated and reported to the IDE
+func (c *Config) ProductDisplayOrder() []product.Product {
+	c.m.Lock()
+	defer c.m.Unlock()
+	order := make([]product.Product, len(c.productDisplayOrder))
+	copy(order, c.productDisplayOrder)
+	return order
+}
+
+func (c *Config) SetProductDisplayOrder(order []product.Product) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.productDisplayOrder = order
+}
+
+// SuppressionPolicyPath returns the path of the policy file with centrally suppressed issues, if any
+func (c *Config) SuppressionPolicyPath() string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.suppressionPolicyPath
+}
+
+func (c *Config) SetSuppressionPolicyPath(path string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.suppressionPolicyPath = path
+}
+
+// IsLockfileIssueRemappingEnabled returns whether issues found via a lockfile are reported against the
+// manifest that declares the vulnerable dependency instead of the lockfile itself
+func (c *Config) IsLockfileIssueRemappingEnabled() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.remapLockfileIssues
+}
+
+func (c *Config) SetLockfileIssueRemapping(enabled bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.remapLockfileIssues = enabled
+}
+
+// LearnLessonLookupConcurrency returns how many learn lessons are looked up in parallel when converting scan results
+func (c *Config) LearnLessonLookupConcurrency() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.learnLessonLookupConcurrency
+}
+
+func (c *Config) SetLearnLessonLookupConcurrency(concurrency int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.learnLessonLookupConcurrency = concurrency
+}
+
+// IsIssueFingerprintingEnabled returns whether published diagnostics carry the fingerprint of their issue
+func (c *Config) IsIssueFingerprintingEnabled() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.includeIssueFingerprints
+}
+
+func (c *Config) SetIssueFingerprinting(enabled bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.includeIssueFingerprints = enabled
+}
+
+// ScanFileMappings returns file names and extension patterns (e.g. "*.tf") mapped to the products that should
+// scan such files, in addition to the files supported by the products
+func (c *Config) ScanFileMappings() map[string][]product.Product {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.scanFileMappings
+}
+
+func (c *Config) SetScanFileMappings(mappings map[string][]product.Product) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.scanFileMappings = mappings
+}
+
+// OssOutputVersion returns the version of the Open Source JSON output requested from the CLI, empty uses the CLI's
+// default output
+func (c *Config) OssOutputVersion() string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.ossOutputVersion
+}
+
+func (c *Config) SetOssOutputVersion(version string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.ossOutputVersion = version
+}
+
+// NotificationLevel returns which ShowMessage notifications are shown to the user
+func (c *Config) NotificationLevel() NotificationLevel {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.notificationLevel
+}
+
+func (c *Config) SetNotificationLevel(level NotificationLevel) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.notificationLevel = level
+}
+
+// IsShowOnlyFixable returns whether issues that can't be fixed right away are filtered out
+func (c *Config) IsShowOnlyFixable() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.showOnlyFixable
+}
+
+func (c *Config) SetShowOnlyFixable(enabled bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.showOnlyFixable = enabled
+}
+
+// IsMajorUpgradeFixIncluded returns whether issues that require a major version upgrade are considered fixable
+func (c *Config) IsMajorUpgradeFixIncluded() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.includeMajorUpgradeFixes
+}
+
+func (c *Config) SetMajorUpgradeFixIncluded(included bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.includeMajorUpgradeFixes = included
+}
+
+// IsFileDeletionWatcherEnabled returns whether the file system of trusted folders is watched for deleted files
+func (c *Config) IsFileDeletionWatcherEnabled() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.fileDeletionWatcherEnabled
+}
+
+func (c *Config) SetFileDeletionWatcherEnabled(enabled bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.fileDeletionWatcherEnabled = enabled
+}
+
+// IdleLogoutTimeout returns the time without scans or commands after which the user is logged out. 0 disables it.
+func (c *Config) IdleLogoutTimeout() time.Duration {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.idleLogoutTimeout
+}
+
+func (c *Config) SetIdleLogoutTimeout(timeout time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.idleLogoutTimeout = timeout
+}
+
+// IsInlineValueKindEnabled returns whether inline values of the kind are shown, which is the case for all kinds
+// if no allowlist is configured
+func (c *Config) IsInlineValueKindEnabled(kind string) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if len(c.inlineValueKinds) == 0 {
+		return true
+	}
+	for _, enabledKind := range c.inlineValueKinds {
+		if enabledKind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) SetInlineValueKinds(kinds []string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.inlineValueKinds = kinds
+}
+
+// IsBaselineEnabled returns whether the issues accepted by the recorded baseline are hidden
+func (c *Config) IsBaselineEnabled() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.baselineEnabled
+}
+
+func (c *Config) SetBaselineEnabled(enabled bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.baselineEnabled = enabled
+}
+
+// BaselinePath returns the file the baseline of accepted issues of the folder is persisted to. A configured relative
+// path is resolved against the folder.
+func (c *Config) BaselinePath(folderPath string) string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return folderDataPath(folderPath, c.baselinePath, "baselines")
+}
+
+// folderDataPath resolves the configured path of a file with data of the folder. Relative paths are resolved against
+// the folder, absolute paths are shared by all folders. By default, each folder has its own file in the data
+// directory.
+func folderDataPath(folderPath string, configuredPath string, dataDir string) string {
+	if configuredPath == "" {
+		hash := sha256.Sum256([]byte(folderPath))
+		return filepath.Join(xdg.DataHome, "vulnmap-ls", dataDir, hex.EncodeToString(hash[:])+".json")
+	}
+	if filepath.IsAbs(configuredPath) {
+		return configuredPath
+	}
+	return filepath.Join(folderPath, configuredPath)
+}
+
+func (c *Config) SetBaselinePath(path string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.baselinePath = path
+}
+
+// AcceptedIssuesPath returns the file the individually accepted issues of the folder and their justifications are
+// persisted to. A configured relative path is resolved against the folder.
+func (c *Config) AcceptedIssuesPath(folderPath string) string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return folderDataPath(folderPath, c.acceptedIssuesPath, "accepted_issues")
+}
+
+func (c *Config) SetAcceptedIssuesPath(path string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.acceptedIssuesPath = path
+}
+
+// IssueTagsPath returns the file the labels users attached to issues of the folder are persisted to. A configured
+// relative path is resolved against the folder.
+func (c *Config) IssueTagsPath(folderPath string) string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return folderDataPath(folderPath, c.issueTagsPath, "issue_tags")
+}
+
+func (c *Config) SetIssueTagsPath(path string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.issueTagsPath = path
+}
+
+// IssueTagFilter returns the tags of which an issue needs at least one to be shown, empty means all issues are shown
+func (c *Config) IssueTagFilter() []string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.issueTagFilter
+}
+
+func (c *Config) SetIssueTagFilter(tags []string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.issueTagFilter = tags
+}
+
+// IsRelativeFilePathDisplay returns whether messages and reports show file paths relative to the folder root
+func (c *Config) IsRelativeFilePathDisplay() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.relativeFilePathDisplay
+}
+
+func (c *Config) SetRelativeFilePathDisplay(relative bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.relativeFilePathDisplay = relative
+}
+
+// LicenseSeverity returns the configured severity of license issues with the given license identifier, e.g.
+// "critical" for "GPL-3.0". ok is false if the severity of the license isn't remapped.
+func (c *Config) LicenseSeverity(license string) (severity string, ok bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	severity, ok = c.licenseSeverities[strings.ToLower(strings.TrimSpace(license))]
+	return severity, ok
+}
+
+// LicenseSeverities returns a copy of the mapping of lower case license identifiers to severities
+func (c *Config) LicenseSeverities() map[string]string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	severities := make(map[string]string, len(c.licenseSeverities))
+	for license, severity := range c.licenseSeverities {
+		severities[license] = severity
+	}
+	return severities
+}
+
+// SetLicenseSeverities replaces the mapping of license identifiers to severities. Identifiers are matched
+// case-insensitively.
+func (c *Config) SetLicenseSeverities(severities map[string]string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.licenseSeverities = make(map[string]string, len(severities))
+	for license, severity := range severities {
+		c.licenseSeverities[strings.ToLower(strings.TrimSpace(license))] = severity
+	}
+}
+
+// RegistryCertificates returns the CA certificate files to trust per package manager, e.g. "npm", when resolving
+// dependencies from private registries with self-signed certificates
+func (c *Config) RegistryCertificates() map[string]string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	certificates := make(map[string]string, len(c.registryCertificates))
+	for packageManager, certificate := range c.registryCertificates {
+		certificates[packageManager] = certificate
+	}
+	return certificates
+}
+
+func (c *Config) SetRegistryCertificates(certificates map[string]string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.registryCertificates = make(map[string]string, len(certificates))
+	for packageManager, certificate := range certificates {
+		c.registryCertificates[strings.ToLower(strings.TrimSpace(packageManager))] = certificate
+	}
+}
+
+// LinkStyle returns how links, e.g. to CVEs and CWEs, are rendered in issue messages
+func (c *Config) LinkStyle() LinkStyle {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.linkStyle
+}
+
+func (c *Config) SetLinkStyle(style LinkStyle) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.linkStyle = style
+}
+
+// SeverityMapping returns a copy of the mapping of lower case severities reported by the CLI to severity names, nil
+// if the default mapping applies
+func (c *Config) SeverityMapping() map[string]string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.severityMapping == nil {
+		return nil
+	}
+	mapping := make(map[string]string, len(c.severityMapping))
+	for reportedSeverity, severity := range c.severityMapping {
+		mapping[reportedSeverity] = severity
+	}
+	return mapping
+}
+
+// SetSeverityMapping replaces the mapping of severities reported by the CLI to severity names ("critical", "high",
+// "medium" or "low"). Reported severities are matched case-insensitively. A nil mapping restores the default mapping.
+func (c *Config) SetSeverityMapping(mapping map[string]string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if mapping == nil {
+		c.severityMapping = nil
+		return
+	}
+	c.severityMapping = make(map[string]string, len(mapping))
+	for reportedSeverity, severity := range mapping {
+		c.severityMapping[strings.ToLower(reportedSeverity)] = severity
+	}
+}
+
+// UnknownSeverityPolicy returns how severities are handled that are not in the severity mapping
+func (c *Config) UnknownSeverityPolicy() UnknownSeverityPolicy {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.unknownSeverityPolicy
+}
+
+func (c *Config) SetUnknownSeverityPolicy(policy UnknownSeverityPolicy) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.unknownSeverityPolicy = policy
+}
+
+// MaxIssuesPerScan returns how many issues a single scan reports at most. 0 means unlimited.
+func (c *Config) MaxIssuesPerScan() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.maxIssuesPerScan
+}
+
+func (c *Config) SetMaxIssuesPerScan(maxIssues int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.maxIssuesPerScan = maxIssues
+}
+
+// MaxMessageLength returns the maximum number of characters of diagnostic messages. 0 means no truncation.
+func (c *Config) MaxMessageLength() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.maxMessageLength
+}
+
+func (c *Config) SetMaxMessageLength(maxLength int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.maxMessageLength = maxLength
+}
+
+// ProjectIssuesTargetFile returns the file, absolute or relative to the folder, that issues without a file are
+// reported on. If empty, they are reported on the primary manifest of the folder.
+func (c *Config) ProjectIssuesTargetFile() string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.projectIssuesTargetFile
+}
+
+func (c *Config) SetProjectIssuesTargetFile(targetFile string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.projectIssuesTargetFile = targetFile
+}
+
+// IssueIdentifier returns which identifier is used as the primary id of open source issues
+func (c *Config) IssueIdentifier() IssueIdentifier {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.issueIdentifier
+}
+
+func (c *Config) SetIssueIdentifier(identifier IssueIdentifier) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.issueIdentifier = identifier
+}
+
+// IssueURLTemplate returns the template of the web page of issues of the product, in which IssueURLIdPlaceholder is
+// replaced by the issue id. It returns the default template if none is configured, or an empty string if there is no
+// template for the product.
+func (c *Config) IssueURLTemplate(p product.Product) string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if template, ok := c.issueURLTemplates[p]; ok {
+		return template
+	}
+	return DefaultIssueURLTemplates()[p]
+}
+
+func (c *Config) SetIssueURLTemplates(templates map[product.Product]string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.issueURLTemplates = templates
+}
+
+// IsSequentialProductScans returns true if the products of a scan run one after another in the product scan order,
+// instead of in parallel
+func (c *Config) IsSequentialProductScans() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.sequentialProductScans
+}
+
+func (c *Config) SetSequentialProductScans(sequential bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.sequentialProductScans = sequential
+}
+
+// ProductScanOrder returns the order in which the products of a scan are started. With sequential product scans,
+// this is the order in which their results are published.
+func (c *Config) ProductScanOrder() []product.Product {
+	c.m.Lock()
+	defer c.m.Unlock()
+	order := make([]product.Product, len(c.productScanOrder))
+	copy(order, c.productScanOrder)
+	return order
+}
+
+func (c *Config) SetProductScanOrder(order []product.Product) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.productScanOrder = order
+}
+
+// IsIncludeLineOwners returns true if issues in git repositories are annotated with the author and commit that last
+// changed their affected line
+func (c *Config) IsIncludeLineOwners() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.includeLineOwners
+}
+
+func (c *Config) SetIncludeLineOwners(include bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.includeLineOwners = include
+}
+
+// ScanDebounce returns the time a scan triggered by saving a file waits for further saves before it starts.
+// Saves within this time are coalesced into a single scan. 0 scans immediately. Folder scans are not debounced.
+func (c *Config) ScanDebounce() time.Duration {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.scanDebounce
+}
+
+func (c *Config) SetScanDebounce(debounce time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.scanDebounce = debounce
+}
+
+// IsAllProjectsEnabled returns true if Open Source scans discover all projects of a folder, e.g. in monorepos
+func (c *Config) IsAllProjectsEnabled() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.allProjects
+}
+
+func (c *Config) SetAllProjectsEnabled(enabled bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.allProjects = enabled
+}
+
+// AllProjectsDetectionDepth returns how many directory levels below a folder projects are discovered, 0 means
+// the CLI default
+func (c *Config) AllProjectsDetectionDepth() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.allProjectsDetectionDepth
+}
+
+func (c *Config) SetAllProjectsDetectionDepth(depth int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.allProjectsDetectionDepth = depth
+}
+
+// AllProjectsExclude returns the directory and file names that are skipped when discovering projects
+func (c *Config) AllProjectsExclude() []string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.allProjectsExclude
+}
+
+func (c *Config) SetAllProjectsExclude(exclude []string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.allProjectsExclude = exclude
+}
+
+// IsManifestSummaryEnabled returns true if a diagnostic summarizing the vulnerabilities is published at the top of
+// each manifest
+func (c *Config) IsManifestSummaryEnabled() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.manifestSummary
+}
+
+func (c *Config) SetManifestSummaryEnabled(enabled bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.manifestSummary = enabled
+}
+
+// PathPrivacy returns how file paths are included in outbound telemetry. Paths are omitted by default.
+func (c *Config) PathPrivacy() PathPrivacy {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.pathPrivacy
+}
+
+func (c *Config) SetPathPrivacy(level PathPrivacy) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.pathPrivacy = level
+}
+
+// UntrustedFolderBehavior returns what happens when a scan of an untrusted folder is skipped
+func (c *Config) UntrustedFolderBehavior() UntrustedFolderBehavior {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.untrustedFolderBehavior
+}
+
+func (c *Config) SetUntrustedFolderBehavior(behavior UntrustedFolderBehavior) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.untrustedFolderBehavior = behavior
+}
+
+// MaxResultAge returns the age after which cached results are marked as possibly outdated when they are published.
+// Results aren't evicted, the marker prompts a rescan. 0 never marks results.
+func (c *Config) MaxResultAge() time.Duration {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.maxResultAge
+}
+
+func (c *Config) SetMaxResultAge(maxAge time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.maxResultAge = maxAge
+}
+
+// AnalyticsEndpoint returns the base URL that both the UX events and the scan analytics are sent to, e.g. to route
+// all telemetry through a proxy. Empty means each client uses its default endpoint.
+func (c *Config) AnalyticsEndpoint() string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.analyticsEndpoint
+}
+
+func (c *Config) SetAnalyticsEndpoint(endpoint string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.analyticsEndpoint = strings.TrimSuffix(endpoint, "/")
+}
+
+// MinEpssScore returns the EPSS score Open Source issues need to reach to be shown. Issues without EPSS score are
+// always shown. 0 shows all issues.
+func (c *Config) MinEpssScore() float64 {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.minEpssScore
+}
+
+func (c *Config) SetMinEpssScore(minScore float64) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.minEpssScore = minScore
+}
+
+// AnalyticsDeduplicationWindow returns the time in which at most one scan done event is sent per folder and product,
+// so that rapid rescans don't inflate the analytics. 0 sends an event for every scan.
+func (c *Config) AnalyticsDeduplicationWindow() time.Duration {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.analyticsDeduplicationWindow
+}
+
+func (c *Config) SetAnalyticsDeduplicationWindow(window time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.analyticsDeduplicationWindow = window
+}
+
+// DiagnosticsPublishConcurrency returns how many files' diagnostics are published in parallel when many files are
+// published at once. 1 publishes them sequentially.
+func (c *Config) DiagnosticsPublishConcurrency() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.diagnosticsPublishConcurrency
+}
+
+func (c *Config) SetDiagnosticsPublishConcurrency(concurrency int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.diagnosticsPublishConcurrency = concurrency
+}
+
+// CliMaxMemoryMB returns the maximum heap size of the CLI in megabytes. 0 means the CLI's default is used.
+func (c *Config) CliMaxMemoryMB() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.cliMaxMemoryMB
+}
+
+func (c *Config) SetCliMaxMemoryMB(maxMemoryMB int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.cliMaxMemoryMB = maxMemoryMB
+}
+
+// DiagnosticsCloseGracePeriod returns how long the diagnostics of a closed file are kept before they are cleared, so
+// that flipping between files doesn't lose their findings. 0 clears them right away and
+// DiagnosticsCloseGracePeriodNever keeps them until the file is rescanned or deleted.
+func (c *Config) DiagnosticsCloseGracePeriod() time.Duration {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.diagnosticsCloseGracePeriod
+}
+
+func (c *Config) SetDiagnosticsCloseGracePeriod(gracePeriod time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.diagnosticsCloseGracePeriod = gracePeriod
+}
+
+// DevDependencyIssues returns how issues in development dependencies are treated, by default they are shown like
+// all other issues
+func (c *Config) DevDependencyIssues() DevDependencyIssues {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.devDependencyIssues
+}
+
+func (c *Config) SetDevDependencyIssues(treatment DevDependencyIssues) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.devDependencyIssues = treatment
+}
+
+// UnknownProductCounting returns how issues without a product are counted in the severity counts of a scan, by
+// default they are counted as "unknown"
+func (c *Config) UnknownProductCounting() UnknownProductCounting {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.unknownProductCounting
+}
+
+func (c *Config) SetUnknownProductCounting(counting UnknownProductCounting) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.unknownProductCounting = counting
+}
+
+// AdvisoryIdentifierPreference returns which advisory identifiers of open source issues are rendered, by default
+// CVEs are preferred over GHSA identifiers
+func (c *Config) AdvisoryIdentifierPreference() AdvisoryIdentifierPreference {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.advisoryIdentifierPreference
+}
+
+func (c *Config) SetAdvisoryIdentifierPreference(preference AdvisoryIdentifierPreference) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.advisoryIdentifierPreference = preference
+}
+
+// DiagnosticsPublishThrottle returns the minimum time between two diagnostics publishes of a folder. Publishes
+// within this time are coalesced into a single publish of the latest diagnostics. 0 publishes immediately.
+func (c *Config) DiagnosticsPublishThrottle() time.Duration {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.diagnosticsPublishThrottle
+}
+
+func (c *Config) SetDiagnosticsPublishThrottle(throttle time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.diagnosticsPublishThrottle = throttle
+}
+
+// AttestationSigningKeyPath returns the PEM file of the PKCS #8 private key exported attestations are signed with,
+// attestations are not signed if it's empty
+func (c *Config) AttestationSigningKeyPath() string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.attestationSigningKeyPath
+}
+
+func (c *Config) SetAttestationSigningKeyPath(path string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.attestationSigningKeyPath = path
+}
+
+// ManifestLockfileDuplicates returns how issues found in both a manifest and its lockfile are reported, by default
+// they are reported for both files
+func (c *Config) ManifestLockfileDuplicates() ManifestLockfileDuplicates {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.manifestLockfileDuplicates
+}
+
+func (c *Config) SetManifestLockfileDuplicates(duplicates ManifestLockfileDuplicates) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.manifestLockfileDuplicates = duplicates
+}
+
+// IsScanOnStartupEnabled returns true if the workspace folders are scanned automatically when the language server
+// starts or a folder is added. Otherwise, folders are scanned on demand or when a file of theirs is opened.
+func (c *Config) IsScanOnStartupEnabled() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.scanOnStartup
+}
+
+func (c *Config) SetScanOnStartup(enabled bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.scanOnStartup = enabled
+}
+
+// IsExploitSeverityEscalationEnabled returns true if the severity of open source issues with a mature public
+// exploit is raised by one level
+func (c *Config) IsExploitSeverityEscalationEnabled() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.exploitSeverityEscalation
+}
+
+func (c *Config) SetExploitSeverityEscalation(enabled bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.exploitSeverityEscalation = enabled
+}
+
+// AnalyticsDeviceID returns the device id to report in analytics events. When analytics are anonymized,
+// a random id that is regenerated on every language server start is returned instead of the stable device id.
+func (c *Config) AnalyticsDeviceID() string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.anonymizeAnalytics {
+		return c.analyticsSessionId
+	}
+	return c.deviceId
+}
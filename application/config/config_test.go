@@ -19,6 +19,7 @@ package config
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
 
 	"github.com/khulnasoft-lab/go-application-framework/pkg/auth"
@@ -160,6 +162,53 @@ func Test_loadFile(t *testing.T) {
 	assert.Equal(t, "D", os.Getenv("C"))
 }
 
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	fileName := filepath.Join(t.TempDir(), "vulnmap.env")
+	require.NoError(t, os.WriteFile(fileName, []byte(content), 0600))
+	return fileName
+}
+
+func Test_Load_LaterConfigFilesOverrideEarlierOnes(t *testing.T) {
+	t.Setenv("TEAM_ONLY", "")
+	t.Setenv("OVERRIDDEN", "")
+	_ = os.Unsetenv("TEAM_ONLY")
+	_ = os.Unsetenv("OVERRIDDEN")
+	teamFile := writeConfigFile(t, "TEAM_ONLY=team\nOVERRIDDEN=team")
+	personalFile := writeConfigFile(t, "OVERRIDDEN=personal")
+	c := New()
+	c.SetConfigFiles([]string{teamFile, personalFile})
+
+	c.Load()
+
+	assert.Equal(t, "team", os.Getenv("TEAM_ONLY"))
+	assert.Equal(t, "personal", os.Getenv("OVERRIDDEN"))
+}
+
+func Test_Load_MissingConfigFileIsSkipped(t *testing.T) {
+	t.Setenv("FROM_EXISTING_FILE", "")
+	_ = os.Unsetenv("FROM_EXISTING_FILE")
+	existingFile := writeConfigFile(t, "FROM_EXISTING_FILE=value")
+	missingFile := filepath.Join(t.TempDir(), "missing.env")
+	c := New()
+	c.SetConfigFiles([]string{existingFile, missingFile})
+
+	c.Load()
+
+	assert.Equal(t, "value", os.Getenv("FROM_EXISTING_FILE"))
+	assert.True(t, c.configLoaded.Get())
+}
+
+func Test_Load_EnvironmentWinsOverConfigFiles(t *testing.T) {
+	t.Setenv("ALREADY_SET", "env")
+	c := New()
+	c.SetConfigFile(writeConfigFile(t, "ALREADY_SET=file"))
+
+	c.Load()
+
+	assert.Equal(t, "env", os.Getenv("ALREADY_SET"))
+}
+
 func TestVulnmapCodeApi(t *testing.T) {
 	t.Run("endpoint not provided", func(t *testing.T) {
 
@@ -281,3 +330,107 @@ func Test_IsTelemetryEnabled(t *testing.T) {
 	assert.True(t, c.Engine().GetConfiguration().GetBool(configuration.ANALYTICS_DISABLED))
 
 }
+
+func Test_IsTelemetryEnabled_DoNotTrack(t *testing.T) {
+	t.Run("opted out via env var", func(t *testing.T) {
+		t.Setenv(DoNotTrack, "1")
+		c := New()
+		c.SetTelemetryEnabled(true)
+		c.SetAnalyticsEnabled(true)
+
+		assert.True(t, c.IsDoNotTrack())
+		assert.False(t, c.IsTelemetryEnabled())
+		assert.False(t, c.IsAnalyticsEnabled())
+		assert.True(t, c.Engine().GetConfiguration().GetBool(configuration.ANALYTICS_DISABLED))
+	})
+
+	t.Run("opted out via config", func(t *testing.T) {
+		t.Setenv(DoNotTrack, "")
+		c := New()
+		c.SetTelemetryEnabled(true)
+		c.SetAnalyticsEnabled(true)
+
+		c.SetDoNotTrack(true)
+		assert.False(t, c.IsTelemetryEnabled())
+		assert.False(t, c.IsAnalyticsEnabled())
+		assert.True(t, c.Engine().GetConfiguration().GetBool(configuration.ANALYTICS_DISABLED))
+
+		c.SetDoNotTrack(false)
+		assert.True(t, c.IsTelemetryEnabled())
+		assert.True(t, c.IsAnalyticsEnabled())
+		assert.False(t, c.Engine().GetConfiguration().GetBool(configuration.ANALYTICS_DISABLED))
+	})
+
+	t.Run("falsy env var doesn't opt out", func(t *testing.T) {
+		t.Setenv(DoNotTrack, "0")
+		c := New()
+
+		assert.False(t, c.IsDoNotTrack())
+		assert.True(t, c.IsTelemetryEnabled())
+	})
+}
+
+func Test_AnalyticsDeviceID(t *testing.T) {
+	t.Run("returns device id by default", func(t *testing.T) {
+		c := New()
+		c.SetDeviceID("device-id")
+
+		assert.Equal(t, "device-id", c.AnalyticsDeviceID())
+	})
+
+	t.Run("returns session id when anonymized", func(t *testing.T) {
+		c := New()
+		c.SetDeviceID("device-id")
+		c.SetAnonymizeAnalytics(true)
+
+		sessionId := c.AnalyticsDeviceID()
+		assert.NotEqual(t, "device-id", sessionId)
+		assert.NotEmpty(t, sessionId)
+		assert.Equal(t, sessionId, c.AnalyticsDeviceID())
+
+		other := New()
+		other.SetDeviceID("device-id")
+		other.SetAnonymizeAnalytics(true)
+		assert.NotEqual(t, sessionId, other.AnalyticsDeviceID())
+	})
+}
+
+func TestConfig_FormatFor(t *testing.T) {
+	c := New()
+	c.SetFormat(FormatHtml)
+	c.SetFormatFor(RenderContextHover, FormatMd)
+
+	assert.Equal(t, FormatMd, c.FormatFor(RenderContextHover))
+	assert.Equal(t, FormatHtml, c.FormatFor(RenderContextExport))
+	assert.Equal(t, FormatHtml, c.FormatFor(RenderContextDiagnostic))
+
+	c.SetFormatFor(RenderContextHover, "")
+	assert.Equal(t, FormatHtml, c.FormatFor(RenderContextHover))
+}
+
+func Test_ParseUnknownSeverityPolicy(t *testing.T) {
+	for value, expected := range map[string]UnknownSeverityPolicy{
+		"low":     UnknownSeverityAsLow,
+		"Medium":  UnknownSeverityAsMedium,
+		"unknown": UnknownSeverityAsIs,
+	} {
+		policy, ok := ParseUnknownSeverityPolicy(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, policy, value)
+	}
+
+	_, ok := ParseUnknownSeverityPolicy("high")
+	assert.False(t, ok)
+}
+
+func Test_SetSeverityMapping_MatchesReportedSeveritiesCaseInsensitively(t *testing.T) {
+	c := New()
+	assert.Nil(t, c.SeverityMapping())
+	assert.Equal(t, UnknownSeverityAsLow, c.UnknownSeverityPolicy())
+
+	c.SetSeverityMapping(map[string]string{"Moderate": "medium"})
+
+	assert.Equal(t, map[string]string{"moderate": "medium"}, c.SeverityMapping())
+	c.SetSeverityMapping(nil)
+	assert.Nil(t, c.SeverityMapping())
+}
@@ -0,0 +1,126 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/go-application-framework/pkg/configuration"
+)
+
+const redactedHeaderValue = "***"
+
+// sensitiveHeaderNameParts are parts of header names whose values must not be logged
+var sensitiveHeaderNameParts = []string{"auth", "token", "key", "secret", "password", "cookie", "session"}
+
+// CustomHttpHeaders returns the extra headers added to outbound requests, e.g. analytics and learn lookups
+func (c *Config) CustomHttpHeaders() map[string]string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	headers := make(map[string]string, len(c.customHttpHeaders))
+	for name, value := range c.customHttpHeaders {
+		headers[name] = value
+	}
+	return headers
+}
+
+// SetCustomHttpHeaders replaces the headers added to requests to the Vulnmap API hosts by the clients returned by
+// WithCustomHttpHeaders. Headers that are no longer configured are not sent anymore.
+func (c *Config) SetCustomHttpHeaders(headers map[string]string) {
+	canonicalHeaders := make(map[string]string, len(headers))
+	for name, value := range headers {
+		canonicalHeaders[http.CanonicalHeaderKey(name)] = value
+	}
+	c.m.Lock()
+	c.customHttpHeaders = canonicalHeaders
+	c.m.Unlock()
+	log.Info().Str("method", "SetCustomHttpHeaders").Interface("headers", RedactHttpHeaders(canonicalHeaders)).
+		Msg("custom HTTP headers set")
+}
+
+// WithCustomHttpHeaders wraps the http client factory, so that the returned clients add the custom HTTP headers to
+// requests to the Vulnmap API hosts, e.g. for analytics and learn lookups. Requests to other hosts, e.g. CLI downloads
+// or third party links, are sent unchanged, so that gateway credentials don't leak.
+func (c *Config) WithCustomHttpHeaders(httpClient func() *http.Client) func() *http.Client {
+	return func() *http.Client {
+		client := *httpClient()
+		transport := client.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		client.Transport = &customHttpHeadersRoundTripper{c: c, base: transport}
+		return &client
+	}
+}
+
+// isApiHost returns true if the host is the host of the Vulnmap API, the Vulnmap Code API or the analytics endpoint
+func (c *Config) isApiHost(host string) bool {
+	apiUrls := []string{
+		c.VulnmapApi(),
+		c.engine.GetConfiguration().GetString(configuration.API_URL),
+		c.VulnmapCodeApi(),
+		c.AnalyticsEndpoint(),
+	}
+	for _, apiUrl := range apiUrls {
+		parsed, err := url.Parse(apiUrl)
+		if err == nil && parsed.Host != "" && strings.EqualFold(parsed.Host, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// customHttpHeadersRoundTripper adds the custom HTTP headers configured at the time of the request to requests to
+// the Vulnmap API hosts
+type customHttpHeadersRoundTripper struct {
+	c    *Config
+	base http.RoundTripper
+}
+
+func (rt *customHttpHeadersRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	headers := rt.c.CustomHttpHeaders()
+	if len(headers) == 0 || !rt.c.isApiHost(request.URL.Host) {
+		return rt.base.RoundTrip(request)
+	}
+	// round trippers must not modify the original request
+	request = request.Clone(request.Context())
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	return rt.base.RoundTrip(request)
+}
+
+// RedactHttpHeaders returns a copy of the headers in which the values of sensitive headers, e.g. authorization
+// headers or API keys, are redacted, so that they can be logged
+func RedactHttpHeaders(headers map[string]string) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		redacted[name] = value
+		lowerName := strings.ToLower(name)
+		for _, part := range sensitiveHeaderNameParts {
+			if strings.Contains(lowerName, part) {
+				redacted[name] = redactedHeaderValue
+				break
+			}
+		}
+	}
+	return redacted
+}
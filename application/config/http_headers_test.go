@@ -0,0 +1,99 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactHttpHeaders_RedactsSensitiveValues(t *testing.T) {
+	headers := map[string]string{
+		"X-Gateway-Route":   "vulnmap",
+		"Authorization":     "Bearer abc",
+		"X-Api-Key":         "abc",
+		"X-Session-Id":      "abc",
+		"Proxy-Credentials": "abc",
+	}
+
+	redacted := RedactHttpHeaders(headers)
+
+	assert.Equal(t, map[string]string{
+		"X-Gateway-Route":   "vulnmap",
+		"Authorization":     redactedHeaderValue,
+		"X-Api-Key":         redactedHeaderValue,
+		"X-Session-Id":      redactedHeaderValue,
+		"Proxy-Credentials": "abc",
+	}, redacted)
+	assert.Equal(t, "Bearer abc", headers["Authorization"], "original headers should not be modified")
+}
+
+func TestSetCustomHttpHeaders_CanonicalizesHeaderNames(t *testing.T) {
+	c := New()
+
+	c.SetCustomHttpHeaders(map[string]string{"x-gateway-route": "vulnmap"})
+
+	assert.Equal(t, map[string]string{"X-Gateway-Route": "vulnmap"}, c.CustomHttpHeaders())
+}
+
+func headerRecordingServer(t *testing.T) (*httptest.Server, *http.Header) {
+	t.Helper()
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func TestWithCustomHttpHeaders_AddsHeadersOnlyToApiHosts(t *testing.T) {
+	c := New()
+	apiServer, apiHeaders := headerRecordingServer(t)
+	otherServer, otherHeaders := headerRecordingServer(t)
+	c.UpdateApiEndpoints(apiServer.URL)
+	c.SetCustomHttpHeaders(map[string]string{"X-Gateway-Auth": "secret"})
+	client := c.WithCustomHttpHeaders(func() *http.Client { return &http.Client{} })()
+
+	_, err := client.Get(apiServer.URL + "/v1/learn")
+	require.NoError(t, err)
+	_, err = client.Get(otherServer.URL + "/download")
+	require.NoError(t, err)
+
+	assert.Equal(t, "secret", apiHeaders.Get("X-Gateway-Auth"))
+	assert.Empty(t, otherHeaders.Get("X-Gateway-Auth"))
+}
+
+func TestWithCustomHttpHeaders_RemovedHeadersAreNotSent(t *testing.T) {
+	c := New()
+	server, headers := headerRecordingServer(t)
+	c.UpdateApiEndpoints(server.URL)
+	client := c.WithCustomHttpHeaders(func() *http.Client { return &http.Client{} })()
+	c.SetCustomHttpHeaders(map[string]string{"X-Gateway-Route": "vulnmap"})
+	_, err := client.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "vulnmap", headers.Get("X-Gateway-Route"))
+
+	c.SetCustomHttpHeaders(map[string]string{})
+	_, err = client.Get(server.URL)
+
+	require.NoError(t, err)
+	assert.Empty(t, headers.Get("X-Gateway-Route"))
+}
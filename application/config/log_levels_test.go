@@ -0,0 +1,73 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubsystemLogger_LogsAtOverrideLevelWhileOthersRespectGlobalLevel(t *testing.T) {
+	previousLevel := zerolog.GlobalLevel()
+	t.Cleanup(func() { zerolog.SetGlobalLevel(previousLevel) })
+	var buffer bytes.Buffer
+	c := New()
+	c.SetLogLevel("info")
+	require.NoError(t, c.SetSubsystemLogLevels("cli=debug, AUTH=trace"))
+	// ConfigureLogging restricts the default logger to the global level
+	logger := zerolog.New(&buffer).Level(zerolog.InfoLevel)
+	c.logger = &logger
+
+	c.SubsystemLogger(LogSubsystemCli).Debug().Msg("cli debug")
+	c.SubsystemLogger(LogSubsystemCli).Trace().Msg("cli trace")
+	c.SubsystemLogger(LogSubsystemAuth).Trace().Msg("auth trace")
+	c.SubsystemLogger(LogSubsystemScan).Debug().Msg("scan debug")
+	c.SubsystemLogger(LogSubsystemScan).Info().Msg("scan info")
+	c.Logger().Debug().Msg("global debug")
+	c.Logger().Info().Msg("global info")
+
+	output := buffer.String()
+	assert.Contains(t, output, "cli debug")
+	assert.NotContains(t, output, "cli trace")
+	assert.Contains(t, output, "auth trace")
+	assert.NotContains(t, output, "scan debug")
+	assert.Contains(t, output, "scan info")
+	assert.NotContains(t, output, "global debug")
+	assert.Contains(t, output, "global info")
+	assert.Equal(t, "info", c.LogLevel())
+}
+
+func TestSetSubsystemLogLevels_RejectsInvalidOverrides(t *testing.T) {
+	previousLevel := zerolog.GlobalLevel()
+	t.Cleanup(func() { zerolog.SetGlobalLevel(previousLevel) })
+	c := New()
+	c.SetLogLevel("info")
+	require.NoError(t, c.SetSubsystemLogLevels("cli=debug"))
+
+	assert.Error(t, c.SetSubsystemLogLevels("cli"))
+	assert.Error(t, c.SetSubsystemLogLevels("cli=loud"))
+	assert.Equal(t, zerolog.DebugLevel, c.SubsystemLogLevel(LogSubsystemCli))
+	assert.Equal(t, zerolog.InfoLevel, c.SubsystemLogLevel(LogSubsystemScan))
+
+	require.NoError(t, c.SetSubsystemLogLevels(""))
+	assert.Equal(t, zerolog.InfoLevel, c.SubsystemLogLevel(LogSubsystemCli))
+	assert.Equal(t, zerolog.InfoLevel, zerolog.GlobalLevel())
+}
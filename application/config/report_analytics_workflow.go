@@ -0,0 +1,87 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/pflag"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/khulnasoft-lab/go-application-framework/pkg/configuration"
+	localworkflows "github.com/khulnasoft-lab/go-application-framework/pkg/local_workflows"
+	"github.com/khulnasoft-lab/go-application-framework/pkg/local_workflows/json_schemas"
+	"github.com/khulnasoft-lab/go-application-framework/pkg/workflow"
+)
+
+var scanDoneSchemaLoader = gojsonschema.NewStringLoader(json_schemas.ScanDoneEventSchema)
+
+// RegisterReportAnalyticsWorkflow replaces the reportAnalytics workflow of the engine with one that sends the
+// analytics with the custom HTTP headers. The network access of the engine can only add headers to all requests and
+// never remove them, so the headers are added by the workflow instead.
+func (c *Config) RegisterReportAnalyticsWorkflow() error {
+	flags := pflag.NewFlagSet("reportAnalytics", pflag.ExitOnError)
+	_, err := c.engine.Register(
+		localworkflows.WORKFLOWID_REPORT_ANALYTICS,
+		workflow.ConfigurationOptionsFromFlagset(flags),
+		c.reportAnalyticsEntrypoint,
+	)
+	return err
+}
+
+func (c *Config) reportAnalyticsEntrypoint(invocationCtx workflow.InvocationContext, inputData []workflow.Data) ([]workflow.Data, error) {
+	conf := invocationCtx.GetConfiguration()
+	url := fmt.Sprintf("%s/rest/api/orgs/%s/analytics", conf.GetString(configuration.API_URL), conf.Get(configuration.ORGANIZATION))
+	httpClient := c.WithCustomHttpHeaders(invocationCtx.GetNetworkAccess().GetHttpClient)()
+	for i, input := range inputData {
+		payload, ok := input.GetPayload().([]byte)
+		if !ok {
+			return nil, fmt.Errorf("payload at index %d is not a byte slice", i)
+		}
+		result, err := gojsonschema.Validate(scanDoneSchemaLoader, gojsonschema.NewBytesLoader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("error validating input at index %d: %w", i, err)
+		}
+		if !result.Valid() {
+			return nil, fmt.Errorf("validation failed for input at index %d: %v", i, result.Errors())
+		}
+		if err = sendAnalytics(httpClient, url, input.GetContentType(), payload); err != nil {
+			return nil, fmt.Errorf("error calling endpoint for input at index %d: %w", i, err)
+		}
+	}
+	return nil, nil
+}
+
+func sendAnalytics(httpClient *http.Client, url string, contentType string, payload []byte) error {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", contentType)
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) { _ = Body.Close() }(response.Body)
+	if response.StatusCode != http.StatusCreated {
+		return fmt.Errorf("unexpected response status: %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TrustedFolderPatternPrefix marks a trusted folder entry as a glob pattern instead of a path prefix, see
+// filepath.Match. Entries without it are paths, even if they contain glob characters.
+const TrustedFolderPatternPrefix = "glob:"
+
+// trustedFolderPatternChars are the wildcard characters of a glob pattern, see filepath.Match
+const trustedFolderPatternChars = "*?["
+
+// minTrustedFolderPatternDepth is the minimum number of directories before the first wildcard of a pattern, so that
+// patterns like /* or /home/* can't trust whole file systems
+const minTrustedFolderPatternDepth = 2
+
+// IsTrustedFolderPattern returns true if the trusted folder entry is a glob pattern, e.g. glob:~/src/myorg-*
+func IsTrustedFolderPattern(entry string) bool {
+	return strings.HasPrefix(entry, TrustedFolderPatternPrefix)
+}
+
+// ValidateTrustedFolderPattern returns an error if the pattern entry is malformed or would trust too broadly
+func ValidateTrustedFolderPattern(entry string) error {
+	pattern := expandHome(strings.TrimPrefix(entry, TrustedFolderPatternPrefix))
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return errors.Wrapf(err, "invalid trusted folder pattern %s", pattern)
+	}
+
+	staticPart := pattern
+	if i := strings.IndexAny(pattern, trustedFolderPatternChars); i >= 0 {
+		staticPart = pattern[:i]
+	}
+	staticDir := filepath.Clean(staticPart)
+	if !strings.HasSuffix(staticPart, string(filepath.Separator)) {
+		staticDir = filepath.Dir(staticDir)
+	}
+	if !filepath.IsAbs(staticDir) {
+		return errors.Errorf("trusted folder pattern %s must be an absolute path", pattern)
+	}
+	if home, err := os.UserHomeDir(); err == nil && staticDir == filepath.Clean(home) {
+		return errors.Errorf("trusted folder pattern %s would trust the whole home directory", pattern)
+	}
+	depth := 0
+	for _, segment := range strings.Split(staticDir[len(filepath.VolumeName(staticDir)):], string(filepath.Separator)) {
+		if segment != "" {
+			depth++
+		}
+	}
+	if depth < minTrustedFolderPatternDepth {
+		return errors.Errorf("trusted folder pattern %s is too broad", pattern)
+	}
+	return nil
+}
+
+// MatchesTrustedFolder returns true if the folder is trusted by the entry. Paths trust all folders they are a prefix
+// of. Patterns trust the folders matching them and their sub folders, invalid or too broad patterns trust nothing.
+func MatchesTrustedFolder(entry string, folderPath string) bool {
+	if !IsTrustedFolderPattern(entry) {
+		return strings.HasPrefix(folderPath, entry)
+	}
+	if ValidateTrustedFolderPattern(entry) != nil {
+		return false
+	}
+
+	pattern := expandHome(strings.TrimPrefix(entry, TrustedFolderPatternPrefix))
+	for path := filepath.Clean(folderPath); ; path = filepath.Dir(path) {
+		if matched, _ := filepath.Match(pattern, path); matched {
+			return true
+		}
+		if path == filepath.Dir(path) {
+			return false
+		}
+	}
+}
+
+// expandHome replaces a leading ~ with the home directory of the user
+func expandHome(path string) string {
+	rest, found := strings.CutPrefix(path, "~")
+	if !found || (rest != "" && rest[0] != '/' && rest[0] != filepath.Separator) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
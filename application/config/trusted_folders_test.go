@@ -0,0 +1,85 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTrustedFolderPattern(t *testing.T) {
+	assert.True(t, IsTrustedFolderPattern("glob:/home/dev/src/myorg-*"))
+	assert.True(t, IsTrustedFolderPattern("glob:/home/dev/src/service-?"))
+	assert.False(t, IsTrustedFolderPattern("/home/dev/src/myorg"))
+	assert.False(t, IsTrustedFolderPattern("/home/dev/src/myorg-*"), "glob characters without the prefix are a path")
+}
+
+func TestValidateTrustedFolderPattern(t *testing.T) {
+	if runtime.GOOS == windows {
+		t.Skipf("Unix/macOS file paths are incompatible with Windows")
+	}
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	assert.NoError(t, ValidateTrustedFolderPattern("glob:/home/dev/src/myorg-*"))
+	assert.NoError(t, ValidateTrustedFolderPattern("glob:/home/dev/*/api"))
+	assert.NoError(t, ValidateTrustedFolderPattern("glob:~/src/myorg-*"))
+	assert.NoError(t, ValidateTrustedFolderPattern("glob:/home/dev/src/myorg"))
+
+	for _, pattern := range []string{"/*", "/home/*", "*", "src/*", "~/*", filepath.Join(home, "*"), "/home/dev/src/[a-", "/"} {
+		assert.Error(t, ValidateTrustedFolderPattern("glob:"+pattern), pattern)
+	}
+}
+
+func TestMatchesTrustedFolder(t *testing.T) {
+	if runtime.GOOS == windows {
+		t.Skipf("Unix/macOS file paths are incompatible with Windows")
+	}
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	t.Run("paths are prefixes", func(t *testing.T) {
+		assert.True(t, MatchesTrustedFolder("/home/dev/src", "/home/dev/src/project"))
+		assert.False(t, MatchesTrustedFolder("/home/dev/src", "/home/dev/other"))
+	})
+
+	t.Run("paths with glob characters are prefixes", func(t *testing.T) {
+		assert.True(t, MatchesTrustedFolder("/home/dev/src/[draft]", "/home/dev/src/[draft]/project"))
+		assert.False(t, MatchesTrustedFolder("/home/dev/src/[draft]", "/home/dev/src/d"))
+	})
+
+	t.Run("patterns match the folder or its parents", func(t *testing.T) {
+		assert.True(t, MatchesTrustedFolder("glob:/home/dev/src/myorg-*", "/home/dev/src/myorg-api"))
+		assert.True(t, MatchesTrustedFolder("glob:/home/dev/src/myorg-*", "/home/dev/src/myorg-api/sub"))
+		assert.False(t, MatchesTrustedFolder("glob:/home/dev/src/myorg-*", "/home/dev/src/other-api"))
+		assert.False(t, MatchesTrustedFolder("glob:/home/dev/src/myorg-*", "/home/dev/src"))
+	})
+
+	t.Run("patterns expand the home directory", func(t *testing.T) {
+		assert.True(t, MatchesTrustedFolder("glob:~/src/myorg-*", filepath.Join(home, "src", "myorg-api")))
+	})
+
+	t.Run("too broad patterns trust nothing", func(t *testing.T) {
+		assert.False(t, MatchesTrustedFolder("glob:/*", "/home"))
+		assert.False(t, MatchesTrustedFolder("glob:/home/*", "/home/dev"))
+	})
+}
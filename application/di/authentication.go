@@ -0,0 +1,85 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/khulnasoft-lab/go-application-framework/pkg/auth"
+	"github.com/khulnasoft-lab/go-application-framework/pkg/configuration"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	cliauth "github.com/khulnasoft-lab/vulnmap-ls/infrastructure/cli/auth"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/oauth"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// NewAuthenticationProvider returns the authentication provider of the method, token authentication is done by the CLI
+func NewAuthenticationProvider(method lsp.AuthenticationMethod) vulnmap.AuthenticationProvider {
+	c := config.CurrentConfig()
+	if method == lsp.OAuthAuthentication {
+		return NewOAuthProvider(c, auth.RefreshToken)
+	}
+	c.Engine().GetConfiguration().Set(configuration.FF_OAUTH_AUTH_FLOW_ENABLED, false)
+	return cliauth.NewCliAuthenticationProvider(ErrorReporter())
+}
+
+func NewOAuthProvider(
+	c *config.Config,
+	customTokenRefresherFunc func(
+		ctx context.Context,
+		oauthConfig *oauth2.Config,
+		token *oauth2.Token,
+	) (*oauth2.Token, error),
+) vulnmap.AuthenticationProvider {
+	engine := c.Engine()
+	conf := engine.GetConfiguration()
+
+	authenticationService := AuthenticationService()
+
+	openBrowserFunc := func(url string) {
+		authenticationService.Provider().SetAuthURL(url)
+		vulnmap.DefaultOpenBrowserFunc(url)
+	}
+	conf.Set(configuration.FF_OAUTH_AUTH_FLOW_ENABLED, true)
+
+	c.Storage().RegisterCallback(auth.CONFIG_KEY_OAUTH_TOKEN, credentialsUpdateCallback)
+
+	authenticator := auth.NewOAuth2AuthenticatorWithOpts(
+		conf,
+		auth.WithOpenBrowserFunc(openBrowserFunc),
+		auth.WithTokenRefresherFunc(customTokenRefresherFunc),
+	)
+	return oauth.NewOAuthProvider(conf, authenticator)
+}
+
+func credentialsUpdateCallback(_ string, value any) {
+	newToken, ok := value.(string)
+	if !ok {
+		msg := fmt.Sprintf("Failed to cast token value of type %T to string", value)
+		log.Error().Str("method", "storage callback token").
+			Msgf(msg)
+		ErrorReporter().CaptureError(errors.New(msg))
+		return
+	}
+	go AuthenticationService().UpdateCredentials(newToken, true)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/cli/cli_constants"
 
 	"github.com/adrg/xdg"
+	"github.com/rs/zerolog/log"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/codeaction"
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
@@ -72,6 +73,7 @@ var codeActionService *codeaction.CodeActionsService
 var fileWatcher *watcher.FileWatcher
 var initMutex = &sync.Mutex{}
 var notifier notification.Notifier
+var idleLogout *vulnmap.IdleLogout
 
 func Init() {
 	initMutex.Lock()
@@ -95,6 +97,9 @@ func initDomain() {
 		infrastructureAsCodeScanner,
 		openSourceScanner,
 	)
+	if delegatingScanner, ok := scanner.(*vulnmap.DelegatingConcurrentScanner); ok {
+		delegatingScanner.SetIdleLogout(idleLogout)
+	}
 }
 
 func initInfrastructure() {
@@ -120,15 +125,25 @@ func initInfrastructure() {
 	// init NetworkAccess
 	networkAccess := c.Engine().GetNetworkAccess()
 
-	notifier = domainNotify.NewNotifier()
-	errorReporter = sentry.NewSentryErrorReporter(notifier)
+	notifier = domainNotify.NewLevelFilteringNotifier(
+		domainNotify.NewDeduplicatingNotifier(domainNotify.NewNotifier(), domainNotify.DefaultShowMessageDeduplicationWindow),
+	)
+	errorReporter = er.NewDeduplicatingErrorReporter(
+		sentry.NewSentryErrorReporter(notifier),
+		er.DefaultDeduplicationWindow,
+		er.DefaultMaxReportsPerWindow,
+	)
 	installer = install.NewInstaller(errorReporter, networkAccess.GetUnauthorizedHttpClient)
-	learnService = learn.New(c, networkAccess.GetUnauthorizedHttpClient, errorReporter)
+	learnService = learn.New(c, c.WithCustomHttpHeaders(networkAccess.GetUnauthorizedHttpClient), errorReporter)
 	instrumentor = performance.NewInstrumentor()
 	vulnmapApiClient = vulnmap_api.NewVulnmapApiClient(networkAccess.GetHttpClient)
+	if err := vulnmap_api.RegisterWhoamiWorkflow(c.Engine()); err != nil {
+		log.Err(err).Msg("unable to register whoami workflow")
+	}
 	analytics = amplitude.NewAmplitudeClient(vulnmap.AuthenticationCheck, errorReporter)
 	authProvider := cliauth.NewCliAuthenticationProvider(errorReporter)
 	authenticationService = vulnmap.NewAuthenticationService(authProvider, analytics, errorReporter, notifier)
+	idleLogout = vulnmap.NewIdleLogout(authenticationService, notifier)
 	vulnmapCli := cli.NewExecutor(authenticationService, errorReporter, analytics, notifier)
 
 	if c.Engine().GetConfiguration().GetString(cli_constants.EXECUTION_MODE_KEY) == cli_constants.EXECUTION_MODE_VALUE_EXTENSION {
@@ -151,10 +166,11 @@ func initInfrastructure() {
 
 func initApplication() {
 	w := workspace.New(instrumentor, scanner, hoverService, scanNotifier, notifier) // don't use getters or it'll deadlock
+	w.SetErrorReporter(errorReporter)
 	workspace.Set(w)
 	fileWatcher = watcher.NewFileWatcher()
 	codeActionService = codeaction.NewService(config.CurrentConfig(), w, fileWatcher, notifier, vulnmapCodeClient)
-	command.SetService(command.NewService(authenticationService, notifier, learnService, w, vulnmapCodeClient))
+	command.SetService(command.NewService(authenticationService, notifier, learnService, w, vulnmapCodeClient, NewAuthenticationProvider))
 }
 
 /*
@@ -228,6 +244,12 @@ func FileWatcher() *watcher.FileWatcher {
 	return fileWatcher
 }
 
+func IdleLogout() *vulnmap.IdleLogout {
+	initMutex.Lock()
+	defer initMutex.Unlock()
+	return idleLogout
+}
+
 func LearnService() learn.Service {
 	initMutex.Lock()
 	defer initMutex.Unlock()
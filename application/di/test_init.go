@@ -76,7 +76,7 @@ func TestInit(t *testing.T) {
 	learnMock := mock_learn.NewMockService(gomock.NewController(t))
 	learnMock.
 		EXPECT().
-		GetLesson(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		GetLesson(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(&learn.Lesson{}, nil).AnyTimes()
 	learnService = learnMock
 	vulnmapCodeScanner = code.New(vulnmapCodeBundleUploader, vulnmapApiClient, errorReporter, analytics, learnService, notifier)
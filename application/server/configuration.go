@@ -18,17 +18,16 @@ package server
 
 import (
 	"context"
-	"errors"
-	"fmt"
+	"net/url"
 	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/creachadair/jrpc2"
 	"github.com/creachadair/jrpc2/handler"
 	"github.com/rs/zerolog/log"
-	"github.com/khulnasoft-lab/go-application-framework/pkg/auth"
 	"github.com/khulnasoft-lab/go-application-framework/pkg/configuration"
 	"golang.org/x/oauth2"
 
@@ -37,9 +36,9 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/ux"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
-	auth2 "github.com/khulnasoft-lab/vulnmap-ls/infrastructure/cli/auth"
-	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/oauth"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/oss"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 )
 
 var cachedOriginalPath = ""
@@ -98,6 +97,7 @@ func UpdateSettings(settings lsp.Settings) {
 	currentConfig := config.CurrentConfig()
 	previouslyEnabledProducts := currentConfig.DisplayableIssueTypes()
 	previousAutoScan := currentConfig.IsAutoScanEnabled()
+	previousConfig := workspace.TakeConfigSnapshot(currentConfig)
 
 	writeSettings(settings, false)
 
@@ -110,6 +110,7 @@ func UpdateSettings(settings lsp.Settings) {
 				ws.ClearIssuesByType(removedIssueType)
 			}
 		}
+		ws.HandleConfigChange(context.Background(), previousConfig.ChangesTo(currentConfig))
 	}
 
 	if currentConfig.IsAutoScanEnabled() != previousAutoScan {
@@ -145,6 +146,61 @@ func writeSettings(settings lsp.Settings, initialize bool) {
 	updateRuntimeInfo(settings)
 	updateAutoScan(settings)
 	updateVulnmapLearnCodeActions(settings)
+	updateChangedFilesScan(settings)
+	updateAnonymizeAnalytics(settings)
+	updatePersistIssueCache(settings)
+	updateProductDisplayOrder(settings)
+	updateSuppressionPolicyPath(settings)
+	updateLockfileIssueRemapping(settings)
+	updateLearnLessonLookupConcurrency(settings)
+	updateIssueFingerprinting(settings)
+	updateScanFileMappings(settings)
+	updateOssOutputVersion(settings)
+	updateNotificationLevel(settings)
+	updateCustomHttpHeaders(settings)
+	updateMaxIssuesPerScan(settings)
+	updateFixabilityFilter(settings)
+	updateFileDeletionWatcher(settings)
+	updateIdleLogoutTimeout(settings)
+	updateInlineValueKinds(settings)
+	updateBaseline(settings)
+	updateBaselinePath(settings)
+	updateAcceptedIssuesPath(settings)
+	updateIssueTagsPath(settings)
+	updateFilePathDisplay(settings)
+	updateLicenseSeverities(settings)
+	updateRegistryCertificates(settings)
+	updateLinkStyle(settings)
+	updateMaxMessageLength(settings)
+	updateProjectIssuesTargetFile(settings)
+	updateIssueIdentifier(settings)
+	updateIssueUrlTemplates(settings)
+	updateProductScans(settings)
+	updateIncludeLineOwners(settings)
+	updateScanDebounce(settings)
+	updateAllProjects(settings)
+	updateManifestSummary(settings)
+	updatePathPrivacy(settings)
+	updateUntrustedFolderBehavior(settings)
+	updateMaxResultAge(settings)
+	updateAnalyticsEndpoint(settings)
+	updateMinEpssScore(settings)
+	updateAnalyticsDeduplicationWindow(settings)
+	updateDiagnosticsPublishConcurrency(settings)
+	updateCliMaxMemory(settings)
+	updateDiagnosticsCloseGracePeriod(settings)
+	updateDevDependencyIssues(settings)
+	updateOutputFormats(settings)
+	updateUnknownProductCounting(settings)
+	updateAdvisoryIdentifierPreference(settings)
+	updateDiagnosticsPublishThrottle(settings)
+	updateAttestationSigningKeyPath(settings)
+	updateManifestLockfileDuplicates(settings)
+	updateScanOnStartup(settings)
+	updateIssueTagFilter(settings)
+	updateExploitSeverityEscalation(settings)
+	updateSeverityMapping(settings)
+	updateUnknownSeverityPolicy(settings)
 
 	if initialize {
 		config.CurrentConfig().SetAnalyticsEnabled(settings.EnableAnalytics)
@@ -157,24 +213,7 @@ func updateAuthenticationMethod(settings lsp.Settings) {
 	}
 	c := config.CurrentConfig()
 	c.SetAuthenticationMethod(settings.AuthenticationMethod)
-	if config.CurrentConfig().AuthenticationMethod() == lsp.OAuthAuthentication {
-		configureOAuth(c, auth.RefreshToken)
-	} else {
-		cliAuthenticationProvider := auth2.NewCliAuthenticationProvider(di.ErrorReporter())
-		di.AuthenticationService().SetProvider(cliAuthenticationProvider)
-	}
-}
-
-func credentialsUpdateCallback(_ string, value any) {
-	newToken, ok := value.(string)
-	if !ok {
-		msg := fmt.Sprintf("Failed to cast token value of type %T to string", value)
-		log.Error().Str("method", "storage callback token").
-			Msgf(msg)
-		di.ErrorReporter().CaptureError(errors.New(msg))
-		return
-	}
-	go di.AuthenticationService().UpdateCredentials(newToken, true)
+	di.AuthenticationService().SetProvider(di.NewAuthenticationProvider(c.AuthenticationMethod()))
 }
 
 func configureOAuth(
@@ -185,26 +224,7 @@ func configureOAuth(
 		token *oauth2.Token,
 	) (*oauth2.Token, error),
 ) {
-	engine := c.Engine()
-	conf := engine.GetConfiguration()
-
-	authenticationService := di.AuthenticationService()
-
-	openBrowserFunc := func(url string) {
-		authenticationService.Provider().SetAuthURL(url)
-		vulnmap.DefaultOpenBrowserFunc(url)
-	}
-	conf.Set(configuration.FF_OAUTH_AUTH_FLOW_ENABLED, true)
-
-	c.Storage().RegisterCallback(auth.CONFIG_KEY_OAUTH_TOKEN, credentialsUpdateCallback)
-
-	authenticator := auth.NewOAuth2AuthenticatorWithOpts(
-		conf,
-		auth.WithOpenBrowserFunc(openBrowserFunc),
-		auth.WithTokenRefresherFunc(customTokenRefresherFunc),
-	)
-	oAuthProvider := oauth.NewOAuthProvider(conf, authenticator)
-	authenticationService.SetProvider(oAuthProvider)
+	di.AuthenticationService().SetProvider(di.NewOAuthProvider(c, customTokenRefresherFunc))
 }
 
 func updateRuntimeInfo(settings lsp.Settings) {
@@ -224,7 +244,17 @@ func updateTrustedFolders(settings lsp.Settings) {
 	}
 
 	if settings.TrustedFolders != nil {
-		config.CurrentConfig().SetTrustedFolders(settings.TrustedFolders)
+		trustedFolders := make([]string, 0, len(settings.TrustedFolders))
+		for _, entry := range settings.TrustedFolders {
+			if config.IsTrustedFolderPattern(entry) {
+				if err := config.ValidateTrustedFolderPattern(entry); err != nil {
+					log.Warn().Err(err).Str("method", "updateTrustedFolders").Msg("ignoring trusted folder pattern")
+					continue
+				}
+			}
+			trustedFolders = append(trustedFolders, entry)
+		}
+		config.CurrentConfig().SetTrustedFolders(trustedFolders)
 	}
 }
 
@@ -265,6 +295,681 @@ func updateVulnmapLearnCodeActions(settings lsp.Settings) {
 	config.CurrentConfig().SetVulnmapLearnCodeActionsEnabled(enable)
 }
 
+func updateChangedFilesScan(settings lsp.Settings) {
+	parseBool, err := strconv.ParseBool(settings.ScanChangedFilesOnly)
+	if err != nil {
+		log.Debug().Msgf("couldn't read scan changed files only %s", settings.ScanChangedFilesOnly)
+	} else {
+		config.CurrentConfig().SetScanChangedFilesOnly(parseBool)
+	}
+	config.CurrentConfig().SetChangedFilesBaseRef(strings.TrimSpace(settings.ChangedFilesBaseRef))
+}
+
+func updateAnonymizeAnalytics(settings lsp.Settings) {
+	parseBool, err := strconv.ParseBool(settings.AnonymizeAnalytics)
+	if err != nil {
+		log.Debug().Msgf("couldn't read anonymize analytics %s", settings.AnonymizeAnalytics)
+	} else {
+		config.CurrentConfig().SetAnonymizeAnalytics(parseBool)
+	}
+}
+
+func updatePersistIssueCache(settings lsp.Settings) {
+	parseBool, err := strconv.ParseBool(settings.PersistIssueCache)
+	if err != nil {
+		log.Debug().Msgf("couldn't read persist issue cache %s", settings.PersistIssueCache)
+	} else {
+		config.CurrentConfig().SetIssueCachePersistence(parseBool)
+	}
+}
+
+func updateProductDisplayOrder(settings lsp.Settings) {
+	if settings.ProductDisplayOrder == nil {
+		return
+	}
+	var order []product.Product
+	for _, codename := range settings.ProductDisplayOrder {
+		p := product.FromProductCodename(codename)
+		if p == product.ProductUnknown {
+			log.Debug().Msgf("couldn't read product display order entry %s", codename)
+			continue
+		}
+		order = append(order, p)
+	}
+	config.CurrentConfig().SetProductDisplayOrder(order)
+}
+
+func updateSuppressionPolicyPath(settings lsp.Settings) {
+	c := config.CurrentConfig()
+	if settings.SuppressionPolicyPath == "" || settings.SuppressionPolicyPath == c.SuppressionPolicyPath() {
+		return
+	}
+	c.SetSuppressionPolicyPath(settings.SuppressionPolicyPath)
+	vulnmap.LogSuppressionPolicyProblems(settings.SuppressionPolicyPath, time.Now())
+}
+
+func updateLockfileIssueRemapping(settings lsp.Settings) {
+	parseBool, err := strconv.ParseBool(settings.RemapLockfileIssuesToManifest)
+	if err != nil {
+		log.Debug().Msgf("couldn't read remap lockfile issues to manifest %s", settings.RemapLockfileIssuesToManifest)
+	} else {
+		config.CurrentConfig().SetLockfileIssueRemapping(parseBool)
+	}
+}
+
+func updateLearnLessonLookupConcurrency(settings lsp.Settings) {
+	if settings.LearnLessonLookupConcurrency == "" {
+		return
+	}
+	concurrency, err := strconv.Atoi(settings.LearnLessonLookupConcurrency)
+	if err != nil || concurrency < 1 {
+		log.Debug().Msgf("couldn't read learn lesson lookup concurrency %s", settings.LearnLessonLookupConcurrency)
+		return
+	}
+	config.CurrentConfig().SetLearnLessonLookupConcurrency(concurrency)
+}
+
+func updateIssueFingerprinting(settings lsp.Settings) {
+	parseBool, err := strconv.ParseBool(settings.IncludeIssueFingerprints)
+	if err != nil {
+		log.Debug().Msgf("couldn't read include issue fingerprints %s", settings.IncludeIssueFingerprints)
+	} else {
+		config.CurrentConfig().SetIssueFingerprinting(parseBool)
+	}
+}
+
+func updateScanFileMappings(settings lsp.Settings) {
+	if settings.ScanFileMappings == nil {
+		return
+	}
+	mappings := map[string][]product.Product{}
+	for pattern, codenames := range settings.ScanFileMappings {
+		for _, codename := range codenames {
+			p := product.FromProductCodename(codename)
+			if p == product.ProductUnknown {
+				log.Debug().Msgf("couldn't read product %s of scan file mapping %s", codename, pattern)
+				continue
+			}
+			mappings[pattern] = append(mappings[pattern], p)
+		}
+	}
+	config.CurrentConfig().SetScanFileMappings(mappings)
+}
+
+func updateOssOutputVersion(settings lsp.Settings) {
+	if settings.OssOutputVersion == "" {
+		return
+	}
+	config.CurrentConfig().SetOssOutputVersion(settings.OssOutputVersion)
+}
+
+func updateNotificationLevel(settings lsp.Settings) {
+	if settings.NotificationLevel == "" {
+		return
+	}
+	level, ok := config.ParseNotificationLevel(settings.NotificationLevel)
+	if !ok {
+		log.Debug().Msgf("couldn't read notification level %s", settings.NotificationLevel)
+		return
+	}
+	config.CurrentConfig().SetNotificationLevel(level)
+}
+
+func updateCustomHttpHeaders(settings lsp.Settings) {
+	if settings.CustomHttpHeaders == nil {
+		return
+	}
+	config.CurrentConfig().SetCustomHttpHeaders(settings.CustomHttpHeaders)
+}
+
+func updateMaxIssuesPerScan(settings lsp.Settings) {
+	if settings.MaxIssuesPerScan == "" {
+		return
+	}
+	maxIssues, err := strconv.Atoi(settings.MaxIssuesPerScan)
+	if err != nil || maxIssues < 0 {
+		log.Debug().Msgf("couldn't read max issues per scan %s", settings.MaxIssuesPerScan)
+		return
+	}
+	config.CurrentConfig().SetMaxIssuesPerScan(maxIssues)
+}
+
+func updateFixabilityFilter(settings lsp.Settings) {
+	c := config.CurrentConfig()
+	if settings.ShowOnlyFixable != "" {
+		showOnlyFixable, err := strconv.ParseBool(settings.ShowOnlyFixable)
+		if err != nil {
+			log.Debug().Msgf("couldn't read show only fixable %s", settings.ShowOnlyFixable)
+		} else {
+			c.SetShowOnlyFixable(showOnlyFixable)
+		}
+	}
+	if settings.IncludeMajorUpgradeFixes != "" {
+		includeMajorUpgrades, err := strconv.ParseBool(settings.IncludeMajorUpgradeFixes)
+		if err != nil {
+			log.Debug().Msgf("couldn't read include major upgrade fixes %s", settings.IncludeMajorUpgradeFixes)
+		} else {
+			c.SetMajorUpgradeFixIncluded(includeMajorUpgrades)
+		}
+	}
+}
+
+func updateFileDeletionWatcher(settings lsp.Settings) {
+	enabled, err := strconv.ParseBool(settings.EnableFileDeletionWatcher)
+	if err != nil {
+		log.Debug().Msgf("couldn't read enable file deletion watcher %s", settings.EnableFileDeletionWatcher)
+		return
+	}
+	config.CurrentConfig().SetFileDeletionWatcherEnabled(enabled)
+}
+
+func updateIdleLogoutTimeout(settings lsp.Settings) {
+	if settings.IdleLogoutTimeout == "" {
+		return
+	}
+	timeout, err := time.ParseDuration(settings.IdleLogoutTimeout)
+	if err != nil || timeout < 0 {
+		log.Warn().Err(err).Msgf("ignoring invalid idle logout timeout %s", settings.IdleLogoutTimeout)
+		return
+	}
+	config.CurrentConfig().SetIdleLogoutTimeout(timeout)
+	di.IdleLogout().Activity()
+}
+
+func updateInlineValueKinds(settings lsp.Settings) {
+	if settings.InlineValueKinds == nil {
+		return
+	}
+	config.CurrentConfig().SetInlineValueKinds(settings.InlineValueKinds)
+}
+
+func updateBaseline(settings lsp.Settings) {
+	enabled, err := strconv.ParseBool(settings.EnableBaseline)
+	if err != nil {
+		log.Debug().Msgf("couldn't read enable baseline %s", settings.EnableBaseline)
+		return
+	}
+	config.CurrentConfig().SetBaselineEnabled(enabled)
+}
+
+func updateBaselinePath(settings lsp.Settings) {
+	if settings.BaselinePath == "" {
+		return
+	}
+	config.CurrentConfig().SetBaselinePath(settings.BaselinePath)
+}
+
+func updateAcceptedIssuesPath(settings lsp.Settings) {
+	if settings.AcceptedIssuesPath == "" {
+		return
+	}
+	config.CurrentConfig().SetAcceptedIssuesPath(settings.AcceptedIssuesPath)
+}
+
+func updateIssueTagsPath(settings lsp.Settings) {
+	if settings.IssueTagsPath == "" {
+		return
+	}
+	config.CurrentConfig().SetIssueTagsPath(settings.IssueTagsPath)
+}
+
+func updateFilePathDisplay(settings lsp.Settings) {
+	switch settings.FilePathDisplay {
+	case "":
+	case "relative":
+		config.CurrentConfig().SetRelativeFilePathDisplay(true)
+	case "absolute":
+		config.CurrentConfig().SetRelativeFilePathDisplay(false)
+	default:
+		log.Debug().Msgf("couldn't read file path display %s", settings.FilePathDisplay)
+	}
+}
+
+func updateLicenseSeverities(settings lsp.Settings) {
+	if settings.LicenseSeverities == nil {
+		return
+	}
+	severities := map[string]string{}
+	for license, severity := range settings.LicenseSeverities {
+		severity = strings.ToLower(severity)
+		switch severity {
+		case "critical", "high", "medium", "low":
+			severities[license] = severity
+		default:
+			log.Debug().Msgf("couldn't read severity %s of license %s", severity, license)
+		}
+	}
+	config.CurrentConfig().SetLicenseSeverities(severities)
+}
+
+func updateRegistryCertificates(settings lsp.Settings) {
+	if settings.RegistryCertificates == nil {
+		return
+	}
+	config.CurrentConfig().SetRegistryCertificates(settings.RegistryCertificates)
+}
+
+func updateLinkStyle(settings lsp.Settings) {
+	if settings.LinkStyle == "" {
+		return
+	}
+	style, ok := config.ParseLinkStyle(settings.LinkStyle)
+	if !ok {
+		log.Debug().Msgf("couldn't read link style %s", settings.LinkStyle)
+		return
+	}
+	config.CurrentConfig().SetLinkStyle(style)
+}
+
+func updateMaxMessageLength(settings lsp.Settings) {
+	if settings.MaxMessageLength == "" {
+		return
+	}
+	maxLength, err := strconv.Atoi(settings.MaxMessageLength)
+	if err != nil || maxLength < 0 {
+		log.Debug().Msgf("couldn't read max message length %s", settings.MaxMessageLength)
+		return
+	}
+	config.CurrentConfig().SetMaxMessageLength(maxLength)
+}
+
+func updateProjectIssuesTargetFile(settings lsp.Settings) {
+	targetFile := strings.TrimSpace(settings.ProjectIssuesTargetFile)
+	if targetFile == "" {
+		return
+	}
+	config.CurrentConfig().SetProjectIssuesTargetFile(targetFile)
+}
+
+func updateIssueIdentifier(settings lsp.Settings) {
+	if settings.IssueIdentifier == "" {
+		return
+	}
+	identifier, ok := config.ParseIssueIdentifier(settings.IssueIdentifier)
+	if !ok {
+		log.Debug().Msgf("couldn't read issue identifier %s", settings.IssueIdentifier)
+		return
+	}
+	config.CurrentConfig().SetIssueIdentifier(identifier)
+}
+
+func updateIssueUrlTemplates(settings lsp.Settings) {
+	if settings.IssueUrlTemplates == nil {
+		return
+	}
+	templates := map[product.Product]string{}
+	for codename, template := range settings.IssueUrlTemplates {
+		p := product.FromProductCodename(strings.ToLower(codename))
+		if p == product.ProductUnknown {
+			log.Debug().Msgf("couldn't read issue url template of product %s", codename)
+			continue
+		}
+		if template = strings.TrimSpace(template); template != "" {
+			templates[p] = template
+		}
+	}
+	config.CurrentConfig().SetIssueURLTemplates(templates)
+}
+
+func updateProductScans(settings lsp.Settings) {
+	c := config.CurrentConfig()
+	if settings.SequentialProductScans != "" {
+		sequential, err := strconv.ParseBool(settings.SequentialProductScans)
+		if err != nil {
+			log.Debug().Msgf("couldn't read sequential product scans %s", settings.SequentialProductScans)
+		} else {
+			c.SetSequentialProductScans(sequential)
+		}
+	}
+	if settings.ProductScanOrder == nil {
+		return
+	}
+	var order []product.Product
+	for _, codename := range settings.ProductScanOrder {
+		p := product.FromProductCodename(codename)
+		if p == product.ProductUnknown {
+			log.Debug().Msgf("couldn't read product scan order entry %s", codename)
+			continue
+		}
+		order = append(order, p)
+	}
+	c.SetProductScanOrder(order)
+}
+
+func updateIncludeLineOwners(settings lsp.Settings) {
+	if settings.IncludeLineOwners == "" {
+		return
+	}
+	include, err := strconv.ParseBool(settings.IncludeLineOwners)
+	if err != nil {
+		log.Debug().Msgf("couldn't read include line owners %s", settings.IncludeLineOwners)
+		return
+	}
+	config.CurrentConfig().SetIncludeLineOwners(include)
+}
+
+func updateScanDebounce(settings lsp.Settings) {
+	if settings.ScanDebounce == "" {
+		return
+	}
+	debounce, err := time.ParseDuration(settings.ScanDebounce)
+	if err != nil || debounce < 0 {
+		log.Warn().Err(err).Msgf("ignoring invalid scan debounce %s", settings.ScanDebounce)
+		return
+	}
+	config.CurrentConfig().SetScanDebounce(debounce)
+}
+
+func updateAllProjects(settings lsp.Settings) {
+	c := config.CurrentConfig()
+	if settings.AllProjects != "" {
+		enabled, err := strconv.ParseBool(settings.AllProjects)
+		if err != nil {
+			log.Debug().Msgf("couldn't read all projects %s", settings.AllProjects)
+		} else {
+			c.SetAllProjectsEnabled(enabled)
+		}
+	}
+	if settings.AllProjectsDetectionDepth != "" {
+		depth, err := strconv.Atoi(settings.AllProjectsDetectionDepth)
+		if err != nil || depth < 0 {
+			log.Warn().Err(err).Msgf("ignoring invalid detection depth %s", settings.AllProjectsDetectionDepth)
+		} else {
+			c.SetAllProjectsDetectionDepth(depth)
+		}
+	}
+	if settings.AllProjectsExclude != nil {
+		var exclude []string
+		for _, name := range settings.AllProjectsExclude {
+			if name = strings.TrimSpace(name); name != "" {
+				exclude = append(exclude, name)
+			}
+		}
+		c.SetAllProjectsExclude(exclude)
+	}
+}
+
+func updateManifestSummary(settings lsp.Settings) {
+	if settings.EnableManifestSummary == "" {
+		return
+	}
+	enabled, err := strconv.ParseBool(settings.EnableManifestSummary)
+	if err != nil {
+		log.Debug().Msgf("couldn't read enable manifest summary %s", settings.EnableManifestSummary)
+		return
+	}
+	config.CurrentConfig().SetManifestSummaryEnabled(enabled)
+}
+
+func updatePathPrivacy(settings lsp.Settings) {
+	if settings.AnalyticsPathPrivacy == "" {
+		return
+	}
+	level, ok := config.ParsePathPrivacy(settings.AnalyticsPathPrivacy)
+	if !ok {
+		log.Debug().Msgf("couldn't read analytics path privacy %s", settings.AnalyticsPathPrivacy)
+		return
+	}
+	config.CurrentConfig().SetPathPrivacy(level)
+}
+
+func updateUntrustedFolderBehavior(settings lsp.Settings) {
+	if settings.UntrustedFolderBehavior == "" {
+		return
+	}
+	behavior, ok := config.ParseUntrustedFolderBehavior(settings.UntrustedFolderBehavior)
+	if !ok {
+		log.Debug().Msgf("couldn't read untrusted folder behavior %s", settings.UntrustedFolderBehavior)
+		return
+	}
+	config.CurrentConfig().SetUntrustedFolderBehavior(behavior)
+}
+
+func updateMaxResultAge(settings lsp.Settings) {
+	if settings.MaxResultAge == "" {
+		return
+	}
+	maxAge, err := time.ParseDuration(settings.MaxResultAge)
+	if err != nil || maxAge < 0 {
+		log.Warn().Err(err).Msgf("ignoring invalid max result age %s", settings.MaxResultAge)
+		return
+	}
+	config.CurrentConfig().SetMaxResultAge(maxAge)
+}
+
+func updateAnalyticsEndpoint(settings lsp.Settings) {
+	endpoint := strings.TrimSpace(settings.AnalyticsEndpoint)
+	if endpoint == "" {
+		// clearing the endpoint sends telemetry to the default endpoints again
+		config.CurrentConfig().SetAnalyticsEndpoint("")
+		return
+	}
+	endpointUrl, err := url.Parse(endpoint)
+	if err != nil || !endpointUrl.IsAbs() {
+		log.Warn().Err(err).Msgf("ignoring invalid analytics endpoint %s", endpoint)
+		return
+	}
+	config.CurrentConfig().SetAnalyticsEndpoint(endpoint)
+}
+
+func updateMinEpssScore(settings lsp.Settings) {
+	if settings.MinEpssScore == "" {
+		return
+	}
+	minScore, err := strconv.ParseFloat(settings.MinEpssScore, 64)
+	if err != nil || minScore < 0 || minScore > 1 {
+		log.Warn().Err(err).Msgf("ignoring invalid min EPSS score %s", settings.MinEpssScore)
+		return
+	}
+	config.CurrentConfig().SetMinEpssScore(minScore)
+}
+
+func updateAnalyticsDeduplicationWindow(settings lsp.Settings) {
+	if settings.AnalyticsDeduplicationWindow == "" {
+		return
+	}
+	window, err := time.ParseDuration(settings.AnalyticsDeduplicationWindow)
+	if err != nil || window < 0 {
+		log.Warn().Err(err).Msgf("ignoring invalid analytics deduplication window %s", settings.AnalyticsDeduplicationWindow)
+		return
+	}
+	config.CurrentConfig().SetAnalyticsDeduplicationWindow(window)
+}
+
+func updateDiagnosticsPublishConcurrency(settings lsp.Settings) {
+	if settings.DiagnosticsPublishConcurrency == "" {
+		return
+	}
+	concurrency, err := strconv.Atoi(settings.DiagnosticsPublishConcurrency)
+	if err != nil || concurrency < 1 {
+		log.Debug().Msgf("couldn't read diagnostics publish concurrency %s", settings.DiagnosticsPublishConcurrency)
+		return
+	}
+	config.CurrentConfig().SetDiagnosticsPublishConcurrency(concurrency)
+}
+
+func updateCliMaxMemory(settings lsp.Settings) {
+	if settings.CliMaxMemory == "" {
+		return
+	}
+	maxMemoryMB, err := strconv.Atoi(settings.CliMaxMemory)
+	if err != nil || maxMemoryMB < 0 {
+		log.Warn().Msgf("ignoring invalid CLI max memory %s", settings.CliMaxMemory)
+		return
+	}
+	config.CurrentConfig().SetCliMaxMemoryMB(maxMemoryMB)
+}
+
+// diagnosticsCloseGracePeriodNever is the setting value that keeps the diagnostics of closed files
+const diagnosticsCloseGracePeriodNever = "never"
+
+func updateDiagnosticsCloseGracePeriod(settings lsp.Settings) {
+	if settings.DiagnosticsCloseGracePeriod == "" {
+		return
+	}
+	if settings.DiagnosticsCloseGracePeriod == diagnosticsCloseGracePeriodNever {
+		config.CurrentConfig().SetDiagnosticsCloseGracePeriod(config.DiagnosticsCloseGracePeriodNever)
+		return
+	}
+	gracePeriod, err := time.ParseDuration(settings.DiagnosticsCloseGracePeriod)
+	if err != nil || gracePeriod < 0 {
+		log.Warn().Msgf("ignoring invalid diagnostics close grace period %s", settings.DiagnosticsCloseGracePeriod)
+		return
+	}
+	config.CurrentConfig().SetDiagnosticsCloseGracePeriod(gracePeriod)
+}
+
+func updateDevDependencyIssues(settings lsp.Settings) {
+	if settings.DevDependencyIssues == "" {
+		return
+	}
+	treatment, ok := config.ParseDevDependencyIssues(settings.DevDependencyIssues)
+	if !ok {
+		log.Debug().Msgf("couldn't read dev dependency issues %s", settings.DevDependencyIssues)
+		return
+	}
+	config.CurrentConfig().SetDevDependencyIssues(treatment)
+}
+
+func updateOutputFormats(settings lsp.Settings) {
+	if settings.OutputFormats == nil {
+		return
+	}
+	c := config.CurrentConfig()
+	for _, renderContext := range config.RenderContexts() {
+		format := strings.ToLower(settings.OutputFormats[string(renderContext)])
+		switch format {
+		case "", config.FormatMd, config.FormatHtml:
+			c.SetFormatFor(renderContext, format)
+		default:
+			log.Debug().Msgf("couldn't read output format %s of %s", format, renderContext)
+		}
+	}
+}
+
+func updateUnknownProductCounting(settings lsp.Settings) {
+	if settings.UnknownProductCounting == "" {
+		return
+	}
+	counting, ok := config.ParseUnknownProductCounting(settings.UnknownProductCounting)
+	if !ok {
+		log.Debug().Msgf("couldn't read unknown product counting %s", settings.UnknownProductCounting)
+		return
+	}
+	config.CurrentConfig().SetUnknownProductCounting(counting)
+}
+
+func updateAdvisoryIdentifierPreference(settings lsp.Settings) {
+	if settings.AdvisoryIdentifierPreference == "" {
+		return
+	}
+	preference, ok := config.ParseAdvisoryIdentifierPreference(settings.AdvisoryIdentifierPreference)
+	if !ok {
+		log.Debug().Msgf("couldn't read advisory identifier preference %s", settings.AdvisoryIdentifierPreference)
+		return
+	}
+	config.CurrentConfig().SetAdvisoryIdentifierPreference(preference)
+}
+
+func updateDiagnosticsPublishThrottle(settings lsp.Settings) {
+	if settings.DiagnosticsPublishThrottle == "" {
+		return
+	}
+	throttle, err := time.ParseDuration(settings.DiagnosticsPublishThrottle)
+	if err != nil || throttle < 0 {
+		log.Warn().Msgf("ignoring invalid diagnostics publish throttle %s", settings.DiagnosticsPublishThrottle)
+		return
+	}
+	config.CurrentConfig().SetDiagnosticsPublishThrottle(throttle)
+}
+
+func updateAttestationSigningKeyPath(settings lsp.Settings) {
+	if settings.AttestationSigningKeyPath == "" {
+		return
+	}
+	config.CurrentConfig().SetAttestationSigningKeyPath(settings.AttestationSigningKeyPath)
+}
+
+func updateManifestLockfileDuplicates(settings lsp.Settings) {
+	if settings.ManifestLockfileDuplicates == "" {
+		return
+	}
+	duplicates, ok := config.ParseManifestLockfileDuplicates(settings.ManifestLockfileDuplicates)
+	if !ok {
+		log.Debug().Msgf("couldn't read manifest lockfile duplicates %s", settings.ManifestLockfileDuplicates)
+		return
+	}
+	config.CurrentConfig().SetManifestLockfileDuplicates(duplicates)
+}
+
+func updateScanOnStartup(settings lsp.Settings) {
+	if settings.ScanOnStartup == "" {
+		return
+	}
+	parseBool, err := strconv.ParseBool(settings.ScanOnStartup)
+	if err != nil {
+		log.Debug().Msgf("couldn't read scan on startup %s", settings.ScanOnStartup)
+		return
+	}
+	config.CurrentConfig().SetScanOnStartup(parseBool)
+}
+
+func updateIssueTagFilter(settings lsp.Settings) {
+	if settings.IssueTagFilter == nil {
+		return
+	}
+	var tags []string
+	for _, tag := range settings.IssueTagFilter {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	config.CurrentConfig().SetIssueTagFilter(tags)
+}
+
+func updateExploitSeverityEscalation(settings lsp.Settings) {
+	if settings.EscalateExploitSeverity == "" {
+		return
+	}
+	parseBool, err := strconv.ParseBool(settings.EscalateExploitSeverity)
+	if err != nil {
+		log.Debug().Msgf("couldn't read escalate exploit severity %s", settings.EscalateExploitSeverity)
+		return
+	}
+	config.CurrentConfig().SetExploitSeverityEscalation(parseBool)
+}
+
+func updateSeverityMapping(settings lsp.Settings) {
+	if settings.SeverityMapping == nil {
+		return
+	}
+	if len(settings.SeverityMapping) == 0 {
+		config.CurrentConfig().SetSeverityMapping(nil)
+		return
+	}
+	mapping := make(map[string]string, len(settings.SeverityMapping))
+	for reportedSeverity, severity := range settings.SeverityMapping {
+		if _, ok := oss.ParseSeverity(severity); !ok {
+			log.Debug().Msgf("couldn't read severity %s of %s", severity, reportedSeverity)
+			continue
+		}
+		mapping[reportedSeverity] = severity
+	}
+	config.CurrentConfig().SetSeverityMapping(mapping)
+}
+
+func updateUnknownSeverityPolicy(settings lsp.Settings) {
+	if settings.UnknownSeverityPolicy == "" {
+		return
+	}
+	policy, ok := config.ParseUnknownSeverityPolicy(settings.UnknownSeverityPolicy)
+	if !ok {
+		log.Debug().Msgf("couldn't read unknown severity policy %s", settings.UnknownSeverityPolicy)
+		return
+	}
+	config.CurrentConfig().SetUnknownSeverityPolicy(policy)
+}
+
 func updateToken(token string) {
 	// Token was sent from the client, no need to send notification
 	di.AuthenticationService().UpdateCredentials(token, false)
@@ -316,6 +1021,16 @@ func updateTelemetry(settings lsp.Settings) {
 			go di.Analytics().Identify()
 		}
 	}
+
+	if settings.DoNotTrack == "" {
+		return
+	}
+	parseBool, err = strconv.ParseBool(settings.DoNotTrack)
+	if err != nil {
+		log.Debug().Msgf("couldn't read do not track %s", settings.DoNotTrack)
+	} else {
+		config.CurrentConfig().SetDoNotTrack(parseBool)
+	}
 }
 
 func manageBinariesAutomatically(settings lsp.Settings) {
@@ -421,16 +1136,6 @@ func updateProductEnablement(settings lsp.Settings) {
 
 func updateSeverityFilter(s lsp.SeverityFilter) {
 	log.Debug().Str("method", "updateSeverityFilter").Interface("severityFilter", s).Msg("Updating severity filter:")
-	modified := config.CurrentConfig().SetSeverityFilter(s)
-
-	if modified {
-		ws := workspace.Get()
-		if ws == nil {
-			return
-		}
-
-		for _, folder := range ws.Folders() {
-			folder.FilterAndPublishCachedDiagnostics("")
-		}
-	}
+	// cached issues are published again by the workspace when the settings change
+	config.CurrentConfig().SetSeverityFilter(s)
 }
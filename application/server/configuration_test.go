@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -40,7 +42,9 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/ux"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/oss"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
 )
 
@@ -148,6 +152,18 @@ func Test_configureOauth_oauthProvider_created_with_injected_refreshMethod(t *te
 	}, 5*time.Second, 100*time.Millisecond, "refresh should have been triggered")
 }
 
+func Test_NewAuthenticationProvider_CreatesProviderOfMethod(t *testing.T) {
+	testutil.UnitTest(t)
+	di.TestInit(t)
+
+	oAuthProvider := di.NewAuthenticationProvider(lsp.OAuthAuthentication)
+	tokenProvider := di.NewAuthenticationProvider(lsp.TokenAuthentication)
+
+	assert.Equal(t, "*oauth.oAuthProvider", reflect.TypeOf(oAuthProvider).String())
+	assert.Equal(t, "*auth.CliAuthenticationProvider", reflect.TypeOf(tokenProvider).String())
+	assert.False(t, config.CurrentConfig().Engine().GetConfiguration().GetBool(configuration.FF_OAUTH_AUTH_FLOW_ENABLED))
+}
+
 func Test_WorkspaceDidChangeConfiguration_Push(t *testing.T) {
 	testutil.UnitTest(t)
 	di.TestInit(t)
@@ -370,6 +386,14 @@ func Test_UpdateSettings(t *testing.T) {
 		assert.Contains(t, c.TrustedFolders(), "/a/b")
 		assert.Contains(t, c.TrustedFolders(), "/b/c")
 	})
+	t.Run("trusted folder patterns", func(t *testing.T) {
+		testutil.NotOnWindows(t, "Unix/macOS file paths are incompatible with Windows")
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{TrustedFolders: []string{"glob:/home/dev/src/myorg-*", "glob:/*", "/a/b", "/a/[b]"}})
+
+		assert.Equal(t, []string{"glob:/home/dev/src/myorg-*", "/a/b", "/a/[b]"}, config.CurrentConfig().TrustedFolders())
+	})
 
 	t.Run("manage binaries automatically", func(t *testing.T) {
 		t.Run("true", func(t *testing.T) {
@@ -461,6 +485,609 @@ func Test_UpdateSettings(t *testing.T) {
 		assert.Equal(t, true, c.IsVulnmapCodeEnabled())
 	})
 
+	t.Run("scan changed files only", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{ScanChangedFilesOnly: "true", ChangedFilesBaseRef: " origin/main "})
+
+		c := config.CurrentConfig()
+		assert.True(t, c.IsScanChangedFilesOnlyEnabled())
+		assert.Equal(t, "origin/main", c.ChangedFilesBaseRef())
+	})
+
+	t.Run("anonymize analytics", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{AnonymizeAnalytics: "true"})
+
+		assert.True(t, config.CurrentConfig().IsAnalyticsAnonymized())
+	})
+
+	t.Run("persist issue cache", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{PersistIssueCache: "true"})
+
+		assert.True(t, config.CurrentConfig().IsIssueCachePersistenceEnabled())
+	})
+
+	t.Run("product display order", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{ProductDisplayOrder: []string{"iac", "unknown", "oss"}})
+
+		assert.Equal(t,
+			[]product.Product{product.ProductInfrastructureAsCode, product.ProductOpenSource},
+			config.CurrentConfig().ProductDisplayOrder())
+	})
+
+	t.Run("suppression policy path", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{SuppressionPolicyPath: "/policy.json"})
+
+		assert.Equal(t, "/policy.json", config.CurrentConfig().SuppressionPolicyPath())
+	})
+
+	t.Run("remap lockfile issues to manifest", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.True(t, config.CurrentConfig().IsLockfileIssueRemappingEnabled())
+
+		UpdateSettings(lsp.Settings{RemapLockfileIssuesToManifest: "false"})
+
+		assert.False(t, config.CurrentConfig().IsLockfileIssueRemappingEnabled())
+	})
+
+	t.Run("learn lesson lookup concurrency", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{LearnLessonLookupConcurrency: "2"})
+		assert.Equal(t, 2, config.CurrentConfig().LearnLessonLookupConcurrency())
+
+		UpdateSettings(lsp.Settings{LearnLessonLookupConcurrency: "0"})
+		assert.Equal(t, 2, config.CurrentConfig().LearnLessonLookupConcurrency())
+	})
+
+	t.Run("include issue fingerprints", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{IncludeIssueFingerprints: "true"})
+
+		assert.True(t, config.CurrentConfig().IsIssueFingerprintingEnabled())
+	})
+
+	t.Run("scan file mappings", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{ScanFileMappings: map[string][]string{"*.hcl": {"iac", "unknown"}}})
+
+		assert.Equal(t, map[string][]product.Product{"*.hcl": {product.ProductInfrastructureAsCode}},
+			config.CurrentConfig().ScanFileMappings())
+	})
+
+	t.Run("oss output version", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{})
+		assert.Empty(t, config.CurrentConfig().OssOutputVersion())
+
+		UpdateSettings(lsp.Settings{OssOutputVersion: "2"})
+		assert.Equal(t, "2", config.CurrentConfig().OssOutputVersion())
+	})
+
+	t.Run("notification level", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, config.NotificationLevelAll, config.CurrentConfig().NotificationLevel())
+
+		UpdateSettings(lsp.Settings{NotificationLevel: "Errors"})
+		assert.Equal(t, config.NotificationLevelErrors, config.CurrentConfig().NotificationLevel())
+
+		UpdateSettings(lsp.Settings{NotificationLevel: "verbose"})
+		assert.Equal(t, config.NotificationLevelErrors, config.CurrentConfig().NotificationLevel())
+	})
+
+	t.Run("custom http headers", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{CustomHttpHeaders: map[string]string{"X-Gateway-Route": "vulnmap"}})
+
+		assert.Equal(t, map[string]string{"X-Gateway-Route": "vulnmap"}, config.CurrentConfig().CustomHttpHeaders())
+
+		UpdateSettings(lsp.Settings{CustomHttpHeaders: map[string]string{}})
+
+		assert.Empty(t, config.CurrentConfig().CustomHttpHeaders())
+	})
+
+	t.Run("fixability filter", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{ShowOnlyFixable: "true", IncludeMajorUpgradeFixes: "false"})
+
+		assert.True(t, config.CurrentConfig().IsShowOnlyFixable())
+		assert.False(t, config.CurrentConfig().IsMajorUpgradeFixIncluded())
+
+		UpdateSettings(lsp.Settings{ActivateVulnmapOpenSource: "true"})
+
+		assert.True(t, config.CurrentConfig().IsShowOnlyFixable(), "omitted settings should keep the current value")
+		assert.False(t, config.CurrentConfig().IsMajorUpgradeFixIncluded())
+	})
+
+	t.Run("file deletion watcher", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.False(t, config.CurrentConfig().IsFileDeletionWatcherEnabled())
+
+		UpdateSettings(lsp.Settings{EnableFileDeletionWatcher: "true"})
+
+		assert.True(t, config.CurrentConfig().IsFileDeletionWatcherEnabled())
+	})
+
+	t.Run("idle logout timeout", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{IdleLogoutTimeout: "30m"})
+		assert.Equal(t, 30*time.Minute, config.CurrentConfig().IdleLogoutTimeout())
+
+		UpdateSettings(lsp.Settings{IdleLogoutTimeout: "invalid"})
+		assert.Equal(t, 30*time.Minute, config.CurrentConfig().IdleLogoutTimeout())
+
+		UpdateSettings(lsp.Settings{IdleLogoutTimeout: "0"})
+		assert.Equal(t, time.Duration(0), config.CurrentConfig().IdleLogoutTimeout())
+	})
+
+	t.Run("inline value kinds", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.True(t, config.CurrentConfig().IsInlineValueKindEnabled("vulnerabilityCount"))
+
+		UpdateSettings(lsp.Settings{InlineValueKinds: []string{"fixedVersion"}})
+
+		assert.False(t, config.CurrentConfig().IsInlineValueKindEnabled("vulnerabilityCount"))
+		assert.True(t, config.CurrentConfig().IsInlineValueKindEnabled("fixedVersion"))
+	})
+
+	t.Run("baseline", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.False(t, config.CurrentConfig().IsBaselineEnabled())
+
+		UpdateSettings(lsp.Settings{EnableBaseline: "true"})
+
+		assert.True(t, config.CurrentConfig().IsBaselineEnabled())
+	})
+
+	t.Run("baseline path", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		folderPath := filepath.Join(t.TempDir(), "folder")
+		otherFolderPath := filepath.Join(t.TempDir(), "other")
+		assert.NotEqual(t, config.CurrentConfig().BaselinePath(folderPath), config.CurrentConfig().BaselinePath(otherFolderPath))
+
+		UpdateSettings(lsp.Settings{BaselinePath: filepath.Join(".vulnmap", "baseline.json")})
+
+		assert.Equal(t, filepath.Join(folderPath, ".vulnmap", "baseline.json"), config.CurrentConfig().BaselinePath(folderPath))
+	})
+
+	t.Run("accepted issues and issue tags paths", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		folderPath := filepath.Join(t.TempDir(), "folder")
+		otherFolderPath := filepath.Join(t.TempDir(), "other")
+		assert.NotEqual(t, config.CurrentConfig().AcceptedIssuesPath(folderPath), config.CurrentConfig().AcceptedIssuesPath(otherFolderPath))
+		assert.NotEqual(t, config.CurrentConfig().IssueTagsPath(folderPath), config.CurrentConfig().IssueTagsPath(otherFolderPath))
+
+		UpdateSettings(lsp.Settings{
+			AcceptedIssuesPath: filepath.Join(".vulnmap", "accepted_issues.json"),
+			IssueTagsPath:      filepath.Join(".vulnmap", "issue_tags.json"),
+		})
+
+		assert.Equal(t, filepath.Join(folderPath, ".vulnmap", "accepted_issues.json"), config.CurrentConfig().AcceptedIssuesPath(folderPath))
+		assert.Equal(t, filepath.Join(folderPath, ".vulnmap", "issue_tags.json"), config.CurrentConfig().IssueTagsPath(folderPath))
+	})
+
+	t.Run("file path display", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.False(t, config.CurrentConfig().IsRelativeFilePathDisplay())
+
+		UpdateSettings(lsp.Settings{FilePathDisplay: "relative"})
+		assert.True(t, config.CurrentConfig().IsRelativeFilePathDisplay())
+
+		UpdateSettings(lsp.Settings{FilePathDisplay: "unknown"})
+		assert.True(t, config.CurrentConfig().IsRelativeFilePathDisplay())
+
+		UpdateSettings(lsp.Settings{FilePathDisplay: "absolute"})
+		assert.False(t, config.CurrentConfig().IsRelativeFilePathDisplay())
+	})
+
+	t.Run("license severities", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{LicenseSeverities: map[string]string{"GPL-3.0": "Critical", "MIT": "severe"}})
+
+		severity, ok := config.CurrentConfig().LicenseSeverity("gpl-3.0")
+		assert.True(t, ok)
+		assert.Equal(t, "critical", severity)
+		_, ok = config.CurrentConfig().LicenseSeverity("MIT")
+		assert.False(t, ok)
+	})
+
+	t.Run("registry certificates", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{RegistryCertificates: map[string]string{"NPM": "/certs/npm-registry.pem"}})
+
+		assert.Equal(t, map[string]string{"npm": "/certs/npm-registry.pem"}, config.CurrentConfig().RegistryCertificates())
+	})
+
+	t.Run("link style", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, config.LinkStyleMarkdown, config.CurrentConfig().LinkStyle())
+
+		UpdateSettings(lsp.Settings{LinkStyle: "URL"})
+		assert.Equal(t, config.LinkStyleUrl, config.CurrentConfig().LinkStyle())
+
+		UpdateSettings(lsp.Settings{LinkStyle: "html"})
+		assert.Equal(t, config.LinkStyleUrl, config.CurrentConfig().LinkStyle())
+	})
+
+	t.Run("severity mapping", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		c := config.CurrentConfig()
+
+		UpdateSettings(lsp.Settings{
+			SeverityMapping:       map[string]string{"moderate": "medium", "info": "invalid"},
+			UnknownSeverityPolicy: "unknown",
+		})
+
+		assert.Equal(t, map[string]string{"moderate": "medium"}, c.SeverityMapping())
+		assert.Equal(t, config.UnknownSeverityAsIs, c.UnknownSeverityPolicy())
+		assert.Equal(t, vulnmap.Medium, oss.ToSeverity(c, "Moderate"))
+		assert.Equal(t, vulnmap.Unknown, oss.ToSeverity(c, "info"))
+		assert.Equal(t, vulnmap.Unknown, oss.ToSeverity(c, "high"), "the mapping replaces the default mapping")
+
+		UpdateSettings(lsp.Settings{SeverityMapping: map[string]string{}, UnknownSeverityPolicy: "medium"})
+
+		assert.Nil(t, c.SeverityMapping())
+		assert.Equal(t, vulnmap.High, oss.ToSeverity(c, "high"))
+		assert.Equal(t, vulnmap.Medium, oss.ToSeverity(c, "moderate"))
+	})
+
+	t.Run("do not track", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.False(t, config.CurrentConfig().IsDoNotTrack())
+
+		UpdateSettings(lsp.Settings{DoNotTrack: "true"})
+
+		assert.True(t, config.CurrentConfig().IsDoNotTrack())
+		assert.False(t, config.CurrentConfig().IsTelemetryEnabled())
+
+		UpdateSettings(lsp.Settings{ActivateVulnmapOpenSource: "true"})
+
+		assert.True(t, config.CurrentConfig().IsDoNotTrack(), "omitted settings should keep the current value")
+	})
+
+	t.Run("max message length", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{MaxMessageLength: "200"})
+		assert.Equal(t, 200, config.CurrentConfig().MaxMessageLength())
+
+		UpdateSettings(lsp.Settings{MaxMessageLength: "-1"})
+		assert.Equal(t, 200, config.CurrentConfig().MaxMessageLength())
+	})
+
+	t.Run("project issues target file", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{ProjectIssuesTargetFile: " Dockerfile "})
+		assert.Equal(t, "Dockerfile", config.CurrentConfig().ProjectIssuesTargetFile())
+
+		UpdateSettings(lsp.Settings{})
+		assert.Equal(t, "Dockerfile", config.CurrentConfig().ProjectIssuesTargetFile())
+	})
+
+	t.Run("issue identifier", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, config.IssueIdentifierVulnmap, config.CurrentConfig().IssueIdentifier())
+
+		UpdateSettings(lsp.Settings{IssueIdentifier: "CVE"})
+		assert.Equal(t, config.IssueIdentifierCve, config.CurrentConfig().IssueIdentifier())
+
+		UpdateSettings(lsp.Settings{IssueIdentifier: "ghsa"})
+		assert.Equal(t, config.IssueIdentifierCve, config.CurrentConfig().IssueIdentifier())
+	})
+
+	t.Run("issue url templates", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{IssueUrlTemplates: map[string]string{
+			"Code":    "https://app.vulnmap.khulnasoft.com/code/rules/{id}",
+			"oss":     " ",
+			"unknown": "https://example.com/{id}",
+		}})
+
+		c := config.CurrentConfig()
+		assert.Equal(t, "https://app.vulnmap.khulnasoft.com/code/rules/{id}", c.IssueURLTemplate(product.ProductCode))
+		assert.Equal(t, "https://vulnmap.khulnasoft.com/vuln/{id}", c.IssueURLTemplate(product.ProductOpenSource))
+	})
+
+	t.Run("product scans", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.False(t, config.CurrentConfig().IsSequentialProductScans())
+
+		UpdateSettings(lsp.Settings{SequentialProductScans: "true", ProductScanOrder: []string{"code", "unknown", "oss"}})
+
+		assert.True(t, config.CurrentConfig().IsSequentialProductScans())
+		assert.Equal(t,
+			[]product.Product{product.ProductCode, product.ProductOpenSource},
+			config.CurrentConfig().ProductScanOrder())
+	})
+
+	t.Run("include line owners", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.False(t, config.CurrentConfig().IsIncludeLineOwners())
+
+		UpdateSettings(lsp.Settings{IncludeLineOwners: "true"})
+
+		assert.True(t, config.CurrentConfig().IsIncludeLineOwners())
+	})
+
+	t.Run("scan debounce", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, time.Duration(0), config.CurrentConfig().ScanDebounce())
+
+		UpdateSettings(lsp.Settings{ScanDebounce: "500ms"})
+		assert.Equal(t, 500*time.Millisecond, config.CurrentConfig().ScanDebounce())
+
+		UpdateSettings(lsp.Settings{ScanDebounce: "-1s"})
+		assert.Equal(t, 500*time.Millisecond, config.CurrentConfig().ScanDebounce())
+	})
+
+	t.Run("all projects", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.False(t, config.CurrentConfig().IsAllProjectsEnabled())
+
+		UpdateSettings(lsp.Settings{
+			AllProjects:               "true",
+			AllProjectsDetectionDepth: "3",
+			AllProjectsExclude:        []string{"node_modules", " ", "fixtures"},
+		})
+
+		c := config.CurrentConfig()
+		assert.True(t, c.IsAllProjectsEnabled())
+		assert.Equal(t, 3, c.AllProjectsDetectionDepth())
+		assert.Equal(t, []string{"node_modules", "fixtures"}, c.AllProjectsExclude())
+
+		UpdateSettings(lsp.Settings{AllProjectsDetectionDepth: "-1"})
+		assert.Equal(t, 3, c.AllProjectsDetectionDepth())
+	})
+
+	t.Run("manifest summary", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.False(t, config.CurrentConfig().IsManifestSummaryEnabled())
+
+		UpdateSettings(lsp.Settings{EnableManifestSummary: "true"})
+
+		assert.True(t, config.CurrentConfig().IsManifestSummaryEnabled())
+	})
+
+	t.Run("analytics path privacy", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, config.PathPrivacyOmitted, config.CurrentConfig().PathPrivacy())
+
+		UpdateSettings(lsp.Settings{AnalyticsPathPrivacy: "Hashed"})
+
+		assert.Equal(t, config.PathPrivacyHashed, config.CurrentConfig().PathPrivacy())
+	})
+
+	t.Run("invalid analytics path privacy is ignored", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{AnalyticsPathPrivacy: "partial"})
+
+		assert.Equal(t, config.PathPrivacyOmitted, config.CurrentConfig().PathPrivacy())
+	})
+
+	t.Run("untrusted folder behavior", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, config.UntrustedFolderBehaviorSkip, config.CurrentConfig().UntrustedFolderBehavior())
+
+		UpdateSettings(lsp.Settings{UntrustedFolderBehavior: "prompt"})
+
+		assert.Equal(t, config.UntrustedFolderBehaviorPrompt, config.CurrentConfig().UntrustedFolderBehavior())
+	})
+
+	t.Run("max result age", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, time.Duration(0), config.CurrentConfig().MaxResultAge())
+
+		UpdateSettings(lsp.Settings{MaxResultAge: "24h"})
+		assert.Equal(t, 24*time.Hour, config.CurrentConfig().MaxResultAge())
+
+		UpdateSettings(lsp.Settings{MaxResultAge: "-1h"})
+		assert.Equal(t, 24*time.Hour, config.CurrentConfig().MaxResultAge())
+	})
+
+	t.Run("analytics endpoint", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Empty(t, config.CurrentConfig().AnalyticsEndpoint())
+
+		UpdateSettings(lsp.Settings{AnalyticsEndpoint: " https://telemetry.example.com/vulnmap/ "})
+		assert.Equal(t, "https://telemetry.example.com/vulnmap", config.CurrentConfig().AnalyticsEndpoint())
+
+		UpdateSettings(lsp.Settings{AnalyticsEndpoint: "telemetry.example.com"})
+		assert.Equal(t, "https://telemetry.example.com/vulnmap", config.CurrentConfig().AnalyticsEndpoint())
+
+		UpdateSettings(lsp.Settings{AnalyticsEndpoint: "", ActivateVulnmapOpenSource: "true"})
+		assert.Empty(t, config.CurrentConfig().AnalyticsEndpoint())
+	})
+	t.Run("min EPSS score", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, 0.0, config.CurrentConfig().MinEpssScore())
+
+		UpdateSettings(lsp.Settings{MinEpssScore: "0.1"})
+		assert.Equal(t, 0.1, config.CurrentConfig().MinEpssScore())
+
+		UpdateSettings(lsp.Settings{MinEpssScore: "1.5"})
+		assert.Equal(t, 0.1, config.CurrentConfig().MinEpssScore())
+	})
+	t.Run("analytics deduplication window", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, time.Duration(0), config.CurrentConfig().AnalyticsDeduplicationWindow())
+
+		UpdateSettings(lsp.Settings{AnalyticsDeduplicationWindow: "30s"})
+		assert.Equal(t, 30*time.Second, config.CurrentConfig().AnalyticsDeduplicationWindow())
+
+		UpdateSettings(lsp.Settings{AnalyticsDeduplicationWindow: "-1s"})
+		assert.Equal(t, 30*time.Second, config.CurrentConfig().AnalyticsDeduplicationWindow())
+	})
+	t.Run("diagnostics publish concurrency", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, config.DefaultDiagnosticsPublishConcurrency, config.CurrentConfig().DiagnosticsPublishConcurrency())
+
+		UpdateSettings(lsp.Settings{DiagnosticsPublishConcurrency: "16"})
+		assert.Equal(t, 16, config.CurrentConfig().DiagnosticsPublishConcurrency())
+
+		UpdateSettings(lsp.Settings{DiagnosticsPublishConcurrency: "0"})
+		assert.Equal(t, 16, config.CurrentConfig().DiagnosticsPublishConcurrency())
+	})
+	t.Run("cli max memory", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, 0, config.CurrentConfig().CliMaxMemoryMB())
+
+		UpdateSettings(lsp.Settings{CliMaxMemory: "8192"})
+		assert.Equal(t, 8192, config.CurrentConfig().CliMaxMemoryMB())
+
+		UpdateSettings(lsp.Settings{CliMaxMemory: "lots"})
+		assert.Equal(t, 8192, config.CurrentConfig().CliMaxMemoryMB())
+	})
+	t.Run("diagnostics close grace period", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, config.DiagnosticsCloseGracePeriodNever, config.CurrentConfig().DiagnosticsCloseGracePeriod(),
+			"never cleared by default")
+
+		UpdateSettings(lsp.Settings{DiagnosticsCloseGracePeriod: "30s"})
+		assert.Equal(t, 30*time.Second, config.CurrentConfig().DiagnosticsCloseGracePeriod())
+
+		UpdateSettings(lsp.Settings{DiagnosticsCloseGracePeriod: "-1s"})
+		assert.Equal(t, 30*time.Second, config.CurrentConfig().DiagnosticsCloseGracePeriod())
+
+		UpdateSettings(lsp.Settings{DiagnosticsCloseGracePeriod: "0"})
+		assert.Equal(t, time.Duration(0), config.CurrentConfig().DiagnosticsCloseGracePeriod())
+
+		UpdateSettings(lsp.Settings{DiagnosticsCloseGracePeriod: "never"})
+		assert.Equal(t, config.DiagnosticsCloseGracePeriodNever, config.CurrentConfig().DiagnosticsCloseGracePeriod())
+	})
+	t.Run("dev dependency issues", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, config.DevDependencyIssuesShow, config.CurrentConfig().DevDependencyIssues())
+
+		UpdateSettings(lsp.Settings{DevDependencyIssues: "Hide"})
+		assert.Equal(t, config.DevDependencyIssuesHide, config.CurrentConfig().DevDependencyIssues())
+
+		UpdateSettings(lsp.Settings{DevDependencyIssues: "ignore"})
+		assert.Equal(t, config.DevDependencyIssuesHide, config.CurrentConfig().DevDependencyIssues())
+	})
+	t.Run("unknown product counting", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, config.UnknownProductCountingKeep, config.CurrentConfig().UnknownProductCounting())
+
+		UpdateSettings(lsp.Settings{UnknownProductCounting: "Infer"})
+		assert.Equal(t, config.UnknownProductCountingInfer, config.CurrentConfig().UnknownProductCounting())
+
+		UpdateSettings(lsp.Settings{UnknownProductCounting: "guess"})
+		assert.Equal(t, config.UnknownProductCountingInfer, config.CurrentConfig().UnknownProductCounting())
+	})
+	t.Run("advisory identifier preference", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, config.AdvisoryIdentifierPreferenceCve, config.CurrentConfig().AdvisoryIdentifierPreference())
+
+		UpdateSettings(lsp.Settings{AdvisoryIdentifierPreference: "GHSA"})
+		assert.Equal(t, config.AdvisoryIdentifierPreferenceGhsa, config.CurrentConfig().AdvisoryIdentifierPreference())
+
+		UpdateSettings(lsp.Settings{AdvisoryIdentifierPreference: "osv"})
+		assert.Equal(t, config.AdvisoryIdentifierPreferenceGhsa, config.CurrentConfig().AdvisoryIdentifierPreference())
+	})
+	t.Run("diagnostics publish throttle", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, time.Duration(0), config.CurrentConfig().DiagnosticsPublishThrottle())
+
+		UpdateSettings(lsp.Settings{DiagnosticsPublishThrottle: "2s"})
+		assert.Equal(t, 2*time.Second, config.CurrentConfig().DiagnosticsPublishThrottle())
+
+		UpdateSettings(lsp.Settings{DiagnosticsPublishThrottle: "-1s"})
+		assert.Equal(t, 2*time.Second, config.CurrentConfig().DiagnosticsPublishThrottle())
+	})
+	t.Run("attestation signing key path", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Empty(t, config.CurrentConfig().AttestationSigningKeyPath())
+
+		UpdateSettings(lsp.Settings{AttestationSigningKeyPath: "/keys/attestation.pem"})
+		assert.Equal(t, "/keys/attestation.pem", config.CurrentConfig().AttestationSigningKeyPath())
+
+		UpdateSettings(lsp.Settings{})
+		assert.Equal(t, "/keys/attestation.pem", config.CurrentConfig().AttestationSigningKeyPath())
+	})
+	t.Run("manifest lockfile duplicates", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Equal(t, config.ManifestLockfileDuplicatesKeep, config.CurrentConfig().ManifestLockfileDuplicates())
+
+		UpdateSettings(lsp.Settings{ManifestLockfileDuplicates: "Collapse"})
+		assert.Equal(t, config.ManifestLockfileDuplicatesCollapse, config.CurrentConfig().ManifestLockfileDuplicates())
+
+		UpdateSettings(lsp.Settings{ManifestLockfileDuplicates: "merge"})
+		assert.Equal(t, config.ManifestLockfileDuplicatesCollapse, config.CurrentConfig().ManifestLockfileDuplicates())
+	})
+	t.Run("scan on startup", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.True(t, config.CurrentConfig().IsScanOnStartupEnabled())
+
+		UpdateSettings(lsp.Settings{ScanOnStartup: "false"})
+		assert.False(t, config.CurrentConfig().IsScanOnStartupEnabled())
+
+		UpdateSettings(lsp.Settings{})
+		assert.False(t, config.CurrentConfig().IsScanOnStartupEnabled())
+
+		UpdateSettings(lsp.Settings{ScanOnStartup: "true"})
+		assert.True(t, config.CurrentConfig().IsScanOnStartupEnabled())
+	})
+	t.Run("issue tag filter", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.Empty(t, config.CurrentConfig().IssueTagFilter())
+
+		UpdateSettings(lsp.Settings{IssueTagFilter: []string{" needs-review ", "", "jira-1234"}})
+		assert.Equal(t, []string{"needs-review", "jira-1234"}, config.CurrentConfig().IssueTagFilter())
+
+		UpdateSettings(lsp.Settings{IssueTagFilter: []string{}})
+		assert.Empty(t, config.CurrentConfig().IssueTagFilter())
+	})
+	t.Run("escalate exploit severity", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+		assert.False(t, config.CurrentConfig().IsExploitSeverityEscalationEnabled())
+
+		UpdateSettings(lsp.Settings{EscalateExploitSeverity: "true"})
+		assert.True(t, config.CurrentConfig().IsExploitSeverityEscalationEnabled())
+
+		UpdateSettings(lsp.Settings{EscalateExploitSeverity: "sometimes"})
+		assert.True(t, config.CurrentConfig().IsExploitSeverityEscalationEnabled())
+	})
+	t.Run("output formats", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{OutputFormats: map[string]string{"export": "HTML", "hover": "pdf"}})
+		c := config.CurrentConfig()
+		assert.Equal(t, config.FormatHtml, c.FormatFor(config.RenderContextExport))
+		assert.Equal(t, config.FormatMd, c.FormatFor(config.RenderContextHover))
+		assert.Equal(t, config.FormatMd, c.FormatFor(config.RenderContextDiagnostic))
+
+		UpdateSettings(lsp.Settings{OutputFormats: map[string]string{}})
+		assert.Equal(t, config.FormatMd, c.FormatFor(config.RenderContextExport))
+	})
+	t.Run("max issues per scan", func(t *testing.T) {
+		config.SetCurrentConfig(config.New())
+
+		UpdateSettings(lsp.Settings{MaxIssuesPerScan: "500"})
+		assert.Equal(t, 500, config.CurrentConfig().MaxIssuesPerScan())
+
+		UpdateSettings(lsp.Settings{MaxIssuesPerScan: "-1"})
+		assert.Equal(t, 500, config.CurrentConfig().MaxIssuesPerScan())
+	})
+
 	t.Run("severity filter", func(t *testing.T) {
 		config.SetCurrentConfig(config.New())
 		t.Run("filtering gets passed", func(t *testing.T) {
@@ -25,6 +25,7 @@ import (
 	"github.com/rs/zerolog/log"
 	sglsp "github.com/sourcegraph/go-lsp"
 
+	"github.com/khulnasoft-lab/vulnmap-ls/application/di"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/command"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 )
@@ -40,6 +41,7 @@ func executeCommandHandler(srv *jrpc2.Server) jrpc2.Handler {
 		defer log.Info().Str("method", method).Interface("command", params).Msg("SENDING")
 
 		commandData := vulnmap.CommandData{CommandId: params.Command, Arguments: params.Arguments, Title: params.Command}
+		di.IdleLogout().Activity()
 
 		result, err := command.Service().ExecuteCommandData(bgCtx, commandData, srv)
 		logError(err, fmt.Sprintf("Error executing command %v", commandData))
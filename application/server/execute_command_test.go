@@ -119,7 +119,7 @@ func Test_loginCommand_StartsAuthentication(t *testing.T) {
 	loc := setupServer(t)
 
 	// reset to use real service
-	command.SetService(command.NewService(di.AuthenticationService(), nil, nil, nil, nil))
+	command.SetService(command.NewService(di.AuthenticationService(), nil, nil, nil, nil, nil))
 
 	config.CurrentConfig().SetAutomaticAuthentication(false)
 	_, err := loc.Client.Call(ctx, "initialize", nil)
@@ -147,7 +147,7 @@ func Test_executeCommand_shouldCopyAuthURLToClipboard(t *testing.T) {
 	loc := setupServer(t)
 
 	// reset to use real service
-	command.SetService(command.NewService(di.AuthenticationService(), nil, nil, nil, nil))
+	command.SetService(command.NewService(di.AuthenticationService(), nil, nil, nil, nil, nil))
 
 	authenticationMock := di.AuthenticationService().Provider().(*vulnmap.FakeAuthenticationProvider)
 	params := lsp.ExecuteCommandParams{Command: vulnmap.CopyAuthLinkCommand}
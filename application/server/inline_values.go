@@ -36,16 +36,29 @@ func textDocumentInlineValueHandler(c *config.Config) jrpc2.Handler {
 		documentURI := params.TextDocument.URI
 		logger.Info().Msgf("Request for %s:%s RECEIVED", documentURI, params.Range.String())
 		defer logger.Info().Msgf("Request for %s:%s DONE", documentURI, params.Range.String())
-		if s, ok := di.Scanner().(vulnmap.InlineValueProvider); ok {
-			filePath := uri.PathFromUri(documentURI)
-			values, err := s.GetInlineValues(filePath, converter.FromRange(params.Range))
-			if err != nil {
-				return nil, err
-			}
-			lspInlineValues := converter.ToInlineValues(values)
-			logger.Debug().Msgf("found %d inline values for %s", len(values), filePath)
-			return lspInlineValues, nil
-		}
-		return nil, nil
+		return inlineValues(c, di.Scanner(), params)
 	})
 }
+
+// inlineValues returns the inline values of the scanner for the requested range, restricted to the kinds enabled
+// in the configuration
+func inlineValues(c *config.Config, scanner vulnmap.Scanner, params lsp.InlineValueParams) ([]lsp.InlineValue, error) {
+	s, ok := scanner.(vulnmap.InlineValueProvider)
+	if !ok {
+		return nil, nil
+	}
+	filePath := uri.PathFromUri(params.TextDocument.URI)
+	values, err := s.GetInlineValues(filePath, converter.FromRange(params.Range))
+	if err != nil {
+		return nil, err
+	}
+	var enabledValues []vulnmap.InlineValue
+	for _, value := range values {
+		if c.IsInlineValueKindEnabled(string(value.Kind())) {
+			enabledValues = append(enabledValues, value)
+		}
+	}
+	c.Logger().Debug().Str("method", "inlineValues").
+		Msgf("found %d inline values for %s, %d of enabled kinds", len(values), filePath, len(enabledValues))
+	return converter.ToInlineValues(enabledValues), nil
+}
@@ -24,12 +24,62 @@ import (
 
 	sglsp "github.com/sourcegraph/go-lsp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/di"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
 )
 
+const fixedVersionKind vulnmap.InlineValueKind = "fixedVersion"
+
+type fakeInlineValue struct {
+	kind vulnmap.InlineValueKind
+	text string
+}
+
+func (v fakeInlineValue) Path() string                  { return "package.json" }
+func (v fakeInlineValue) Range() vulnmap.Range          { return vulnmap.Range{} }
+func (v fakeInlineValue) Text() string                  { return v.text }
+func (v fakeInlineValue) Kind() vulnmap.InlineValueKind { return v.kind }
+func (v fakeInlineValue) String() string                { return v.text }
+
+type fakeInlineValueScanner struct {
+	*vulnmap.TestScanner
+	values []vulnmap.InlineValue
+}
+
+func (s *fakeInlineValueScanner) GetInlineValues(_ string, _ vulnmap.Range) ([]vulnmap.InlineValue, error) {
+	return s.values, nil
+}
+
+func (s *fakeInlineValueScanner) ClearInlineValues(_ string) {}
+
+func Test_inlineValues_FiltersDisabledKinds(t *testing.T) {
+	c := testutil.UnitTest(t)
+	scanner := &fakeInlineValueScanner{
+		TestScanner: vulnmap.NewTestScanner(),
+		values: []vulnmap.InlineValue{
+			fakeInlineValue{kind: vulnmap.InlineValueKindVulnerabilityCount, text: "Vulnerabilities: 1"},
+			fakeInlineValue{kind: fixedVersionKind, text: "Fixed in 4.17.21"},
+		},
+	}
+	params := lsp.InlineValueParams{TextDocument: sglsp.TextDocumentIdentifier{URI: uri.PathToUri("package.json")}}
+
+	allValues, err := inlineValues(c, scanner, params)
+	require.NoError(t, err)
+	assert.Len(t, allValues, 2)
+
+	c.SetInlineValueKinds([]string{string(fixedVersionKind)})
+	values, err := inlineValues(c, scanner, params)
+
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	assert.Equal(t, "Fixed in 4.17.21", values[0].Text)
+}
+
 func Test_textDocumentInlineValues_shouldBeServed(t *testing.T) {
 	loc := setupServer(t)
 
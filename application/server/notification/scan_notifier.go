@@ -3,7 +3,10 @@ package notification
 import (
 	"errors"
 	"strconv"
+	"time"
 
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/converter"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
@@ -30,27 +33,34 @@ func NewScanNotifier(notifier notification.Notifier) (vulnmap.ScanNotifier, erro
 	}, nil
 }
 
-func (n *scanNotifier) SendError(pr product.Product, folderPath string) {
+func (n *scanNotifier) SendError(pr product.Product, folderPath string, scanId string) {
 	n.notifier.Send(
 		lsp.VulnmapScanParams{
 			Status:     lsp.ErrorStatus,
 			Product:    product.ToProductCodename(pr),
 			FolderPath: folderPath,
+			ScanId:     scanId,
 		},
 	)
 }
 
-// Reports success for all enabled products
-func (n *scanNotifier) SendSuccessForAllProducts(folderPath string, issues []vulnmap.Issue) {
-	for product, enabled := range enabledProducts {
+// Reports success for all enabled products, with the id of the scan that found the issues of each product
+func (n *scanNotifier) SendSuccessForAllProducts(folderPath string, scanIds map[product.Product]string,
+	issues []vulnmap.Issue) {
+	var products []product.Product
+	for p, enabled := range enabledProducts {
 		if enabled {
-			n.sendSuccess(product, folderPath, issues)
+			products = append(products, p)
 		}
 	}
+	for _, p := range product.SortByDisplayOrder(products, config.CurrentConfig().ProductDisplayOrder()) {
+		n.sendSuccess(p, folderPath, scanIds[p], issues)
+	}
 }
 
 // Sends scan success message for a single enabled product
-func (n *scanNotifier) SendSuccess(reportedProduct product.Product, folderPath string, issues []vulnmap.Issue) {
+func (n *scanNotifier) SendSuccess(reportedProduct product.Product, folderPath string, scanId string,
+	issues []vulnmap.Issue) {
 	// If no issues found, we still should send success message the reported product
 	productIssues := make([]vulnmap.Issue, 0)
 
@@ -64,10 +74,10 @@ func (n *scanNotifier) SendSuccess(reportedProduct product.Product, folderPath s
 		productIssues = append(productIssues, issue)
 	}
 
-	n.sendSuccess(reportedProduct, folderPath, productIssues)
+	n.sendSuccess(reportedProduct, folderPath, scanId, productIssues)
 }
 
-func (n *scanNotifier) sendSuccess(pr product.Product, folderPath string, issues []vulnmap.Issue) {
+func (n *scanNotifier) sendSuccess(pr product.Product, folderPath string, scanId string, issues []vulnmap.Issue) {
 	enabled, ok := enabledProducts[pr]
 	if !enabled || !ok {
 		return
@@ -85,14 +95,34 @@ func (n *scanNotifier) sendSuccess(pr product.Product, folderPath string, issues
 
 	n.notifier.Send(
 		lsp.VulnmapScanParams{
-			Status:     lsp.Success,
-			Product:    product.ToProductCodename(pr),
-			FolderPath: folderPath,
-			Issues:     scanIssues,
+			Status:        lsp.Success,
+			Product:       product.ToProductCodename(pr),
+			FolderPath:    folderPath,
+			ScanId:        scanId,
+			Issues:        scanIssues,
+			SeverityCount: severityCount(scanIssues),
 		},
 	)
 }
 
+// severityCount counts the issues that are sent to the client, so that summaries match the filtered issues
+func severityCount(scanIssues []lsp.ScanIssue) *lsp.ScanSeverityCount {
+	count := lsp.ScanSeverityCount{}
+	for _, issue := range scanIssues {
+		switch issue.Severity {
+		case vulnmap.Critical.String():
+			count.Critical++
+		case vulnmap.High.String():
+			count.High++
+		case vulnmap.Medium.String():
+			count.Medium++
+		case vulnmap.Low.String():
+			count.Low++
+		}
+	}
+	return &count
+}
+
 func (n *scanNotifier) appendOssIssues(scanIssues []lsp.ScanIssue, folderPath string, issues []vulnmap.Issue) []lsp.ScanIssue {
 	for _, issue := range issues {
 		additionalData, ok := issue.AdditionalData.(vulnmap.OssIssueData)
@@ -105,6 +135,7 @@ func (n *scanNotifier) appendOssIssues(scanIssues []lsp.ScanIssue, folderPath st
 			Title:    additionalData.Title,
 			Severity: issue.Severity.String(),
 			FilePath: issue.AffectedFilePath,
+			Tags:     issue.Tags,
 			AdditionalData: lsp.OssIssueData{
 				License: additionalData.License,
 				Identifiers: lsp.OssIdentifiers{
@@ -118,6 +149,7 @@ func (n *scanNotifier) appendOssIssues(scanIssues []lsp.ScanIssue, folderPath st
 				Name:              additionalData.Name,
 				Version:           additionalData.Version,
 				Exploit:           additionalData.Exploit,
+				FeedSeverity:      additionalData.FeedSeverity,
 				CVSSv3:            additionalData.CVSSv3,
 				CvssScore:         strconv.FormatFloat(additionalData.CvssScore, 'f', 2, 64), // convert float64 to string with 2 decimal places
 				FixedIn:           additionalData.FixedIn,
@@ -128,6 +160,10 @@ func (n *scanNotifier) appendOssIssues(scanIssues []lsp.ScanIssue, folderPath st
 				ProjectName:       additionalData.ProjectName,
 				DisplayTargetFile: additionalData.DisplayTargetFile,
 				Details:           additionalData.Details,
+				Owner:             toLineOwner(additionalData.Owner),
+				EpssScore:         formatEpssScore(additionalData.EpssScore),
+				DisclosureTime:    formatAdvisoryTime(additionalData.DisclosureTime),
+				ModificationTime:  formatAdvisoryTime(additionalData.ModificationTime),
 			},
 		})
 	}
@@ -147,6 +183,7 @@ func (n *scanNotifier) appendIacIssues(scanIssues []lsp.ScanIssue, folderPath st
 			Title:    additionalData.Title,
 			Severity: issue.Severity.String(),
 			FilePath: issue.AffectedFilePath,
+			Tags:     issue.Tags,
 			AdditionalData: lsp.IacIssueData{
 				PublicId:      additionalData.PublicId,
 				Documentation: additionalData.Documentation,
@@ -156,6 +193,7 @@ func (n *scanNotifier) appendIacIssues(scanIssues []lsp.ScanIssue, folderPath st
 				Resolve:       additionalData.Resolve,
 				Path:          additionalData.Path,
 				References:    additionalData.References,
+				Owner:         toLineOwner(additionalData.Owner),
 			},
 		})
 	}
@@ -204,11 +242,23 @@ func (n *scanNotifier) appendCodeIssues(scanIssues []lsp.ScanIssue, folderPath s
 			})
 		}
 
+		var dataFlow []lsp.DataflowElement
+		for _, step := range additionalData.DataFlow {
+			dataFlow = append(dataFlow, lsp.DataflowElement{
+				Position:  step.Position,
+				FilePath:  step.FilePath,
+				FlowRange: converter.ToRange(step.FlowRange),
+				Content:   step.Content,
+				Message:   step.Message,
+			})
+		}
+
 		scanIssues = append(scanIssues, lsp.ScanIssue{
 			Id:       additionalData.Key,
 			Title:    issue.Message,
 			Severity: issue.Severity.String(),
 			FilePath: issue.AffectedFilePath,
+			Tags:     issue.Tags,
 			AdditionalData: lsp.CodeIssueData{
 				Message:            additionalData.Message,
 				Rule:               additionalData.Rule,
@@ -221,8 +271,10 @@ func (n *scanNotifier) appendCodeIssues(scanIssues []lsp.ScanIssue, folderPath s
 				Cols:               additionalData.Cols,
 				Rows:               additionalData.Rows,
 
-				Markers: markers,
-				LeadURL: "",
+				Markers:  markers,
+				LeadURL:  "",
+				DataFlow: dataFlow,
+				Owner:    toLineOwner(additionalData.Owner),
 			},
 		})
 	}
@@ -230,8 +282,40 @@ func (n *scanNotifier) appendCodeIssues(scanIssues []lsp.ScanIssue, folderPath s
 	return scanIssues
 }
 
+func toLineOwner(owner *vulnmap.LineOwner) *lsp.LineOwner {
+	if owner == nil {
+		return nil
+	}
+	return &lsp.LineOwner{Author: owner.Author, CommitSha: owner.CommitSha}
+}
+
+// formatEpssScore returns the EPSS score as string, empty if it is unknown
+func formatEpssScore(score *float64) string {
+	if score == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*score, 'f', -1, 64)
+}
+
+// formatAdvisoryTime returns the advisory timestamp in RFC 3339, empty if it is unknown
+func formatAdvisoryTime(advisoryTime *time.Time) string {
+	if advisoryTime == nil {
+		return ""
+	}
+	return advisoryTime.UTC().Format(time.RFC3339)
+}
+
 // Notifies all vulnmap/scan enabled product messages
-func (n *scanNotifier) SendInProgress(folderPath string) {
+func (n *scanNotifier) SendInProgress(folderPath string, scanId string) {
+	n.sendStatusForEnabledProducts(lsp.InProgress, folderPath, scanId)
+}
+
+// SendPending notifies the enabled products that the folder is waiting for an explicit scan
+func (n *scanNotifier) SendPending(folderPath string) {
+	n.sendStatusForEnabledProducts(lsp.Pending, folderPath, "")
+}
+
+func (n *scanNotifier) sendStatusForEnabledProducts(status lsp.ScanStatus, folderPath string, scanId string) {
 	for pr, enabled := range enabledProducts {
 		if !enabled {
 			continue
@@ -239,9 +323,10 @@ func (n *scanNotifier) SendInProgress(folderPath string) {
 
 		n.notifier.Send(
 			lsp.VulnmapScanParams{
-				Status:     lsp.InProgress,
+				Status:     status,
 				Product:    product.ToProductCodename(pr),
 				FolderPath: folderPath,
+				ScanId:     scanId,
 				Issues:     nil,
 			},
 		)
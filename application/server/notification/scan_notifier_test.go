@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	notification2 "github.com/khulnasoft-lab/vulnmap-ls/application/server/notification"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 	lsp2 "github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
@@ -29,21 +31,28 @@ func Test_SendMessage(t *testing.T) {
 		{
 			name: "SendInProgressMessage",
 			act: func(scanNotifier vulnmap.ScanNotifier) {
-				scanNotifier.SendInProgress(folderPath)
+				scanNotifier.SendInProgress(folderPath, "")
 			},
 			expectedStatus: lsp2.InProgress,
 		},
+		{
+			name: "SendPendingMessage",
+			act: func(scanNotifier vulnmap.ScanNotifier) {
+				scanNotifier.SendPending(folderPath)
+			},
+			expectedStatus: lsp2.Pending,
+		},
 		{
 			name: "SendSuccessMessage",
 			act: func(scanNotifier vulnmap.ScanNotifier) {
-				scanNotifier.SendSuccess(product.ProductCode, folderPath, []vulnmap.Issue{})
+				scanNotifier.SendSuccess(product.ProductCode, folderPath, "", []vulnmap.Issue{})
 			},
 			expectedStatus: lsp2.Success,
 		},
 		{
 			name: "SendErrorMessage",
 			act: func(scanNotifier vulnmap.ScanNotifier) {
-				scanNotifier.SendError(product.ProductCode, folderPath)
+				scanNotifier.SendError(product.ProductCode, folderPath, "")
 			},
 			expectedStatus: lsp2.ErrorStatus,
 		},
@@ -191,7 +200,7 @@ func Test_SendSuccess_SendsForAllEnabledProducts(t *testing.T) {
 	}
 
 	// Act - run the test
-	scanNotifier.SendSuccessForAllProducts(folderPath, scanIssues)
+	scanNotifier.SendSuccessForAllProducts(folderPath, nil, scanIssues)
 
 	// Assert - check the messages matches the expected message for each product
 	for _, msg := range mockNotifier.SentMessages() {
@@ -309,7 +318,7 @@ func Test_SendSuccess_SendsForOpenSource(t *testing.T) {
 	}
 
 	// Act - run the test
-	scanNotifier.SendSuccess(product.ProductOpenSource, folderPath, issues)
+	scanNotifier.SendSuccess(product.ProductOpenSource, folderPath, "", issues)
 
 	// Assert - check that there are messages sent
 	assert.NotEmpty(t, mockNotifier.SentMessages())
@@ -393,7 +402,7 @@ func Test_SendSuccess_SendsForVulnmapCode(t *testing.T) {
 	}
 
 	// Act - run the test
-	scanNotifier.SendSuccess(product.ProductCode, folderPath, scanIssues)
+	scanNotifier.SendSuccess(product.ProductCode, folderPath, "", scanIssues)
 
 	// Assert - check the messages matches the expected message for each product
 	for _, msg := range mockNotifier.SentMessages() {
@@ -466,7 +475,7 @@ func Test_SendSuccess_SendsForAllVulnmapIac(t *testing.T) {
 	}
 
 	// Act - run the test
-	scanNotifier.SendSuccess(product.ProductInfrastructureAsCode, folderPath, scanIssues)
+	scanNotifier.SendSuccess(product.ProductInfrastructureAsCode, folderPath, "", scanIssues)
 
 	// Assert - check the messages matches the expected message for each product
 	for _, msg := range mockNotifier.SentMessages() {
@@ -483,6 +492,24 @@ func Test_NewScanNotifier_NilNotifier_Errors(t *testing.T) {
 	assert.Nil(t, scanNotifier)
 }
 
+func Test_SendMessage_ContainsScanId(t *testing.T) {
+	testutil.UnitTest(t)
+	mockNotifier := notification.NewMockNotifier()
+	scanNotifier, _ := notification2.NewScanNotifier(mockNotifier)
+
+	scanNotifier.SendInProgress("/test/folderPath", "scan-id")
+	scanNotifier.SendSuccess(product.ProductCode, "/test/folderPath", "scan-id", []vulnmap.Issue{})
+	scanNotifier.SendError(product.ProductCode, "/test/folderPath", "scan-id")
+	scanNotifier.SendSuccessForAllProducts("/test/folderPath",
+		map[product.Product]string{product.ProductCode: "scan-id", product.ProductOpenSource: "scan-id",
+			product.ProductInfrastructureAsCode: "scan-id"}, []vulnmap.Issue{})
+
+	require.Len(t, mockNotifier.SentMessages(), 8)
+	for _, msg := range mockNotifier.SentMessages() {
+		assert.Equal(t, "scan-id", msg.(lsp2.VulnmapScanParams).ScanId)
+	}
+}
+
 func Test_SendInProgress_SendsForAllEnabledProducts(t *testing.T) {
 	testutil.UnitTest(t)
 
@@ -491,7 +518,7 @@ func Test_SendInProgress_SendsForAllEnabledProducts(t *testing.T) {
 	scanNotifier, _ := notification2.NewScanNotifier(mockNotifier)
 
 	// Act
-	scanNotifier.SendInProgress("/test/folderPath")
+	scanNotifier.SendInProgress("/test/folderPath", "")
 
 	// Assert
 	assert.Equal(t, 3, len(mockNotifier.SentMessages()))
@@ -513,3 +540,86 @@ func containsMatchingMessage(t *testing.T,
 	}
 	return false
 }
+
+func Test_SendSuccess_CountsSentIssuesBySeverity(t *testing.T) {
+	testutil.UnitTest(t)
+	mockNotifier := notification.NewMockNotifier()
+	scanNotifier, _ := notification2.NewScanNotifier(mockNotifier)
+	newIssue := func(id string, severity vulnmap.Severity) vulnmap.Issue {
+		return vulnmap.Issue{
+			ID:               id,
+			Severity:         severity,
+			AffectedFilePath: "/test/oss/folderPath/package.json",
+			Product:          product.ProductOpenSource,
+			AdditionalData:   vulnmap.OssIssueData{Key: id},
+		}
+	}
+	critical := newIssue("critical", vulnmap.Critical)
+	high := newIssue("high", vulnmap.High)
+	low := newIssue("low", vulnmap.Low)
+
+	// the second call has the issues after low severities were filtered out
+	scanNotifier.SendSuccess(product.ProductOpenSource, "/test/oss/folderPath", "", []vulnmap.Issue{critical, high, low})
+	scanNotifier.SendSuccess(product.ProductOpenSource, "/test/oss/folderPath", "", []vulnmap.Issue{critical, high})
+
+	messages := mockNotifier.SentMessages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, &lsp2.ScanSeverityCount{Critical: 1, High: 1, Low: 1}, messages[0].(lsp2.VulnmapScanParams).SeverityCount)
+	assert.Equal(t, &lsp2.ScanSeverityCount{Critical: 1, High: 1}, messages[1].(lsp2.VulnmapScanParams).SeverityCount)
+}
+
+func Test_SendSuccessForAllProducts_SendsInConfiguredDisplayOrder(t *testing.T) {
+	testutil.UnitTest(t)
+	config.CurrentConfig().SetProductDisplayOrder([]product.Product{product.ProductCode, product.ProductOpenSource})
+	mockNotifier := notification.NewMockNotifier()
+	scanNotifier, _ := notification2.NewScanNotifier(mockNotifier)
+
+	scanNotifier.SendSuccessForAllProducts("/test/folderPath", nil, []vulnmap.Issue{})
+
+	var sentProducts []string
+	for _, msg := range mockNotifier.SentMessages() {
+		sentProducts = append(sentProducts, msg.(lsp2.VulnmapScanParams).Product)
+	}
+	assert.Equal(t, []string{"code", "oss", "iac"}, sentProducts)
+}
+
+func Test_SendSuccess_SendsDataFlowOfVulnmapCodeIssues(t *testing.T) {
+	testutil.UnitTest(t)
+	mockNotifier := notification.NewMockNotifier()
+	scanNotifier, _ := notification2.NewScanNotifier(mockNotifier)
+	step := func(position int, message string) vulnmap.DataFlowElement {
+		return vulnmap.DataFlowElement{
+			Position:  position,
+			FilePath:  "/folder/app.js",
+			FlowRange: vulnmap.Range{Start: vulnmap.Position{Line: position}, End: vulnmap.Position{Line: position, Character: 4}},
+			Content:   "line of code",
+			Message:   message,
+		}
+	}
+	codeIssue := vulnmap.Issue{
+		ID:               "javascript/Sqli",
+		Severity:         vulnmap.High,
+		AffectedFilePath: "/folder/app.js",
+		Product:          product.ProductCode,
+		AdditionalData: vulnmap.CodeIssueData{
+			Key:      "key",
+			DataFlow: []vulnmap.DataFlowElement{step(0, "source"), step(1, "concatenation"), step(2, "sink")},
+		},
+	}
+
+	scanNotifier.SendSuccess(product.ProductCode, "/folder", "", []vulnmap.Issue{codeIssue})
+
+	require.Len(t, mockNotifier.SentMessages(), 1)
+	issues := mockNotifier.SentMessages()[0].(lsp2.VulnmapScanParams).Issues
+	require.Len(t, issues, 1)
+	dataFlow := issues[0].AdditionalData.(lsp2.CodeIssueData).DataFlow
+	require.Len(t, dataFlow, 3)
+	for i, message := range []string{"source", "concatenation", "sink"} {
+		assert.Equal(t, i, dataFlow[i].Position)
+		assert.Equal(t, "/folder/app.js", dataFlow[i].FilePath)
+		assert.Equal(t, i, dataFlow[i].FlowRange.Start.Line)
+		assert.Equal(t, 4, dataFlow[i].FlowRange.End.Character)
+		assert.Equal(t, "line of code", dataFlow[i].Content)
+		assert.Equal(t, message, dataFlow[i].Message)
+	}
+}
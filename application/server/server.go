@@ -19,8 +19,11 @@ package server
 import (
 	"context"
 	"os"
+	"os/signal"
 	"runtime"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/creachadair/jrpc2"
@@ -43,6 +46,7 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/cli"
 	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/learn"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/oss"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/progress"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
@@ -66,6 +70,11 @@ func Start(c *config.Config) {
 	logger.Info().Msg("Starting up...")
 	srv = srv.Start(channel.Header("")(os.Stdin, os.Stdout))
 
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go shutdownOnSignal(c, srv, signals)
+
 	status := srv.WaitStatus()
 	if status.Err != nil {
 		logger.Err(status.Err).Msg("server stopped because of error")
@@ -74,14 +83,51 @@ func Start(c *config.Config) {
 	}
 }
 
+// shutdownTimeout is the maximum time to wait for pending work during shutdown
+const shutdownTimeout = 5 * time.Second
+
+func shutdownOnSignal(c *config.Config, srv *jrpc2.Server, signals chan os.Signal) {
+	sig := <-signals
+	c.Logger().Info().Str("method", "shutdownOnSignal").Msgf("received %v, shutting down", sig)
+	gracefulShutdown(c)
+	srv.Stop()
+}
+
+// shutdownOnce guards the graceful shutdown of the current server, which can be triggered by the shutdown request,
+// the exit notification and by a signal. It is reset whenever the handlers of a new server are initialized.
+var shutdownOnce = &sync.Once{}
+
+// gracefulShutdown stops running scans, waits for pending analytics and releases the listeners. Only the first call
+// does the work, later calls return immediately.
+func gracefulShutdown(c *config.Config) {
+	shutdownOnce.Do(func() { shutdownServices(c) })
+}
+
+func shutdownServices(c *config.Config) {
+	logger := c.Logger().With().Str("method", "gracefulShutdown").Logger()
+	if w := workspace.Get(); w != nil && !w.Shutdown(shutdownTimeout) {
+		logger.Warn().Msg("shutdown timed out waiting for pending work")
+	}
+	di.ErrorReporter().FlushErrorReporting()
+
+	disposeProgressListener()
+	di.Notifier().DisposeListener()
+	err := di.Analytics().Shutdown()
+	if err != nil {
+		logger.Err(err).Msg("Error shutting down analytics")
+	}
+	di.HoverService().Close()
+}
+
 const textDocumentDidOpenOperation = "textDocument/didOpen"
 const textDocumentDidSaveOperation = "textDocument/didSave"
 
 func initHandlers(c *config.Config, srv *jrpc2.Server, handlers handler.Map) {
+	shutdownOnce = &sync.Once{}
 	handlers["initialize"] = initializeHandler(srv, c)
 	handlers["initialized"] = initializedHandler(srv)
 	handlers["textDocument/didChange"] = textDocumentDidChangeHandler()
-	handlers["textDocument/didClose"] = noOpHandler()
+	handlers["textDocument/didClose"] = textDocumentDidCloseHandler()
 	handlers[textDocumentDidOpenOperation] = textDocumentDidOpenHandler()
 	handlers[textDocumentDidSaveOperation] = textDocumentDidSaveHandler()
 	handlers["textDocument/hover"] = textDocumentHover()
@@ -264,6 +310,46 @@ func initializeHandler(srv *jrpc2.Server, c *config.Config) handler.Func {
 						vulnmap.GetLearnLesson,
 						vulnmap.GetSettingsSastEnabled,
 						vulnmap.GetActiveUserCommand,
+						vulnmap.SearchIssuesCommand,
+						vulnmap.ScanAndGateCommand,
+						vulnmap.GetFolderTrustStatusCommand,
+						vulnmap.UpgradeAllInFileCommand,
+						vulnmap.DiffScansCommand,
+						vulnmap.RescanFileCommand,
+						vulnmap.ExportCsvCommand,
+						vulnmap.ValidateManifestCommand,
+						vulnmap.GetLastScanOutputCommand,
+						vulnmap.WarmLearnCacheCommand,
+						vulnmap.IssuesByCWECommand,
+						vulnmap.ResendAnalyticsCommand,
+						vulnmap.RecordBaselineCommand,
+						vulnmap.ClearBaselineCommand,
+						vulnmap.TriggerScanCommand,
+						vulnmap.GetIssueHoverCommand,
+						vulnmap.PauseScanningCommand,
+						vulnmap.ResumeScanningCommand,
+						vulnmap.GetSupportedEcosystemsCommand,
+						vulnmap.GetAuthStateCommand,
+						vulnmap.GetIssueDeltaCommand,
+						vulnmap.ExportJunitCommand,
+						vulnmap.IssuesByEcosystemCommand,
+						vulnmap.TrustFolderCommand,
+						vulnmap.SetAuthMethodCommand,
+						vulnmap.CheckTokenPermissionsCommand,
+						vulnmap.OpenLogsCommand,
+						vulnmap.AcceptIssueCommand,
+						vulnmap.GetAcceptedIssuesCommand,
+						vulnmap.GetFixAdviceCommand,
+						vulnmap.GetScanWarningsCommand,
+						vulnmap.ResetTrustPromptCommand,
+						vulnmap.GetDependencyTreeCommand,
+						vulnmap.ApplyAllFixesCommand,
+						vulnmap.PreviewFilterCommand,
+						vulnmap.ExportAttestationCommand,
+						vulnmap.FindByCVECommand,
+						vulnmap.TagIssueCommand,
+						vulnmap.UntagIssueCommand,
+						vulnmap.GetIssueTagsCommand,
 						vulnmap.CodeFixCommand,
 						vulnmap.CodeSubmitFixFeedback,
 					},
@@ -304,7 +390,7 @@ func initializedHandler(srv *jrpc2.Server) handler.Func {
 		autoScanEnabled := config.CurrentConfig().IsAutoScanEnabled()
 		if autoScanEnabled && authenticated {
 			logger.Debug().Msg("triggering workspace scan after successful initialization")
-			workspace.Get().ScanWorkspace(context.Background())
+			workspace.Get().StartupScan(context.Background())
 		} else {
 			logger.Debug().Msg("No automatic workspace scan on initialization - auto-scan is disabled")
 		}
@@ -397,14 +483,7 @@ func shutdown(c *config.Config) jrpc2.Handler {
 		logger := c.Logger().With().Str("method", "Shutdown").Logger()
 		logger.Info().Msg("ENTERING")
 		defer logger.Info().Msg("RETURNING")
-		di.ErrorReporter().FlushErrorReporting()
-
-		disposeProgressListener()
-		di.Notifier().DisposeListener()
-		err := di.Analytics().Shutdown()
-		if err != nil {
-			logger.Err(err).Msg("Error shutting down analytics")
-		}
+		gracefulShutdown(c)
 		return nil, nil
 	})
 }
@@ -413,8 +492,7 @@ func exit(srv *jrpc2.Server, c *config.Config) jrpc2.Handler {
 	return handler.New(func(_ context.Context) (any, error) {
 		logger := c.Logger().With().Str("method", "Exit").Logger()
 		logger.Info().Msg("ENTERING")
-		logger.Info().Msg("Flushing error reporting...")
-		di.ErrorReporter().FlushErrorReporting()
+		gracefulShutdown(c)
 		logger.Info().Msg("Stopping server...")
 		srv.Stop()
 		return nil, nil
@@ -440,8 +518,9 @@ func textDocumentDidOpenHandler() jrpc2.Handler {
 			return nil, nil
 		}
 
+		folder.OpenFile(filePath)
 		issues := folder.DocumentDiagnosticsFromCache(filePath)
-		filteredIssues := workspace.FilterIssues(issues, config.CurrentConfig().DisplayableIssueTypes())
+		filteredIssues := workspace.FilterIssues(folder.Path(), issues, config.CurrentConfig().DisplayableIssueTypes())
 
 		if len(filteredIssues) > 0 {
 			logger.Info().Msg("Sending cached issues")
@@ -455,6 +534,24 @@ func textDocumentDidOpenHandler() jrpc2.Handler {
 		if scanner, ok := di.Scanner().(vulnmap.PackageScanner); ok {
 			scanner.ScanPackages(context.Background(), config.CurrentConfig(), filePath, "")
 		}
+
+		if config.CurrentConfig().IsAutoScanEnabled() && folder.ScanIfPending(context.Background(), filePath) {
+			logger.Info().Msg("triggered pending folder scan")
+		} else if config.CurrentConfig().IsAutoScanEnabled() && oss.IsSupportedManifest(filePath) {
+			// give quick feedback for opened manifests, if the folder scan didn't cover them yet
+			folder.ScanOpenedFile(context.Background(), filePath)
+		}
+		return nil, nil
+	})
+}
+
+func textDocumentDidCloseHandler() jrpc2.Handler {
+	return handler.New(func(_ context.Context, params sglsp.DidCloseTextDocumentParams) (any, error) {
+		filePath := uri.PathFromUri(params.TextDocument.URI)
+		log.Info().Str("method", "TextDocumentDidCloseHandler").Str("documentURI", filePath).Msg("RECEIVING")
+		if folder := workspace.Get().GetFolderContaining(filePath); folder != nil {
+			folder.CloseFile(filePath)
+		}
 		return nil, nil
 	})
 }
@@ -472,11 +569,14 @@ func textDocumentDidSaveHandler() jrpc2.Handler {
 
 		// todo can we push cache management down?
 		f := workspace.Get().GetFolderContaining(filePath)
+		if f != nil {
+			f.InvalidateStaleIssues(filePath)
+		}
 		autoScanEnabled := config.CurrentConfig().IsAutoScanEnabled()
 		if f != nil && autoScanEnabled {
 			f.ClearDiagnosticsFromFile(filePath)
 			di.HoverService().DeleteHover(filePath)
-			go f.ScanFile(bgCtx, filePath)
+			go f.TriggerScan(bgCtx, filePath)
 		} else {
 			if autoScanEnabled {
 				logger.Warn().Str("documentURI", filePath).Msg("Not scanning, file not part of workspace")
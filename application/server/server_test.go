@@ -191,6 +191,18 @@ func Test_shutdown_shouldBeServed(t *testing.T) {
 	assert.NotNil(t, rsp)
 }
 
+func Test_shutdown_andExit_shouldShutDownOnlyOnce(t *testing.T) {
+	loc := setupServer(t)
+	analytics := di.Analytics().(*ux.TestAnalytics)
+
+	_, err := loc.Client.Call(ctx, "shutdown", nil)
+	assert.NoError(t, err)
+	_ = loc.Client.Notify(ctx, "exit", nil)
+
+	assert.Eventually(t, func() bool { return loc.Server.WaitStatus().Stopped }, time.Second*5, time.Millisecond)
+	assert.Equal(t, 1, analytics.ShutdownCount)
+}
+
 func Test_initialize_containsServerInfo(t *testing.T) {
 	loc := setupServer(t)
 
@@ -0,0 +1,89 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// acceptIssueCommand accepts a single issue, so that it is hidden, and records why and by whom it was accepted.
+// Arguments: the fingerprint of the issue, the justification and an optional expiry date (YYYY-MM-DD). It returns
+// the recorded acceptance.
+type acceptIssueCommand struct {
+	command vulnmap.CommandData
+	// acceptedBy returns the name of the user accepting the issue, empty if it can't be determined
+	acceptedBy func() string
+}
+
+func (cmd *acceptIssueCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *acceptIssueCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 2 {
+		return nil, errors.New("command is missing arguments. expected: issue fingerprint, justification")
+	}
+	fingerprint, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("issue fingerprint must be a string")
+	}
+	justification, ok := args[1].(string)
+	if !ok {
+		return nil, errors.New("justification must be a string")
+	}
+	expires := ""
+	if len(args) > 2 {
+		expires, ok = args[2].(string)
+		if !ok {
+			return nil, errors.New("expiry date must be a string in the format YYYY-MM-DD")
+		}
+	}
+	return workspace.Get().AcceptIssue(fingerprint, justification, expires, cmd.acceptedBy())
+}
+
+// activeUserName returns the name of the authenticated user, or its id if the name is unknown
+func activeUserName() string {
+	user, err := vulnmap.GetActiveUser()
+	if err != nil {
+		log.Debug().Err(err).Str("method", "activeUserName").Msg("couldn't determine the active user")
+		return ""
+	}
+	if user.UserName != "" {
+		return user.UserName
+	}
+	return user.Id
+}
+
+// getAcceptedIssuesCommand returns the accepted issues with their justifications, including expired ones
+type getAcceptedIssuesCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *getAcceptedIssuesCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *getAcceptedIssuesCommand) Execute(_ context.Context) (any, error) {
+	return workspace.Get().AcceptedIssues(), nil
+}
@@ -0,0 +1,83 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_acceptIssueCommand_AcceptsIssueWithJustification(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAcceptedIssuesPath(filepath.Join(t.TempDir(), "accepted_issues.json"))
+	manifest := filepath.Join(t.TempDir(), "package.json")
+	folderPath := setupSearchIssuesWorkspace(t,
+		vulnmap.Issue{ID: "VULNMAP-JS-LODASH-1", AffectedFilePath: manifest, Product: product.ProductOpenSource},
+	)
+	folder := workspace.Get().GetFolderContaining(folderPath)
+	fingerprint := folder.AllIssuesFor(manifest)[0].Fingerprint()
+	cmd := acceptIssueCommand{
+		command: vulnmap.CommandData{
+			CommandId: vulnmap.AcceptIssueCommand,
+			Arguments: []any{fingerprint, "not reachable from user input", "2999-12-31"},
+		},
+		acceptedBy: func() string { return "jane" },
+	}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	accepted, ok := result.(lsp.AcceptedIssue)
+	require.True(t, ok)
+	assert.Equal(t, "VULNMAP-JS-LODASH-1", accepted.IssueId)
+	assert.Equal(t, "not reachable from user input", accepted.Justification)
+	assert.Equal(t, "jane", accepted.AcceptedBy)
+	assert.Equal(t, "2999-12-31", accepted.Expires)
+	assert.Empty(t, folder.FilteredIssues())
+
+	listCmd := getAcceptedIssuesCommand{command: vulnmap.CommandData{CommandId: vulnmap.GetAcceptedIssuesCommand}}
+	listed, err := listCmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	acceptedIssue := listed.([]lsp.AcceptedIssue)[0]
+	assert.Equal(t, fingerprint, acceptedIssue.Fingerprint)
+	assert.Equal(t, "not reachable from user input", acceptedIssue.Justification)
+	assert.Equal(t, "jane", acceptedIssue.AcceptedBy)
+	assert.True(t, accepted.AcceptedAt.Equal(acceptedIssue.AcceptedAt))
+}
+
+func Test_acceptIssueCommand_RequiresJustification(t *testing.T) {
+	cmd := acceptIssueCommand{
+		command:    vulnmap.CommandData{Arguments: []any{"fingerprint"}},
+		acceptedBy: func() string { return "" },
+	}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/converter"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/oss"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// applyAllFixesCommand returns a workspace edit that applies the upgrades of all visible open source issues of a
+// folder across all its manifests. Conflicting upgrades and major upgrades, unless they are included by the settings,
+// are skipped.
+// Arguments: the path of the folder.
+type applyAllFixesCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *applyAllFixesCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *applyAllFixesCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: folder path")
+	}
+	folderPath, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("folder path must be a string")
+	}
+	folder := workspace.Get().GetFolderContaining(folderPath)
+	if folder == nil {
+		return nil, errors.Errorf("folder %s is not in the workspace", folderPath)
+	}
+
+	plan := oss.PlanUpgradesInFolder(folder.FilteredIssues(), config.CurrentConfig().IsMajorUpgradeFixIncluded())
+
+	result := lsp.ApplyAllFixesResult{
+		Edit:      converter.ToWorkspaceEdit(plan.Edit),
+		Manifests: []lsp.ManifestUpgrades{},
+	}
+	for _, manifest := range plan.Manifests {
+		manifestUpgrades := lsp.ManifestUpgrades{
+			FilePath: manifest.FilePath,
+			Upgrades: []lsp.PackageUpgrade{},
+			Skipped:  []lsp.SkippedUpgrade{},
+		}
+		for _, upgrade := range manifest.Upgrades {
+			manifestUpgrades.Upgrades = append(manifestUpgrades.Upgrades, lsp.PackageUpgrade(upgrade))
+		}
+		for _, skipped := range manifest.Skipped {
+			manifestUpgrades.Skipped = append(manifestUpgrades.Skipped, lsp.SkippedUpgrade(skipped))
+		}
+		result.Manifests = append(result.Manifests, manifestUpgrades)
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+func applyAllFixesTestIssue(id string, manifest string, packageName string, upgrade string) vulnmap.Issue {
+	return vulnmap.Issue{
+		ID:               id,
+		AffectedFilePath: manifest,
+		Product:          product.ProductOpenSource,
+		Severity:         vulnmap.High,
+		AdditionalData: vulnmap.OssIssueData{
+			PackageName:  packageName,
+			IsUpgradable: upgrade != "",
+			UpgradePath:  []any{false, upgrade},
+		},
+	}
+}
+
+func Test_ApplyAllFixesCommand_ReturnsEditSpanningAllManifests(t *testing.T) {
+	testutil.UnitTest(t)
+	dir := t.TempDir()
+	frontend := filepath.Join(dir, "frontend", "package.json")
+	backend := filepath.Join(dir, "backend", "package.json")
+	testutil.CreateFileOrFail(t, frontend, []byte(`{
+  "dependencies": {
+    "lodash": "^4.17.4"
+  }
+}`))
+	testutil.CreateFileOrFail(t, backend, []byte(`{
+  "dependencies": {
+    "express": "4.12.4",
+    "tap": "5.8.0"
+  }
+}`))
+	folderPath := setupSearchIssuesWorkspace(t,
+		applyAllFixesTestIssue("VULNMAP-JS-LODASH-1", frontend, "lodash", "lodash@4.17.21"),
+		applyAllFixesTestIssue("VULNMAP-JS-EXPRESS-1", backend, "express", "express@4.19.2"),
+		applyAllFixesTestIssue("VULNMAP-JS-TAP-1", backend, "tap", ""),
+	)
+	cmd := applyAllFixesCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.ApplyAllFixesCommand,
+		Arguments: []any{folderPath},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	fixesResult, ok := result.(lsp.ApplyAllFixesResult)
+	require.True(t, ok)
+	require.NotNil(t, fixesResult.Edit)
+	assert.Len(t, fixesResult.Edit.Changes, 2)
+	frontendEdits := fixesResult.Edit.Changes[string(uri.PathToUri(frontend))]
+	require.Len(t, frontendEdits, 1)
+	assert.Equal(t, "^4.17.21", frontendEdits[0].NewText)
+	backendEdits := fixesResult.Edit.Changes[string(uri.PathToUri(backend))]
+	require.Len(t, backendEdits, 1)
+	assert.Equal(t, "4.19.2", backendEdits[0].NewText)
+	require.Len(t, fixesResult.Manifests, 2)
+	assert.Equal(t, backend, fixesResult.Manifests[0].FilePath)
+	assert.Equal(t, []lsp.SkippedUpgrade{{IssueId: "VULNMAP-JS-TAP-1", PackageName: "tap", Reason: "no upgrade path available"}},
+		fixesResult.Manifests[0].Skipped)
+	assert.Len(t, fixesResult.Manifests[1].Upgrades, 1)
+}
+
+func Test_ApplyAllFixesCommand_UnknownFolderReturnsError(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t)
+	cmd := applyAllFixesCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.ApplyAllFixesCommand,
+		Arguments: []any{filepath.Join(t.TempDir(), "unknown")},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
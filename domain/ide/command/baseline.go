@@ -0,0 +1,61 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// recordBaselineCommand accepts the current issues of the workspace, so that only new issues are shown.
+// Arguments: optional expiry date of the accepted issues (YYYY-MM-DD). It returns the number of accepted issues.
+type recordBaselineCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *recordBaselineCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *recordBaselineCommand) Execute(_ context.Context) (any, error) {
+	expires := ""
+	if len(cmd.command.Arguments) > 0 {
+		var ok bool
+		expires, ok = cmd.command.Arguments[0].(string)
+		if !ok {
+			return nil, errors.New("expiry date must be a string in the format YYYY-MM-DD")
+		}
+	}
+	return workspace.Get().RecordBaseline(expires)
+}
+
+// clearBaselineCommand removes the recorded baseline, so that all issues are shown again
+type clearBaselineCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *clearBaselineCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *clearBaselineCommand) Execute(_ context.Context) (any, error) {
+	return nil, workspace.Get().ClearBaseline()
+}
@@ -0,0 +1,76 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_BaselineCommands_RecordAndClear(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetBaselineEnabled(true)
+	c.SetBaselinePath(filepath.Join(t.TempDir(), "baseline.json"))
+	setupSearchIssuesWorkspace(t, vulnmap.Issue{
+		ID:               "VULNMAP-JS-LODASH-1",
+		AffectedFilePath: filepath.Join(t.TempDir(), "package.json"),
+		Product:          product.ProductOpenSource,
+		Severity:         vulnmap.High,
+	})
+	folder := workspace.Get().Folders()[0]
+	require.Len(t, folder.FilteredIssues(), 1)
+	record := recordBaselineCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.RecordBaselineCommand,
+		Arguments: []any{"2999-12-31"},
+	}}
+
+	accepted, err := record.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, accepted)
+	assert.Empty(t, folder.FilteredIssues())
+
+	clearCmd := clearBaselineCommand{command: vulnmap.CommandData{CommandId: vulnmap.ClearBaselineCommand}}
+	_, err = clearCmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, folder.FilteredIssues(), 1)
+}
+
+func Test_RecordBaselineCommand_InvalidExpiry(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetBaselinePath(filepath.Join(t.TempDir(), "baseline.json"))
+	folderPath := setupSearchIssuesWorkspace(t)
+	cmd := recordBaselineCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.RecordBaselineCommand,
+		Arguments: []any{"tomorrow"},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+	assert.NoFileExists(t, c.BaselinePath(folderPath))
+}
@@ -0,0 +1,113 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/vulnmap_api"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// iacEntitlement is the organization entitlement required for Vulnmap IaC scans
+const iacEntitlement = "infrastructureAsCode"
+
+// checkTokenPermissionsCommand reports whether the token is authenticated, has access to the organization, and
+// which products the organization is entitled to, e.g. to explain why Vulnmap Code scans return nothing.
+// Returns a lsp.TokenPermissions.
+type checkTokenPermissionsCommand struct {
+	command     vulnmap.CommandData
+	authService vulnmap.AuthenticationService
+	apiClient   vulnmap_api.VulnmapApiClient
+}
+
+func (cmd *checkTokenPermissionsCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *checkTokenPermissionsCommand) Execute(_ context.Context) (any, error) {
+	c := config.CurrentConfig()
+	permissions := lsp.TokenPermissions{Organization: c.Organization()}
+	if !c.NonEmptyToken() {
+		permissions.Error = "no token is set"
+		return permissions, nil
+	}
+	if _, err := cmd.authService.Provider().GetCheckAuthenticationFunction()(); err != nil {
+		if vulnmap.IsTransientError(err) {
+			return nil, err
+		}
+		permissions.Error = err.Error()
+		return permissions, nil
+	}
+	permissions.Authenticated = true
+
+	entitlements, err := cmd.apiClient.Entitlements(permissions.Organization)
+	if err != nil {
+		if !isPermissionDenied(err) {
+			return nil, err
+		}
+		log.Debug().Err(err).Str("method", "checkTokenPermissionsCommand.Execute").Msg("no access to organization")
+		permissions.Error = "the token has no access to the organization"
+		permissions.Products = productPermissions(false, false, nil, permissions.Error)
+		return permissions, nil
+	}
+	permissions.OrganizationAccess = true
+	permissions.Entitlements = entitlements
+
+	sastResponse, err := cmd.apiClient.SastSettings()
+	if err != nil && !isPermissionDenied(err) {
+		return nil, err
+	}
+	permissions.Products = productPermissions(true, err == nil && sastResponse.SastEnabled, entitlements, "")
+	return permissions, nil
+}
+
+// productPermissions returns the permissions of the scanned products. Open Source only requires access to the
+// organization, Code has to be enabled for the organization and IaC requires an entitlement.
+func productPermissions(orgAccess bool, sastEnabled bool, entitlements map[string]bool, noAccessReason string) []lsp.ProductPermission {
+	permission := func(p product.Product, entitled bool, reason string) lsp.ProductPermission {
+		if !orgAccess {
+			return lsp.ProductPermission{Product: string(p), Reason: noAccessReason}
+		}
+		if entitled {
+			reason = ""
+		}
+		return lsp.ProductPermission{Product: string(p), Entitled: entitled, Reason: reason}
+	}
+	return []lsp.ProductPermission{
+		permission(product.ProductOpenSource, true, ""),
+		permission(product.ProductCode, sastEnabled, "Vulnmap Code is not enabled for the organization"),
+		permission(product.ProductInfrastructureAsCode, entitlements[iacEntitlement],
+			"the organization is not entitled to Vulnmap IaC"),
+	}
+}
+
+// isPermissionDenied returns true if the API rejected the call because the token lacks permissions
+func isPermissionDenied(err error) bool {
+	var apiError *vulnmap_api.VulnmapApiError
+	if !errors.As(err, &apiError) {
+		return false
+	}
+	return apiError.StatusCode() == http.StatusUnauthorized || apiError.StatusCode() == http.StatusForbidden
+}
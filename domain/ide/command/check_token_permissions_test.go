@@ -0,0 +1,119 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/ux"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/vulnmap_api"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+const testOrganization = "54125374-3f93-402e-b693-e0724794d71f"
+
+func setupCheckTokenPermissions(t *testing.T, authenticated bool, apiClient vulnmap_api.VulnmapApiClient) *checkTokenPermissionsCommand {
+	t.Helper()
+	c := testutil.UnitTest(t)
+	c.SetToken("token")
+	c.SetOrganization(testOrganization)
+	provider := vulnmap.NewFakeCliAuthenticationProvider()
+	provider.IsAuthenticated = authenticated
+	authService := vulnmap.NewAuthenticationService(provider, ux.NewTestAnalytics(),
+		error_reporting.NewTestErrorReporter(), notification.NewNotifier())
+	return &checkTokenPermissionsCommand{
+		command:     vulnmap.CommandData{CommandId: vulnmap.CheckTokenPermissionsCommand},
+		authService: authService,
+		apiClient:   apiClient,
+	}
+}
+
+func Test_CheckTokenPermissionsCommand_ReportsLimitedEntitlements(t *testing.T) {
+	apiClient := &vulnmap_api.FakeApiClient{
+		CodeEnabled:       false,
+		EntitlementsByOrg: map[string]map[string]bool{testOrganization: {"infrastructureAsCode": true}},
+	}
+	cmd := setupCheckTokenPermissions(t, true, apiClient)
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	permissions := result.(lsp.TokenPermissions)
+	assert.True(t, permissions.Authenticated)
+	assert.True(t, permissions.OrganizationAccess)
+	assert.Equal(t, testOrganization, permissions.Organization)
+	assert.Equal(t, map[string]bool{"infrastructureAsCode": true}, permissions.Entitlements)
+	assert.Equal(t, []lsp.ProductPermission{
+		{Product: string(product.ProductOpenSource), Entitled: true},
+		{Product: string(product.ProductCode), Reason: "Vulnmap Code is not enabled for the organization"},
+		{Product: string(product.ProductInfrastructureAsCode), Entitled: true},
+	}, permissions.Products)
+	assert.Equal(t, []any{testOrganization}, apiClient.GetCallParams(0, vulnmap_api.EntitlementsOperation))
+}
+
+func Test_CheckTokenPermissionsCommand_ReportsMissingOrganizationAccess(t *testing.T) {
+	apiClient := &vulnmap_api.FakeApiClient{
+		CodeEnabled:       true,
+		EntitlementsError: vulnmap_api.NewVulnmapApiError("forbidden", http.StatusForbidden),
+	}
+	cmd := setupCheckTokenPermissions(t, true, apiClient)
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	permissions := result.(lsp.TokenPermissions)
+	assert.True(t, permissions.Authenticated)
+	assert.False(t, permissions.OrganizationAccess)
+	assert.Equal(t, "the token has no access to the organization", permissions.Error)
+	for _, productPermission := range permissions.Products {
+		assert.False(t, productPermission.Entitled, productPermission.Product)
+	}
+	assert.Empty(t, apiClient.GetAllCalls(vulnmap_api.SastEnabledOperation))
+}
+
+func Test_CheckTokenPermissionsCommand_ReportsFailedAuthenticationWithoutCallingApi(t *testing.T) {
+	apiClient := &vulnmap_api.FakeApiClient{}
+	cmd := setupCheckTokenPermissions(t, false, apiClient)
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	permissions := result.(lsp.TokenPermissions)
+	assert.False(t, permissions.Authenticated)
+	assert.NotEmpty(t, permissions.Error)
+	assert.Empty(t, permissions.Products)
+	assert.Empty(t, apiClient.GetAllCalls(vulnmap_api.EntitlementsOperation))
+}
+
+func Test_CheckTokenPermissionsCommand_ReturnsServerErrors(t *testing.T) {
+	apiError := vulnmap_api.NewVulnmapApiError("unavailable", http.StatusServiceUnavailable)
+	cmd := setupCheckTokenPermissions(t, true, &vulnmap_api.FakeApiClient{EntitlementsError: apiError})
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Equal(t, apiError, err)
+}
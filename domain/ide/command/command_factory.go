@@ -36,6 +36,7 @@ func CreateFromCommandData(
 	notifier noti.Notifier,
 	issueProvider ide.IssueProvider,
 	codeApiClient VulnmapCodeHttpClient,
+	authProviderFactory AuthenticationProviderFactory,
 ) (vulnmap.Command, error) {
 
 	switch commandData.CommandId {
@@ -63,9 +64,99 @@ func CreateFromCommandData(
 		apiClient := vulnmap_api.NewVulnmapApiClient(config.CurrentConfig().Engine().GetNetworkAccess().GetHttpClient)
 		return &sastEnabled{command: commandData, apiClient: apiClient}, nil
 	case vulnmap.GetActiveUserCommand:
-		return &getActiveUser{command: commandData, authService: authService, notifier: notifier}, nil
+		return &getActiveUser{
+			command:      commandData,
+			authService:  authService,
+			notifier:     notifier,
+			retryBackoff: getActiveUserRetryBackoff,
+		}, nil
 	case vulnmap.ReportAnalyticsCommand:
 		return &reportAnalyticsCommand{command: commandData}, nil
+	case vulnmap.SearchIssuesCommand:
+		return &searchIssuesCommand{command: commandData}, nil
+	case vulnmap.ScanAndGateCommand:
+		return &scanAndGateCommand{command: commandData, authService: authService}, nil
+	case vulnmap.GetFolderTrustStatusCommand:
+		return &folderTrustStatusCommand{command: commandData}, nil
+	case vulnmap.UpgradeAllInFileCommand:
+		return &upgradeAllInFileCommand{command: commandData}, nil
+	case vulnmap.DiffScansCommand:
+		return &diffScansCommand{command: commandData}, nil
+	case vulnmap.RescanFileCommand:
+		return &rescanFileCommand{command: commandData}, nil
+	case vulnmap.ExportCsvCommand:
+		return &exportCsvCommand{command: commandData}, nil
+	case vulnmap.ValidateManifestCommand:
+		return &validateManifestCommand{command: commandData}, nil
+	case vulnmap.GetLastScanOutputCommand:
+		return &getLastScanOutputCommand{command: commandData}, nil
+	case vulnmap.WarmLearnCacheCommand:
+		return &warmLearnCacheCommand{command: commandData, learnService: learnService}, nil
+	case vulnmap.IssuesByCWECommand:
+		return &issuesByCWECommand{command: commandData}, nil
+	case vulnmap.ResendAnalyticsCommand:
+		return &resendAnalyticsCommand{command: commandData, retryBackoff: resendAnalyticsRetryBackoff}, nil
+	case vulnmap.RecordBaselineCommand:
+		return &recordBaselineCommand{command: commandData}, nil
+	case vulnmap.ClearBaselineCommand:
+		return &clearBaselineCommand{command: commandData}, nil
+	case vulnmap.TriggerScanCommand:
+		return &triggerScanCommand{command: commandData}, nil
+	case vulnmap.GetIssueHoverCommand:
+		return &getIssueHoverCommand{command: commandData}, nil
+	case vulnmap.PauseScanningCommand:
+		return &pauseScanningCommand{command: commandData}, nil
+	case vulnmap.ResumeScanningCommand:
+		return &resumeScanningCommand{command: commandData}, nil
+	case vulnmap.GetSupportedEcosystemsCommand:
+		return &getSupportedEcosystemsCommand{command: commandData}, nil
+	case vulnmap.GetAuthStateCommand:
+		return &getAuthStateCommand{command: commandData, authService: authService}, nil
+	case vulnmap.GetIssueDeltaCommand:
+		return &getIssueDeltaCommand{command: commandData}, nil
+	case vulnmap.ExportJunitCommand:
+		return &exportJunitCommand{command: commandData}, nil
+	case vulnmap.IssuesByEcosystemCommand:
+		return &issuesByEcosystemCommand{command: commandData}, nil
+	case vulnmap.TrustFolderCommand:
+		return &trustFolderCommand{command: commandData}, nil
+	case vulnmap.SetAuthMethodCommand:
+		return &setAuthMethodCommand{
+			command:             commandData,
+			authService:         authService,
+			authProviderFactory: authProviderFactory,
+		}, nil
+	case vulnmap.CheckTokenPermissionsCommand:
+		apiClient := vulnmap_api.NewVulnmapApiClient(config.CurrentConfig().Engine().GetNetworkAccess().GetHttpClient)
+		return &checkTokenPermissionsCommand{command: commandData, authService: authService, apiClient: apiClient}, nil
+	case vulnmap.OpenLogsCommand:
+		return &openLogsCommand{command: commandData}, nil
+	case vulnmap.AcceptIssueCommand:
+		return &acceptIssueCommand{command: commandData, acceptedBy: activeUserName}, nil
+	case vulnmap.GetAcceptedIssuesCommand:
+		return &getAcceptedIssuesCommand{command: commandData}, nil
+	case vulnmap.GetFixAdviceCommand:
+		return &getFixAdviceCommand{command: commandData}, nil
+	case vulnmap.GetScanWarningsCommand:
+		return &getScanWarningsCommand{command: commandData}, nil
+	case vulnmap.ResetTrustPromptCommand:
+		return &resetTrustPromptCommand{command: commandData}, nil
+	case vulnmap.GetDependencyTreeCommand:
+		return &getDependencyTreeCommand{command: commandData}, nil
+	case vulnmap.ApplyAllFixesCommand:
+		return &applyAllFixesCommand{command: commandData}, nil
+	case vulnmap.PreviewFilterCommand:
+		return &previewFilterCommand{command: commandData}, nil
+	case vulnmap.ExportAttestationCommand:
+		return &exportAttestationCommand{command: commandData}, nil
+	case vulnmap.FindByCVECommand:
+		return &findByCVECommand{command: commandData}, nil
+	case vulnmap.TagIssueCommand:
+		return &tagIssueCommand{command: commandData}, nil
+	case vulnmap.UntagIssueCommand:
+		return &untagIssueCommand{command: commandData}, nil
+	case vulnmap.GetIssueTagsCommand:
+		return &getIssueTagsCommand{command: commandData}, nil
 	case vulnmap.CodeFixCommand:
 		return &fixCodeIssue{command: commandData, issueProvider: issueProvider, notifier: notifier}, nil
 	case vulnmap.CodeSubmitFixFeedback:
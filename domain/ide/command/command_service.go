@@ -33,20 +33,22 @@ import (
 var instance vulnmap.CommandService
 
 type serviceImpl struct {
-	authService   vulnmap.AuthenticationService
-	notifier      noti.Notifier
-	learnService  learn.Service
-	issueProvider ide.IssueProvider
-	codeApiClient VulnmapCodeHttpClient
+	authService         vulnmap.AuthenticationService
+	notifier            noti.Notifier
+	learnService        learn.Service
+	issueProvider       ide.IssueProvider
+	codeApiClient       VulnmapCodeHttpClient
+	authProviderFactory AuthenticationProviderFactory
 }
 
-func NewService(authService vulnmap.AuthenticationService, notifier noti.Notifier, learnService learn.Service, issueProvider ide.IssueProvider, codeApiClient VulnmapCodeHttpClient) vulnmap.CommandService {
+func NewService(authService vulnmap.AuthenticationService, notifier noti.Notifier, learnService learn.Service, issueProvider ide.IssueProvider, codeApiClient VulnmapCodeHttpClient, authProviderFactory AuthenticationProviderFactory) vulnmap.CommandService {
 	return &serviceImpl{
-		authService:   authService,
-		notifier:      notifier,
-		learnService:  learnService,
-		issueProvider: issueProvider,
-		codeApiClient: codeApiClient,
+		authService:         authService,
+		notifier:            notifier,
+		learnService:        learnService,
+		issueProvider:       issueProvider,
+		codeApiClient:       codeApiClient,
+		authProviderFactory: authProviderFactory,
 	}
 }
 
@@ -67,7 +69,7 @@ func (service *serviceImpl) ExecuteCommandData(ctx context.Context, commandData
 		"command.serviceImpl.ExecuteCommandData",
 	).Msgf("executing command %s", commandData.CommandId)
 
-	command, err := CreateFromCommandData(commandData, server, service.authService, service.learnService, service.notifier, service.issueProvider, service.codeApiClient)
+	command, err := CreateFromCommandData(commandData, server, service.authService, service.learnService, service.notifier, service.issueProvider, service.codeApiClient, service.authProviderFactory)
 	if err != nil {
 		log.Error().Err(err).Str("method", "command.serviceImpl.ExecuteCommandData").Msg("failed to create command")
 		return nil, err
@@ -0,0 +1,136 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// diffScansCommand compares the issues of two scans, e.g. of a base branch and a pull request branch, and reports
+// which issues were added, removed or stayed unchanged.
+// Arguments: the base and the head lsp.ScanSnapshot, each either as an object or as a JSON string.
+type diffScansCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *diffScansCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *diffScansCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 2 {
+		return nil, errors.New("command is missing arguments. expected: base snapshot, head snapshot")
+	}
+	base, err := toScanSnapshot(args[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read base snapshot")
+	}
+	head, err := toScanSnapshot(args[1])
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read head snapshot")
+	}
+	return diffScans(base, head), nil
+}
+
+func toScanSnapshot(arg any) (lsp.ScanSnapshot, error) {
+	var snapshot lsp.ScanSnapshot
+	payload, isString := arg.(string)
+	if !isString {
+		bytes, err := json.Marshal(arg)
+		if err != nil {
+			return snapshot, err
+		}
+		payload = string(bytes)
+	}
+	err := json.Unmarshal([]byte(payload), &snapshot)
+	return snapshot, err
+}
+
+// diffScans matches the issues of both snapshots by their fingerprint. Unchanged issues are reported as found in
+// the head snapshot.
+func diffScans(base lsp.ScanSnapshot, head lsp.ScanSnapshot) lsp.DiffScansResult {
+	baseIssues, baseFingerprints := fingerprintIssues(base)
+	headIssues, headFingerprints := fingerprintIssues(head)
+
+	result := lsp.DiffScansResult{
+		Added:     lsp.IssueDiffGroup{Issues: []lsp.SnapshotIssue{}},
+		Removed:   lsp.IssueDiffGroup{Issues: []lsp.SnapshotIssue{}},
+		Unchanged: lsp.IssueDiffGroup{Issues: []lsp.SnapshotIssue{}},
+	}
+	for _, issue := range headIssues {
+		if baseFingerprints[issue.fingerprint] {
+			result.Unchanged.Issues = append(result.Unchanged.Issues, issue.SnapshotIssue)
+		} else {
+			result.Added.Issues = append(result.Added.Issues, issue.SnapshotIssue)
+		}
+	}
+	for _, issue := range baseIssues {
+		if !headFingerprints[issue.fingerprint] {
+			result.Removed.Issues = append(result.Removed.Issues, issue.SnapshotIssue)
+		}
+	}
+	result.Added.Count = len(result.Added.Issues)
+	result.Removed.Count = len(result.Removed.Issues)
+	result.Unchanged.Count = len(result.Unchanged.Issues)
+	return result
+}
+
+type fingerprintedIssue struct {
+	lsp.SnapshotIssue
+	fingerprint string
+}
+
+// fingerprintIssues returns the issues of the snapshot in their original order without duplicate fingerprints,
+// and the set of fingerprints.
+func fingerprintIssues(snapshot lsp.ScanSnapshot) ([]fingerprintedIssue, map[string]bool) {
+	var issues []fingerprintedIssue
+	fingerprints := map[string]bool{}
+	for _, issue := range snapshot.Issues {
+		fingerprint := issueFingerprint(snapshot.Root, issue)
+		if fingerprints[fingerprint] {
+			continue
+		}
+		fingerprints[fingerprint] = true
+		issues = append(issues, fingerprintedIssue{SnapshotIssue: issue, fingerprint: fingerprint})
+	}
+	return issues, fingerprints
+}
+
+// issueFingerprint identifies an issue across scans of different revisions or checkouts by its ID, the affected
+// package@version and the file path relative to the scanned root.
+func issueFingerprint(root string, issue lsp.SnapshotIssue) string {
+	return strings.Join([]string{issue.Id, issue.PackageName + "@" + issue.Version, normalizePath(root, issue.FilePath)}, "|")
+}
+
+func normalizePath(root string, path string) string {
+	if root != "" {
+		relativePath, err := filepath.Rel(root, path)
+		if err == nil && !strings.HasPrefix(relativePath, "..") {
+			path = relativePath
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
@@ -0,0 +1,124 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func dependencyBumpSnapshots(t *testing.T) (lsp.ScanSnapshot, lsp.ScanSnapshot) {
+	t.Helper()
+	baseRoot := filepath.Join(t.TempDir(), "main")
+	headRoot := filepath.Join(t.TempDir(), "feature")
+	base := lsp.ScanSnapshot{
+		Root: baseRoot,
+		Issues: []lsp.SnapshotIssue{
+			{Id: "VULNMAP-JS-LODASH-567746", FilePath: filepath.Join(baseRoot, "package.json"), PackageName: "lodash", Version: "4.17.4"},
+			{Id: "VULNMAP-JS-LODASH-1018905", FilePath: filepath.Join(baseRoot, "package.json"), PackageName: "lodash", Version: "4.17.4"},
+			{Id: "VULNMAP-JS-MINIMIST-559764", FilePath: filepath.Join(baseRoot, "package.json"), PackageName: "minimist", Version: "0.0.8"},
+		},
+	}
+	// lodash was bumped, which fixes its issues but pulls in a vulnerable transitive dependency
+	head := lsp.ScanSnapshot{
+		Root: headRoot,
+		Issues: []lsp.SnapshotIssue{
+			{Id: "VULNMAP-JS-MINIMIST-559764", FilePath: filepath.Join(headRoot, "package.json"), PackageName: "minimist", Version: "0.0.8"},
+			{Id: "VULNMAP-JS-MS-10064", FilePath: filepath.Join(headRoot, "package.json"), PackageName: "ms", Version: "0.7.0"},
+		},
+	}
+	return base, head
+}
+
+func Test_DiffScansCommand_DependencyBumpRemovesAndAddsIssues(t *testing.T) {
+	testutil.UnitTest(t)
+	base, head := dependencyBumpSnapshots(t)
+	cmd := diffScansCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.DiffScansCommand,
+		Arguments: []any{base, head},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	diff, ok := result.(lsp.DiffScansResult)
+	require.True(t, ok)
+	assert.Equal(t, 1, diff.Added.Count)
+	assert.Equal(t, "VULNMAP-JS-MS-10064", diff.Added.Issues[0].Id)
+	assert.Equal(t, 2, diff.Removed.Count)
+	assert.Equal(t, "VULNMAP-JS-LODASH-567746", diff.Removed.Issues[0].Id)
+	assert.Equal(t, "VULNMAP-JS-LODASH-1018905", diff.Removed.Issues[1].Id)
+	assert.Equal(t, 1, diff.Unchanged.Count)
+	assert.Equal(t, head.Issues[0], diff.Unchanged.Issues[0])
+}
+
+func Test_DiffScansCommand_AcceptsJsonStringSnapshots(t *testing.T) {
+	testutil.UnitTest(t)
+	base, head := dependencyBumpSnapshots(t)
+	baseJson, err := json.Marshal(base)
+	require.NoError(t, err)
+	headJson, err := json.Marshal(head)
+	require.NoError(t, err)
+	cmd := diffScansCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.DiffScansCommand,
+		Arguments: []any{string(baseJson), string(headJson)},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	diff := result.(lsp.DiffScansResult)
+	assert.Equal(t, 1, diff.Added.Count)
+	assert.Equal(t, 2, diff.Removed.Count)
+	assert.Equal(t, 1, diff.Unchanged.Count)
+}
+
+func Test_DiffScans_VersionChangeIsReportedAsNewIssue(t *testing.T) {
+	base := lsp.ScanSnapshot{Issues: []lsp.SnapshotIssue{
+		{Id: "VULNMAP-JS-MINIMIST-559764", FilePath: "package.json", PackageName: "minimist", Version: "0.0.8"},
+	}}
+	head := lsp.ScanSnapshot{Issues: []lsp.SnapshotIssue{
+		{Id: "VULNMAP-JS-MINIMIST-559764", FilePath: "package.json", PackageName: "minimist", Version: "0.0.10"},
+	}}
+
+	diff := diffScans(base, head)
+
+	assert.Equal(t, 1, diff.Added.Count)
+	assert.Equal(t, 1, diff.Removed.Count)
+	assert.Equal(t, 0, diff.Unchanged.Count)
+}
+
+func Test_DiffScansCommand_MissingSnapshotReturnsError(t *testing.T) {
+	testutil.UnitTest(t)
+	cmd := diffScansCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.DiffScansCommand,
+		Arguments: []any{"{}"},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,283 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+const (
+	inTotoStatementType      = "https://in-toto.io/Statement/v1"
+	inTotoPayloadType        = "application/vnd.in-toto+json"
+	scanSummaryPredicateType = "https://vulnmap.khulnasoft.com/attestation/scan-summary/v1"
+)
+
+// inTotoStatement is an in-toto attestation statement, see https://github.com/in-toto/attestation
+type inTotoStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []inTotoSubject      `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     scanSummaryPredicate `json:"predicate"`
+}
+
+// inTotoSubject identifies a scanned folder by its URI, as folders have no content digest
+type inTotoSubject struct {
+	Name string `json:"name"`
+	URI  string `json:"uri"`
+}
+
+type scanSummaryPredicate struct {
+	Scanner   attestationScanner  `json:"scanner"`
+	Timestamp string              `json:"timestamp"`
+	Folders   []attestationFolder `json:"folders"`
+}
+
+type attestationScanner struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	CliVersion string `json:"cliVersion,omitempty"`
+}
+
+type attestationFolder struct {
+	Path           string                   `json:"path"`
+	Scans          []attestationScan        `json:"scans"`
+	SeverityCounts attestationSeverityCount `json:"severityCounts"`
+}
+
+type attestationScan struct {
+	Product           string `json:"product"`
+	DurationMs        int64  `json:"durationMs"`
+	TimestampFinished string `json:"timestampFinished"`
+}
+
+type attestationSeverityCount struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+}
+
+// dsseEnvelope is a signed attestation, see https://github.com/secure-systems-lab/dsse
+type dsseEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []dsseSignature `json:"signatures"`
+}
+
+type dsseSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// exportAttestationCommand writes an in-toto statement summarizing the scans of the workspace folders, i.e. the
+// scanned folders, the scanner versions, when the products were scanned and the severity counts of the visible
+// issues. Folders that weren't scanned yet are left out, as there are no results to attest. With a configured signing
+// key, the statement is written as a signed DSSE envelope.
+// Arguments: the path of the JSON file and optionally a folder path, without it all workspace folders are summarized.
+type exportAttestationCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *exportAttestationCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *exportAttestationCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: output path, [folder path]")
+	}
+	outputPath, ok := args[0].(string)
+	if !ok || outputPath == "" {
+		return nil, errors.New("output path must be a non-empty string")
+	}
+	w := workspace.Get()
+	folders := w.Folders()
+	if len(args) > 1 {
+		folderPath, isString := args[1].(string)
+		if !isString {
+			return nil, errors.New("folder path must be a string")
+		}
+		folder := w.GetFolderContaining(folderPath)
+		if folder == nil {
+			return nil, errors.Errorf("folder %s is not in the workspace", folderPath)
+		}
+		folders = []*workspace.Folder{folder}
+	}
+
+	c := config.CurrentConfig()
+	summary, err := newScanSummaryStatement(c, folders, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	statement, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't encode attestation")
+	}
+
+	output := statement
+	if keyPath := c.AttestationSigningKeyPath(); keyPath != "" {
+		signer, err := loadAttestationSigner(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		envelope, err := signStatement(signer, statement)
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't sign attestation")
+		}
+		if output, err = json.MarshalIndent(envelope, "", "  "); err != nil {
+			return nil, errors.Wrap(err, "couldn't encode signed attestation")
+		}
+	}
+
+	if err = os.WriteFile(outputPath, append(output, '\n'), 0600); err != nil {
+		return nil, errors.Wrap(err, "couldn't write attestation file")
+	}
+	return nil, nil
+}
+
+// newScanSummaryStatement summarizes the scanned folders and fails if none of the folders was scanned
+func newScanSummaryStatement(c *config.Config, folders []*workspace.Folder, now time.Time) (inTotoStatement, error) {
+	statement := inTotoStatement{
+		Type:          inTotoStatementType,
+		Subject:       []inTotoSubject{},
+		PredicateType: scanSummaryPredicateType,
+		Predicate: scanSummaryPredicate{
+			Scanner: attestationScanner{
+				Name:       "vulnmap-ls",
+				Version:    config.Version,
+				CliVersion: c.CliSettings().Version(),
+			},
+			Timestamp: now.UTC().Format(time.RFC3339),
+			Folders:   []attestationFolder{},
+		},
+	}
+	for _, folder := range folders {
+		lastScans := folder.LastScans()
+		if len(lastScans) == 0 {
+			continue
+		}
+		statement.Subject = append(statement.Subject, inTotoSubject{
+			Name: folder.Path(),
+			URI:  string(uri.PathToUri(folder.Path())),
+		})
+		severityCount := folder.FilteredSeverityCount()
+		summary := attestationFolder{
+			Path:  folder.Path(),
+			Scans: []attestationScan{},
+			SeverityCounts: attestationSeverityCount{
+				Critical: severityCount.Critical,
+				High:     severityCount.High,
+				Medium:   severityCount.Medium,
+				Low:      severityCount.Low,
+			},
+		}
+		for _, scan := range lastScans {
+			summary.Scans = append(summary.Scans, attestationScan{
+				Product:           string(scan.Product),
+				DurationMs:        scan.DurationMs,
+				TimestampFinished: scan.TimestampFinished.UTC().Format(time.RFC3339),
+			})
+		}
+		statement.Predicate.Folders = append(statement.Predicate.Folders, summary)
+	}
+	if len(statement.Subject) == 0 {
+		return inTotoStatement{}, errors.New("no scanned folder to attest, scan the folders first")
+	}
+	return statement, nil
+}
+
+// loadAttestationSigner reads a PEM encoded PKCS #8 private key
+func loadAttestationSigner(keyPath string) (crypto.Signer, error) {
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read attestation signing key")
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.Errorf("attestation signing key %s is not PEM encoded", keyPath)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't parse attestation signing key")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("attestation signing key %s can't sign", keyPath)
+	}
+	return signer, nil
+}
+
+// signStatement wraps the statement in a DSSE envelope signed with the signer. Ed25519 keys sign the message itself,
+// all other keys its SHA-256 digest.
+func signStatement(signer crypto.Signer, statement []byte) (dsseEnvelope, error) {
+	message := dssePreAuthEncoding(inTotoPayloadType, statement)
+	var sig []byte
+	var err error
+	if _, isEd25519 := signer.(ed25519.PrivateKey); isEd25519 {
+		sig, err = signer.Sign(rand.Reader, message, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(message)
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return dsseEnvelope{}, err
+	}
+	keyID, err := attestationKeyID(signer.Public())
+	if err != nil {
+		return dsseEnvelope{}, err
+	}
+	return dsseEnvelope{
+		PayloadType: inTotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(statement),
+		Signatures:  []dsseSignature{{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// dssePreAuthEncoding returns the message that is signed for a DSSE envelope
+func dssePreAuthEncoding(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// attestationKeyID identifies a signing key by the SHA-256 digest of its DER encoded public key
+func attestationKeyID(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(der)
+	return hex.EncodeToString(digest[:]), nil
+}
@@ -0,0 +1,193 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+func exportAttestation(t *testing.T) []byte {
+	t.Helper()
+	outputPath := filepath.Join(t.TempDir(), "attestation.json")
+	cmd := exportAttestationCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.ExportAttestationCommand,
+		Arguments: []any{outputPath},
+	}}
+	_, err := cmd.Execute(context.Background())
+	require.NoError(t, err)
+	output, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	return output
+}
+
+func writeSigningKey(t *testing.T, key crypto.PrivateKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "attestation.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	return keyPath
+}
+
+func decodeSignedAttestation(t *testing.T, output []byte) (dsseEnvelope, []byte, []byte) {
+	t.Helper()
+	var envelope dsseEnvelope
+	require.NoError(t, json.Unmarshal(output, &envelope))
+	assert.Equal(t, "application/vnd.in-toto+json", envelope.PayloadType)
+	require.Len(t, envelope.Signatures, 1)
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	require.NoError(t, err)
+	sig, err := base64.StdEncoding.DecodeString(envelope.Signatures[0].Sig)
+	require.NoError(t, err)
+	return envelope, payload, sig
+}
+
+func Test_ExportAttestationCommand_WritesStatement(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.CliSettings().SetVersion("1.1234.0 (standalone)")
+	manifest := filepath.Join(t.TempDir(), "package.json")
+	folderPath := setupSearchIssuesWorkspace(t,
+		vulnmap.NewTestOssIssue("critical", manifest, vulnmap.Critical),
+		vulnmap.NewTestOssIssue("high", manifest, vulnmap.High),
+		vulnmap.NewTestOssIssue("other-high", manifest, vulnmap.High),
+	)
+
+	var statement inTotoStatement
+	require.NoError(t, json.Unmarshal(exportAttestation(t), &statement))
+
+	assert.Equal(t, "https://in-toto.io/Statement/v1", statement.Type)
+	assert.Equal(t, "https://vulnmap.khulnasoft.com/attestation/scan-summary/v1", statement.PredicateType)
+	assert.Equal(t, []inTotoSubject{{Name: folderPath, URI: string(uri.PathToUri(folderPath))}}, statement.Subject)
+	assert.Equal(t, attestationScanner{Name: "vulnmap-ls", Version: config.Version, CliVersion: "1.1234.0 (standalone)"},
+		statement.Predicate.Scanner)
+	timestamp, err := time.Parse(time.RFC3339, statement.Predicate.Timestamp)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), timestamp, time.Minute)
+
+	require.Len(t, statement.Predicate.Folders, 1)
+	folder := statement.Predicate.Folders[0]
+	assert.Equal(t, folderPath, folder.Path)
+	assert.Equal(t, attestationSeverityCount{Critical: 1, High: 2}, folder.SeverityCounts)
+	require.Len(t, folder.Scans, 1)
+	assert.Equal(t, string(product.ProductOpenSource), folder.Scans[0].Product)
+	assert.Equal(t, int64(1234), folder.Scans[0].DurationMs)
+	assert.NotEmpty(t, folder.Scans[0].TimestampFinished)
+}
+
+func Test_ExportAttestationCommand_SkipsFoldersThatWereNotScanned(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := setupSearchIssuesWorkspace(t)
+	w := workspace.Get()
+	notifier := notification.NewNotifier()
+	w.AddFolder(workspace.NewFolder(t.TempDir(), "not scanned", vulnmap.NewTestScanner(), hover.NewFakeHoverService(),
+		vulnmap.NewMockScanNotifier(), notifier))
+
+	var statement inTotoStatement
+	require.NoError(t, json.Unmarshal(exportAttestation(t), &statement))
+
+	assert.Equal(t, []inTotoSubject{{Name: folderPath, URI: string(uri.PathToUri(folderPath))}}, statement.Subject)
+	require.Len(t, statement.Predicate.Folders, 1)
+	assert.Equal(t, folderPath, statement.Predicate.Folders[0].Path)
+}
+
+func Test_ExportAttestationCommand_FailsWithoutScannedFolder(t *testing.T) {
+	testutil.UnitTest(t)
+	notifier := notification.NewNotifier()
+	w := workspace.New(performance.NewInstrumentor(), vulnmap.NewTestScanner(), hover.NewFakeHoverService(),
+		vulnmap.NewMockScanNotifier(), notifier)
+	workspace.Set(w)
+	w.AddFolder(workspace.NewFolder(t.TempDir(), "not scanned", vulnmap.NewTestScanner(), hover.NewFakeHoverService(),
+		vulnmap.NewMockScanNotifier(), notifier))
+	outputPath := filepath.Join(t.TempDir(), "attestation.json")
+
+	_, err := (&exportAttestationCommand{command: vulnmap.CommandData{Arguments: []any{outputPath}}}).Execute(context.Background())
+
+	assert.Error(t, err)
+	assert.NoFileExists(t, outputPath)
+}
+
+func Test_ExportAttestationCommand_SignsWithEd25519Key(t *testing.T) {
+	c := testutil.UnitTest(t)
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	c.SetAttestationSigningKeyPath(writeSigningKey(t, privateKey))
+	setupSearchIssuesWorkspace(t)
+
+	envelope, payload, sig := decodeSignedAttestation(t, exportAttestation(t))
+
+	assert.True(t, ed25519.Verify(publicKey, dssePreAuthEncoding(envelope.PayloadType, payload), sig))
+	keyID, err := attestationKeyID(publicKey)
+	require.NoError(t, err)
+	assert.Equal(t, keyID, envelope.Signatures[0].KeyID)
+	var statement inTotoStatement
+	require.NoError(t, json.Unmarshal(payload, &statement))
+	assert.Equal(t, "https://in-toto.io/Statement/v1", statement.Type)
+}
+
+func Test_ExportAttestationCommand_SignsWithEcdsaKey(t *testing.T) {
+	c := testutil.UnitTest(t)
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	c.SetAttestationSigningKeyPath(writeSigningKey(t, privateKey))
+	setupSearchIssuesWorkspace(t)
+
+	envelope, payload, sig := decodeSignedAttestation(t, exportAttestation(t))
+
+	digest := sha256.Sum256(dssePreAuthEncoding(envelope.PayloadType, payload))
+	assert.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], sig))
+}
+
+func Test_ExportAttestationCommand_InvalidSigningKey(t *testing.T) {
+	c := testutil.UnitTest(t)
+	keyPath := filepath.Join(t.TempDir(), "attestation.pem")
+	require.NoError(t, os.WriteFile(keyPath, []byte("not a key"), 0600))
+	c.SetAttestationSigningKeyPath(keyPath)
+	setupSearchIssuesWorkspace(t)
+	outputPath := filepath.Join(t.TempDir(), "attestation.json")
+
+	_, err := (&exportAttestationCommand{command: vulnmap.CommandData{Arguments: []any{outputPath}}}).Execute(context.Background())
+
+	assert.Error(t, err)
+	assert.NoFileExists(t, outputPath)
+}
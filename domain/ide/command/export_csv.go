@@ -0,0 +1,139 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+var csvHeader = []string{
+	"ID", "Title", "Severity", "Product", "Package", "Version", "CVE", "CWE", "Fixed In", "File Path", "CVSS Score",
+	"Description", "Details",
+}
+
+// exportCsvCommand writes the issues of all workspace folders that are visible with the current filters to a CSV file.
+// Arguments: the path of the CSV file.
+type exportCsvCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *exportCsvCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *exportCsvCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: output path")
+	}
+	outputPath, ok := args[0].(string)
+	if !ok || outputPath == "" {
+		return nil, errors.New("output path must be a non-empty string")
+	}
+
+	issues := exportedIssues()
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't create CSV file")
+	}
+	defer func() { _ = file.Close() }()
+	if err = writeIssuesCsv(file, issues); err != nil {
+		return nil, errors.Wrap(err, "couldn't write CSV file")
+	}
+	return nil, file.Close()
+}
+
+// exportedIssues returns the issues of all workspace folders that are visible with the current filters, ordered by
+// file, severity and ID
+func exportedIssues() []vulnmap.Issue {
+	var issues []vulnmap.Issue
+	for _, folder := range workspace.Get().Folders() {
+		issues = append(issues, folder.FilteredIssues()...)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.AffectedFilePath != b.AffectedFilePath {
+			return a.AffectedFilePath < b.AffectedFilePath
+		}
+		if a.Severity != b.Severity {
+			return a.Severity < b.Severity
+		}
+		return a.ID < b.ID
+	})
+	return issues
+}
+
+// writeIssuesCsv writes a header and one row per issue. Fields containing separators, quotes or newlines are quoted.
+func writeIssuesCsv(w io.Writer, issues []vulnmap.Issue) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		if err := writer.Write(toCsvRecord(issue)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// toCsvRecord converts the issue to a row, its details are rendered in the output format configured for exports
+func toCsvRecord(issue vulnmap.Issue) []string {
+	var packageName, version, fixedIn, cvssScore string
+	description := issue.Message
+	if data, isOss := issue.AdditionalData.(vulnmap.OssIssueData); isOss {
+		packageName = data.PackageName
+		version = data.Version
+		fixedIn = strings.Join(data.FixedIn, ", ")
+		if data.CvssScore > 0 {
+			cvssScore = strconv.FormatFloat(data.CvssScore, 'f', -1, 64)
+		}
+		description = data.Description
+	}
+	return []string{
+		issue.ID,
+		workspace.IssueTitle(issue),
+		issue.Severity.String(),
+		string(issue.Product),
+		packageName,
+		version,
+		strings.Join(issue.CVEs, ", "),
+		strings.Join(issue.CWEs, ", "),
+		fixedIn,
+		workspace.DisplayPath(issue.AffectedFilePath),
+		cvssScore,
+		workspace.DisplayPathsIn(description, issue.AffectedFilePath),
+		workspace.DisplayPathsIn(
+			issue.FormattedMessageIn(config.CurrentConfig().FormatFor(config.RenderContextExport)),
+			issue.AffectedFilePath,
+		),
+	}
+}
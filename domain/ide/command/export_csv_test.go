@@ -0,0 +1,178 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+func csvTestIssue(filePath string) vulnmap.Issue {
+	return vulnmap.Issue{
+		ID:               "VULNMAP-JS-LODASH-567746",
+		Severity:         vulnmap.High,
+		Product:          product.ProductOpenSource,
+		AffectedFilePath: filePath,
+		CVEs:             []string{"CVE-2020-8203"},
+		CWEs:             []string{"CWE-400"},
+		AdditionalData: vulnmap.OssIssueData{
+			Title:       "Prototype Pollution",
+			PackageName: "lodash",
+			Version:     "4.17.4",
+			FixedIn:     []string{"4.17.16"},
+			CvssScore:   7.4,
+			Description: "Affected versions are vulnerable to Prototype Pollution via zipObjectDeep, set, and merge.\n\"Upgrade\" lodash.",
+		},
+	}
+}
+
+func Test_writeIssuesCsv_QuotesFieldsWithCommasAndNewlines(t *testing.T) {
+	issue := csvTestIssue("/project/package.json")
+	var buffer bytes.Buffer
+
+	err := writeIssuesCsv(&buffer, []vulnmap.Issue{issue})
+
+	require.NoError(t, err)
+	assert.Contains(t, buffer.String(),
+		`"Affected versions are vulnerable to Prototype Pollution via zipObjectDeep, set, and merge.`+"\n"+`""Upgrade"" lodash."`)
+	records, err := csv.NewReader(&buffer).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{
+		"VULNMAP-JS-LODASH-567746",
+		"Prototype Pollution",
+		"high",
+		string(product.ProductOpenSource),
+		"lodash",
+		"4.17.4",
+		"CVE-2020-8203",
+		"CWE-400",
+		"4.17.16",
+		"/project/package.json",
+		"7.4",
+		issue.AdditionalData.(vulnmap.OssIssueData).Description,
+		"",
+	}, records[1])
+}
+
+func Test_writeIssuesCsv_RendersDetailsInExportFormat(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetFormatFor(config.RenderContextExport, config.FormatHtml)
+	issue := csvTestIssue("/project/package.json")
+	issue.FormattedMessage = "### Prototype Pollution"
+	issue.FormattedMessages = map[string]string{config.FormatHtml: "<h3>Prototype Pollution</h3>"}
+	var buffer bytes.Buffer
+
+	err := writeIssuesCsv(&buffer, []vulnmap.Issue{issue})
+
+	require.NoError(t, err)
+	records, err := csv.NewReader(&buffer).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, "<h3>Prototype Pollution</h3>", records[1][len(csvHeader)-1])
+}
+
+func Test_ExportCsvCommand_WritesIssuesOfAllFolders(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	manifestPath := filepath.Join(folderPath, "package.json")
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(csvTestIssue(manifestPath))
+	notifier := notification.NewNotifier()
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	folder := workspace.NewFolder(folderPath, "test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(folder)
+	workspace.Set(w)
+	folder.ScanFolder(context.Background())
+	outputPath := filepath.Join(t.TempDir(), "issues.csv")
+	cmd := exportCsvCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.ExportCsvCommand,
+		Arguments: []any{outputPath},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	file, err := os.Open(outputPath)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "VULNMAP-JS-LODASH-567746", records[1][0])
+	assert.Equal(t, manifestPath, records[1][9])
+}
+
+func Test_ExportCsvCommand_WritesRelativeFilePathsIfConfigured(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetRelativeFilePathDisplay(true)
+	folderPath := t.TempDir()
+	manifestPath := filepath.Join(folderPath, "app", "package.json")
+	scanner := vulnmap.NewTestScanner()
+	issue := csvTestIssue(manifestPath)
+	issue.FormattedMessage = "introduced in [package.json](" + string(uri.PathToUri(manifestPath)) + ")"
+	scanner.AddTestIssue(issue)
+	notifier := notification.NewNotifier()
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	folder := workspace.NewFolder(folderPath, "test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(folder)
+	workspace.Set(w)
+	folder.ScanFolder(context.Background())
+	outputPath := filepath.Join(t.TempDir(), "issues.csv")
+	cmd := exportCsvCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.ExportCsvCommand,
+		Arguments: []any{outputPath},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "app/package.json", records[1][9])
+	assert.Equal(t, "introduced in [package.json](app/package.json)", records[1][12])
+	assert.Equal(t, manifestPath, folder.FilteredIssues()[0].AffectedFilePath)
+}
+
+func Test_ExportCsvCommand_MissingOutputPathReturnsError(t *testing.T) {
+	testutil.UnitTest(t)
+	cmd := exportCsvCommand{command: vulnmap.CommandData{CommandId: vulnmap.ExportCsvCommand}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,166 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// exportJunitCommand writes the issues of all workspace folders that are visible with the current filters to a JUnit
+// XML file, so that CI systems can show them in their test reports. Every issue is a failed test case and the issues
+// of a product are grouped into one test suite.
+// Arguments: the path of the XML file.
+type exportJunitCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *exportJunitCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *exportJunitCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: output path")
+	}
+	outputPath, ok := args[0].(string)
+	if !ok || outputPath == "" {
+		return nil, errors.New("output path must be a non-empty string")
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't create JUnit file")
+	}
+	defer func() { _ = file.Close() }()
+	if err = writeIssuesJunit(file, exportedIssues()); err != nil {
+		return nil, errors.Wrap(err, "couldn't write JUnit file")
+	}
+	return nil, file.Close()
+}
+
+// writeIssuesJunit writes the issues as JUnit XML with one test suite per product, ordered by product name.
+func writeIssuesJunit(w io.Writer, issues []vulnmap.Issue) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err = encoder.Encode(toJunitTestSuites(issues)); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func toJunitTestSuites(issues []vulnmap.Issue) junitTestSuites {
+	suitesByProduct := map[product.Product]*junitTestSuite{}
+	for _, issue := range issues {
+		suite, ok := suitesByProduct[issue.Product]
+		if !ok {
+			suite = &junitTestSuite{Name: string(issue.Product)}
+			suitesByProduct[issue.Product] = suite
+		}
+		suite.TestCases = append(suite.TestCases, toJunitTestCase(issue))
+		suite.Tests++
+		suite.Failures++
+	}
+
+	result := junitTestSuites{Name: "Vulnmap", Suites: []junitTestSuite{}}
+	for _, suite := range suitesByProduct {
+		result.Suites = append(result.Suites, *suite)
+		result.Tests += suite.Tests
+		result.Failures += suite.Failures
+	}
+	sort.Slice(result.Suites, func(i, j int) bool {
+		return result.Suites[i].Name < result.Suites[j].Name
+	})
+	return result
+}
+
+// toJunitTestCase uses the package of an open source issue as class name and the file of any other issue. The test
+// case is named after the CVEs of the issue and falls back to the issue ID.
+func toJunitTestCase(issue vulnmap.Issue) junitTestCase {
+	displayPath := workspace.DisplayPath(issue.AffectedFilePath)
+	className := displayPath
+	if data, isOss := issue.AdditionalData.(vulnmap.OssIssueData); isOss && data.PackageName != "" {
+		className = data.PackageName
+		if data.Version != "" {
+			className += "@" + data.Version
+		}
+	}
+	name := issue.ID
+	if len(issue.CVEs) > 0 {
+		name = strings.Join(issue.CVEs, ", ")
+	}
+
+	severity := issue.Severity.String()
+	message := workspace.DisplayPathsIn(issue.Message, issue.AffectedFilePath)
+	text := fmt.Sprintf("%s\nSeverity: %s\nID: %s\nFile: %s:%d", message, severity, issue.ID, displayPath,
+		issue.Range.Start.Line+1)
+	return junitTestCase{
+		ClassName: className,
+		Name:      name,
+		File:      displayPath,
+		Failure: &junitFailure{
+			Message: fmt.Sprintf("[%s] %s", severity, workspace.IssueTitle(issue)),
+			Type:    severity,
+			Text:    text,
+		},
+	}
+}
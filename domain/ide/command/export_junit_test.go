@@ -0,0 +1,130 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_writeIssuesJunit_WritesWellFormedXml(t *testing.T) {
+	ossIssue := csvTestIssue("/project/package.json")
+	ossIssue.Message = "Prototype Pollution <in> lodash & friends"
+	codeIssue := vulnmap.Issue{
+		ID:               "javascript/XSS",
+		Severity:         vulnmap.Medium,
+		Product:          product.ProductCode,
+		AffectedFilePath: "/project/app.js",
+		Message:          "Cross-site Scripting",
+	}
+	var buffer bytes.Buffer
+
+	err := writeIssuesJunit(&buffer, []vulnmap.Issue{ossIssue, codeIssue})
+
+	require.NoError(t, err)
+	var result junitTestSuites
+	require.NoError(t, xml.Unmarshal(buffer.Bytes(), &result))
+	assert.Equal(t, 2, result.Tests)
+	assert.Equal(t, 2, result.Failures)
+	require.Len(t, result.Suites, 2)
+	assert.Equal(t, string(product.ProductCode), result.Suites[0].Name)
+	assert.Equal(t, string(product.ProductOpenSource), result.Suites[1].Name)
+	ossTestCase := result.Suites[1].TestCases[0]
+	assert.Equal(t, "lodash@4.17.4", ossTestCase.ClassName)
+	assert.Equal(t, "CVE-2020-8203", ossTestCase.Name)
+	assert.Contains(t, ossTestCase.Failure.Text, ossIssue.Message)
+	codeTestCase := result.Suites[0].TestCases[0]
+	assert.Equal(t, "/project/app.js", codeTestCase.ClassName)
+	assert.Equal(t, "javascript/XSS", codeTestCase.Name)
+}
+
+func Test_writeIssuesJunit_CriticalIssueProducesFailure(t *testing.T) {
+	issue := csvTestIssue("/project/package.json")
+	issue.Severity = vulnmap.Critical
+	var buffer bytes.Buffer
+
+	err := writeIssuesJunit(&buffer, []vulnmap.Issue{issue})
+
+	require.NoError(t, err)
+	assert.Contains(t, buffer.String(), `<failure message="[critical] Prototype Pollution" type="critical">`)
+}
+
+func Test_writeIssuesJunit_NoIssuesWritesEmptyReport(t *testing.T) {
+	var buffer bytes.Buffer
+
+	err := writeIssuesJunit(&buffer, nil)
+
+	require.NoError(t, err)
+	var result junitTestSuites
+	require.NoError(t, xml.Unmarshal(buffer.Bytes(), &result))
+	assert.Equal(t, 0, result.Tests)
+	assert.Empty(t, result.Suites)
+}
+
+func Test_ExportJunitCommand_WritesIssuesOfAllFolders(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	manifestPath := filepath.Join(folderPath, "package.json")
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(csvTestIssue(manifestPath))
+	notifier := notification.NewNotifier()
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	folder := workspace.NewFolder(folderPath, "test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(folder)
+	workspace.Set(w)
+	folder.ScanFolder(context.Background())
+	outputPath := filepath.Join(t.TempDir(), "vulnmap-junit.xml")
+	cmd := exportJunitCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.ExportJunitCommand,
+		Arguments: []any{outputPath},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	var result junitTestSuites
+	require.NoError(t, xml.Unmarshal(content, &result))
+	require.Len(t, result.Suites, 1)
+	require.Len(t, result.Suites[0].TestCases, 1)
+	assert.Equal(t, manifestPath, result.Suites[0].TestCases[0].File)
+}
+
+func Test_ExportJunitCommand_MissingOutputPathReturnsError(t *testing.T) {
+	testutil.UnitTest(t)
+	cmd := exportJunitCommand{command: vulnmap.CommandData{CommandId: vulnmap.ExportJunitCommand}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
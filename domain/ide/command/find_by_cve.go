@@ -0,0 +1,71 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/converter"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// findByCVECommand returns every file, package and version of the workspace that is affected by a CVE, so that
+// incident responders can check whether an advisory concerns them.
+// Arguments: the CVE, e.g. "CVE-2021-44228".
+type findByCVECommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *findByCVECommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *findByCVECommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: CVE")
+	}
+	cve, ok := args[0].(string)
+	if !ok || cve == "" {
+		return nil, errors.New("CVE must be a non-empty string")
+	}
+
+	occurrences := workspace.Get().FindByCVE(cve)
+	results := make([]lsp.CVEOccurrence, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		issue := occurrence.Issue
+		result := lsp.CVEOccurrence{
+			FolderPath: occurrence.FolderPath,
+			FilePath:   issue.AffectedFilePath,
+			Range:      converter.ToRange(issue.Range),
+			Id:         issue.ID,
+			Title:      workspace.IssueTitle(issue),
+			Severity:   issue.Severity.String(),
+			Product:    string(issue.Product),
+		}
+		if data, isOss := issue.AdditionalData.(vulnmap.OssIssueData); isOss {
+			result.PackageName = data.PackageName
+			result.Version = data.Version
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,98 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_FindByCVECommand_ReturnsAffectedPackages(t *testing.T) {
+	testutil.UnitTest(t)
+	manifest := filepath.Join(t.TempDir(), "pom.xml")
+	folderPath := setupSearchIssuesWorkspace(t,
+		vulnmap.Issue{
+			ID:               "VULNMAP-JAVA-LOG4J-1",
+			AffectedFilePath: manifest,
+			Product:          product.ProductOpenSource,
+			Severity:         vulnmap.Critical,
+			CVEs:             []string{"CVE-2021-44228"},
+			AdditionalData: vulnmap.OssIssueData{
+				Title:       "Remote Code Execution",
+				PackageName: "log4j-core",
+				Version:     "2.14.1",
+			},
+		},
+		vulnmap.Issue{
+			ID:               "VULNMAP-JS-LODASH-1",
+			AffectedFilePath: manifest,
+			Product:          product.ProductOpenSource,
+			Severity:         vulnmap.High,
+			CVEs:             []string{"CVE-2021-23337"},
+			AdditionalData:   vulnmap.OssIssueData{Title: "Command Injection", PackageName: "lodash"},
+		},
+	)
+	cmd := findByCVECommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.FindByCVECommand,
+		Arguments: []any{"CVE-2021-44228"},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	occurrences, ok := result.([]lsp.CVEOccurrence)
+	require.True(t, ok)
+	require.Len(t, occurrences, 1)
+	assert.Equal(t, folderPath, occurrences[0].FolderPath)
+	assert.Equal(t, manifest, occurrences[0].FilePath)
+	assert.Equal(t, "VULNMAP-JAVA-LOG4J-1", occurrences[0].Id)
+	assert.Equal(t, "Remote Code Execution", occurrences[0].Title)
+	assert.Equal(t, "log4j-core", occurrences[0].PackageName)
+	assert.Equal(t, "2.14.1", occurrences[0].Version)
+}
+
+func Test_FindByCVECommand_ReturnsEmptyResultForUnknownCVE(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t)
+	cmd := findByCVECommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.FindByCVECommand,
+		Arguments: []any{"CVE-1999-0001"},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []lsp.CVEOccurrence{}, result)
+}
+
+func Test_FindByCVECommand_RequiresCVE(t *testing.T) {
+	testutil.UnitTest(t)
+	cmd := findByCVECommand{command: vulnmap.CommandData{CommandId: vulnmap.FindByCVECommand}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"sort"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+const trustFeatureDisabledNote = "trusted folder feature is disabled, all folders are trusted"
+
+// folderTrustStatusCommand returns the trust status of all open workspace folders, so that users can find out
+// why a folder is not scanned
+type folderTrustStatusCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *folderTrustStatusCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *folderTrustStatusCommand) Execute(_ context.Context) (any, error) {
+	featureEnabled := config.CurrentConfig().IsTrustedFolderFeatureEnabled()
+	folders := workspace.Get().Folders()
+	statuses := make([]lsp.FolderTrustStatus, 0, len(folders))
+	for _, folder := range folders {
+		status := lsp.FolderTrustStatus{FolderPath: folder.Path()}
+		if featureEnabled {
+			status.TrustedPrefix, status.Trusted = folder.TrustedPrefix()
+		} else {
+			status.Trusted = true
+			status.Note = trustFeatureDisabledNote
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].FolderPath < statuses[j].FolderPath
+	})
+	return statuses, nil
+}
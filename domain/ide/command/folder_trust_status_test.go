@@ -0,0 +1,77 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func setupTrustStatusWorkspace(t *testing.T, folderPaths ...string) {
+	t.Helper()
+	notifier := notification.NewNotifier()
+	scanner := vulnmap.NewTestScanner()
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	for _, folderPath := range folderPaths {
+		w.AddFolder(workspace.NewFolder(folderPath, filepath.Base(folderPath), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier))
+	}
+	workspace.Set(w)
+}
+
+func Test_FolderTrustStatusCommand_ReportsTrustedAndUntrustedFolders(t *testing.T) {
+	c := testutil.UnitTest(t)
+	root := t.TempDir()
+	trustedFolder := filepath.Join(root, "trusted", "project")
+	untrustedFolder := filepath.Join(root, "untrusted")
+	c.SetTrustedFolderFeatureEnabled(true)
+	c.SetTrustedFolders([]string{filepath.Join(root, "trusted")})
+	setupTrustStatusWorkspace(t, trustedFolder, untrustedFolder)
+	cmd := folderTrustStatusCommand{command: vulnmap.CommandData{CommandId: vulnmap.GetFolderTrustStatusCommand}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []lsp.FolderTrustStatus{
+		{FolderPath: trustedFolder, Trusted: true, TrustedPrefix: filepath.Join(root, "trusted")},
+		{FolderPath: untrustedFolder, Trusted: false},
+	}, result)
+}
+
+func Test_FolderTrustStatusCommand_FeatureDisabledReportsAllTrusted(t *testing.T) {
+	c := testutil.UnitTest(t)
+	folder := t.TempDir()
+	c.SetTrustedFolderFeatureEnabled(false)
+	setupTrustStatusWorkspace(t, folder)
+	cmd := folderTrustStatusCommand{command: vulnmap.CommandData{CommandId: vulnmap.GetFolderTrustStatusCommand}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []lsp.FolderTrustStatus{{FolderPath: folder, Trusted: true, Note: trustFeatureDisabledNote}}, result)
+}
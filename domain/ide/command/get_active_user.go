@@ -18,11 +18,19 @@ package command
 
 import (
 	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
 
 	noti "github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 )
 
+const (
+	getActiveUserMaxAttempts  = 3
+	getActiveUserRetryBackoff = 500 * time.Millisecond
+)
+
 // oauthRefreshCommand is a command that refreshes the oauth token
 // This is needed because the token is only valid for a certain period of time
 // For doing this we call the whoami workflow that will refresh the token automatically
@@ -30,13 +38,30 @@ type getActiveUser struct {
 	command     vulnmap.CommandData
 	authService vulnmap.AuthenticationService
 	notifier    noti.Notifier
+	// retryBackoff is the wait time before the first retry of a transient failure, it doubles with every retry
+	retryBackoff time.Duration
 }
 
 func (cmd *getActiveUser) Command() vulnmap.CommandData {
 	return cmd.command
 }
 
+// Execute retries transient failures (network and server errors) with exponential backoff. Authentication errors
+// are returned immediately, so that the user can re-authenticate.
 func (cmd *getActiveUser) Execute(ctx context.Context) (any, error) {
-	user, err := vulnmap.GetActiveUser()
-	return user, err
+	backoff := cmd.retryBackoff
+	for attempt := 1; ; attempt++ {
+		user, err := vulnmap.GetActiveUser()
+		if err == nil || attempt == getActiveUserMaxAttempts || !vulnmap.IsTransientError(err) {
+			return user, err
+		}
+		log.Debug().Err(err).Str("method", "getActiveUser.Execute").Int("attempt", attempt).
+			Msg("transient failure getting active user, retrying")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
 }
@@ -20,6 +20,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
+	"net/http"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -29,6 +32,7 @@ import (
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/vulnmap_api"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
 )
@@ -96,6 +100,78 @@ func Test_getActiveUser_Execute_Error_Result(t *testing.T) {
 	assert.Empty(t, actualUser)
 }
 
+func Test_getActiveUser_Execute_RetriesTransientErrors(t *testing.T) {
+	testutil.UnitTest(t)
+	cmd := &getActiveUser{
+		command: vulnmap.CommandData{
+			CommandId: vulnmap.GetActiveUserCommand,
+		},
+	}
+
+	expectedUser, expectedUserData := whoamiWorkflowResponse(t)
+	c := config.CurrentConfig()
+	c.SetAuthenticationMethod(lsp.OAuthAuthentication)
+	mockEngine, engineConfig := setUpEngineMock(t, c)
+	mockEngine.EXPECT().GetConfiguration().Return(engineConfig).AnyTimes()
+	networkError := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection reset by peer")}
+	gomock.InOrder(
+		mockEngine.EXPECT().InvokeWithConfig(localworkflows.WORKFLOWID_WHOAMI, gomock.Any()).Return(nil, networkError),
+		mockEngine.EXPECT().InvokeWithConfig(localworkflows.WORKFLOWID_WHOAMI, gomock.Any()).
+			Return(nil, vulnmap_api.NewVulnmapApiError("request failed (status 503)", http.StatusServiceUnavailable)),
+		mockEngine.EXPECT().InvokeWithConfig(localworkflows.WORKFLOWID_WHOAMI, gomock.Any()).Return(expectedUserData, nil),
+	)
+
+	actualUser, err := cmd.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUser, actualUser)
+}
+
+func Test_getActiveUser_Execute_AuthenticationErrorIsNotRetried(t *testing.T) {
+	testutil.UnitTest(t)
+	cmd := &getActiveUser{
+		command: vulnmap.CommandData{
+			CommandId: vulnmap.GetActiveUserCommand,
+		},
+	}
+
+	c := config.CurrentConfig()
+	c.SetAuthenticationMethod(lsp.OAuthAuthentication)
+	mockEngine, engineConfig := setUpEngineMock(t, c)
+	mockEngine.EXPECT().GetConfiguration().Return(engineConfig).AnyTimes()
+	mockEngine.EXPECT().InvokeWithConfig(localworkflows.WORKFLOWID_WHOAMI, gomock.Any()).
+		Return(nil, fmt.Errorf("error while fetching user: %w",
+			vulnmap_api.NewVulnmapApiError("invalid API key (status 401)", http.StatusUnauthorized))).
+		Times(1)
+
+	actualUser, err := cmd.Execute(context.Background())
+
+	var authenticationFailedError *vulnmap.AuthenticationFailedError
+	assert.ErrorAs(t, err, &authenticationFailedError)
+	assert.Nil(t, actualUser)
+}
+
+func Test_getActiveUser_Execute_GivesUpAfterMaxAttempts(t *testing.T) {
+	testutil.UnitTest(t)
+	cmd := &getActiveUser{
+		command: vulnmap.CommandData{
+			CommandId: vulnmap.GetActiveUserCommand,
+		},
+	}
+
+	c := config.CurrentConfig()
+	c.SetAuthenticationMethod(lsp.OAuthAuthentication)
+	mockEngine, engineConfig := setUpEngineMock(t, c)
+	mockEngine.EXPECT().GetConfiguration().Return(engineConfig).AnyTimes()
+	mockEngine.EXPECT().InvokeWithConfig(localworkflows.WORKFLOWID_WHOAMI, gomock.Any()).
+		Return(nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("no such host")}).
+		Times(getActiveUserMaxAttempts)
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
+
 func whoamiWorkflowResponse(t *testing.T) (*vulnmap.ActiveUser, []workflow.Data) {
 	expectedUser := vulnmap.ActiveUser{
 		Id:       "id",
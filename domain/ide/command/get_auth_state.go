@@ -0,0 +1,37 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// getAuthStateCommand returns whether the user is authenticated, never authenticated or has an expired token
+type getAuthStateCommand struct {
+	command     vulnmap.CommandData
+	authService vulnmap.AuthenticationService
+}
+
+func (cmd *getAuthStateCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *getAuthStateCommand) Execute(_ context.Context) (any, error) {
+	return cmd.authService.AuthState()
+}
@@ -0,0 +1,56 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/ux"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_GetAuthState_ReturnsExpiredForInvalidToken(t *testing.T) {
+	testutil.UnitTest(t)
+	provider := vulnmap.NewFakeCliAuthenticationProvider()
+	authenticationService := vulnmap.NewAuthenticationService(
+		provider,
+		ux.NewTestAnalytics(),
+		error_reporting.NewTestErrorReporter(),
+		notification.NewNotifier(),
+	)
+	cmd := getAuthStateCommand{
+		command:     vulnmap.CommandData{CommandId: vulnmap.GetAuthStateCommand},
+		authService: authenticationService,
+	}
+
+	state, err := cmd.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, vulnmap.AuthStateExpired, state)
+
+	provider.IsAuthenticated = true
+	state, err = cmd.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, vulnmap.AuthStateAuthenticated, state)
+}
@@ -0,0 +1,91 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// getDependencyTreeCommand returns the dependency paths of the open source issues merged into a tree, so that users
+// can explore where vulnerabilities enter their dependency graph.
+// Arguments: optionally a folder path, without it the trees of all workspace folders are returned.
+type getDependencyTreeCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *getDependencyTreeCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *getDependencyTreeCommand) Execute(_ context.Context) (any, error) {
+	w := workspace.Get()
+	folders := w.Folders()
+	if args := cmd.command.Arguments; len(args) > 0 {
+		folderPath, ok := args[0].(string)
+		if !ok {
+			return nil, errors.New("folder path must be a string")
+		}
+		folder := w.GetFolderContaining(folderPath)
+		if folder == nil {
+			return nil, errors.Errorf("folder %s is not in the workspace", folderPath)
+		}
+		folders = []*workspace.Folder{folder}
+	}
+
+	trees := []lsp.DependencyTree{}
+	for _, folder := range folders {
+		dependencies := folder.DependencyTree()
+		if len(dependencies) == 0 {
+			continue
+		}
+		trees = append(trees, lsp.DependencyTree{
+			FolderPath:   folder.Path(),
+			Dependencies: toDependencyNodes(dependencies),
+		})
+	}
+	return trees, nil
+}
+
+func toDependencyNodes(nodes []*workspace.DependencyNode) []lsp.DependencyNode {
+	results := make([]lsp.DependencyNode, 0, len(nodes))
+	for _, node := range nodes {
+		result := lsp.DependencyNode{
+			Package:      node.Package,
+			Severity:     node.Severity.String(),
+			Dependencies: toDependencyNodes(node.Dependencies),
+		}
+		for _, issue := range node.Vulnerabilities {
+			title := issue.Message
+			if data, isOss := issue.AdditionalData.(vulnmap.OssIssueData); isOss {
+				title = data.Title
+			}
+			result.Vulnerabilities = append(result.Vulnerabilities, lsp.DependencyVulnerability{
+				ID:       issue.ID,
+				Title:    title,
+				Severity: issue.Severity.String(),
+			})
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,82 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_GetDependencyTreeCommand_ReturnsAnnotatedTree(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := setupSearchIssuesWorkspace(t, vulnmap.Issue{
+		ID:               "VULNMAP-JS-LODASH-1",
+		AffectedFilePath: filepath.Join(t.TempDir(), "package.json"),
+		Product:          product.ProductOpenSource,
+		Severity:         vulnmap.High,
+		AdditionalData: vulnmap.OssIssueData{
+			Key:   "VULNMAP-JS-LODASH-1",
+			Title: "Prototype Pollution",
+			From:  []string{"app@1.0.0", "lodash@4.17.20"},
+		},
+	})
+	cmd := getDependencyTreeCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.GetDependencyTreeCommand,
+		Arguments: []any{folderPath},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []lsp.DependencyTree{{
+		FolderPath: folderPath,
+		Dependencies: []lsp.DependencyNode{{
+			Package:  "app@1.0.0",
+			Severity: "high",
+			Dependencies: []lsp.DependencyNode{{
+				Package:  "lodash@4.17.20",
+				Severity: "high",
+				Vulnerabilities: []lsp.DependencyVulnerability{
+					{ID: "VULNMAP-JS-LODASH-1", Title: "Prototype Pollution", Severity: "high"},
+				},
+				Dependencies: []lsp.DependencyNode{},
+			}},
+		}},
+	}}, result)
+}
+
+func Test_GetDependencyTreeCommand_UnknownFolderReturnsError(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t)
+	cmd := getDependencyTreeCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.GetDependencyTreeCommand,
+		Arguments: []any{filepath.Join(t.TempDir(), "unknown")},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// getFixAdviceCommand returns the remediation guidance of a single issue. The result is nil if no workspace folder
+// has an issue with the fingerprint.
+// Arguments: the fingerprint of the issue.
+type getFixAdviceCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *getFixAdviceCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *getFixAdviceCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: issue fingerprint")
+	}
+	fingerprint, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("issue fingerprint must be a string")
+	}
+
+	issue, found := workspace.Get().IssueByFingerprint(fingerprint)
+	if !found {
+		return nil, nil
+	}
+	return issue.FixAdvice(), nil
+}
@@ -0,0 +1,84 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_getFixAdviceCommand_ReturnsAdviceOfIssue(t *testing.T) {
+	testutil.UnitTest(t)
+	manifest := filepath.Join(t.TempDir(), "package.json")
+	folderPath := setupSearchIssuesWorkspace(t,
+		vulnmap.Issue{
+			ID:               "VULNMAP-JS-LODASH-1",
+			AffectedFilePath: manifest,
+			Product:          product.ProductOpenSource,
+			AdditionalData: vulnmap.OssIssueData{
+				PackageName:  "lodash",
+				Version:      "4.17.4",
+				From:         []string{"goof@1.0.1", "lodash@4.17.4"},
+				UpgradePath:  []any{false, "lodash@4.17.21"},
+				IsUpgradable: true,
+			},
+		},
+	)
+	issues := workspace.Get().GetFolderContaining(folderPath).AllIssuesFor(manifest)
+	require.Len(t, issues, 1)
+	cmd := getFixAdviceCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.GetFixAdviceCommand,
+		Arguments: []any{issues[0].Fingerprint()},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	advice, ok := result.(vulnmap.FixAdvice)
+	require.True(t, ok)
+	assert.True(t, advice.Fixable)
+	assert.Equal(t, "lodash@4.17.21", advice.UpgradeTo)
+	assert.Equal(t, vulnmap.UpgradeRiskPatch, advice.UpgradeRisk)
+}
+
+func Test_getFixAdviceCommand_UnknownFingerprint(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t)
+	cmd := getFixAdviceCommand{command: vulnmap.CommandData{Arguments: []any{"stale-fingerprint"}}}
+
+	result, err := cmd.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func Test_getFixAdviceCommand_MissingFingerprint(t *testing.T) {
+	cmd := getFixAdviceCommand{command: vulnmap.CommandData{}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
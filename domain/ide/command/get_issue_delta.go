@@ -0,0 +1,101 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// getIssueDeltaCommand returns the issues added and removed by the last scan of each product, e.g. to show
+// "+3 new since last scan" in a status bar.
+// Arguments: optionally a folder path, without it the deltas of all workspace folders are summed up.
+type getIssueDeltaCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *getIssueDeltaCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *getIssueDeltaCommand) Execute(_ context.Context) (any, error) {
+	w := workspace.Get()
+	folders := w.Folders()
+	if args := cmd.command.Arguments; len(args) > 0 {
+		folderPath, ok := args[0].(string)
+		if !ok {
+			return nil, errors.New("folder path must be a string")
+		}
+		folder := w.GetFolderContaining(folderPath)
+		if folder == nil {
+			return nil, errors.Errorf("folder %s is not in the workspace", folderPath)
+		}
+		folders = []*workspace.Folder{folder}
+	}
+
+	deltas := map[product.Product]workspace.IssueDelta{}
+	for _, folder := range folders {
+		for p, delta := range folder.IssueDeltas() {
+			sum := deltas[p]
+			sum.Added = addSeverityCounts(sum.Added, delta.Added)
+			sum.Removed = addSeverityCounts(sum.Removed, delta.Removed)
+			deltas[p] = sum
+		}
+	}
+
+	results := make([]lsp.IssueDelta, 0, len(deltas))
+	for p, delta := range deltas {
+		results = append(results, lsp.IssueDelta{
+			Product: string(p),
+			Added:   toSeverityMap(delta.Added),
+			Removed: toSeverityMap(delta.Removed),
+			Net: toSeverityMap(vulnmap.SeverityCount{
+				Critical: delta.Added.Critical - delta.Removed.Critical,
+				High:     delta.Added.High - delta.Removed.High,
+				Medium:   delta.Added.Medium - delta.Removed.Medium,
+				Low:      delta.Added.Low - delta.Removed.Low,
+			}),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Product < results[j].Product })
+	return results, nil
+}
+
+func addSeverityCounts(a vulnmap.SeverityCount, b vulnmap.SeverityCount) vulnmap.SeverityCount {
+	return vulnmap.SeverityCount{
+		Critical: a.Critical + b.Critical,
+		High:     a.High + b.High,
+		Medium:   a.Medium + b.Medium,
+		Low:      a.Low + b.Low,
+	}
+}
+
+func toSeverityMap(count vulnmap.SeverityCount) map[string]int {
+	return map[string]int{
+		vulnmap.Critical.String(): count.Critical,
+		vulnmap.High.String():     count.High,
+		vulnmap.Medium.String():   count.Medium,
+		vulnmap.Low.String():      count.Low,
+	}
+}
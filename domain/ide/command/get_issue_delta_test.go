@@ -0,0 +1,87 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_GetIssueDeltaCommand_ReturnsDeltaSinceLastScan(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	manifest := filepath.Join(folderPath, "package.json")
+	notifier := notification.NewNotifier()
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(vulnmap.NewTestOssIssue("fixed", manifest, vulnmap.High))
+	scanner.AddTestIssue(vulnmap.NewTestOssIssue("kept", manifest, vulnmap.Low))
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	folder := workspace.NewFolder(folderPath, t.Name(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	workspace.Set(w)
+	w.AddFolder(folder)
+	folder.ScanFolder(context.Background())
+
+	scanner.Issues = []vulnmap.Issue{
+		vulnmap.NewTestOssIssue("kept", manifest, vulnmap.Low),
+		vulnmap.NewTestOssIssue("new-1", manifest, vulnmap.Critical),
+		vulnmap.NewTestOssIssue("new-2", manifest, vulnmap.High),
+		vulnmap.NewTestOssIssue("new-3", manifest, vulnmap.High),
+	}
+	folder.ClearDiagnostics()
+	folder.ScanFolder(context.Background())
+	cmd := getIssueDeltaCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.GetIssueDeltaCommand,
+		Arguments: []any{folderPath},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	deltas, ok := result.([]lsp.IssueDelta)
+	require.True(t, ok)
+	require.Len(t, deltas, 1)
+	assert.Equal(t, string(product.ProductOpenSource), deltas[0].Product)
+	assert.Equal(t, map[string]int{"critical": 1, "high": 2, "medium": 0, "low": 0}, deltas[0].Added)
+	assert.Equal(t, map[string]int{"critical": 0, "high": 1, "medium": 0, "low": 0}, deltas[0].Removed)
+	assert.Equal(t, map[string]int{"critical": 1, "high": 1, "medium": 0, "low": 0}, deltas[0].Net)
+}
+
+func Test_GetIssueDeltaCommand_UnknownFolderReturnsError(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t)
+	cmd := getIssueDeltaCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.GetIssueDeltaCommand,
+		Arguments: []any{filepath.Join(t.TempDir(), "unknown")},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
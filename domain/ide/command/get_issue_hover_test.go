@@ -0,0 +1,86 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_GetIssueHoverCommand_ReturnsHoverOfIssue(t *testing.T) {
+	testutil.UnitTest(t)
+	manifest := filepath.Join(t.TempDir(), "package.json")
+	folderPath := setupSearchIssuesWorkspace(t,
+		vulnmap.Issue{
+			ID:               "VULNMAP-JS-LODASH-1",
+			AffectedFilePath: manifest,
+			Product:          product.ProductOpenSource,
+			Severity:         vulnmap.High,
+			FormattedMessage: "## Prototype Pollution<br>in lodash",
+			AdditionalData:   vulnmap.OssIssueData{PackageName: "lodash", Version: "4.17.4"},
+		},
+		vulnmap.Issue{ID: "VULNMAP-JS-EXPRESS-1", AffectedFilePath: manifest, Product: product.ProductOpenSource},
+	)
+	issues := workspace.Get().GetFolderContaining(folderPath).AllIssuesFor(manifest)
+	require.Len(t, issues, 2)
+	fingerprint := issues[0].Fingerprint()
+	cmd := getIssueHoverCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.GetIssueHoverCommand,
+		Arguments: []any{fingerprint},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	issueHover, ok := result.(lsp.IssueHover)
+	require.True(t, ok)
+	assert.Equal(t, fingerprint, issueHover.Fingerprint)
+	assert.Equal(t, "VULNMAP-JS-LODASH-1", issueHover.IssueId)
+	assert.Equal(t, manifest, issueHover.FilePath)
+	assert.Equal(t, "## Prototype Pollution\n\nin lodash", issueHover.Message)
+}
+
+func Test_GetIssueHoverCommand_UnknownFingerprint(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t,
+		vulnmap.Issue{ID: "VULNMAP-JS-LODASH-1", AffectedFilePath: "package.json", Product: product.ProductOpenSource},
+	)
+	cmd := getIssueHoverCommand{command: vulnmap.CommandData{Arguments: []any{"stale-fingerprint"}}}
+
+	result, err := cmd.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func Test_GetIssueHoverCommand_MissingFingerprint(t *testing.T) {
+	cmd := getIssueHoverCommand{command: vulnmap.CommandData{}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
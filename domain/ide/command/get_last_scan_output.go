@@ -0,0 +1,54 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/cli"
+)
+
+// getLastScanOutputCommand returns the redacted raw output of the last CLI run of a folder, so that users can attach
+// it to bug reports about discrepancies between CLI and IDE results.
+// Arguments: the path of the workspace folder.
+type getLastScanOutputCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *getLastScanOutputCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *getLastScanOutputCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: folder path")
+	}
+	folderPath, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("folder path must be a string")
+	}
+
+	output, found := cli.LastScanOutput(folderPath)
+	if !found {
+		return nil, nil
+	}
+	return output, nil
+}
@@ -37,17 +37,17 @@ func (cmd *getLearnLesson) Command() vulnmap.CommandData {
 	return cmd.command
 }
 
-func (cmd *getLearnLesson) Execute(_ context.Context) (any, error) {
+func (cmd *getLearnLesson) Execute(ctx context.Context) (any, error) {
 	args := cmd.command.Arguments
 	if len(args) < 5 {
 		return nil, errors.New("command is missing arguments. expected: rule, ecosystem, cwes, cves, issueType")
 	}
 
-	lesson, err := learnLesson(args, cmd.learnService)
+	lesson, err := learnLesson(ctx, args, cmd.learnService)
 	return lesson, err
 }
 
-func learnLesson(args []any, learnService learn.Service) (*learn.Lesson, error) {
+func learnLesson(ctx context.Context, args []any, learnService learn.Service) (*learn.Lesson, error) {
 	rule := args[0].(string)
 	ecosystem := args[1].(string)
 	cwes := strings.Split(args[2].(string), ",")
@@ -55,7 +55,7 @@ func learnLesson(args []any, learnService learn.Service) (*learn.Lesson, error)
 	// json numbers are mapped to float64 (https://pkg.go.dev/encoding/json#Unmarshal)
 	issueType := vulnmap.Type(args[4].(float64))
 
-	lesson, err := learnService.GetLesson(ecosystem, rule, cwes, cves, issueType)
+	lesson, err := learnService.GetLesson(ctx, ecosystem, rule, cwes, cves, issueType)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get lesson")
 	}
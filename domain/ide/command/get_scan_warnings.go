@@ -0,0 +1,73 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// getScanWarningsCommand returns the warnings of the last scans, e.g. projects the CLI skipped. Unlike errors,
+// warnings don't fail a scan, so they are otherwise easy to miss.
+// Arguments: optionally a folder path, without it the warnings of all workspace folders are returned.
+type getScanWarningsCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *getScanWarningsCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *getScanWarningsCommand) Execute(_ context.Context) (any, error) {
+	w := workspace.Get()
+	folders := w.Folders()
+	if args := cmd.command.Arguments; len(args) > 0 {
+		folderPath, ok := args[0].(string)
+		if !ok {
+			return nil, errors.New("folder path must be a string")
+		}
+		folder := w.GetFolderContaining(folderPath)
+		if folder == nil {
+			return nil, errors.Errorf("folder %s is not in the workspace", folderPath)
+		}
+		folders = []*workspace.Folder{folder}
+	}
+
+	results := []lsp.ScanWarnings{}
+	for _, folder := range folders {
+		for p, warnings := range folder.ScanWarnings() {
+			results = append(results, lsp.ScanWarnings{
+				FolderPath: folder.Path(),
+				Product:    string(p),
+				Warnings:   warnings,
+			})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].FolderPath != results[j].FolderPath {
+			return results[i].FolderPath < results[j].FolderPath
+		}
+		return results[i].Product < results[j].Product
+	})
+	return results, nil
+}
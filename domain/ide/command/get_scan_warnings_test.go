@@ -0,0 +1,75 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_GetScanWarningsCommand_ReturnsWarningsOfLastScan(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	notifier := notification.NewNotifier()
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(vulnmap.NewTestOssIssue("issue", filepath.Join(folderPath, "package.json"), vulnmap.High))
+	scanner.Warnings = []string{"could not resolve project a"}
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	folder := workspace.NewFolder(folderPath, t.Name(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	workspace.Set(w)
+	w.AddFolder(folder)
+	folder.ScanFolder(context.Background())
+	cmd := getScanWarningsCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.GetScanWarningsCommand,
+		Arguments: []any{folderPath},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []lsp.ScanWarnings{{
+		FolderPath: folderPath,
+		Product:    string(product.ProductOpenSource),
+		Warnings:   []string{"could not resolve project a"},
+	}}, result)
+}
+
+func Test_GetScanWarningsCommand_UnknownFolderReturnsError(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t)
+	cmd := getScanWarningsCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.GetScanWarningsCommand,
+		Arguments: []any{filepath.Join(t.TempDir(), "unknown")},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
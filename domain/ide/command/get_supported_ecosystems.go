@@ -0,0 +1,48 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/learn"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/oss"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// getSupportedEcosystemsCommand returns the package managers of the manifests the language server can scan
+type getSupportedEcosystemsCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *getSupportedEcosystemsCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *getSupportedEcosystemsCommand) Execute(_ context.Context) (any, error) {
+	packageManagers := oss.SupportedPackageManagers()
+	ecosystems := make([]lsp.SupportedEcosystem, 0, len(packageManagers))
+	for _, packageManager := range packageManagers {
+		ecosystems = append(ecosystems, lsp.SupportedEcosystem{
+			PackageManager: packageManager.Name,
+			DisplayName:    packageManager.DisplayName,
+			Ecosystem:      learn.Ecosystem(packageManager.Name),
+		})
+	}
+	return ecosystems, nil
+}
@@ -0,0 +1,50 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_GetSupportedEcosystems_ReturnsKnownPackageManagers(t *testing.T) {
+	testutil.UnitTest(t)
+	cmd := &getSupportedEcosystemsCommand{command: vulnmap.CommandData{CommandId: vulnmap.GetSupportedEcosystemsCommand}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	ecosystems, ok := result.([]lsp.SupportedEcosystem)
+	require.True(t, ok)
+	byPackageManager := map[string]lsp.SupportedEcosystem{}
+	for _, ecosystem := range ecosystems {
+		byPackageManager[ecosystem.PackageManager] = ecosystem
+	}
+	for _, packageManager := range []string{"npm", "pip", "gomodules", "maven"} {
+		assert.Contains(t, byPackageManager, packageManager)
+	}
+	assert.Equal(t, "Go Modules", byPackageManager["gomodules"].DisplayName)
+	assert.Equal(t, "golang", byPackageManager["gomodules"].Ecosystem)
+	assert.Equal(t, "java", byPackageManager["maven"].Ecosystem)
+}
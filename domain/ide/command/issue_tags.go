@@ -0,0 +1,99 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// tagIssueCommand attaches a label to an issue, e.g. "needs-review" or "jira-1234", for custom triage workflows.
+// Arguments: the fingerprint of the issue and the tag. It returns the tags of the issue.
+type tagIssueCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *tagIssueCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *tagIssueCommand) Execute(_ context.Context) (any, error) {
+	fingerprint, tag, err := issueTagArguments(cmd.command.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	return workspace.Get().TagIssue(fingerprint, tag)
+}
+
+// untagIssueCommand removes a label from an issue.
+// Arguments: the fingerprint of the issue and the tag. It returns the remaining tags of the issue.
+type untagIssueCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *untagIssueCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *untagIssueCommand) Execute(_ context.Context) (any, error) {
+	fingerprint, tag, err := issueTagArguments(cmd.command.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	return workspace.Get().UntagIssue(fingerprint, tag)
+}
+
+func issueTagArguments(args []any) (fingerprint string, tag string, err error) {
+	if len(args) < 2 {
+		return "", "", errors.New("command is missing arguments. expected: issue fingerprint, tag")
+	}
+	fingerprint, ok := args[0].(string)
+	if !ok {
+		return "", "", errors.New("issue fingerprint must be a string")
+	}
+	tag, ok = args[1].(string)
+	if !ok {
+		return "", "", errors.New("tag must be a string")
+	}
+	return fingerprint, tag, nil
+}
+
+// getIssueTagsCommand returns the tags of an issue, or of all tagged issues if no fingerprint is given.
+// Arguments: an optional issue fingerprint.
+type getIssueTagsCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *getIssueTagsCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *getIssueTagsCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) == 0 {
+		return workspace.Get().AllIssueTags(), nil
+	}
+	fingerprint, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("issue fingerprint must be a string")
+	}
+	return workspace.Get().IssueTags(fingerprint), nil
+}
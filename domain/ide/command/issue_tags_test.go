@@ -0,0 +1,79 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_issueTagCommands_TagRetrieveAndUntag(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetIssueTagsPath(filepath.Join(t.TempDir(), "issue_tags.json"))
+	manifest := filepath.Join(t.TempDir(), "package.json")
+	folderPath := setupSearchIssuesWorkspace(t,
+		vulnmap.Issue{ID: "VULNMAP-JS-LODASH-1", AffectedFilePath: manifest, Product: product.ProductOpenSource},
+	)
+	folder := workspace.Get().GetFolderContaining(folderPath)
+	fingerprint := folder.AllIssuesFor(manifest)[0].Fingerprint()
+	execute := func(cmd vulnmap.Command) any {
+		t.Helper()
+		result, err := cmd.Execute(context.Background())
+		require.NoError(t, err)
+		return result
+	}
+	getTags := func(args ...any) any {
+		return execute(&getIssueTagsCommand{command: vulnmap.CommandData{CommandId: vulnmap.GetIssueTagsCommand, Arguments: args}})
+	}
+
+	tagged := execute(&tagIssueCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.TagIssueCommand,
+		Arguments: []any{fingerprint, "needs-review"},
+	}})
+
+	expected := lsp.IssueTags{Fingerprint: fingerprint, Tags: []string{"needs-review"}}
+	assert.Equal(t, expected, tagged)
+	assert.Equal(t, expected, getTags(fingerprint))
+	assert.Equal(t, []lsp.IssueTags{expected}, getTags())
+	assert.Equal(t, []string{"needs-review"}, folder.FilteredIssues()[0].Tags)
+
+	untagged := execute(&untagIssueCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.UntagIssueCommand,
+		Arguments: []any{fingerprint, "needs-review"},
+	}})
+
+	assert.Empty(t, untagged.(lsp.IssueTags).Tags)
+	assert.Empty(t, getTags())
+}
+
+func Test_tagIssueCommand_RequiresTag(t *testing.T) {
+	cmd := tagIssueCommand{command: vulnmap.CommandData{Arguments: []any{"fingerprint"}}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
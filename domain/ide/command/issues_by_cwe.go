@@ -0,0 +1,47 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// issuesByCWECommand groups the issues of all workspace folders that are visible with the current filters by CWE
+type issuesByCWECommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *issuesByCWECommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *issuesByCWECommand) Execute(_ context.Context) (any, error) {
+	groups := workspace.Get().IssuesByCWE()
+	results := make([]lsp.CWEIssueGroup, 0, len(groups))
+	for _, group := range groups {
+		issues := make([]lsp.IssueSearchResult, 0, len(group.Issues))
+		for _, issue := range group.Issues {
+			issues = append(issues, toIssueSearchResult(issue, 0))
+		}
+		results = append(results, lsp.CWEIssueGroup{CWE: group.CWE, Count: len(issues), Issues: issues})
+	}
+	return results, nil
+}
@@ -0,0 +1,70 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_IssuesByCWECommand_GroupsIssuesByCWE(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetVulnmapCodeEnabled(true)
+	file := filepath.Join(t.TempDir(), "app.js")
+	setupSearchIssuesWorkspace(t,
+		vulnmap.Issue{
+			ID:               "javascript/DOMXSS",
+			AffectedFilePath: file,
+			Product:          product.ProductCode,
+			Severity:         vulnmap.High,
+			CWEs:             []string{"CWE-79"},
+			AdditionalData:   vulnmap.CodeIssueData{Title: "Cross-site Scripting"},
+		},
+		vulnmap.Issue{
+			ID:               "javascript/XSS",
+			AffectedFilePath: file,
+			Product:          product.ProductCode,
+			Severity:         vulnmap.Medium,
+			CWEs:             []string{"CWE-79", "CWE-116"},
+			AdditionalData:   vulnmap.CodeIssueData{Title: "Improper Encoding"},
+		},
+	)
+	cmd := issuesByCWECommand{command: vulnmap.CommandData{CommandId: vulnmap.IssuesByCWECommand}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	groups, ok := result.([]lsp.CWEIssueGroup)
+	require.True(t, ok)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "CWE-79", groups[0].CWE)
+	assert.Equal(t, 2, groups[0].Count)
+	assert.Len(t, groups[0].Issues, 2)
+	assert.Equal(t, "CWE-116", groups[1].CWE)
+	assert.Equal(t, 1, groups[1].Count)
+	assert.Equal(t, "javascript/XSS", groups[1].Issues[0].Id)
+	assert.Equal(t, "Improper Encoding", groups[1].Issues[0].Title)
+}
@@ -0,0 +1,48 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// issuesByEcosystemCommand counts the issues of all workspace folders that are visible with the current filters by
+// package ecosystem, e.g. npm or maven
+type issuesByEcosystemCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *issuesByEcosystemCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *issuesByEcosystemCommand) Execute(_ context.Context) (any, error) {
+	counts := workspace.Get().IssuesByEcosystem()
+	results := make([]lsp.EcosystemIssueCount, 0, len(counts))
+	for _, count := range counts {
+		results = append(results, lsp.EcosystemIssueCount{
+			Ecosystem:  count.Ecosystem,
+			Count:      count.Count,
+			Severities: toSeverityMap(count.SeverityCount),
+		})
+	}
+	return results, nil
+}
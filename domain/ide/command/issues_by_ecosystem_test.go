@@ -0,0 +1,63 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_IssuesByEcosystemCommand_CountsIssuesPerEcosystem(t *testing.T) {
+	testutil.UnitTest(t)
+	dir := t.TempDir()
+	newIssue := func(id string, file string, severity vulnmap.Severity, ecosystem string) vulnmap.Issue {
+		return vulnmap.Issue{
+			ID:               id,
+			AffectedFilePath: filepath.Join(dir, file),
+			Product:          product.ProductOpenSource,
+			Severity:         severity,
+			Ecosystem:        ecosystem,
+		}
+	}
+	setupSearchIssuesWorkspace(t,
+		newIssue("VULNMAP-JS-LODASH-1", "package.json", vulnmap.High, "npm"),
+		newIssue("VULNMAP-JS-MINIMIST-2", "package.json", vulnmap.Critical, "npm"),
+		newIssue("VULNMAP-JAVA-LOG4J-3", "pom.xml", vulnmap.Critical, "maven"),
+	)
+	cmd := issuesByEcosystemCommand{command: vulnmap.CommandData{CommandId: vulnmap.IssuesByEcosystemCommand}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	counts, ok := result.([]lsp.EcosystemIssueCount)
+	require.True(t, ok)
+	require.Len(t, counts, 2)
+	assert.Equal(t, "npm", counts[0].Ecosystem)
+	assert.Equal(t, 2, counts[0].Count)
+	assert.Equal(t, map[string]int{"critical": 1, "high": 1, "medium": 0, "low": 0}, counts[0].Severities)
+	assert.Equal(t, "maven", counts[1].Ecosystem)
+	assert.Equal(t, 1, counts[1].Count)
+}
@@ -37,13 +37,13 @@ func (cmd *openLearnLesson) Command() vulnmap.CommandData {
 	return cmd.command
 }
 
-func (cmd *openLearnLesson) Execute(_ context.Context) (any, error) {
+func (cmd *openLearnLesson) Execute(ctx context.Context) (any, error) {
 	args := cmd.command.Arguments
 	if len(args) < 5 {
 		return nil, errors.New("command is missing arguments. expected: rule, ecosystem, cwes, cves, issueType")
 	}
 
-	lesson, err := learnLesson(args, cmd.learnService)
+	lesson, err := learnLesson(ctx, args, cmd.learnService)
 
 	if cmd.openBrowserHandleFunc != nil {
 		cmd.openBrowserHandleFunc(lesson.Url)
@@ -54,7 +54,7 @@ func Test_openLearnLesson_Execute(t *testing.T) {
 	expectedLessonURL := "https://lessonURL"
 	expectedLesson := &learn.Lesson{Url: expectedLessonURL}
 	mockService.EXPECT().
-		GetLesson(eco, rule, []string{"CWE-89", "CWE-ZZ"}, []string{"CVE-2020-1234"}, vulnmap.DependencyVulnerability).
+		GetLesson(gomock.Any(), eco, rule, []string{"CWE-89", "CWE-ZZ"}, []string{"CVE-2020-1234"}, vulnmap.DependencyVulnerability).
 		Return(expectedLesson, nil)
 
 	_, err := cut.Execute(context.Background())
@@ -0,0 +1,44 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// openLogsCommand returns where the client can find the language server logs: the log file path if logging to a
+// file, otherwise the most recent log lines, so that users can attach them to bug reports. The recent log lines are
+// also returned if the log file couldn't be opened.
+type openLogsCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *openLogsCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *openLogsCommand) Execute(_ context.Context) (any, error) {
+	c := config.CurrentConfig()
+	if c.IsLoggingToFile() {
+		return lsp.LogsResult{LogPath: c.LogPath()}, nil
+	}
+	return lsp.LogsResult{RecentLines: c.RecentLogLines()}, nil
+}
@@ -0,0 +1,79 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_openLogsCommand_ReturnsConfiguredLogPath(t *testing.T) {
+	c := testutil.UnitTest(t)
+	logPath := filepath.Join(t.TempDir(), "vulnmap-ls.log")
+	c.SetLogPath(logPath)
+	c.ConfigureLogging(nil)
+	t.Cleanup(c.DisableLoggingToFile)
+	cmd := &openLogsCommand{command: vulnmap.CommandData{CommandId: vulnmap.OpenLogsCommand}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, lsp.LogsResult{LogPath: logPath}, result)
+}
+
+func Test_openLogsCommand_ReturnsRecentLogLinesWithoutLogFile(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetLogPath("")
+	c.ConfigureLogging(nil)
+	log.Info().Msg("a recent log line")
+	cmd := &openLogsCommand{command: vulnmap.CommandData{CommandId: vulnmap.OpenLogsCommand}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	logs, ok := result.(lsp.LogsResult)
+	require.True(t, ok)
+	assert.Empty(t, logs.LogPath)
+	require.NotEmpty(t, logs.RecentLines)
+	assert.Contains(t, logs.RecentLines[len(logs.RecentLines)-1], "a recent log line")
+}
+
+func Test_openLogsCommand_ReturnsRecentLogLinesWhenLogFileCannotBeOpened(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetLogPath(filepath.Join(t.TempDir(), "missing-dir", "vulnmap-ls.log"))
+	c.ConfigureLogging(nil)
+	log.Info().Msg("a recent log line")
+	cmd := &openLogsCommand{command: vulnmap.CommandData{CommandId: vulnmap.OpenLogsCommand}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	logs, ok := result.(lsp.LogsResult)
+	require.True(t, ok)
+	assert.Empty(t, logs.LogPath)
+	require.NotEmpty(t, logs.RecentLines)
+	assert.Contains(t, logs.RecentLines[len(logs.RecentLines)-1], "a recent log line")
+}
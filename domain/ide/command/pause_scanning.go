@@ -0,0 +1,52 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// pauseScanningCommand skips all scans until scanning is resumed, the cached issues stay visible
+type pauseScanningCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *pauseScanningCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *pauseScanningCommand) Execute(_ context.Context) (any, error) {
+	workspace.Get().PauseScanning()
+	return nil, nil
+}
+
+// resumeScanningCommand resumes scanning and scans the folders once that requested scans while scanning was
+// paused. It returns the number of folders that are scanned.
+type resumeScanningCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *resumeScanningCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *resumeScanningCommand) Execute(ctx context.Context) (any, error) {
+	return workspace.Get().ResumeScanning(ctx), nil
+}
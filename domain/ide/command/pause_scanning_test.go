@@ -0,0 +1,50 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_PauseAndResumeScanningCommands(t *testing.T) {
+	testutil.UnitTest(t)
+	scanner := vulnmap.NewTestScanner()
+	folder := setupTriggerScanWorkspace(t, scanner, t.TempDir())[0]
+	pause := pauseScanningCommand{command: vulnmap.CommandData{CommandId: vulnmap.PauseScanningCommand}}
+	resume := resumeScanningCommand{command: vulnmap.CommandData{CommandId: vulnmap.ResumeScanningCommand}}
+
+	_, err := pause.Execute(context.Background())
+	require.NoError(t, err)
+	folder.ScanFolder(context.Background())
+	assert.True(t, workspace.Get().IsScanningPaused())
+	assert.Equal(t, 0, scanner.Calls())
+
+	result, err := resume.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+	assert.Eventually(t, func() bool { return scanner.Calls() == 1 }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,135 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// previewFilterCommand previews which issues a filter change would hide or show, without changing the config or
+// republishing diagnostics.
+// Arguments: optional list of visible severities (e.g. ["critical", "high"]), optional list of visible issue types
+// (e.g. ["Open Source", "Code Security"]) and optional minimum CVSS score (e.g. 7). Omitted or null arguments keep
+// the current setting.
+type previewFilterCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *previewFilterCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *previewFilterCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	previewed := workspace.PreviewedFilter{}
+	if len(args) > 0 && args[0] != nil {
+		severityFilter, err := toSeverityFilter(args[0])
+		if err != nil {
+			return nil, err
+		}
+		previewed.SeverityFilter = &severityFilter
+	}
+	if len(args) > 1 && args[1] != nil {
+		issueTypes, err := toIssueTypes(args[1])
+		if err != nil {
+			return nil, err
+		}
+		previewed.IssueTypes = issueTypes
+	}
+	if len(args) > 2 && args[2] != nil {
+		minCvssScore, ok := args[2].(float64)
+		if !ok || minCvssScore < 0 || minCvssScore > 10 {
+			return nil, errors.New("min CVSS score must be a number between 0 and 10")
+		}
+		previewed.MinCvssScore = minCvssScore
+	}
+
+	preview := workspace.Get().PreviewFilter(previewed)
+	return lsp.FilterPreviewResult{
+		HiddenCount: len(preview.Hidden),
+		ShownCount:  len(preview.Shown),
+		Hidden:      toFilterPreviewIssues(preview.Hidden),
+		Shown:       toFilterPreviewIssues(preview.Shown),
+	}, nil
+}
+
+func toSeverityFilter(arg any) (lsp.SeverityFilter, error) {
+	values, ok := arg.([]any)
+	if !ok {
+		return lsp.SeverityFilter{}, errors.New("severities must be a list of severities")
+	}
+	severityFilter := lsp.SeverityFilter{}
+	for _, value := range values {
+		name, isString := value.(string)
+		if !isString {
+			return lsp.SeverityFilter{}, errors.New("severities must be a list of severities")
+		}
+		severity, err := toSeverity(name)
+		if err != nil {
+			return lsp.SeverityFilter{}, err
+		}
+		switch severity {
+		case vulnmap.Critical:
+			severityFilter.Critical = true
+		case vulnmap.High:
+			severityFilter.High = true
+		case vulnmap.Medium:
+			severityFilter.Medium = true
+		case vulnmap.Low:
+			severityFilter.Low = true
+		}
+	}
+	return severityFilter, nil
+}
+
+func toIssueTypes(arg any) (map[product.FilterableIssueType]bool, error) {
+	values, ok := arg.([]any)
+	if !ok {
+		return nil, errors.New("issue types must be a list of issue types")
+	}
+	issueTypes := map[product.FilterableIssueType]bool{}
+	for _, value := range values {
+		name, isString := value.(string)
+		if !isString {
+			return nil, errors.New("issue types must be a list of issue types")
+		}
+		issueType := product.FilterableIssueType(name)
+		switch issueType {
+		case product.FilterableIssueTypeOpenSource, product.FilterableIssueTypeCodeSecurity,
+			product.FilterableIssueTypeCodeQuality, product.FilterableIssueTypeInfrastructureAsCode:
+			issueTypes[issueType] = true
+		default:
+			return nil, errors.Errorf("unknown issue type %s", name)
+		}
+	}
+	return issueTypes, nil
+}
+
+func toFilterPreviewIssues(issues []vulnmap.Issue) []lsp.IssueSearchResult {
+	results := make([]lsp.IssueSearchResult, 0, len(issues))
+	for _, issue := range issues {
+		results = append(results, toIssueSearchResult(issue, 0))
+	}
+	return results
+}
@@ -0,0 +1,78 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_PreviewFilterCommand_CountsMatchApplyingTheFilter(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetSeverityFilter(lsp.NewSeverityFilter(true, true, false, false))
+	manifest := filepath.Join(t.TempDir(), "package.json")
+	folderPath := setupSearchIssuesWorkspace(t,
+		vulnmap.NewTestOssIssue("critical", manifest, vulnmap.Critical),
+		vulnmap.NewTestOssIssue("high", manifest, vulnmap.High),
+		vulnmap.NewTestOssIssue("medium", manifest, vulnmap.Medium),
+	)
+	folder := workspace.Get().GetFolderContaining(folderPath)
+	visibleBefore := len(folder.FilteredIssues())
+	cmd := previewFilterCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.PreviewFilterCommand,
+		Arguments: []any{[]any{"critical", "medium"}},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	preview, ok := result.(lsp.FilterPreviewResult)
+	require.True(t, ok)
+	assert.Equal(t, 1, preview.HiddenCount)
+	assert.Equal(t, "high", preview.Hidden[0].Id)
+	assert.Equal(t, 1, preview.ShownCount)
+	assert.Equal(t, "medium", preview.Shown[0].Id)
+	assert.Equal(t, lsp.NewSeverityFilter(true, true, false, false), c.FilterSeverity())
+
+	c.SetSeverityFilter(lsp.NewSeverityFilter(true, false, true, false))
+	assert.Len(t, folder.FilteredIssues(), visibleBefore-preview.HiddenCount+preview.ShownCount)
+}
+
+func Test_PreviewFilterCommand_InvalidArguments(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t)
+
+	for _, args := range [][]any{
+		{"critical"},
+		{[]any{"severe"}},
+		{nil, []any{"Secrets"}},
+		{nil, nil, "high"},
+		{nil, nil, 11.0},
+	} {
+		_, err := (&previewFilterCommand{command: vulnmap.CommandData{Arguments: args}}).Execute(context.Background())
+		assert.Error(t, err, "arguments %v", args)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// rescanFileCommand scans a single file without using cached results, e.g. after a fix was applied, and
+// republishes only the diagnostics of that file.
+// Arguments: the path of the file.
+type rescanFileCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *rescanFileCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *rescanFileCommand) Execute(ctx context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: file path")
+	}
+	filePath, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("file path must be a string")
+	}
+	folder := workspace.Get().GetFolderContaining(filePath)
+	if folder == nil {
+		return nil, errors.Errorf("file %s is not part of the workspace", filePath)
+	}
+	folder.RescanFile(ctx, filePath)
+	return nil, nil
+}
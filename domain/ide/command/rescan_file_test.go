@@ -0,0 +1,73 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_RescanFileCommand_RescansFileInContainingFolder(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	lockfilePath := filepath.Join(folderPath, "package-lock.json")
+	testutil.CreateFileOrFail(t, lockfilePath, []byte("{}"))
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewNotifier()
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	folder := workspace.NewFolder(folderPath, "test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(folder)
+	workspace.Set(w)
+	scanner.AddTestIssue(vulnmap.Issue{ID: "VULNMAP-JS-LODASH-567746", AffectedFilePath: lockfilePath})
+	cmd := rescanFileCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.RescanFileCommand,
+		Arguments: []any{lockfilePath},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, scanner.Calls())
+	assert.Len(t, folder.DocumentDiagnosticsFromCache(lockfilePath), 1)
+}
+
+func Test_RescanFileCommand_FileOutsideWorkspaceReturnsError(t *testing.T) {
+	testutil.UnitTest(t)
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewNotifier()
+	workspace.Set(workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier))
+	cmd := rescanFileCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.RescanFileCommand,
+		Arguments: []any{filepath.Join(t.TempDir(), "package.json")},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, scanner.Calls())
+}
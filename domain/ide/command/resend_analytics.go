@@ -0,0 +1,76 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+const (
+	resendAnalyticsMaxAttempts  = 3
+	resendAnalyticsRetryBackoff = 500 * time.Millisecond
+)
+
+// resendAnalyticsCommand re-attempts sending the scan done events of all workspace folders whose sending failed,
+// e.g. because the network was down. It returns the number of resent events.
+type resendAnalyticsCommand struct {
+	command vulnmap.CommandData
+	// retryBackoff is the wait time before the first retry, it doubles with every retry
+	retryBackoff time.Duration
+}
+
+func (cmd *resendAnalyticsCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *resendAnalyticsCommand) Execute(ctx context.Context) (any, error) {
+	if !config.CurrentConfig().IsAnalyticsEnabled() {
+		log.Debug().Str("method", "resendAnalyticsCommand.Execute").Msg("analytics disabled, not resending")
+		return 0, nil
+	}
+
+	resent := 0
+	backoff := cmd.retryBackoff
+	for attempt := 1; ; attempt++ {
+		var firstErr error
+		for _, folder := range workspace.Get().Folders() {
+			folderResent, err := folder.ResendFailedAnalytics()
+			resent += folderResent
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		if firstErr == nil || attempt == resendAnalyticsMaxAttempts {
+			return resent, firstErr
+		}
+		log.Debug().Err(firstErr).Str("method", "resendAnalyticsCommand.Execute").Int("attempt", attempt).
+			Msg("failed to resend analytics, retrying")
+		select {
+		case <-ctx.Done():
+			return resent, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	localworkflows "github.com/khulnasoft-lab/go-application-framework/pkg/local_workflows"
+	"github.com/khulnasoft-lab/go-application-framework/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_ResendAnalyticsCommand_RetriesUntilFailedEventIsSent(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAnalyticsEnabled(true)
+	engineMock := mocks.NewMockEngine(gomock.NewController(t))
+	engineMock.EXPECT().GetConfiguration().AnyTimes().Return(c.Engine().GetConfiguration())
+	c.SetEngine(engineMock)
+	networkErr := errors.New("network down")
+	gomock.InOrder(
+		// scan and first resend attempt fail, the retry succeeds
+		engineMock.EXPECT().InvokeWithInputAndConfig(localworkflows.WORKFLOWID_REPORT_ANALYTICS, gomock.Any(), gomock.Any()).
+			Times(2).Return(nil, networkErr),
+		engineMock.EXPECT().InvokeWithInputAndConfig(localworkflows.WORKFLOWID_REPORT_ANALYTICS, gomock.Any(), gomock.Any()).
+			Times(1).Return(nil, nil),
+	)
+	setupSearchIssuesWorkspace(t, vulnmap.Issue{
+		ID:               "VULNMAP-JS-LODASH-1",
+		AffectedFilePath: filepath.Join(t.TempDir(), "package.json"),
+		Product:          product.ProductOpenSource,
+		Severity:         vulnmap.High,
+	})
+	cmd := resendAnalyticsCommand{command: vulnmap.CommandData{CommandId: vulnmap.ResendAnalyticsCommand}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+	for _, folder := range workspace.Get().Folders() {
+		assert.Empty(t, folder.FailedAnalyticsProducts())
+	}
+}
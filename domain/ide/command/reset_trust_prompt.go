@@ -0,0 +1,55 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// resetTrustPromptCommand asks the user again to trust a workspace folder, e.g. after they dismissed the prompt and
+// changed their mind. Without the reset, the prompt is only shown once per session.
+// Arguments: the path of the workspace folder.
+type resetTrustPromptCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *resetTrustPromptCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *resetTrustPromptCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: folder path")
+	}
+	path, ok := args[0].(string)
+	if !ok || path == "" {
+		return nil, errors.New("folder path must be a non-empty string")
+	}
+
+	folder := workspace.Get().GetFolderContaining(path)
+	if folder == nil {
+		return nil, errors.Errorf("folder %s is not in the workspace", path)
+	}
+	folder.ResetTrustPrompt()
+	return nil, nil
+}
@@ -0,0 +1,242 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// scanAndGateCommand scans the given workspace folders and fails if the visible issues exceed the thresholds.
+// Arguments: folder paths and optionally the thresholds, e.g. {"critical": 0, "high": 10} or {"failOnAnyCritical": true}.
+// A threshold is the maximum number of allowed issues of a severity, severities without threshold are not gated.
+// The gate never passes if a folder couldn't be scanned completely, an error is returned instead.
+type scanAndGateCommand struct {
+	command     vulnmap.CommandData
+	authService vulnmap.AuthenticationService
+}
+
+// gateThresholds are the maximum allowed issue counts per severity, nil means unlimited
+type gateThresholds struct {
+	Critical *int
+	High     *int
+	Medium   *int
+	Low      *int
+}
+
+func (cmd *scanAndGateCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *scanAndGateCommand) Execute(ctx context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: folders, [thresholds]")
+	}
+	folderPaths, err := toStrings(args[0])
+	if err != nil {
+		return nil, err
+	}
+	thresholds := gateThresholds{}
+	if len(args) > 1 {
+		thresholds, err = parseGateThresholds(args[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	authenticated, err := cmd.authService.IsAuthenticated()
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't check authentication, not gating")
+	}
+	if !authenticated {
+		return nil, errors.New("not authenticated, the folders can't be scanned")
+	}
+
+	w := workspace.Get()
+	var counts vulnmap.SeverityCount
+	for _, folderPath := range folderPaths {
+		folder := w.GetFolderContaining(folderPath)
+		if folder == nil {
+			return nil, errors.Errorf("folder %s is not in the workspace", folderPath)
+		}
+		if err = scanFolderForGate(ctx, folder); err != nil {
+			return nil, err
+		}
+		folderCounts := folder.FilteredSeverityCount()
+		counts.Critical += folderCounts.Critical
+		counts.High += folderCounts.High
+		counts.Medium += folderCounts.Medium
+		counts.Low += folderCounts.Low
+	}
+
+	result := evaluateGate(counts, thresholds)
+	log.Info().Str("method", "scanAndGateCommand.Execute").Msg(result.Summary)
+	return result, nil
+}
+
+// scanFolderForGate scans the folder and returns an error if the scan didn't run or didn't finish for all products
+func scanFolderForGate(ctx context.Context, folder *workspace.Folder) error {
+	if !folder.IsTrusted() {
+		return errors.Errorf("folder %s is not trusted", folder.Path())
+	}
+	if folder.IsStopped() {
+		return errors.Errorf("scans of folder %s are stopped", folder.Path())
+	}
+
+	folder.ClearScannedStatus()
+	folder.ScanFolder(ctx)
+
+	if ctx.Err() != nil {
+		return errors.Wrapf(ctx.Err(), "scan of folder %s was cancelled", folder.Path())
+	}
+	if folder.IsStopped() {
+		return errors.Errorf("scan of folder %s was stopped", folder.Path())
+	}
+	if folder.Status() == workspace.ScanErrored {
+		return errors.Errorf("scan of folder %s was aborted", folder.Path())
+	}
+	outcome := folder.LastScanOutcome()
+	if len(outcome.Failed) > 0 {
+		failures := make([]string, 0, len(outcome.Failed))
+		for p, scanErr := range outcome.Failed {
+			failures = append(failures, fmt.Sprintf("%s: %v", p, scanErr))
+		}
+		sort.Strings(failures)
+		return errors.Errorf("scan of folder %s failed (%s)", folder.Path(), strings.Join(failures, ", "))
+	}
+	if len(outcome.Succeeded) == 0 {
+		return errors.Errorf("folder %s was not scanned", folder.Path())
+	}
+	return nil
+}
+
+func evaluateGate(counts vulnmap.SeverityCount, thresholds gateThresholds) lsp.ScanGateResult {
+	result := lsp.ScanGateResult{
+		Passed: true,
+		Counts: map[string]int{
+			vulnmap.Critical.String(): counts.Critical,
+			vulnmap.High.String():     counts.High,
+			vulnmap.Medium.String():   counts.Medium,
+			vulnmap.Low.String():      counts.Low,
+		},
+	}
+
+	severities := []struct {
+		severity  vulnmap.Severity
+		count     int
+		threshold *int
+	}{
+		{vulnmap.Critical, counts.Critical, thresholds.Critical},
+		{vulnmap.High, counts.High, thresholds.High},
+		{vulnmap.Medium, counts.Medium, thresholds.Medium},
+		{vulnmap.Low, counts.Low, thresholds.Low},
+	}
+	summaries := make([]string, 0, len(severities))
+	for _, s := range severities {
+		if s.threshold == nil {
+			summaries = append(summaries, fmt.Sprintf("%s: %d", s.severity, s.count))
+			continue
+		}
+		summaries = append(summaries, fmt.Sprintf("%s: %d (max %d)", s.severity, s.count, *s.threshold))
+		if s.count > *s.threshold {
+			result.Passed = false
+			result.Violations = append(result.Violations,
+				fmt.Sprintf("%d %s severity issues exceed the threshold of %d", s.count, s.severity, *s.threshold))
+		}
+	}
+
+	status := "PASSED"
+	if !result.Passed {
+		status = "FAILED"
+		result.ExitCode = 1
+	}
+	result.Summary = fmt.Sprintf("Vulnmap scan gate %s - %s", status, strings.Join(summaries, ", "))
+	return result
+}
+
+func parseGateThresholds(arg any) (gateThresholds, error) {
+	thresholds := gateThresholds{}
+	if arg == nil {
+		return thresholds, nil
+	}
+	values, ok := arg.(map[string]any)
+	if !ok {
+		return thresholds, errors.New("thresholds must be an object")
+	}
+
+	for key, value := range values {
+		if key == "failOnAnyCritical" {
+			failOnAnyCritical, isBool := value.(bool)
+			if !isBool {
+				return thresholds, errors.New("failOnAnyCritical must be a boolean")
+			}
+			if failOnAnyCritical {
+				zero := 0
+				thresholds.Critical = &zero
+			}
+			continue
+		}
+
+		// json numbers are mapped to float64 (https://pkg.go.dev/encoding/json#Unmarshal)
+		number, isNumber := value.(float64)
+		if !isNumber || number < 0 {
+			return thresholds, errors.Errorf("threshold %s must be a non-negative number", key)
+		}
+		threshold := int(number)
+		switch key {
+		case vulnmap.Critical.String():
+			if thresholds.Critical == nil {
+				thresholds.Critical = &threshold
+			}
+		case vulnmap.High.String():
+			thresholds.High = &threshold
+		case vulnmap.Medium.String():
+			thresholds.Medium = &threshold
+		case vulnmap.Low.String():
+			thresholds.Low = &threshold
+		default:
+			return thresholds, errors.Errorf("unknown threshold %s", key)
+		}
+	}
+	return thresholds, nil
+}
+
+func toStrings(arg any) ([]string, error) {
+	values, ok := arg.([]any)
+	if !ok {
+		return nil, errors.New("folders must be a list of paths")
+	}
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		s, isString := value.(string)
+		if !isString {
+			return nil, errors.New("folders must be a list of paths")
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
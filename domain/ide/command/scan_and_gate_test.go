@@ -0,0 +1,226 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/ux"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_evaluateGate_ThresholdBoundaries(t *testing.T) {
+	threshold := 2
+	thresholds := gateThresholds{High: &threshold}
+
+	tests := []struct {
+		name       string
+		highCount  int
+		shouldPass bool
+	}{
+		{name: "below threshold", highCount: 1, shouldPass: true},
+		{name: "at threshold", highCount: 2, shouldPass: true},
+		{name: "above threshold", highCount: 3, shouldPass: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := evaluateGate(vulnmap.SeverityCount{High: test.highCount, Low: 100}, thresholds)
+
+			assert.Equal(t, test.shouldPass, result.Passed)
+			if test.shouldPass {
+				assert.Equal(t, 0, result.ExitCode)
+				assert.Empty(t, result.Violations)
+			} else {
+				assert.Equal(t, 1, result.ExitCode)
+				assert.Len(t, result.Violations, 1)
+			}
+		})
+	}
+}
+
+func Test_parseGateThresholds_FailOnAnyCritical(t *testing.T) {
+	thresholds, err := parseGateThresholds(map[string]any{"failOnAnyCritical": true, "critical": float64(5), "high": float64(3)})
+	require.NoError(t, err)
+
+	assert.False(t, evaluateGate(vulnmap.SeverityCount{Critical: 1}, thresholds).Passed)
+	assert.True(t, evaluateGate(vulnmap.SeverityCount{High: 3}, thresholds).Passed)
+	assert.False(t, evaluateGate(vulnmap.SeverityCount{High: 4}, thresholds).Passed)
+}
+
+func Test_parseGateThresholds_InvalidThresholds(t *testing.T) {
+	_, err := parseGateThresholds(map[string]any{"severe": float64(1)})
+	assert.Error(t, err)
+
+	_, err = parseGateThresholds(map[string]any{"high": float64(-1)})
+	assert.Error(t, err)
+}
+
+// newScanAndGateCommand returns a gate command for the folders with an authenticated user
+func newScanAndGateCommand(t *testing.T, args ...any) *scanAndGateCommand {
+	t.Helper()
+	config.CurrentConfig().SetToken("token")
+	provider := vulnmap.NewFakeCliAuthenticationProvider()
+	provider.IsAuthenticated = true
+	authService := vulnmap.NewAuthenticationService(provider, ux.NewTestAnalytics(),
+		error_reporting.NewTestErrorReporter(), notification.NewNotifier())
+	return &scanAndGateCommand{
+		command:     vulnmap.CommandData{CommandId: vulnmap.ScanAndGateCommand, Arguments: args},
+		authService: authService,
+	}
+}
+
+// errorScanner reports an error for every scan
+type errorScanner struct {
+	vulnmap.TestScanner
+}
+
+func (s *errorScanner) Scan(_ context.Context, _ string, processResults vulnmap.ScanResultProcessor, _ string) {
+	processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Err: errors.New("cli failed")})
+}
+
+// noopScanner reports no results, like a scanner whose products are all skipped
+type noopScanner struct {
+	vulnmap.TestScanner
+}
+
+func (s *noopScanner) Scan(context.Context, string, vulnmap.ScanResultProcessor, string) {}
+
+func setupGateFolder(t *testing.T, scanner vulnmap.Scanner) string {
+	t.Helper()
+	notifier := notification.NewNotifier()
+	folderPath := t.TempDir()
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	workspace.Set(w)
+	w.AddFolder(workspace.NewFolder(folderPath, t.Name(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier))
+	return folderPath
+}
+
+func Test_ScanAndGateCommand_ScansFoldersAndGates(t *testing.T) {
+	testutil.UnitTest(t)
+	manifest := "package.json"
+	folderPath := setupSearchIssuesWorkspace(t,
+		vulnmap.Issue{ID: "critical", AffectedFilePath: manifest, Product: product.ProductOpenSource, Severity: vulnmap.Critical},
+		vulnmap.Issue{ID: "high", AffectedFilePath: manifest, Product: product.ProductOpenSource, Severity: vulnmap.High},
+	)
+	cmd := newScanAndGateCommand(t, []any{folderPath}, map[string]any{"failOnAnyCritical": true})
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	gateResult, ok := result.(lsp.ScanGateResult)
+	require.True(t, ok)
+	assert.False(t, gateResult.Passed)
+	assert.Equal(t, 1, gateResult.ExitCode)
+	assert.Equal(t, 1, gateResult.Counts["critical"])
+	assert.Equal(t, 1, gateResult.Counts["high"])
+	assert.Contains(t, gateResult.Summary, "FAILED")
+}
+
+func Test_ScanAndGateCommand_UnknownFolder(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t)
+	cmd := newScanAndGateCommand(t, []any{"/not/in/workspace"})
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
+
+func Test_ScanAndGateCommand_FailsIfNotAuthenticated(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := setupSearchIssuesWorkspace(t)
+	cmd := newScanAndGateCommand(t, []any{folderPath})
+	config.CurrentConfig().SetToken("")
+
+	result, err := cmd.Execute(context.Background())
+
+	assert.ErrorContains(t, err, "not authenticated")
+	assert.Nil(t, result)
+}
+
+func Test_ScanAndGateCommand_FailsIfFolderIsUntrusted(t *testing.T) {
+	c := testutil.UnitTest(t)
+	folderPath := setupSearchIssuesWorkspace(t)
+	c.SetTrustedFolderFeatureEnabled(true)
+	cmd := newScanAndGateCommand(t, []any{folderPath})
+
+	result, err := cmd.Execute(context.Background())
+
+	assert.ErrorContains(t, err, "not trusted")
+	assert.Nil(t, result)
+}
+
+func Test_ScanAndGateCommand_FailsIfFolderIsStopped(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := setupSearchIssuesWorkspace(t)
+	workspace.Get().GetFolderContaining(folderPath).StopScans()
+	cmd := newScanAndGateCommand(t, []any{folderPath})
+
+	result, err := cmd.Execute(context.Background())
+
+	assert.ErrorContains(t, err, "stopped")
+	assert.Nil(t, result)
+}
+
+func Test_ScanAndGateCommand_FailsIfScanReturnsError(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := setupGateFolder(t, &errorScanner{})
+	cmd := newScanAndGateCommand(t, []any{folderPath})
+
+	result, err := cmd.Execute(context.Background())
+
+	assert.ErrorContains(t, err, "cli failed")
+	assert.Nil(t, result)
+}
+
+func Test_ScanAndGateCommand_FailsIfNothingWasScanned(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := setupGateFolder(t, &noopScanner{})
+	cmd := newScanAndGateCommand(t, []any{folderPath})
+
+	result, err := cmd.Execute(context.Background())
+
+	assert.ErrorContains(t, err, "was not scanned")
+	assert.Nil(t, result)
+}
+
+func Test_ScanAndGateCommand_FailsIfCancelled(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := setupSearchIssuesWorkspace(t)
+	cmd := newScanAndGateCommand(t, []any{folderPath})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := cmd.Execute(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, result)
+}
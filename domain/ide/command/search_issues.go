@@ -0,0 +1,145 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/converter"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// sort orders of the search results
+const (
+	sortByRelevance = "relevance"
+	sortByRecency   = "recency"
+)
+
+// searchIssuesCommand searches the issues of all workspace folders.
+// Arguments: query, optional product (e.g. "Vulnmap Open Source"), optional minimum severity (e.g. "high"), optional
+// date since which the advisories were updated (e.g. "2024-01-31" or an RFC 3339 timestamp) and optional sort order
+// ("relevance" or "recency").
+type searchIssuesCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *searchIssuesCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *searchIssuesCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: query, [product], [minSeverity], [updatedSince], [sortBy]")
+	}
+	query, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("query must be a string")
+	}
+
+	filter := workspace.IssueSearchFilter{}
+	if len(args) > 1 {
+		productArg, _ := args[1].(string)
+		filter.Product = product.Product(productArg)
+	}
+	if len(args) > 2 {
+		severityArg, _ := args[2].(string)
+		if severityArg != "" {
+			severity, err := toSeverity(severityArg)
+			if err != nil {
+				return nil, err
+			}
+			filter.MinSeverity = &severity
+		}
+	}
+	if len(args) > 3 {
+		updatedSinceArg, _ := args[3].(string)
+		if updatedSinceArg != "" {
+			updatedSince, err := parseUpdatedSince(updatedSinceArg)
+			if err != nil {
+				return nil, err
+			}
+			filter.UpdatedSince = &updatedSince
+		}
+	}
+	if len(args) > 4 {
+		sortByArg, _ := args[4].(string)
+		switch sortByArg {
+		case "", sortByRelevance:
+		case sortByRecency:
+			filter.SortByRecency = true
+		default:
+			return nil, errors.Errorf("unknown sort order %s", sortByArg)
+		}
+	}
+
+	searchResults := workspace.Get().SearchIssues(query, filter)
+	results := make([]lsp.IssueSearchResult, 0, len(searchResults))
+	for _, searchResult := range searchResults {
+		results = append(results, toIssueSearchResult(searchResult.Issue, searchResult.Score))
+	}
+	return results, nil
+}
+
+func toIssueSearchResult(issue vulnmap.Issue, score int) lsp.IssueSearchResult {
+	result := lsp.IssueSearchResult{
+		Id:       issue.ID,
+		Title:    workspace.IssueTitle(issue),
+		Severity: issue.Severity.String(),
+		Product:  string(issue.Product),
+		FilePath: issue.AffectedFilePath,
+		Range:    converter.ToRange(issue.Range),
+		CVEs:     issue.CVEs,
+		CWEs:     issue.CWEs,
+		Score:    score,
+	}
+	if data, isOss := issue.AdditionalData.(vulnmap.OssIssueData); isOss {
+		result.PackageName = data.PackageName
+	}
+	if updated, ok := issue.AdvisoryUpdated(); ok {
+		result.AdvisoryUpdated = updated.UTC().Format(time.RFC3339)
+	}
+	return result
+}
+
+// parseUpdatedSince accepts a date or an RFC 3339 timestamp
+func parseUpdatedSince(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid date %s, expected e.g. 2024-01-31", value)
+	}
+	return timestamp, nil
+}
+
+func toSeverity(severity string) (vulnmap.Severity, error) {
+	for _, s := range []vulnmap.Severity{vulnmap.Critical, vulnmap.High, vulnmap.Medium, vulnmap.Low} {
+		if s.String() == strings.ToLower(severity) {
+			return s, nil
+		}
+	}
+	return vulnmap.Unknown, errors.Errorf("unknown severity %s", severity)
+}
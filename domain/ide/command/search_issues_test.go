@@ -0,0 +1,153 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func setupSearchIssuesWorkspace(t *testing.T, issues ...vulnmap.Issue) string {
+	t.Helper()
+	notifier := notification.NewNotifier()
+	scanner := vulnmap.NewTestScanner()
+	for _, issue := range issues {
+		scanner.AddTestIssue(issue)
+	}
+	folderPath := t.TempDir()
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	folder := workspace.NewFolder(folderPath, t.Name(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	workspace.Set(w)
+	w.AddFolder(folder)
+	folder.ScanFolder(context.Background())
+	return folderPath
+}
+
+func Test_SearchIssuesCommand_ReturnsMatchingIssues(t *testing.T) {
+	testutil.UnitTest(t)
+	manifest := filepath.Join(t.TempDir(), "package.json")
+	setupSearchIssuesWorkspace(t,
+		vulnmap.Issue{
+			ID:               "VULNMAP-JS-LODASH-1",
+			AffectedFilePath: manifest,
+			Product:          product.ProductOpenSource,
+			Severity:         vulnmap.High,
+			CVEs:             []string{"CVE-2021-23337"},
+			AdditionalData:   vulnmap.OssIssueData{Title: "Command Injection", PackageName: "lodash"},
+		},
+		vulnmap.Issue{
+			ID:               "VULNMAP-JS-EXPRESS-1",
+			AffectedFilePath: manifest,
+			Product:          product.ProductOpenSource,
+			Severity:         vulnmap.Low,
+			AdditionalData:   vulnmap.OssIssueData{Title: "Open Redirect", PackageName: "express"},
+		},
+	)
+	cmd := searchIssuesCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.SearchIssuesCommand,
+		Arguments: []any{"2021-2333", string(product.ProductOpenSource), "high"},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	results, ok := result.([]lsp.IssueSearchResult)
+	require.True(t, ok)
+	require.Len(t, results, 1)
+	assert.Equal(t, "VULNMAP-JS-LODASH-1", results[0].Id)
+	assert.Equal(t, "Command Injection", results[0].Title)
+	assert.Equal(t, "high", results[0].Severity)
+	assert.Equal(t, "lodash", results[0].PackageName)
+	assert.Equal(t, manifest, results[0].FilePath)
+}
+
+func Test_SearchIssuesCommand_InvalidArguments(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t)
+
+	_, err := (&searchIssuesCommand{command: vulnmap.CommandData{Arguments: []any{}}}).Execute(context.Background())
+	assert.Error(t, err)
+
+	_, err = (&searchIssuesCommand{command: vulnmap.CommandData{Arguments: []any{"query", "", "severe"}}}).Execute(context.Background())
+	assert.Error(t, err)
+
+	_, err = (&searchIssuesCommand{command: vulnmap.CommandData{Arguments: []any{"query", "", "", "last week"}}}).Execute(context.Background())
+	assert.Error(t, err)
+
+	_, err = (&searchIssuesCommand{command: vulnmap.CommandData{Arguments: []any{"query", "", "", "", "age"}}}).Execute(context.Background())
+	assert.Error(t, err)
+}
+
+func Test_SearchIssuesCommand_ReturnsRecentlyUpdatedAdvisoriesFirst(t *testing.T) {
+	testutil.UnitTest(t)
+	manifest := filepath.Join(t.TempDir(), "package.json")
+	disclosed := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	modified := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	old := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	setupSearchIssuesWorkspace(t,
+		vulnmap.Issue{
+			ID:               "VULNMAP-JS-LODASH-1",
+			AffectedFilePath: manifest,
+			Product:          product.ProductOpenSource,
+			Severity:         vulnmap.High,
+			AdditionalData:   vulnmap.OssIssueData{Key: "1", PackageName: "lodash", DisclosureTime: &disclosed},
+		},
+		vulnmap.Issue{
+			ID:               "VULNMAP-JS-EXPRESS-1",
+			AffectedFilePath: manifest,
+			Product:          product.ProductOpenSource,
+			Severity:         vulnmap.Low,
+			AdditionalData:   vulnmap.OssIssueData{Key: "2", PackageName: "express", DisclosureTime: &old, ModificationTime: &modified},
+		},
+		vulnmap.Issue{
+			ID:               "VULNMAP-JS-MINIMIST-1",
+			AffectedFilePath: manifest,
+			Product:          product.ProductOpenSource,
+			Severity:         vulnmap.Critical,
+			AdditionalData:   vulnmap.OssIssueData{Key: "3", PackageName: "minimist", DisclosureTime: &old},
+		},
+	)
+	cmd := searchIssuesCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.SearchIssuesCommand,
+		Arguments: []any{"", "", "", "2024-01-01", "recency"},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	results, ok := result.([]lsp.IssueSearchResult)
+	require.True(t, ok)
+	require.Len(t, results, 2)
+	assert.Equal(t, "VULNMAP-JS-EXPRESS-1", results[0].Id)
+	assert.Equal(t, "2024-03-05T12:00:00Z", results[0].AdvisoryUpdated)
+	assert.Equal(t, "VULNMAP-JS-LODASH-1", results[1].Id)
+	assert.Equal(t, "2024-01-10T00:00:00Z", results[1].AdvisoryUpdated)
+}
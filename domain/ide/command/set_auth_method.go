@@ -0,0 +1,65 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// AuthenticationProviderFactory creates the authentication provider of an authentication method
+type AuthenticationProviderFactory func(method lsp.AuthenticationMethod) vulnmap.AuthenticationProvider
+
+// setAuthMethodCommand switches the authentication method without a restart, e.g. to token authentication after
+// OAuth failed. The authentication of the previous method is cleared, the next login uses the new method.
+// Arguments: the authentication method ("oauth" or "token", token authentication is done by the CLI).
+type setAuthMethodCommand struct {
+	command             vulnmap.CommandData
+	authService         vulnmap.AuthenticationService
+	authProviderFactory AuthenticationProviderFactory
+}
+
+func (cmd *setAuthMethodCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *setAuthMethodCommand) Execute(ctx context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: authentication method")
+	}
+	name, _ := args[0].(string)
+	method := lsp.AuthenticationMethod(name)
+	if method != lsp.OAuthAuthentication && method != lsp.TokenAuthentication {
+		return nil, errors.Errorf("unknown authentication method %v", args[0])
+	}
+	if cmd.authProviderFactory == nil {
+		return nil, errors.New("switching the authentication method is not supported")
+	}
+
+	log.Info().Str("method", "setAuthMethodCommand.Execute").Msgf("switching authentication method to %s", method)
+	cmd.authService.Logout(ctx)
+	config.CurrentConfig().SetAuthenticationMethod(method)
+	cmd.authService.SetProvider(cmd.authProviderFactory(method))
+	return nil, nil
+}
@@ -0,0 +1,108 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/ux"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_SetAuthMethodCommand_ClearsOldAuthenticationAndUsesNewProvider(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAuthenticationMethod(lsp.OAuthAuthentication)
+	c.SetToken("old-token")
+	oldProvider := vulnmap.NewFakeCliAuthenticationProvider()
+	oldProvider.IsAuthenticated = true
+	newProvider := vulnmap.NewFakeCliAuthenticationProvider()
+	var requestedMethod lsp.AuthenticationMethod
+	authService := vulnmap.NewAuthenticationService(oldProvider, ux.NewTestAnalytics(),
+		error_reporting.NewTestErrorReporter(), notification.NewNotifier())
+	cmd := setAuthMethodCommand{
+		command:     vulnmap.CommandData{CommandId: vulnmap.SetAuthMethodCommand, Arguments: []any{"token"}},
+		authService: authService,
+		authProviderFactory: func(method lsp.AuthenticationMethod) vulnmap.AuthenticationProvider {
+			requestedMethod = method
+			return newProvider
+		},
+	}
+
+	_, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, oldProvider.IsAuthenticated)
+	assert.Empty(t, c.Token())
+	assert.Equal(t, lsp.TokenAuthentication, c.AuthenticationMethod())
+	assert.Equal(t, lsp.TokenAuthentication, requestedMethod)
+	assert.Same(t, newProvider, authService.Provider())
+
+	token, err := authService.Authenticate(context.Background())
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.True(t, newProvider.IsAuthenticated)
+	assert.False(t, oldProvider.IsAuthenticated)
+}
+
+func Test_SetAuthMethodCommand_UnknownMethodKeepsProvider(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAuthenticationMethod(lsp.OAuthAuthentication)
+	provider := vulnmap.NewFakeCliAuthenticationProvider()
+	provider.IsAuthenticated = true
+	authService := vulnmap.NewAuthenticationService(provider, ux.NewTestAnalytics(),
+		error_reporting.NewTestErrorReporter(), notification.NewNotifier())
+	cmd := setAuthMethodCommand{
+		command:     vulnmap.CommandData{CommandId: vulnmap.SetAuthMethodCommand, Arguments: []any{"password"}},
+		authService: authService,
+		authProviderFactory: func(method lsp.AuthenticationMethod) vulnmap.AuthenticationProvider {
+			return vulnmap.NewFakeCliAuthenticationProvider()
+		},
+	}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, provider.IsAuthenticated)
+	assert.Same(t, provider, authService.Provider())
+	assert.Equal(t, lsp.OAuthAuthentication, c.AuthenticationMethod())
+}
+
+func Test_SetAuthMethodCommand_UsesFactoryOfCommandService(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAuthenticationMethod(lsp.OAuthAuthentication)
+	newProvider := vulnmap.NewFakeCliAuthenticationProvider()
+	authService := vulnmap.NewAuthenticationService(vulnmap.NewFakeCliAuthenticationProvider(), ux.NewTestAnalytics(),
+		error_reporting.NewTestErrorReporter(), notification.NewNotifier())
+	service := NewService(authService, nil, nil, nil, nil, func(method lsp.AuthenticationMethod) vulnmap.AuthenticationProvider {
+		return newProvider
+	})
+
+	_, err := service.ExecuteCommandData(context.Background(),
+		vulnmap.CommandData{CommandId: vulnmap.SetAuthMethodCommand, Arguments: []any{"token"}}, nil)
+
+	require.NoError(t, err)
+	assert.Same(t, newProvider, authService.Provider())
+}
@@ -0,0 +1,144 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// triggerScanCommand starts scans of a workspace folder, or of all trusted folders, and returns a ticket for them.
+// The progress and results of the scans are reported with the usual scan notifications, which contain the scan id of
+// the ticket.
+// Arguments: the folder path or "" for all folders, optionally the product codenames to scan, e.g. ["oss", "code"],
+// and a force refresh flag that discards the cached results of the folders before scanning.
+type triggerScanCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *triggerScanCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *triggerScanCommand) Execute(ctx context.Context) (any, error) {
+	args := cmd.command.Arguments
+	folderPath := ""
+	if len(args) > 0 && args[0] != nil {
+		var ok bool
+		folderPath, ok = args[0].(string)
+		if !ok {
+			return nil, errors.New("folder path must be a string")
+		}
+	}
+	var products []product.Product
+	var err error
+	if len(args) > 1 && args[1] != nil {
+		products, err = toProducts(args[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	forceRefresh := false
+	if len(args) > 2 && args[2] != nil {
+		var ok bool
+		forceRefresh, ok = args[2].(bool)
+		if !ok {
+			return nil, errors.New("force refresh must be a boolean")
+		}
+	}
+
+	folders, err := foldersToScan(folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket := lsp.ScanTicket{ScanId: uuid.NewString(), FolderPaths: []string{}, ForceRefresh: forceRefresh}
+	for _, p := range products {
+		ticket.Products = append(ticket.Products, product.ToProductCodename(p))
+	}
+	scanCtx := vulnmap.ContextWithScanId(ctx, ticket.ScanId)
+	if len(products) > 0 {
+		scanCtx = vulnmap.ContextWithProducts(scanCtx, products)
+	}
+	for _, folder := range folders {
+		if forceRefresh {
+			clearCachedResults(folder, products)
+		}
+		folder.ClearScannedStatus()
+		ticket.FolderPaths = append(ticket.FolderPaths, folder.Path())
+		go folder.ScanFolder(scanCtx)
+	}
+	log.Info().Str("method", "triggerScanCommand.Execute").Interface("ticket", ticket).Msg("scans triggered")
+	return ticket, nil
+}
+
+// clearCachedResults discards the cached results of the products in the folder, or of all products if none are given
+func clearCachedResults(folder *workspace.Folder, products []product.Product) {
+	if len(products) == 0 {
+		folder.ClearDiagnostics()
+		return
+	}
+	var issueTypes []product.FilterableIssueType
+	for _, p := range products {
+		issueTypes = append(issueTypes, product.ToFilterableIssueTypes(p)...)
+	}
+	folder.ClearDiagnosticsByIssueTypes(issueTypes...)
+}
+
+func foldersToScan(folderPath string) ([]*workspace.Folder, error) {
+	w := workspace.Get()
+	if folderPath == "" {
+		trusted, _ := w.GetFolderTrust()
+		return trusted, nil
+	}
+	folder := w.GetFolderContaining(folderPath)
+	if folder == nil {
+		return nil, errors.Errorf("folder %s is not in the workspace", folderPath)
+	}
+	if !folder.IsTrusted() {
+		return nil, errors.Errorf("folder %s is not trusted", folderPath)
+	}
+	return []*workspace.Folder{folder}, nil
+}
+
+func toProducts(arg any) ([]product.Product, error) {
+	values, ok := arg.([]any)
+	if !ok {
+		return nil, errors.New("products must be a list of product codenames")
+	}
+	products := make([]product.Product, 0, len(values))
+	for _, value := range values {
+		codename, isString := value.(string)
+		if !isString {
+			return nil, errors.New("products must be a list of product codenames")
+		}
+		p := product.FromProductCodename(codename)
+		if p == product.ProductUnknown {
+			return nil, errors.Errorf("unknown product %s, expected one of oss, code, iac", codename)
+		}
+		products = append(products, p)
+	}
+	return products, nil
+}
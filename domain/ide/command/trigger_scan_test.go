@@ -0,0 +1,210 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+// productRecordingScanner records the products each scan was restricted to
+type productRecordingScanner struct {
+	*vulnmap.TestScanner
+	mutex    sync.Mutex
+	products [][]product.Product
+}
+
+func (s *productRecordingScanner) Scan(ctx context.Context, path string, processResults vulnmap.ScanResultProcessor, folderPath string) {
+	products, _ := vulnmap.RequestedProducts(ctx)
+	s.mutex.Lock()
+	s.products = append(s.products, products)
+	s.mutex.Unlock()
+	s.TestScanner.Scan(ctx, path, processResults, folderPath)
+}
+
+func (s *productRecordingScanner) requestedProducts() [][]product.Product {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.products
+}
+
+func setupTriggerScanWorkspace(t *testing.T, scanner vulnmap.Scanner, folderPaths ...string) []*workspace.Folder {
+	t.Helper()
+	notifier := notification.NewNotifier()
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	workspace.Set(w)
+	folders := make([]*workspace.Folder, 0, len(folderPaths))
+	for _, folderPath := range folderPaths {
+		folder := workspace.NewFolder(folderPath, folderPath, scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+		w.AddFolder(folder)
+		folders = append(folders, folder)
+	}
+	return folders
+}
+
+func Test_TriggerScanCommand_ScansFolderWithRequestedProducts(t *testing.T) {
+	testutil.UnitTest(t)
+	scanner := &productRecordingScanner{TestScanner: vulnmap.NewTestScanner()}
+	folderPath, otherPath := t.TempDir(), t.TempDir()
+	folders := setupTriggerScanWorkspace(t, scanner, folderPath, otherPath)
+	cmd := triggerScanCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.TriggerScanCommand,
+		Arguments: []any{folderPath, []any{"oss", "code"}},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	ticket, ok := result.(lsp.ScanTicket)
+	require.True(t, ok)
+	assert.NotEmpty(t, ticket.ScanId)
+	assert.Equal(t, []string{folderPath}, ticket.FolderPaths)
+	assert.Equal(t, []string{"oss", "code"}, ticket.Products)
+	assert.False(t, ticket.ForceRefresh)
+	assert.Eventually(t, folders[0].IsScanned, time.Second, time.Millisecond)
+	assert.False(t, folders[1].IsScanned())
+	assert.Equal(t, [][]product.Product{{product.ProductOpenSource, product.ProductCode}}, scanner.requestedProducts())
+}
+
+func Test_TriggerScanCommand_NotifiesScanStatusWithScanId(t *testing.T) {
+	testutil.UnitTest(t)
+	scanner := vulnmap.NewTestScanner()
+	scanNotifier := vulnmap.NewMockScanNotifier()
+	notifier := notification.NewNotifier()
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), scanNotifier, notifier)
+	workspace.Set(w)
+	folder := workspace.NewFolder(t.TempDir(), "folder", scanner, hover.NewFakeHoverService(), scanNotifier, notifier)
+	w.AddFolder(folder)
+	cmd := triggerScanCommand{command: vulnmap.CommandData{CommandId: vulnmap.TriggerScanCommand, Arguments: []any{""}}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	ticket := result.(lsp.ScanTicket)
+	assert.Eventually(t, func() bool { return len(scanNotifier.SuccessCalls()) > 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{ticket.ScanId}, scanNotifier.ScanIds())
+	assert.Equal(t, ticket.ScanId, folder.LastScans()[0].ScanId)
+}
+
+func Test_TriggerScanCommand_ScansAllFoldersWithoutFolderPath(t *testing.T) {
+	testutil.UnitTest(t)
+	scanner := &productRecordingScanner{TestScanner: vulnmap.NewTestScanner()}
+	folders := setupTriggerScanWorkspace(t, scanner, t.TempDir(), t.TempDir())
+	cmd := triggerScanCommand{command: vulnmap.CommandData{CommandId: vulnmap.TriggerScanCommand, Arguments: []any{""}}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	ticket := result.(lsp.ScanTicket)
+	assert.ElementsMatch(t, []string{folders[0].Path(), folders[1].Path()}, ticket.FolderPaths)
+	assert.Empty(t, ticket.Products)
+	assert.Eventually(t, func() bool { return folders[0].IsScanned() && folders[1].IsScanned() }, time.Second, time.Millisecond)
+	assert.Equal(t, [][]product.Product{nil, nil}, scanner.requestedProducts())
+}
+
+func Test_TriggerScanCommand_ForceRefreshDiscardsCachedResults(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	manifest := filepath.Join(folderPath, "package.json")
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(vulnmap.Issue{ID: "fixed", AffectedFilePath: manifest, Product: product.ProductOpenSource, Severity: vulnmap.High})
+	folder := setupTriggerScanWorkspace(t, scanner, folderPath)[0]
+	folder.ScanFolder(context.Background())
+	require.Len(t, folder.DocumentDiagnosticsFromCache(manifest), 1)
+	scanner.Issues = []vulnmap.Issue{}
+
+	t.Run("without force refresh cached results are kept", func(t *testing.T) {
+		cmd := triggerScanCommand{command: vulnmap.CommandData{Arguments: []any{folderPath, nil, false}}}
+
+		_, err := cmd.Execute(context.Background())
+
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool { return scanner.Calls() == 2 && folder.IsScanned() }, time.Second, time.Millisecond)
+		assert.Len(t, folder.DocumentDiagnosticsFromCache(manifest), 1)
+	})
+
+	t.Run("with force refresh cached results are discarded", func(t *testing.T) {
+		cmd := triggerScanCommand{command: vulnmap.CommandData{Arguments: []any{folderPath, nil, true}}}
+
+		result, err := cmd.Execute(context.Background())
+
+		require.NoError(t, err)
+		assert.True(t, result.(lsp.ScanTicket).ForceRefresh)
+		assert.Eventually(t, func() bool { return scanner.Calls() == 3 && folder.IsScanned() }, time.Second, time.Millisecond)
+		assert.Empty(t, folder.DocumentDiagnosticsFromCache(manifest))
+	})
+}
+
+func Test_TriggerScanCommand_ForceRefreshOfProductsKeepsTheResultsOfOtherProducts(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	manifest := filepath.Join(folderPath, "package.json")
+	source := filepath.Join(folderPath, "app.js")
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(vulnmap.Issue{ID: "oss", AffectedFilePath: manifest, Product: product.ProductOpenSource, Severity: vulnmap.High})
+	scanner.AddTestIssue(vulnmap.Issue{ID: "code", AffectedFilePath: source, Product: product.ProductCode, Severity: vulnmap.High})
+	folder := setupTriggerScanWorkspace(t, scanner, folderPath)[0]
+	folder.ScanFolder(context.Background())
+	scanner.Issues = []vulnmap.Issue{}
+	cmd := triggerScanCommand{command: vulnmap.CommandData{Arguments: []any{folderPath, []any{"code"}, true}}}
+
+	_, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return scanner.Calls() == 2 && folder.IsScanned() }, time.Second, time.Millisecond)
+	assert.Len(t, folder.DocumentDiagnosticsFromCache(manifest), 1)
+	assert.Empty(t, folder.DocumentDiagnosticsFromCache(source))
+}
+
+func Test_TriggerScanCommand_InvalidArguments(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	setupTriggerScanWorkspace(t, vulnmap.NewTestScanner(), folderPath)
+
+	tests := []struct {
+		name string
+		args []any
+	}{
+		{name: "unknown folder", args: []any{"/not/in/workspace"}},
+		{name: "unknown product", args: []any{folderPath, []any{"secrets"}}},
+		{name: "invalid force refresh", args: []any{folderPath, nil, "yes"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := triggerScanCommand{command: vulnmap.CommandData{Arguments: test.args}}
+
+			_, err := cmd.Execute(context.Background())
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// trustFolderCommand adds a workspace folder to the trusted folders and scans it, e.g. when the user accepts the
+// prompt to trust an untrusted folder.
+// Arguments: the path of the workspace folder.
+type trustFolderCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *trustFolderCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *trustFolderCommand) Execute(ctx context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: folder path")
+	}
+	path, ok := args[0].(string)
+	if !ok || path == "" {
+		return nil, errors.New("folder path must be a non-empty string")
+	}
+
+	w := workspace.Get()
+	folder := w.GetFolderContaining(path)
+	if folder == nil {
+		return nil, errors.Errorf("folder %s is not in the workspace", path)
+	}
+	if folder.IsTrusted() {
+		go folder.ScanFolder(ctx)
+		return nil, nil
+	}
+	w.TrustFoldersAndScan(ctx, []*workspace.Folder{folder})
+	return nil, nil
+}
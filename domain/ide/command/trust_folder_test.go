@@ -0,0 +1,105 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_TrustFolderCommand_TrustsFolderAndTriggersRescan(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(true)
+	folderPath := t.TempDir()
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewMockNotifier()
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	folder := workspace.NewFolder(folderPath, "test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(folder)
+	workspace.Set(w)
+	folder.ScanFolder(context.Background())
+	require.Equal(t, 0, scanner.Calls())
+	cmd := trustFolderCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.TrustFolderCommand,
+		Arguments: []any{folderPath},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Contains(t, c.TrustedFolders(), folderPath)
+	assert.Eventually(t, func() bool {
+		return scanner.Calls() == 1
+	}, time.Second, time.Millisecond, "folder should be scanned after trust is granted")
+}
+
+func Test_TrustFolderCommand_ScansFolderWhosePromptWasDismissed(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(true)
+	c.SetUntrustedFolderBehavior(config.UntrustedFolderBehaviorPrompt)
+	folderPath := t.TempDir()
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewMockNotifier()
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	folder := workspace.NewFolder(folderPath, "test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(folder)
+	workspace.Set(w)
+	folder.ScanFolder(context.Background())
+	folder.ScanFolder(context.Background())
+	require.Equal(t, 1, trustPrompts(notifier), "the dismissed prompt isn't repeated")
+	cmd := trustFolderCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.TrustFolderCommand,
+		Arguments: []any{folderPath},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return scanner.Calls() == 1
+	}, time.Second, time.Millisecond, "the previously untrusted folder should be scanned")
+
+	// revoking the trust again prompts again
+	c.SetTrustedFolders(nil)
+	folder.ScanFolder(context.Background())
+	assert.Equal(t, 2, trustPrompts(notifier))
+}
+
+func Test_TrustFolderCommand_UnknownFolderReturnsError(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t)
+	cmd := trustFolderCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.TrustFolderCommand,
+		Arguments: []any{t.TempDir()},
+	}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
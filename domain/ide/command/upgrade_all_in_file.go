@@ -0,0 +1,74 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/converter"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/oss"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// upgradeAllInFileCommand returns a workspace edit that applies all upgrades of the open source issues of a manifest.
+// Arguments: the path of the manifest.
+type upgradeAllInFileCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *upgradeAllInFileCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *upgradeAllInFileCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: file path")
+	}
+	filePath, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("file path must be a string")
+	}
+	folder := workspace.Get().GetFolderContaining(filePath)
+	if folder == nil {
+		return nil, errors.Errorf("file %s is not part of the workspace", filePath)
+	}
+	fileContent, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read manifest")
+	}
+
+	plan := oss.PlanUpgradesInFile(filePath, fileContent, folder.AllIssuesFor(filePath))
+
+	result := lsp.UpgradeAllResult{
+		Edit:     converter.ToWorkspaceEdit(plan.Edit),
+		Upgrades: []lsp.PackageUpgrade{},
+		Skipped:  []lsp.SkippedUpgrade{},
+	}
+	for _, upgrade := range plan.Upgrades {
+		result.Upgrades = append(result.Upgrades, lsp.PackageUpgrade(upgrade))
+	}
+	for _, skipped := range plan.Skipped {
+		result.Skipped = append(result.Skipped, lsp.SkippedUpgrade(skipped))
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+func Test_UpgradeAllInFileCommand_ReturnsEditForUpgradableDependencies(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	manifest := filepath.Join(folderPath, "package.json")
+	require.NoError(t, os.WriteFile(manifest, []byte(`{
+  "dependencies": {
+    "lodash": "^4.17.4",
+    "express": "4.12.4"
+  }
+}`), 0600))
+	notifier := notification.NewNotifier()
+	scanner := vulnmap.NewTestScanner()
+	for _, dependency := range []struct{ name, upgrade string }{
+		{name: "lodash", upgrade: "lodash@4.17.21"},
+		{name: "express", upgrade: "express@4.19.2"},
+	} {
+		scanner.AddTestIssue(vulnmap.Issue{
+			ID:               "VULNMAP-JS-" + dependency.name,
+			AffectedFilePath: manifest,
+			Product:          product.ProductOpenSource,
+			Severity:         vulnmap.High,
+			AdditionalData: vulnmap.OssIssueData{
+				PackageName:  dependency.name,
+				IsUpgradable: true,
+				UpgradePath:  []any{false, dependency.upgrade},
+			},
+		})
+	}
+	w := workspace.New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	folder := workspace.NewFolder(folderPath, t.Name(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	workspace.Set(w)
+	w.AddFolder(folder)
+	folder.ScanFolder(context.Background())
+	cmd := upgradeAllInFileCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.UpgradeAllInFileCommand,
+		Arguments: []any{manifest},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	upgradeResult, ok := result.(lsp.UpgradeAllResult)
+	require.True(t, ok)
+	require.NotNil(t, upgradeResult.Edit)
+	edits := upgradeResult.Edit.Changes[string(uri.PathToUri(manifest))]
+	require.Len(t, edits, 2)
+	assert.Equal(t, "4.19.2", edits[0].NewText)
+	assert.Equal(t, "^4.17.21", edits[1].NewText)
+	assert.Len(t, upgradeResult.Upgrades, 2)
+	assert.Empty(t, upgradeResult.Skipped)
+}
+
+func Test_UpgradeAllInFileCommand_FileOutsideWorkspace(t *testing.T) {
+	testutil.UnitTest(t)
+	setupSearchIssuesWorkspace(t)
+	cmd := upgradeAllInFileCommand{command: vulnmap.CommandData{Arguments: []any{"/not/in/workspace/package.json"}}}
+
+	_, err := cmd.Execute(context.Background())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/converter"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/oss"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// validateManifestCommand reports structural problems of a manifest or lock file without scanning it, so that users
+// can tell a broken file from a file without vulnerabilities.
+// Arguments: the path of the manifest.
+type validateManifestCommand struct {
+	command vulnmap.CommandData
+}
+
+func (cmd *validateManifestCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *validateManifestCommand) Execute(_ context.Context) (any, error) {
+	args := cmd.command.Arguments
+	if len(args) < 1 {
+		return nil, errors.New("command is missing arguments. expected: file path")
+	}
+	filePath, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("file path must be a string")
+	}
+
+	warnings, err := oss.ValidateManifest(filePath)
+	if err != nil {
+		return nil, err
+	}
+	result := lsp.ManifestValidationResult{FilePath: filePath, Warnings: []lsp.ManifestWarning{}}
+	for _, warning := range warnings {
+		result.Warnings = append(result.Warnings, lsp.ManifestWarning{
+			Message: warning.Message,
+			Range:   converter.ToRange(warning.Range),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_ValidateManifestCommand_MalformedPackageJson(t *testing.T) {
+	testutil.UnitTest(t)
+	manifestPath := filepath.Join(t.TempDir(), "package.json")
+	testutil.CreateFileOrFail(t, manifestPath, []byte(`{"dependencies": {"lodash": }`))
+	cmd := validateManifestCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.ValidateManifestCommand,
+		Arguments: []any{manifestPath},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	validation, ok := result.(lsp.ManifestValidationResult)
+	require.True(t, ok)
+	assert.Equal(t, manifestPath, validation.FilePath)
+	require.Len(t, validation.Warnings, 1)
+	assert.Contains(t, validation.Warnings[0].Message, "not valid JSON")
+}
+
+func Test_ValidateManifestCommand_ValidPackageJson(t *testing.T) {
+	testutil.UnitTest(t)
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "package.json")
+	testutil.CreateFileOrFail(t, manifestPath, []byte(`{"dependencies": {"lodash": "^4.17.4"}}`))
+	testutil.CreateFileOrFail(t, filepath.Join(dir, "package-lock.json"),
+		[]byte(`{"lockfileVersion": 3, "packages": {"": {"dependencies": {"lodash": "^4.17.4"}}}}`))
+	cmd := validateManifestCommand{command: vulnmap.CommandData{
+		CommandId: vulnmap.ValidateManifestCommand,
+		Arguments: []any{manifestPath},
+	}}
+
+	result, err := cmd.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, lsp.ManifestValidationResult{FilePath: manifestPath, Warnings: []lsp.ManifestWarning{}}, result)
+}
@@ -0,0 +1,131 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/workspace"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/learn"
+)
+
+// warmLearnCacheCommand looks up the learn lessons of the issues of the open workspace folders in the background,
+// so that the learn service has them cached when the code actions of an issue are requested.
+// Arguments: optional folder path, if omitted all workspace folders are warmed.
+type warmLearnCacheCommand struct {
+	command      vulnmap.CommandData
+	learnService learn.Service
+}
+
+func (cmd *warmLearnCacheCommand) Command() vulnmap.CommandData {
+	return cmd.command
+}
+
+func (cmd *warmLearnCacheCommand) Execute(ctx context.Context) (any, error) {
+	c := config.CurrentConfig()
+	if !c.IsVulnmapLearnCodeActionsEnabled() {
+		c.Logger().Debug().Str("method", "warmLearnCacheCommand.Execute").Msg("learn code actions are disabled")
+		return nil, nil
+	}
+
+	var folderPath string
+	if args := cmd.command.Arguments; len(args) > 0 {
+		path, ok := args[0].(string)
+		if !ok {
+			return nil, errors.New("folder path must be a string")
+		}
+		folderPath = path
+	}
+
+	var issues []vulnmap.Issue
+	for _, folder := range workspace.Get().Folders() {
+		if folderPath == "" || folder.Path() == folderPath {
+			issues = append(issues, folder.FilteredIssues()...)
+		}
+	}
+
+	// the lookups continue after the command returned, so they must not be cancelled with the request
+	go warmLearnCache(context.WithoutCancel(ctx), cmd.learnService, issues, c.LearnLessonLookupConcurrency())
+	return nil, nil
+}
+
+// learnLookupKey identifies a lesson lookup. The learn service only considers the first CWE and CVE of an issue.
+type learnLookupKey struct {
+	ecosystem string
+	rule      string
+	cwe       string
+	cve       string
+	issueType vulnmap.Type
+}
+
+func newLearnLookupKey(issue vulnmap.Issue) learnLookupKey {
+	key := learnLookupKey{ecosystem: issue.Ecosystem, rule: issue.ID, issueType: issue.IssueType}
+	if len(issue.CWEs) > 0 {
+		key.cwe = issue.CWEs[0]
+	}
+	if len(issue.CVEs) > 0 {
+		key.cve = issue.CVEs[0]
+	}
+	return key
+}
+
+// warmLearnCache looks up the lesson of each distinct lookup key of the given issues once, with at most concurrency
+// parallel lookups. As a failing lookup indicates that the learn service is unavailable, no further lookups are
+// started after the first error or once the context is cancelled. Returns the number of started lookups.
+func warmLearnCache(ctx context.Context, learnService learn.Service, issues []vulnmap.Issue, concurrency int) int {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	logger := config.CurrentConfig().Logger().With().Str("method", "warmLearnCache").Logger()
+	wg := sync.WaitGroup{}
+	semaphore := make(chan struct{}, concurrency)
+	requested := map[learnLookupKey]bool{}
+	failed := atomic.Bool{}
+	for _, issue := range issues {
+		key := newLearnLookupKey(issue)
+		if requested[key] {
+			continue
+		}
+
+		semaphore <- struct{}{} // Acquire semaphore
+		if failed.Load() || ctx.Err() != nil {
+			<-semaphore
+			break
+		}
+		requested[key] = true
+
+		wg.Add(1)
+		go func(issue vulnmap.Issue) {
+			defer wg.Done()
+			defer func() { <-semaphore }() // Release semaphore
+			_, err := learnService.GetLesson(ctx, issue.Ecosystem, issue.ID, issue.CWEs, issue.CVEs, issue.IssueType)
+			if err != nil {
+				logger.Debug().Err(err).Msgf("failed to look up lesson for %s, stopping", issue.ID)
+				failed.Store(true)
+			}
+		}(issue)
+	}
+	wg.Wait()
+	logger.Debug().Msgf("looked up lessons for %d distinct issues", len(requested))
+	return len(requested)
+}
@@ -0,0 +1,89 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/learn/mock_learn"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_warmLearnCache_LooksUpDistinctLessonsOnce(t *testing.T) {
+	testutil.UnitTest(t)
+	ctrl := gomock.NewController(t)
+	mockService := mock_learn.NewMockService(ctrl)
+	lodash := vulnmap.Issue{
+		ID:        "VULNMAP-JS-LODASH-1",
+		Ecosystem: "npm",
+		CWEs:      []string{"CWE-78"},
+		CVEs:      []string{"CVE-2021-23337"},
+		IssueType: vulnmap.DependencyVulnerability,
+	}
+	lodashInOtherFile := lodash
+	lodashInOtherFile.AffectedFilePath = "other/package.json"
+	sqlInjection := vulnmap.Issue{
+		ID:        "javascript/Sqli",
+		CWEs:      []string{"CWE-89"},
+		IssueType: vulnmap.CodeSecurityVulnerability,
+	}
+	mockService.EXPECT().
+		GetLesson(gomock.Any(), "npm", lodash.ID, lodash.CWEs, lodash.CVEs, vulnmap.DependencyVulnerability).
+		Return(nil, nil).
+		Times(1)
+	mockService.EXPECT().
+		GetLesson(gomock.Any(), "", sqlInjection.ID, sqlInjection.CWEs, gomock.Any(), vulnmap.CodeSecurityVulnerability).
+		Return(nil, nil).
+		Times(1)
+
+	lookups := warmLearnCache(context.Background(), mockService, []vulnmap.Issue{lodash, sqlInjection, lodashInOtherFile, lodash}, 2)
+
+	assert.Equal(t, 2, lookups)
+}
+
+func Test_warmLearnCache_StopsAfterFailedLookup(t *testing.T) {
+	testutil.UnitTest(t)
+	ctrl := gomock.NewController(t)
+	mockService := mock_learn.NewMockService(ctrl)
+	issues := []vulnmap.Issue{{ID: "id1"}, {ID: "id2"}, {ID: "id3"}}
+	mockService.EXPECT().
+		GetLesson(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("learn service unavailable")).
+		Times(1)
+
+	lookups := warmLearnCache(context.Background(), mockService, issues, 1)
+
+	assert.Equal(t, 1, lookups)
+}
+
+func Test_warmLearnCache_StopsOnceContextIsCancelled(t *testing.T) {
+	testutil.UnitTest(t)
+	ctrl := gomock.NewController(t)
+	mockService := mock_learn.NewMockService(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	lookups := warmLearnCache(ctx, mockService, []vulnmap.Issue{{ID: "id1"}, {ID: "id2"}}, 1)
+
+	assert.Equal(t, 0, lookups)
+}
@@ -19,9 +19,13 @@ package converter
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	sglsp "github.com/sourcegraph/go-lsp"
 
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
@@ -155,22 +159,199 @@ func ToDiagnostics(issues []vulnmap.Issue) []lsp.Diagnostic {
 	// Do not prefer nil over an empty slice in this case. The next line ensures that even if issues is empty,
 	// the return value of this function will not be null.
 	diagnostics := []lsp.Diagnostic{}
+	includeFingerprints := config.CurrentConfig().IsIssueFingerprintingEnabled()
+	maxMessageLength := config.CurrentConfig().MaxMessageLength()
 
+	for _, issue := range sortedIssues(issues) {
+		diagnostic := lsp.Diagnostic{
+			Range:              ToRange(issue.Range),
+			Severity:           ToSeverity(issue.Severity),
+			Code:               issue.DisplayedID(),
+			Source:             string(issue.Product),
+			Message:            truncateMessage(issue.Message, maxMessageLength),
+			CodeDescription:    lsp.CodeDescription{Href: toCodeDescriptionHref(issue)},
+			Tags:               toDiagnosticTags(issue),
+			RelatedInformation: toRelatedInformation(issue),
+		}
+		if includeFingerprints {
+			diagnostic.Data = lsp.DiagnosticData{
+				Fingerprint: issue.Fingerprint(),
+				Product:     string(issue.Product),
+				Severity:    issue.Severity.String(),
+			}
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// ManifestSummaryCode is the code of the diagnostic summarizing the vulnerabilities of a manifest
+const ManifestSummaryCode = "vulnmap-manifest-summary"
+
+// ToManifestSummaryDiagnostic returns an informational diagnostic at the top of the file that counts the
+// vulnerabilities of the given issues by severity, e.g. "3 vulnerabilities (1 critical, 2 high)". found is false
+// if the issues contain no vulnerabilities.
+func ToManifestSummaryDiagnostic(issues []vulnmap.Issue) (diagnostic lsp.Diagnostic, found bool) {
+	var count vulnmap.SeverityCount
+	total := 0
 	for _, issue := range issues {
-		s := ""
-		if issue.IssueDescriptionURL != nil {
-			s = issue.IssueDescriptionURL.String()
+		if issue.IssueType != vulnmap.DependencyVulnerability {
+			continue
+		}
+		total++
+		switch issue.Severity {
+		case vulnmap.Critical:
+			count.Critical++
+		case vulnmap.High:
+			count.High++
+		case vulnmap.Medium:
+			count.Medium++
+		case vulnmap.Low:
+			count.Low++
+		}
+	}
+	if total == 0 {
+		return diagnostic, false
+	}
+
+	var counts []string
+	for _, severityCount := range []struct {
+		severity vulnmap.Severity
+		count    int
+	}{
+		{vulnmap.Critical, count.Critical},
+		{vulnmap.High, count.High},
+		{vulnmap.Medium, count.Medium},
+		{vulnmap.Low, count.Low},
+	} {
+		if severityCount.count > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", severityCount.count, severityCount.severity))
 		}
-		diagnostics = append(diagnostics, lsp.Diagnostic{
-			Range:           ToRange(issue.Range),
-			Severity:        ToSeverity(issue.Severity),
-			Code:            issue.ID,
-			Source:          string(issue.Product),
-			Message:         issue.Message,
-			CodeDescription: lsp.CodeDescription{Href: lsp.Uri(s)},
+	}
+	noun := "vulnerabilities"
+	if total == 1 {
+		noun = "vulnerability"
+	}
+	return lsp.Diagnostic{
+		Range:    sglsp.Range{},
+		Severity: lsp.DiagnosticsSeverityInformation,
+		Code:     ManifestSummaryCode,
+		Source:   string(issues[0].Product),
+		Message:  fmt.Sprintf("%d %s (%s)", total, noun, strings.Join(counts, ", ")),
+	}, true
+}
+
+// StaleResultsCode is the code of the diagnostic marking the results of a file as possibly outdated
+const StaleResultsCode = "vulnmap-stale-results"
+
+// ToStaleResultsDiagnostic returns an informational diagnostic at the top of the file that tells the user that the
+// results of the file are older than the configured max result age and should be refreshed by a rescan
+func ToStaleResultsDiagnostic(issues []vulnmap.Issue, age time.Duration) lsp.Diagnostic {
+	source := ""
+	if len(issues) > 0 {
+		source = string(issues[0].Product)
+	}
+	return lsp.Diagnostic{
+		Range:    sglsp.Range{},
+		Severity: lsp.DiagnosticsSeverityInformation,
+		Code:     StaleResultsCode,
+		Source:   source,
+		Message:  fmt.Sprintf("Results may be outdated, they were found %s ago. Rescan to refresh them.", formatAge(age)),
+	}
+}
+
+// formatAge returns the age in its largest whole unit, e.g. "2 days" or "5 hours"
+func formatAge(age time.Duration) string {
+	value, unit := int(age/time.Minute), "minute"
+	if age >= 24*time.Hour {
+		value, unit = int(age/(24*time.Hour)), "day"
+	} else if age >= time.Hour {
+		value, unit = int(age/time.Hour), "hour"
+	}
+	if value != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", value, unit)
+}
+
+// truncationSuffix points to the hover, which always contains the full message
+const truncationSuffix = "… (see hover for full details)"
+
+// truncateMessage shortens messages that are longer than maxLength characters, including the truncation suffix.
+// A maxLength of 0 means no truncation.
+func truncateMessage(message string, maxLength int) string {
+	runes := []rune(message)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return message
+	}
+	kept := maxLength - len([]rune(truncationSuffix))
+	if kept < 1 {
+		return string(runes[:maxLength-1]) + "…"
+	}
+	return strings.TrimRight(string(runes[:kept]), " ") + truncationSuffix
+}
+
+// sortedIssues returns a copy of the issues ordered by range start, severity (most severe first) and ID, so that
+// repeated publishes of the same issues are identical regardless of the order they were cached in.
+func sortedIssues(issues []vulnmap.Issue) []vulnmap.Issue {
+	sorted := make([]vulnmap.Issue, len(issues))
+	copy(sorted, issues)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Range.Start.Line != b.Range.Start.Line {
+			return a.Range.Start.Line < b.Range.Start.Line
+		}
+		if a.Range.Start.Character != b.Range.Start.Character {
+			return a.Range.Start.Character < b.Range.Start.Character
+		}
+		if a.Severity != b.Severity {
+			return a.Severity < b.Severity
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Message < b.Message
+	})
+	return sorted
+}
+
+// toCodeDescriptionHref links to the issue description, falling back to the Vulnmap Learn lesson and then to the
+// first CWE of the issue.
+func toCodeDescriptionHref(issue vulnmap.Issue) lsp.Uri {
+	if issue.IssueDescriptionURL != nil && issue.IssueDescriptionURL.String() != "" {
+		return lsp.Uri(issue.IssueDescriptionURL.String())
+	}
+	if issue.LessonURL != nil && issue.LessonURL.String() != "" {
+		return lsp.Uri(issue.LessonURL.String())
+	}
+	for _, cwe := range issue.CWEs {
+		if id, found := strings.CutPrefix(strings.ToUpper(cwe), "CWE-"); found && id != "" {
+			return lsp.Uri(fmt.Sprintf("https://cwe.mitre.org/data/definitions/%s.html", id))
+		}
+	}
+	return ""
+}
+
+func toDiagnosticTags(issue vulnmap.Issue) []lsp.DiagnosticTag {
+	if ossData, ok := issue.AdditionalData.(vulnmap.OssIssueData); ok && ossData.IsUpgradable {
+		return []lsp.DiagnosticTag{lsp.Upgradable}
+	}
+	return nil
+}
+
+// toRelatedInformation links the issue to its other occurrences, nil if the issue occurs only once
+func toRelatedInformation(issue vulnmap.Issue) []lsp.DiagnosticRelatedInformation {
+	if len(issue.RelatedLocations) == 0 {
+		return nil
+	}
+	relatedInformation := make([]lsp.DiagnosticRelatedInformation, 0, len(issue.RelatedLocations))
+	for _, location := range issue.RelatedLocations {
+		relatedInformation = append(relatedInformation, lsp.DiagnosticRelatedInformation{
+			Location: sglsp.Location{URI: uri.PathToUri(location.FilePath), Range: ToRange(location.Range)},
+			Message:  location.Message,
 		})
 	}
-	return diagnostics
+	return relatedInformation
 }
 
 func ToHoversDocument(path string, issues []vulnmap.Issue) hover.DocumentHovers {
@@ -197,6 +378,10 @@ func ToHovers(issues []vulnmap.Issue) (hovers []hover.Hover[hover.Context]) {
 			}
 		}
 
+		if len(i.Tags) > 0 {
+			message += "\n\nTags: " + strings.Join(i.Tags, ", ")
+		}
+
 		// sanitize the message, substitute <br> with line break
 		message = re.ReplaceAllString(message, "\n\n")
 
@@ -17,12 +17,23 @@
 package converter
 
 import (
+	"encoding/json"
+	"math/rand"
+	"net/url"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	sglsp "github.com/sourcegraph/go-lsp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
 )
 
 func TestToHovers(t *testing.T) {
@@ -31,3 +42,296 @@ func TestToHovers(t *testing.T) {
 	hovers := ToHovers([]vulnmap.Issue{testIssue})
 	assert.Equal(t, "\n\n\n\n\n\n", hovers[0].Message)
 }
+
+func TestToHovers_ShowsTags(t *testing.T) {
+	testutil.UnitTest(t)
+	testIssue := vulnmap.Issue{Message: "Prototype Pollution", Tags: []string{"jira-1234", "needs-review"}}
+
+	hovers := ToHovers([]vulnmap.Issue{testIssue})
+
+	assert.Equal(t, "Prototype Pollution\n\nTags: jira-1234, needs-review", hovers[0].Message)
+}
+
+func scannedIssue() vulnmap.Issue {
+	return vulnmap.Issue{
+		ID:               "VULNMAP-JS-LODASH-567746",
+		Severity:         vulnmap.High,
+		Product:          product.ProductOpenSource,
+		AffectedFilePath: "/project/package.json",
+		Range:            vulnmap.Range{Start: vulnmap.Position{Line: 17, Character: 4}, End: vulnmap.Position{Line: 17, Character: 21}},
+		AdditionalData:   vulnmap.OssIssueData{PackageName: "lodash", Version: "4.17.4"},
+	}
+}
+
+func TestToDiagnostics_FingerprintIsStableAcrossScans(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetIssueFingerprinting(true)
+	firstScan := scannedIssue()
+	secondScan := scannedIssue()
+	secondScan.Message = "message of a later scan"
+	secondScan.Range.Start.Line = 18 // a line was added above the dependency
+
+	first := ToDiagnostics([]vulnmap.Issue{firstScan})[0].Data.(lsp.DiagnosticData)
+	second := ToDiagnostics([]vulnmap.Issue{secondScan})[0].Data.(lsp.DiagnosticData)
+
+	assert.NotEmpty(t, first.Fingerprint)
+	assert.Equal(t, first.Fingerprint, second.Fingerprint)
+	assert.Equal(t, string(product.ProductOpenSource), first.Product)
+	assert.Equal(t, "high", first.Severity)
+}
+
+func TestToDiagnostics_FingerprintRoundTripsThroughDataField(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetIssueFingerprinting(true)
+	issue := scannedIssue()
+
+	published, err := json.Marshal(ToDiagnostics([]vulnmap.Issue{issue})[0])
+	require.NoError(t, err)
+	var received struct {
+		Data lsp.DiagnosticData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(published, &received))
+
+	assert.Equal(t, issue.Fingerprint(), received.Data.Fingerprint)
+}
+
+func TestToDiagnostics_FingerprintsDisabledByDefault(t *testing.T) {
+	testutil.UnitTest(t)
+
+	diagnostics := ToDiagnostics([]vulnmap.Issue{scannedIssue()})
+
+	assert.Nil(t, diagnostics[0].Data)
+}
+
+func TestToDiagnostics_LongMessageIsTruncated(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetMaxMessageLength(60)
+	issue := scannedIssue()
+	issue.Message = strings.Repeat("Prototype Pollution in lodash. ", 100)
+
+	diagnostics := ToDiagnostics([]vulnmap.Issue{issue})
+	hovers := ToHovers([]vulnmap.Issue{issue})
+
+	assert.Equal(t, "Prototype Pollution in lodash.… (see hover for full details)", diagnostics[0].Message)
+	assert.Len(t, []rune(diagnostics[0].Message), 60)
+	assert.Equal(t, issue.Message, hovers[0].Message)
+}
+
+func TestToDiagnostics_MessageIsNotTruncatedByDefault(t *testing.T) {
+	testutil.UnitTest(t)
+	issue := scannedIssue()
+	issue.Message = strings.Repeat("a", 10000)
+
+	diagnostics := ToDiagnostics([]vulnmap.Issue{issue})
+
+	assert.Equal(t, issue.Message, diagnostics[0].Message)
+}
+
+func Test_truncateMessage(t *testing.T) {
+	assert.Equal(t, "short", truncateMessage("short", 5))
+	assert.Equal(t, "Füße überall", truncateMessage("Füße überall", 0))
+	assert.Equal(t, "abc…", truncateMessage("abcdefgh", 4), "too short for the suffix")
+}
+
+func TestToDiagnostics_UpgradableIssueIsTagged(t *testing.T) {
+	testutil.UnitTest(t)
+	issue := scannedIssue()
+	issue.AdditionalData = vulnmap.OssIssueData{PackageName: "lodash", Version: "4.17.4", IsUpgradable: true}
+
+	diagnostics := ToDiagnostics([]vulnmap.Issue{issue, scannedIssue()})
+
+	assert.Equal(t, []lsp.DiagnosticTag{lsp.Upgradable}, diagnostics[0].Tags)
+	assert.Empty(t, diagnostics[1].Tags)
+}
+
+func TestToDiagnostics_RelatedInformationLinksOtherOccurrences(t *testing.T) {
+	testutil.UnitTest(t)
+	otherFile := filepath.Join(t.TempDir(), "sub", "package.json")
+	otherRange := vulnmap.Range{Start: vulnmap.Position{Line: 3, Character: 4}, End: vulnmap.Position{Line: 3, Character: 20}}
+	issue := scannedIssue()
+	issue.CVEs = []string{"CVE-2019-10744"}
+	issue.RelatedLocations = []vulnmap.RelatedLocation{
+		{FilePath: otherFile, Range: otherRange, Message: "VULNMAP-JS-LODASH-1 also affects lodash@4.17.4"},
+	}
+
+	diagnostics := ToDiagnostics([]vulnmap.Issue{issue})
+
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, []lsp.DiagnosticRelatedInformation{{
+		Location: sglsp.Location{URI: uri.PathToUri(otherFile), Range: ToRange(otherRange)},
+		Message:  "VULNMAP-JS-LODASH-1 also affects lodash@4.17.4",
+	}}, diagnostics[0].RelatedInformation)
+}
+
+func TestToDiagnostics_SingleOccurrenceHasNoRelatedInformation(t *testing.T) {
+	testutil.UnitTest(t)
+
+	published, err := json.Marshal(ToDiagnostics([]vulnmap.Issue{scannedIssue()})[0])
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(published), "relatedInformation")
+}
+
+func TestToDiagnostics_TagsAreSerializedAsProtocolField(t *testing.T) {
+	testutil.UnitTest(t)
+	issue := scannedIssue()
+	issue.AdditionalData = vulnmap.OssIssueData{IsUpgradable: true}
+
+	published, err := json.Marshal(ToDiagnostics([]vulnmap.Issue{issue})[0])
+	require.NoError(t, err)
+
+	assert.Contains(t, string(published), `"tags":[100]`)
+}
+
+func TestToDiagnostics_CodeDescription(t *testing.T) {
+	testutil.UnitTest(t)
+
+	t.Run("uses the issue description url", func(t *testing.T) {
+		issue := scannedIssue()
+		issueURL, _ := url.Parse("https://vulnmap.khulnasoft.com/vuln/VULNMAP-JS-LODASH-567746")
+		issue.IssueDescriptionURL = issueURL
+
+		diagnostics := ToDiagnostics([]vulnmap.Issue{issue})
+
+		assert.Equal(t, lsp.Uri(issueURL.String()), diagnostics[0].CodeDescription.Href)
+	})
+
+	t.Run("falls back to the lesson", func(t *testing.T) {
+		issue := scannedIssue()
+		issue.IssueDescriptionURL = &url.URL{}
+		issue.LessonURL, _ = url.Parse("https://learn.vulnmap.khulnasoft.com/lesson/prototype-pollution")
+		issue.CWEs = []string{"CWE-1321"}
+
+		diagnostics := ToDiagnostics([]vulnmap.Issue{issue})
+
+		assert.Equal(t, lsp.Uri("https://learn.vulnmap.khulnasoft.com/lesson/prototype-pollution"),
+			diagnostics[0].CodeDescription.Href)
+	})
+
+	t.Run("falls back to the cwe", func(t *testing.T) {
+		issue := scannedIssue()
+		issue.CWEs = []string{"CWE-79"}
+
+		diagnostics := ToDiagnostics([]vulnmap.Issue{issue})
+
+		assert.Equal(t, lsp.Uri("https://cwe.mitre.org/data/definitions/79.html"), diagnostics[0].CodeDescription.Href)
+	})
+}
+
+func TestToDiagnostics_CodeIsTheIssueIdentifier(t *testing.T) {
+	testutil.UnitTest(t)
+	issue := scannedIssue()
+	issue.ID = "CVE-2021-23337"
+	issue.IssueDescriptionURL, _ = url.Parse("https://vulnmap.khulnasoft.com/vuln/VULNMAP-JS-LODASH-1040724")
+
+	diagnostics := ToDiagnostics([]vulnmap.Issue{issue})
+
+	assert.Equal(t, "CVE-2021-23337", diagnostics[0].Code)
+	assert.Equal(t, lsp.Uri("https://vulnmap.khulnasoft.com/vuln/VULNMAP-JS-LODASH-1040724"), diagnostics[0].CodeDescription.Href)
+}
+
+func TestToStaleResultsDiagnostic(t *testing.T) {
+	issues := []vulnmap.Issue{{ID: "1", Product: product.ProductOpenSource}}
+
+	diagnostic := ToStaleResultsDiagnostic(issues, 5*time.Hour+30*time.Minute)
+
+	assert.Equal(t, StaleResultsCode, diagnostic.Code)
+	assert.Equal(t, lsp.DiagnosticsSeverityInformation, diagnostic.Severity)
+	assert.Equal(t, string(product.ProductOpenSource), diagnostic.Source)
+	assert.Equal(t, "Results may be outdated, they were found 5 hours ago. Rescan to refresh them.", diagnostic.Message)
+}
+
+func Test_formatAge(t *testing.T) {
+	assert.Equal(t, "1 minute", formatAge(time.Minute+10*time.Second))
+	assert.Equal(t, "45 minutes", formatAge(45*time.Minute))
+	assert.Equal(t, "1 hour", formatAge(time.Hour))
+	assert.Equal(t, "3 days", formatAge(80*time.Hour))
+}
+
+func TestToManifestSummaryDiagnostic(t *testing.T) {
+	vulnerability := func(severity vulnmap.Severity) vulnmap.Issue {
+		issue := scannedIssue()
+		issue.IssueType = vulnmap.DependencyVulnerability
+		issue.Severity = severity
+		return issue
+	}
+
+	t.Run("counts the vulnerabilities by severity", func(t *testing.T) {
+		license := scannedIssue()
+		license.IssueType = vulnmap.LicenceIssue
+
+		diagnostic, found := ToManifestSummaryDiagnostic([]vulnmap.Issue{
+			vulnerability(vulnmap.High), vulnerability(vulnmap.Critical), vulnerability(vulnmap.High), license,
+		})
+
+		require.True(t, found)
+		assert.Equal(t, "3 vulnerabilities (1 critical, 2 high)", diagnostic.Message)
+		assert.Equal(t, ManifestSummaryCode, diagnostic.Code)
+		assert.Equal(t, lsp.DiagnosticsSeverityInformation, diagnostic.Severity)
+		assert.Equal(t, sglsp.Range{}, diagnostic.Range)
+		assert.Equal(t, string(product.ProductOpenSource), diagnostic.Source)
+	})
+
+	t.Run("no summary without vulnerabilities", func(t *testing.T) {
+		_, found := ToManifestSummaryDiagnostic([]vulnmap.Issue{})
+
+		assert.False(t, found)
+	})
+}
+
+func TestToSeverity(t *testing.T) {
+	tests := map[vulnmap.Severity]lsp.DiagnosticSeverity{
+		vulnmap.Critical: lsp.DiagnosticsSeverityError,
+		vulnmap.High:     lsp.DiagnosticsSeverityError,
+		vulnmap.Medium:   lsp.DiagnosticsSeverityWarning,
+		vulnmap.Low:      lsp.DiagnosticsSeverityInformation,
+		vulnmap.Unknown:  lsp.DiagnosticsSeverityHint,
+	}
+	for severity, expected := range tests {
+		assert.Equal(t, expected, ToSeverity(severity), severity.String())
+	}
+}
+
+func TestToDiagnostics_OrderIsDeterministic(t *testing.T) {
+	testutil.UnitTest(t)
+	issueAt := func(id string, line int, severity vulnmap.Severity) vulnmap.Issue {
+		return vulnmap.Issue{
+			ID:       id,
+			Severity: severity,
+			Range:    vulnmap.Range{Start: vulnmap.Position{Line: line}, End: vulnmap.Position{Line: line, Character: 5}},
+		}
+	}
+	issues := []vulnmap.Issue{
+		issueAt("c", 3, vulnmap.Low),
+		issueAt("b", 1, vulnmap.Medium),
+		issueAt("a", 1, vulnmap.Medium),
+		issueAt("d", 1, vulnmap.Critical),
+		issueAt("e", 0, vulnmap.Low),
+	}
+	shuffled := make([]vulnmap.Issue, len(issues))
+	copy(shuffled, issues)
+	rand.New(rand.NewSource(42)).Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	first, err := json.Marshal(ToDiagnostics(issues))
+	require.NoError(t, err)
+	second, err := json.Marshal(ToDiagnostics(shuffled))
+	require.NoError(t, err)
+
+	assert.Equal(t, string(first), string(second))
+	var codes []any
+	for _, diagnostic := range ToDiagnostics(shuffled) {
+		codes = append(codes, diagnostic.Code)
+	}
+	assert.Equal(t, []any{"e", "d", "a", "b", "c"}, codes)
+	assert.Equal(t, "c", issues[0].ID, "input must not be reordered")
+}
+
+func TestToDiagnostics_CodeIsTheDisplayedId(t *testing.T) {
+	testutil.UnitTest(t)
+	issue := scannedIssue()
+	issue.DisplayID = "CVE-2021-23337"
+
+	diagnostic := ToDiagnostics([]vulnmap.Issue{issue})[0]
+
+	assert.Equal(t, "CVE-2021-23337", diagnostic.Code)
+}
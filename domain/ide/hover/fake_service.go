@@ -59,6 +59,8 @@ func (t *FakeHoverService) SetAnalytics(_ ux2.Analytics) {
 	panic("implement me")
 }
 
+func (t *FakeHoverService) Close() {}
+
 func (t *FakeHoverService) Calls() int {
 	return t.calls
 }
@@ -33,6 +33,7 @@ type Service interface {
 	ClearAllHovers()
 	GetHover(path string, pos vulnmap.Position) Result
 	SetAnalytics(analytics ux2.Analytics)
+	Close()
 }
 
 type DefaultHoverService struct {
@@ -41,6 +42,7 @@ type DefaultHoverService struct {
 	hoverChan    chan DocumentHovers
 	mutex        *sync.Mutex
 	analytics    ux2.Analytics
+	closeOnce    sync.Once
 }
 
 func NewDefaultService(analytics ux2.Analytics) Service {
@@ -143,8 +145,7 @@ func (s *DefaultHoverService) trackHoverDetails(hover Hover[Context]) {
 }
 
 func (s *DefaultHoverService) createHoverListener() {
-	for {
-		result := <-s.hoverChan
+	for result := range s.hoverChan {
 		log.Trace().
 			Str("method", "createHoverListener").
 			Str("uri", result.Path).
@@ -157,3 +158,10 @@ func (s *DefaultHoverService) createHoverListener() {
 func (s *DefaultHoverService) SetAnalytics(analytics ux2.Analytics) {
 	s.analytics = analytics
 }
+
+// Close closes the hover channel, which stops the hover listener
+func (s *DefaultHoverService) Close() {
+	s.closeOnce.Do(func() {
+		close(s.hoverChan)
+	})
+}
@@ -0,0 +1,97 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	goos "os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// AcceptIssue accepts the cached issue with the fingerprint until the expiry date in the format YYYY-MM-DD, or
+// forever if it is empty, and records the justification and the accepting user in the accepted issues of its folder.
+// Accepted issues are hidden.
+func (w *Workspace) AcceptIssue(fingerprint string, justification string, expires string,
+	acceptedBy string) (lsp.AcceptedIssue, error) {
+	folder, issue, found := w.folderIssueByFingerprint(fingerprint)
+	if !found {
+		return lsp.AcceptedIssue{}, errors.Errorf("no issue with fingerprint %s found", fingerprint)
+	}
+
+	path := config.CurrentConfig().AcceptedIssuesPath(folder.Path())
+	acceptedIssues, err := vulnmap.LoadAcceptedIssues(path)
+	if errors.Is(err, goos.ErrNotExist) {
+		acceptedIssues = vulnmap.NewAcceptedIssues()
+	} else if err != nil {
+		return lsp.AcceptedIssue{}, err
+	}
+	now := time.Now()
+	accepted, err := acceptedIssues.Accept(issue, justification, acceptedBy, now, expires)
+	if err != nil {
+		return lsp.AcceptedIssue{}, err
+	}
+	if err = acceptedIssues.Save(path); err != nil {
+		return lsp.AcceptedIssue{}, err
+	}
+
+	acceptedIssuesLoader.Reset()
+	for _, folder := range w.Folders() {
+		folder.FilterAndPublishCachedDiagnostics("")
+	}
+	return toLspAcceptedIssue(fingerprint, accepted, now), nil
+}
+
+// AcceptedIssues returns the accepted issues of all folders, including expired ones, ordered by the time they were
+// accepted
+func (w *Workspace) AcceptedIssues() []lsp.AcceptedIssue {
+	now := time.Now()
+	result := []lsp.AcceptedIssue{}
+	for _, path := range w.folderDataPaths(config.CurrentConfig().AcceptedIssuesPath) {
+		acceptedIssues := acceptedIssuesLoader.Load(path)
+		if acceptedIssues == nil {
+			continue
+		}
+		for fingerprint, accepted := range acceptedIssues.Entries {
+			result = append(result, toLspAcceptedIssue(fingerprint, accepted, now))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].AcceptedAt.Equal(result[j].AcceptedAt) {
+			return result[i].AcceptedAt.Before(result[j].AcceptedAt)
+		}
+		return result[i].Fingerprint < result[j].Fingerprint
+	})
+	return result
+}
+
+func toLspAcceptedIssue(fingerprint string, accepted vulnmap.AcceptedIssue, now time.Time) lsp.AcceptedIssue {
+	return lsp.AcceptedIssue{
+		Fingerprint:   fingerprint,
+		IssueId:       accepted.IssueId,
+		Justification: accepted.Justification,
+		AcceptedBy:    accepted.AcceptedBy,
+		AcceptedAt:    accepted.AcceptedAt,
+		Expires:       accepted.Expires,
+		Expired:       accepted.IsExpired(now),
+	}
+}
@@ -0,0 +1,152 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func setupAcceptedIssues(t *testing.T) {
+	t.Helper()
+	c := testutil.UnitTest(t)
+	c.SetAcceptedIssuesPath(filepath.Join(t.TempDir(), "accepted_issues.json"))
+	t.Cleanup(acceptedIssuesLoader.Reset)
+}
+
+func Test_AcceptIssue_HidesIssueAndRecordsJustification(t *testing.T) {
+	setupAcceptedIssues(t)
+	accepted := NewMockIssue("VULNMAP-JS-LODASH-1", "package.json")
+	other := NewMockIssue("VULNMAP-JS-EXPRESS-1", "package.json")
+	w := setupSearchWorkspace(t, accepted, other)
+
+	result, err := w.AcceptIssue(accepted.Fingerprint(), "not reachable from user input", "", "jane")
+
+	require.NoError(t, err)
+	assert.Equal(t, accepted.ID, result.IssueId)
+	assert.Equal(t, "not reachable from user input", result.Justification)
+	assert.Equal(t, "jane", result.AcceptedBy)
+	assert.False(t, result.AcceptedAt.IsZero())
+	assert.Equal(t, []vulnmap.Issue{other}, w.Folders()[0].FilteredIssues())
+}
+
+func Test_AcceptIssue_RequiresJustification(t *testing.T) {
+	setupAcceptedIssues(t)
+	issue := NewMockIssue("VULNMAP-JS-LODASH-1", "package.json")
+	w := setupSearchWorkspace(t, issue)
+
+	_, err := w.AcceptIssue(issue.Fingerprint(), "", "", "jane")
+
+	assert.Error(t, err)
+	assert.Len(t, w.Folders()[0].FilteredIssues(), 1)
+}
+
+func Test_AcceptIssue_UnknownFingerprint(t *testing.T) {
+	setupAcceptedIssues(t)
+	w := setupSearchWorkspace(t)
+
+	_, err := w.AcceptIssue("unknown", "accepted risk", "", "jane")
+
+	assert.Error(t, err)
+}
+
+func Test_AcceptIssue_ExpiredAcceptanceResurfacesIssue(t *testing.T) {
+	setupAcceptedIssues(t)
+	issue := NewMockIssue("VULNMAP-JS-LODASH-1", "package.json")
+	w := setupSearchWorkspace(t, issue)
+
+	result, err := w.AcceptIssue(issue.Fingerprint(), "fix planned for next sprint", "2000-01-01", "jane")
+
+	require.NoError(t, err)
+	assert.True(t, result.Expired)
+	assert.Equal(t, []vulnmap.Issue{issue}, w.Folders()[0].FilteredIssues())
+}
+
+func Test_AcceptedIssues_ListsAcceptedIssuesInOrder(t *testing.T) {
+	setupAcceptedIssues(t)
+	first := NewMockIssue("VULNMAP-JS-LODASH-1", "package.json")
+	second := NewMockIssue("VULNMAP-JS-EXPRESS-1", "package.json")
+	w := setupSearchWorkspace(t, first, second)
+	assert.Empty(t, w.AcceptedIssues())
+
+	_, err := w.AcceptIssue(first.Fingerprint(), "accepted risk", "", "jane")
+	require.NoError(t, err)
+	_, err = w.AcceptIssue(second.Fingerprint(), "false positive", "2000-01-01", "john")
+	require.NoError(t, err)
+
+	acceptedIssues := w.AcceptedIssues()
+
+	require.Len(t, acceptedIssues, 2)
+	assert.Equal(t, first.Fingerprint(), acceptedIssues[0].Fingerprint)
+	assert.Equal(t, "accepted risk", acceptedIssues[0].Justification)
+	assert.Equal(t, "jane", acceptedIssues[0].AcceptedBy)
+	assert.False(t, acceptedIssues[0].Expired)
+	assert.Equal(t, second.Fingerprint(), acceptedIssues[1].Fingerprint)
+	assert.Equal(t, "false positive", acceptedIssues[1].Justification)
+	assert.True(t, acceptedIssues[1].Expired)
+}
+
+// setupTwoFolderWorkspace returns a workspace with two folders, each caching one issue with the given id
+// for its own package.json.
+func setupTwoFolderWorkspace(t *testing.T, issueId string) (*Workspace, []*Folder, []vulnmap.Issue) {
+	t.Helper()
+	notifier := notification.NewNotifier()
+	w := New(performance.NewInstrumentor(), vulnmap.NewTestScanner(), nil, nil, notifier)
+	var folders []*Folder
+	var issues []vulnmap.Issue
+	for _, name := range []string{"first", "second"} {
+		folderPath := t.TempDir()
+		f := NewFolder(folderPath, name, vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+		filePath := filepath.Join(folderPath, "package.json")
+		issue := NewMockIssue(issueId, filePath)
+		f.documentDiagnosticCache.Store(filePath, []vulnmap.Issue{issue})
+		w.AddFolder(f)
+		folders = append(folders, f)
+		issues = append(issues, issue)
+	}
+	return w, folders, issues
+}
+
+func Test_AcceptIssue_IsRecordedPerFolder(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAcceptedIssuesPath(".vulnmap-accepted-issues.json")
+	t.Cleanup(acceptedIssuesLoader.Reset)
+	w, folders, issues := setupTwoFolderWorkspace(t, "VULNMAP-JS-LODASH-1")
+
+	_, err := w.AcceptIssue(issues[0].Fingerprint(), "not reachable from user input", "", "jane")
+
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(folders[0].Path(), ".vulnmap-accepted-issues.json"))
+	assert.NoFileExists(t, filepath.Join(folders[1].Path(), ".vulnmap-accepted-issues.json"))
+	assert.Empty(t, folders[0].FilteredIssues())
+	assert.Len(t, folders[1].FilteredIssues(), 1)
+
+	_, err = w.AcceptIssue(issues[1].Fingerprint(), "dev dependency only", "", "jane")
+
+	require.NoError(t, err)
+	assert.Empty(t, folders[1].FilteredIssues())
+	assert.Len(t, w.AcceptedIssues(), 2)
+}
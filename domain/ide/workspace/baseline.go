@@ -0,0 +1,75 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"time"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// RecordBaseline accepts all cached issues of each folder until the expiry date in the format YYYY-MM-DD, or
+// forever if it is empty. It returns the number of accepted issues. The issues are hidden if the baseline is enabled.
+func (w *Workspace) RecordBaseline(expires string) (int, error) {
+	// folders share a baseline file if an absolute baseline path is configured
+	issuesByPath := map[string][]vulnmap.Issue{}
+	for _, folder := range w.Folders() {
+		var issues []vulnmap.Issue
+		folder.documentDiagnosticCache.Range(func(_ string, fileIssues []vulnmap.Issue) bool {
+			issues = append(issues, fileIssues...)
+			return true
+		})
+		path := config.CurrentConfig().BaselinePath(folder.path)
+		issuesByPath[path] = append(issuesByPath[path], issues...)
+	}
+	now := time.Now()
+	accepted := 0
+	for path, issues := range issuesByPath {
+		baseline, err := vulnmap.NewBaseline(issues, now, expires)
+		if err != nil {
+			return 0, err
+		}
+		if err = baseline.Save(path); err != nil {
+			return 0, err
+		}
+		accepted += len(baseline.Entries)
+	}
+	w.publishBaselineChange()
+	return accepted, nil
+}
+
+// ClearBaseline removes the recorded baselines of all folders, so that all issues are shown again
+func (w *Workspace) ClearBaseline() error {
+	for _, folder := range w.Folders() {
+		if err := vulnmap.RemoveBaseline(config.CurrentConfig().BaselinePath(folder.path)); err != nil {
+			return err
+		}
+	}
+	w.publishBaselineChange()
+	return nil
+}
+
+func (w *Workspace) publishBaselineChange() {
+	baselineLoader.Reset()
+	if !config.CurrentConfig().IsBaselineEnabled() {
+		return
+	}
+	for _, folder := range w.Folders() {
+		folder.FilterAndPublishCachedDiagnostics("")
+	}
+}
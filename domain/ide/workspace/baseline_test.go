@@ -0,0 +1,91 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_Baseline_HidesAcceptedIssuesAndShowsNewOnes(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetBaselineEnabled(true)
+	c.SetBaselinePath(filepath.Join(t.TempDir(), "baseline.json"))
+	t.Cleanup(baselineLoader.Reset)
+	w := setupSearchWorkspace(t,
+		NewMockIssue("VULNMAP-JS-LODASH-1", "package.json"),
+		NewMockIssue("VULNMAP-JS-EXPRESS-1", "package.json"),
+	)
+	f := w.Folders()[0]
+
+	accepted, err := w.RecordBaseline("")
+	require.NoError(t, err)
+	assert.Equal(t, 2, accepted)
+	assert.Empty(t, f.FilteredIssues())
+
+	newIssue := NewMockIssue("VULNMAP-JS-MINIMIST-1", "package.json")
+	cachedIssues, _ := f.documentDiagnosticCache.Load("package.json")
+	f.documentDiagnosticCache.Store("package.json", append(cachedIssues, newIssue))
+	assert.Equal(t, []vulnmap.Issue{newIssue}, f.FilteredIssues())
+
+	c.SetBaselineEnabled(false)
+	assert.Len(t, f.FilteredIssues(), 3)
+	c.SetBaselineEnabled(true)
+
+	require.NoError(t, w.ClearBaseline())
+	assert.Len(t, f.FilteredIssues(), 3)
+}
+
+func Test_Baseline_IsRecordedPerFolder(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetBaselineEnabled(true)
+	c.SetBaselinePath(".vulnmap-baseline.json")
+	t.Cleanup(baselineLoader.Reset)
+	notifier := notification.NewNotifier()
+	w := New(performance.NewInstrumentor(), vulnmap.NewTestScanner(), nil, nil, notifier)
+	var folders []*Folder
+	for _, name := range []string{"first", "second"} {
+		folderPath := t.TempDir()
+		f := NewFolder(folderPath, name, vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+		filePath := filepath.Join(folderPath, "package.json")
+		f.documentDiagnosticCache.Store(filePath, []vulnmap.Issue{NewMockIssue("VULNMAP-JS-LODASH-1", filePath)})
+		w.AddFolder(f)
+		folders = append(folders, f)
+	}
+
+	accepted, err := w.RecordBaseline("")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, accepted)
+	for _, f := range folders {
+		assert.FileExists(t, filepath.Join(f.Path(), ".vulnmap-baseline.json"))
+		assert.Empty(t, f.FilteredIssues())
+	}
+
+	require.NoError(t, vulnmap.RemoveBaseline(c.BaselinePath(folders[1].Path())))
+	assert.Empty(t, folders[0].FilteredIssues())
+	assert.Len(t, folders[1].FilteredIssues(), 1)
+}
@@ -0,0 +1,73 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+)
+
+// CloseFile clears the diagnostics of the closed file once the configured grace period has passed, so that users
+// flipping between files don't lose the findings they were just looking at. The diagnostics are kept if the file is
+// opened again within the grace period. A grace period of 0 clears them right away, a negative one keeps them.
+func (f *Folder) CloseFile(path string) {
+	gracePeriod := config.CurrentConfig().DiagnosticsCloseGracePeriod()
+	if gracePeriod < 0 {
+		return
+	}
+	if gracePeriod == 0 {
+		f.cancelClosedFileClear(path)
+		f.ClearDiagnosticsFromFile(path)
+		return
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.closedFileClears == nil {
+		f.closedFileClears = map[string]*time.Timer{}
+	}
+	if timer, pending := f.closedFileClears[path]; pending {
+		timer.Stop()
+	}
+	f.closedFileClears[path] = time.AfterFunc(gracePeriod, func() {
+		f.mutex.Lock()
+		delete(f.closedFileClears, path)
+		f.mutex.Unlock()
+		if f.stopCtx.Err() != nil {
+			return
+		}
+		log.Debug().Str("method", "CloseFile").Str("path", path).Msg("clearing diagnostics of closed file")
+		f.ClearDiagnosticsFromFile(path)
+	})
+}
+
+// OpenFile keeps the diagnostics of a file that was closed within the grace period
+func (f *Folder) OpenFile(path string) {
+	f.cancelClosedFileClear(path)
+}
+
+func (f *Folder) cancelClosedFileClear(path string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if timer, pending := f.closedFileClears[path]; pending {
+		timer.Stop()
+		delete(f.closedFileClears, path)
+	}
+}
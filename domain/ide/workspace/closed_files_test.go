@@ -0,0 +1,100 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func setUpClosedFileFolder(t *testing.T, gracePeriod time.Duration) (*Folder, string) {
+	t.Helper()
+	c := testutil.UnitTest(t)
+	c.SetDiagnosticsCloseGracePeriod(gracePeriod)
+	folderPath := t.TempDir()
+	filePath := filepath.Join(folderPath, "closed.txt")
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(NewMockIssue("id", filePath))
+	f := NewFolder(folderPath, "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	t.Cleanup(f.StopScans)
+	f.ScanFolder(context.Background())
+	require.NotEmpty(t, f.DocumentDiagnosticsFromCache(filePath))
+	return f, filePath
+}
+
+func Test_CloseFile_DiagnosticsSurviveTheGracePeriod(t *testing.T) {
+	f, filePath := setUpClosedFileFolder(t, time.Hour)
+
+	f.CloseFile(filePath)
+
+	assert.NotEmpty(t, f.DocumentDiagnosticsFromCache(filePath))
+}
+
+func Test_CloseFile_DiagnosticsAreClearedAfterTheGracePeriod(t *testing.T) {
+	f, filePath := setUpClosedFileFolder(t, 50*time.Millisecond)
+
+	f.CloseFile(filePath)
+
+	assert.NotEmpty(t, f.DocumentDiagnosticsFromCache(filePath))
+	assert.Eventually(t, func() bool {
+		_, found := f.documentDiagnosticCache.Load(filePath)
+		return !found
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func Test_CloseFile_ReopeningWithinTheGracePeriodKeepsDiagnostics(t *testing.T) {
+	f, filePath := setUpClosedFileFolder(t, 50*time.Millisecond)
+
+	f.CloseFile(filePath)
+	f.OpenFile(filePath)
+
+	assert.Never(t, func() bool {
+		_, found := f.documentDiagnosticCache.Load(filePath)
+		return !found
+	}, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func Test_CloseFile_ZeroGracePeriodClearsDiagnosticsRightAway(t *testing.T) {
+	f, filePath := setUpClosedFileFolder(t, 0)
+
+	f.CloseFile(filePath)
+
+	_, found := f.documentDiagnosticCache.Load(filePath)
+	assert.False(t, found)
+}
+
+func Test_CloseFile_NeverGracePeriodKeepsDiagnostics(t *testing.T) {
+	f, filePath := setUpClosedFileFolder(t, config.DiagnosticsCloseGracePeriodNever)
+
+	f.CloseFile(filePath)
+
+	assert.Never(t, func() bool {
+		_, found := f.documentDiagnosticCache.Load(filePath)
+		return !found
+	}, 100*time.Millisecond, 10*time.Millisecond)
+}
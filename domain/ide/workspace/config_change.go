@@ -0,0 +1,181 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// issueTypeProducts maps the filterable issue types to the product that finds them
+var issueTypeProducts = map[product.FilterableIssueType]product.Product{
+	product.FilterableIssueTypeOpenSource:           product.ProductOpenSource,
+	product.FilterableIssueTypeCodeSecurity:         product.ProductCode,
+	product.FilterableIssueTypeCodeQuality:          product.ProductCode,
+	product.FilterableIssueTypeInfrastructureAsCode: product.ProductInfrastructureAsCode,
+}
+
+// ConfigSnapshot captures the settings that determine which issues are published and how they are rendered
+type ConfigSnapshot struct {
+	severityFilter        lsp.SeverityFilter
+	suppressionPolicyPath string
+	baselineEnabled       bool
+	manifestSummary       bool
+	maxResultAge          time.Duration
+	minEpssScore          float64
+	devDependencyIssues   config.DevDependencyIssues
+	issueTagFilter        string
+	showOnlyFixable       bool
+	majorUpgradeFixes     bool
+	displayableIssueTypes map[product.FilterableIssueType]bool
+	// the settings applied when the cached issues are published
+	maxMessageLength        int
+	issueFingerprinting     bool
+	productDisplayOrder     string
+	projectIssuesTargetFile string
+	// productRendering contains the settings applied when the scan results of a product are converted to issues
+	productRendering map[product.Product]string
+}
+
+// ConfigChange describes how the published issues are affected by a settings change.
+// Filter changes only require the cached issues to be published again, while newly enabled
+// products need to be scanned, because there are no cached issues for them. The cached issues of
+// rerendered products were rendered with outdated settings, so these products are scanned again.
+type ConfigChange struct {
+	FilterChanged      bool
+	EnabledProducts    []product.Product
+	RerenderedProducts []product.Product
+}
+
+func TakeConfigSnapshot(c *config.Config) ConfigSnapshot {
+	return ConfigSnapshot{
+		severityFilter:        c.FilterSeverity(),
+		suppressionPolicyPath: c.SuppressionPolicyPath(),
+		baselineEnabled:       c.IsBaselineEnabled(),
+		manifestSummary:       c.IsManifestSummaryEnabled(),
+		maxResultAge:          c.MaxResultAge(),
+		minEpssScore:          c.MinEpssScore(),
+		devDependencyIssues:   c.DevDependencyIssues(),
+		issueTagFilter:        strings.Join(c.IssueTagFilter(), ","),
+		showOnlyFixable:       c.IsShowOnlyFixable(),
+		majorUpgradeFixes:     c.IsMajorUpgradeFixIncluded(),
+		displayableIssueTypes: c.DisplayableIssueTypes(),
+
+		maxMessageLength:        c.MaxMessageLength(),
+		issueFingerprinting:     c.IsIssueFingerprintingEnabled(),
+		productDisplayOrder:     fmt.Sprint(c.ProductDisplayOrder()),
+		projectIssuesTargetFile: c.ProjectIssuesTargetFile(),
+		productRendering:        productRenderingSettings(c),
+	}
+}
+
+// productRenderingSettings returns the settings that each product applies when it converts scan results to issues
+func productRenderingSettings(c *config.Config) map[product.Product]string {
+	var formats []string
+	for _, renderContext := range config.RenderContexts() {
+		formats = append(formats, c.FormatFor(renderContext))
+	}
+	return map[product.Product]string{
+		product.ProductOpenSource: fmt.Sprint(formats, c.IssueURLTemplate(product.ProductOpenSource), c.LinkStyle(),
+			c.AdvisoryIdentifierPreference(), c.LicenseSeverities(), c.IsExploitSeverityEscalationEnabled(),
+			c.IssueIdentifier(), c.SeverityMapping(), c.UnknownSeverityPolicy()),
+		product.ProductCode: fmt.Sprint(c.IssueURLTemplate(product.ProductCode), c.IsRelativeFilePathDisplay()),
+		product.ProductInfrastructureAsCode: fmt.Sprint(formats,
+			c.IssueURLTemplate(product.ProductInfrastructureAsCode)),
+	}
+}
+
+// ChangesTo compares the snapshot with the current settings
+func (s ConfigSnapshot) ChangesTo(c *config.Config) ConfigChange {
+	current := TakeConfigSnapshot(c)
+	change := ConfigChange{
+		FilterChanged: s.severityFilter != current.severityFilter ||
+			s.suppressionPolicyPath != current.suppressionPolicyPath ||
+			s.baselineEnabled != current.baselineEnabled ||
+			s.manifestSummary != current.manifestSummary ||
+			s.maxResultAge != current.maxResultAge ||
+			s.minEpssScore != current.minEpssScore ||
+			s.devDependencyIssues != current.devDependencyIssues ||
+			s.issueTagFilter != current.issueTagFilter ||
+			s.showOnlyFixable != current.showOnlyFixable ||
+			s.majorUpgradeFixes != current.majorUpgradeFixes ||
+			s.maxMessageLength != current.maxMessageLength ||
+			s.issueFingerprinting != current.issueFingerprinting ||
+			s.productDisplayOrder != current.productDisplayOrder ||
+			s.projectIssuesTargetFile != current.projectIssuesTargetFile,
+	}
+
+	enabledProducts := map[product.Product]bool{}
+	displayedProducts := map[product.Product]bool{}
+	for issueType, displayable := range current.displayableIssueTypes {
+		if !displayable {
+			continue
+		}
+		displayedProducts[issueTypeProducts[issueType]] = true
+		if !s.displayableIssueTypes[issueType] {
+			enabledProducts[issueTypeProducts[issueType]] = true
+		}
+	}
+	var rerenderedProducts []product.Product
+	for p, rendering := range current.productRendering {
+		if displayedProducts[p] && !enabledProducts[p] && rendering != s.productRendering[p] {
+			rerenderedProducts = append(rerenderedProducts, p)
+		}
+	}
+	if len(rerenderedProducts) > 0 {
+		change.RerenderedProducts = product.SortByDisplayOrder(rerenderedProducts, c.ProductDisplayOrder())
+	}
+	if len(enabledProducts) == 0 {
+		return change
+	}
+	for p := range enabledProducts {
+		change.EnabledProducts = append(change.EnabledProducts, p)
+	}
+	change.EnabledProducts = product.SortByDisplayOrder(change.EnabledProducts, c.ProductDisplayOrder())
+	return change
+}
+
+// HandleConfigChange publishes the cached issues again if a filter changed and scans newly enabled and rerendered
+// products
+func (w *Workspace) HandleConfigChange(ctx context.Context, change ConfigChange) {
+	if change.FilterChanged {
+		log.Debug().Str("method", "HandleConfigChange").Msg("filter changed, publishing cached issues")
+		for _, folder := range w.Folders() {
+			folder.FilterAndPublishCachedDiagnostics("")
+		}
+	}
+
+	scannedProducts := append(append([]product.Product{}, change.EnabledProducts...), change.RerenderedProducts...)
+	if len(scannedProducts) == 0 || !config.CurrentConfig().IsAutoScanEnabled() {
+		return
+	}
+	log.Debug().Str("method", "HandleConfigChange").Interface("enabled", change.EnabledProducts).
+		Interface("rerendered", change.RerenderedProducts).Msg("products enabled or rerendered, scanning")
+	trusted, _ := w.GetFolderTrust()
+	for _, folder := range trusted {
+		go folder.ScanFolder(vulnmap.ContextWithProducts(ctx, scannedProducts))
+	}
+}
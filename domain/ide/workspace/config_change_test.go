@@ -0,0 +1,332 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/converter"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+func Test_ConfigSnapshot_ChangesTo(t *testing.T) {
+	t.Run("severity filter change requires refilter only", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		snapshot := TakeConfigSnapshot(c)
+
+		c.SetSeverityFilter(lsp.NewSeverityFilter(true, false, false, false))
+
+		assert.Equal(t, ConfigChange{FilterChanged: true}, snapshot.ChangesTo(c))
+	})
+
+	t.Run("enabled product requires rescan of the product", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		c.SetVulnmapIacEnabled(false)
+		snapshot := TakeConfigSnapshot(c)
+
+		c.SetVulnmapIacEnabled(true)
+
+		assert.Equal(t, ConfigChange{EnabledProducts: []product.Product{product.ProductInfrastructureAsCode}},
+			snapshot.ChangesTo(c))
+	})
+
+	t.Run("manifest summary change requires refilter only", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		snapshot := TakeConfigSnapshot(c)
+
+		c.SetManifestSummaryEnabled(true)
+
+		assert.Equal(t, ConfigChange{FilterChanged: true}, snapshot.ChangesTo(c))
+	})
+
+	t.Run("max result age change requires refilter only", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		snapshot := TakeConfigSnapshot(c)
+
+		c.SetMaxResultAge(time.Hour)
+
+		assert.Equal(t, ConfigChange{FilterChanged: true}, snapshot.ChangesTo(c))
+	})
+
+	t.Run("dev dependency issues change requires refilter only", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		snapshot := TakeConfigSnapshot(c)
+
+		c.SetDevDependencyIssues(config.DevDependencyIssuesHide)
+
+		assert.Equal(t, ConfigChange{FilterChanged: true}, snapshot.ChangesTo(c))
+	})
+
+	t.Run("fixability filter change requires refilter only", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		snapshot := TakeConfigSnapshot(c)
+
+		c.SetShowOnlyFixable(true)
+
+		assert.Equal(t, ConfigChange{FilterChanged: true}, snapshot.ChangesTo(c))
+	})
+
+	t.Run("major upgrade fixes change requires refilter only", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		snapshot := TakeConfigSnapshot(c)
+
+		c.SetMajorUpgradeFixIncluded(!c.IsMajorUpgradeFixIncluded())
+
+		assert.Equal(t, ConfigChange{FilterChanged: true}, snapshot.ChangesTo(c))
+	})
+
+	t.Run("disabled product requires neither", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		c.SetVulnmapOssEnabled(true)
+		snapshot := TakeConfigSnapshot(c)
+
+		c.SetVulnmapOssEnabled(false)
+
+		assert.Equal(t, ConfigChange{}, snapshot.ChangesTo(c))
+	})
+
+	publishSettings := []struct {
+		name   string
+		change func(c *config.Config)
+	}{
+		{"max message length", func(c *config.Config) { c.SetMaxMessageLength(c.MaxMessageLength() + 10) }},
+		{"issue fingerprinting", func(c *config.Config) { c.SetIssueFingerprinting(!c.IsIssueFingerprintingEnabled()) }},
+		{"product display order", func(c *config.Config) {
+			c.SetProductDisplayOrder([]product.Product{product.ProductInfrastructureAsCode, product.ProductCode,
+				product.ProductOpenSource})
+		}},
+		{"project issues target file", func(c *config.Config) { c.SetProjectIssuesTargetFile("package.json") }},
+	}
+	for _, setting := range publishSettings {
+		t.Run(setting.name+" change requires refilter only", func(t *testing.T) {
+			c := testutil.UnitTest(t)
+			snapshot := TakeConfigSnapshot(c)
+
+			setting.change(c)
+
+			assert.Equal(t, ConfigChange{FilterChanged: true}, snapshot.ChangesTo(c))
+		})
+	}
+
+	renderingSettings := []struct {
+		name    string
+		product product.Product
+		change  func(c *config.Config)
+	}{
+		{"diagnostic format", product.ProductOpenSource, func(c *config.Config) {
+			c.SetFormatFor(config.RenderContextDiagnostic, config.FormatHtml)
+		}},
+		{"hover format", product.ProductInfrastructureAsCode, func(c *config.Config) {
+			c.SetFormatFor(config.RenderContextHover, config.FormatHtml)
+		}},
+		{"open source issue url template", product.ProductOpenSource, func(c *config.Config) {
+			c.SetIssueURLTemplates(map[product.Product]string{product.ProductOpenSource: "https://example.com/{id}"})
+		}},
+		{"link style", product.ProductOpenSource, func(c *config.Config) { c.SetLinkStyle(config.LinkStyleText) }},
+		{"advisory identifier preference", product.ProductOpenSource, func(c *config.Config) {
+			c.SetAdvisoryIdentifierPreference(config.AdvisoryIdentifierPreferenceGhsa)
+		}},
+		{"license severities", product.ProductOpenSource, func(c *config.Config) {
+			c.SetLicenseSeverities(map[string]string{"gpl-3.0": "high"})
+		}},
+		{"exploit severity escalation", product.ProductOpenSource, func(c *config.Config) {
+			c.SetExploitSeverityEscalation(!c.IsExploitSeverityEscalationEnabled())
+		}},
+		{"issue identifier", product.ProductOpenSource, func(c *config.Config) {
+			c.SetIssueIdentifier(config.IssueIdentifierCve)
+		}},
+		{"code issue url template", product.ProductCode, func(c *config.Config) {
+			c.SetIssueURLTemplates(map[product.Product]string{product.ProductCode: "https://example.com/{id}"})
+		}},
+		{"relative file path display", product.ProductCode, func(c *config.Config) {
+			c.SetRelativeFilePathDisplay(!c.IsRelativeFilePathDisplay())
+		}},
+		{"iac issue url template", product.ProductInfrastructureAsCode, func(c *config.Config) {
+			c.SetIssueURLTemplates(map[product.Product]string{product.ProductInfrastructureAsCode: "https://example.com/{id}"})
+		}},
+	}
+	for _, setting := range renderingSettings {
+		t.Run(setting.name+" change requires rescan of the product", func(t *testing.T) {
+			c := testutil.UnitTest(t)
+			c.SetVulnmapOssEnabled(setting.product == product.ProductOpenSource)
+			c.SetVulnmapCodeEnabled(setting.product == product.ProductCode)
+			c.SetVulnmapIacEnabled(setting.product == product.ProductInfrastructureAsCode)
+			snapshot := TakeConfigSnapshot(c)
+
+			setting.change(c)
+
+			assert.Equal(t, ConfigChange{RerenderedProducts: []product.Product{setting.product}},
+				snapshot.ChangesTo(c))
+		})
+	}
+
+	t.Run("rendering change of a disabled product requires neither", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		c.SetVulnmapOssEnabled(false)
+		snapshot := TakeConfigSnapshot(c)
+
+		c.SetLinkStyle(config.LinkStyleText)
+
+		assert.Equal(t, ConfigChange{}, snapshot.ChangesTo(c))
+	})
+}
+
+func Test_HandleConfigChange_FilterChangeRepublishesWithoutScan(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(false)
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewMockNotifier()
+	w := New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	f := NewFolder(t.TempDir(), "dummy", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(f)
+	filePath := filepath.Join(f.path, "package.json")
+	f.documentDiagnosticCache.Store(filePath, []vulnmap.Issue{
+		{ID: "low", AffectedFilePath: filePath, Severity: vulnmap.Low, Product: product.ProductOpenSource},
+	})
+	c.SetSeverityFilter(lsp.NewSeverityFilter(true, true, true, false))
+
+	w.HandleConfigChange(context.Background(), ConfigChange{FilterChanged: true})
+
+	assert.Equal(t, 0, scanner.Calls())
+	assert.Contains(t, notifier.SentMessages(), lsp.PublishDiagnosticsParams{
+		URI:         uri.PathToUri(filePath),
+		Diagnostics: []lsp.Diagnostic{},
+	})
+}
+
+func Test_HandleConfigChange_FilterChangeSendsFilteredIssuesToScanNotifier(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(false)
+	scanner := vulnmap.NewTestScanner()
+	scanNotifier := vulnmap.NewMockScanNotifier()
+	notifier := notification.NewMockNotifier()
+	w := New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), scanNotifier, notifier)
+	f := NewFolder(t.TempDir(), "dummy", scanner, hover.NewFakeHoverService(), scanNotifier, notifier)
+	w.AddFolder(f)
+	filePath := filepath.Join(f.path, "package.json")
+	f.documentDiagnosticCache.Store(filePath, []vulnmap.Issue{
+		{ID: "high", AffectedFilePath: filePath, Severity: vulnmap.High, Product: product.ProductOpenSource},
+		{ID: "low", AffectedFilePath: filePath, Severity: vulnmap.Low, Product: product.ProductOpenSource},
+	})
+
+	c.SetSeverityFilter(lsp.NewSeverityFilter(true, true, true, false))
+	w.HandleConfigChange(context.Background(), ConfigChange{FilterChanged: true})
+	c.SetSeverityFilter(lsp.DefaultSeverityFilter())
+	w.HandleConfigChange(context.Background(), ConfigChange{FilterChanged: true})
+
+	assert.Equal(t, 0, scanner.Calls())
+	successIssues := scanNotifier.SuccessIssues()
+	require.Len(t, successIssues, 2)
+	assert.Len(t, successIssues[0], 1)
+	assert.Equal(t, "high", successIssues[0][0].ID)
+	assert.Len(t, successIssues[1], 2)
+}
+
+func Test_HandleConfigChange_ManifestSummaryReflectsFilteredCounts(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(false)
+	c.SetManifestSummaryEnabled(true)
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewMockNotifier()
+	w := New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	f := NewFolder(t.TempDir(), "dummy", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(f)
+	filePath := filepath.Join(f.path, "package.json")
+	vulnerability := func(id string, severity vulnmap.Severity) vulnmap.Issue {
+		return vulnmap.Issue{ID: id, AffectedFilePath: filePath, Severity: severity, Product: product.ProductOpenSource,
+			IssueType: vulnmap.DependencyVulnerability}
+	}
+	f.documentDiagnosticCache.Store(filePath, []vulnmap.Issue{
+		vulnerability("critical", vulnmap.Critical),
+		vulnerability("high-1", vulnmap.High),
+		vulnerability("high-2", vulnmap.High),
+		vulnerability("low", vulnmap.Low),
+	})
+	lastSummary := func() string {
+		summary := ""
+		for _, msg := range notifier.SentMessages() {
+			params, ok := msg.(lsp.PublishDiagnosticsParams)
+			if !ok || params.URI != uri.PathToUri(filePath) {
+				continue
+			}
+			summary = ""
+			if len(params.Diagnostics) > 0 && params.Diagnostics[0].Code == converter.ManifestSummaryCode {
+				assert.Equal(t, lsp.DiagnosticsSeverityInformation, params.Diagnostics[0].Severity)
+				summary = params.Diagnostics[0].Message
+			}
+		}
+		return summary
+	}
+
+	w.HandleConfigChange(context.Background(), ConfigChange{FilterChanged: true})
+	assert.Equal(t, "4 vulnerabilities (1 critical, 2 high, 1 low)", lastSummary())
+
+	c.SetSeverityFilter(lsp.NewSeverityFilter(true, false, true, false))
+	w.HandleConfigChange(context.Background(), ConfigChange{FilterChanged: true})
+	assert.Equal(t, "1 vulnerability (1 critical)", lastSummary())
+
+	c.SetManifestSummaryEnabled(false)
+	w.HandleConfigChange(context.Background(), ConfigChange{FilterChanged: true})
+	assert.Empty(t, lastSummary())
+}
+
+func Test_HandleConfigChange_EnabledProductTriggersScan(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(false)
+	c.SetAutomaticScanning(true)
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewNotifier()
+	w := New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(NewFolder(t.TempDir(), "dummy", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier))
+
+	w.HandleConfigChange(context.Background(), ConfigChange{EnabledProducts: []product.Product{product.ProductOpenSource}})
+
+	assert.Eventually(t, func() bool {
+		return scanner.Calls() == 1
+	}, time.Second, time.Millisecond)
+}
+
+func Test_HandleConfigChange_RerenderedProductTriggersScan(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(false)
+	c.SetAutomaticScanning(true)
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewNotifier()
+	w := New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(NewFolder(t.TempDir(), "dummy", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier))
+
+	w.HandleConfigChange(context.Background(),
+		ConfigChange{RerenderedProducts: []product.Product{product.ProductOpenSource}})
+
+	assert.Eventually(t, func() bool {
+		return scanner.Calls() == 1
+	}, time.Second, time.Millisecond)
+}
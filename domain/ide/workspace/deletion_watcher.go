@@ -0,0 +1,201 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"io/fs"
+	goos "os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+var (
+	// deletionWatcherDebounce is the time without further deletions after which the diagnostics of deleted files
+	// are cleared, so that e.g. a branch checkout clears all diagnostics at once
+	deletionWatcherDebounce = 500 * time.Millisecond
+
+	// maxWatchedDirectories bounds the number of directories that are watched per folder
+	maxWatchedDirectories = 1000
+)
+
+// deletionWatcher watches the directories of a folder and clears the diagnostics of files that are deleted outside
+// the IDE, e.g. by checking out a branch without them
+type deletionWatcher struct {
+	folder       *Folder
+	watcher      *fsnotify.Watcher
+	mutex        sync.Mutex
+	deletedPaths map[string]bool
+	timer        *time.Timer
+	debounce     time.Duration
+	limitLogged  bool
+}
+
+// startDeletionWatcher starts watching the folder for deleted files, if enabled and the folder is trusted. The
+// watcher is stopped by stopDeletionWatcher when the scans of the folder are stopped.
+func (f *Folder) startDeletionWatcher() {
+	const method = "domain.ide.workspace.folder.startDeletionWatcher"
+	if !config.CurrentConfig().IsFileDeletionWatcherEnabled() || f.stopCtx.Err() != nil || !f.IsTrusted() {
+		return
+	}
+
+	f.mutex.Lock()
+	if f.deletionWatcher != nil {
+		f.mutex.Unlock()
+		return
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		f.mutex.Unlock()
+		log.Warn().Err(err).Str("method", method).Str("folder", f.path).Msg("couldn't create file system watcher")
+		return
+	}
+	w := &deletionWatcher{folder: f, watcher: watcher, deletedPaths: map[string]bool{}, debounce: deletionWatcherDebounce}
+	f.deletionWatcher = w
+	f.mutex.Unlock()
+
+	w.watchDirectories(f.path)
+	go w.run(f.stopCtx)
+}
+
+// watchDirectories adds the given directory and its subdirectories to the watcher, skipping excluded directories
+// and stopping once maxWatchedDirectories are watched
+func (w *deletionWatcher) watchDirectories(root string) {
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != w.folder.path && (d.Name() == ".git" || w.folder.isExcluded(path)) {
+			return filepath.SkipDir
+		}
+
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		if len(w.watcher.WatchList()) >= maxWatchedDirectories {
+			if !w.limitLogged {
+				log.Warn().Str("method", "deletionWatcher.watchDirectories").Str("folder", w.folder.path).
+					Msgf("watching at most %d directories, deletions in other directories are not detected",
+						maxWatchedDirectories)
+				w.limitLogged = true
+			}
+			return filepath.SkipAll
+		}
+		if err = w.watcher.Add(path); err != nil {
+			log.Debug().Err(err).Str("method", "deletionWatcher.watchDirectories").Str("path", path).
+				Msg("couldn't watch directory")
+		}
+		return nil
+	})
+}
+
+func (w *deletionWatcher) run(ctx context.Context) {
+	defer w.close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(event)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Debug().Err(err).Str("method", "deletionWatcher.run").Str("folder", w.folder.path).
+				Msg("file system watcher error")
+		}
+	}
+}
+
+func (w *deletionWatcher) handleEvent(event fsnotify.Event) {
+	if event.Has(fsnotify.Create) {
+		// watch directories that are created e.g. by switching back to a branch
+		w.watchDirectories(event.Name)
+	}
+	if !event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.deletedPaths[event.Name] = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(w.debounce, w.clearDeletedPaths)
+}
+
+// clearDeletedPaths clears the diagnostics of the deleted files and of the files in deleted directories. Paths that
+// exist again are skipped, because editors save atomically by renaming a temporary file over the saved one.
+func (w *deletionWatcher) clearDeletedPaths() {
+	w.mutex.Lock()
+	deletedPaths := w.deletedPaths
+	w.deletedPaths = map[string]bool{}
+	w.mutex.Unlock()
+
+	for path := range deletedPaths {
+		if _, err := goos.Stat(path); err == nil {
+			delete(deletedPaths, path)
+		}
+	}
+
+	f := w.folder
+	f.documentDiagnosticCache.Range(func(filePath string, _ []vulnmap.Issue) bool {
+		for path := filePath; f.Contains(path); path = filepath.Dir(path) {
+			if deletedPaths[path] {
+				log.Debug().Str("method", "deletionWatcher.clearDeletedPaths").Str("path", filePath).
+					Msg("clearing diagnostics of deleted file")
+				f.ClearDiagnosticsFromFile(filePath)
+				break
+			}
+			if path == filepath.Dir(path) {
+				break
+			}
+		}
+		return true
+	})
+}
+
+// stopDeletionWatcher closes the watcher of the folder and discards the deletions waiting for the debounce, so that
+// a removed folder doesn't keep watching its directories
+func (f *Folder) stopDeletionWatcher() {
+	f.mutex.Lock()
+	w := f.deletionWatcher
+	f.deletionWatcher = nil
+	f.mutex.Unlock()
+	if w != nil {
+		w.close()
+	}
+}
+
+func (w *deletionWatcher) close() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	_ = w.watcher.Close()
+}
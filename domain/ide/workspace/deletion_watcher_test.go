@@ -0,0 +1,142 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	goos "os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func setUpWatchedFolder(t *testing.T, filePaths ...string) *Folder {
+	t.Helper()
+	c := testutil.UnitTest(t)
+	c.SetFileDeletionWatcherEnabled(true)
+	previousDebounce := deletionWatcherDebounce
+	deletionWatcherDebounce = 10 * time.Millisecond
+	t.Cleanup(func() { deletionWatcherDebounce = previousDebounce })
+
+	folderPath := t.TempDir()
+	scanner := vulnmap.NewTestScanner()
+	for _, filePath := range filePaths {
+		testutil.CreateFileOrFail(t, filepath.Join(folderPath, filePath), []byte("content"))
+		scanner.AddTestIssue(NewMockIssue(filePath, filepath.Join(folderPath, filePath)))
+	}
+	f := NewFolder(folderPath, "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	t.Cleanup(f.StopScans)
+	f.ScanFolder(context.Background())
+	return f
+}
+
+func Test_DeletionWatcher_DeletedFileClearsItsDiagnostics(t *testing.T) {
+	f := setUpWatchedFolder(t, "deleted.txt", "kept.txt")
+	deletedFile := filepath.Join(f.Path(), "deleted.txt")
+	require.NotEmpty(t, f.DocumentDiagnosticsFromCache(deletedFile))
+
+	require.NoError(t, goos.Remove(deletedFile))
+
+	assert.Eventually(t, func() bool {
+		_, found := f.documentDiagnosticCache.Load(deletedFile)
+		return !found
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.NotEmpty(t, f.DocumentDiagnosticsFromCache(filepath.Join(f.Path(), "kept.txt")))
+}
+
+func Test_DeletionWatcher_AtomicSaveKeepsDiagnostics(t *testing.T) {
+	f := setUpWatchedFolder(t, "saved.txt")
+	savedFile := filepath.Join(f.Path(), "saved.txt")
+	backupFile := savedFile + "~"
+	tempFile := savedFile + ".tmp"
+	testutil.CreateFileOrFail(t, tempFile, []byte("saved content"))
+
+	require.NoError(t, goos.Rename(savedFile, backupFile))
+	require.NoError(t, goos.Rename(tempFile, savedFile))
+	require.NoError(t, goos.Remove(backupFile))
+
+	assert.Never(t, func() bool {
+		_, found := f.documentDiagnosticCache.Load(savedFile)
+		return !found
+	}, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func Test_DeletionWatcher_DeletedDirectoryClearsDiagnosticsBelow(t *testing.T) {
+	f := setUpWatchedFolder(t, filepath.Join("dir", "sub", "deleted.txt"), "kept.txt")
+	deletedFile := filepath.Join(f.Path(), "dir", "sub", "deleted.txt")
+
+	require.NoError(t, goos.RemoveAll(filepath.Join(f.Path(), "dir")))
+
+	assert.Eventually(t, func() bool {
+		_, found := f.documentDiagnosticCache.Load(deletedFile)
+		return !found
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.NotEmpty(t, f.DocumentDiagnosticsFromCache(filepath.Join(f.Path(), "kept.txt")))
+}
+
+func Test_DeletionWatcher_SkipsExcludedDirectoriesAndIsBounded(t *testing.T) {
+	testutil.UnitTest(t)
+	previousMax := maxWatchedDirectories
+	maxWatchedDirectories = 3
+	t.Cleanup(func() { maxWatchedDirectories = previousMax })
+	folderPath := t.TempDir()
+	for _, dir := range []string{filepath.Join("node_modules", "lib"), ".git", "a", "b", "c"} {
+		require.NoError(t, goos.MkdirAll(filepath.Join(folderPath, dir), 0700))
+	}
+	f := NewFolder(folderPath, "Test", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	f.SetConfigOverlay(&vulnmap.FolderConfig{ExcludePatterns: []string{"node_modules"}})
+	watcher, err := fsnotify.NewWatcher()
+	require.NoError(t, err)
+	w := &deletionWatcher{folder: f, watcher: watcher, deletedPaths: map[string]bool{}}
+	defer w.close()
+
+	w.watchDirectories(f.Path())
+
+	assert.ElementsMatch(t, []string{folderPath, filepath.Join(folderPath, "a"), filepath.Join(folderPath, "b")},
+		w.watcher.WatchList())
+}
+
+func Test_DeletionWatcher_NotStartedWhenDisabled(t *testing.T) {
+	testutil.UnitTest(t)
+	f := NewFolder(t.TempDir(), "Test", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	t.Cleanup(f.StopScans)
+
+	f.ScanFolder(context.Background())
+
+	assert.Nil(t, f.deletionWatcher)
+}
+
+func Test_DeletionWatcher_StoppedWithTheScansOfTheFolder(t *testing.T) {
+	f := setUpWatchedFolder(t, "file.txt")
+	w := f.deletionWatcher
+	require.NotNil(t, w)
+	require.NotEmpty(t, w.watcher.WatchList())
+
+	f.StopScans()
+
+	assert.Nil(t, f.deletionWatcher)
+	assert.Error(t, w.watcher.Add(f.Path()), "the watcher is closed")
+}
@@ -0,0 +1,95 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"sort"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// DependencyNode is a package of the dependency tree of a folder, e.g. "lodash@4.17.20"
+type DependencyNode struct {
+	Package string
+	// Vulnerabilities are the issues affecting the package itself
+	Vulnerabilities []vulnmap.Issue
+	// Severity is the highest severity of the vulnerabilities of the package and its dependencies
+	Severity     vulnmap.Severity
+	Dependencies []*DependencyNode
+}
+
+// DependencyTree returns the dependency paths of the filtered open source issues of the folder merged into a tree
+// per project. Only packages that lead to a vulnerability are contained, as the scan results don't include the others.
+// Packages occurring several times in a path, i.e. cyclic dependencies, end the path at their first occurrence.
+func (f *Folder) DependencyTree() []*DependencyNode {
+	root := &DependencyNode{}
+	for _, issue := range f.FilteredIssues() {
+		data, isOss := issue.AdditionalData.(vulnmap.OssIssueData)
+		if !isOss || len(data.From) == 0 {
+			continue
+		}
+		node := root
+		visited := map[string]bool{}
+		for _, pkg := range data.From {
+			if visited[pkg] {
+				break
+			}
+			visited[pkg] = true
+			node = node.dependency(pkg)
+		}
+		node.addVulnerability(issue)
+	}
+	root.rollUpSeverity()
+	return root.Dependencies
+}
+
+func (n *DependencyNode) dependency(pkg string) *DependencyNode {
+	for _, dependency := range n.Dependencies {
+		if dependency.Package == pkg {
+			return dependency
+		}
+	}
+	dependency := &DependencyNode{Package: pkg}
+	n.Dependencies = append(n.Dependencies, dependency)
+	return dependency
+}
+
+// addVulnerability adds the issue unless it was already added, e.g. because it was reported for several files
+func (n *DependencyNode) addVulnerability(issue vulnmap.Issue) {
+	for _, vulnerability := range n.Vulnerabilities {
+		if vulnerability.ID == issue.ID {
+			return
+		}
+	}
+	n.Vulnerabilities = append(n.Vulnerabilities, issue)
+}
+
+// rollUpSeverity sets the severities of the node and its dependencies and sorts the dependencies by name
+func (n *DependencyNode) rollUpSeverity() vulnmap.Severity {
+	// more severe severities have lower values
+	n.Severity = vulnmap.Unknown
+	for _, vulnerability := range n.Vulnerabilities {
+		n.Severity = min(n.Severity, vulnerability.Severity)
+	}
+	for _, dependency := range n.Dependencies {
+		n.Severity = min(n.Severity, dependency.rollUpSeverity())
+	}
+	sort.Slice(n.Dependencies, func(i, j int) bool {
+		return n.Dependencies[i].Package < n.Dependencies[j].Package
+	})
+	return n.Severity
+}
@@ -0,0 +1,123 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func newDependencyTreeTestIssue(id string, path string, severity vulnmap.Severity, from ...string) vulnmap.Issue {
+	return vulnmap.Issue{
+		ID:               id,
+		AffectedFilePath: path,
+		Product:          product.ProductOpenSource,
+		Severity:         severity,
+		AdditionalData:   vulnmap.OssIssueData{Key: id, From: from},
+	}
+}
+
+func scanDependencyTreeFolder(t *testing.T, issues ...vulnmap.Issue) *Folder {
+	t.Helper()
+	scanner := vulnmap.NewTestScanner()
+	for _, issue := range issues {
+		scanner.AddTestIssue(issue)
+	}
+	f := NewFolder(t.TempDir(), "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	t.Cleanup(f.StopScans)
+	f.ScanFolder(context.Background())
+	return f
+}
+
+func Test_DependencyTree_AnnotatesVulnerablePackagesAndRollsUpSeverity(t *testing.T) {
+	testutil.UnitTest(t)
+	manifest := filepath.Join(t.TempDir(), "package.json")
+	f := scanDependencyTreeFolder(t,
+		newDependencyTreeTestIssue("lodash-vuln", manifest, vulnmap.Low, "app@1.0.0", "express@4.0.0", "lodash@4.17.20"),
+		newDependencyTreeTestIssue("qs-vuln", manifest, vulnmap.Critical, "app@1.0.0", "express@4.0.0", "qs@6.0.0"),
+		newDependencyTreeTestIssue("express-vuln", manifest, vulnmap.Medium, "app@1.0.0", "express@4.0.0"),
+		newDependencyTreeTestIssue("minimist-vuln", manifest, vulnmap.High, "app@1.0.0", "minimist@1.0.0"),
+	)
+
+	tree := f.DependencyTree()
+
+	require.Len(t, tree, 1)
+	app := tree[0]
+	assert.Equal(t, "app@1.0.0", app.Package)
+	assert.Equal(t, vulnmap.Critical, app.Severity)
+	assert.Empty(t, app.Vulnerabilities)
+	require.Len(t, app.Dependencies, 2)
+
+	express := app.Dependencies[0]
+	assert.Equal(t, "express@4.0.0", express.Package)
+	assert.Equal(t, vulnmap.Critical, express.Severity)
+	require.Len(t, express.Vulnerabilities, 1)
+	assert.Equal(t, "express-vuln", express.Vulnerabilities[0].ID)
+	require.Len(t, express.Dependencies, 2)
+	assert.Equal(t, "lodash@4.17.20", express.Dependencies[0].Package)
+	assert.Equal(t, vulnmap.Low, express.Dependencies[0].Severity)
+	assert.Equal(t, "qs@6.0.0", express.Dependencies[1].Package)
+	assert.Equal(t, vulnmap.Critical, express.Dependencies[1].Severity)
+
+	minimist := app.Dependencies[1]
+	assert.Equal(t, "minimist@1.0.0", minimist.Package)
+	assert.Equal(t, vulnmap.High, minimist.Severity)
+}
+
+func Test_DependencyTree_DuplicatedPathsAreMerged(t *testing.T) {
+	testutil.UnitTest(t)
+	dir := t.TempDir()
+	f := scanDependencyTreeFolder(t,
+		newDependencyTreeTestIssue("lodash-vuln", filepath.Join(dir, "package.json"), vulnmap.High, "app@1.0.0", "lodash@4.17.20"),
+		newDependencyTreeTestIssue("lodash-vuln", filepath.Join(dir, "package-lock.json"), vulnmap.High, "app@1.0.0", "lodash@4.17.20"),
+	)
+
+	tree := f.DependencyTree()
+
+	require.Len(t, tree, 1)
+	require.Len(t, tree[0].Dependencies, 1)
+	assert.Len(t, tree[0].Dependencies[0].Vulnerabilities, 1)
+}
+
+func Test_DependencyTree_CyclicPathsEndAtTheRepeatedPackage(t *testing.T) {
+	testutil.UnitTest(t)
+	manifest := filepath.Join(t.TempDir(), "package.json")
+	f := scanDependencyTreeFolder(t,
+		newDependencyTreeTestIssue("cycle-vuln", manifest, vulnmap.Medium, "app@1.0.0", "a@1.0.0", "b@1.0.0", "a@1.0.0", "c@1.0.0"),
+	)
+
+	tree := f.DependencyTree()
+
+	require.Len(t, tree, 1)
+	a := tree[0].Dependencies[0]
+	require.Len(t, a.Dependencies, 1)
+	b := a.Dependencies[0]
+	assert.Equal(t, "b@1.0.0", b.Package)
+	assert.Empty(t, b.Dependencies)
+	assert.Equal(t, "cycle-vuln", b.Vulnerabilities[0].ID)
+	assert.Equal(t, vulnmap.Medium, tree[0].Severity)
+}
@@ -0,0 +1,66 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	noti "github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+// DiagnosticsSink receives the diagnostics a folder publishes for a file. Empty issues and diagnostics clear the
+// previously published diagnostics of the file. The diagnostics of different files may be sent concurrently.
+type DiagnosticsSink interface {
+	SendDiagnostics(filePath string, issues []vulnmap.Issue, diagnostics []lsp.Diagnostic)
+}
+
+// DiagnosticsSinkFunc adapts a function to a DiagnosticsSink
+type DiagnosticsSinkFunc func(filePath string, issues []vulnmap.Issue, diagnostics []lsp.Diagnostic)
+
+func (f DiagnosticsSinkFunc) SendDiagnostics(filePath string, issues []vulnmap.Issue, diagnostics []lsp.Diagnostic) {
+	f(filePath, issues, diagnostics)
+}
+
+type notifierDiagnosticsSink struct {
+	notifier noti.Notifier
+}
+
+// NewNotifierDiagnosticsSink returns the default sink, publishing the diagnostics to the client as LSP notifications
+func NewNotifierDiagnosticsSink(notifier noti.Notifier) DiagnosticsSink {
+	return &notifierDiagnosticsSink{notifier: notifier}
+}
+
+func (s *notifierDiagnosticsSink) SendDiagnostics(filePath string, _ []vulnmap.Issue, diagnostics []lsp.Diagnostic) {
+	s.notifier.Send(lsp.PublishDiagnosticsParams{
+		URI:         uri.PathToUri(filePath),
+		Diagnostics: diagnostics,
+	})
+}
+
+// SetDiagnosticsSink replaces the sink the folder publishes its diagnostics to
+func (f *Folder) SetDiagnosticsSink(sink DiagnosticsSink) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.diagnosticsSink = sink
+}
+
+func (f *Folder) sink() DiagnosticsSink {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.diagnosticsSink
+}
@@ -0,0 +1,192 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+type capturedDiagnostics struct {
+	issues      []vulnmap.Issue
+	diagnostics []lsp.Diagnostic
+}
+
+type capturingSink struct {
+	mutex sync.Mutex
+	sent  map[string]capturedDiagnostics
+}
+
+func (s *capturingSink) SendDiagnostics(filePath string, issues []vulnmap.Issue, diagnostics []lsp.Diagnostic) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sent[filePath] = capturedDiagnostics{issues: issues, diagnostics: diagnostics}
+}
+
+func (s *capturingSink) last(filePath string) (capturedDiagnostics, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	captured, ok := s.sent[filePath]
+	return captured, ok
+}
+
+func Test_DiagnosticsSink_ReceivesPublishedAndClearedDiagnostics(t *testing.T) {
+	testutil.UnitTest(t)
+	notifier := notification.NewMockNotifier()
+	f := NewFolder(t.TempDir(), "Test", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	sink := &capturingSink{sent: map[string]capturedDiagnostics{}}
+	f.SetDiagnosticsSink(sink)
+	filePath := filepath.Join(f.path, "package.json")
+	issue := NewMockIssue("1", filePath)
+
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{issue}})
+
+	captured, ok := sink.last(filePath)
+	assert.True(t, ok)
+	assert.Equal(t, []vulnmap.Issue{issue}, captured.issues)
+	assert.Len(t, captured.diagnostics, 1)
+	assert.Equal(t, issue.Message, captured.diagnostics[0].Message)
+	for _, msg := range notifier.SentMessages() {
+		_, isDiagnostics := msg.(lsp.PublishDiagnosticsParams)
+		assert.False(t, isDiagnostics, "a configured sink replaces the LSP notifications")
+	}
+
+	f.ClearDiagnostics()
+
+	captured, _ = sink.last(filePath)
+	assert.Empty(t, captured.issues)
+	assert.Empty(t, captured.diagnostics)
+}
+
+func Test_NewNotifierDiagnosticsSink_PublishesDiagnosticsNotification(t *testing.T) {
+	notifier := notification.NewMockNotifier()
+	sink := NewNotifierDiagnosticsSink(notifier)
+	diagnostics := []lsp.Diagnostic{{Message: "diagnostic"}}
+
+	sink.SendDiagnostics("/folder/package.json", nil, diagnostics)
+
+	assert.Equal(t, []any{lsp.PublishDiagnosticsParams{
+		URI:         uri.PathToUri("/folder/package.json"),
+		Diagnostics: diagnostics,
+	}}, notifier.SentMessages())
+}
+
+func Test_DiagnosticsSinkFunc_CallsFunction(t *testing.T) {
+	var receivedPath string
+	sink := DiagnosticsSinkFunc(func(filePath string, _ []vulnmap.Issue, _ []lsp.Diagnostic) {
+		receivedPath = filePath
+	})
+
+	sink.SendDiagnostics("/folder/package.json", nil, nil)
+
+	assert.Equal(t, "/folder/package.json", receivedPath)
+}
+
+// concurrencyTrackingSink records how many files' diagnostics were sent at the same time at most
+type concurrencyTrackingSink struct {
+	mutex         sync.Mutex
+	running       int
+	maxConcurrent int
+	sent          map[string]int
+}
+
+func (s *concurrencyTrackingSink) SendDiagnostics(filePath string, _ []vulnmap.Issue, _ []lsp.Diagnostic) {
+	s.mutex.Lock()
+	s.running++
+	s.maxConcurrent = max(s.maxConcurrent, s.running)
+	s.sent[filePath]++
+	s.mutex.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	s.mutex.Lock()
+	s.running--
+	s.mutex.Unlock()
+}
+
+func issuesForFiles(folderPath string, fileCount int) map[string][]vulnmap.Issue {
+	issuesByFile := map[string][]vulnmap.Issue{}
+	for i := 0; i < fileCount; i++ {
+		filePath := filepath.Join(folderPath, fmt.Sprintf("file-%d.js", i))
+		issuesByFile[filePath] = []vulnmap.Issue{NewMockIssue(strconv.Itoa(i), filePath)}
+	}
+	return issuesByFile
+}
+
+func Test_sendDiagnostics_ManyFilesArePublishedInParallel(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetDiagnosticsPublishConcurrency(4)
+	f := NewFolder(t.TempDir(), "Test", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewMockNotifier())
+	sink := &concurrencyTrackingSink{sent: map[string]int{}}
+	f.SetDiagnosticsSink(sink)
+	issuesByFile := issuesForFiles(f.path, 2*parallelDiagnosticsThreshold)
+
+	f.sendDiagnostics(issuesByFile)
+
+	assert.Len(t, sink.sent, len(issuesByFile))
+	for filePath := range issuesByFile {
+		assert.Equal(t, 1, sink.sent[filePath])
+	}
+	assert.Greater(t, sink.maxConcurrent, 1)
+	assert.LessOrEqual(t, sink.maxConcurrent, 4)
+}
+
+func Test_sendDiagnostics_FewFilesArePublishedSequentially(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetDiagnosticsPublishConcurrency(4)
+	f := NewFolder(t.TempDir(), "Test", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewMockNotifier())
+	sink := &concurrencyTrackingSink{sent: map[string]int{}}
+	f.SetDiagnosticsSink(sink)
+
+	f.sendDiagnostics(issuesForFiles(f.path, parallelDiagnosticsThreshold-1))
+
+	assert.Len(t, sink.sent, parallelDiagnosticsThreshold-1)
+	assert.Equal(t, 1, sink.maxConcurrent)
+}
+
+func Benchmark_sendDiagnostics_10kFiles(b *testing.B) {
+	for _, concurrency := range []int{1, config.DefaultDiagnosticsPublishConcurrency} {
+		b.Run(fmt.Sprintf("concurrency %d", concurrency), func(b *testing.B) {
+			c := config.New()
+			c.SetDiagnosticsPublishConcurrency(concurrency)
+			config.SetCurrentConfig(c)
+			folderPath := b.TempDir()
+			f := NewFolder(folderPath, "Test", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewMockNotifier())
+			issuesByFile := issuesForFiles(folderPath, 10000)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				f.sendDiagnostics(issuesByFile)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"sort"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// PreviewedFilter is a hypothetical change of the issue filter. Nil fields keep the current setting.
+type PreviewedFilter struct {
+	SeverityFilter *lsp.SeverityFilter
+	IssueTypes     map[product.FilterableIssueType]bool
+	// MinCvssScore hides open source issues below the CVSS score, 0 doesn't hide any issue
+	MinCvssScore float64
+}
+
+// FilterPreview lists the currently visible issues that the previewed filter would hide and the hidden issues it
+// would show
+type FilterPreview struct {
+	Hidden []vulnmap.Issue
+	Shown  []vulnmap.Issue
+}
+
+// PreviewFilter compares the cached issues of all folders that are visible with the current filter to those that
+// would be visible with the previewed filter. Neither the config nor the published diagnostics are changed.
+func (w *Workspace) PreviewFilter(previewed PreviewedFilter) FilterPreview {
+	c := config.CurrentConfig()
+	preview := FilterPreview{}
+	for _, folder := range w.Folders() {
+		current := newIssueFilter(c, folder.path, c.DisplayableIssueTypes())
+		hypothetical := *current
+		if previewed.SeverityFilter != nil {
+			hypothetical.severityFilter = *previewed.SeverityFilter
+		}
+		if previewed.IssueTypes != nil {
+			hypothetical.supportedIssueTypes = previewed.IssueTypes
+		}
+		hypothetical.minCvssScore = previewed.MinCvssScore
+		folder.documentDiagnosticCache.Range(func(_ string, issues []vulnmap.Issue) bool {
+			visibleNow := folder.uniqueIssueIDs(current.filter(issues))
+			visibleThen := folder.uniqueIssueIDs(hypothetical.filter(issues))
+			for _, issue := range issues {
+				id := folder.getUniqueIssueID(issue)
+				switch {
+				case visibleNow[id] && !visibleThen[id]:
+					preview.Hidden = append(preview.Hidden, issue)
+				case !visibleNow[id] && visibleThen[id]:
+					preview.Shown = append(preview.Shown, issue)
+				}
+			}
+			return true
+		})
+	}
+	sortIssuesByLocation(preview.Hidden)
+	sortIssuesByLocation(preview.Shown)
+	return preview
+}
+
+func (f *Folder) uniqueIssueIDs(issues []vulnmap.Issue) map[string]bool {
+	ids := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		ids[f.getUniqueIssueID(issue)] = true
+	}
+	return ids
+}
+
+func sortIssuesByLocation(issues []vulnmap.Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].AffectedFilePath != issues[j].AffectedFilePath {
+			return issues[i].AffectedFilePath < issues[j].AffectedFilePath
+		}
+		if issues[i].Range.Start.Line != issues[j].Range.Start.Line {
+			return issues[i].Range.Start.Line < issues[j].Range.Start.Line
+		}
+		return issues[i].ID < issues[j].ID
+	})
+}
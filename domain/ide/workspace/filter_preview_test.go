@@ -0,0 +1,105 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func newCvssIssue(id string, severity vulnmap.Severity, cvssScore float64) vulnmap.Issue {
+	issue := NewMockIssueWithSeverity(id, "package.json", severity)
+	issue.AdditionalData = vulnmap.OssIssueData{Key: id, CvssScore: cvssScore}
+	return issue
+}
+
+func visibleIssueIDs(w *Workspace) []string {
+	var ids []string
+	for _, folder := range w.Folders() {
+		folder.documentDiagnosticCache.Range(func(_ string, issues []vulnmap.Issue) bool {
+			for _, issue := range FilterIssues("", issues, config.CurrentConfig().DisplayableIssueTypes()) {
+				ids = append(ids, issue.ID)
+			}
+			return true
+		})
+	}
+	return ids
+}
+
+func Test_PreviewFilter_MatchesApplyingTheFilter(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetSeverityFilter(lsp.NewSeverityFilter(true, true, false, false))
+	w := setupSearchWorkspace(t,
+		newCvssIssue("critical-high-cvss", vulnmap.Critical, 9.8),
+		newCvssIssue("high-low-cvss", vulnmap.High, 5.3),
+		newCvssIssue("medium-high-cvss", vulnmap.Medium, 7.5),
+		newCvssIssue("low-low-cvss", vulnmap.Low, 3.1),
+	)
+	visibleBefore := visibleIssueIDs(w)
+	severityFilter := lsp.NewSeverityFilter(true, true, true, false)
+
+	preview := w.PreviewFilter(PreviewedFilter{SeverityFilter: &severityFilter, MinCvssScore: 7})
+
+	assert.Equal(t, []string{"high-low-cvss"}, issueIds(preview.Hidden))
+	assert.Equal(t, []string{"medium-high-cvss"}, issueIds(preview.Shown))
+	// the preview doesn't change the config
+	assert.Equal(t, lsp.NewSeverityFilter(true, true, false, false), c.FilterSeverity())
+	assert.ElementsMatch(t, visibleBefore, visibleIssueIDs(w))
+
+	// the filter is applied, but min CVSS scores can only be previewed, so only the severity change is compared
+	severityOnlyPreview := w.PreviewFilter(PreviewedFilter{SeverityFilter: &severityFilter})
+	c.SetSeverityFilter(severityFilter)
+	visibleAfter := visibleIssueIDs(w)
+	assert.Len(t, visibleAfter, len(visibleBefore)-len(severityOnlyPreview.Hidden)+len(severityOnlyPreview.Shown))
+	assert.ElementsMatch(t, []string{"critical-high-cvss", "high-low-cvss", "medium-high-cvss"}, visibleAfter)
+}
+
+func Test_PreviewFilter_IssueTypes(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetVulnmapCodeEnabled(false)
+	codeIssue := NewMockIssue("code", "main.go")
+	codeIssue.Product = product.ProductCode
+	codeIssue.IssueType = vulnmap.CodeSecurityVulnerability
+	w := setupSearchWorkspace(t, NewMockIssue("oss", "package.json"), codeIssue)
+
+	preview := w.PreviewFilter(PreviewedFilter{
+		IssueTypes: map[product.FilterableIssueType]bool{product.FilterableIssueTypeCodeSecurity: true},
+	})
+
+	require.Len(t, preview.Hidden, 1)
+	assert.Equal(t, "oss", preview.Hidden[0].ID)
+	require.Len(t, preview.Shown, 1)
+	assert.Equal(t, "code", preview.Shown[0].ID)
+}
+
+func Test_PreviewFilter_UnchangedFilterHidesNothing(t *testing.T) {
+	testutil.UnitTest(t)
+	w := setupSearchWorkspace(t, newCvssIssue("issue", vulnmap.High, 5))
+
+	preview := w.PreviewFilter(PreviewedFilter{})
+
+	assert.Empty(t, preview.Hidden)
+	assert.Empty(t, preview.Shown)
+}
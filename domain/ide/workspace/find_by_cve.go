@@ -0,0 +1,70 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// CVEOccurrence is an issue of a workspace folder that is affected by a CVE
+type CVEOccurrence struct {
+	FolderPath string
+	Issue      vulnmap.Issue
+}
+
+// FindByCVE returns the cached issues of all folders that reference the CVE, including issues hidden by the current
+// filters, so that an advisory can't be overlooked. The CVE has to match exactly, ignoring case.
+func (w *Workspace) FindByCVE(cve string) []CVEOccurrence {
+	cve = strings.TrimSpace(cve)
+	occurrences := []CVEOccurrence{}
+	if cve == "" {
+		return occurrences
+	}
+	for _, folder := range w.Folders() {
+		folder.documentDiagnosticCache.Range(func(_ string, issues []vulnmap.Issue) bool {
+			for _, issue := range issues {
+				if hasCVE(issue, cve) {
+					occurrences = append(occurrences, CVEOccurrence{FolderPath: folder.Path(), Issue: issue})
+				}
+			}
+			return true
+		})
+	}
+	sort.Slice(occurrences, func(i, j int) bool {
+		a, b := occurrences[i], occurrences[j]
+		if a.FolderPath != b.FolderPath {
+			return a.FolderPath < b.FolderPath
+		}
+		if a.Issue.AffectedFilePath != b.Issue.AffectedFilePath {
+			return a.Issue.AffectedFilePath < b.Issue.AffectedFilePath
+		}
+		return a.Issue.Range.Start.Line < b.Issue.Range.Start.Line
+	})
+	return occurrences
+}
+
+func hasCVE(issue vulnmap.Issue, cve string) bool {
+	for _, issueCVE := range issue.CVEs {
+		if strings.EqualFold(strings.TrimSpace(issueCVE), cve) {
+			return true
+		}
+	}
+	return false
+}
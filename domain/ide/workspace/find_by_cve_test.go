@@ -0,0 +1,88 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func addFolderWithIssues(w *Workspace, path string, issues ...vulnmap.Issue) {
+	f := NewFolder(path, path, vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), w.notifier)
+	for _, issue := range issues {
+		cachedIssues, _ := f.documentDiagnosticCache.Load(issue.AffectedFilePath)
+		f.documentDiagnosticCache.Store(issue.AffectedFilePath, append(cachedIssues, issue))
+	}
+	w.AddFolder(f)
+}
+
+func newVersionedOssIssue(id string, path string, packageName string, version string, cves ...string) vulnmap.Issue {
+	issue := newOssIssue(id, path, vulnmap.Critical, packageName, cves...)
+	data := issue.AdditionalData.(vulnmap.OssIssueData)
+	data.Version = version
+	issue.AdditionalData = data
+	return issue
+}
+
+func Test_FindByCVE_ReturnsAllOccurrencesAcrossFolders(t *testing.T) {
+	c := testutil.UnitTest(t)
+	// hidden issues must be found, too
+	c.SetSeverityFilter(lsp.NewSeverityFilter(false, true, true, true))
+	w := New(performance.NewInstrumentor(), vulnmap.NewTestScanner(), nil, nil, notification.NewNotifier())
+	addFolderWithIssues(w, "/backend",
+		newVersionedOssIssue("VULNMAP-JAVA-LOG4J-1", "/backend/pom.xml", "log4j-core", "2.14.1", "CVE-2021-44228"),
+		newVersionedOssIssue("VULNMAP-JAVA-LOG4J-2", "/backend/pom.xml", "log4j-core", "2.14.1", "CVE-2021-45046"),
+	)
+	addFolderWithIssues(w, "/service",
+		newVersionedOssIssue("VULNMAP-JAVA-LOG4J-1", "/service/build.gradle", "log4j-core", "2.13.0", "CVE-2021-44228"),
+		newVersionedOssIssue("VULNMAP-JAVA-LOG4J-1", "/service/api/pom.xml", "log4j-api", "2.10.0", "cve-2021-44228"),
+	)
+
+	occurrences := w.FindByCVE(" CVE-2021-44228 ")
+
+	require.Len(t, occurrences, 3)
+	assert.Equal(t, "/backend", occurrences[0].FolderPath)
+	assert.Equal(t, "/backend/pom.xml", occurrences[0].Issue.AffectedFilePath)
+	assert.Equal(t, "2.14.1", occurrences[0].Issue.AdditionalData.(vulnmap.OssIssueData).Version)
+	assert.Equal(t, "/service", occurrences[1].FolderPath)
+	assert.Equal(t, "/service/api/pom.xml", occurrences[1].Issue.AffectedFilePath)
+	assert.Equal(t, "log4j-api", occurrences[1].Issue.AdditionalData.(vulnmap.OssIssueData).PackageName)
+	assert.Equal(t, "/service", occurrences[2].FolderPath)
+	assert.Equal(t, "/service/build.gradle", occurrences[2].Issue.AffectedFilePath)
+	assert.Equal(t, "2.13.0", occurrences[2].Issue.AdditionalData.(vulnmap.OssIssueData).Version)
+}
+
+func Test_FindByCVE_MatchesExactly(t *testing.T) {
+	testutil.UnitTest(t)
+	w := setupSearchWorkspace(t,
+		newOssIssue("VULNMAP-JAVA-LOG4J-1", "pom.xml", vulnmap.Critical, "log4j-core", "CVE-2021-44228"),
+	)
+
+	assert.Empty(t, w.FindByCVE("CVE-2021-4422"))
+	assert.Empty(t, w.FindByCVE("CVE-2021-442289"))
+	assert.Empty(t, w.FindByCVE(""))
+	assert.NotNil(t, w.FindByCVE("CVE-1999-0001"))
+}
@@ -20,12 +20,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	goos "os"
+	"path/filepath"
 	"runtime"
+	"runtime/debug"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/puzpuzpuz/xsync/v3"
 	"github.com/rs/zerolog/log"
+	ignore "github.com/sabhiram/go-gitignore"
 	"github.com/khulnasoft-lab/go-application-framework/pkg/configuration"
 	"github.com/khulnasoft-lab/go-application-framework/pkg/local_workflows/json_schemas"
 
@@ -33,8 +39,12 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/converter"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
 	noti "github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/analytics"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/git"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/persistence"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/clock"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
@@ -45,9 +55,31 @@ type FolderStatus int
 const (
 	Unscanned FolderStatus = iota
 	Scanned   FolderStatus = iota
+	// ScanErrored means that the last scan of the folder was aborted by a panic
+	ScanErrored FolderStatus = iota
+	// ScanPending means that the folder was not scanned on startup and waits for an explicit scan or an opened file
+	ScanPending FolderStatus = iota
 )
 
 var (
+	// suppressionPolicyLoader caches the suppression policy configured in the settings
+	suppressionPolicyLoader = vulnmap.NewSuppressionPolicyLoader()
+
+	// baselineLoader caches the baseline of accepted issues
+	baselineLoader = vulnmap.NewBaselineLoader()
+
+	// acceptedIssuesLoader caches the individually accepted issues
+	acceptedIssuesLoader = vulnmap.NewAcceptedIssuesLoader()
+
+	// issueTagsLoader caches the labels users attached to issues
+	issueTagsLoader = vulnmap.NewIssueTagsLoader()
+
+	// openedFileScanDebounce is the time an opened file has to stay open before it is scanned on its own
+	openedFileScanDebounce = 500 * time.Millisecond
+
+	// pendingAnalytics tracks analytics sends that are still in flight, so that shutdown can wait for them
+	pendingAnalytics sync.WaitGroup
+
 	os = map[string]string{
 		"darwin":  "macOS",
 		"linux":   "Linux",
@@ -75,6 +107,66 @@ type Folder struct {
 	mutex                   sync.Mutex
 	scanNotifier            vulnmap.ScanNotifier
 	notifier                noti.Notifier
+	changedFilesProvider    git.ChangedFilesProvider
+	blameProvider           git.BlameProvider
+	issueCache              persistence.IssueCache
+	configOverlay           *vulnmap.FolderConfig
+	excludeMatcher          *ignore.GitIgnore
+	stopCtx                 context.Context
+	stopScans               context.CancelFunc
+	hoverDispatcher         *hoverDispatcher
+	// contentHashes contains the content hashes of the files at the time their issues were cached
+	contentHashes *xsync.MapOf[string, string]
+	// resultTimestamps contains the times the issues of the files were last reported by a scan
+	resultTimestamps *xsync.MapOf[string, time.Time]
+	// clock provides the current time, it is replaced in tests
+	clock clock.Clock
+	// blames caches the last commits of the lines of files by path
+	blames *xsync.MapOf[string, fileBlame]
+	// changedFiles restricts scans and published results to the contained files, nil means no restriction
+	changedFiles map[string]bool
+	// errorReporter reports panics during scans, nil means they are only logged
+	errorReporter error_reporting.ErrorReporter
+	// folderScanInProgress is true while the whole folder is scanned
+	folderScanInProgress bool
+	// openedFileScans contains the pending scans of opened files by path
+	openedFileScans map[string]*time.Timer
+	// closedFileClears contains the pending clears of the diagnostics of closed files by path
+	closedFileClears map[string]*time.Timer
+	// pendingScan contains the triggered scans waiting for the scan debounce to expire, nil if there are none
+	pendingScan *pendingScan
+	// pendingPublish is the diagnostics publish waiting for the publish throttle to expire, nil if there is none
+	pendingPublish *pendingPublish
+	// issueFingerprints contains the severities of the issues after the last scan of each product by fingerprint
+	issueFingerprints map[product.Product]map[string]vulnmap.Severity
+	// issueDeltas contains the issues added and removed by the last scan of each product
+	issueDeltas map[product.Product]IssueDelta
+	// deletionWatcher clears the diagnostics of externally deleted files, nil if not watching
+	deletionWatcher *deletionWatcher
+	// enclosingFolder is the workspace folder this folder is nested in, nil if it is not nested
+	enclosingFolder *Folder
+	// scanDoneEvents contains the last computed analytics event per product
+	scanDoneEvents map[product.Product]json_schemas.ScanDoneEvent
+	// failedScanDoneEvents is true for the products whose last scan done event couldn't be sent
+	failedScanDoneEvents map[product.Product]bool
+	// scanDoneEventSentAt contains the time the last scan done event of each product was sent
+	scanDoneEventSentAt map[product.Product]time.Time
+	// scanWarnings contains the warnings of the last scan of each product, they don't fail the scan
+	scanWarnings map[product.Product][]string
+	// scanMetadata contains the metadata of the last successful scan of each product
+	scanMetadata map[product.Product]ScanMetadata
+	// scanOutcome records which products finished or failed during the last folder scan
+	scanOutcome    ScanOutcome
+	analyticsMutex sync.Mutex
+	// scanPause skips scans while scanning is paused in the workspace, nil if the folder isn't in a workspace
+	scanPause *scanPause
+	// trustPrompted is true once the user was asked to trust the folder, so that they are asked at most once
+	trustPrompted bool
+	// diagnosticsSink receives the published diagnostics, by default they are sent as LSP notifications
+	diagnosticsSink DiagnosticsSink
+	// restoredProducts contains the products whose cached issues were restored from the persisted cache and not yet
+	// replaced by the results of a scan
+	restoredProducts map[product.Product]bool
 }
 
 func NewFolder(path string, name string, scanner vulnmap.Scanner, hoverService hover.Service, scanNotifier vulnmap.ScanNotifier, notifier noti.Notifier) *Folder {
@@ -86,11 +178,57 @@ func NewFolder(path string, name string, scanner vulnmap.Scanner, hoverService h
 		hoverService: hoverService,
 		scanNotifier: scanNotifier,
 		notifier:     notifier,
+
+		changedFilesProvider: git.NewChangedFilesProvider(),
+		blameProvider:        git.NewBlameProvider(),
+		issueCache:           persistence.NewFileIssueCache(persistence.DefaultCacheDir(), persistence.DefaultMaxCacheSize),
 	}
 	folder.documentDiagnosticCache = xsync.NewMapOf[string, []vulnmap.Issue]()
+	folder.contentHashes = xsync.NewMapOf[string, string]()
+	folder.resultTimestamps = xsync.NewMapOf[string, time.Time]()
+	folder.clock = clock.New()
+	folder.diagnosticsSink = NewNotifierDiagnosticsSink(notifier)
+	folder.blames = xsync.NewMapOf[string, fileBlame]()
+	folder.stopCtx, folder.stopScans = context.WithCancel(context.Background())
+	folder.hoverDispatcher = newHoverDispatcher(folder.stopCtx, hoverService)
+	configOverlay, err := vulnmap.LoadFolderConfig(folder.path)
+	if err != nil {
+		log.Warn().Err(err).Str("method", "NewFolder").Str("folder", folder.path).Msg("ignoring invalid folder config")
+	}
+	folder.SetConfigOverlay(configOverlay)
 	return &folder
 }
 
+// SetConfigOverlay sets the folder specific settings that shadow the global settings during scans of the folder
+func (f *Folder) SetConfigOverlay(configOverlay *vulnmap.FolderConfig) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.configOverlay = configOverlay
+	f.excludeMatcher = nil
+	if configOverlay != nil && len(configOverlay.ExcludePatterns) > 0 {
+		f.excludeMatcher = ignore.CompileIgnoreLines(configOverlay.ExcludePatterns...)
+	}
+}
+
+func (f *Folder) ConfigOverlay() *vulnmap.FolderConfig {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.configOverlay
+}
+
+func (f *Folder) isExcluded(filePath string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.excludeMatcher == nil {
+		return false
+	}
+	relativePath, err := filepath.Rel(f.path, filePath)
+	if err != nil {
+		return false
+	}
+	return f.excludeMatcher.MatchesPath(filepath.ToSlash(relativePath))
+}
+
 func (f *Folder) IsScanned() bool {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
@@ -109,17 +247,216 @@ func (f *Folder) SetStatus(status FolderStatus) {
 	f.status = status
 }
 
+func (f *Folder) SetErrorReporter(errorReporter error_reporting.ErrorReporter) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.errorReporter = errorReporter
+}
+
 func (f *Folder) ScanFolder(ctx context.Context) {
-	f.scan(ctx, f.path)
+	if f.isScanPaused() {
+		return
+	}
+	f.mutex.Lock()
+	if f.enclosingFolder != nil {
+		f.mutex.Unlock()
+		log.Debug().Str("method", "ScanFolder").Str("folder", f.path).Str("enclosingFolder", f.enclosingFolder.path).
+			Msg("skipping scan of nested folder, it is scanned as part of the enclosing folder")
+		return
+	}
+	if f.status == ScanErrored || f.status == ScanPending {
+		f.status = Unscanned
+	}
+	f.scanOutcome = ScanOutcome{}
+	f.folderScanInProgress = true
+	f.mutex.Unlock()
+
+	f.updateChangedFiles()
+	f.scanFolderOrChangedFiles(ctx)
+	f.startDeletionWatcher()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.folderScanInProgress = false
+	if f.status != ScanErrored {
+		f.status = Scanned
+	}
+}
+
+// IsFolderScanInProgress returns true while the whole folder is being scanned
+func (f *Folder) IsFolderScanInProgress() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.folderScanInProgress
+}
+
+// needsOpenedFileScan returns true if an opened file is not covered by a completed or running folder scan
+func (f *Folder) needsOpenedFileScan() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.status != Scanned && !f.folderScanInProgress
+}
+
+// ScanOpenedFile scans a file that was opened before the folder was scanned, so that its diagnostics are published
+// without waiting for the folder scan. The scan is debounced, repeated calls for the same file restart the debounce.
+// Nothing is scanned if the folder was scanned or a folder scan is in progress when the debounce expires.
+func (f *Folder) ScanOpenedFile(ctx context.Context, path string) {
+	if !f.needsOpenedFileScan() {
+		return
+	}
+
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
-	f.status = Scanned
+	if f.openedFileScans == nil {
+		f.openedFileScans = map[string]*time.Timer{}
+	}
+	if timer, pending := f.openedFileScans[path]; pending {
+		timer.Stop()
+	}
+	f.openedFileScans[path] = time.AfterFunc(openedFileScanDebounce, func() {
+		f.mutex.Lock()
+		delete(f.openedFileScans, path)
+		f.mutex.Unlock()
+		if ctx.Err() != nil || !f.needsOpenedFileScan() {
+			log.Debug().Str("method", "ScanOpenedFile").Str("path", path).Msg("skipping scan of opened file")
+			return
+		}
+		f.ScanFile(ctx, path)
+	})
 }
 
 func (f *Folder) ScanFile(ctx context.Context, path string) {
+	// the file may have become changed since the last scan, e.g. because it was saved
+	f.updateChangedFiles()
+	if !f.isChangedFile(path) {
+		log.Debug().Str("method", "ScanFile").Str("path", path).Msg("skipping scan of unchanged file")
+		return
+	}
 	f.scan(ctx, path)
 }
 
+// RescanFile scans the given file without using cached results and republishes only its diagnostics. Cached
+// results of other files and the scan status of the folder are left untouched. If a product fails, the
+// previously cached results of the file are kept.
+func (f *Folder) RescanFile(ctx context.Context, path string) {
+	const method = "domain.ide.workspace.folder.RescanFile"
+	if f.stopCtx.Err() != nil || !f.IsTrusted() {
+		log.Debug().Str("path", path).Str("method", method).Msg("skipping rescan of stopped or untrusted folder")
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(f.stopCtx, cancel)
+	defer stop()
+
+	var mutex sync.Mutex
+	var issues []vulnmap.Issue
+	failed := false
+	processFileResults := func(scanData vulnmap.ScanData) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if scanData.Err != nil {
+			f.scanNotifier.SendError(scanData.Product, f.path, scanData.ScanId)
+			log.Err(scanData.Err).Str("method", method).Str("product", string(scanData.Product)).
+				Msg("Product returned an error")
+			failed = true
+			return
+		}
+		for _, issue := range scanData.Issues {
+			if issue.AffectedFilePath != path {
+				continue
+			}
+			if transformedIssue, keep := transformIssue(issue); keep {
+				issues = append(issues, transformedIssue)
+			}
+		}
+	}
+	f.scanner.Scan(vulnmap.ContextWithFolderConfig(ctx, f.ConfigOverlay()), path, processFileResults, f.path)
+	if ctx.Err() != nil || failed {
+		return
+	}
+
+	if issues == nil {
+		issues = []vulnmap.Issue{}
+	}
+	f.documentDiagnosticCache.Store(path, issues)
+	f.storeContentHash(path)
+	f.persistIssues()
+
+	visibleIssues := []vulnmap.Issue{}
+	if f.isChangedFile(path) {
+		visibleIssues = FilterIssues(f.path, issues, config.CurrentConfig().DisplayableIssueTypes())
+	}
+	f.sendDiagnosticsForFile(path, visibleIssues)
+	f.sendHoversForFile(path, visibleIssues)
+	f.sendScanResults("", f.filterCachedDiagnostics())
+}
+
+// updateChangedFiles determines the changed files of the folder if only changed files should be scanned.
+// If the folder is not a git repository, the whole folder is scanned.
+func (f *Folder) updateChangedFiles() {
+	var changedFiles map[string]bool
+	c := config.CurrentConfig()
+	if c.IsScanChangedFilesOnlyEnabled() {
+		files, err := f.changedFilesProvider.ChangedFiles(f.path, c.ChangedFilesBaseRef())
+		if err != nil {
+			log.Warn().Err(err).Str("method", "updateChangedFiles").Str("folder", f.path).
+				Msg("couldn't determine changed files, falling back to full scan")
+		} else {
+			changedFiles = make(map[string]bool, len(files))
+			for _, file := range files {
+				changedFiles[file] = true
+			}
+		}
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.changedFiles = changedFiles
+}
+
+// scanFolderOrChangedFiles scans the whole folder, or only its changed files if only changed files should be scanned
+func (f *Folder) scanFolderOrChangedFiles(ctx context.Context) {
+	changedFiles, restricted := f.changedFileList()
+	if !restricted {
+		f.scan(ctx, f.path)
+		return
+	}
+	for _, filePath := range changedFiles {
+		if _, err := goos.Stat(filePath); err != nil {
+			// deleted files don't have issues
+			continue
+		}
+		f.scan(ctx, filePath)
+	}
+}
+
+// changedFileList returns the sorted changed files of the folder, and false if scans are not restricted to them
+func (f *Folder) changedFileList() ([]string, bool) {
+	if !config.CurrentConfig().IsScanChangedFilesOnlyEnabled() {
+		return nil, false
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.changedFiles == nil {
+		return nil, false
+	}
+	files := make([]string, 0, len(f.changedFiles))
+	for file := range f.changedFiles {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, true
+}
+
+func (f *Folder) isChangedFile(path string) bool {
+	if !config.CurrentConfig().IsScanChangedFilesOnlyEnabled() {
+		return true
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.changedFiles == nil || f.changedFiles[path]
+}
+
 func (f *Folder) Contains(path string) bool {
 	return uri.FolderContains(f.path, path)
 }
@@ -128,14 +465,21 @@ func (f *Folder) Contains(path string) bool {
 // with empty diagnostics results for the specific file
 func (f *Folder) ClearDiagnosticsFromFile(filePath string) {
 	// todo: can we manage the cache internally without leaking it, e.g. by using as a key an MD5 hash rather than a path and defining a TTL?
+	f.cancelClosedFileClear(filePath)
 	f.documentDiagnosticCache.Delete(filePath)
+	f.contentHashes.Delete(filePath)
+	f.resultTimestamps.Delete(filePath)
+	f.hoverDispatcher.discard(filePath)
+	if config.CurrentConfig().IsIssueCachePersistenceEnabled() {
+		err := f.issueCache.Remove(f.path, filePath)
+		if err != nil {
+			log.Warn().Err(err).Str("method", "ClearDiagnosticsFromFile").Msg("couldn't remove persisted issues")
+		}
+	}
 	if scanner, ok := f.scanner.(vulnmap.InlineValueProvider); ok {
 		scanner.ClearInlineValues(filePath)
 	}
-	f.notifier.Send(lsp.PublishDiagnosticsParams{
-		URI:         uri.PathToUri(filePath),
-		Diagnostics: []lsp.Diagnostic{},
-	})
+	f.sink().SendDiagnostics(filePath, []vulnmap.Issue{}, []lsp.Diagnostic{})
 	f.ClearScannedStatus()
 
 }
@@ -150,14 +494,46 @@ func (f *Folder) ClearDiagnosticsFromPathRecursively(removedPath string) {
 	})
 }
 
+// StopScans cancels running and pending scans of the folder and prevents new scans from being started
+func (f *Folder) StopScans() {
+	f.stopScans()
+	f.cancelPendingScan()
+	f.cancelPendingPublish()
+	f.stopDeletionWatcher()
+}
+
+// IsStopped returns true once the scans of the folder were stopped, e.g. because it was removed from the workspace
+func (f *Folder) IsStopped() bool {
+	return f.stopCtx.Err() != nil
+}
+
 func (f *Folder) scan(ctx context.Context, path string) {
 	const method = "domain.ide.workspace.folder.scan"
+	defer f.recoverScanPanic(path, "", vulnmap.ScanIdFromContext(ctx))
+	if f.stopCtx.Err() != nil {
+		log.Debug().Str("path", path).Str("method", method).Msg("skipping scan of stopped folder")
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(f.stopCtx, cancel)
+	defer stop()
+
+	if f.isScanPaused() {
+		return
+	}
 	if !f.IsTrusted() {
 		log.Warn().Str("path", path).Str("method", method).Msg("skipping scan of untrusted path")
+		f.promptTrust()
 		return
 	}
+	if f.restorePersistedIssues() {
+		log.Info().Str("method", method).Msgf("Persisted results found: Publishing them until the scan of %s finishes", path)
+		f.FilterAndPublishCachedDiagnostics("")
+	}
+	f.InvalidateStaleIssues(path)
 	issuesSlice := f.DocumentDiagnosticsFromCache(path)
-	if issuesSlice != nil {
+	if issuesSlice != nil && !f.hasRestoredIssues() {
 		log.Info().Str("method", method).
 			Int("issueSliceLength", len(issuesSlice)).
 			Msgf("Cached results found: Skipping scan for %s", path)
@@ -167,57 +543,263 @@ func (f *Folder) scan(ctx context.Context, path string) {
 		return
 	}
 
-	f.scanner.Scan(ctx, path, f.processResults, f.path)
+	f.scanner.Scan(vulnmap.ContextWithFolderConfig(ctx, f.ConfigOverlay()), path, f.processResults, f.path)
 }
 
 func (f *Folder) DocumentDiagnosticsFromCache(file string) []vulnmap.Issue {
 	issues, _ := f.documentDiagnosticCache.Load(file)
+	if issues == nil && config.CurrentConfig().IsIssueCachePersistenceEnabled() {
+		persistedIssues, ok := f.issueCache.Get(f.path, file)
+		if ok {
+			return persistedIssues
+		}
+	}
 	if issues == nil {
 		return nil
 	}
 	return issues
 }
 
+// InvalidateStaleIssues discards the cached issues of files that were edited since they were scanned, so that they
+// aren't served from the cache or merged with the results of the rescan. The changed path is either a file, which
+// also affects the cached files in its directory, as their lock files may have changed, or a directory.
+func (f *Folder) InvalidateStaleIssues(changedPath string) {
+	changedDir := filepath.Dir(changedPath)
+	f.documentDiagnosticCache.Range(func(filePath string, _ []vulnmap.Issue) bool {
+		affected := filePath == changedPath || filepath.Dir(filePath) == changedDir ||
+			uri.FolderContains(changedPath, filePath)
+		if affected && f.isContentChanged(filePath) {
+			log.Debug().Str("method", "InvalidateStaleIssues").Str("file", filePath).
+				Msg("content changed, discarding cached issues")
+			f.documentDiagnosticCache.Delete(filePath)
+			f.contentHashes.Delete(filePath)
+			f.resultTimestamps.Delete(filePath)
+		}
+		return true
+	})
+}
+
+// storeContentHash remembers the current content hash of the file, so that cached issues can be invalidated when
+// the file or its lock files change. Files that can't be hashed, e.g. because they don't exist, are never invalidated.
+func (f *Folder) storeContentHash(filePath string) {
+	hash, err := persistence.ContentHash(filePath)
+	if err != nil {
+		f.contentHashes.Delete(filePath)
+		return
+	}
+	f.contentHashes.Store(filePath, hash)
+}
+
+// isContentChanged returns true if the file or its lock files changed since its issues were cached
+func (f *Folder) isContentChanged(filePath string) bool {
+	cachedHash, ok := f.contentHashes.Load(filePath)
+	if !ok {
+		return false
+	}
+	hash, err := persistence.ContentHash(filePath)
+	return err != nil || hash != cachedHash
+}
+
+// restorePersistedIssues seeds the empty in-memory cache with the still valid persisted issues of the folder, so
+// that results are shown right away after a restart. The restored issues don't have code actions, so they are only
+// kept until a scan reports fresh results for their product. It returns true if any issues were restored.
+func (f *Folder) restorePersistedIssues() bool {
+	if !config.CurrentConfig().IsIssueCachePersistenceEnabled() || f.documentDiagnosticCache.Size() > 0 {
+		return false
+	}
+	issuesByFile, _ := f.issueCache.GetAll(f.path)
+	for filePath, issues := range issuesByFile {
+		f.storeRestoredIssues(filePath, issues)
+	}
+	return len(issuesByFile) > 0
+}
+
+// storeRestoredIssues stores persisted issues of the file in the in-memory cache and remembers their products, so
+// that they are replaced by the next results of the products.
+func (f *Folder) storeRestoredIssues(filePath string, issues []vulnmap.Issue) {
+	f.documentDiagnosticCache.Store(filePath, issues)
+	f.storeContentHash(filePath)
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.restoredProducts == nil {
+		f.restoredProducts = map[product.Product]bool{}
+	}
+	for _, issue := range issues {
+		f.restoredProducts[issue.Product] = true
+	}
+}
+
+func (f *Folder) hasRestoredIssues() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.restoredProducts) > 0
+}
+
+// clearRestoredIssues removes the restored issues of the product from the in-memory cache, as they are superseded by
+// the results of a scan of the product.
+func (f *Folder) clearRestoredIssues(p product.Product) {
+	f.mutex.Lock()
+	restored := f.restoredProducts[p]
+	delete(f.restoredProducts, p)
+	f.mutex.Unlock()
+	if !restored {
+		return
+	}
+	f.documentDiagnosticCache.Range(func(filePath string, issues []vulnmap.Issue) bool {
+		var kept []vulnmap.Issue
+		for _, issue := range issues {
+			if issue.Product != p {
+				kept = append(kept, issue)
+			}
+		}
+		if len(kept) == 0 {
+			f.documentDiagnosticCache.Delete(filePath)
+		} else if len(kept) < len(issues) {
+			f.documentDiagnosticCache.Store(filePath, kept)
+		}
+		return true
+	})
+}
+
+func (f *Folder) persistIssues() {
+	if !config.CurrentConfig().IsIssueCachePersistenceEnabled() {
+		return
+	}
+	issuesByFile := map[string][]vulnmap.Issue{}
+	f.documentDiagnosticCache.Range(func(filePath string, issues []vulnmap.Issue) bool {
+		issuesByFile[filePath] = issues
+		return true
+	})
+	err := f.issueCache.Store(f.path, issuesByFile)
+	if err != nil {
+		log.Warn().Err(err).Str("method", "persistIssues").Str("folder", f.path).Msg("couldn't persist issues")
+	}
+}
+
+// recoverScanPanic recovers a panic during the scan of the given path, so that other folders continue to be served.
+// The panic is reported along with the scan context and the folder's scan is marked as errored. It must be
+// deferred directly.
+func (f *Folder) recoverScanPanic(path string, p product.Product, scanId string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	scannedProduct := string(p)
+	if scannedProduct == "" {
+		scannedProduct = "all products"
+	}
+	err := fmt.Errorf("panic during scan of %s (folder: %s, product: %s): %v\n%s",
+		path, f.path, scannedProduct, r, sanitizeStack(debug.Stack()))
+	log.Error().Err(err).Str("method", "recoverScanPanic").Msg("recovered from panic")
+
+	f.mutex.Lock()
+	f.status = ScanErrored
+	errorReporter := f.errorReporter
+	f.mutex.Unlock()
+	if errorReporter != nil {
+		errorReporter.CaptureError(err)
+	}
+	if p != "" {
+		f.scanNotifier.SendError(p, f.path, scanId)
+	}
+}
+
+// sanitizeStack reduces a stack trace to function names and file base names, so that it doesn't contain the local
+// paths of the machine or the argument values of the calls.
+func sanitizeStack(stack []byte) string {
+	var sanitized []string
+	for _, line := range strings.Split(string(stack), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(line, "goroutine ") {
+			continue
+		}
+		if strings.HasPrefix(line, "\t") {
+			// file location, e.g. /home/user/folder.go:123 +0x1d
+			location, _, _ := strings.Cut(trimmed, " ")
+			sanitized = append(sanitized, "\t"+filepath.Base(location))
+			continue
+		}
+		if i := strings.LastIndex(trimmed, "("); i > 0 {
+			trimmed = trimmed[:i]
+		}
+		sanitized = append(sanitized, trimmed)
+	}
+	return strings.Join(sanitized, "\n")
+}
+
 func (f *Folder) processResults(scanData vulnmap.ScanData) {
+	defer f.recoverScanPanic(f.path, scanData.Product, scanData.ScanId)
+	f.updateScanWarnings(scanData.Product, scanData.Warnings)
+	f.recordScanOutcome(scanData.Product, scanData.Err)
 	if scanData.Err != nil {
-		f.scanNotifier.SendError(scanData.Product, f.path)
+		f.scanNotifier.SendError(scanData.Product, f.path, scanData.ScanId)
 		log.Err(scanData.Err).
 			Str("method", "processResults").
 			Str("product", string(scanData.Product)).
 			Msg("Product returned an error")
 		return
 	}
+	f.updateScanMetadata(scanData)
+	if scanData.Product != "" {
+		f.clearRestoredIssues(scanData.Product)
+	}
 
 	dedupMap := f.createDedupMap()
+	updatedFiles := map[string]bool{}
 
 	// TODO: perform issue diffing (current <-> newly reported)
 	// Update diagnostic cache
 	for _, issue := range scanData.Issues {
+		issue = f.routeProjectIssue(issue)
+		if f.isExcluded(issue.AffectedFilePath) {
+			continue
+		}
+		isDuplicate := dedupMap[f.getUniqueIssueID(issue)]
+		if !isDuplicate {
+			var keep bool
+			issue, keep = transformIssue(issue)
+			if !keep {
+				continue
+			}
+		}
+
 		cachedIssues, _ := f.documentDiagnosticCache.Load(issue.AffectedFilePath)
 		if cachedIssues == nil {
 			cachedIssues = []vulnmap.Issue{}
 		}
 
-		if !dedupMap[f.getUniqueIssueID(issue)] {
+		if !isDuplicate {
 			cachedIssues = append(cachedIssues, issue)
 			incrementSeverityCount(&scanData, issue)
 		}
 
 		f.documentDiagnosticCache.Store(issue.AffectedFilePath, cachedIssues)
-
+		updatedFiles[issue.AffectedFilePath] = true
+	}
+	now := f.clock.Now()
+	for filePath := range updatedFiles {
+		f.storeContentHash(filePath)
+		f.resultTimestamps.Store(filePath, now)
+	}
+	if scanData.Product != "" {
+		f.updateIssueDelta(scanData.Product)
 	}
 	log.Debug().Str("method", "processResults").Interface("scanData", scanData).Msg("Finished processing results. Sending analytics.")
-	sendAnalytics(&scanData)
+	f.sendAnalytics(&scanData)
+	f.persistIssues()
 
 	// Filter and publish cached diagnostics
 	f.FilterAndPublishCachedDiagnostics(scanData.Product)
+
+	if config.CurrentConfig().IsIncludeLineOwners() && len(updatedFiles) > 0 {
+		go f.annotateLineOwners(scanData.Product, updatedFiles)
+	}
 }
 
 func incrementSeverityCount(scanData *vulnmap.ScanData, issue vulnmap.Issue) {
-	issueProduct := issue.Product
-	if issueProduct == "" {
-		log.Debug().Str("method", "incrementSeverityCount").Msg("Issue product is empty. Setting to unknown")
-		issueProduct = "unknown"
+	issueProduct, ok := severityCountProduct(issue)
+	if !ok {
+		return
 	}
 
 	initializeSeverityCountForProduct(scanData, issueProduct)
@@ -241,6 +823,43 @@ func incrementSeverityCount(scanData *vulnmap.ScanData, issue vulnmap.Issue) {
 	scanData.SeverityCount[issueProduct] = severityCount // reassign the value to the map
 }
 
+// severityCountProduct returns the product an issue is counted for, depending on the configured unknown product
+// counting for issues without a product. It returns false if the issue isn't counted at all.
+func severityCountProduct(issue vulnmap.Issue) (product.Product, bool) {
+	if issue.Product != "" {
+		return issue.Product, true
+	}
+	logger := log.With().Str("method", "severityCountProduct").Str("issueId", issue.ID).Logger()
+	switch config.CurrentConfig().UnknownProductCounting() {
+	case config.UnknownProductCountingDrop:
+		logger.Warn().Msg("Issue product is empty. Not counting issue")
+		return "", false
+	case config.UnknownProductCountingInfer:
+		if inferredProduct, inferred := issueTypeProduct(issue.IssueType); inferred {
+			logger.Debug().Msgf("Issue product is empty. Inferred %s from issue type", inferredProduct)
+			return inferredProduct, true
+		}
+		logger.Warn().Msg("Issue product is empty and can't be inferred from issue type. Setting to unknown")
+	default:
+		logger.Debug().Msg("Issue product is empty. Setting to unknown")
+	}
+	return "unknown", true
+}
+
+// issueTypeProduct returns the product that reports issues of the given type
+func issueTypeProduct(issueType vulnmap.Type) (product.Product, bool) {
+	switch issueType {
+	case vulnmap.CodeQualityIssue, vulnmap.CodeSecurityVulnerability:
+		return product.ProductCode, true
+	case vulnmap.LicenceIssue, vulnmap.DependencyVulnerability:
+		return product.ProductOpenSource, true
+	case vulnmap.InfrastructureIssue:
+		return product.ProductInfrastructureAsCode, true
+	default:
+		return "", false
+	}
+}
+
 func initializeSeverityCountForProduct(scanData *vulnmap.ScanData, productType product.Product) {
 	if scanData.SeverityCount == nil {
 		scanData.SeverityCount = make(map[product.Product]vulnmap.SeverityCount)
@@ -256,7 +875,9 @@ func initializeSeverityCountForProduct(scanData *vulnmap.ScanData, productType p
 	}
 }
 
-func sendAnalytics(data *vulnmap.ScanData) {
+func (f *Folder) sendAnalytics(data *vulnmap.ScanData) {
+	pendingAnalytics.Add(1)
+	defer pendingAnalytics.Done()
 	initializeSeverityCountForProduct(data, data.Product)
 
 	c := config.CurrentConfig()
@@ -276,7 +897,7 @@ func sendAnalytics(data *vulnmap.ScanData) {
 	scanEvent := json_schemas.ScanDoneEvent{}
 	// Populate the fields with data
 	scanEvent.Data.Type = "analytics"
-	scanEvent.Data.Attributes.DeviceId = c.DeviceID()
+	scanEvent.Data.Attributes.DeviceId = c.AnalyticsDeviceID()
 	scanEvent.Data.Attributes.Application = "vulnmap-ls"
 	scanEvent.Data.Attributes.ApplicationVersion = config.Version
 	scanEvent.Data.Attributes.Os = os[runtime.GOOS]
@@ -295,20 +916,94 @@ func sendAnalytics(data *vulnmap.ScanData) {
 	scanEvent.Data.Attributes.DurationMs = fmt.Sprintf("%d", data.DurationMs)
 	scanEvent.Data.Attributes.TimestampFinished = data.TimestampFinished
 
-	bytes, err := json.Marshal(scanEvent)
-	if err != nil {
-		logger.Err(err).Msg("Error marshalling scan event")
+	f.analyticsMutex.Lock()
+	if f.scanDoneEvents == nil {
+		f.scanDoneEvents = map[product.Product]json_schemas.ScanDoneEvent{}
+		f.failedScanDoneEvents = map[product.Product]bool{}
+		f.scanDoneEventSentAt = map[product.Product]time.Time{}
+	}
+	// rapid rescans would inflate the analytics, so at most one event per product is sent within the window
+	now := f.clock.Now()
+	window := c.AnalyticsDeduplicationWindow()
+	if sentAt, sent := f.scanDoneEventSentAt[data.Product]; sent && window > 0 && now.Sub(sentAt) < window {
+		f.analyticsMutex.Unlock()
+		logger.Debug().Str("product", string(data.Product)).Msg("Skipping duplicate scan done event")
 		return
 	}
+	f.scanDoneEventSentAt[data.Product] = now
+	f.scanDoneEvents[data.Product] = scanEvent
+	f.analyticsMutex.Unlock()
 
-	err = analytics.SendAnalyticsToAPI(c, bytes)
+	err := f.sendScanDoneEvent(data.Product, scanEvent)
 	if err != nil {
 		logger.Err(err).Msg("Error sending analytics to API")
 		return
 	}
 }
 
-func (f *Folder) FilterAndPublishCachedDiagnostics(product product.Product) {
+// sendScanDoneEvent sends the event and records whether sending failed, unless a newer event of the product has
+// been computed in the meantime.
+func (f *Folder) sendScanDoneEvent(p product.Product, scanEvent json_schemas.ScanDoneEvent) error {
+	bytes, err := json.Marshal(scanEvent)
+	if err != nil {
+		return err
+	}
+	err = analytics.SendAnalyticsToAPI(config.CurrentConfig(), bytes)
+
+	f.analyticsMutex.Lock()
+	defer f.analyticsMutex.Unlock()
+	if f.scanDoneEvents[p] == scanEvent {
+		f.failedScanDoneEvents[p] = err != nil
+	}
+	return err
+}
+
+// FailedAnalyticsProducts returns the products whose last scan done event couldn't be sent
+func (f *Folder) FailedAnalyticsProducts() []product.Product {
+	f.analyticsMutex.Lock()
+	defer f.analyticsMutex.Unlock()
+	var products []product.Product
+	for p, failed := range f.failedScanDoneEvents {
+		if failed {
+			products = append(products, p)
+		}
+	}
+	return products
+}
+
+// ResendFailedAnalytics re-attempts sending the last scan done events of the products whose sending failed. It
+// returns the number of resent events and the first error. Nothing is sent if analytics are disabled.
+func (f *Folder) ResendFailedAnalytics() (int, error) {
+	if !config.CurrentConfig().IsAnalyticsEnabled() {
+		return 0, nil
+	}
+	pendingAnalytics.Add(1)
+	defer pendingAnalytics.Done()
+
+	f.analyticsMutex.Lock()
+	failedEvents := map[product.Product]json_schemas.ScanDoneEvent{}
+	for p, failed := range f.failedScanDoneEvents {
+		if failed {
+			failedEvents[p] = f.scanDoneEvents[p]
+		}
+	}
+	f.analyticsMutex.Unlock()
+
+	resent := 0
+	var firstErr error
+	for p, scanEvent := range failedEvents {
+		if err := f.sendScanDoneEvent(p, scanEvent); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		resent++
+	}
+	return resent, firstErr
+}
+
+func (f *Folder) publishCachedDiagnostics(product product.Product) {
 	issuesByFile := f.filterCachedDiagnostics()
 	f.publishDiagnostics(product, issuesByFile)
 }
@@ -321,54 +1016,58 @@ func (f *Folder) filterCachedDiagnostics() (fileIssues map[string][]vulnmap.Issu
 		return issuesByFile
 	}
 
-	filterSeverity := config.CurrentConfig().FilterSeverity()
-	logger.Debug().Interface("filterSeverity", filterSeverity).Msg("Filtering issues by severity")
+	// all files are filtered with the same settings, even if they change during filtering
+	c := config.CurrentConfig()
+	filter := newIssueFilter(c, f.path, c.DisplayableIssueTypes())
+	logger.Debug().Interface("filterSeverity", filter.severityFilter).Msg("Filtering issues by severity")
 
-	supportedIssueTypes := config.CurrentConfig().DisplayableIssueTypes()
 	f.documentDiagnosticCache.Range(func(filePath string, issues []vulnmap.Issue) bool {
 		// Consider doing the loop body in parallel for performance (and use a thread-safe map)
-		filteredIssues := FilterIssues(issues, supportedIssueTypes)
-		issuesByFile[filePath] = filteredIssues
+		if !f.isChangedFile(filePath) {
+			// publishing an empty slice clears diagnostics of files outside the changed file set
+			issuesByFile[filePath] = []vulnmap.Issue{}
+			return true
+		}
+		issuesByFile[filePath] = filter.filter(issues)
 		return true
 	})
 
 	return issuesByFile
 }
 
-func FilterIssues(issues []vulnmap.Issue, supportedIssueTypes map[product.FilterableIssueType]bool) []vulnmap.Issue {
-	logger := log.With().Str("method", "FilterIssues").Logger()
-	filteredIssues := make([]vulnmap.Issue, 0)
-
-	for _, issue := range issues {
-		// Logging here might hurt performance, should benchmark if filtering is slow
-		if isVisibleSeverity(issue) && supportedIssueTypes[issue.GetFilterableIssueType()] {
-			logger.Trace().Msgf("Including visible severity issue: %v", issue)
-			filteredIssues = append(filteredIssues, issue)
-		} else {
-			logger.Trace().Msgf("Filtering out issue %v", issue)
-		}
+// FilteredIssues returns the cached issues of the folder that are visible with the current filters
+func (f *Folder) FilteredIssues() []vulnmap.Issue {
+	var filteredIssues []vulnmap.Issue
+	for _, issues := range f.filterCachedDiagnostics() {
+		filteredIssues = append(filteredIssues, issues...)
 	}
 	return filteredIssues
 }
 
-func isVisibleSeverity(issue vulnmap.Issue) bool {
-	switch issue.Severity {
-	case vulnmap.Critical:
-		return config.CurrentConfig().FilterSeverity().Critical
-	case vulnmap.High:
-		return config.CurrentConfig().FilterSeverity().High
-	case vulnmap.Medium:
-		return config.CurrentConfig().FilterSeverity().Medium
-	case vulnmap.Low:
-		return config.CurrentConfig().FilterSeverity().Low
+// FilteredSeverityCount counts the cached issues of the folder that are visible with the current severity and
+// issue type filters
+func (f *Folder) FilteredSeverityCount() vulnmap.SeverityCount {
+	scanData := vulnmap.ScanData{}
+	for _, issues := range f.filterCachedDiagnostics() {
+		for _, issue := range issues {
+			incrementSeverityCount(&scanData, issue)
+		}
 	}
-	return false
+
+	var total vulnmap.SeverityCount
+	for _, count := range scanData.SeverityCount {
+		total.Critical += count.Critical
+		total.High += count.High
+		total.Medium += count.Medium
+		total.Low += count.Low
+	}
+	return total
 }
 
 func (f *Folder) publishDiagnostics(product product.Product, issuesByFile map[string][]vulnmap.Issue) {
 	f.sendDiagnostics(issuesByFile)
 	f.sendScanResults(product, issuesByFile)
-	f.sendHovers(issuesByFile) // TODO: this locks up the thread, need to investigate
+	f.sendHovers(issuesByFile)
 }
 
 func (f *Folder) createDedupMap() (dedupMap map[string]bool) {
@@ -385,23 +1084,56 @@ func (f *Folder) createDedupMap() (dedupMap map[string]bool) {
 }
 
 func (f *Folder) getUniqueIssueID(issue vulnmap.Issue) string {
+	// a Code rule can be violated several times in a file, the occurrences are told apart by the affected code
+	if data, isCode := issue.AdditionalData.(vulnmap.CodeIssueData); isCode && data.ContextHash != "" {
+		return issue.Fingerprint()
+	}
 	uniqueID := issue.ID + "|" + issue.AffectedFilePath
 	return uniqueID
 }
 
+// parallelDiagnosticsThreshold is the number of files from which their diagnostics are published in parallel. For
+// fewer files, starting the goroutines costs more than it saves.
+const parallelDiagnosticsThreshold = 100
+
+// sendDiagnostics publishes the diagnostics of the files. Many files are published in parallel with at most the
+// configured concurrency, each file is still published by a single goroutine.
 func (f *Folder) sendDiagnostics(issuesByFile map[string][]vulnmap.Issue) {
+	concurrency := config.CurrentConfig().DiagnosticsPublishConcurrency()
+	if concurrency <= 1 || len(issuesByFile) < parallelDiagnosticsThreshold {
+		for path, issues := range issuesByFile {
+			f.sendDiagnosticsForFile(path, issues)
+		}
+		return
+	}
+
+	wg := sync.WaitGroup{}
+	semaphore := make(chan struct{}, concurrency)
 	for path, issues := range issuesByFile {
-		f.sendDiagnosticsForFile(path, issues)
+		semaphore <- struct{}{} // Acquire semaphore
+		wg.Add(1)
+		go func(path string, issues []vulnmap.Issue) {
+			defer wg.Done()
+			defer func() { <-semaphore }() // Release semaphore
+			f.sendDiagnosticsForFile(path, issues)
+		}(path, issues)
 	}
+	wg.Wait()
 }
 
 func (f *Folder) sendDiagnosticsForFile(path string, issues []vulnmap.Issue) {
 	log.Debug().Str("method", "sendDiagnosticsForFile").Str("affectedFilePath", path).Int("issueCount",
 		len(issues)).Send()
-	f.notifier.Send(lsp.PublishDiagnosticsParams{
-		URI:         uri.PathToUri(path),
-		Diagnostics: converter.ToDiagnostics(issues),
-	})
+	diagnostics := converter.ToDiagnostics(issues)
+	if config.CurrentConfig().IsManifestSummaryEnabled() {
+		if summary, found := converter.ToManifestSummaryDiagnostic(issues); found {
+			diagnostics = append([]lsp.Diagnostic{summary}, diagnostics...)
+		}
+	}
+	if age, stale := f.staleResultAge(path, issues); stale {
+		diagnostics = append([]lsp.Diagnostic{converter.ToStaleResultsDiagnostic(issues, age)}, diagnostics...)
+	}
+	f.sink().SendDiagnostics(path, issues, diagnostics)
 }
 
 func (f *Folder) sendHovers(issuesByFile map[string][]vulnmap.Issue) {
@@ -410,8 +1142,9 @@ func (f *Folder) sendHovers(issuesByFile map[string][]vulnmap.Issue) {
 	}
 }
 
+// sendHoversForFile hands the hovers to the hover dispatcher, so that a slow hover consumer doesn't block publishing
 func (f *Folder) sendHoversForFile(path string, issues []vulnmap.Issue) {
-	f.hoverService.Channel() <- converter.ToHoversDocument(path, issues)
+	f.hoverDispatcher.dispatch(converter.ToHoversDocument(path, issues))
 }
 
 func (f *Folder) Path() string         { return f.path }
@@ -444,32 +1177,58 @@ func (f *Folder) AllIssuesFor(filePath string) (matchingIssues []vulnmap.Issue)
 func (f *Folder) ClearDiagnostics() {
 	f.documentDiagnosticCache.Range(func(key string, _ []vulnmap.Issue) bool {
 		// we must republish empty diagnostics for all files that were reported with diagnostics
-		f.notifier.Send(lsp.PublishDiagnosticsParams{
-			URI:         uri.PathToUri(key),
-			Diagnostics: []lsp.Diagnostic{},
-		})
+		f.sink().SendDiagnostics(key, []vulnmap.Issue{}, []lsp.Diagnostic{})
 		f.documentDiagnosticCache.Delete(key)
+		f.contentHashes.Delete(key)
+		f.resultTimestamps.Delete(key)
 		return true
 	})
+	f.blames.Clear()
+	f.resetIssueDeltas()
+	f.hoverDispatcher.discardAll()
+	if config.CurrentConfig().IsIssueCachePersistenceEnabled() {
+		err := f.issueCache.Clear(f.path)
+		if err != nil {
+			log.Warn().Err(err).Str("method", "ClearDiagnostics").Msg("couldn't clear persisted issues")
+		}
+	}
 }
 
 func (f *Folder) ClearDiagnosticsByIssueType(removedType product.FilterableIssueType) {
+	f.ClearDiagnosticsByIssueTypes(removedType)
+}
+
+// ClearDiagnosticsByIssueTypes removes the issues of all given types and republishes the diagnostics of the affected
+// files once
+func (f *Folder) ClearDiagnosticsByIssueTypes(removedTypes ...product.FilterableIssueType) {
+	removed := make(map[product.FilterableIssueType]bool, len(removedTypes))
+	for _, removedType := range removedTypes {
+		removed[removedType] = true
+	}
+	updatedFiles := map[string][]vulnmap.Issue{}
+	f.mutex.Lock()
 	f.documentDiagnosticCache.Range(func(filePath string, previousIssues []vulnmap.Issue) bool {
 		newIssues := []vulnmap.Issue{}
 		for _, issue := range previousIssues {
-			if issue.GetFilterableIssueType() != removedType {
+			if !removed[issue.GetFilterableIssueType()] {
 				newIssues = append(newIssues, issue)
 			}
 		}
 
 		if len(previousIssues) != len(newIssues) { // Only send diagnostics update when issues were removed
 			f.documentDiagnosticCache.Store(filePath, newIssues)
-			f.sendDiagnosticsForFile(filePath, newIssues)
-			f.sendHoversForFile(filePath, newIssues)
+			updatedFiles[filePath] = newIssues
 		}
 
 		return true
 	})
+	f.mutex.Unlock()
+
+	// publishing takes the folder mutex itself, so it happens after the cache was updated
+	for filePath, newIssues := range updatedFiles {
+		f.sendDiagnosticsForFile(filePath, newIssues)
+		f.sendHoversForFile(filePath, newIssues)
+	}
 }
 
 func (f *Folder) IsTrusted() bool {
@@ -477,23 +1236,42 @@ func (f *Folder) IsTrusted() bool {
 		return true
 	}
 
-	for _, path := range config.CurrentConfig().TrustedFolders() {
-		if strings.HasPrefix(f.path, path) {
-			return true
+	_, trusted := f.TrustedPrefix()
+	return trusted
+}
+
+// TrustedPrefix returns the configured trusted folder that the folder path starts with or that matches the folder
+// path if it's a pattern, if any
+func (f *Folder) TrustedPrefix() (string, bool) {
+	for _, entry := range config.CurrentConfig().TrustedFolders() {
+		if config.MatchesTrustedFolder(entry, f.path) {
+			return entry, true
 		}
 	}
-	return false
+	return "", false
 }
 
 func (f *Folder) sendScanResults(processedProduct product.Product, issuesByFile map[string][]vulnmap.Issue) {
-	var productIssues []vulnmap.Issue
+	issuesByProduct := map[product.Product][]vulnmap.Issue{}
+	var products []product.Product
 	for _, issues := range issuesByFile {
-		productIssues = append(productIssues, issues...)
+		for _, issue := range issues {
+			if _, exists := issuesByProduct[issue.Product]; !exists {
+				products = append(products, issue.Product)
+			}
+			issuesByProduct[issue.Product] = append(issuesByProduct[issue.Product], issue)
+		}
+	}
+
+	var productIssues []vulnmap.Issue
+	for _, p := range product.SortByDisplayOrder(products, config.CurrentConfig().ProductDisplayOrder()) {
+		productIssues = append(productIssues, issuesByProduct[p]...)
 	}
 
+	scanIds := f.lastScanIds()
 	if processedProduct != "" {
-		f.scanNotifier.SendSuccess(processedProduct, f.Path(), productIssues)
+		f.scanNotifier.SendSuccess(processedProduct, f.Path(), scanIds[processedProduct], productIssues)
 	} else {
-		f.scanNotifier.SendSuccessForAllProducts(f.Path(), productIssues)
+		f.scanNotifier.SendSuccessForAllProducts(f.Path(), scanIds, productIssues)
 	}
 }
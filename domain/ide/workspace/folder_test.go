@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -37,11 +38,15 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
 	noti "github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/persistence"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/clock"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
 )
 
 func Test_Scan_WhenCachedResults_shouldNotReScan(t *testing.T) {
@@ -59,6 +64,187 @@ func Test_Scan_WhenCachedResults_shouldNotReScan(t *testing.T) {
 	assert.Equal(t, 1, scanner.Calls())
 }
 
+func Test_Scan_WhenPersistedResults_shouldPublishThemAndReScanAfterRestart(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetIssueCachePersistence(true)
+	folderPath := t.TempDir()
+	filePath := filepath.Join(folderPath, "package.json")
+	testutil.CreateFileOrFail(t, filePath, []byte("{}"))
+	cacheDir := t.TempDir()
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(NewMockIssue("1", filePath))
+	f := NewFolder(folderPath, "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	f.issueCache = persistence.NewFileIssueCache(cacheDir, persistence.DefaultMaxCacheSize)
+	f.ScanFolder(context.Background())
+	require.Equal(t, 1, scanner.Calls())
+
+	restartedScanner := vulnmap.NewTestScanner()
+	restartedScanner.AddTestIssue(NewMockIssue("2", filePath))
+	restarted := NewFolder(folderPath, "Test", restartedScanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	restarted.issueCache = persistence.NewFileIssueCache(cacheDir, persistence.DefaultMaxCacheSize)
+	require.True(t, restarted.restorePersistedIssues())
+	assert.Equal(t, []string{"1"}, issueIds(restarted.DocumentDiagnosticsFromCache(filePath)))
+
+	restarted.ScanFolder(context.Background())
+
+	assert.Equal(t, 1, restartedScanner.Calls())
+	assert.Equal(t, []string{"2"}, issueIds(restarted.DocumentDiagnosticsFromCache(filePath)))
+	assert.False(t, restarted.hasRestoredIssues())
+}
+
+func Test_Scan_WhenPersistedResultsAreStale_shouldReScan(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetIssueCachePersistence(true)
+	folderPath := t.TempDir()
+	filePath := filepath.Join(folderPath, "package.json")
+	testutil.CreateFileOrFail(t, filePath, []byte("{}"))
+	cacheDir := t.TempDir()
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(NewMockIssue("1", filePath))
+	f := NewFolder(folderPath, "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	f.issueCache = persistence.NewFileIssueCache(cacheDir, persistence.DefaultMaxCacheSize)
+	f.ScanFolder(context.Background())
+	testutil.CreateFileOrFail(t, filePath, []byte(`{"name": "changed"}`))
+
+	restartedScanner := vulnmap.NewTestScanner()
+	restarted := NewFolder(folderPath, "Test", restartedScanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	restarted.issueCache = persistence.NewFileIssueCache(cacheDir, persistence.DefaultMaxCacheSize)
+	restarted.ScanFolder(context.Background())
+
+	assert.Equal(t, 1, restartedScanner.Calls())
+	assert.Nil(t, restarted.DocumentDiagnosticsFromCache(filePath))
+}
+
+func Test_RescanFile_UpdatesOnlyTheRescannedFile(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	lockfilePath := filepath.Join(folderPath, "package-lock.json")
+	// cached issues are invalidated when lock files in the same directory change, so the sibling has its own directory
+	siblingPath := filepath.Join(folderPath, "backend", "pom.xml")
+	testutil.CreateFileOrFail(t, lockfilePath, []byte("{}"))
+	testutil.CreateFileOrFail(t, siblingPath, []byte("<project/>"))
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(NewMockIssue("lock-1", lockfilePath))
+	scanner.AddTestIssue(NewMockIssue("sibling-1", siblingPath))
+	notifier := notification.NewMockNotifier()
+	f := NewFolder(folderPath, "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	f.ScanFolder(context.Background())
+	require.True(t, f.IsScanned())
+	sentBeforeRescan := len(notifier.SentMessages())
+
+	testutil.CreateFileOrFail(t, lockfilePath, []byte(`{"lockfileVersion": 3}`))
+	scanner.Issues = []vulnmap.Issue{
+		NewMockIssue("lock-2", lockfilePath),
+		NewMockIssue("lock-3", lockfilePath),
+		NewMockIssue("sibling-2", siblingPath),
+	}
+	f.RescanFile(context.Background(), lockfilePath)
+
+	lockfileIssues := f.DocumentDiagnosticsFromCache(lockfilePath)
+	require.Len(t, lockfileIssues, 2)
+	assert.Equal(t, "lock-2", lockfileIssues[0].ID)
+	assert.Equal(t, "lock-3", lockfileIssues[1].ID)
+	siblingIssues := f.DocumentDiagnosticsFromCache(siblingPath)
+	require.Len(t, siblingIssues, 1)
+	assert.Equal(t, "sibling-1", siblingIssues[0].ID)
+	assert.True(t, f.IsScanned())
+
+	var publishedURIs []string
+	for _, msg := range notifier.SentMessages()[sentBeforeRescan:] {
+		if params, ok := msg.(lsp.PublishDiagnosticsParams); ok {
+			publishedURIs = append(publishedURIs, string(params.URI))
+		}
+	}
+	assert.Equal(t, []string{string(uri.PathToUri(lockfilePath))}, publishedURIs)
+}
+
+func Test_ScanFile_WhenFileWasEdited_shouldBypassCache(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	filePath := filepath.Join(folderPath, "package.json")
+	testutil.CreateFileOrFail(t, filePath, []byte(`{"dependencies": {"lodash": "4.17.4"}}`))
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(NewMockIssue("lodash-issue", filePath))
+	f := NewFolder(folderPath, "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	f.ScanFile(context.Background(), filePath)
+	f.ScanFile(context.Background(), filePath)
+	require.Equal(t, 1, scanner.Calls(), "unchanged file should be served from the cache")
+
+	testutil.CreateFileOrFail(t, filePath, []byte(`{"dependencies": {"lodash": "4.17.21"}}`))
+	scanner.Issues = []vulnmap.Issue{NewMockIssue("other-issue", filePath)}
+	f.ScanFile(context.Background(), filePath)
+
+	assert.Equal(t, 2, scanner.Calls())
+	issues := f.DocumentDiagnosticsFromCache(filePath)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "other-issue", issues[0].ID)
+}
+
+func Test_InvalidateStaleIssues_WhenLockFileWasEdited_shouldDiscardIssues(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	filePath := filepath.Join(folderPath, "package.json")
+	lockFilePath := filepath.Join(folderPath, "package-lock.json")
+	testutil.CreateFileOrFail(t, filePath, []byte("{}"))
+	testutil.CreateFileOrFail(t, lockFilePath, []byte(`{"lockfileVersion": 2}`))
+	f := NewFolder(folderPath, "Test", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue("1", filePath)}})
+	require.Len(t, f.DocumentDiagnosticsFromCache(filePath), 1)
+
+	testutil.CreateFileOrFail(t, lockFilePath, []byte(`{"lockfileVersion": 3}`))
+	require.Len(t, f.DocumentDiagnosticsFromCache(filePath), 1, "reading the cache should not invalidate it")
+
+	f.InvalidateStaleIssues(lockFilePath)
+
+	assert.Nil(t, f.DocumentDiagnosticsFromCache(filePath))
+}
+
+type folderConfigRecordingScanner struct {
+	folderConfigs map[string]*vulnmap.FolderConfig
+}
+
+func (s *folderConfigRecordingScanner) Scan(ctx context.Context, _ string, _ vulnmap.ScanResultProcessor, folderPath string) {
+	s.folderConfigs[folderPath] = vulnmap.FolderConfigFromContext(ctx)
+}
+
+func (s *folderConfigRecordingScanner) Init() error { return nil }
+
+func Test_Scan_UsesFolderConfigOnlyForItsFolder(t *testing.T) {
+	testutil.UnitTest(t)
+	folderWithConfig := t.TempDir()
+	siblingFolder := t.TempDir()
+	testutil.CreateFileOrFail(t, filepath.Join(folderWithConfig, vulnmap.FolderConfigFileName),
+		[]byte(`{"enabledProducts": {"oss": false}}`))
+	scanner := &folderConfigRecordingScanner{folderConfigs: map[string]*vulnmap.FolderConfig{}}
+	f1 := NewFolder(folderWithConfig, "f1", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	f2 := NewFolder(siblingFolder, "f2", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+
+	f1.ScanFolder(context.Background())
+	f2.ScanFolder(context.Background())
+
+	require.NotNil(t, scanner.folderConfigs[folderWithConfig])
+	assert.False(t, scanner.folderConfigs[folderWithConfig].IsProductEnabled(product.ProductOpenSource, true))
+	assert.Nil(t, scanner.folderConfigs[siblingFolder])
+	assert.True(t, scanner.folderConfigs[siblingFolder].IsProductEnabled(product.ProductOpenSource, true))
+}
+
+func Test_processResults_SkipsIssuesInExcludedPaths(t *testing.T) {
+	testutil.UnitTest(t)
+	f := NewMockFolder(notification.NewNotifier())
+	f.SetConfigOverlay(&vulnmap.FolderConfig{ExcludePatterns: []string{"vendor/"}})
+
+	f.processResults(vulnmap.ScanData{
+		Product: product.ProductOpenSource,
+		Issues: []vulnmap.Issue{
+			NewMockIssue("id1", filepath.Join(f.path, "vendor", "lib", "package.json")),
+			NewMockIssue("id2", filepath.Join(f.path, "package.json")),
+		},
+	})
+
+	assert.Nil(t, f.DocumentDiagnosticsFromCache(filepath.Join(f.path, "vendor", "lib", "package.json")))
+	assert.Len(t, f.DocumentDiagnosticsFromCache(filepath.Join(f.path, "package.json")), 1)
+}
+
 func Test_Scan_WhenNoIssues_shouldNotProcessResults(t *testing.T) {
 	hoverRecorder := hover.NewFakeHoverService()
 	testutil.UnitTest(t)
@@ -245,7 +431,7 @@ func TestProcessResults_whenFilteringSeverity_ProcessesOnlyFilteredIssues(t *tes
 			mtx.Lock()
 			defer mtx.Unlock()
 
-			hasCorrectIssues := diagnostics[0].Code == "id1" && diagnostics[1].Code == "id3" && diagnostics[2].Code == "id5"
+			hasCorrectIssues := diagnostics[0].Code == "id1" && diagnostics[1].Code == "id5" && diagnostics[2].Code == "id3"
 			return hasCorrectIssues
 		},
 		1*time.Second,
@@ -329,6 +515,23 @@ func Test_IsTrusted_shouldReturnFalseForDifferentFolder(t *testing.T) {
 	assert.False(t, f.IsTrusted())
 }
 
+func Test_IsTrusted_GlobPatternTrustsMatchingFolders(t *testing.T) {
+	testutil.UnitTest(t)
+	testutil.NotOnWindows(t, "Unix/macOS file paths are incompatible with Windows")
+	config.CurrentConfig().SetTrustedFolderFeatureEnabled(true)
+	config.CurrentConfig().SetTrustedFolders([]string{"glob:/home/dev/src/myorg-*"})
+	newFolder := func(path string) *Folder {
+		return NewFolder(path, "dummy", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	}
+
+	assert.True(t, newFolder("/home/dev/src/myorg-api").IsTrusted())
+	assert.True(t, newFolder("/home/dev/src/myorg-api/services/billing").IsTrusted())
+	assert.False(t, newFolder("/home/dev/src/otherorg-api").IsTrusted())
+	assert.False(t, newFolder("/home/dev/src").IsTrusted())
+	prefix, _ := newFolder("/home/dev/src/myorg-web").TrustedPrefix()
+	assert.Equal(t, "glob:/home/dev/src/myorg-*", prefix)
+}
+
 func Test_IsTrusted_shouldReturnTrueForSubfolderOfTrustedFolders(t *testing.T) {
 	testutil.IntegTest(t)
 	testutil.OnlyOnWindows(t, "Windows specific test")
@@ -344,6 +547,87 @@ func Test_IsTrusted_shouldReturnTrueIfTrustFeatureDisabled(t *testing.T) {
 	assert.True(t, f.IsTrusted())
 }
 
+func Test_FilterIssues_dropsIssuesSuppressedByPolicy(t *testing.T) {
+	c := testutil.UnitTest(t)
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	testutil.CreateFileOrFail(t, policyPath, []byte(`{"suppressions": [
+		{"cve": "CVE-2021-44228", "reason": "not exploitable"},
+		{"package": "lodash", "reason": "expired risk acceptance", "expires": "2000-01-01"}
+	]}`))
+	c.SetSuppressionPolicyPath(policyPath)
+	suppressedIssue := vulnmap.Issue{ID: "suppressed", Severity: vulnmap.High, Product: product.ProductOpenSource,
+		CVEs: []string{"CVE-2021-44228"}}
+	expiredIssue := vulnmap.Issue{ID: "expired", Severity: vulnmap.High, Product: product.ProductOpenSource,
+		AdditionalData: vulnmap.OssIssueData{PackageName: "lodash"}}
+
+	filteredIssues := FilterIssues("", []vulnmap.Issue{suppressedIssue, expiredIssue}, c.DisplayableIssueTypes())
+
+	assert.Equal(t, []vulnmap.Issue{expiredIssue}, filteredIssues)
+}
+
+func Test_FilterIssues_ShowOnlyFixable(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetShowOnlyFixable(true)
+	upgradable := vulnmap.Issue{ID: "upgradable", Severity: vulnmap.High, Product: product.ProductOpenSource,
+		AdditionalData: vulnmap.OssIssueData{IsUpgradable: true, From: []string{"goof@1.0.0", "lodash@4.17.4"},
+			UpgradePath: []any{false, "lodash@4.17.21"}}}
+	majorUpgrade := vulnmap.Issue{ID: "major", Severity: vulnmap.High, Product: product.ProductOpenSource,
+		AdditionalData: vulnmap.OssIssueData{IsUpgradable: true, From: []string{"goof@1.0.0", "express@4.17.1"},
+			UpgradePath: []any{false, "express@5.0.0"}}}
+	patchable := vulnmap.Issue{ID: "patchable", Severity: vulnmap.High, Product: product.ProductOpenSource,
+		AdditionalData: vulnmap.OssIssueData{IsPatchable: true}}
+	unfixable := vulnmap.Issue{ID: "unfixable", Severity: vulnmap.High, Product: product.ProductOpenSource,
+		AdditionalData: vulnmap.OssIssueData{}}
+	lowUpgradable := upgradable
+	lowUpgradable.ID = "low"
+	lowUpgradable.Severity = vulnmap.Low
+	c.SetSeverityFilter(lsp.NewSeverityFilter(true, true, true, false))
+	issues := []vulnmap.Issue{upgradable, majorUpgrade, patchable, unfixable, lowUpgradable}
+
+	assert.Equal(t, []vulnmap.Issue{upgradable, majorUpgrade, patchable}, FilterIssues("", issues, c.DisplayableIssueTypes()))
+
+	c.SetMajorUpgradeFixIncluded(false)
+	assert.Equal(t, []vulnmap.Issue{upgradable, patchable}, FilterIssues("", issues, c.DisplayableIssueTypes()))
+
+	c.SetShowOnlyFixable(false)
+	assert.Equal(t, []vulnmap.Issue{upgradable, majorUpgrade, patchable, unfixable}, FilterIssues("", issues, c.DisplayableIssueTypes()))
+}
+
+func Test_FilterIssues_MinEpssScore(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetMinEpssScore(0.25)
+	epssIssue := func(id string, score float64) vulnmap.Issue {
+		return vulnmap.Issue{ID: id, Severity: vulnmap.High, Product: product.ProductOpenSource,
+			AdditionalData: vulnmap.OssIssueData{EpssScore: &score}}
+	}
+	atThreshold := epssIssue("at threshold", 0.25)
+	belowThreshold := epssIssue("below threshold", 0.2499)
+	aboveThreshold := epssIssue("above threshold", 0.9)
+	withoutEpss := vulnmap.Issue{ID: "without EPSS", Severity: vulnmap.High, Product: product.ProductOpenSource,
+		AdditionalData: vulnmap.OssIssueData{}}
+	issues := []vulnmap.Issue{atThreshold, belowThreshold, aboveThreshold, withoutEpss}
+
+	assert.Equal(t, []vulnmap.Issue{atThreshold, aboveThreshold, withoutEpss}, FilterIssues("", issues, c.DisplayableIssueTypes()))
+
+	c.SetMinEpssScore(0)
+	assert.Equal(t, issues, FilterIssues("", issues, c.DisplayableIssueTypes()))
+}
+
+func Test_FilterIssues_DevDependencyIssues(t *testing.T) {
+	c := testutil.UnitTest(t)
+	devIssue := vulnmap.Issue{ID: "dev", Severity: vulnmap.High, Product: product.ProductOpenSource,
+		AdditionalData: vulnmap.OssIssueData{IsDevDependency: true}}
+	prodIssue := vulnmap.Issue{ID: "prod", Severity: vulnmap.High, Product: product.ProductOpenSource,
+		AdditionalData: vulnmap.OssIssueData{}}
+	issues := []vulnmap.Issue{devIssue, prodIssue}
+
+	c.SetDevDependencyIssues(config.DevDependencyIssuesHide)
+	assert.Equal(t, []vulnmap.Issue{prodIssue}, FilterIssues("", issues, c.DisplayableIssueTypes()))
+
+	c.SetDevDependencyIssues(config.DevDependencyIssuesTag)
+	assert.Equal(t, issues, FilterIssues("", issues, c.DisplayableIssueTypes()))
+}
+
 func Test_FilterCachedDiagnostics_filtersDisabledSeverity(t *testing.T) {
 	testutil.UnitTest(t)
 
@@ -452,6 +736,30 @@ func Test_processResults_ShouldSendError(t *testing.T) {
 	assert.Empty(t, scanNotifier.SuccessCalls())
 	assert.Len(t, scanNotifier.ErrorCalls(), 1)
 }
+func Test_processResults_WarningsAreRetainedWithoutFailingTheScan(t *testing.T) {
+	testutil.UnitTest(t)
+	f, scanNotifier := NewMockFolderWithScanNotifier(notification.NewNotifier())
+	data := vulnmap.ScanData{
+		Product:  product.ProductOpenSource,
+		Issues:   []vulnmap.Issue{NewMockIssue("id1", "path1")},
+		Warnings: []string{"could not resolve project a"},
+	}
+
+	f.processResults(data)
+
+	assert.Len(t, scanNotifier.SuccessCalls(), 1)
+	assert.Empty(t, scanNotifier.ErrorCalls())
+	assert.Len(t, f.AllIssuesFor("path1"), 1)
+	assert.Equal(t, map[product.Product][]string{
+		product.ProductOpenSource: {"could not resolve project a"},
+	}, f.ScanWarnings())
+
+	// a scan without warnings replaces the warnings of the previous scan
+	data.Warnings = nil
+	f.processResults(data)
+	assert.Empty(t, f.ScanWarnings())
+}
+
 func Test_processResults_ShouldSendAnalyticsToAPI(t *testing.T) {
 	c := testutil.UnitTest(t)
 	c.SetAnalyticsEnabled(true)
@@ -485,6 +793,39 @@ func Test_processResults_ShouldSendAnalyticsToAPI(t *testing.T) {
 	// Act
 	f.processResults(data)
 }
+func Test_processResults_ShouldNotSendDeviceIdIfAnalyticsAnonymized(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAnalyticsEnabled(true)
+	c.SetAnonymizeAnalytics(true)
+	c.SetDeviceID("stable-device-id")
+
+	engineMock, gafConfig := setUpEngineMock(t, c)
+
+	f, _ := NewMockFolderWithScanNotifier(notification.NewNotifier())
+	data := vulnmap.ScanData{
+		Product: product.ProductOpenSource,
+		Issues:  []vulnmap.Issue{NewMockIssue("id1", "path1")},
+	}
+
+	engineMock.EXPECT().GetConfiguration().AnyTimes().Return(gafConfig)
+	engineMock.EXPECT().InvokeWithInputAndConfig(localworkflows.WORKFLOWID_REPORT_ANALYTICS, gomock.Any(), gomock.Any()).
+		Do(func(id workflow.Identifier, workflowInputData []workflow.Data, config configuration.Configuration) {
+			require.Equal(t, 1, len(workflowInputData))
+			payloadBytes, ok := workflowInputData[0].GetPayload().([]byte)
+			require.True(t, ok)
+
+			var scanDoneEvent json_schemas.ScanDoneEvent
+			err := json.Unmarshal(payloadBytes, &scanDoneEvent)
+			require.NoError(t, err)
+			require.NotEqual(t, "stable-device-id", scanDoneEvent.Data.Attributes.DeviceId)
+			require.Equal(t, c.AnalyticsDeviceID(), scanDoneEvent.Data.Attributes.DeviceId)
+			require.Equal(t, 1, scanDoneEvent.Data.Attributes.UniqueIssueCount.Medium)
+		})
+
+	// Act
+	f.processResults(data)
+}
+
 func Test_processResults_ShouldNotSendAnalyticsToAPIIfDisabled(t *testing.T) {
 	c := testutil.UnitTest(t)
 
@@ -507,6 +848,113 @@ func Test_processResults_ShouldNotSendAnalyticsToAPIIfDisabled(t *testing.T) {
 	f.processResults(data)
 }
 
+func Test_processResults_ShouldNotSendAnalyticsToAPIIfDoNotTrack(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAnalyticsEnabled(true)
+	t.Setenv(config.DoNotTrack, "1")
+
+	engineMock, gafConfig := setUpEngineMock(t, c)
+
+	f, _ := NewMockFolderWithScanNotifier(notification.NewNotifier())
+	data := vulnmap.ScanData{
+		Product: product.ProductOpenSource,
+		Issues:  []vulnmap.Issue{NewMockIssue("id1", "path1")},
+	}
+
+	engineMock.EXPECT().GetConfiguration().AnyTimes().Return(gafConfig)
+	engineMock.EXPECT().InvokeWithInputAndConfig(localworkflows.WORKFLOWID_REPORT_ANALYTICS, gomock.Any(),
+		gomock.Any()).Times(0)
+
+	f.processResults(data)
+}
+
+func Test_processResults_SendsOneAnalyticsEventWithinDeduplicationWindow(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAnalyticsEnabled(true)
+	c.SetAnalyticsDeduplicationWindow(time.Minute)
+
+	engineMock, gafConfig := setUpEngineMock(t, c)
+	f, _ := NewMockFolderWithScanNotifier(notification.NewNotifier())
+	fakeClock := clock.NewFakeClock(time.Now())
+	f.clock = fakeClock
+	data := vulnmap.ScanData{
+		Product: product.ProductOpenSource,
+		Issues:  []vulnmap.Issue{NewMockIssue("id1", "path1")},
+	}
+
+	engineMock.EXPECT().GetConfiguration().AnyTimes().Return(gafConfig)
+	engineMock.EXPECT().InvokeWithInputAndConfig(localworkflows.WORKFLOWID_REPORT_ANALYTICS, gomock.Any(), gomock.Any()).
+		Times(1)
+
+	for i := 0; i < 3; i++ {
+		f.processResults(data)
+		fakeClock.Advance(10 * time.Second)
+	}
+
+	// after the window, the next scan is reported again
+	engineMock.EXPECT().InvokeWithInputAndConfig(localworkflows.WORKFLOWID_REPORT_ANALYTICS, gomock.Any(), gomock.Any()).
+		Times(1)
+	fakeClock.Advance(time.Minute)
+	f.processResults(data)
+}
+
+func Test_ResendFailedAnalytics_ResendsEventsWhoseSendingFailed(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAnalyticsEnabled(true)
+	engineMock, gafConfig := setUpEngineMock(t, c)
+	f, _ := NewMockFolderWithScanNotifier(notification.NewNotifier())
+	data := vulnmap.ScanData{
+		Product: product.ProductOpenSource,
+		Issues:  []vulnmap.Issue{NewMockIssue("id1", "path1")},
+	}
+	var sentPayloads [][]byte
+	capturePayload := func(_ workflow.Identifier, workflowInputData []workflow.Data, _ configuration.Configuration) {
+		payload, ok := workflowInputData[0].GetPayload().([]byte)
+		require.True(t, ok)
+		sentPayloads = append(sentPayloads, payload)
+	}
+	engineMock.EXPECT().GetConfiguration().AnyTimes().Return(gafConfig)
+	gomock.InOrder(
+		engineMock.EXPECT().InvokeWithInputAndConfig(localworkflows.WORKFLOWID_REPORT_ANALYTICS, gomock.Any(), gomock.Any()).
+			Do(capturePayload).Return(nil, errors.New("network down")),
+		engineMock.EXPECT().InvokeWithInputAndConfig(localworkflows.WORKFLOWID_REPORT_ANALYTICS, gomock.Any(), gomock.Any()).
+			Do(capturePayload).Return(nil, nil),
+	)
+	f.processResults(data)
+	require.Equal(t, []product.Product{product.ProductOpenSource}, f.FailedAnalyticsProducts())
+
+	resent, err := f.ResendFailedAnalytics()
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, resent)
+	assert.Empty(t, f.FailedAnalyticsProducts())
+	require.Len(t, sentPayloads, 2)
+	assert.Equal(t, sentPayloads[0], sentPayloads[1])
+
+	resent, err = f.ResendFailedAnalytics()
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, resent)
+}
+
+func Test_ResendFailedAnalytics_KeepsEventsIfAnalyticsDisabled(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAnalyticsEnabled(true)
+	engineMock, gafConfig := setUpEngineMock(t, c)
+	f, _ := NewMockFolderWithScanNotifier(notification.NewNotifier())
+	engineMock.EXPECT().GetConfiguration().AnyTimes().Return(gafConfig)
+	engineMock.EXPECT().InvokeWithInputAndConfig(localworkflows.WORKFLOWID_REPORT_ANALYTICS, gomock.Any(), gomock.Any()).
+		Times(1).Return(nil, errors.New("network down"))
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource})
+	c.SetAnalyticsEnabled(false)
+
+	resent, err := f.ResendFailedAnalytics()
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, resent)
+	assert.Equal(t, []product.Product{product.ProductOpenSource}, f.FailedAnalyticsProducts())
+}
+
 func Test_processResults_ShouldCountSeverityByProduct(t *testing.T) {
 	c := testutil.UnitTest(t)
 	c.SetAnalyticsEnabled(false)
@@ -597,6 +1045,99 @@ func NewMockFolderWithScanNotifier(notifier noti.Notifier) (*Folder, *vulnmap.Mo
 	return NewFolder("dummy", "dummy", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), scanNotifier, notifier), scanNotifier
 }
 
+type panickingScanner struct {
+	processResults bool
+}
+
+func (s *panickingScanner) Init() error { return nil }
+
+func (s *panickingScanner) Scan(_ context.Context, path string, processResults vulnmap.ScanResultProcessor, _ string) {
+	if s.processResults {
+		processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue("1", path)}})
+		return
+	}
+	panic("scanner failed")
+}
+
+// panickingScanNotifier panics when results are published
+type panickingScanNotifier struct {
+	*vulnmap.MockScanNotifier
+}
+
+func (n *panickingScanNotifier) SendSuccess(product.Product, string, string, []vulnmap.Issue) {
+	panic("publishing failed")
+}
+
+type recordingErrorReporter struct {
+	mutex  sync.Mutex
+	errors []error
+}
+
+func (r *recordingErrorReporter) FlushErrorReporting() {}
+
+func (r *recordingErrorReporter) CaptureError(err error) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.errors = append(r.errors, err)
+	return true
+}
+
+func (r *recordingErrorReporter) CaptureErrorAndReportAsIssue(_ string, err error) bool {
+	return r.CaptureError(err)
+}
+
+func Test_ScanFolder_PanicIsReportedAndMarksFolderAsErrored(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	errorReporter := &recordingErrorReporter{}
+	f := NewFolder(folderPath, "Test", &panickingScanner{}, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	f.SetErrorReporter(errorReporter)
+
+	assert.NotPanics(t, func() { f.ScanFolder(context.Background()) })
+
+	assert.Equal(t, ScanErrored, f.Status())
+	assert.False(t, f.IsScanned())
+	require.Len(t, errorReporter.errors, 1)
+	assert.Contains(t, errorReporter.errors[0].Error(), "panic during scan of "+folderPath)
+	assert.Contains(t, errorReporter.errors[0].Error(), "scanner failed")
+	assert.Contains(t, errorReporter.errors[0].Error(), "folder_test.go")
+	assert.NotContains(t, errorReporter.errors[0].Error(), filepath.Join("workspace", "folder_test.go"))
+}
+
+func Test_ScanFolder_PanicDuringResultProcessingIsReportedWithProduct(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	errorReporter := &recordingErrorReporter{}
+	scanNotifier := &panickingScanNotifier{vulnmap.NewMockScanNotifier()}
+	f := NewFolder(folderPath, "Test", &panickingScanner{processResults: true}, hover.NewFakeHoverService(), scanNotifier, notification.NewNotifier())
+	f.SetErrorReporter(errorReporter)
+
+	f.ScanFolder(context.Background())
+
+	assert.Equal(t, ScanErrored, f.Status())
+	require.Len(t, errorReporter.errors, 1)
+	assert.Contains(t, errorReporter.errors[0].Error(), "publishing failed")
+	assert.Contains(t, errorReporter.errors[0].Error(), string(product.ProductOpenSource))
+	assert.Equal(t, []string{folderPath}, scanNotifier.ErrorCalls())
+}
+
+func Test_ScanFolder_PanicDoesNotAffectOtherFolders(t *testing.T) {
+	testutil.UnitTest(t)
+	notifier := notification.NewNotifier()
+	w := New(performance.NewInstrumentor(), vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.SetErrorReporter(&recordingErrorReporter{})
+	failing := NewFolder(t.TempDir(), "failing", &panickingScanner{}, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	healthy := NewFolder(t.TempDir(), "healthy", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(failing)
+	w.AddFolder(healthy)
+
+	failing.ScanFolder(context.Background())
+	healthy.ScanFolder(context.Background())
+
+	assert.Equal(t, ScanErrored, failing.Status())
+	assert.True(t, healthy.IsScanned())
+}
+
 func NewMockIssue(id, path string) vulnmap.Issue {
 	return vulnmap.Issue{
 		ID:               id,
@@ -625,3 +1166,205 @@ func setUpEngineMock(t *testing.T, c *config.Config) (*mocks.MockEngine, configu
 	c.SetEngine(mockEngine)
 	return mockEngine, engineConfig
 }
+
+type fakeChangedFilesProvider struct {
+	changedFiles []string
+	err          error
+}
+
+func (p *fakeChangedFilesProvider) ChangedFiles(_ string, _ string) ([]string, error) {
+	return p.changedFiles, p.err
+}
+
+// setupChangedFilesFolder creates a folder with the given files, of which the changed files are reported as changed
+func setupChangedFilesFolder(t *testing.T, scanner vulnmap.Scanner, files []string, changedFiles ...string) (*Folder, string) {
+	t.Helper()
+	folderPath := t.TempDir()
+	for _, file := range files {
+		testutil.CreateFileOrFail(t, filepath.Join(folderPath, file), []byte("{}"))
+	}
+	f := NewFolder(folderPath, "dummy", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	provider := &fakeChangedFilesProvider{}
+	for _, file := range changedFiles {
+		provider.changedFiles = append(provider.changedFiles, filepath.Join(folderPath, file))
+	}
+	f.changedFilesProvider = provider
+	return f, folderPath
+}
+
+func Test_ScanFolder_ChangedFilesOnly_ScansAndPublishesOnlyChangedFiles(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetScanChangedFilesOnly(true)
+	scanner := vulnmap.NewTestScanner()
+	f, folderPath := setupChangedFilesFolder(t, scanner, []string{"changed", "unchanged"}, "changed", "deleted")
+	changed := filepath.Join(folderPath, "changed")
+	unchanged := filepath.Join(folderPath, "unchanged")
+	scanner.AddTestIssue(NewMockIssue("id1", changed))
+	scanner.AddTestIssue(NewMockIssue("id2", unchanged))
+
+	f.ScanFolder(context.Background())
+	filteredDiagnostics := f.filterCachedDiagnostics()
+
+	assert.Equal(t, 1, scanner.Calls(), "only the existing changed file should be scanned")
+	assert.Len(t, filteredDiagnostics[changed], 1)
+	assert.Empty(t, filteredDiagnostics[unchanged])
+	assert.Contains(t, filteredDiagnostics, unchanged, "unchanged files should be published empty to clear diagnostics")
+}
+
+func Test_ScanFolder_ChangedFilesOnly_NonGitFolderFallsBackToFullScan(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetScanChangedFilesOnly(true)
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(NewMockIssue("id1", "file1"))
+	scanner.AddTestIssue(NewMockIssue("id2", "file2"))
+	f := NewFolder("dummy", "dummy", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	f.changedFilesProvider = &fakeChangedFilesProvider{err: errors.New("not a git repository")}
+
+	f.ScanFolder(context.Background())
+	filteredDiagnostics := f.filterCachedDiagnostics()
+
+	assert.Len(t, filteredDiagnostics["file1"], 1)
+	assert.Len(t, filteredDiagnostics["file2"], 1)
+}
+
+func Test_ScanFile_ChangedFilesOnly_SkipsUnchangedFiles(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetScanChangedFilesOnly(true)
+	scanner := vulnmap.NewTestScanner()
+	f, folderPath := setupChangedFilesFolder(t, scanner, []string{"changed", "unchanged"}, "changed")
+	f.ScanFolder(context.Background())
+	callsAfterFolderScan := scanner.Calls()
+
+	f.ScanFile(context.Background(), filepath.Join(folderPath, "unchanged"))
+	assert.Equal(t, callsAfterFolderScan, scanner.Calls())
+
+	f.ScanFile(context.Background(), filepath.Join(folderPath, "changed"))
+	assert.Equal(t, callsAfterFolderScan+1, scanner.Calls())
+}
+
+func Test_ScanFile_ChangedFilesOnly_RefreshesTheChangedFiles(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetScanChangedFilesOnly(true)
+	scanner := vulnmap.NewTestScanner()
+	f, folderPath := setupChangedFilesFolder(t, scanner, []string{"changed", "edited"}, "changed")
+	edited := filepath.Join(folderPath, "edited")
+	f.ScanFolder(context.Background())
+	callsAfterFolderScan := scanner.Calls()
+
+	f.changedFilesProvider.(*fakeChangedFilesProvider).changedFiles = append(
+		f.changedFilesProvider.(*fakeChangedFilesProvider).changedFiles, edited)
+	f.ScanFile(context.Background(), edited)
+
+	assert.Equal(t, callsAfterFolderScan+1, scanner.Calls())
+	assert.True(t, f.isChangedFile(edited))
+}
+
+func setOpenedFileScanDebounce(t *testing.T, debounce time.Duration) {
+	t.Helper()
+	previousDebounce := openedFileScanDebounce
+	openedFileScanDebounce = debounce
+	t.Cleanup(func() { openedFileScanDebounce = previousDebounce })
+}
+
+func Test_ScanOpenedFile_WhenFolderUnscanned_ScansFileOnceAndPublishesDiagnostics(t *testing.T) {
+	testutil.UnitTest(t)
+	setOpenedFileScanDebounce(t, 50*time.Millisecond)
+	folderPath := t.TempDir()
+	filePath := filepath.Join(folderPath, "package.json")
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(NewMockIssue("lodash-issue", filePath))
+	notifier := notification.NewMockNotifier()
+	f := NewFolder(folderPath, "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+
+	f.ScanOpenedFile(context.Background(), filePath)
+	f.ScanOpenedFile(context.Background(), filePath)
+
+	require.Eventually(t, func() bool { return scanner.Calls() > 0 }, time.Second, 10*time.Millisecond)
+	assert.Never(t, func() bool { return scanner.Calls() > 1 }, 200*time.Millisecond, 10*time.Millisecond)
+	published := false
+	for _, msg := range notifier.SentMessages() {
+		if params, ok := msg.(lsp.PublishDiagnosticsParams); ok && params.URI == uri.PathToUri(filePath) {
+			published = len(params.Diagnostics) == 1
+		}
+	}
+	assert.True(t, published, "diagnostics of the opened file should be published")
+	assert.False(t, f.IsScanned(), "scanning an opened file must not mark the folder as scanned")
+}
+
+func Test_ScanOpenedFile_WhenFolderScanned_DoesNotScan(t *testing.T) {
+	testutil.UnitTest(t)
+	setOpenedFileScanDebounce(t, 10*time.Millisecond)
+	scanner := vulnmap.NewTestScanner()
+	f := NewFolder(t.TempDir(), "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	f.SetStatus(Scanned)
+
+	f.ScanOpenedFile(context.Background(), filepath.Join(f.Path(), "package.json"))
+
+	assert.Never(t, func() bool { return scanner.Calls() > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+}
+
+func Test_ScanOpenedFile_WhenFolderScanStartsDuringDebounce_DoesNotScan(t *testing.T) {
+	testutil.UnitTest(t)
+	setOpenedFileScanDebounce(t, 50*time.Millisecond)
+	scanner := vulnmap.NewTestScanner()
+	f := NewFolder(t.TempDir(), "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+
+	f.ScanOpenedFile(context.Background(), filepath.Join(f.Path(), "package.json"))
+	f.mutex.Lock()
+	f.folderScanInProgress = true
+	f.mutex.Unlock()
+
+	assert.Never(t, func() bool { return scanner.Calls() > 0 }, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func Test_incrementSeverityCount_EmptyProduct(t *testing.T) {
+	issue := vulnmap.Issue{
+		ID:        "VULNMAP-JS-LODASH-1",
+		Severity:  vulnmap.High,
+		IssueType: vulnmap.DependencyVulnerability,
+	}
+
+	t.Run("keep counts the issue as unknown", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		c.SetUnknownProductCounting(config.UnknownProductCountingKeep)
+		scanData := vulnmap.ScanData{}
+
+		incrementSeverityCount(&scanData, issue)
+
+		require.Equal(t, 1, scanData.SeverityCount["unknown"].High)
+		require.Len(t, scanData.SeverityCount, 1)
+	})
+
+	t.Run("drop doesn't count the issue", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		c.SetUnknownProductCounting(config.UnknownProductCountingDrop)
+		scanData := vulnmap.ScanData{}
+
+		incrementSeverityCount(&scanData, issue)
+
+		require.Empty(t, scanData.SeverityCount)
+	})
+
+	t.Run("infer counts the issue for the product of its issue type", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		c.SetUnknownProductCounting(config.UnknownProductCountingInfer)
+		scanData := vulnmap.ScanData{}
+
+		incrementSeverityCount(&scanData, issue)
+
+		require.Equal(t, 1, scanData.SeverityCount[product.ProductOpenSource].High)
+		require.Len(t, scanData.SeverityCount, 1)
+	})
+
+	t.Run("infer counts the issue as unknown if the issue type has no product", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		c.SetUnknownProductCounting(config.UnknownProductCountingInfer)
+		scanData := vulnmap.ScanData{}
+		containerIssue := issue
+		containerIssue.IssueType = vulnmap.ContainerVulnerability
+
+		incrementSeverityCount(&scanData, containerIssue)
+
+		require.Equal(t, 1, scanData.SeverityCount["unknown"].High)
+	})
+}
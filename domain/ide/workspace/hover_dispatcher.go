@@ -0,0 +1,206 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+)
+
+// hoverSendTimeout is how long the background goroutine waits for the hover service to accept a document before
+// the document is dropped
+var hoverSendTimeout = 30 * time.Second
+
+// hoverDispatcher forwards hover documents to the hover service without blocking the caller. Documents are sent
+// directly while the hover channel has capacity. Otherwise, they are queued and sent by a background goroutine,
+// and a queued document is replaced by a newer document for the same path, so stale hovers are never delivered.
+// Once the hover channel is closed, e.g. because the hover service shut down, or the dispatcher is stopped, all
+// documents are dropped.
+type hoverDispatcher struct {
+	hoverService hover.Service
+	stopCtx      context.Context
+	mutex        sync.Mutex
+	pending      map[string]hover.DocumentHovers
+	order        []string
+	sending      bool
+	closed       bool
+	signal       chan struct{}
+	startOnce    sync.Once
+}
+
+func newHoverDispatcher(stopCtx context.Context, hoverService hover.Service) *hoverDispatcher {
+	return &hoverDispatcher{
+		hoverService: hoverService,
+		stopCtx:      stopCtx,
+		pending:      map[string]hover.DocumentHovers{},
+		signal:       make(chan struct{}, 1),
+	}
+}
+
+func (d *hoverDispatcher) dispatch(document hover.DocumentHovers) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.closed {
+		log.Trace().Str("method", "hoverDispatcher.dispatch").Str("path", document.Path).
+			Msg("hover channel is closed, dropping hover document")
+		return
+	}
+	if d.stopCtx.Err() != nil {
+		log.Trace().Str("method", "hoverDispatcher.dispatch").Str("path", document.Path).
+			Msg("hover dispatcher is stopped, dropping hover document")
+		return
+	}
+	if !d.sending && len(d.pending) == 0 {
+		sent, closed := d.send(document, 0)
+		if sent {
+			return
+		}
+		if closed {
+			d.close()
+			return
+		}
+	}
+
+	if _, queued := d.pending[document.Path]; !queued {
+		d.order = append(d.order, document.Path)
+	} else {
+		log.Trace().Str("method", "hoverDispatcher.dispatch").Str("path", document.Path).
+			Msg("replacing stale hover document")
+	}
+	d.pending[document.Path] = document
+	d.startOnce.Do(func() { go d.run() })
+	select {
+	case d.signal <- struct{}{}:
+	default:
+	}
+}
+
+// discard drops the queued hover document of the path
+func (d *hoverDispatcher) discard(path string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if _, queued := d.pending[path]; !queued {
+		return
+	}
+	delete(d.pending, path)
+	for i, queuedPath := range d.order {
+		if queuedPath == path {
+			d.order = append(d.order[:i], d.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// discardAll drops all queued hover documents
+func (d *hoverDispatcher) discardAll() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.pending = map[string]hover.DocumentHovers{}
+	d.order = nil
+}
+
+func (d *hoverDispatcher) run() {
+	for {
+		select {
+		case <-d.stopCtx.Done():
+			return
+		case <-d.signal:
+		}
+
+		for {
+			document, ok := d.next()
+			if !ok {
+				break
+			}
+			sent, closed := d.send(document, hoverSendTimeout)
+			if d.stopCtx.Err() != nil {
+				return
+			}
+			d.mutex.Lock()
+			d.sending = false
+			if closed {
+				d.close()
+			}
+			d.mutex.Unlock()
+			if closed {
+				return
+			}
+			if !sent {
+				log.Warn().Str("method", "hoverDispatcher.run").Str("path", document.Path).
+					Msg("hover service did not accept the hover document in time, dropping it")
+			}
+		}
+	}
+}
+
+// send sends the document to the hover service, waiting at most the timeout for the channel to accept it. A zero
+// timeout doesn't wait. A closed channel is reported instead of panicking.
+func (d *hoverDispatcher) send(document hover.DocumentHovers, timeout time.Duration) (sent bool, closed bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Warn().Str("method", "hoverDispatcher.send").Str("path", document.Path).Interface("panic", r).
+				Msg("couldn't send hover document, the hover channel is closed")
+			sent, closed = false, true
+		}
+	}()
+
+	if timeout == 0 {
+		select {
+		case d.hoverService.Channel() <- document:
+			return true, false
+		default:
+			return false, false
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case d.hoverService.Channel() <- document:
+		return true, false
+	case <-d.stopCtx.Done():
+	case <-timer.C:
+	}
+	return false, false
+}
+
+// close drops all queued documents and makes the dispatcher drop all further documents. The mutex must be held.
+func (d *hoverDispatcher) close() {
+	d.closed = true
+	d.pending = map[string]hover.DocumentHovers{}
+	d.order = nil
+}
+
+func (d *hoverDispatcher) next() (hover.DocumentHovers, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if len(d.order) == 0 {
+		return hover.DocumentHovers{}, false
+	}
+	path := d.order[0]
+	d.order = d.order[1:]
+	document := d.pending[path]
+	delete(d.pending, path)
+	d.sending = true
+	return document, true
+}
@@ -0,0 +1,241 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	ux2 "github.com/khulnasoft-lab/vulnmap-ls/domain/observability/ux"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+// slowHoverService has an unbuffered hover channel, so that every hover document waits for the consumer
+type slowHoverService struct {
+	hovers   chan hover.DocumentHovers
+	mutex    sync.Mutex
+	received map[string]hover.DocumentHovers
+}
+
+func newSlowHoverService() *slowHoverService {
+	return &slowHoverService{hovers: make(chan hover.DocumentHovers), received: map[string]hover.DocumentHovers{}}
+}
+
+func (s *slowHoverService) consume(t *testing.T, delay time.Duration) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case document := <-s.hovers:
+				time.Sleep(delay)
+				s.mutex.Lock()
+				s.received[document.Path] = document
+				s.mutex.Unlock()
+			}
+		}
+	}()
+}
+
+func (s *slowHoverService) receivedDocuments() map[string]hover.DocumentHovers {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	received := map[string]hover.DocumentHovers{}
+	for path, document := range s.received {
+		received[path] = document
+	}
+	return received
+}
+
+func (s *slowHoverService) DeleteHover(_ string)                               {}
+func (s *slowHoverService) Channel() chan hover.DocumentHovers                 { return s.hovers }
+func (s *slowHoverService) ClearAllHovers()                                    {}
+func (s *slowHoverService) GetHover(_ string, _ vulnmap.Position) hover.Result { return hover.Result{} }
+func (s *slowHoverService) SetAnalytics(_ ux2.Analytics)                       {}
+func (s *slowHoverService) Close()                                             {}
+
+func Test_processResults_SlowHoverConsumerDoesNotBlockDiagnostics(t *testing.T) {
+	testutil.UnitTest(t)
+	hoverService := newSlowHoverService()
+	hoverService.consume(t, 100*time.Millisecond)
+	notifier := notification.NewMockNotifier()
+	f := NewFolder(t.TempDir(), "dummy", vulnmap.NewTestScanner(), hoverService, vulnmap.NewMockScanNotifier(), notifier)
+	t.Cleanup(f.StopScans)
+	const fileCount = 5
+	var issues []vulnmap.Issue
+	for i := 0; i < fileCount; i++ {
+		issues = append(issues, vulnmap.Issue{
+			ID:               fmt.Sprintf("id-%d", i),
+			AffectedFilePath: filepath.Join(f.path, fmt.Sprintf("file-%d", i)),
+			Product:          product.ProductOpenSource,
+			Severity:         vulnmap.High,
+		})
+	}
+
+	start := time.Now()
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: issues})
+
+	assert.Less(t, time.Since(start), 200*time.Millisecond)
+	diagnosticsCount := 0
+	for _, msg := range notifier.SentMessages() {
+		if _, ok := msg.(lsp.PublishDiagnosticsParams); ok {
+			diagnosticsCount++
+		}
+	}
+	assert.Equal(t, fileCount, diagnosticsCount)
+	assert.Eventually(t, func() bool {
+		return len(hoverService.receivedDocuments()) == fileCount
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_hoverDispatcher_ReplacesStaleQueuedDocuments(t *testing.T) {
+	hoverService := newSlowHoverService()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcher := newHoverDispatcher(ctx, hoverService)
+	staleHover := []hover.Hover[hover.Context]{{Id: "stale"}}
+	currentHover := []hover.Hover[hover.Context]{{Id: "current"}}
+
+	// nobody consumes yet, so the first document blocks the background goroutine and the others are queued
+	dispatcher.dispatch(hover.DocumentHovers{Path: "a"})
+	require.Eventually(t, func() bool {
+		dispatcher.mutex.Lock()
+		defer dispatcher.mutex.Unlock()
+		return dispatcher.sending
+	}, time.Second, time.Millisecond)
+	dispatcher.dispatch(hover.DocumentHovers{Path: "b", Hover: staleHover})
+	dispatcher.dispatch(hover.DocumentHovers{Path: "b", Hover: currentHover})
+	hoverService.consume(t, 0)
+
+	assert.Eventually(t, func() bool {
+		return len(hoverService.receivedDocuments()) == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, currentHover, hoverService.receivedDocuments()["b"].Hover)
+}
+
+func Test_hoverDispatcher_DiscardDropsQueuedDocuments(t *testing.T) {
+	hoverService := newSlowHoverService()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcher := newHoverDispatcher(ctx, hoverService)
+
+	dispatcher.dispatch(hover.DocumentHovers{Path: "a"})
+	require.Eventually(t, func() bool {
+		dispatcher.mutex.Lock()
+		defer dispatcher.mutex.Unlock()
+		return dispatcher.sending
+	}, time.Second, time.Millisecond)
+	dispatcher.dispatch(hover.DocumentHovers{Path: "b"})
+	dispatcher.dispatch(hover.DocumentHovers{Path: "c"})
+	dispatcher.discard("b")
+	hoverService.consume(t, 0)
+
+	assert.Eventually(t, func() bool {
+		return len(hoverService.receivedDocuments()) == 2
+	}, time.Second, time.Millisecond)
+	assert.NotContains(t, hoverService.receivedDocuments(), "b")
+}
+
+func Test_processResults_ClosedHoverChannelDoesNotPanic(t *testing.T) {
+	testutil.UnitTest(t)
+	hoverService := newSlowHoverService()
+	close(hoverService.hovers)
+	notifier := notification.NewMockNotifier()
+	f := NewFolder(t.TempDir(), "dummy", vulnmap.NewTestScanner(), hoverService, vulnmap.NewMockScanNotifier(), notifier)
+	t.Cleanup(f.StopScans)
+	issue := NewMockIssue("id", filepath.Join(f.path, "package.json"))
+
+	assert.NotPanics(t, func() {
+		f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{issue}})
+		f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{issue}})
+	})
+
+	assert.Len(t, f.DocumentDiagnosticsFromCache(issue.AffectedFilePath), 1)
+	assert.NotEmpty(t, notifier.SentMessages())
+}
+
+func Test_hoverDispatcher_SendReportsClosedChannel(t *testing.T) {
+	hoverService := newSlowHoverService()
+	close(hoverService.hovers)
+	dispatcher := newHoverDispatcher(context.Background(), hoverService)
+
+	for _, timeout := range []time.Duration{0, time.Second} {
+		sent, closed := dispatcher.send(hover.DocumentHovers{Path: "a"}, timeout)
+
+		assert.False(t, sent)
+		assert.True(t, closed)
+	}
+}
+
+func Test_hoverDispatcher_DropsDocumentWhenChannelStaysFull(t *testing.T) {
+	previousTimeout := hoverSendTimeout
+	hoverSendTimeout = 10 * time.Millisecond
+	t.Cleanup(func() { hoverSendTimeout = previousTimeout })
+	hoverService := newSlowHoverService()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcher := newHoverDispatcher(ctx, hoverService)
+
+	start := time.Now()
+	dispatcher.dispatch(hover.DocumentHovers{Path: "a"})
+	dispatcher.dispatch(hover.DocumentHovers{Path: "b"})
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		dispatcher.mutex.Lock()
+		defer dispatcher.mutex.Unlock()
+		return !dispatcher.sending && len(dispatcher.pending) == 0
+	}, time.Second, time.Millisecond)
+
+	// the dispatcher keeps working once the consumer catches up
+	hoverService.consume(t, 0)
+	dispatcher.dispatch(hover.DocumentHovers{Path: "c"})
+	assert.Eventually(t, func() bool {
+		_, received := hoverService.receivedDocuments()["c"]
+		return received
+	}, time.Second, time.Millisecond)
+}
+
+func Test_hoverDispatcher_StoppedDispatcherDropsDocuments(t *testing.T) {
+	hoverService := newSlowHoverService()
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher := newHoverDispatcher(ctx, hoverService)
+	cancel()
+
+	dispatcher.dispatch(hover.DocumentHovers{Path: "a"})
+	dispatcher.dispatch(hover.DocumentHovers{Path: "b"})
+
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+	assert.Empty(t, dispatcher.pending)
+	assert.Empty(t, dispatcher.order)
+}
@@ -0,0 +1,98 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// IssueDelta contains the issues of a product that were added and removed by its last scan, counted by severity
+type IssueDelta struct {
+	Added   vulnmap.SeverityCount
+	Removed vulnmap.SeverityCount
+}
+
+// updateIssueDelta compares the cached issues of the product with the issues after its previous scan and stores
+// the difference. The first scan of a product has an empty delta.
+func (f *Folder) updateIssueDelta(p product.Product) {
+	current := map[string]vulnmap.Severity{}
+	f.documentDiagnosticCache.Range(func(_ string, issues []vulnmap.Issue) bool {
+		for _, issue := range issues {
+			if issue.Product == p {
+				current[issue.Fingerprint()] = issue.Severity
+			}
+		}
+		return true
+	})
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.issueFingerprints == nil {
+		f.issueFingerprints = map[product.Product]map[string]vulnmap.Severity{}
+		f.issueDeltas = map[product.Product]IssueDelta{}
+	}
+	previous := f.issueFingerprints[p]
+	f.issueFingerprints[p] = current
+
+	delta := IssueDelta{}
+	if previous != nil {
+		for fingerprint, severity := range current {
+			if _, found := previous[fingerprint]; !found {
+				addToSeverityCount(&delta.Added, severity)
+			}
+		}
+		for fingerprint, severity := range previous {
+			if _, found := current[fingerprint]; !found {
+				addToSeverityCount(&delta.Removed, severity)
+			}
+		}
+	}
+	f.issueDeltas[p] = delta
+}
+
+// IssueDeltas returns the issue delta of each product that was scanned since the cache was last cleared
+func (f *Folder) IssueDeltas() map[product.Product]IssueDelta {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	deltas := make(map[product.Product]IssueDelta, len(f.issueDeltas))
+	for p, delta := range f.issueDeltas {
+		deltas[p] = delta
+	}
+	return deltas
+}
+
+// resetIssueDeltas discards the deltas of the last scans. The issues of the last scans are kept, so that the next
+// scans are still compared to them.
+func (f *Folder) resetIssueDeltas() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.issueDeltas = map[product.Product]IssueDelta{}
+}
+
+func addToSeverityCount(count *vulnmap.SeverityCount, severity vulnmap.Severity) {
+	switch severity {
+	case vulnmap.Critical:
+		count.Critical++
+	case vulnmap.High:
+		count.High++
+	case vulnmap.Medium:
+		count.Medium++
+	case vulnmap.Low:
+		count.Low++
+	}
+}
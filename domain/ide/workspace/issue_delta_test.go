@@ -0,0 +1,78 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_IssueDeltas_ComparesScanWithPreviousScan(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	manifest := filepath.Join(folderPath, "package.json")
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(vulnmap.NewTestOssIssue("kept", manifest, vulnmap.High))
+	scanner.AddTestIssue(vulnmap.NewTestOssIssue("fixed", manifest, vulnmap.Critical))
+	f := NewFolder(folderPath, "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+
+	f.ScanFolder(context.Background())
+
+	assert.Equal(t, map[product.Product]IssueDelta{product.ProductOpenSource: {}}, f.IssueDeltas(),
+		"the first scan has nothing to compare to")
+
+	scanner.Issues = []vulnmap.Issue{
+		vulnmap.NewTestOssIssue("kept", manifest, vulnmap.High),
+		vulnmap.NewTestOssIssue("new-1", manifest, vulnmap.High),
+		vulnmap.NewTestOssIssue("new-2", manifest, vulnmap.Low),
+	}
+	f.ClearDiagnostics()
+	f.ScanFolder(context.Background())
+
+	assert.Equal(t, IssueDelta{
+		Added:   vulnmap.SeverityCount{High: 1, Low: 1},
+		Removed: vulnmap.SeverityCount{Critical: 1},
+	}, f.IssueDeltas()[product.ProductOpenSource])
+}
+
+func Test_IssueDeltas_ResetWhenCacheIsCleared(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	manifest := filepath.Join(folderPath, "package.json")
+	scanner := vulnmap.NewTestScanner()
+	f := NewFolder(folderPath, "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	f.ScanFolder(context.Background())
+	scanner.AddTestIssue(vulnmap.NewTestOssIssue("new", manifest, vulnmap.Medium))
+	f.ScanFolder(context.Background())
+	assert.Equal(t, 1, f.IssueDeltas()[product.ProductOpenSource].Added.Medium)
+
+	f.ClearDiagnostics()
+
+	assert.Empty(t, f.IssueDeltas())
+	f.ScanFolder(context.Background())
+	assert.Equal(t, IssueDelta{}, f.IssueDeltas()[product.ProductOpenSource],
+		"the scan after clearing the cache is compared to the scan before")
+}
@@ -0,0 +1,157 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// issueFilter is a snapshot of the settings that determine which issues are visible. Filtering with a snapshot
+// gives a consistent result even if the settings change during filtering, and avoids reading the global config
+// for every issue.
+type issueFilter struct {
+	severityFilter       lsp.SeverityFilter
+	supportedIssueTypes  map[product.FilterableIssueType]bool
+	policy               *vulnmap.SuppressionPolicy
+	baseline             *vulnmap.Baseline
+	acceptedIssues       *vulnmap.AcceptedIssues
+	issueTags            *vulnmap.IssueTags
+	tagFilter            []string
+	showOnlyFixable      bool
+	includeMajorUpgrades bool
+	minEpssScore         float64
+	minCvssScore         float64
+	hideDevDependencies  bool
+	now                  time.Time
+}
+
+// newIssueFilter creates a filter for the issues of the folder, as baselines, accepted issues and issue tags are
+// recorded per folder
+func newIssueFilter(c *config.Config, folderPath string, supportedIssueTypes map[product.FilterableIssueType]bool) *issueFilter {
+	filter := &issueFilter{
+		severityFilter:       c.FilterSeverity(),
+		supportedIssueTypes:  supportedIssueTypes,
+		policy:               suppressionPolicyLoader.Load(c.SuppressionPolicyPath()),
+		acceptedIssues:       acceptedIssuesLoader.Load(c.AcceptedIssuesPath(folderPath)),
+		issueTags:            issueTagsLoader.Load(c.IssueTagsPath(folderPath)),
+		tagFilter:            c.IssueTagFilter(),
+		showOnlyFixable:      c.IsShowOnlyFixable(),
+		includeMajorUpgrades: c.IsMajorUpgradeFixIncluded(),
+		minEpssScore:         c.MinEpssScore(),
+		hideDevDependencies:  c.DevDependencyIssues() == config.DevDependencyIssuesHide,
+		now:                  time.Now(),
+	}
+	if c.IsBaselineEnabled() {
+		filter.baseline = baselineLoader.Load(c.BaselinePath(folderPath))
+	}
+	return filter
+}
+
+// FilterIssues returns the issues of the folder that are visible with the current settings and of the supported issue
+// types
+func FilterIssues(folderPath string, issues []vulnmap.Issue, supportedIssueTypes map[product.FilterableIssueType]bool) []vulnmap.Issue {
+	return newIssueFilter(config.CurrentConfig(), folderPath, supportedIssueTypes).filter(issues)
+}
+
+func (f *issueFilter) filter(issues []vulnmap.Issue) []vulnmap.Issue {
+	logger := log.With().Str("method", "FilterIssues").Logger()
+	filteredIssues := make([]vulnmap.Issue, 0)
+	for _, issue := range issues {
+		if suppression, suppressed := f.policy.Suppression(issue, f.now); suppressed {
+			logger.Debug().Str("issue", issue.ID).Msgf("Suppressing issue by policy: %s", suppression.Reason)
+			continue
+		}
+		if f.baseline.Contains(issue, f.now) {
+			logger.Trace().Str("issue", issue.ID).Msg("Filtering out issue accepted by baseline")
+			continue
+		}
+		if f.acceptedIssues.Contains(issue, f.now) {
+			logger.Trace().Str("issue", issue.ID).Msg("Filtering out accepted issue")
+			continue
+		}
+		if f.showOnlyFixable && !issue.IsFixable(f.includeMajorUpgrades) {
+			logger.Trace().Str("issue", issue.ID).Msg("Filtering out unfixable issue")
+			continue
+		}
+		if !issue.MeetsMinEpssScore(f.minEpssScore) {
+			logger.Trace().Str("issue", issue.ID).Msg("Filtering out issue below min EPSS score")
+			continue
+		}
+		if !issue.MeetsMinCvssScore(f.minCvssScore) {
+			logger.Trace().Str("issue", issue.ID).Msg("Filtering out issue below min CVSS score")
+			continue
+		}
+		if f.hideDevDependencies && issue.IsDevDependency() {
+			logger.Trace().Str("issue", issue.ID).Msg("Filtering out dev dependency issue")
+			continue
+		}
+		if f.issueTags != nil || len(f.tagFilter) > 0 {
+			issue.Tags = f.issueTags.Tags(issue.Fingerprint())
+			if !matchesTagFilter(issue.Tags, f.tagFilter) {
+				logger.Trace().Str("issue", issue.ID).Msg("Filtering out issue without filtered tag")
+				continue
+			}
+		}
+		// Logging here might hurt performance, should benchmark if filtering is slow
+		if f.isVisibleSeverity(issue) && f.supportedIssueTypes[issue.GetFilterableIssueType()] {
+			logger.Trace().Msgf("Including visible severity issue: %v", issue)
+			filteredIssues = append(filteredIssues, issue)
+		} else {
+			logger.Trace().Msgf("Filtering out issue %v", issue)
+		}
+	}
+	return filteredIssues
+}
+
+// matchesTagFilter returns true if there is no tag filter or the tags contain one of the filtered tags
+func matchesTagFilter(tags []string, tagFilter []string) bool {
+	if len(tagFilter) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		for _, filtered := range tagFilter {
+			if tag == filtered {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *issueFilter) isVisibleSeverity(issue vulnmap.Issue) bool {
+	switch issue.Severity {
+	case vulnmap.Critical:
+		return f.severityFilter.Critical
+	case vulnmap.High:
+		return f.severityFilter.High
+	case vulnmap.Medium:
+		return f.severityFilter.Medium
+	case vulnmap.Low:
+		return f.severityFilter.Low
+	case vulnmap.Unknown:
+		// unrecognized severities cannot be filtered and are always shown
+		return true
+	}
+	return false
+}
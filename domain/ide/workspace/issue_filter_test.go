@@ -0,0 +1,87 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_issueFilter_UsesSnapshotOfConfig(t *testing.T) {
+	c := testutil.UnitTest(t)
+	criticalIssue := vulnmap.Issue{ID: "critical", Severity: vulnmap.Critical, Product: product.ProductOpenSource}
+	lowIssue := vulnmap.Issue{ID: "low", Severity: vulnmap.Low, Product: product.ProductOpenSource}
+	issues := []vulnmap.Issue{criticalIssue, lowIssue}
+
+	filter := newIssueFilter(c, "", c.DisplayableIssueTypes())
+	c.SetSeverityFilter(lsp.NewSeverityFilter(true, false, false, false))
+
+	assert.Equal(t, issues, filter.filter(issues))
+	assert.Equal(t, []vulnmap.Issue{criticalIssue}, FilterIssues("", issues, c.DisplayableIssueTypes()))
+}
+
+func Test_FilterIssues_ConsistentWhileConfigChanges(t *testing.T) {
+	allSeverities := testutil.UnitTest(t)
+	supportedIssueTypes := allSeverities.DisplayableIssueTypes()
+	criticalOnly := config.New()
+	criticalOnly.SetSeverityFilter(lsp.NewSeverityFilter(true, false, false, false))
+	t.Cleanup(func() { config.SetCurrentConfig(allSeverities) })
+
+	issues := []vulnmap.Issue{
+		{ID: "critical", Severity: vulnmap.Critical, Product: product.ProductOpenSource},
+		{ID: "high", Severity: vulnmap.High, Product: product.ProductOpenSource},
+		{ID: "medium", Severity: vulnmap.Medium, Product: product.ProductOpenSource},
+		{ID: "low", Severity: vulnmap.Low, Product: product.ProductOpenSource},
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if i%2 == 0 {
+				config.SetCurrentConfig(criticalOnly)
+			} else {
+				config.SetCurrentConfig(allSeverities)
+			}
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		filtered := FilterIssues("", issues, supportedIssueTypes)
+		// either all issues with the first config or only the critical one with the second, never a mix
+		if len(filtered) != len(issues) {
+			assert.Equal(t, issues[:1], filtered)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,62 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// IssueByFingerprint looks up a cached issue of any folder by its fingerprint. found is false if no folder has an
+// issue with the fingerprint, e.g. because the issue was fixed since the fingerprint was handed out.
+func (w *Workspace) IssueByFingerprint(fingerprint string) (issue vulnmap.Issue, found bool) {
+	_, issue, found = w.folderIssueByFingerprint(fingerprint)
+	return issue, found
+}
+
+// folderIssueByFingerprint looks up a cached issue by its fingerprint and returns it with the folder it belongs to
+func (w *Workspace) folderIssueByFingerprint(fingerprint string) (folder *Folder, issue vulnmap.Issue, found bool) {
+	for _, folder = range w.Folders() {
+		folder.documentDiagnosticCache.Range(func(_ string, issues []vulnmap.Issue) bool {
+			for _, candidate := range issues {
+				if candidate.Fingerprint() == fingerprint {
+					issue, found = candidate, true
+					return false
+				}
+			}
+			return true
+		})
+		if found {
+			return folder, issue, true
+		}
+	}
+	return nil, vulnmap.Issue{}, false
+}
+
+// folderDataPaths returns the distinct data files of the folders, e.g. their accepted issues. Folders share a file if
+// an absolute path is configured.
+func (w *Workspace) folderDataPaths(dataPath func(folderPath string) string) []string {
+	var paths []string
+	seen := map[string]bool{}
+	for _, folder := range w.Folders() {
+		path := dataPath(folder.Path())
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
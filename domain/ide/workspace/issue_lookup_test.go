@@ -0,0 +1,40 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_IssueByFingerprint(t *testing.T) {
+	testutil.UnitTest(t)
+	log4j := newOssIssue("VULNMAP-JAVA-LOG4J-1", "pom.xml", vulnmap.Critical, "log4j-core", "CVE-2021-44228")
+	lodash := newOssIssue("VULNMAP-JS-LODASH-1", "package.json", vulnmap.High, "lodash", "CVE-2021-23337")
+	w := setupSearchWorkspace(t, log4j, lodash)
+
+	issue, found := w.IssueByFingerprint(lodash.Fingerprint())
+	assert.True(t, found)
+	assert.Equal(t, lodash.ID, issue.ID)
+
+	_, found = w.IssueByFingerprint("stale-fingerprint")
+	assert.False(t, found)
+}
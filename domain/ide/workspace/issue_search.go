@@ -0,0 +1,190 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// IssueSearchFilter restricts the issues returned by a search. Empty fields don't restrict the results.
+type IssueSearchFilter struct {
+	Product     product.Product
+	MinSeverity *vulnmap.Severity
+	// UpdatedSince only returns issues whose advisory was modified or disclosed since then, issues without advisory
+	// dates are not returned
+	UpdatedSince *time.Time
+	// SortByRecency orders the results by the last advisory update instead of the match score, most recent first
+	SortByRecency bool
+}
+
+// IssueSearchResult is an issue matching a search query, with a higher score meaning a better match
+type IssueSearchResult struct {
+	Issue vulnmap.Issue
+	Score int
+}
+
+// match scores of the issue fields a query can match
+const (
+	exactIdScore        = 100
+	cveScore            = 80
+	exactPackageScore   = 70
+	cweScore            = 60
+	partialIdScore      = 50
+	partialPackageScore = 40
+	titleScore          = 30
+)
+
+// SearchIssues searches the cached issues of all folders for the query. The query is matched case-insensitively
+// against the issue id, title, package name, CVEs and CWEs. Issues hidden by the severity filter or of disabled
+// products are not returned. An empty query matches all issues.
+func (w *Workspace) SearchIssues(query string, filter IssueSearchFilter) []IssueSearchResult {
+	query = strings.ToLower(strings.TrimSpace(query))
+	c := config.CurrentConfig()
+	var results []IssueSearchResult
+	for _, folder := range w.Folders() {
+		visible := newIssueFilter(c, folder.path, c.DisplayableIssueTypes())
+		folder.documentDiagnosticCache.Range(func(_ string, issues []vulnmap.Issue) bool {
+			for _, issue := range visible.filter(issues) {
+				if !filter.matches(issue) {
+					continue
+				}
+				score := matchScore(issue, query)
+				if score > 0 || query == "" {
+					results = append(results, IssueSearchResult{Issue: issue, Score: score})
+				}
+			}
+			return true
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if filter.SortByRecency {
+			aUpdated, _ := a.Issue.AdvisoryUpdated()
+			bUpdated, _ := b.Issue.AdvisoryUpdated()
+			if !aUpdated.Equal(bUpdated) {
+				return aUpdated.After(bUpdated)
+			}
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Issue.Severity != b.Issue.Severity {
+			return a.Issue.Severity < b.Issue.Severity
+		}
+		if a.Issue.AffectedFilePath != b.Issue.AffectedFilePath {
+			return a.Issue.AffectedFilePath < b.Issue.AffectedFilePath
+		}
+		return a.Issue.ID < b.Issue.ID
+	})
+	return results
+}
+
+func (f IssueSearchFilter) matches(issue vulnmap.Issue) bool {
+	if f.Product != "" && issue.Product != f.Product {
+		return false
+	}
+	// severities are ordered from critical to low
+	if f.MinSeverity != nil && issue.Severity > *f.MinSeverity {
+		return false
+	}
+	if f.UpdatedSince != nil {
+		updated, ok := issue.AdvisoryUpdated()
+		if !ok || updated.Before(*f.UpdatedSince) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchScore(issue vulnmap.Issue, query string) int {
+	if query == "" {
+		return 0
+	}
+	score := 0
+	id := strings.ToLower(issue.ID)
+	if id == query {
+		score += exactIdScore
+	} else if strings.Contains(id, query) {
+		score += partialIdScore
+	}
+	if containsIdentifier(issue.CVEs, query) {
+		score += cveScore
+	}
+	if containsIdentifier(issue.CWEs, query) {
+		score += cweScore
+	}
+	packageName := strings.ToLower(issuePackageName(issue))
+	if packageName == query {
+		score += exactPackageScore
+	} else if packageName != "" && strings.Contains(packageName, query) {
+		score += partialPackageScore
+	}
+	if strings.Contains(strings.ToLower(IssueTitle(issue)), query) {
+		score += titleScore
+	}
+	return score
+}
+
+// containsIdentifier matches partial identifiers, ignoring case and separators, e.g. "cve 2021 44228" or "2021-44228"
+// both match "CVE-2021-44228"
+func containsIdentifier(identifiers []string, query string) bool {
+	normalizedQuery := normalizeIdentifier(query)
+	if normalizedQuery == "" {
+		return false
+	}
+	for _, identifier := range identifiers {
+		if strings.Contains(normalizeIdentifier(identifier), normalizedQuery) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeIdentifier(identifier string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(identifier))
+}
+
+// IssueTitle returns the product specific title of the issue, or its message if there is no title
+func IssueTitle(issue vulnmap.Issue) string {
+	var title string
+	switch data := issue.AdditionalData.(type) {
+	case vulnmap.OssIssueData:
+		title = data.Title
+	case vulnmap.CodeIssueData:
+		title = data.Title
+	case vulnmap.IaCIssueData:
+		title = data.Title
+	}
+	if title == "" {
+		return issue.Message
+	}
+	return title
+}
+
+func issuePackageName(issue vulnmap.Issue) string {
+	if data, ok := issue.AdditionalData.(vulnmap.OssIssueData); ok {
+		return data.PackageName
+	}
+	return ""
+}
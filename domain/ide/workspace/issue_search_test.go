@@ -0,0 +1,148 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func newOssIssue(id string, path string, severity vulnmap.Severity, packageName string, cves ...string) vulnmap.Issue {
+	issue := NewMockIssueWithSeverity(id, path, severity)
+	issue.CVEs = cves
+	issue.AdditionalData = vulnmap.OssIssueData{Key: id, Title: "Vulnerability in " + packageName, PackageName: packageName}
+	return issue
+}
+
+func setupSearchWorkspace(t *testing.T, issues ...vulnmap.Issue) *Workspace {
+	t.Helper()
+	notifier := notification.NewNotifier()
+	w := New(performance.NewInstrumentor(), vulnmap.NewTestScanner(), nil, nil, notifier)
+	f := NewMockFolder(notifier)
+	for _, issue := range issues {
+		cachedIssues, _ := f.documentDiagnosticCache.Load(issue.AffectedFilePath)
+		f.documentDiagnosticCache.Store(issue.AffectedFilePath, append(cachedIssues, issue))
+	}
+	w.AddFolder(f)
+	return w
+}
+
+func Test_SearchIssues_MatchesPartialCve(t *testing.T) {
+	testutil.UnitTest(t)
+	w := setupSearchWorkspace(t,
+		newOssIssue("VULNMAP-JAVA-LOG4J-1", "pom.xml", vulnmap.Critical, "log4j-core", "CVE-2021-44228"),
+		newOssIssue("VULNMAP-JS-LODASH-1", "package.json", vulnmap.High, "lodash", "CVE-2021-23337"),
+	)
+
+	results := w.SearchIssues("cve 2021-4422", IssueSearchFilter{})
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "VULNMAP-JAVA-LOG4J-1", results[0].Issue.ID)
+	assert.Equal(t, cveScore, results[0].Score)
+}
+
+func Test_SearchIssues_MatchesPackageNameWithSeverityFilter(t *testing.T) {
+	c := testutil.UnitTest(t)
+	w := setupSearchWorkspace(t,
+		newOssIssue("VULNMAP-JS-LODASH-1", "package.json", vulnmap.High, "lodash"),
+		newOssIssue("VULNMAP-JS-LODASH-2", "package.json", vulnmap.Critical, "lodash"),
+		newOssIssue("VULNMAP-JS-LODASH-3", "package.json", vulnmap.Low, "lodash"),
+		newOssIssue("VULNMAP-JS-LODASHES-1", "package.json", vulnmap.Critical, "lodash.template"),
+		newOssIssue("VULNMAP-JS-EXPRESS-1", "package.json", vulnmap.Critical, "express"),
+	)
+	minSeverity := vulnmap.High
+
+	results := w.SearchIssues("LODASH", IssueSearchFilter{Product: product.ProductOpenSource, MinSeverity: &minSeverity})
+
+	require.Len(t, results, 3)
+	// exact package name matches rank above partial ones, then by severity
+	assert.Equal(t, "VULNMAP-JS-LODASH-2", results[0].Issue.ID)
+	assert.Equal(t, "VULNMAP-JS-LODASH-1", results[1].Issue.ID)
+	assert.Equal(t, "VULNMAP-JS-LODASHES-1", results[2].Issue.ID)
+
+	t.Run("respects severity filter setting", func(t *testing.T) {
+		c.SetSeverityFilter(lsp.NewSeverityFilter(false, true, true, true))
+
+		results = w.SearchIssues("lodash", IssueSearchFilter{})
+
+		require.Len(t, results, 2)
+		assert.Equal(t, "VULNMAP-JS-LODASH-1", results[0].Issue.ID)
+		assert.Equal(t, "VULNMAP-JS-LODASH-3", results[1].Issue.ID)
+	})
+}
+
+func Test_SearchIssues_FiltersByProduct(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetVulnmapCodeEnabled(true)
+	codeIssue := NewMockIssue("javascript/sqlinjection", "app.js")
+	codeIssue.Product = product.ProductCode
+	codeIssue.IssueType = vulnmap.CodeSecurityVulnerability
+	codeIssue.AdditionalData = vulnmap.CodeIssueData{Title: "SQL Injection"}
+	w := setupSearchWorkspace(t, codeIssue, newOssIssue("VULNMAP-JS-MYSQL-1", "package.json", vulnmap.High, "mysql"))
+
+	results := w.SearchIssues("sql", IssueSearchFilter{Product: product.ProductCode})
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "javascript/sqlinjection", results[0].Issue.ID)
+}
+
+func newOssIssueWithAdvisoryDates(id string, disclosed *time.Time, modified *time.Time) vulnmap.Issue {
+	issue := newOssIssue(id, "package.json", vulnmap.High, "lodash")
+	data := issue.AdditionalData.(vulnmap.OssIssueData)
+	data.DisclosureTime = disclosed
+	data.ModificationTime = modified
+	issue.AdditionalData = data
+	return issue
+}
+
+func Test_SearchIssues_FiltersAndSortsByAdvisoryRecency(t *testing.T) {
+	testutil.UnitTest(t)
+	day := func(d int) *time.Time {
+		date := time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	w := setupSearchWorkspace(t,
+		newOssIssueWithAdvisoryDates("disclosed-recently", day(10), nil),
+		newOssIssueWithAdvisoryDates("modified-recently", day(1), day(20)),
+		newOssIssueWithAdvisoryDates("old", day(1), day(2)),
+		newOssIssueWithAdvisoryDates("no-dates", nil, nil),
+	)
+
+	results := w.SearchIssues("", IssueSearchFilter{UpdatedSince: day(5), SortByRecency: true})
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "modified-recently", results[0].Issue.ID)
+	assert.Equal(t, "disclosed-recently", results[1].Issue.ID)
+
+	t.Run("issues without dates are sorted last", func(t *testing.T) {
+		results = w.SearchIssues("", IssueSearchFilter{SortByRecency: true})
+
+		require.Len(t, results, 4)
+		assert.Equal(t, "modified-recently", results[0].Issue.ID)
+		assert.Equal(t, "no-dates", results[3].Issue.ID)
+	})
+}
@@ -0,0 +1,134 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	goos "os"
+	"slices"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+)
+
+// TagIssue attaches the tag to the cached issue with the fingerprint in the issue tags of its folder and returns its
+// tags
+func (w *Workspace) TagIssue(fingerprint string, tag string) (lsp.IssueTags, error) {
+	folder, _, found := w.folderIssueByFingerprint(fingerprint)
+	if !found {
+		return lsp.IssueTags{}, errors.Errorf("no issue with fingerprint %s found", fingerprint)
+	}
+	path := config.CurrentConfig().IssueTagsPath(folder.Path())
+	tags, err := updateIssueTags(path, func(issueTags *vulnmap.IssueTags) ([]string, error) {
+		return issueTags.Tag(fingerprint, tag)
+	})
+	if err != nil {
+		return lsp.IssueTags{}, err
+	}
+	w.publishRetaggedIssues()
+	return lsp.IssueTags{Fingerprint: fingerprint, Tags: tags}, nil
+}
+
+// UntagIssue removes the tag from the issue with the fingerprint in the issue tags of all folders and returns its
+// remaining tags. The issue doesn't need to be cached, so that tags of issues that are gone can be cleaned up.
+func (w *Workspace) UntagIssue(fingerprint string, tag string) (lsp.IssueTags, error) {
+	remaining := []string{}
+	for _, path := range w.folderDataPaths(config.CurrentConfig().IssueTagsPath) {
+		if issueTagsLoader.Load(path).Tags(fingerprint) == nil {
+			continue
+		}
+		tags, err := updateIssueTags(path, func(issueTags *vulnmap.IssueTags) ([]string, error) {
+			return issueTags.Untag(fingerprint, tag), nil
+		})
+		if err != nil {
+			return lsp.IssueTags{}, err
+		}
+		remaining = mergeTags(remaining, tags)
+	}
+	w.publishRetaggedIssues()
+	return lsp.IssueTags{Fingerprint: fingerprint, Tags: remaining}, nil
+}
+
+func updateIssueTags(path string, update func(issueTags *vulnmap.IssueTags) ([]string, error)) ([]string, error) {
+	issueTags, err := vulnmap.LoadIssueTags(path)
+	if errors.Is(err, goos.ErrNotExist) {
+		issueTags = vulnmap.NewIssueTags()
+	} else if err != nil {
+		return nil, err
+	}
+	tags, err := update(issueTags)
+	if err != nil {
+		return nil, err
+	}
+	if err = issueTags.Save(path); err != nil {
+		return nil, err
+	}
+	issueTagsLoader.Reset()
+	return tags, nil
+}
+
+func (w *Workspace) publishRetaggedIssues() {
+	for _, folder := range w.Folders() {
+		folder.FilterAndPublishCachedDiagnostics("")
+	}
+}
+
+// IssueTags returns the tags of the issue with the fingerprint in all folders
+func (w *Workspace) IssueTags(fingerprint string) lsp.IssueTags {
+	tags := []string{}
+	for _, path := range w.folderDataPaths(config.CurrentConfig().IssueTagsPath) {
+		tags = mergeTags(tags, issueTagsLoader.Load(path).Tags(fingerprint))
+	}
+	return lsp.IssueTags{Fingerprint: fingerprint, Tags: tags}
+}
+
+// AllIssueTags returns the tags of all tagged issues of all folders, ordered by fingerprint
+func (w *Workspace) AllIssueTags() []lsp.IssueTags {
+	tagsByFingerprint := map[string][]string{}
+	for _, path := range w.folderDataPaths(config.CurrentConfig().IssueTagsPath) {
+		issueTags := issueTagsLoader.Load(path)
+		if issueTags == nil {
+			continue
+		}
+		for fingerprint := range issueTags.Entries {
+			tagsByFingerprint[fingerprint] = mergeTags(tagsByFingerprint[fingerprint], issueTags.Tags(fingerprint))
+		}
+	}
+	result := make([]lsp.IssueTags, 0, len(tagsByFingerprint))
+	for fingerprint, tags := range tagsByFingerprint {
+		result = append(result, lsp.IssueTags{Fingerprint: fingerprint, Tags: tags})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Fingerprint < result[j].Fingerprint
+	})
+	return result
+}
+
+// mergeTags returns the sorted union of the tags
+func mergeTags(tags []string, additional []string) []string {
+	merged := append([]string{}, tags...)
+	for _, tag := range additional {
+		if !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
@@ -0,0 +1,144 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func setupIssueTags(t *testing.T) *config.Config {
+	t.Helper()
+	c := testutil.UnitTest(t)
+	c.SetIssueTagsPath(filepath.Join(t.TempDir(), "issue_tags.json"))
+	t.Cleanup(issueTagsLoader.Reset)
+	return c
+}
+
+func Test_TagIssue_AttachesTagsToFilteredIssues(t *testing.T) {
+	setupIssueTags(t)
+	tagged := NewMockIssue("VULNMAP-JS-LODASH-1", "package.json")
+	untagged := NewMockIssue("VULNMAP-JS-EXPRESS-1", "package.json")
+	w := setupSearchWorkspace(t, tagged, untagged)
+
+	_, err := w.TagIssue(tagged.Fingerprint(), "needs-review")
+	require.NoError(t, err)
+	result, err := w.TagIssue(tagged.Fingerprint(), "jira-1234")
+
+	require.NoError(t, err)
+	assert.Equal(t, lsp.IssueTags{Fingerprint: tagged.Fingerprint(), Tags: []string{"jira-1234", "needs-review"}}, result)
+	assert.Equal(t, result, w.IssueTags(tagged.Fingerprint()))
+	assert.Equal(t, []string{}, w.IssueTags(untagged.Fingerprint()).Tags)
+	assert.Equal(t, []lsp.IssueTags{result}, w.AllIssueTags())
+	filtered := w.Folders()[0].FilteredIssues()
+	require.Len(t, filtered, 2)
+	for _, issue := range filtered {
+		if issue.ID == tagged.ID {
+			assert.Equal(t, []string{"jira-1234", "needs-review"}, issue.Tags)
+		} else {
+			assert.Empty(t, issue.Tags)
+		}
+	}
+}
+
+func Test_TagIssue_UnknownFingerprint(t *testing.T) {
+	setupIssueTags(t)
+	w := setupSearchWorkspace(t)
+
+	_, err := w.TagIssue("unknown", "needs-review")
+
+	assert.Error(t, err)
+	assert.Empty(t, w.AllIssueTags())
+}
+
+func Test_UntagIssue_RemovesTag(t *testing.T) {
+	setupIssueTags(t)
+	issue := NewMockIssue("VULNMAP-JS-LODASH-1", "package.json")
+	w := setupSearchWorkspace(t, issue)
+	_, err := w.TagIssue(issue.Fingerprint(), "needs-review")
+	require.NoError(t, err)
+
+	result, err := w.UntagIssue(issue.Fingerprint(), "needs-review")
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Tags)
+	assert.Empty(t, w.AllIssueTags())
+	assert.Empty(t, w.Folders()[0].FilteredIssues()[0].Tags)
+}
+
+func Test_IssueTagFilter_ShowsOnlyIssuesWithFilteredTag(t *testing.T) {
+	c := setupIssueTags(t)
+	needsReview := NewMockIssue("VULNMAP-JS-LODASH-1", "package.json")
+	tracked := NewMockIssue("VULNMAP-JS-EXPRESS-1", "package.json")
+	untagged := NewMockIssue("VULNMAP-JS-MINIMIST-1", "package.json")
+	w := setupSearchWorkspace(t, needsReview, tracked, untagged)
+	_, err := w.TagIssue(needsReview.Fingerprint(), "needs-review")
+	require.NoError(t, err)
+	_, err = w.TagIssue(tracked.Fingerprint(), "jira-1234")
+	require.NoError(t, err)
+
+	c.SetIssueTagFilter([]string{"needs-review"})
+
+	assert.Equal(t, []string{needsReview.ID}, issueIds(w.Folders()[0].FilteredIssues()))
+
+	c.SetIssueTagFilter([]string{"needs-review", "jira-1234"})
+
+	assert.ElementsMatch(t, []string{needsReview.ID, tracked.ID}, issueIds(w.Folders()[0].FilteredIssues()))
+
+	c.SetIssueTagFilter(nil)
+
+	assert.Len(t, w.Folders()[0].FilteredIssues(), 3)
+}
+
+func Test_IssueTags_PersistAcrossReload(t *testing.T) {
+	setupIssueTags(t)
+	issue := NewMockIssue("VULNMAP-JS-LODASH-1", "package.json")
+	_, err := setupSearchWorkspace(t, issue).TagIssue(issue.Fingerprint(), "needs-review")
+	require.NoError(t, err)
+
+	// a restarted language server starts with an empty loader and workspace
+	issueTagsLoader.Reset()
+	reloaded := setupSearchWorkspace(t, issue)
+
+	assert.Equal(t, []string{"needs-review"}, reloaded.IssueTags(issue.Fingerprint()).Tags)
+	assert.Equal(t, []string{"needs-review"}, reloaded.Folders()[0].FilteredIssues()[0].Tags)
+}
+
+func Test_TagIssue_IsRecordedPerFolder(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetIssueTagsPath(".vulnmap-issue-tags.json")
+	t.Cleanup(issueTagsLoader.Reset)
+	w, folders, issues := setupTwoFolderWorkspace(t, "VULNMAP-JS-LODASH-1")
+
+	_, err := w.TagIssue(issues[0].Fingerprint(), "needs-review")
+	require.NoError(t, err)
+	_, err = w.TagIssue(issues[1].Fingerprint(), "jira-1234")
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(folders[0].Path(), ".vulnmap-issue-tags.json"))
+	assert.FileExists(t, filepath.Join(folders[1].Path(), ".vulnmap-issue-tags.json"))
+	assert.Equal(t, []string{"needs-review"}, folders[0].FilteredIssues()[0].Tags)
+	assert.Equal(t, []string{"jira-1234"}, folders[1].FilteredIssues()[0].Tags)
+	assert.Len(t, w.AllIssueTags(), 2)
+}
@@ -0,0 +1,52 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"sync"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// IssueTransformer is applied to every newly reported issue before it is cached and published.
+// It returns the (possibly modified) issue and false if the issue should be dropped.
+type IssueTransformer func(issue vulnmap.Issue) (vulnmap.Issue, bool)
+
+var (
+	issueTransformers      []IssueTransformer
+	issueTransformersMutex sync.RWMutex
+)
+
+// RegisterIssueTransformer appends a transformer to the chain. Transformers are applied in registration order.
+func RegisterIssueTransformer(transformer IssueTransformer) {
+	issueTransformersMutex.Lock()
+	defer issueTransformersMutex.Unlock()
+	issueTransformers = append(issueTransformers, transformer)
+}
+
+func transformIssue(issue vulnmap.Issue) (vulnmap.Issue, bool) {
+	issueTransformersMutex.RLock()
+	defer issueTransformersMutex.RUnlock()
+	for _, transform := range issueTransformers {
+		var keep bool
+		issue, keep = transform(issue)
+		if !keep {
+			return issue, false
+		}
+	}
+	return issue, true
+}
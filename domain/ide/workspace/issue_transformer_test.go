@@ -0,0 +1,117 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func resetIssueTransformers(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		issueTransformersMutex.Lock()
+		defer issueTransformersMutex.Unlock()
+		issueTransformers = nil
+	})
+}
+
+func Test_transformIssue_WithoutTransformers_ReturnsIssueUnchanged(t *testing.T) {
+	resetIssueTransformers(t)
+	issue := NewMockIssueWithSeverity("id1", "path1", vulnmap.High)
+
+	transformed, keep := transformIssue(issue)
+
+	assert.True(t, keep)
+	assert.Equal(t, issue, transformed)
+}
+
+func Test_transformIssue_AppliesTransformersInRegistrationOrder(t *testing.T) {
+	resetIssueTransformers(t)
+	RegisterIssueTransformer(func(issue vulnmap.Issue) (vulnmap.Issue, bool) {
+		issue.Message += "-first"
+		return issue, true
+	})
+	RegisterIssueTransformer(func(issue vulnmap.Issue) (vulnmap.Issue, bool) {
+		issue.Message += "-second"
+		return issue, true
+	})
+
+	transformed, keep := transformIssue(vulnmap.Issue{Message: "message"})
+
+	assert.True(t, keep)
+	assert.Equal(t, "message-first-second", transformed.Message)
+}
+
+func Test_processResults_AppliesIssueTransformers(t *testing.T) {
+	testutil.UnitTest(t)
+	resetIssueTransformers(t)
+	RegisterIssueTransformer(func(issue vulnmap.Issue) (vulnmap.Issue, bool) {
+		if issue.ID == "downgraded" {
+			issue.Severity = vulnmap.Low
+		}
+		return issue, true
+	})
+	RegisterIssueTransformer(func(issue vulnmap.Issue) (vulnmap.Issue, bool) {
+		return issue, issue.ID != "dropped"
+	})
+
+	f := NewMockFolder(notification.NewNotifier())
+	mtx := &sync.Mutex{}
+	var diagnostics []lsp.Diagnostic
+	f.notifier.CreateListener(func(event any) {
+		if params, ok := event.(lsp.PublishDiagnosticsParams); ok {
+			mtx.Lock()
+			defer mtx.Unlock()
+			diagnostics = params.Diagnostics
+		}
+	})
+
+	f.processResults(vulnmap.ScanData{
+		Product: product.ProductOpenSource,
+		Issues: []vulnmap.Issue{
+			NewMockIssueWithSeverity("downgraded", "path1", vulnmap.Critical),
+			NewMockIssueWithSeverity("dropped", "path1", vulnmap.Critical),
+			NewMockIssueWithSeverity("unchanged", "path1", vulnmap.High),
+		},
+	})
+
+	cachedIssues := GetValueFromMap(f.documentDiagnosticCache, "path1")
+	require.Len(t, cachedIssues, 2)
+	assert.Equal(t, "downgraded", cachedIssues[0].ID)
+	assert.Equal(t, vulnmap.Low, cachedIssues[0].Severity)
+	assert.Equal(t, "unchanged", cachedIssues[1].ID)
+	assert.Equal(t, vulnmap.High, cachedIssues[1].Severity)
+	assert.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		// diagnostics are ordered by severity, the downgraded issue is now less severe than the unchanged one
+		return len(diagnostics) == 2 &&
+			diagnostics[0].Code == "unchanged" &&
+			diagnostics[1].Code == "downgraded" && diagnostics[1].Severity == lsp.DiagnosticsSeverityInformation
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,61 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// CWEIssues are the issues of the workspace sharing a CWE
+type CWEIssues struct {
+	CWE    string
+	Issues []vulnmap.Issue
+}
+
+// IssuesByCWE groups the filtered issues of all folders by CWE. An issue with multiple CWEs is counted under each of
+// them, issues without a CWE are left out. Groups are sorted by their number of issues, largest first.
+func (w *Workspace) IssuesByCWE() []CWEIssues {
+	issuesByCWE := map[string][]vulnmap.Issue{}
+	for _, folder := range w.Folders() {
+		for _, issue := range folder.FilteredIssues() {
+			seen := map[string]bool{}
+			for _, cwe := range issue.CWEs {
+				cwe = strings.ToUpper(strings.TrimSpace(cwe))
+				if cwe == "" || seen[cwe] {
+					continue
+				}
+				seen[cwe] = true
+				issuesByCWE[cwe] = append(issuesByCWE[cwe], issue)
+			}
+		}
+	}
+
+	groups := make([]CWEIssues, 0, len(issuesByCWE))
+	for cwe, issues := range issuesByCWE {
+		groups = append(groups, CWEIssues{CWE: cwe, Issues: issues})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i].Issues) != len(groups[j].Issues) {
+			return len(groups[i].Issues) > len(groups[j].Issues)
+		}
+		return groups[i].CWE < groups[j].CWE
+	})
+	return groups
+}
@@ -0,0 +1,78 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func newIssueWithCWEs(id string, severity vulnmap.Severity, cwes ...string) vulnmap.Issue {
+	issue := NewMockIssueWithSeverity(id, "package.json", severity)
+	issue.CWEs = cwes
+	return issue
+}
+
+func Test_IssuesByCWE_CountsIssuesUnderEachOfTheirCWEs(t *testing.T) {
+	testutil.UnitTest(t)
+	w := setupSearchWorkspace(t,
+		newIssueWithCWEs("xss-1", vulnmap.High, "CWE-79"),
+		newIssueWithCWEs("xss-2", vulnmap.Medium, "CWE-79", "CWE-80"),
+		newIssueWithCWEs("xss-3", vulnmap.Low, "CWE-79", "cwe-79"),
+		newIssueWithCWEs("sqli-1", vulnmap.Critical, "CWE-89", "CWE-80"),
+		newIssueWithCWEs("no-cwe", vulnmap.High),
+	)
+
+	groups := w.IssuesByCWE()
+
+	require.Len(t, groups, 3)
+	assert.Equal(t, "CWE-79", groups[0].CWE)
+	assert.ElementsMatch(t, []string{"xss-1", "xss-2", "xss-3"}, issueIds(groups[0].Issues))
+	assert.Equal(t, "CWE-80", groups[1].CWE)
+	assert.ElementsMatch(t, []string{"xss-2", "sqli-1"}, issueIds(groups[1].Issues))
+	assert.Equal(t, "CWE-89", groups[2].CWE)
+	assert.ElementsMatch(t, []string{"sqli-1"}, issueIds(groups[2].Issues))
+}
+
+func Test_IssuesByCWE_HonorsSeverityFilter(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetSeverityFilter(lsp.NewSeverityFilter(true, true, false, false))
+	w := setupSearchWorkspace(t,
+		newIssueWithCWEs("xss-1", vulnmap.High, "CWE-79"),
+		newIssueWithCWEs("xss-2", vulnmap.Low, "CWE-79", "CWE-80"),
+	)
+
+	groups := w.IssuesByCWE()
+
+	require.Len(t, groups, 1)
+	assert.Equal(t, "CWE-79", groups[0].CWE)
+	assert.Equal(t, []string{"xss-1"}, issueIds(groups[0].Issues))
+}
+
+func issueIds(issues []vulnmap.Issue) []string {
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	return ids
+}
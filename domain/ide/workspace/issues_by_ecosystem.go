@@ -0,0 +1,64 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// EcosystemIssueCount counts the issues of the workspace in a package ecosystem, e.g. npm or maven
+type EcosystemIssueCount struct {
+	Ecosystem     string
+	Count         int
+	SeverityCount vulnmap.SeverityCount
+}
+
+// IssuesByEcosystem counts the filtered issues of all folders by ecosystem, issues without an ecosystem are left out.
+// Ecosystems are sorted by their number of issues, largest first.
+func (w *Workspace) IssuesByEcosystem() []EcosystemIssueCount {
+	countsByEcosystem := map[string]*EcosystemIssueCount{}
+	for _, folder := range w.Folders() {
+		for _, issue := range folder.FilteredIssues() {
+			ecosystem := strings.ToLower(strings.TrimSpace(issue.Ecosystem))
+			if ecosystem == "" {
+				continue
+			}
+			count, ok := countsByEcosystem[ecosystem]
+			if !ok {
+				count = &EcosystemIssueCount{Ecosystem: ecosystem}
+				countsByEcosystem[ecosystem] = count
+			}
+			count.Count++
+			addToSeverityCount(&count.SeverityCount, issue.Severity)
+		}
+	}
+
+	counts := make([]EcosystemIssueCount, 0, len(countsByEcosystem))
+	for _, count := range countsByEcosystem {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Ecosystem < counts[j].Ecosystem
+	})
+	return counts
+}
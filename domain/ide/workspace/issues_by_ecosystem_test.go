@@ -0,0 +1,76 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func newIssueWithEcosystem(id string, severity vulnmap.Severity, ecosystem string) vulnmap.Issue {
+	issue := NewMockIssueWithSeverity(id, "package.json", severity)
+	issue.Ecosystem = ecosystem
+	return issue
+}
+
+func Test_IssuesByEcosystem_GroupsIssuesByEcosystem(t *testing.T) {
+	testutil.UnitTest(t)
+	w := setupSearchWorkspace(t,
+		newIssueWithEcosystem("npm-1", vulnmap.High, "npm"),
+		newIssueWithEcosystem("npm-2", vulnmap.Critical, "npm"),
+		newIssueWithEcosystem("npm-3", vulnmap.High, "NPM"),
+		newIssueWithEcosystem("maven-1", vulnmap.Low, "maven"),
+		newIssueWithEcosystem("pip-1", vulnmap.Medium, "pip"),
+		newIssueWithEcosystem("no-ecosystem", vulnmap.High, ""),
+	)
+
+	counts := w.IssuesByEcosystem()
+
+	require.Len(t, counts, 3)
+	assert.Equal(t, EcosystemIssueCount{
+		Ecosystem:     "npm",
+		Count:         3,
+		SeverityCount: vulnmap.SeverityCount{Critical: 1, High: 2},
+	}, counts[0])
+	assert.Equal(t, EcosystemIssueCount{
+		Ecosystem:     "maven",
+		Count:         1,
+		SeverityCount: vulnmap.SeverityCount{Low: 1},
+	}, counts[1])
+	assert.Equal(t, "pip", counts[2].Ecosystem)
+}
+
+func Test_IssuesByEcosystem_HonorsSeverityFilter(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetSeverityFilter(lsp.NewSeverityFilter(true, true, false, false))
+	w := setupSearchWorkspace(t,
+		newIssueWithEcosystem("npm-1", vulnmap.High, "npm"),
+		newIssueWithEcosystem("maven-1", vulnmap.Low, "maven"),
+	)
+
+	counts := w.IssuesByEcosystem()
+
+	require.Len(t, counts, 1)
+	assert.Equal(t, "npm", counts[0].Ecosystem)
+	assert.Equal(t, 1, counts[0].Count)
+}
@@ -0,0 +1,98 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/git"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/persistence"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// fileBlame contains the last commits of the lines of a file with a given content
+type fileBlame struct {
+	contentHash string
+	lines       []git.LineCommit
+}
+
+// annotateLineOwners annotates the cached issues of the product in the given files with the owners of their lines and
+// publishes them again. It blames each file once and is meant to run in the background, so that blaming doesn't delay
+// the publication of scan results.
+func (f *Folder) annotateLineOwners(p product.Product, filePaths map[string]bool) {
+	annotated := false
+	for filePath := range filePaths {
+		lines := f.blame(filePath)
+		if len(lines) == 0 {
+			continue
+		}
+		f.documentDiagnosticCache.Compute(filePath, func(issues []vulnmap.Issue, loaded bool) ([]vulnmap.Issue, bool) {
+			if !loaded {
+				return issues, true
+			}
+			withOwners := make([]vulnmap.Issue, len(issues))
+			for i, issue := range issues {
+				if p == "" || issue.Product == p {
+					issue = withLineOwner(issue, lines)
+				}
+				withOwners[i] = issue
+			}
+			return withOwners, false
+		})
+		annotated = true
+	}
+	if !annotated {
+		return
+	}
+	f.persistIssues()
+	f.FilterAndPublishCachedDiagnostics(p)
+}
+
+// withLineOwner annotates the additional data of the issue with the last commit of its first affected line. Issues on
+// uncommitted lines are returned unchanged.
+func withLineOwner(issue vulnmap.Issue, lines []git.LineCommit) vulnmap.Issue {
+	if issue.AdditionalData == nil {
+		return issue
+	}
+	line := issue.Range.Start.Line
+	if line < 0 || line >= len(lines) || lines[line].CommitSha == "" {
+		return issue
+	}
+	owner := &vulnmap.LineOwner{Author: lines[line].Author, CommitSha: lines[line].CommitSha}
+	issue.AdditionalData = vulnmap.WithLineOwner(issue.AdditionalData, owner)
+	return issue
+}
+
+// blame returns the last commits of the lines of the file. Blames are cached until the content of the file changes,
+// files that can't be blamed are cached without lines.
+func (f *Folder) blame(filePath string) []git.LineCommit {
+	contentHash, err := persistence.ContentHash(filePath)
+	if err != nil {
+		return nil
+	}
+	if cached, ok := f.blames.Load(filePath); ok && cached.contentHash == contentHash {
+		return cached.lines
+	}
+
+	lines, err := f.blameProvider.Blame(filePath)
+	if err != nil {
+		log.Debug().Err(err).Str("method", "blame").Str("file", filePath).Msg("couldn't determine line owners")
+	}
+	f.blames.Store(filePath, fileBlame{contentHash: contentHash, lines: lines})
+	return lines
+}
@@ -0,0 +1,180 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/git"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+type fakeBlameProvider struct {
+	mutex sync.Mutex
+	lines []git.LineCommit
+	err   error
+	calls int
+}
+
+func (p *fakeBlameProvider) Blame(_ string) ([]git.LineCommit, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.calls++
+	return p.lines, p.err
+}
+
+func (p *fakeBlameProvider) callCount() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.calls
+}
+
+func waitForBlames(t *testing.T, p *fakeBlameProvider, calls int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return p.callCount() == calls }, 5*time.Second, 10*time.Millisecond)
+}
+
+func newLineOwnerIssue(id string, path string, line int) vulnmap.Issue {
+	issue := NewMockIssue(id, path)
+	issue.Range = vulnmap.Range{Start: vulnmap.Position{Line: line}, End: vulnmap.Position{Line: line}}
+	issue.AdditionalData = vulnmap.OssIssueData{Key: id}
+	return issue
+}
+
+func ownerOf(t *testing.T, f *Folder, path string, id string) *vulnmap.LineOwner {
+	t.Helper()
+	for _, issue := range f.DocumentDiagnosticsFromCache(path) {
+		if issue.ID == id {
+			return issue.AdditionalData.(vulnmap.OssIssueData).Owner
+		}
+	}
+	require.Failf(t, "issue not found", "issue %s", id)
+	return nil
+}
+
+func Test_processResults_AnnotatesIssuesWithLineOwners(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetIncludeLineOwners(true)
+	f := NewMockFolder(notification.NewNotifier())
+	blameProvider := &fakeBlameProvider{lines: []git.LineCommit{
+		{Author: "Jane Doe", CommitSha: "a1b2c3"},
+		{},
+	}}
+	f.blameProvider = blameProvider
+	path := filepath.Join(t.TempDir(), "package.json")
+	testutil.CreateFileOrFail(t, path, []byte("{\n}"))
+
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{
+		newLineOwnerIssue("committed", path, 0),
+		newLineOwnerIssue("uncommitted", path, 1),
+		newLineOwnerIssue("out-of-range", path, 5),
+	}})
+
+	expected := &vulnmap.LineOwner{Author: "Jane Doe", CommitSha: "a1b2c3"}
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(expected, ownerOf(t, f, path, "committed"))
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Nil(t, ownerOf(t, f, path, "uncommitted"))
+	assert.Nil(t, ownerOf(t, f, path, "out-of-range"))
+	assert.Equal(t, 1, blameProvider.callCount(), "a file should be blamed once for all of its issues")
+}
+
+func Test_processResults_BlamesFileAgainWhenContentChanged(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetIncludeLineOwners(true)
+	f := NewMockFolder(notification.NewNotifier())
+	blameProvider := &fakeBlameProvider{lines: []git.LineCommit{{Author: "Jane Doe", CommitSha: "a1b2c3"}}}
+	f.blameProvider = blameProvider
+	path := filepath.Join(t.TempDir(), "package.json")
+	testutil.CreateFileOrFail(t, path, []byte("{}"))
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{newLineOwnerIssue("1", path, 0)}})
+	waitForBlames(t, blameProvider, 1)
+
+	testutil.CreateFileOrFail(t, path, []byte("{ }"))
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{newLineOwnerIssue("2", path, 0)}})
+
+	waitForBlames(t, blameProvider, 2)
+}
+
+func Test_processResults_SkipsLineOwnersOutsideOfGitRepositories(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetIncludeLineOwners(true)
+	f := NewMockFolder(notification.NewNotifier())
+	blameProvider := &fakeBlameProvider{err: errors.New("not a git repository")}
+	f.blameProvider = blameProvider
+	path := filepath.Join(t.TempDir(), "package.json")
+	testutil.CreateFileOrFail(t, path, []byte("{}"))
+
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{
+		newLineOwnerIssue("1", path, 0),
+		newLineOwnerIssue("2", path, 0),
+	}})
+	waitForBlames(t, blameProvider, 1)
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{newLineOwnerIssue("3", path, 0)}})
+
+	assert.Never(t, func() bool { return blameProvider.callCount() > 1 }, 200*time.Millisecond, 10*time.Millisecond,
+		"a file that can't be blamed should not be blamed again")
+	assert.Nil(t, ownerOf(t, f, path, "1"))
+}
+
+func Test_processResults_DoesNotBlameWhenLineOwnersAreDisabled(t *testing.T) {
+	testutil.UnitTest(t)
+	f := NewMockFolder(notification.NewNotifier())
+	blameProvider := &fakeBlameProvider{lines: []git.LineCommit{{Author: "Jane Doe", CommitSha: "a1b2c3"}}}
+	f.blameProvider = blameProvider
+	path := filepath.Join(t.TempDir(), "package.json")
+	testutil.CreateFileOrFail(t, path, []byte("{}"))
+
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{newLineOwnerIssue("1", path, 0)}})
+
+	assert.Nil(t, ownerOf(t, f, path, "1"))
+	assert.Never(t, func() bool { return blameProvider.callCount() > 0 }, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func Test_processResults_PublishesBeforeLineOwnersAreResolved(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetIncludeLineOwners(true)
+	f := NewMockFolder(notification.NewNotifier())
+	blameProvider := &blockingBlameProvider{release: make(chan struct{})}
+	f.blameProvider = blameProvider
+	defer close(blameProvider.release)
+	path := filepath.Join(t.TempDir(), "package.json")
+	testutil.CreateFileOrFail(t, path, []byte("{}"))
+
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{newLineOwnerIssue("1", path, 0)}})
+
+	assert.Nil(t, ownerOf(t, f, path, "1"))
+}
+
+type blockingBlameProvider struct {
+	release chan struct{}
+}
+
+func (p *blockingBlameProvider) Blame(_ string) ([]git.LineCommit, error) {
+	<-p.release
+	return []git.LineCommit{{Author: "Jane Doe", CommitSha: "a1b2c3"}}, nil
+}
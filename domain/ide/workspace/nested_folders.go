@@ -0,0 +1,91 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	sglsp "github.com/sourcegraph/go-lsp"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+)
+
+// EnclosingFolder returns the outermost other workspace folder that contains this folder, e.g. /repo for
+// /repo/subdir, or nil if it is not nested. Nested folders are scanned as part of their enclosing folder.
+func (f *Folder) EnclosingFolder() *Folder {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.enclosingFolder
+}
+
+func (f *Folder) setEnclosingFolder(enclosingFolder *Folder) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.enclosingFolder = enclosingFolder
+}
+
+// updateNestedFolders determines the enclosing folder of each folder. Folders that became nested are cleared, as
+// their files are published by the enclosing folder. Folders that are no longer nested are returned, so that they can
+// be scanned on their own. A trusted folder is never nested in an untrusted one, as its own, more specific trust wins.
+// The workspace mutex must be held.
+func (w *Workspace) updateNestedFolders() (unnested []*Folder) {
+	for _, f := range w.folders {
+		var enclosingFolder *Folder
+		trusted := f.IsTrusted()
+		for _, other := range w.folders {
+			if other == f || !other.Contains(f.Path()) || trusted && !other.IsTrusted() {
+				continue
+			}
+			if enclosingFolder == nil || len(other.Path()) < len(enclosingFolder.Path()) {
+				enclosingFolder = other
+			}
+		}
+
+		previous := f.EnclosingFolder()
+		if previous == enclosingFolder {
+			continue
+		}
+		f.setEnclosingFolder(enclosingFolder)
+		switch {
+		case enclosingFolder != nil && previous == nil:
+			msg := fmt.Sprintf("The workspace folder %s is inside %s and is scanned as part of it.",
+				f.Path(), enclosingFolder.Path())
+			log.Info().Str("method", "updateNestedFolders").Msg(msg)
+			f.ClearDiagnostics()
+			w.notifier.SendShowMessage(sglsp.Info, msg)
+		case enclosingFolder == nil:
+			log.Info().Str("method", "updateNestedFolders").Str("folder", f.Path()).
+				Msg("workspace folder is no longer nested, scanning it on its own")
+			unnested = append(unnested, f)
+		}
+	}
+	return unnested
+}
+
+// scanUnnestedFolders scans the trusted folders that are no longer nested, if auto scan is enabled
+func scanUnnestedFolders(unnested []*Folder) {
+	if !config.CurrentConfig().IsAutoScanEnabled() {
+		return
+	}
+	for _, f := range unnested {
+		if f.IsTrusted() {
+			go f.ScanFolder(context.Background())
+		}
+	}
+}
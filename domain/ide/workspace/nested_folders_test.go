@@ -0,0 +1,140 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+func setupNestedFolders(t *testing.T) (w *Workspace, outer *Folder, nested *Folder, filePath string,
+	notifier *notification.MockNotifier) {
+	t.Helper()
+	outerPath := t.TempDir()
+	nestedPath := filepath.Join(outerPath, "subdir")
+	filePath = filepath.Join(nestedPath, "package.json")
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(NewMockIssue("nested", filePath))
+	notifier = notification.NewMockNotifier()
+	w = New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	outer = NewFolder(outerPath, "outer", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	nested = NewFolder(nestedPath, "nested", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	return w, outer, nested, filePath, notifier
+}
+
+func publishedDiagnosticsCount(notifier *notification.MockNotifier, filePath string) int {
+	count := 0
+	for _, msg := range notifier.SentMessages() {
+		params, ok := msg.(lsp.PublishDiagnosticsParams)
+		if ok && params.URI == uri.PathToUri(filePath) && len(params.Diagnostics) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func Test_NestedFolder_IsNotScannedTwice(t *testing.T) {
+	testutil.UnitTest(t)
+	w, outer, nested, filePath, notifier := setupNestedFolders(t)
+	w.AddFolder(outer)
+	w.AddFolder(nested)
+
+	outer.ScanFolder(context.Background())
+	nested.ScanFolder(context.Background())
+
+	assert.Equal(t, outer, nested.EnclosingFolder())
+	assert.Nil(t, outer.EnclosingFolder())
+	assert.Equal(t, 1, publishedDiagnosticsCount(notifier, filePath))
+	assert.Len(t, outer.DocumentDiagnosticsFromCache(filePath), 1)
+	assert.Empty(t, nested.DocumentDiagnosticsFromCache(filePath))
+	assert.Equal(t, outer, w.GetFolderContaining(filePath))
+	assert.Equal(t, 1, notifier.SendShowMessageCount())
+}
+
+func Test_NestedFolder_ClearedWhenEnclosingFolderIsAdded(t *testing.T) {
+	testutil.UnitTest(t)
+	w, outer, nested, filePath, notifier := setupNestedFolders(t)
+	w.AddFolder(nested)
+	nested.ScanFolder(context.Background())
+	require.Len(t, nested.DocumentDiagnosticsFromCache(filePath), 1)
+
+	w.AddFolder(outer)
+	outer.ScanFolder(context.Background())
+
+	assert.Equal(t, outer, nested.EnclosingFolder())
+	assert.Empty(t, nested.DocumentDiagnosticsFromCache(filePath))
+	assert.Len(t, outer.DocumentDiagnosticsFromCache(filePath), 1)
+	assert.Equal(t, 2, publishedDiagnosticsCount(notifier, filePath), "once for each folder, with a clear in between")
+}
+
+func Test_NestedFolder_NoLongerNestedWhenEnclosingFolderIsRemoved(t *testing.T) {
+	testutil.UnitTest(t)
+	w, outer, nested, filePath, _ := setupNestedFolders(t)
+	w.AddFolder(outer)
+	w.AddFolder(nested)
+
+	w.RemoveFolder(outer.Path())
+
+	assert.Nil(t, nested.EnclosingFolder())
+	assert.Equal(t, nested, w.GetFolderContaining(filePath))
+}
+
+func Test_NestedFolder_TrustedFolderIsNotNestedInUntrustedFolder(t *testing.T) {
+	testutil.UnitTest(t)
+	w, outer, nested, filePath, notifier := setupNestedFolders(t)
+	config.CurrentConfig().SetTrustedFolderFeatureEnabled(true)
+	config.CurrentConfig().SetTrustedFolders([]string{nested.Path()})
+	w.AddFolder(outer)
+	w.AddFolder(nested)
+
+	nested.ScanFolder(context.Background())
+
+	assert.True(t, nested.IsTrusted())
+	assert.False(t, outer.IsTrusted())
+	assert.Nil(t, nested.EnclosingFolder())
+	assert.Len(t, nested.DocumentDiagnosticsFromCache(filePath), 1)
+	assert.Equal(t, nested, w.GetFolderContaining(filePath))
+	assert.Equal(t, 0, notifier.SendShowMessageCount())
+}
+
+func Test_NestedFolder_NestedOnceEnclosingFolderIsTrusted(t *testing.T) {
+	testutil.UnitTest(t)
+	w, outer, nested, filePath, _ := setupNestedFolders(t)
+	config.CurrentConfig().SetTrustedFolderFeatureEnabled(true)
+	config.CurrentConfig().SetTrustedFolders([]string{nested.Path()})
+	w.AddFolder(outer)
+	w.AddFolder(nested)
+	require.Nil(t, nested.EnclosingFolder())
+
+	w.TrustFoldersAndScan(context.Background(), []*Folder{outer})
+
+	assert.Equal(t, outer, nested.EnclosingFolder())
+	assert.Equal(t, outer, w.GetFolderContaining(filePath))
+}
@@ -0,0 +1,75 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	goos "os"
+	"path/filepath"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// primaryManifests are the manifests that project-level issues are reported on by default, in order of preference
+var primaryManifests = []string{
+	"package.json",
+	"pom.xml",
+	"build.gradle",
+	"build.gradle.kts",
+	"build.sbt",
+	"go.mod",
+	"requirements.txt",
+	"Pipfile",
+	"pyproject.toml",
+	"Gemfile",
+	"composer.json",
+	"packages.config",
+	"paket.dependencies",
+	"Podfile",
+	"mix.exs",
+	"Dockerfile",
+}
+
+// routeProjectIssue reports issues without a file, or on the folder itself, on the project issues target file
+func (f *Folder) routeProjectIssue(issue vulnmap.Issue) vulnmap.Issue {
+	if issue.AffectedFilePath != "" && filepath.Clean(issue.AffectedFilePath) != filepath.Clean(f.path) {
+		return issue
+	}
+	targetFile := f.projectIssuesTargetFile()
+	if targetFile != "" {
+		issue.AffectedFilePath = targetFile
+	}
+	return issue
+}
+
+// projectIssuesTargetFile returns the configured target file of project-level issues, or the first primary manifest
+// that exists in the folder root. It returns an empty string if there is none.
+func (f *Folder) projectIssuesTargetFile() string {
+	if targetFile := config.CurrentConfig().ProjectIssuesTargetFile(); targetFile != "" {
+		if filepath.IsAbs(targetFile) {
+			return targetFile
+		}
+		return filepath.Join(f.path, targetFile)
+	}
+	for _, manifest := range primaryManifests {
+		manifestPath := filepath.Join(f.path, manifest)
+		if _, err := goos.Stat(manifestPath); err == nil {
+			return manifestPath
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func newProjectIssuesFolder(t *testing.T) *Folder {
+	t.Helper()
+	return NewFolder(t.TempDir(), t.Name(), vulnmap.NewTestScanner(), hover.NewFakeHoverService(),
+		vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+}
+
+func Test_processResults_ReportsFilelessIssueOnConfiguredTargetFile(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetProjectIssuesTargetFile("Dockerfile")
+	f := newProjectIssuesFolder(t)
+	testutil.CreateFileOrFail(t, filepath.Join(f.path, "package.json"), []byte("{}"))
+
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue("id1", "")}})
+
+	issues := f.DocumentDiagnosticsFromCache(filepath.Join(f.path, "Dockerfile"))
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, filepath.Join(f.path, "Dockerfile"), issues[0].AffectedFilePath)
+	}
+	assert.Nil(t, f.DocumentDiagnosticsFromCache(""))
+}
+
+func Test_processResults_ReportsFolderIssueOnPrimaryManifest(t *testing.T) {
+	testutil.UnitTest(t)
+	f := newProjectIssuesFolder(t)
+	manifest := filepath.Join(f.path, "package.json")
+	testutil.CreateFileOrFail(t, manifest, []byte("{}"))
+
+	f.processResults(vulnmap.ScanData{
+		Product: product.ProductOpenSource,
+		Issues:  []vulnmap.Issue{NewMockIssue("id1", ""), NewMockIssue("id2", f.path)},
+	})
+
+	assert.Len(t, f.DocumentDiagnosticsFromCache(manifest), 2)
+	assert.Nil(t, f.DocumentDiagnosticsFromCache(f.path))
+}
+
+func Test_processResults_KeepsFilelessIssueWithoutTargetFile(t *testing.T) {
+	testutil.UnitTest(t)
+	f := newProjectIssuesFolder(t)
+
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue("id1", "")}})
+
+	assert.Len(t, f.DocumentDiagnosticsFromCache(""), 1)
+}
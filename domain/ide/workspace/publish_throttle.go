@@ -0,0 +1,81 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"time"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// pendingPublish contains the products whose diagnostics were published within the current publish throttle of a
+// folder
+type pendingPublish struct {
+	timer    *time.Timer
+	products map[product.Product]bool
+}
+
+// FilterAndPublishCachedDiagnostics filters the cached issues of the folder and publishes them. With a diagnostics
+// publish throttle, the first publish waits for the throttle to expire and further publishes in the meantime are
+// coalesced, so that only the latest diagnostics are published. Without a throttle they are published immediately.
+func (f *Folder) FilterAndPublishCachedDiagnostics(p product.Product) {
+	throttle := config.CurrentConfig().DiagnosticsPublishThrottle()
+	if throttle <= 0 {
+		f.publishCachedDiagnostics(p)
+		return
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.pendingPublish != nil {
+		f.pendingPublish.products[p] = true
+		return
+	}
+	pending := &pendingPublish{products: map[product.Product]bool{p: true}}
+	f.pendingPublish = pending
+	pending.timer = time.AfterFunc(throttle, func() { f.runPendingPublish(pending) })
+}
+
+func (f *Folder) runPendingPublish(pending *pendingPublish) {
+	f.mutex.Lock()
+	if f.pendingPublish != pending {
+		f.mutex.Unlock()
+		return
+	}
+	f.pendingPublish = nil
+	f.mutex.Unlock()
+
+	// the scan results of several products are sent for all products
+	var publishedProduct product.Product
+	if len(pending.products) == 1 {
+		for p := range pending.products {
+			publishedProduct = p
+		}
+	}
+	f.publishCachedDiagnostics(publishedProduct)
+}
+
+// cancelPendingPublish drops the diagnostics publish waiting for the publish throttle, if there is one
+func (f *Folder) cancelPendingPublish() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.pendingPublish != nil {
+		f.pendingPublish.timer.Stop()
+		f.pendingPublish = nil
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+// countingSink records every diagnostics publish
+type countingSink struct {
+	mutex     sync.Mutex
+	published [][]vulnmap.Issue
+}
+
+func (s *countingSink) SendDiagnostics(_ string, issues []vulnmap.Issue, _ []lsp.Diagnostic) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.published = append(s.published, issues)
+}
+
+func (s *countingSink) publishes() [][]vulnmap.Issue {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([][]vulnmap.Issue{}, s.published...)
+}
+
+func newThrottleTestFolder(t *testing.T, throttle time.Duration) (*Folder, *countingSink) {
+	t.Helper()
+	c := testutil.UnitTest(t)
+	c.SetDiagnosticsPublishThrottle(throttle)
+	f := NewFolder(t.TempDir(), "Test", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	sink := &countingSink{}
+	f.SetDiagnosticsSink(sink)
+	return f, sink
+}
+
+func Test_FilterAndPublishCachedDiagnostics_CoalescesRapidScanCompletions(t *testing.T) {
+	f, sink := newThrottleTestFolder(t, 100*time.Millisecond)
+	filePath := filepath.Join(f.Path(), "package.json")
+
+	for _, id := range []string{"1", "2", "3"} {
+		f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue(id, filePath)}})
+	}
+
+	assert.Empty(t, sink.publishes(), "the publish should wait for the throttle")
+	require.Eventually(t, func() bool { return len(sink.publishes()) > 0 }, time.Second, 10*time.Millisecond)
+	assert.Never(t, func() bool { return len(sink.publishes()) > 1 }, 300*time.Millisecond, 10*time.Millisecond)
+	assert.Len(t, sink.publishes()[0], 3, "the latest diagnostics should be published")
+}
+
+func Test_FilterAndPublishCachedDiagnostics_PublishesAgainAfterThrottle(t *testing.T) {
+	f, sink := newThrottleTestFolder(t, 50*time.Millisecond)
+	filePath := filepath.Join(f.Path(), "package.json")
+
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue("1", filePath)}})
+	require.Eventually(t, func() bool { return len(sink.publishes()) == 1 }, time.Second, 10*time.Millisecond)
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue("2", filePath)}})
+
+	require.Eventually(t, func() bool { return len(sink.publishes()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Len(t, sink.publishes()[1], 2)
+}
+
+func Test_FilterAndPublishCachedDiagnostics_WithoutThrottlePublishesImmediately(t *testing.T) {
+	f, sink := newThrottleTestFolder(t, 0)
+	filePath := filepath.Join(f.Path(), "package.json")
+
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue("1", filePath)}})
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue("2", filePath)}})
+
+	assert.Len(t, sink.publishes(), 2)
+}
+
+func Test_RemoveFolder_CancelsPendingPublish(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetDiagnosticsPublishThrottle(50 * time.Millisecond)
+	scanNotifier := vulnmap.NewMockScanNotifier()
+	f := NewFolder(t.TempDir(), "Test", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), scanNotifier, notification.NewNotifier())
+	w := New(performance.NewInstrumentor(), vulnmap.NewTestScanner(), hover.NewFakeHoverService(), scanNotifier, notification.NewNotifier())
+	w.AddFolder(f)
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue("1", filepath.Join(f.Path(), "package.json"))}})
+
+	w.RemoveFolder(f.Path())
+
+	assert.Never(t, func() bool { return len(scanNotifier.SuccessCalls()) > 0 }, 200*time.Millisecond, 10*time.Millisecond,
+		"the scan results of the removed folder shouldn't be published")
+}
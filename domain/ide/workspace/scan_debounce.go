@@ -0,0 +1,94 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+)
+
+// maxScanDebounceFactor limits how long continuous triggers can delay a scan, as a multiple of the scan debounce
+const maxScanDebounceFactor = 4
+
+// pendingScan contains the paths triggered within the current scan debounce of a folder
+type pendingScan struct {
+	timer *time.Timer
+	paths map[string]bool
+	since time.Time
+}
+
+// TriggerScan scans a file of the folder after the configured scan debounce. Triggers within the debounce restart it
+// and are coalesced, so that each triggered path is scanned once when the debounce expires. Continuous triggers delay
+// the scan at most maxScanDebounceFactor times the debounce. Without a debounce the file is scanned immediately.
+// Only saves trigger scans this way: the language server doesn't scan on watched-file events, and folder scans, e.g.
+// from commands or configuration changes, are started right away.
+func (f *Folder) TriggerScan(ctx context.Context, path string) {
+	debounce := config.CurrentConfig().ScanDebounce()
+	if debounce <= 0 {
+		f.ScanFile(ctx, path)
+		return
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.stopCtx.Err() != nil {
+		return
+	}
+	now := time.Now()
+	if f.pendingScan == nil {
+		f.pendingScan = &pendingScan{paths: map[string]bool{}, since: now}
+	} else {
+		f.pendingScan.timer.Stop()
+	}
+	pending := f.pendingScan
+	pending.paths[path] = true
+	delay := min(debounce, pending.since.Add(maxScanDebounceFactor*debounce).Sub(now))
+	pending.timer = time.AfterFunc(delay, func() { f.runPendingScan(ctx, pending) })
+}
+
+func (f *Folder) runPendingScan(ctx context.Context, pending *pendingScan) {
+	f.mutex.Lock()
+	if f.pendingScan != pending {
+		// already run by a timer that fired while the debounce was restarted
+		f.mutex.Unlock()
+		return
+	}
+	f.pendingScan = nil
+	f.mutex.Unlock()
+
+	for path := range pending.paths {
+		if ctx.Err() != nil || f.stopCtx.Err() != nil {
+			log.Debug().Str("method", "runPendingScan").Str("folder", f.path).Msg("skipping cancelled scan")
+			return
+		}
+		f.ScanFile(ctx, path)
+	}
+}
+
+// cancelPendingScan discards the triggered scans that are waiting for the scan debounce to expire
+func (f *Folder) cancelPendingScan() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.pendingScan != nil {
+		f.pendingScan.timer.Stop()
+		f.pendingScan = nil
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func newDebounceTestFolder(t *testing.T, debounce time.Duration) (*Folder, *vulnmap.TestScanner) {
+	t.Helper()
+	c := testutil.UnitTest(t)
+	c.SetScanDebounce(debounce)
+	scanner := vulnmap.NewTestScanner()
+	f := NewFolder(t.TempDir(), "Test", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewNotifier())
+	return f, scanner
+}
+
+func Test_TriggerScan_CoalescesTriggersWithinDebounce(t *testing.T) {
+	f, scanner := newDebounceTestFolder(t, 50*time.Millisecond)
+	filePath := filepath.Join(f.Path(), "package.json")
+
+	for i := 0; i < 5; i++ {
+		f.TriggerScan(context.Background(), filePath)
+	}
+
+	assert.Zero(t, scanner.Calls(), "the scan should wait for the debounce")
+	require.Eventually(t, func() bool { return scanner.Calls() > 0 }, time.Second, 10*time.Millisecond)
+	assert.Never(t, func() bool { return scanner.Calls() > 1 }, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func Test_TriggerScan_ScansEachTriggeredPathOnce(t *testing.T) {
+	f, scanner := newDebounceTestFolder(t, 50*time.Millisecond)
+
+	f.TriggerScan(context.Background(), filepath.Join(f.Path(), "package.json"))
+	f.TriggerScan(context.Background(), filepath.Join(f.Path(), "main.tf"))
+	f.TriggerScan(context.Background(), filepath.Join(f.Path(), "package.json"))
+
+	require.Eventually(t, func() bool { return scanner.Calls() == 2 }, time.Second, 10*time.Millisecond)
+	assert.Never(t, func() bool { return scanner.Calls() > 2 }, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func Test_TriggerScan_ContinuousTriggersStillScan(t *testing.T) {
+	f, scanner := newDebounceTestFolder(t, 50*time.Millisecond)
+	filePath := filepath.Join(f.Path(), "package.json")
+
+	deadline := time.Now().Add(maxScanDebounceFactor * 50 * time.Millisecond * 3)
+	for time.Now().Before(deadline) && scanner.Calls() == 0 {
+		f.TriggerScan(context.Background(), filePath)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Positive(t, scanner.Calls(), "continuous triggers must not delay the scan forever")
+}
+
+func Test_TriggerScan_WithoutDebounceScansImmediately(t *testing.T) {
+	f, scanner := newDebounceTestFolder(t, 0)
+
+	f.TriggerScan(context.Background(), filepath.Join(f.Path(), "package.json"))
+
+	assert.Equal(t, 1, scanner.Calls())
+}
+
+func Test_TriggerScan_StopScansCancelsPendingScan(t *testing.T) {
+	f, scanner := newDebounceTestFolder(t, 50*time.Millisecond)
+	f.TriggerScan(context.Background(), filepath.Join(f.Path(), "package.json"))
+
+	f.StopScans()
+
+	assert.Never(t, func() bool { return scanner.Calls() > 0 }, 200*time.Millisecond, 10*time.Millisecond)
+	f.TriggerScan(context.Background(), filepath.Join(f.Path(), "package.json"))
+	assert.Nil(t, f.pendingScan, "stopped folders should not schedule scans")
+}
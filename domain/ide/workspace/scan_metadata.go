@@ -0,0 +1,111 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"sort"
+	"time"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// ScanMetadata describes the last successful scan of a product
+type ScanMetadata struct {
+	// ScanId is the id of the scan, empty if the scan has no id
+	ScanId            string
+	Product           product.Product
+	DurationMs        int64
+	TimestampFinished time.Time
+}
+
+// updateScanMetadata replaces the metadata of the previous scan of the product
+func (f *Folder) updateScanMetadata(scanData vulnmap.ScanData) {
+	if scanData.Product == "" {
+		return
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.scanMetadata == nil {
+		f.scanMetadata = map[product.Product]ScanMetadata{}
+	}
+	f.scanMetadata[scanData.Product] = ScanMetadata{
+		ScanId:            scanData.ScanId,
+		Product:           scanData.Product,
+		DurationMs:        scanData.DurationMs,
+		TimestampFinished: scanData.TimestampFinished,
+	}
+}
+
+// LastScans returns the metadata of the last successful scan of each product, ordered by product
+func (f *Folder) LastScans() []ScanMetadata {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	scans := make([]ScanMetadata, 0, len(f.scanMetadata))
+	for _, metadata := range f.scanMetadata {
+		scans = append(scans, metadata)
+	}
+	sort.Slice(scans, func(i, j int) bool { return scans[i].Product < scans[j].Product })
+	return scans
+}
+
+// lastScanIds returns the id of the last successful scan of each product
+func (f *Folder) lastScanIds() map[product.Product]string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	scanIds := make(map[product.Product]string, len(f.scanMetadata))
+	for p, metadata := range f.scanMetadata {
+		scanIds[p] = metadata.ScanId
+	}
+	return scanIds
+}
+
+// ScanOutcome describes how the products of the last folder scan finished
+type ScanOutcome struct {
+	// Succeeded contains the products that reported results
+	Succeeded []product.Product
+	// Failed contains the errors of the products that failed
+	Failed map[product.Product]error
+}
+
+// recordScanOutcome records that the product finished the scan, failed if err is not nil
+func (f *Folder) recordScanOutcome(p product.Product, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err == nil {
+		f.scanOutcome.Succeeded = append(f.scanOutcome.Succeeded, p)
+		return
+	}
+	if f.scanOutcome.Failed == nil {
+		f.scanOutcome.Failed = map[product.Product]error{}
+	}
+	f.scanOutcome.Failed[p] = err
+}
+
+// LastScanOutcome returns which products finished or failed since the last folder scan was started
+func (f *Folder) LastScanOutcome() ScanOutcome {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	outcome := ScanOutcome{Succeeded: append([]product.Product{}, f.scanOutcome.Succeeded...)}
+	if len(f.scanOutcome.Failed) > 0 {
+		outcome.Failed = map[product.Product]error{}
+		for p, err := range f.scanOutcome.Failed {
+			outcome.Failed[p] = err
+		}
+	}
+	return outcome
+}
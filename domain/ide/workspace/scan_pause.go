@@ -0,0 +1,116 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// scanPause is shared by a workspace and its folders. While scanning is paused, scans are skipped and the folders
+// that requested a scan are remembered, so that each of them is scanned once when scanning is resumed.
+type scanPause struct {
+	mutex   sync.Mutex
+	paused  bool
+	pending map[*Folder]bool
+}
+
+func newScanPause() *scanPause {
+	return &scanPause{pending: map[*Folder]bool{}}
+}
+
+// skip returns true if scanning is paused and remembers that the folder requested a scan
+func (p *scanPause) skip(f *Folder) bool {
+	if p == nil {
+		return false
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.paused {
+		p.pending[f] = true
+	}
+	return p.paused
+}
+
+func (p *scanPause) isPaused() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.paused
+}
+
+func (p *scanPause) setPaused(paused bool) (pending []*Folder) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.paused = paused
+	if paused {
+		return nil
+	}
+	for f := range p.pending {
+		pending = append(pending, f)
+	}
+	p.pending = map[*Folder]bool{}
+	return pending
+}
+
+// PauseScanning skips all scans until scanning is resumed, e.g. during heavy local builds. The cached issues stay
+// published while scanning is paused.
+func (w *Workspace) PauseScanning() {
+	w.scanPause.setPaused(true)
+	log.Info().Str("method", "PauseScanning").Msg("scanning paused")
+}
+
+// ResumeScanning resumes scanning and scans the folders that requested scans while scanning was paused once. It
+// returns the number of folders that are scanned.
+func (w *Workspace) ResumeScanning(ctx context.Context) int {
+	pending := w.scanPause.setPaused(false)
+	scanned := 0
+	for _, f := range pending {
+		if w.GetFolderContaining(f.Path()) != f {
+			continue
+		}
+		f.ClearScannedStatus()
+		go f.ScanFolder(ctx)
+		scanned++
+	}
+	log.Info().Str("method", "ResumeScanning").Int("scannedFolders", scanned).Msg("scanning resumed")
+	return scanned
+}
+
+// IsScanningPaused returns true if scans are skipped because scanning is paused
+func (w *Workspace) IsScanningPaused() bool {
+	return w.scanPause.isPaused()
+}
+
+func (f *Folder) setScanPause(pause *scanPause) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.scanPause = pause
+}
+
+// isScanPaused returns true if the scan must be skipped because scanning is paused in the folder's workspace
+func (f *Folder) isScanPaused() bool {
+	f.mutex.Lock()
+	pause := f.scanPause
+	f.mutex.Unlock()
+	if pause.skip(f) {
+		log.Debug().Str("method", "isScanPaused").Str("folder", f.path).Msg("scanning is paused, skipping scan")
+		return true
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_PauseScanning_SkipsScansAndKeepsCachedIssues(t *testing.T) {
+	testutil.UnitTest(t)
+	folderPath := t.TempDir()
+	manifest := filepath.Join(folderPath, "package.json")
+	scanner := vulnmap.NewTestScanner()
+	scanner.AddTestIssue(NewMockIssue("id", manifest))
+	notifier := notification.NewMockNotifier()
+	w := New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	f := NewFolder(folderPath, "folder", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(f)
+	f.ScanFolder(context.Background())
+	require.Equal(t, 1, scanner.Calls())
+	sentBeforePause := len(notifier.SentMessages())
+
+	w.PauseScanning()
+	f.ScanFolder(context.Background())
+	f.scan(context.Background(), manifest)
+
+	assert.True(t, w.IsScanningPaused())
+	assert.Equal(t, 1, scanner.Calls())
+	assert.Len(t, f.DocumentDiagnosticsFromCache(manifest), 1)
+	assert.Len(t, notifier.SentMessages(), sentBeforePause, "cached diagnostics must not be cleared")
+}
+
+func Test_ResumeScanning_RescansRequestedFoldersOnce(t *testing.T) {
+	testutil.UnitTest(t)
+	requestedScanner, otherScanner := vulnmap.NewTestScanner(), vulnmap.NewTestScanner()
+	notifier := notification.NewNotifier()
+	w := New(performance.NewInstrumentor(), requestedScanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	requestedPath := t.TempDir()
+	requested := NewFolder(requestedPath, "requested", requestedScanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	other := NewFolder(t.TempDir(), "other", otherScanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(requested)
+	w.AddFolder(other)
+
+	w.PauseScanning()
+	requested.ScanFolder(context.Background())
+	requested.ScanFolder(context.Background())
+	requested.scan(context.Background(), filepath.Join(requestedPath, "package.json"))
+	require.Equal(t, 0, requestedScanner.Calls())
+
+	scanned := w.ResumeScanning(context.Background())
+
+	assert.False(t, w.IsScanningPaused())
+	assert.Equal(t, 1, scanned)
+	assert.Eventually(t, requested.IsScanned, time.Second, time.Millisecond)
+	assert.Equal(t, 1, requestedScanner.Calls())
+	assert.Equal(t, 0, otherScanner.Calls())
+	assert.Equal(t, 0, w.ResumeScanning(context.Background()), "pending scans are only run once")
+}
+
+func Test_ResumeScanning_SkipsRemovedFolders(t *testing.T) {
+	testutil.UnitTest(t)
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewNotifier()
+	w := New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	f := NewFolder(t.TempDir(), "removed", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(f)
+	w.PauseScanning()
+	f.ScanFolder(context.Background())
+	w.RemoveFolder(f.Path())
+
+	assert.Equal(t, 0, w.ResumeScanning(context.Background()))
+}
@@ -0,0 +1,81 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+)
+
+// StartupScan scans the trusted folders when the language server starts. If scanning on startup is disabled, the
+// folders are marked as pending instead, so that the expensive scans only run when the user asks for them.
+func (w *Workspace) StartupScan(ctx context.Context) {
+	if config.CurrentConfig().IsScanOnStartupEnabled() {
+		w.ScanWorkspace(ctx)
+		return
+	}
+
+	trusted, _ := w.GetFolderTrust()
+	for _, folder := range trusted {
+		folder.MarkScanPending()
+	}
+}
+
+// MarkScanPending marks an unscanned folder as waiting for a scan and notifies the client that the scan is pending.
+// Nested folders are scanned as part of their enclosing folder and are not marked.
+func (f *Folder) MarkScanPending() {
+	f.mutex.Lock()
+	if f.status != Unscanned || f.folderScanInProgress || f.enclosingFolder != nil {
+		f.mutex.Unlock()
+		return
+	}
+	f.status = ScanPending
+	f.mutex.Unlock()
+
+	log.Info().Str("method", "MarkScanPending").Str("folder", f.path).Msg("scan on startup is disabled, scan is pending")
+	f.scanNotifier.SendPending(f.path)
+}
+
+// IsScanPending returns true if the folder waits for an explicit scan or an opened file to be scanned
+func (f *Folder) IsScanPending() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.status == ScanPending
+}
+
+// ScanIfPending starts the scan of a pending folder when one of its files is opened. Excluded files don't trigger
+// the scan. It returns true if the scan was started.
+func (f *Folder) ScanIfPending(ctx context.Context, openedFilePath string) bool {
+	if f.isExcluded(openedFilePath) {
+		return false
+	}
+
+	f.mutex.Lock()
+	if f.status != ScanPending {
+		f.mutex.Unlock()
+		return false
+	}
+	// the folder is no longer pending, so that further opened files don't start another scan
+	f.status = Unscanned
+	f.mutex.Unlock()
+
+	go f.ScanFolder(ctx)
+	return true
+}
@@ -0,0 +1,103 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func setupStartupScanWorkspace(t *testing.T, scanOnStartup bool) (*Workspace, *vulnmap.TestScanner, *vulnmap.MockScanNotifier) {
+	t.Helper()
+	c := testutil.UnitTest(t)
+	c.SetAutomaticScanning(true)
+	c.SetScanOnStartup(scanOnStartup)
+	scanner := vulnmap.NewTestScanner()
+	scanNotifier := vulnmap.NewMockScanNotifier()
+	w := New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), scanNotifier, notification.NewNotifier())
+	return w, scanner, scanNotifier
+}
+
+func Test_AddFolderAndScan_ScanOnStartupDisabled_MarksFolderPending(t *testing.T) {
+	w, scanner, scanNotifier := setupStartupScanWorkspace(t, false)
+
+	f := w.AddFolderAndScan(context.Background(), t.TempDir(), "added")
+
+	assert.Never(t, f.IsScanned, 50*time.Millisecond, time.Millisecond)
+	assert.Equal(t, 0, scanner.Calls())
+	assert.True(t, f.IsScanPending())
+	assert.Equal(t, []string{f.Path()}, scanNotifier.PendingCalls())
+}
+
+func Test_StartupScan_ScanOnStartupDisabled_ScansOnExplicitTrigger(t *testing.T) {
+	w, scanner, scanNotifier := setupStartupScanWorkspace(t, false)
+	f := NewFolder(t.TempDir(), "folder", scanner, hover.NewFakeHoverService(), scanNotifier, w.notifier)
+	w.AddFolder(f)
+
+	w.StartupScan(context.Background())
+
+	assert.Equal(t, 0, scanner.Calls())
+	assert.True(t, f.IsScanPending())
+
+	w.ScanWorkspace(context.Background())
+
+	assert.Eventually(t, f.IsScanned, time.Second, time.Millisecond)
+	assert.Equal(t, 1, scanner.Calls())
+	assert.False(t, f.IsScanPending())
+}
+
+func Test_StartupScan_ScanOnStartupEnabled_ScansFolders(t *testing.T) {
+	w, scanner, scanNotifier := setupStartupScanWorkspace(t, true)
+	f := NewFolder(t.TempDir(), "folder", scanner, hover.NewFakeHoverService(), scanNotifier, w.notifier)
+	w.AddFolder(f)
+
+	w.StartupScan(context.Background())
+
+	assert.Eventually(t, f.IsScanned, time.Second, time.Millisecond)
+	assert.Empty(t, scanNotifier.PendingCalls())
+}
+
+func Test_ScanIfPending_ScansFolderOnceOnFirstOpenedFile(t *testing.T) {
+	w, scanner, _ := setupStartupScanWorkspace(t, false)
+	f := w.AddFolderAndScan(context.Background(), t.TempDir(), "added")
+
+	assert.True(t, f.ScanIfPending(context.Background(), filepath.Join(f.Path(), "package.json")))
+	assert.False(t, f.ScanIfPending(context.Background(), filepath.Join(f.Path(), "pom.xml")))
+
+	assert.Eventually(t, f.IsScanned, time.Second, time.Millisecond)
+	assert.Equal(t, 1, scanner.Calls())
+}
+
+func Test_ScanIfPending_IgnoresScannedFolder(t *testing.T) {
+	w, scanner, _ := setupStartupScanWorkspace(t, false)
+	f := NewFolder(t.TempDir(), "folder", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), w.notifier)
+	w.AddFolder(f)
+	f.ScanFolder(context.Background())
+
+	assert.False(t, f.ScanIfPending(context.Background(), filepath.Join(f.Path(), "package.json")))
+	assert.Equal(t, 1, scanner.Calls())
+}
@@ -0,0 +1,46 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// updateScanWarnings replaces the warnings of the previous scan of the product
+func (f *Folder) updateScanWarnings(p product.Product, warnings []string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.scanWarnings == nil {
+		f.scanWarnings = map[product.Product][]string{}
+	}
+	if len(warnings) == 0 {
+		delete(f.scanWarnings, p)
+		return
+	}
+	f.scanWarnings[p] = warnings
+}
+
+// ScanWarnings returns the warnings of the last scan of each product that reported any
+func (f *Folder) ScanWarnings() map[product.Product][]string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	warnings := make(map[product.Product][]string, len(f.scanWarnings))
+	for p, productWarnings := range f.scanWarnings {
+		warnings[p] = append([]string(nil), productWarnings...)
+	}
+	return warnings
+}
@@ -0,0 +1,40 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"time"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// staleResultAge returns the age of the issues of the file and whether it exceeds the configured max result age.
+// Issues that weren't reported by a scan of this session, e.g. restored from the persisted cache, have no known age
+// and are never stale.
+func (f *Folder) staleResultAge(filePath string, issues []vulnmap.Issue) (age time.Duration, stale bool) {
+	maxAge := config.CurrentConfig().MaxResultAge()
+	if maxAge <= 0 || len(issues) == 0 {
+		return 0, false
+	}
+	timestamp, ok := f.resultTimestamps.Load(filePath)
+	if !ok {
+		return 0, false
+	}
+	age = f.clock.Now().Sub(timestamp)
+	return age, age > maxAge
+}
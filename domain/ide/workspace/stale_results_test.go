@@ -0,0 +1,84 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/converter"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/clock"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+func Test_FilterAndPublishCachedDiagnostics_MarksResultsOlderThanMaxResultAge(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetMaxResultAge(24 * time.Hour)
+	notifier := notification.NewMockNotifier()
+	f := NewFolder(t.TempDir(), "Test", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	scannedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(scannedAt)
+	f.clock = fakeClock
+	filePath := filepath.Join(f.path, "package.json")
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue("1", filePath)}})
+	lastDiagnostics := func() []lsp.Diagnostic {
+		var diagnostics []lsp.Diagnostic
+		for _, msg := range notifier.SentMessages() {
+			if params, ok := msg.(lsp.PublishDiagnosticsParams); ok && params.URI == uri.PathToUri(filePath) {
+				diagnostics = params.Diagnostics
+			}
+		}
+		return diagnostics
+	}
+
+	fakeClock.Set(scannedAt.Add(23 * time.Hour))
+	f.FilterAndPublishCachedDiagnostics("")
+	assert.Len(t, lastDiagnostics(), 1)
+	assert.NotEqual(t, converter.StaleResultsCode, lastDiagnostics()[0].Code)
+
+	fakeClock.Set(scannedAt.Add(25 * time.Hour))
+	f.FilterAndPublishCachedDiagnostics("")
+	diagnostics := lastDiagnostics()
+	assert.Len(t, diagnostics, 2)
+	assert.Equal(t, converter.StaleResultsCode, diagnostics[0].Code)
+	assert.Equal(t, lsp.DiagnosticsSeverityInformation, diagnostics[0].Severity)
+	assert.Equal(t, "Results may be outdated, they were found 1 day ago. Rescan to refresh them.", diagnostics[0].Message)
+
+	f.processResults(vulnmap.ScanData{Product: product.ProductOpenSource, Issues: []vulnmap.Issue{NewMockIssue("1", filePath)}})
+	assert.Len(t, lastDiagnostics(), 1, "a rescan refreshes the results")
+}
+
+func Test_staleResultAge_DisabledByDefault(t *testing.T) {
+	testutil.UnitTest(t)
+	f := NewFolder(t.TempDir(), "Test", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notification.NewMockNotifier())
+	filePath := filepath.Join(f.path, "package.json")
+	issues := []vulnmap.Issue{NewMockIssue("1", filePath)}
+	f.resultTimestamps.Store(filePath, time.Now().Add(-365*24*time.Hour))
+
+	_, stale := f.staleResultAge(filePath, issues)
+
+	assert.False(t, stale)
+}
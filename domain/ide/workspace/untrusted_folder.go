@@ -0,0 +1,79 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"fmt"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/data_structure"
+)
+
+const (
+	trustFolderMessageActionItemTitle     vulnmap.MessageAction = "Trust folder and scan"
+	dontTrustFolderMessageActionItemTitle vulnmap.MessageAction = "Don't trust"
+)
+
+// untrustedFolderMessage explains why the folder isn't scanned
+func untrustedFolderMessage(path string) string {
+	return fmt.Sprintf("Vulnmap didn't scan %s because the folder isn't trusted. When scanning for vulnerabilities, "+
+		"Vulnmap may automatically execute code such as invoking the package manager to get dependency information. "+
+		"You should only scan folders you trust.", path)
+}
+
+// promptTrust asks the user to trust the folder if configured, at most once per folder until the prompt is reset
+func (f *Folder) promptTrust() {
+	if config.CurrentConfig().UntrustedFolderBehavior() != config.UntrustedFolderBehaviorPrompt {
+		return
+	}
+	f.mutex.Lock()
+	alreadyPrompted := f.trustPrompted
+	f.trustPrompted = true
+	f.mutex.Unlock()
+	if alreadyPrompted {
+		return
+	}
+
+	actions := data_structure.NewOrderedMap[vulnmap.MessageAction, vulnmap.CommandData]()
+	actions.Add(trustFolderMessageActionItemTitle, vulnmap.CommandData{
+		Title:     vulnmap.TrustFolderCommand,
+		CommandId: vulnmap.TrustFolderCommand,
+		Arguments: []any{f.path},
+	})
+	actions.Add(dontTrustFolderMessageActionItemTitle, vulnmap.CommandData{})
+	f.notifier.Send(vulnmap.ShowMessageRequest{
+		Message: untrustedFolderMessage(f.path),
+		Type:    vulnmap.Warning,
+		Actions: actions,
+	})
+}
+
+// ResetTrustPrompt forgets that the user was asked to trust the folder. If the folder is still untrusted, the user
+// is asked again right away.
+func (f *Folder) ResetTrustPrompt() {
+	f.resetTrustPrompt()
+	if !f.IsTrusted() {
+		f.promptTrust()
+	}
+}
+
+func (f *Folder) resetTrustPrompt() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.trustPrompted = false
+}
@@ -0,0 +1,105 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func showMessageRequests(notifier *notification.MockNotifier) []vulnmap.ShowMessageRequest {
+	var requests []vulnmap.ShowMessageRequest
+	for _, message := range notifier.SentMessages() {
+		if request, ok := message.(vulnmap.ShowMessageRequest); ok {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+func Test_scan_UntrustedFolderPromptsToTrustOncePerSession(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(true)
+	c.SetUntrustedFolderBehavior(config.UntrustedFolderBehaviorPrompt)
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewMockNotifier()
+	f := NewFolder(t.TempDir(), "untrusted", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+
+	f.ScanFolder(context.Background())
+	f.ScanFolder(context.Background())
+
+	assert.Equal(t, 0, scanner.Calls())
+	requests := showMessageRequests(notifier)
+	require.Len(t, requests, 1)
+	assert.Equal(t, vulnmap.Warning, requests[0].Type)
+	assert.Equal(t, untrustedFolderMessage(f.Path()), requests[0].Message)
+	trustCommand, ok := requests[0].Actions.Get(trustFolderMessageActionItemTitle)
+	require.True(t, ok)
+	assert.Equal(t, vulnmap.TrustFolderCommand, trustCommand.CommandId)
+	assert.Equal(t, []any{f.Path()}, trustCommand.Arguments)
+}
+
+func Test_scan_UntrustedFolderIsSkippedSilentlyByDefault(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(true)
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewMockNotifier()
+	f := NewFolder(t.TempDir(), "untrusted", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+
+	f.ScanFolder(context.Background())
+
+	assert.Equal(t, 0, scanner.Calls())
+	assert.Empty(t, showMessageRequests(notifier))
+}
+
+func Test_ResetTrustPrompt_PromptsAgainForUntrustedFolder(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(true)
+	c.SetUntrustedFolderBehavior(config.UntrustedFolderBehaviorPrompt)
+	notifier := notification.NewMockNotifier()
+	f := NewFolder(t.TempDir(), "untrusted", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	f.ScanFolder(context.Background())
+	require.Len(t, showMessageRequests(notifier), 1)
+
+	f.ResetTrustPrompt()
+
+	assert.Len(t, showMessageRequests(notifier), 2)
+	f.ScanFolder(context.Background())
+	assert.Len(t, showMessageRequests(notifier), 2, "the repeated prompt is shown once, too")
+}
+
+func Test_ResetTrustPrompt_DoesntPromptForTrustedFolder(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(true)
+	c.SetUntrustedFolderBehavior(config.UntrustedFolderBehaviorPrompt)
+	notifier := notification.NewMockNotifier()
+	f := NewFolder(t.TempDir(), "trusted", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	c.SetTrustedFolders([]string{f.Path()})
+
+	f.ResetTrustPrompt()
+
+	assert.Empty(t, showMessageRequests(notifier))
+}
@@ -19,12 +19,14 @@ package workspace
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
 	noti "github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
@@ -37,7 +39,7 @@ var instance *Workspace
 
 // Workspace represents the highest entity in an IDE that contains code. A workspace may contain multiple folders
 type Workspace struct {
-	mutex               sync.Mutex
+	mutex               sync.RWMutex
 	folders             map[string]*Folder
 	instrumentor        performance.Instrumentor
 	scanner             vulnmap.Scanner
@@ -46,6 +48,8 @@ type Workspace struct {
 	trustMutex          sync.Mutex
 	trustRequestOngoing bool // for debouncing
 	notifier            noti.Notifier
+	errorReporter       error_reporting.ErrorReporter
+	scanPause           *scanPause
 }
 
 func New(instrumentor performance.Instrumentor,
@@ -61,6 +65,7 @@ func New(instrumentor performance.Instrumentor,
 		hoverService: hoverService,
 		scanNotifier: scanNotifier,
 		notifier:     notifier,
+		scanPause:    newScanPause(),
 	}
 }
 
@@ -73,21 +78,32 @@ func Set(w *Workspace) {
 	instance = w
 }
 
+// RemoveFolder disposes the workspace folder with the given path: its scans are stopped, its diagnostics are
+// cleared and it's removed from the workspace. Other folders are not affected. If the path is not a workspace
+// folder but is contained in one, e.g. a deleted directory, only the diagnostics below the path are cleared.
 func (w *Workspace) RemoveFolder(folderPath string) {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
-	folder := w.GetFolderContaining(folderPath)
+	if folder, exists := w.folders[folderPath]; exists {
+		folder.StopScans()
+		if folder.EnclosingFolder() == nil {
+			folder.ClearDiagnostics()
+		}
+		delete(w.folders, folderPath)
+		scanUnnestedFolders(w.updateNestedFolders())
+		return
+	}
+	folder := w.folderContaining(folderPath)
 	if folder == nil {
 		return
 	}
 	folder.ClearDiagnosticsFromPathRecursively(folderPath)
-	delete(w.folders, folderPath)
 }
 
 func (w *Workspace) DeleteFile(filePath string) {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
-	folder := w.GetFolderContaining(filePath)
+	folder := w.folderContaining(filePath)
 	if folder != nil {
 		folder.ClearDiagnosticsFromFile(filePath)
 	}
@@ -99,7 +115,45 @@ func (w *Workspace) AddFolder(f *Folder) {
 	if w.folders == nil {
 		w.folders = map[string]*Folder{}
 	}
+	if w.errorReporter != nil {
+		f.SetErrorReporter(w.errorReporter)
+	}
+	f.setScanPause(w.scanPause)
 	w.folders[f.Path()] = f
+	scanUnnestedFolders(w.updateNestedFolders())
+}
+
+// SetErrorReporter sets the error reporter that folders added to the workspace use to report panics during scans
+func (w *Workspace) SetErrorReporter(errorReporter error_reporting.ErrorReporter) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.errorReporter = errorReporter
+	for _, f := range w.folders {
+		f.SetErrorReporter(errorReporter)
+	}
+}
+
+// AddFolderAndScan adds a new folder to the workspace and, if automatic scanning is enabled and the folder is
+// trusted, scans it. If scanning on startup is disabled, the folder is marked as pending instead. Other folders are
+// not rescanned. If the folder already exists, it is returned unchanged.
+func (w *Workspace) AddFolderAndScan(ctx context.Context, folderPath string, name string) *Folder {
+	w.mutex.Lock()
+	if existing, exists := w.folders[folderPath]; exists {
+		w.mutex.Unlock()
+		return existing
+	}
+	w.mutex.Unlock()
+
+	f := NewFolder(folderPath, name, w.scanner, w.hoverService, w.scanNotifier, w.notifier)
+	w.AddFolder(f)
+	if config.CurrentConfig().IsAutoScanEnabled() && f.IsTrusted() {
+		if config.CurrentConfig().IsScanOnStartupEnabled() {
+			go f.ScanFolder(ctx)
+		} else {
+			f.MarkScanPending()
+		}
+	}
+	return f
 }
 
 func (w *Workspace) IssuesFor(path string, r vulnmap.Range) []vulnmap.Issue {
@@ -111,16 +165,31 @@ func (w *Workspace) IssuesFor(path string, r vulnmap.Range) []vulnmap.Issue {
 	return folder.IssuesFor(path, r)
 }
 
+// GetFolderContaining returns the workspace folder that publishes the issues of the path. Nested folders are skipped,
+// as their files are published by their enclosing folder. Of the remaining folders, the most specific one wins.
 func (w *Workspace) GetFolderContaining(path string) (folder *Folder) {
-	for _, folder := range w.folders {
-		if folder.Contains(path) {
-			return folder
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.folderContaining(path)
+}
+
+// folderContaining is GetFolderContaining for callers that hold the mutex of the workspace
+func (w *Workspace) folderContaining(path string) (folder *Folder) {
+	for _, f := range w.folders {
+		if !f.Contains(path) || f.EnclosingFolder() != nil {
+			continue
+		}
+		if folder == nil || len(f.Path()) > len(folder.Path()) {
+			folder = f
 		}
 	}
-	return nil
+	return folder
 }
 
+// Folders returns a copy of the workspace folders, which can be iterated without holding the mutex of the workspace
 func (w *Workspace) Folders() (folder []*Folder) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
 	folders := make([]*Folder, 0, len(w.folders))
 	for _, folder := range w.folders {
 		folders = append(folders, folder)
@@ -137,24 +206,41 @@ func (w *Workspace) ScanWorkspace(ctx context.Context) {
 	}
 }
 
-// ChangeWorkspaceFolders clears the "Removed" folders, adds the "New" folders,
-// and starts an automatic scan if auto-scans are enabled.
+// Shutdown stops the scans of all folders and waits up to the given timeout for pending analytics to be sent.
+// It returns false if the timeout was reached before all analytics were sent.
+func (w *Workspace) Shutdown(timeout time.Duration) bool {
+	for _, folder := range w.Folders() {
+		folder.StopScans()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		pendingAnalytics.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		log.Warn().Str("method", "Workspace.Shutdown").Msg("timed out waiting for pending analytics")
+		return false
+	}
+}
+
+// ChangeWorkspaceFolders removes the "Removed" folders and adds the "New" folders. If auto-scans are enabled,
+// only the added folders are scanned.
 func (w *Workspace) ChangeWorkspaceFolders(ctx context.Context, params lsp.DidChangeWorkspaceFoldersParams) {
 	for _, folder := range params.Event.Removed {
 		w.RemoveFolder(uri.PathFromUri(folder.Uri))
 	}
 	for _, folder := range params.Event.Added {
-		f := NewFolder(uri.PathFromUri(folder.Uri), folder.Name, w.scanner, w.hoverService, w.scanNotifier, w.notifier)
-		w.AddFolder(f)
-	}
-
-	if config.CurrentConfig().IsAutoScanEnabled() {
-		w.ScanWorkspace(ctx)
+		w.AddFolderAndScan(ctx, uri.PathFromUri(folder.Uri), folder.Name)
 	}
 }
 
 func (w *Workspace) ClearIssues(_ context.Context) {
-	for _, folder := range w.folders {
+	for _, folder := range w.Folders() {
 		folder.ClearScannedStatus()
 		folder.ClearDiagnostics()
 	}
@@ -169,13 +255,21 @@ func (w *Workspace) TrustFoldersAndScan(ctx context.Context, foldersToBeTrusted
 		// we need to append and set the trusted path to the config before the scan, as the scan is checking for trust
 		trustedFolderPaths = append(trustedFolderPaths, f.Path())
 		currentConfig.SetTrustedFolders(trustedFolderPaths)
+		// a folder that becomes untrusted again prompts again
+		f.resetTrustPrompt()
+	}
+	// trust decides which folders are nested, the folders that are no longer nested are among the trusted ones
+	w.mutex.Lock()
+	w.updateNestedFolders()
+	w.mutex.Unlock()
+	for _, f := range foldersToBeTrusted {
 		go f.ScanFolder(ctx)
 	}
 	w.notifier.Send(lsp.VulnmapTrustedFoldersParams{TrustedFolders: trustedFolderPaths})
 }
 
 func (w *Workspace) GetFolderTrust() (trusted []*Folder, untrusted []*Folder) {
-	for _, folder := range w.folders {
+	for _, folder := range w.Folders() {
 		if folder.IsTrusted() {
 			trusted = append(trusted, folder)
 			log.Info().Str("folder", folder.Path()).Msg("Trusted folder")
@@ -188,7 +282,46 @@ func (w *Workspace) GetFolderTrust() (trusted []*Folder, untrusted []*Folder) {
 }
 
 func (w *Workspace) ClearIssuesByType(removedType product.FilterableIssueType) {
-	for _, folder := range w.folders {
+	for _, folder := range w.Folders() {
 		folder.ClearDiagnosticsByIssueType(removedType)
 	}
 }
+
+// ClearProductDiagnostics removes the issues of the product from all folders and republishes their diagnostics
+func (w *Workspace) ClearProductDiagnostics(p product.Product) {
+	removedTypes := product.ToFilterableIssueTypes(p)
+	if len(removedTypes) == 0 {
+		return
+	}
+	for _, folder := range w.Folders() {
+		folder.ClearDiagnosticsByIssueTypes(removedTypes...)
+	}
+}
+
+// DisplayPath returns the path as it is shown to users in messages and reports, relative to the root of the
+// containing workspace folder if configured. See vulnmap.DisplayPath.
+func DisplayPath(path string) string {
+	w := Get()
+	if w == nil {
+		return path
+	}
+	folder := w.GetFolderContaining(path)
+	if folder == nil {
+		return path
+	}
+	return vulnmap.DisplayPath(path, folder.Path())
+}
+
+// DisplayPathsIn returns the text about the file at the path, e.g. the formatted message of an issue, with the paths
+// of the containing workspace folder shown relative to its root if configured. See vulnmap.DisplayPathsIn.
+func DisplayPathsIn(text string, path string) string {
+	w := Get()
+	if w == nil {
+		return text
+	}
+	folder := w.GetFolderContaining(path)
+	if folder == nil {
+		return text
+	}
+	return vulnmap.DisplayPathsIn(text, folder.Path())
+}
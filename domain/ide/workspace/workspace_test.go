@@ -18,16 +18,21 @@ package workspace
 
 import (
 	"context"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/hover"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
 )
@@ -113,6 +118,86 @@ func Test_AddAndRemoveFoldersAndTriggerScan(t *testing.T) {
 	}, time.Second, time.Millisecond, "scanner should be called after trust is granted")
 }
 
+func Test_AddFolderAndScan_ScansOnlyTheAddedFolder(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAutomaticScanning(true)
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewNotifier()
+	w := New(performance.NewInstrumentor(), scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	existing := NewFolder(t.TempDir(), "existing", scanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(existing)
+
+	added := w.AddFolderAndScan(context.Background(), t.TempDir(), "added")
+
+	assert.Eventually(t, added.IsScanned, time.Second, time.Millisecond)
+	assert.Equal(t, 1, scanner.Calls())
+	assert.False(t, existing.IsScanned())
+	assert.Equal(t, added, w.GetFolderContaining(added.Path()))
+}
+
+func Test_RemoveFolder_ClearsOnlyDiagnosticsOfRemovedFolder(t *testing.T) {
+	testutil.UnitTest(t)
+	removedPath, keptPath := t.TempDir(), t.TempDir()
+	removedFile, keptFile := filepath.Join(removedPath, "package.json"), filepath.Join(keptPath, "package.json")
+	removedScanner, keptScanner := vulnmap.NewTestScanner(), vulnmap.NewTestScanner()
+	removedScanner.AddTestIssue(NewMockIssue("removed", removedFile))
+	keptScanner.AddTestIssue(NewMockIssue("kept", keptFile))
+	notifier := notification.NewMockNotifier()
+	w := New(performance.NewInstrumentor(), keptScanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	removed := NewFolder(removedPath, "removed", removedScanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	kept := NewFolder(keptPath, "kept", keptScanner, hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(removed)
+	w.AddFolder(kept)
+	removed.ScanFolder(context.Background())
+	kept.ScanFolder(context.Background())
+	require.Len(t, removed.DocumentDiagnosticsFromCache(removedFile), 1)
+	sentBeforeRemoval := len(notifier.SentMessages())
+
+	w.RemoveFolder(removedPath)
+
+	assert.Nil(t, w.GetFolderContaining(removedPath))
+	assert.Nil(t, removed.DocumentDiagnosticsFromCache(removedFile))
+	assert.Error(t, removed.stopCtx.Err(), "scans of the removed folder should be stopped")
+	assert.Len(t, kept.DocumentDiagnosticsFromCache(keptFile), 1)
+	assert.Equal(t, kept, w.GetFolderContaining(keptPath))
+	assert.Equal(t, []any{lsp.PublishDiagnosticsParams{URI: uri.PathToUri(removedFile), Diagnostics: []lsp.Diagnostic{}}},
+		notifier.SentMessages()[sentBeforeRemoval:])
+}
+
+func Test_ClearProductDiagnostics_ClearsOnlyTheProductInAllFolders(t *testing.T) {
+	testutil.UnitTest(t)
+	notifier := notification.NewMockNotifier()
+	w := New(performance.NewInstrumentor(), vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	var files []string
+	for _, name := range []string{"first", "second"} {
+		folder := NewFolder(t.TempDir(), name, vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+		w.AddFolder(folder)
+		file := filepath.Join(folder.Path(), "main.go")
+		files = append(files, file)
+		codeSecurityIssue := NewMockIssue(name+"-code-security", file)
+		codeSecurityIssue.Product = product.ProductCode
+		codeSecurityIssue.IssueType = vulnmap.CodeSecurityVulnerability
+		codeQualityIssue := NewMockIssue(name+"-code-quality", file)
+		codeQualityIssue.Product = product.ProductCode
+		codeQualityIssue.IssueType = vulnmap.CodeQualityIssue
+		ossIssue := NewMockIssue(name+"-oss", file)
+		folder.processResults(vulnmap.ScanData{
+			Product: product.ProductCode,
+			Issues:  []vulnmap.Issue{codeSecurityIssue, codeQualityIssue, ossIssue},
+		})
+	}
+	sentBeforeClear := len(notifier.SentMessages())
+
+	w.ClearProductDiagnostics(product.ProductCode)
+
+	for _, file := range files {
+		issues := w.GetFolderContaining(file).DocumentDiagnosticsFromCache(file)
+		require.Len(t, issues, 1)
+		assert.Equal(t, product.ProductOpenSource, issues[0].Product)
+	}
+	assert.Len(t, notifier.SentMessages()[sentBeforeClear:], len(files), "diagnostics are republished once per file")
+}
+
 func Test_Get(t *testing.T) {
 	New(nil, nil, nil, nil, nil)
 	assert.Equal(t, instance, Get())
@@ -123,3 +208,72 @@ func Test_Set(t *testing.T) {
 	Set(w)
 	assert.Equal(t, w, instance)
 }
+
+func Test_Shutdown_shouldWaitForPendingAnalytics(t *testing.T) {
+	testutil.UnitTest(t)
+	w := New(performance.NewInstrumentor(), &vulnmap.TestScanner{}, nil, nil, notification.NewNotifier())
+	pendingAnalytics.Add(1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		pendingAnalytics.Done()
+	}()
+
+	start := time.Now()
+	completed := w.Shutdown(time.Second)
+
+	assert.True(t, completed)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func Test_Shutdown_shouldStopWaitingAfterTimeout(t *testing.T) {
+	testutil.UnitTest(t)
+	w := New(performance.NewInstrumentor(), &vulnmap.TestScanner{}, nil, nil, notification.NewNotifier())
+	pendingAnalytics.Add(1)
+	t.Cleanup(pendingAnalytics.Done)
+
+	completed := w.Shutdown(50 * time.Millisecond)
+
+	assert.False(t, completed)
+}
+
+func Test_Shutdown_shouldStopScansOfFolders(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetTrustedFolderFeatureEnabled(false)
+	scanner := vulnmap.NewTestScanner()
+	notifier := notification.NewNotifier()
+	w := New(performance.NewInstrumentor(), scanner, nil, nil, notifier)
+	f := NewFolder(t.TempDir(), "dummy", scanner, nil, vulnmap.NewMockScanNotifier(), notifier)
+	w.AddFolder(f)
+
+	w.Shutdown(time.Second)
+	f.ScanFolder(context.Background())
+
+	assert.Equal(t, 0, scanner.Calls())
+}
+
+func Test_Folders_CanBeReadWhileFoldersAreAddedAndRemoved(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAutomaticScanning(false)
+	notifier := notification.NewNotifier()
+	w := New(performance.NewInstrumentor(), vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier)
+	folderPath := t.TempDir()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			w.AddFolder(NewFolder(folderPath, "dummy", vulnmap.NewTestScanner(), hover.NewFakeHoverService(), vulnmap.NewMockScanNotifier(), notifier))
+			w.RemoveFolder(folderPath)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_ = w.Folders()
+		_ = w.GetFolderContaining(folderPath)
+		_, _ = w.GetFolderTrust()
+		w.ClearIssuesByType(product.FilterableIssueTypeOpenSource)
+	}
+	wg.Wait()
+
+	assert.Empty(t, w.Folders())
+}
@@ -0,0 +1,186 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package error_reporting
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultDeduplicationWindow = 30 * time.Second
+	DefaultMaxReportsPerWindow = 20
+)
+
+// DuplicateError is reported at the end of the deduplication window if an error occurred multiple times within it
+type DuplicateError struct {
+	Err   error
+	Count int
+}
+
+func (e *DuplicateError) Error() string {
+	if e.Count <= 1 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s (occurred %d times)", e.Err.Error(), e.Count)
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return e.Err
+}
+
+// pendingReport tracks an error that was reported within the current deduplication window
+type pendingReport struct {
+	err     error
+	path    string
+	asIssue bool
+	// count is the number of occurrences of the error within the window, including the reported first one
+	count int
+	timer *time.Timer
+}
+
+// deduplicatingErrorReporter wraps an ErrorReporter and limits the number of reports sent per window. The first
+// occurrence of an error is reported immediately, identical errors (same message and stack top) that occur within
+// the window are collapsed into a single summary report at its end.
+type deduplicatingErrorReporter struct {
+	delegate   ErrorReporter
+	window     time.Duration
+	maxReports int
+	mutex      sync.Mutex
+	pending    map[string]*pendingReport
+	reportedAt []time.Time
+}
+
+func NewDeduplicatingErrorReporter(delegate ErrorReporter, window time.Duration, maxReports int) ErrorReporter {
+	return &deduplicatingErrorReporter{
+		delegate:   delegate,
+		window:     window,
+		maxReports: maxReports,
+		pending:    map[string]*pendingReport{},
+	}
+}
+
+func (d *deduplicatingErrorReporter) FlushErrorReporting() {
+	d.mutex.Lock()
+	reports := make([]*pendingReport, 0, len(d.pending))
+	for key, report := range d.pending {
+		report.timer.Stop()
+		reports = append(reports, report)
+		delete(d.pending, key)
+	}
+	d.mutex.Unlock()
+
+	for _, report := range reports {
+		d.send(report)
+	}
+	d.delegate.FlushErrorReporting()
+}
+
+func (d *deduplicatingErrorReporter) CaptureError(err error) bool {
+	return d.capture("", err, false)
+}
+
+func (d *deduplicatingErrorReporter) CaptureErrorAndReportAsIssue(path string, err error) bool {
+	return d.capture(path, err, true)
+}
+
+func (d *deduplicatingErrorReporter) capture(path string, err error, asIssue bool) bool {
+	if err == nil {
+		return false
+	}
+	key := fingerprint(path, err)
+
+	d.mutex.Lock()
+	if report, exists := d.pending[key]; exists {
+		report.count++
+		d.mutex.Unlock()
+		return true
+	}
+
+	now := time.Now()
+	d.pruneReportedAt(now)
+	if len(d.reportedAt) >= d.maxReports {
+		d.mutex.Unlock()
+		log.Warn().Str("method", "deduplicatingErrorReporter.capture").Err(err).Msg("error report rate limit exceeded, dropping error")
+		return false
+	}
+	d.reportedAt = append(d.reportedAt, now)
+
+	report := &pendingReport{err: err, path: path, asIssue: asIssue, count: 1}
+	report.timer = time.AfterFunc(d.window, func() { d.expire(key, report) })
+	d.pending[key] = report
+	d.mutex.Unlock()
+
+	return d.sendError(path, err, asIssue)
+}
+
+func (d *deduplicatingErrorReporter) expire(key string, report *pendingReport) {
+	d.mutex.Lock()
+	if d.pending[key] != report {
+		// already flushed
+		d.mutex.Unlock()
+		return
+	}
+	delete(d.pending, key)
+	d.mutex.Unlock()
+	d.send(report)
+}
+
+// send reports the repetitions of the error within the window, the first occurrence was already reported
+func (d *deduplicatingErrorReporter) send(report *pendingReport) {
+	if report.count <= 1 {
+		return
+	}
+	d.sendError(report.path, &DuplicateError{Err: report.err, Count: report.count}, report.asIssue)
+}
+
+func (d *deduplicatingErrorReporter) sendError(path string, err error, asIssue bool) bool {
+	if asIssue {
+		return d.delegate.CaptureErrorAndReportAsIssue(path, err)
+	}
+	return d.delegate.CaptureError(err)
+}
+
+func (d *deduplicatingErrorReporter) pruneReportedAt(now time.Time) {
+	i := 0
+	for i < len(d.reportedAt) && now.Sub(d.reportedAt[i]) >= d.window {
+		i++
+	}
+	d.reportedAt = d.reportedAt[i:]
+}
+
+type stackTracer interface {
+	StackTrace() errors.StackTrace
+}
+
+// fingerprint identifies an error by its message and the top of its stack. If the error does not carry a stack
+// trace, the caller of the error reporter is used instead.
+func fingerprint(path string, err error) string {
+	var stackTop string
+	var tracer stackTracer
+	if errors.As(err, &tracer) && len(tracer.StackTrace()) > 0 {
+		stackTop = fmt.Sprintf("%+v", tracer.StackTrace()[0])
+	} else if _, file, line, ok := runtime.Caller(3); ok {
+		stackTop = fmt.Sprintf("%s:%d", file, line)
+	}
+	return path + "|" + err.Error() + "|" + stackTop
+}
@@ -0,0 +1,136 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package error_reporting
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingErrorReporter struct {
+	mutex   sync.Mutex
+	errors  []error
+	paths   []string
+	flushes int
+}
+
+func (r *recordingErrorReporter) FlushErrorReporting() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.flushes++
+}
+
+func (r *recordingErrorReporter) CaptureError(err error) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.errors = append(r.errors, err)
+	return true
+}
+
+func (r *recordingErrorReporter) CaptureErrorAndReportAsIssue(path string, err error) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.errors = append(r.errors, err)
+	r.paths = append(r.paths, path)
+	return true
+}
+
+func (r *recordingErrorReporter) Errors() []error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.errors
+}
+
+func Test_DeduplicatingErrorReporter_CollapsesIdenticalErrors(t *testing.T) {
+	delegate := &recordingErrorReporter{}
+	reporter := NewDeduplicatingErrorReporter(delegate, time.Minute, 10)
+
+	for i := 0; i < 100; i++ {
+		assert.True(t, reporter.CaptureError(errors.New("systemic failure")))
+	}
+	require.Len(t, delegate.Errors(), 1, "the first occurrence is reported immediately")
+	assert.Equal(t, "systemic failure", delegate.Errors()[0].Error())
+	reporter.FlushErrorReporting()
+
+	require.Len(t, delegate.Errors(), 2)
+	var duplicateError *DuplicateError
+	require.ErrorAs(t, delegate.Errors()[1], &duplicateError)
+	assert.Equal(t, 100, duplicateError.Count)
+	assert.Equal(t, "systemic failure (occurred 100 times)", duplicateError.Error())
+	assert.Equal(t, 1, delegate.flushes)
+}
+
+func Test_DeduplicatingErrorReporter_SingleErrorIsReportedUnchanged(t *testing.T) {
+	delegate := &recordingErrorReporter{}
+	reporter := NewDeduplicatingErrorReporter(delegate, time.Minute, 10)
+	err := errors.New("single failure")
+
+	reporter.CaptureError(err)
+
+	require.Len(t, delegate.Errors(), 1)
+	assert.Same(t, err, delegate.Errors()[0])
+	reporter.FlushErrorReporting()
+	assert.Len(t, delegate.Errors(), 1, "no summary is reported for a single occurrence")
+}
+
+func Test_DeduplicatingErrorReporter_DistinguishesMessagesAndPaths(t *testing.T) {
+	delegate := &recordingErrorReporter{}
+	reporter := NewDeduplicatingErrorReporter(delegate, time.Minute, 10)
+
+	for i := 0; i < 3; i++ {
+		reporter.CaptureError(errors.New("a"))
+		reporter.CaptureError(errors.New("b"))
+		reporter.CaptureErrorAndReportAsIssue("path1", errors.New("a"))
+		reporter.CaptureErrorAndReportAsIssue("path2", errors.New("a"))
+	}
+	assert.Len(t, delegate.Errors(), 4)
+	reporter.FlushErrorReporting()
+
+	assert.Len(t, delegate.Errors(), 8, "the first occurrences and the summaries of the repetitions are reported")
+	assert.ElementsMatch(t, []string{"path1", "path2", "path1", "path2"}, delegate.paths)
+}
+
+func Test_DeduplicatingErrorReporter_RateLimitsDistinctErrors(t *testing.T) {
+	delegate := &recordingErrorReporter{}
+	reporter := NewDeduplicatingErrorReporter(delegate, time.Minute, 2)
+
+	assert.True(t, reporter.CaptureError(errors.New("a")))
+	assert.True(t, reporter.CaptureError(errors.New("b")))
+	assert.False(t, reporter.CaptureError(errors.New("c")))
+	reporter.FlushErrorReporting()
+
+	assert.Len(t, delegate.Errors(), 2)
+}
+
+func Test_DeduplicatingErrorReporter_ReportsWhenWindowExpires(t *testing.T) {
+	delegate := &recordingErrorReporter{}
+	reporter := NewDeduplicatingErrorReporter(delegate, 10*time.Millisecond, 10)
+
+	for i := 0; i < 2; i++ {
+		reporter.CaptureError(errors.New("a"))
+	}
+
+	assert.Eventually(t, func() bool { return len(delegate.Errors()) == 2 }, time.Second, time.Millisecond)
+	var duplicateError *DuplicateError
+	require.ErrorAs(t, delegate.Errors()[1], &duplicateError)
+	assert.Equal(t, 2, duplicateError.Count)
+}
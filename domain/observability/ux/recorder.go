@@ -34,6 +34,7 @@ type TestAnalytics struct {
 	Identified              bool
 	Initialized             bool
 	ScanModeIsSelectedCount int
+	ShutdownCount           int
 }
 
 func (n *TestAnalytics) GetAnalytics() []any {
@@ -76,6 +77,9 @@ func (n *TestAnalytics) Initialise() {
 }
 func (n *TestAnalytics) Shutdown() error {
 	log.Info().Str("method", "Shutdown").Msgf("no op")
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.ShutdownCount++
 	return nil
 }
 func (n *TestAnalytics) Identify() {
@@ -21,6 +21,8 @@ type AnalysisIsReadyProperties struct {
 	Result            Result       `json:"result"`
 	FileCount         int          `json:"fileCount,omitempty"`
 	DurationInSeconds float64      `json:"durationInSeconds,omitempty"`
+	// OmittedIssueCount is the number of issues dropped because the scan exceeded the configured maximum
+	OmittedIssueCount int `json:"omittedIssueCount,omitempty"`
 }
 
 type AnalysisIsTriggeredProperties struct {
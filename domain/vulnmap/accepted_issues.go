@@ -0,0 +1,122 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AcceptedIssues contains the individually accepted issues with the justification for accepting them, so that
+// teams have an audit trail of why issues are hidden
+type AcceptedIssues struct {
+	// Entries are keyed by the fingerprint of the accepted issue
+	Entries map[string]AcceptedIssue `json:"entries"`
+}
+
+type AcceptedIssue struct {
+	IssueId       string `json:"issueId"`
+	Justification string `json:"justification"`
+	// AcceptedBy is the user that accepted the issue, empty if it couldn't be determined
+	AcceptedBy string    `json:"acceptedBy,omitempty"`
+	AcceptedAt time.Time `json:"acceptedAt"`
+	// Expires is a date in the format YYYY-MM-DD after which the issue is shown again
+	Expires string `json:"expires,omitempty"`
+}
+
+// IsExpired returns true if the acceptance doesn't apply anymore at the given time
+func (a AcceptedIssue) IsExpired(now time.Time) bool {
+	return isExpired(a.Expires, now)
+}
+
+func NewAcceptedIssues() *AcceptedIssues {
+	return &AcceptedIssues{Entries: make(map[string]AcceptedIssue)}
+}
+
+// LoadAcceptedIssues reads the accepted issues from the given JSON file
+func LoadAcceptedIssues(path string) (*AcceptedIssues, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read accepted issues")
+	}
+	var acceptedIssues AcceptedIssues
+	err = json.Unmarshal(bytes, &acceptedIssues)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't parse accepted issues")
+	}
+	if acceptedIssues.Entries == nil {
+		acceptedIssues.Entries = make(map[string]AcceptedIssue)
+	}
+	return &acceptedIssues, nil
+}
+
+// Save writes the accepted issues to the given path, creating its directory if needed
+func (a *AcceptedIssues) Save(path string) error {
+	bytes, err := json.Marshal(a)
+	if err != nil {
+		return errors.Wrap(err, "couldn't marshal accepted issues")
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrap(err, "couldn't create accepted issues directory")
+	}
+	return errors.Wrap(os.WriteFile(path, bytes, 0600), "couldn't write accepted issues")
+}
+
+// Accept accepts the issue until the expiry date in the format YYYY-MM-DD, or forever if it is empty. A
+// justification is required. Accepting an issue again replaces its previous acceptance.
+func (a *AcceptedIssues) Accept(issue Issue, justification string, acceptedBy string, now time.Time,
+	expires string) (AcceptedIssue, error) {
+	justification = strings.TrimSpace(justification)
+	if justification == "" {
+		return AcceptedIssue{}, errors.New("a justification is required to accept an issue")
+	}
+	if _, err := parseExpiry(expires); err != nil {
+		return AcceptedIssue{}, errors.Wrapf(err, "invalid expiry date %s", expires)
+	}
+	accepted := AcceptedIssue{
+		IssueId:       issue.ID,
+		Justification: justification,
+		AcceptedBy:    acceptedBy,
+		AcceptedAt:    now,
+		Expires:       expires,
+	}
+	a.Entries[issue.Fingerprint()] = accepted
+	return accepted, nil
+}
+
+// Contains returns true if the issue is accepted and its acceptance isn't expired at the given time
+func (a *AcceptedIssues) Contains(issue Issue, now time.Time) bool {
+	if a == nil {
+		return false
+	}
+	entry, ok := a.Entries[issue.Fingerprint()]
+	return ok && !entry.IsExpired(now)
+}
+
+// AcceptedIssuesLoader loads the accepted issues of the folders and reloads them only when their files changed
+type AcceptedIssuesLoader struct {
+	*cachedFileLoader[AcceptedIssues]
+}
+
+func NewAcceptedIssuesLoader() *AcceptedIssuesLoader {
+	return &AcceptedIssuesLoader{newCachedFileLoader("accepted issues", LoadAcceptedIssues)}
+}
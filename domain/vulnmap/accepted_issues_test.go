@@ -0,0 +1,75 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AcceptedIssues_AcceptWithJustification(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	issue := Issue{ID: "VULNMAP-JS-LODASH-1", AffectedFilePath: "package.json"}
+	acceptedIssues := NewAcceptedIssues()
+
+	accepted, err := acceptedIssues.Accept(issue, " not reachable from user input ", "jane", now, "")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "nested", "accepted_issues.json")
+	require.NoError(t, acceptedIssues.Save(path))
+	loaded, err := LoadAcceptedIssues(path)
+
+	require.NoError(t, err)
+	expected := AcceptedIssue{
+		IssueId:       issue.ID,
+		Justification: "not reachable from user input",
+		AcceptedBy:    "jane",
+		AcceptedAt:    now,
+	}
+	assert.Equal(t, expected, accepted)
+	assert.Equal(t, expected, loaded.Entries[issue.Fingerprint()])
+	assert.True(t, loaded.Contains(issue, now))
+	assert.False(t, loaded.Contains(Issue{ID: "VULNMAP-JS-LODASH-2", AffectedFilePath: "package.json"}, now))
+}
+
+func Test_AcceptedIssues_RequiresJustification(t *testing.T) {
+	acceptedIssues := NewAcceptedIssues()
+
+	_, err := acceptedIssues.Accept(Issue{ID: "VULNMAP-JS-LODASH-1"}, "  ", "", time.Now(), "")
+
+	assert.Error(t, err)
+	assert.Empty(t, acceptedIssues.Entries)
+}
+
+func Test_AcceptedIssues_InvalidExpiry(t *testing.T) {
+	_, err := NewAcceptedIssues().Accept(Issue{ID: "VULNMAP-JS-LODASH-1"}, "accepted risk", "", time.Now(), "next week")
+
+	assert.Error(t, err)
+}
+
+func Test_AcceptedIssues_ExpiredAcceptanceDoesntApply(t *testing.T) {
+	issue := Issue{ID: "VULNMAP-JS-LODASH-1", AffectedFilePath: "package.json"}
+	acceptedIssues := NewAcceptedIssues()
+	_, err := acceptedIssues.Accept(issue, "fix planned for next sprint", "", time.Now(), "2024-06-01")
+	require.NoError(t, err)
+
+	assert.True(t, acceptedIssues.Contains(issue, time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC)))
+	assert.False(t, acceptedIssues.Contains(issue, time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)))
+}
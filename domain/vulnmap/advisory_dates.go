@@ -0,0 +1,35 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import "time"
+
+// AdvisoryUpdated returns when the advisory of the issue was last modified, or disclosed if it was never modified.
+// ok is false if the issue has no advisory dates, e.g. for licence issues or issues of other products.
+func (i Issue) AdvisoryUpdated() (updated time.Time, ok bool) {
+	data, isOss := i.AdditionalData.(OssIssueData)
+	if !isOss {
+		return time.Time{}, false
+	}
+	if data.ModificationTime != nil {
+		return *data.ModificationTime, true
+	}
+	if data.DisclosureTime != nil {
+		return *data.DisclosureTime, true
+	}
+	return time.Time{}, false
+}
@@ -33,6 +33,10 @@ type AuthenticationService interface {
 	// IsAuthenticated returns true if the token is verified
 	IsAuthenticated() (bool, error)
 
+	// AuthState returns whether no token is set, the token is valid or the token is set but no longer valid.
+	// Transient failures while checking the token are returned as error.
+	AuthState() (AuthState, error)
+
 	// SetProvider sets the authentication provider
 	SetProvider(provider AuthenticationProvider)
 }
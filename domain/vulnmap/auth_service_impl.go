@@ -20,8 +20,6 @@ import (
 	"context"
 	"reflect"
 
-	"github.com/rs/zerolog/log"
-
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	noti "github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
@@ -63,9 +61,10 @@ func (a *authenticationService) Provider() AuthenticationProvider {
 }
 
 func (a *authenticationService) Authenticate(ctx context.Context) (string, error) {
+	logger := config.CurrentConfig().SubsystemLogger(config.LogSubsystemAuth)
 	token, err := a.authenticationProvider.Authenticate(ctx)
 	if token == "" || err != nil {
-		log.Error().Err(err).Msgf("Failed to authenticate using auth provider %v", reflect.TypeOf(a.Provider()))
+		logger.Error().Err(err).Msgf("Failed to authenticate using auth provider %v", reflect.TypeOf(a.Provider()))
 		return "", err
 	}
 	a.UpdateCredentials(token, true)
@@ -88,9 +87,10 @@ func (a *authenticationService) UpdateCredentials(newToken string, sendNotificat
 }
 
 func (a *authenticationService) Logout(ctx context.Context) {
+	logger := config.CurrentConfig().SubsystemLogger(config.LogSubsystemAuth)
 	err := a.authenticationProvider.ClearAuthentication(ctx)
 	if err != nil {
-		log.Error().Err(err).Str("method", "Logout").Msg("Failed to log out.")
+		logger.Error().Err(err).Str("method", "Logout").Msg("Failed to log out.")
 		a.errorReporter.CaptureError(err)
 		return
 	}
@@ -102,22 +102,41 @@ func (a *authenticationService) Logout(ctx context.Context) {
 // If the token is set, but not valid IsAuthenticated returns false and the reported error
 func (a *authenticationService) IsAuthenticated() (bool, error) {
 	c := config.CurrentConfig()
+	logger := c.SubsystemLogger(config.LogSubsystemAuth)
 	if !c.NonEmptyToken() {
-		c.Logger().Info().Str("method", "IsAuthenticated").Msg("No token set")
+		logger.Info().Str("method", "IsAuthenticated").Msg("No token set")
 		return false, nil
 	}
 
 	user, getActiveUserErr := a.authenticationProvider.GetCheckAuthenticationFunction()()
 
 	if getActiveUserErr != nil {
-		log.Err(getActiveUserErr).Str("method", "IsAuthenticated").Msg("Failed to get active user")
+		logger.Err(getActiveUserErr).Str("method", "IsAuthenticated").Msg("Failed to get active user")
 		return false, getActiveUserErr
 	}
 
-	log.Debug().Msg("IsAuthenticated: " + user)
+	logger.Debug().Msg("IsAuthenticated: " + user)
 	return true, nil
 }
 
+func (a *authenticationService) AuthState() (AuthState, error) {
+	c := config.CurrentConfig()
+	logger := c.SubsystemLogger(config.LogSubsystemAuth)
+	if !c.NonEmptyToken() {
+		return AuthStateNotAuthenticated, nil
+	}
+
+	_, err := a.authenticationProvider.GetCheckAuthenticationFunction()()
+	if err == nil {
+		return AuthStateAuthenticated, nil
+	}
+	if IsTransientError(err) {
+		logger.Err(err).Str("method", "AuthState").Msg("Failed to check authentication")
+		return "", err
+	}
+	return AuthStateExpired, nil
+}
+
 func (a *authenticationService) SetProvider(provider AuthenticationProvider) {
 	a.authenticationProvider = provider
 }
@@ -111,6 +111,45 @@ func Test_IsAuthenticated(t *testing.T) {
 	})
 }
 
+func Test_AuthState(t *testing.T) {
+	newService := func(isAuthenticated bool) vulnmap.AuthenticationService {
+		return vulnmap.NewAuthenticationService(
+			&vulnmap.FakeAuthenticationProvider{IsAuthenticated: isAuthenticated},
+			ux.NewTestAnalytics(),
+			error_reporting.NewTestErrorReporter(),
+			notification.NewNotifier(),
+		)
+	}
+
+	t.Run("no token is not authenticated", func(t *testing.T) {
+		c := testutil.UnitTest(t)
+		c.SetToken("")
+
+		state, err := newService(true).AuthState()
+
+		assert.NoError(t, err)
+		assert.Equal(t, vulnmap.AuthStateNotAuthenticated, state)
+	})
+
+	t.Run("valid token is authenticated", func(t *testing.T) {
+		testutil.UnitTest(t)
+
+		state, err := newService(true).AuthState()
+
+		assert.NoError(t, err)
+		assert.Equal(t, vulnmap.AuthStateAuthenticated, state)
+	})
+
+	t.Run("invalid token is expired", func(t *testing.T) {
+		testutil.UnitTest(t)
+
+		state, err := newService(false).AuthState()
+
+		assert.NoError(t, err)
+		assert.Equal(t, vulnmap.AuthStateExpired, state)
+	})
+}
+
 func Test_Logout(t *testing.T) {
 	testutil.IntegTest(t)
 
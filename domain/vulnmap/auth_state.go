@@ -0,0 +1,27 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+// AuthState distinguishes a user that never authenticated from one whose token is no longer valid, so that the client
+// can prompt accordingly
+type AuthState string
+
+const (
+	AuthStateNotAuthenticated AuthState = "notAuthenticated"
+	AuthStateAuthenticated    AuthState = "authenticated"
+	AuthStateExpired          AuthState = "expired"
+)
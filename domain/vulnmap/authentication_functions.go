@@ -18,6 +18,8 @@ package vulnmap
 
 import (
 	"encoding/json"
+	"net"
+	"net/http"
 
 	"github.com/pkg/errors"
 
@@ -25,6 +27,7 @@ import (
 	localworkflows "github.com/khulnasoft-lab/go-application-framework/pkg/local_workflows"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/vulnmap_api"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
 )
 
@@ -52,7 +55,7 @@ func GetActiveUser() (*ActiveUser, error) {
 	result, err := c.Engine().InvokeWithConfig(localworkflows.WORKFLOWID_WHOAMI, conf)
 
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to invoke whoami workflow")
+		return nil, errors.Wrap(toTypedWhoamiError(err), "failed to invoke whoami workflow")
 	}
 	if len(result) == 0 {
 		return nil, errors.New("no user data found")
@@ -77,3 +80,31 @@ func GetActiveUser() (*ActiveUser, error) {
 
 	return &user, nil
 }
+
+// toTypedWhoamiError converts the HTTP status errors of the whoami workflow, which are registered by
+// vulnmap_api.RegisterWhoamiWorkflow, into authentication errors if the request wasn't authorized
+func toTypedWhoamiError(err error) error {
+	var apiError *vulnmap_api.VulnmapApiError
+	if !errors.As(err, &apiError) {
+		return err
+	}
+	if apiError.StatusCode() == http.StatusUnauthorized || apiError.StatusCode() == http.StatusForbidden {
+		return &AuthenticationFailedError{}
+	}
+	return err
+}
+
+// IsTransientError returns true for errors that may go away when retrying, i.e. network errors and server errors.
+// Authentication errors are never transient.
+func IsTransientError(err error) bool {
+	var authenticationFailedError *AuthenticationFailedError
+	if errors.As(err, &authenticationFailedError) || errors.Is(err, ErrEmptyAPIToken) {
+		return false
+	}
+	var apiError *vulnmap_api.VulnmapApiError
+	if errors.As(err, &apiError) {
+		return apiError.StatusCode() >= http.StatusInternalServerError
+	}
+	var netError net.Error
+	return errors.As(err, &netError)
+}
@@ -0,0 +1,60 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/vulnmap_api"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{name: "network error", err: &net.OpError{Op: "dial", Err: errors.New("timeout")}, transient: true},
+		{name: "server error", err: vulnmap_api.NewVulnmapApiError("bad gateway", 502), transient: true},
+		{name: "client error", err: vulnmap_api.NewVulnmapApiError("bad request", 400), transient: false},
+		{name: "authentication error", err: errors.Wrap(&AuthenticationFailedError{}, "whoami"), transient: false},
+		{name: "empty token", err: ErrEmptyAPIToken, transient: false},
+		{name: "unknown error", err: errors.New("no user data found"), transient: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.transient, IsTransientError(test.err))
+		})
+	}
+}
+
+func Test_toTypedWhoamiError(t *testing.T) {
+	var authenticationFailedError *AuthenticationFailedError
+	unauthorized := errors.Wrap(vulnmap_api.NewVulnmapApiError("invalid API key (status 401)", 401), "whoami")
+	assert.ErrorAs(t, toTypedWhoamiError(unauthorized), &authenticationFailedError)
+
+	var apiError *vulnmap_api.VulnmapApiError
+	assert.ErrorAs(t, toTypedWhoamiError(vulnmap_api.NewVulnmapApiError("request failed (status 503)", 503)), &apiError)
+	assert.Equal(t, 503, apiError.StatusCode())
+
+	untyped := errors.New("request failed (status 401)")
+	assert.Equal(t, untyped, toTypedWhoamiError(untyped), "the status is not parsed from the error text")
+}
@@ -0,0 +1,105 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Baseline contains the fingerprints of accepted issues, e.g. the backlog of a team at the time of migrating to
+// Vulnmap, so that only issues introduced later are shown
+type Baseline struct {
+	// Entries are keyed by the fingerprint of the accepted issue
+	Entries map[string]BaselineEntry `json:"entries"`
+}
+
+type BaselineEntry struct {
+	IssueId    string    `json:"issueId"`
+	RecordedAt time.Time `json:"recordedAt"`
+	// Expires is a date in the format YYYY-MM-DD after which the issue is shown again
+	Expires string `json:"expires,omitempty"`
+}
+
+// NewBaseline accepts the given issues until the expiry date in the format YYYY-MM-DD, or forever if it is empty
+func NewBaseline(issues []Issue, now time.Time, expires string) (*Baseline, error) {
+	if _, err := parseExpiry(expires); err != nil {
+		return nil, errors.Wrapf(err, "invalid expiry date %s", expires)
+	}
+	baseline := &Baseline{Entries: make(map[string]BaselineEntry, len(issues))}
+	for _, issue := range issues {
+		baseline.Entries[issue.Fingerprint()] = BaselineEntry{IssueId: issue.ID, RecordedAt: now, Expires: expires}
+	}
+	return baseline, nil
+}
+
+// LoadBaseline reads the baseline from the given JSON file
+func LoadBaseline(path string) (*Baseline, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read baseline")
+	}
+	var baseline Baseline
+	err = json.Unmarshal(bytes, &baseline)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't parse baseline")
+	}
+	return &baseline, nil
+}
+
+// Save writes the baseline to the given path, creating its directory if needed
+func (b *Baseline) Save(path string) error {
+	bytes, err := json.Marshal(b)
+	if err != nil {
+		return errors.Wrap(err, "couldn't marshal baseline")
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrap(err, "couldn't create baseline directory")
+	}
+	return errors.Wrap(os.WriteFile(path, bytes, 0600), "couldn't write baseline")
+}
+
+// RemoveBaseline deletes the baseline file at the path, if there is one
+func RemoveBaseline(path string) error {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "couldn't remove baseline")
+	}
+	return nil
+}
+
+// Contains returns true if the issue is accepted by the baseline and its entry isn't expired at the given time
+func (b *Baseline) Contains(issue Issue, now time.Time) bool {
+	if b == nil {
+		return false
+	}
+	entry, ok := b.Entries[issue.Fingerprint()]
+	return ok && !isExpired(entry.Expires, now)
+}
+
+// BaselineLoader loads the baselines of the folders and reloads them only when their files changed
+type BaselineLoader struct {
+	*cachedFileLoader[Baseline]
+}
+
+func NewBaselineLoader() *BaselineLoader {
+	return &BaselineLoader{newCachedFileLoader("baseline", LoadBaseline)}
+}
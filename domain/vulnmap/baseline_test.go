@@ -0,0 +1,127 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Baseline_SaveAndLoad(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	accepted := Issue{ID: "VULNMAP-JS-LODASH-1", AffectedFilePath: "package.json"}
+	baseline, err := NewBaseline([]Issue{accepted}, now, "")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "nested", "baseline.json")
+
+	require.NoError(t, baseline.Save(path))
+	loaded, err := LoadBaseline(path)
+
+	require.NoError(t, err)
+	assert.True(t, loaded.Contains(accepted, now))
+	assert.False(t, loaded.Contains(Issue{ID: "VULNMAP-JS-LODASH-2", AffectedFilePath: "package.json"}, now))
+}
+
+func Test_Baseline_ExpiredEntriesDontApply(t *testing.T) {
+	issue := Issue{ID: "VULNMAP-JS-LODASH-1", AffectedFilePath: "package.json"}
+	baseline, err := NewBaseline([]Issue{issue}, time.Now(), "2024-06-01")
+	require.NoError(t, err)
+
+	assert.True(t, baseline.Contains(issue, time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC)))
+	assert.False(t, baseline.Contains(issue, time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)))
+}
+
+func Test_NewBaseline_InvalidExpiry(t *testing.T) {
+	_, err := NewBaseline(nil, time.Now(), "next week")
+
+	assert.Error(t, err)
+}
+
+func Test_BaselineLoader_ReloadsAfterReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	first := Issue{ID: "first"}
+	second := Issue{ID: "second"}
+	loader := NewBaselineLoader()
+	assert.Nil(t, loader.Load(path))
+
+	baseline, _ := NewBaseline([]Issue{first}, time.Now(), "")
+	require.NoError(t, baseline.Save(path))
+	assert.True(t, loader.Load(path).Contains(first, time.Now()))
+
+	baseline, _ = NewBaseline([]Issue{second}, time.Now(), "")
+	require.NoError(t, baseline.Save(path))
+	loader.Reset()
+
+	assert.True(t, loader.Load(path).Contains(second, time.Now()))
+	require.NoError(t, RemoveBaseline(path))
+	assert.Nil(t, loader.Load(path))
+	assert.NoError(t, RemoveBaseline(path))
+}
+
+func Test_BaselineLoader_KeepsTheBaselinesOfMultipleFolders(t *testing.T) {
+	firstPath := filepath.Join(t.TempDir(), "baseline.json")
+	secondPath := filepath.Join(t.TempDir(), "baseline.json")
+	first := Issue{ID: "first"}
+	second := Issue{ID: "second"}
+	baseline, _ := NewBaseline([]Issue{first}, time.Now(), "")
+	require.NoError(t, baseline.Save(firstPath))
+	baseline, _ = NewBaseline([]Issue{second}, time.Now(), "")
+	require.NoError(t, baseline.Save(secondPath))
+	loader := NewBaselineLoader()
+
+	firstBaseline := loader.Load(firstPath)
+	secondBaseline := loader.Load(secondPath)
+
+	assert.Same(t, firstBaseline, loader.Load(firstPath))
+	assert.True(t, firstBaseline.Contains(first, time.Now()))
+	assert.False(t, firstBaseline.Contains(second, time.Now()))
+	assert.True(t, secondBaseline.Contains(second, time.Now()))
+}
+
+func Test_Baseline_ContainsMovedIssues(t *testing.T) {
+	now := time.Now()
+	moved := func(line int) Range {
+		return Range{Start: Position{Line: line}, End: Position{Line: line, Character: 10}}
+	}
+	iacIssue := Issue{
+		ID:               "VULNMAP-CC-TF-1",
+		AffectedFilePath: "main.tf",
+		Range:            moved(3),
+		AdditionalData:   IaCIssueData{Path: []string{"resource", "aws_s3_bucket[logs]", "acl"}},
+	}
+	codeIssue := Issue{
+		ID:               "javascript/Sqli",
+		AffectedFilePath: "app.js",
+		Range:            moved(7),
+		AdditionalData:   CodeIssueData{RuleId: "javascript/Sqli", Message: "Unsanitized input flows into query"},
+	}
+	baseline, err := NewBaseline([]Issue{iacIssue, codeIssue}, now, "")
+	require.NoError(t, err)
+
+	iacIssue.Range = moved(12)
+	codeIssue.Range = moved(20)
+
+	assert.True(t, baseline.Contains(iacIssue, now))
+	assert.True(t, baseline.Contains(codeIssue, now))
+	otherAttribute := iacIssue
+	otherAttribute.AdditionalData = IaCIssueData{Path: []string{"resource", "aws_s3_bucket[data]", "acl"}}
+	assert.False(t, baseline.Contains(otherAttribute, now))
+}
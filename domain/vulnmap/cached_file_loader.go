@@ -0,0 +1,77 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// cachedFileLoader loads files of the folders with a parse function and reloads them only when they changed
+type cachedFileLoader[T any] struct {
+	mutex sync.Mutex
+	// name describes the loaded files in log messages, e.g. "baseline"
+	name  string
+	parse func(path string) (*T, error)
+	// loaded contains the last loaded value by path
+	loaded map[string]loadedFile[T]
+}
+
+type loadedFile[T any] struct {
+	modTime time.Time
+	size    int64
+	value   *T
+}
+
+func newCachedFileLoader[T any](name string, parse func(path string) (*T, error)) *cachedFileLoader[T] {
+	return &cachedFileLoader[T]{name: name, parse: parse, loaded: map[string]loadedFile[T]{}}
+}
+
+// Load returns the parsed file at the path, or nil if there is none or it can't be parsed
+func (l *cachedFileLoader[T]) Load(path string) *T {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn().Err(err).Str("method", "cachedFileLoader.Load").Msgf("couldn't access %s", l.name)
+		}
+		delete(l.loaded, path)
+		return nil
+	}
+	if loaded, ok := l.loaded[path]; ok && info.ModTime().Equal(loaded.modTime) && info.Size() == loaded.size {
+		return loaded.value
+	}
+
+	value, err := l.parse(path)
+	if err != nil {
+		log.Warn().Err(err).Str("method", "cachedFileLoader.Load").Msgf("ignoring %s", l.name)
+	}
+	l.loaded[path] = loadedFile[T]{modTime: info.ModTime(), size: info.Size(), value: value}
+	return value
+}
+
+// Reset forces the next Load to read the files again, e.g. after they were rewritten
+func (l *cachedFileLoader[T]) Reset() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.loaded = map[string]loadedFile[T]{}
+}
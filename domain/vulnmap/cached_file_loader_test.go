@@ -0,0 +1,58 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_cachedFileLoader_ParsesOnlyChangedFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	parses := 0
+	loader := newCachedFileLoader("test file", func(path string) (*string, error) {
+		parses++
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(content) == 0 {
+			return nil, errors.New("empty file")
+		}
+		value := string(content)
+		return &value, nil
+	})
+	require.NoError(t, os.WriteFile(path, []byte("first"), 0600))
+
+	assert.Equal(t, "first", *loader.Load(path))
+	assert.Equal(t, "first", *loader.Load(path))
+	assert.Equal(t, 1, parses)
+
+	require.NoError(t, os.WriteFile(path, []byte("second!"), 0600))
+	assert.Equal(t, "second!", *loader.Load(path))
+	assert.Equal(t, 2, parses)
+
+	require.NoError(t, os.WriteFile(path, []byte{}, 0600))
+	assert.Nil(t, loader.Load(path))
+	assert.Nil(t, loader.Load(path))
+	assert.Equal(t, 3, parses, "files that can't be parsed are not parsed again until they change")
+}
@@ -26,19 +26,59 @@ import (
 )
 
 const (
-	NavigateToRangeCommand       = "vulnmap.navigateToRange"
-	WorkspaceScanCommand         = "vulnmap.workspace.scan"
-	WorkspaceFolderScanCommand   = "vulnmap.workspaceFolder.scan"
-	OpenBrowserCommand           = "vulnmap.openBrowser"
-	LoginCommand                 = "vulnmap.login"
-	CopyAuthLinkCommand          = "vulnmap.copyAuthLink"
-	LogoutCommand                = "vulnmap.logout"
-	TrustWorkspaceFoldersCommand = "vulnmap.trustWorkspaceFolders"
-	OpenLearnLesson              = "vulnmap.openLearnLesson"
-	GetLearnLesson               = "vulnmap.getLearnLesson"
-	GetSettingsSastEnabled       = "vulnmap.getSettingsSastEnabled"
-	GetActiveUserCommand         = "vulnmap.getActiveUser"
-	ReportAnalyticsCommand       = "vulnmap.reportAnalytics"
+	NavigateToRangeCommand        = "vulnmap.navigateToRange"
+	WorkspaceScanCommand          = "vulnmap.workspace.scan"
+	WorkspaceFolderScanCommand    = "vulnmap.workspaceFolder.scan"
+	OpenBrowserCommand            = "vulnmap.openBrowser"
+	LoginCommand                  = "vulnmap.login"
+	CopyAuthLinkCommand           = "vulnmap.copyAuthLink"
+	LogoutCommand                 = "vulnmap.logout"
+	TrustWorkspaceFoldersCommand  = "vulnmap.trustWorkspaceFolders"
+	OpenLearnLesson               = "vulnmap.openLearnLesson"
+	GetLearnLesson                = "vulnmap.getLearnLesson"
+	GetSettingsSastEnabled        = "vulnmap.getSettingsSastEnabled"
+	GetActiveUserCommand          = "vulnmap.getActiveUser"
+	ReportAnalyticsCommand        = "vulnmap.reportAnalytics"
+	SearchIssuesCommand           = "vulnmap.searchIssues"
+	ScanAndGateCommand            = "vulnmap.scanAndGate"
+	GetFolderTrustStatusCommand   = "vulnmap.getFolderTrustStatus"
+	UpgradeAllInFileCommand       = "vulnmap.upgradeAllInFile"
+	DiffScansCommand              = "vulnmap.diffScans"
+	RescanFileCommand             = "vulnmap.rescanFile"
+	ExportCsvCommand              = "vulnmap.exportCsv"
+	ValidateManifestCommand       = "vulnmap.validateManifest"
+	GetLastScanOutputCommand      = "vulnmap.getLastScanOutput"
+	WarmLearnCacheCommand         = "vulnmap.warmLearnCache"
+	IssuesByCWECommand            = "vulnmap.issuesByCwe"
+	ResendAnalyticsCommand        = "vulnmap.resendAnalytics"
+	RecordBaselineCommand         = "vulnmap.recordBaseline"
+	ClearBaselineCommand          = "vulnmap.clearBaseline"
+	TriggerScanCommand            = "vulnmap.triggerScan"
+	GetIssueHoverCommand          = "vulnmap.getIssueHover"
+	PauseScanningCommand          = "vulnmap.pauseScanning"
+	ResumeScanningCommand         = "vulnmap.resumeScanning"
+	GetSupportedEcosystemsCommand = "vulnmap.getSupportedEcosystems"
+	GetAuthStateCommand           = "vulnmap.getAuthState"
+	GetIssueDeltaCommand          = "vulnmap.getIssueDelta"
+	ExportJunitCommand            = "vulnmap.exportJunit"
+	IssuesByEcosystemCommand      = "vulnmap.issuesByEcosystem"
+	TrustFolderCommand            = "vulnmap.trustFolder"
+	SetAuthMethodCommand          = "vulnmap.setAuthMethod"
+	CheckTokenPermissionsCommand  = "vulnmap.checkTokenPermissions"
+	OpenLogsCommand               = "vulnmap.openLogs"
+	AcceptIssueCommand            = "vulnmap.acceptIssue"
+	GetAcceptedIssuesCommand      = "vulnmap.getAcceptedIssues"
+	GetFixAdviceCommand           = "vulnmap.getFixAdvice"
+	GetScanWarningsCommand        = "vulnmap.getScanWarnings"
+	ResetTrustPromptCommand       = "vulnmap.resetTrustPrompt"
+	GetDependencyTreeCommand      = "vulnmap.getDependencyTree"
+	ApplyAllFixesCommand          = "vulnmap.applyAllFixes"
+	PreviewFilterCommand          = "vulnmap.previewFilter"
+	ExportAttestationCommand      = "vulnmap.exportAttestation"
+	FindByCVECommand              = "vulnmap.findByCve"
+	TagIssueCommand               = "vulnmap.tagIssue"
+	UntagIssueCommand             = "vulnmap.untagIssue"
+	GetIssueTagsCommand           = "vulnmap.getIssueTags"
 
 	// Vulnmap Code specific commands
 	CodeFixCommand        = "vulnmap.code.fix"
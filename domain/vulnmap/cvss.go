@@ -0,0 +1,30 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+// MeetsMinCvssScore returns whether the CVSS score of the issue is at least minScore. Issues without a CVSS score,
+// e.g. licence issues or issues of other products, always meet it. A minScore of 0 disables the threshold.
+func (i Issue) MeetsMinCvssScore(minScore float64) bool {
+	if minScore <= 0 {
+		return true
+	}
+	data, ok := i.AdditionalData.(OssIssueData)
+	if !ok || data.CvssScore <= 0 {
+		return true
+	}
+	return data.CvssScore >= minScore
+}
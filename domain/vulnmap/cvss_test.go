@@ -0,0 +1,45 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssue_MeetsMinCvssScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     any
+		minScore float64
+		expected bool
+	}{
+		{"score above threshold", OssIssueData{CvssScore: 7.5}, 7, true},
+		{"score equal to threshold", OssIssueData{CvssScore: 7.5}, 7.5, true},
+		{"score below threshold", OssIssueData{CvssScore: 5.3}, 7, false},
+		{"threshold disabled", OssIssueData{CvssScore: 5.3}, 0, true},
+		{"unknown score", OssIssueData{}, 9, true},
+		{"issue of other product", CodeIssueData{}, 9, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issue := Issue{AdditionalData: test.data}
+			assert.Equal(t, test.expected, issue.MeetsMinCvssScore(test.minScore))
+		})
+	}
+}
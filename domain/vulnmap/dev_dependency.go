@@ -0,0 +1,24 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+// IsDevDependency returns whether the issue affects a package that is only introduced by development dependencies
+// of the project. Issues of other products are never dev dependency issues.
+func (i Issue) IsDevDependency() bool {
+	data, ok := i.AdditionalData.(OssIssueData)
+	return ok && data.IsDevDependency
+}
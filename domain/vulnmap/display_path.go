@@ -0,0 +1,50 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+// DisplayPath returns the path as it is shown to users in messages and reports. If relative file path display is
+// enabled, paths inside the folder root are shown relative to it, so that reports don't reveal the local directory
+// structure. Cache keys and URIs must keep using the absolute path.
+func DisplayPath(path string, folderRoot string) string {
+	if folderRoot == "" || !config.CurrentConfig().IsRelativeFilePathDisplay() {
+		return path
+	}
+	relativePath, err := filepath.Rel(folderRoot, path)
+	if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(relativePath)
+}
+
+// DisplayPathsIn returns the text with the absolute paths and file URIs of files inside the folder root replaced by
+// their display paths, e.g. for the data flow links in the formatted message of an issue that is exported
+func DisplayPathsIn(text string, folderRoot string) string {
+	if folderRoot == "" || !config.CurrentConfig().IsRelativeFilePathDisplay() {
+		return text
+	}
+	folderUri := strings.TrimSuffix(string(uri.PathToUri(folderRoot)), "/") + "/"
+	text = strings.ReplaceAll(text, folderUri, "")
+	return strings.ReplaceAll(text, filepath.Clean(folderRoot)+string(filepath.Separator), "")
+}
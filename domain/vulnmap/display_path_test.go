@@ -0,0 +1,55 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+func Test_DisplayPath(t *testing.T) {
+	c := testutil.UnitTest(t)
+	root := filepath.Join(t.TempDir(), "project")
+	path := filepath.Join(root, "src", "package.json")
+	outside := filepath.Join(filepath.Dir(root), "other", "package.json")
+
+	assert.Equal(t, path, DisplayPath(path, root))
+
+	c.SetRelativeFilePathDisplay(true)
+
+	assert.Equal(t, "src/package.json", DisplayPath(path, root))
+	assert.Equal(t, outside, DisplayPath(outside, root))
+	assert.Equal(t, path, DisplayPath(path, ""))
+}
+
+func Test_DisplayPathsIn(t *testing.T) {
+	c := testutil.UnitTest(t)
+	root := filepath.Join(t.TempDir(), "project")
+	path := filepath.Join(root, "src", "app.js")
+	text := "1. [src/app.js:5](" + string(uri.PathToUri(path)) + ") in " + path
+
+	assert.Equal(t, text, DisplayPathsIn(text, root))
+
+	c.SetRelativeFilePathDisplay(true)
+
+	assert.Equal(t, "1. [src/app.js:5](src/app.js) in src/app.js", DisplayPathsIn(text, root))
+}
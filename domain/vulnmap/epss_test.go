@@ -0,0 +1,46 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssue_MeetsMinEpssScore(t *testing.T) {
+	score := 0.25
+	tests := []struct {
+		name     string
+		data     any
+		minScore float64
+		expected bool
+	}{
+		{"score above threshold", OssIssueData{EpssScore: &score}, 0.2, true},
+		{"score equal to threshold", OssIssueData{EpssScore: &score}, 0.25, true},
+		{"score below threshold", OssIssueData{EpssScore: &score}, 0.2500001, false},
+		{"threshold disabled", OssIssueData{EpssScore: &score}, 0, true},
+		{"unknown score", OssIssueData{}, 0.9, true},
+		{"issue of other product", CodeIssueData{}, 0.9, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issue := Issue{AdditionalData: test.data}
+			assert.Equal(t, test.expected, issue.MeetsMinEpssScore(test.minScore))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UpgradeRisk classifies an upgrade by the highest semantic version segment it bumps
+type UpgradeRisk string
+
+const (
+	UpgradeRiskPatch UpgradeRisk = "patch"
+	UpgradeRiskMinor UpgradeRisk = "minor"
+	UpgradeRiskMajor UpgradeRisk = "major"
+	// UpgradeRiskUnknown is used if there is no upgrade or its versions aren't semantic versions
+	UpgradeRiskUnknown UpgradeRisk = "unknown"
+)
+
+// FixAdvice is the remediation guidance for an issue
+type FixAdvice struct {
+	Fixable bool `json:"fixable"`
+	// Advice is the remediation guidance as text, it explains why if no fix is available
+	Advice      string `json:"advice"`
+	PackageName string `json:"packageName,omitempty"`
+	Version     string `json:"version,omitempty"`
+	// UpgradeTo is the package reference the direct dependency needs to be upgraded to, e.g. lodash@4.17.21
+	UpgradeTo   string      `json:"upgradeTo,omitempty"`
+	UpgradeRisk UpgradeRisk `json:"upgradeRisk,omitempty"`
+	FixedIn     []string    `json:"fixedIn,omitempty"`
+	IsPatchable bool        `json:"isPatchable"`
+}
+
+var upgradeRiskAdvice = map[UpgradeRisk]string{
+	UpgradeRiskPatch:   "This is a patch-level upgrade, which shouldn't contain breaking changes.",
+	UpgradeRiskMinor:   "This is a minor version upgrade, which should be backwards compatible.",
+	UpgradeRiskMajor:   "This is a major version upgrade, which may contain breaking changes. Review the changelog before upgrading.",
+	UpgradeRiskUnknown: "The impact of the upgrade couldn't be determined, review the changelog before upgrading.",
+}
+
+// FixAdvice synthesizes remediation guidance from the fix information of the issue. Only Open Source issues carry
+// enough fix information for detailed advice.
+func (i Issue) FixAdvice() FixAdvice {
+	switch data := i.AdditionalData.(type) {
+	case OssIssueData:
+		return data.fixAdvice()
+	case CodeIssueData:
+		if data.IsAutofixable {
+			return FixAdvice{
+				Fixable: true,
+				Advice:  "This issue can be fixed automatically with the \"⚡ Fix this issue\" code action.",
+			}
+		}
+		return FixAdvice{Advice: "No automatic fix is available for this issue, follow the remediation guidance in its description."}
+	default:
+		return FixAdvice{Advice: "No fix advice is available for this issue, follow the remediation guidance in its description."}
+	}
+}
+
+func (d OssIssueData) fixAdvice() FixAdvice {
+	advice := FixAdvice{
+		PackageName: d.PackageName,
+		Version:     d.Version,
+		FixedIn:     d.FixedIn,
+		IsPatchable: d.IsPatchable,
+	}
+	vulnerablePackage := d.PackageName + "@" + d.Version
+	var sentences []string
+
+	if upgrade, ok := d.directUpgrade(); ok && d.IsUpgradable && len(d.From) > 1 {
+		advice.Fixable = true
+		advice.UpgradeTo = upgrade
+		advice.UpgradeRisk = d.upgradeRisk()
+		if d.From[1] == vulnerablePackage {
+			sentences = append(sentences, fmt.Sprintf("Upgrade %s to %s.", d.From[1], upgrade))
+		} else {
+			sentences = append(sentences,
+				fmt.Sprintf("Upgrade %s to %s to fix the vulnerable %s.", d.From[1], upgrade, vulnerablePackage))
+		}
+		sentences = append(sentences, upgradeRiskAdvice[advice.UpgradeRisk])
+	}
+
+	if d.IsPatchable {
+		advice.Fixable = true
+		sentences = append(sentences, fmt.Sprintf("A patch is available for %s.", vulnerablePackage))
+	}
+
+	if !advice.Fixable {
+		if len(d.FixedIn) > 0 {
+			sentences = append(sentences, fmt.Sprintf(
+				"The vulnerability is fixed in %s %s, but no upgrade of your direct dependencies includes the fix yet.",
+				d.PackageName, strings.Join(d.FixedIn, ", ")))
+		} else {
+			sentences = append(sentences, fmt.Sprintf("No fix is available for %s yet.", vulnerablePackage))
+		}
+	}
+	advice.Advice = strings.Join(sentences, " ")
+	return advice
+}
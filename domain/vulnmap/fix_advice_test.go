@@ -0,0 +1,112 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssue_FixAdvice_PatchLevelUpgrade(t *testing.T) {
+	issue := Issue{AdditionalData: OssIssueData{
+		PackageName:  "lodash",
+		Version:      "4.17.4",
+		From:         []string{"goof@1.0.1", "lodash@4.17.4"},
+		UpgradePath:  []any{false, "lodash@4.17.21"},
+		IsUpgradable: true,
+		FixedIn:      []string{"4.17.21"},
+	}}
+
+	advice := issue.FixAdvice()
+
+	assert.True(t, advice.Fixable)
+	assert.Equal(t, "lodash@4.17.21", advice.UpgradeTo)
+	assert.Equal(t, UpgradeRiskPatch, advice.UpgradeRisk)
+	assert.Equal(t, []string{"4.17.21"}, advice.FixedIn)
+	assert.Equal(t,
+		"Upgrade lodash@4.17.4 to lodash@4.17.21. This is a patch-level upgrade, which shouldn't contain breaking changes.",
+		advice.Advice)
+}
+
+func TestIssue_FixAdvice_MajorVersionOnlyFixOfTransitiveDependency(t *testing.T) {
+	issue := Issue{AdditionalData: OssIssueData{
+		PackageName:  "qs",
+		Version:      "2.3.3",
+		From:         []string{"goof@1.0.1", "express@3.21.2", "qs@2.3.3"},
+		UpgradePath:  []any{false, "express@4.17.3", "qs@6.9.7"},
+		IsUpgradable: true,
+		FixedIn:      []string{"6.9.7"},
+	}}
+
+	advice := issue.FixAdvice()
+
+	assert.True(t, advice.Fixable)
+	assert.Equal(t, "express@4.17.3", advice.UpgradeTo)
+	assert.Equal(t, UpgradeRiskMajor, advice.UpgradeRisk)
+	assert.Contains(t, advice.Advice, "Upgrade express@3.21.2 to express@4.17.3 to fix the vulnerable qs@2.3.3.")
+	assert.Contains(t, advice.Advice, "major version upgrade, which may contain breaking changes")
+}
+
+func TestIssue_FixAdvice_MinorUpgrade(t *testing.T) {
+	issue := Issue{AdditionalData: OssIssueData{
+		PackageName:  "lodash",
+		Version:      "4.16.0",
+		From:         []string{"goof@1.0.1", "lodash@4.16.0"},
+		UpgradePath:  []any{false, "lodash@4.17.21"},
+		IsUpgradable: true,
+	}}
+
+	assert.Equal(t, UpgradeRiskMinor, issue.FixAdvice().UpgradeRisk)
+}
+
+func TestIssue_FixAdvice_Unfixable(t *testing.T) {
+	t.Run("fixed in a version no upgrade reaches", func(t *testing.T) {
+		issue := Issue{AdditionalData: OssIssueData{
+			PackageName: "minimist",
+			Version:     "0.0.8",
+			From:        []string{"goof@1.0.1", "mkdirp@0.5.1", "minimist@0.0.8"},
+			UpgradePath: []any{},
+			FixedIn:     []string{"0.2.1", "1.2.3"},
+		}}
+
+		advice := issue.FixAdvice()
+
+		assert.False(t, advice.Fixable)
+		assert.Empty(t, advice.UpgradeTo)
+		assert.Equal(t, "The vulnerability is fixed in minimist 0.2.1, 1.2.3, but no upgrade of your direct "+
+			"dependencies includes the fix yet.", advice.Advice)
+	})
+
+	t.Run("no fixed version", func(t *testing.T) {
+		issue := Issue{AdditionalData: OssIssueData{PackageName: "marked", Version: "0.3.5"}}
+
+		advice := issue.FixAdvice()
+
+		assert.False(t, advice.Fixable)
+		assert.Equal(t, "No fix is available for marked@0.3.5 yet.", advice.Advice)
+	})
+}
+
+func TestIssue_FixAdvice_Patchable(t *testing.T) {
+	issue := Issue{AdditionalData: OssIssueData{PackageName: "uglify-js", Version: "2.4.24", IsPatchable: true}}
+
+	advice := issue.FixAdvice()
+
+	assert.True(t, advice.Fixable)
+	assert.Equal(t, "A patch is available for uglify-js@2.4.24.", advice.Advice)
+}
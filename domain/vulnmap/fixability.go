@@ -0,0 +1,93 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// IsFixable returns whether the issue can be fixed right away. Open Source issues are fixable if they have an upgrade
+// or patch path, Code issues if they can be fixed automatically. If includeMajorUpgrades is false, Open Source issues
+// whose upgrade requires a new major version of the direct dependency are not considered fixable, unless they can be
+// patched. Issues of other products carry no fix information and are always considered fixable, so that they are not
+// hidden.
+func (i Issue) IsFixable(includeMajorUpgrades bool) bool {
+	switch data := i.AdditionalData.(type) {
+	case OssIssueData:
+		if data.IsPatchable {
+			return true
+		}
+		if !data.IsUpgradable || len(data.UpgradePath) < 2 {
+			return false
+		}
+		return includeMajorUpgrades || !data.requiresMajorUpgrade()
+	case CodeIssueData:
+		return data.IsAutofixable
+	default:
+		return true
+	}
+}
+
+// requiresMajorUpgrade returns true if the upgrade path bumps the major version of the direct dependency
+func (d OssIssueData) requiresMajorUpgrade() bool {
+	return d.upgradeRisk() == UpgradeRiskMajor
+}
+
+// directUpgrade returns the package reference the direct dependency needs to be upgraded to, e.g. lodash@4.17.21
+func (d OssIssueData) directUpgrade() (string, bool) {
+	if len(d.UpgradePath) < 2 {
+		return "", false
+	}
+	upgrade, ok := d.UpgradePath[1].(string)
+	return upgrade, ok && upgrade != ""
+}
+
+// upgradeRisk classifies the upgrade of the direct dependency by the highest version segment it bumps
+func (d OssIssueData) upgradeRisk() UpgradeRisk {
+	upgrade, ok := d.directUpgrade()
+	if !ok || len(d.From) < 2 {
+		return UpgradeRiskUnknown
+	}
+	current, err := version.NewVersion(packageVersion(d.From[1]))
+	if err != nil {
+		return UpgradeRiskUnknown
+	}
+	target, err := version.NewVersion(packageVersion(upgrade))
+	if err != nil {
+		return UpgradeRiskUnknown
+	}
+	currentSegments, targetSegments := current.Segments(), target.Segments()
+	switch {
+	case targetSegments[0] > currentSegments[0]:
+		return UpgradeRiskMajor
+	case targetSegments[1] > currentSegments[1]:
+		return UpgradeRiskMinor
+	default:
+		return UpgradeRiskPatch
+	}
+}
+
+// packageVersion returns the version of a package reference, e.g. 4.17.4 for lodash@4.17.4 or @scope/pkg@1.0.0
+func packageVersion(packageReference string) string {
+	separator := strings.LastIndex(packageReference, "@")
+	if separator <= 0 {
+		return ""
+	}
+	return packageReference[separator+1:]
+}
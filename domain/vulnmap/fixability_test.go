@@ -0,0 +1,49 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssue_IsFixable(t *testing.T) {
+	tests := []struct {
+		name                 string
+		data                 any
+		includeMajorUpgrades bool
+		expected             bool
+	}{
+		{"upgradable", OssIssueData{IsUpgradable: true, From: []string{"p@1", "a@1.0.0"}, UpgradePath: []any{false, "a@1.2.0"}}, false, true},
+		{"upgradable without path", OssIssueData{IsUpgradable: true}, true, false},
+		{"patchable", OssIssueData{IsPatchable: true}, false, true},
+		{"no fix", OssIssueData{}, true, false},
+		{"major upgrade included", OssIssueData{IsUpgradable: true, From: []string{"p@1", "a@1.0.0"}, UpgradePath: []any{false, "a@2.0.0"}}, true, true},
+		{"major upgrade excluded", OssIssueData{IsUpgradable: true, From: []string{"p@1", "a@1.0.0"}, UpgradePath: []any{false, "a@2.0.0"}}, false, false},
+		{"scoped package major upgrade", OssIssueData{IsUpgradable: true, From: []string{"p@1", "@s/a@1.0.0"}, UpgradePath: []any{false, "@s/a@2.0.0"}}, false, false},
+		{"autofixable code issue", CodeIssueData{IsAutofixable: true}, false, true},
+		{"code issue without autofix", CodeIssueData{}, true, false},
+		{"issue without fix information", IaCIssueData{}, false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issue := Issue{AdditionalData: test.data}
+			assert.Equal(t, test.expected, issue.IsFixable(test.includeMajorUpgrades))
+		})
+	}
+}
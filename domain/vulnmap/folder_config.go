@@ -0,0 +1,120 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// FolderConfigFileName is the name of the file at the root of a workspace folder that contains its FolderConfig
+const FolderConfigFileName = ".vulnmap-ls"
+
+type folderConfigKey struct{}
+
+// FolderConfig shadows global settings during the scans of a single workspace folder.
+// Unset fields fall back to the global configuration.
+type FolderConfig struct {
+	// EnabledProducts enables or disables products by codename (oss, code, iac)
+	EnabledProducts map[string]bool `json:"enabledProducts,omitempty"`
+	// ExcludePatterns are gitignore-style patterns, relative to the folder, of files whose issues are not reported
+	ExcludePatterns []string `json:"excludePatterns,omitempty"`
+	// AdditionalCliArgs are appended to the CLI commands of the folder scans
+	AdditionalCliArgs []string `json:"additionalCliArgs,omitempty"`
+	// Organization overrides the global organization for the CLI commands of the folder scans
+	Organization string `json:"organization,omitempty"`
+	// Env are environment variables set for the CLI commands of the folder scans, e.g. the token of a private
+	// registry. They take precedence over the global environment and their values are redacted in logs.
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// LoadFolderConfig reads the FolderConfig from the root of the folder. It returns nil if the folder has no config file.
+func LoadFolderConfig(folderPath string) (*FolderConfig, error) {
+	bytes, err := os.ReadFile(filepath.Join(folderPath, FolderConfigFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read folder config")
+	}
+	var folderConfig FolderConfig
+	err = json.Unmarshal(bytes, &folderConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't parse folder config")
+	}
+	return &folderConfig, nil
+}
+
+// IsProductEnabled returns whether the product is enabled for the folder, falling back to the global setting
+func (fc *FolderConfig) IsProductEnabled(p product.Product, globallyEnabled bool) bool {
+	if fc == nil {
+		return globallyEnabled
+	}
+	enabled, ok := fc.EnabledProducts[product.ToProductCodename(p)]
+	if !ok {
+		return globallyEnabled
+	}
+	return enabled
+}
+
+// ContextWithFolderConfig returns a context that carries the FolderConfig to the product scanners
+func ContextWithFolderConfig(ctx context.Context, folderConfig *FolderConfig) context.Context {
+	if folderConfig == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, folderConfigKey{}, folderConfig)
+}
+
+// FolderConfigFromContext returns the FolderConfig of the scanned folder, or nil if there is none
+func FolderConfigFromContext(ctx context.Context) *FolderConfig {
+	folderConfig, _ := ctx.Value(folderConfigKey{}).(*FolderConfig)
+	return folderConfig
+}
+
+// AdditionalCliArgsFromContext returns the additional CLI arguments of the scanned folder
+func AdditionalCliArgsFromContext(ctx context.Context) []string {
+	folderConfig := FolderConfigFromContext(ctx)
+	if folderConfig == nil {
+		return nil
+	}
+	return folderConfig.AdditionalCliArgs
+}
+
+// OrganizationFromContext returns the organization override of the scanned folder, or an empty string if the
+// global organization applies
+func OrganizationFromContext(ctx context.Context) string {
+	folderConfig := FolderConfigFromContext(ctx)
+	if folderConfig == nil {
+		return ""
+	}
+	return folderConfig.Organization
+}
+
+// EnvFromContext returns the additional environment variables of the scanned folder
+func EnvFromContext(ctx context.Context) map[string]string {
+	folderConfig := FolderConfigFromContext(ctx)
+	if folderConfig == nil {
+		return nil
+	}
+	return folderConfig.Env
+}
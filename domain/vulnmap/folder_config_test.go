@@ -0,0 +1,92 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+func TestLoadFolderConfig(t *testing.T) {
+	t.Run("returns nil without config file", func(t *testing.T) {
+		folderConfig, err := LoadFolderConfig(t.TempDir())
+
+		assert.NoError(t, err)
+		assert.Nil(t, folderConfig)
+	})
+
+	t.Run("reads config file", func(t *testing.T) {
+		dir := t.TempDir()
+		content := `{"enabledProducts": {"iac": false}, "excludePatterns": ["vendor/"], "additionalCliArgs": ["--dev"]}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, FolderConfigFileName), []byte(content), 0600))
+
+		folderConfig, err := LoadFolderConfig(dir)
+
+		require.NoError(t, err)
+		assert.False(t, folderConfig.IsProductEnabled(product.ProductInfrastructureAsCode, true))
+		assert.True(t, folderConfig.IsProductEnabled(product.ProductOpenSource, true))
+		assert.Equal(t, []string{"vendor/"}, folderConfig.ExcludePatterns)
+		assert.Equal(t, []string{"--dev"}, AdditionalCliArgsFromContext(ContextWithFolderConfig(context.Background(), folderConfig)))
+	})
+
+	t.Run("returns error for invalid config file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, FolderConfigFileName), []byte("{"), 0600))
+
+		_, err := LoadFolderConfig(dir)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestOrganizationFromContext(t *testing.T) {
+	assert.Empty(t, OrganizationFromContext(context.Background()))
+	assert.Empty(t, OrganizationFromContext(ContextWithFolderConfig(context.Background(), &FolderConfig{})))
+
+	ctx := ContextWithFolderConfig(context.Background(), &FolderConfig{Organization: "team-a"})
+	assert.Equal(t, "team-a", OrganizationFromContext(ctx))
+}
+
+func TestEnvFromContext(t *testing.T) {
+	assert.Nil(t, EnvFromContext(context.Background()))
+
+	dir := t.TempDir()
+	content := `{"env": {"NPM_TOKEN": "secret"}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FolderConfigFileName), []byte(content), 0600))
+	folderConfig, err := LoadFolderConfig(dir)
+	require.NoError(t, err)
+
+	ctx := ContextWithFolderConfig(context.Background(), folderConfig)
+	assert.Equal(t, map[string]string{"NPM_TOKEN": "secret"}, EnvFromContext(ctx))
+}
+
+func TestFolderConfig_IsProductEnabled_FallsBackToGlobalSetting(t *testing.T) {
+	var folderConfig *FolderConfig
+	assert.True(t, folderConfig.IsProductEnabled(product.ProductCode, true))
+	assert.False(t, folderConfig.IsProductEnabled(product.ProductCode, false))
+
+	folderConfig = &FolderConfig{EnabledProducts: map[string]bool{"code": true}}
+	assert.True(t, folderConfig.IsProductEnabled(product.ProductCode, false))
+	assert.False(t, folderConfig.IsProductEnabled(product.ProductOpenSource, false))
+}
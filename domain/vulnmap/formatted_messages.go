@@ -0,0 +1,43 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+)
+
+// RenderFormattedMessages renders the formatted message of an issue in the hover format and in the formats of the
+// other rendering contexts that differ from it, so that e.g. hovers can show markdown while exports contain HTML.
+func RenderFormattedMessages(c *config.Config, render func(format string) string) (string, map[string]string) {
+	hoverFormat := c.FormatFor(config.RenderContextHover)
+	formattedMessage := render(hoverFormat)
+	var formattedMessages map[string]string
+	for _, renderContext := range config.RenderContexts() {
+		format := c.FormatFor(renderContext)
+		if format == hoverFormat {
+			continue
+		}
+		if _, rendered := formattedMessages[format]; rendered {
+			continue
+		}
+		if formattedMessages == nil {
+			formattedMessages = map[string]string{}
+		}
+		formattedMessages[format] = render(format)
+	}
+	return formattedMessage, formattedMessages
+}
@@ -0,0 +1,122 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	sglsp "github.com/sourcegraph/go-lsp"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	noti "github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/clock"
+)
+
+// IdleLogout logs the user out once no scans were run and no commands were executed for the configured idle
+// logout timeout, e.g. on shared machines. The timeout doesn't expire while a scan is running.
+// All methods can be called on a nil IdleLogout, which doesn't track activity.
+type IdleLogout struct {
+	authService AuthenticationService
+	notifier    noti.Notifier
+	mutex       sync.Mutex
+	clock       clock.Clock
+	timer       clock.Timer
+	// generation identifies the current timer, so that a timer that fires while being reset doesn't log out
+	generation  int
+	activeScans int
+}
+
+func NewIdleLogout(authService AuthenticationService, notifier noti.Notifier) *IdleLogout {
+	return &IdleLogout{authService: authService, notifier: notifier, clock: clock.New()}
+}
+
+// SetClock sets the clock that measures the idle time, e.g. a fake clock in tests
+func (i *IdleLogout) SetClock(clock clock.Clock) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.clock = clock
+}
+
+// Activity restarts the idle logout timeout, e.g. after a command was executed or the timeout was changed
+func (i *IdleLogout) Activity() {
+	if i == nil {
+		return
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.resetTimer()
+}
+
+// ScanStarted pauses the idle logout timeout until the scan is finished
+func (i *IdleLogout) ScanStarted() {
+	if i == nil {
+		return
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.activeScans++
+	i.resetTimer()
+}
+
+// ScanFinished restarts the idle logout timeout once no scan is running anymore
+func (i *IdleLogout) ScanFinished() {
+	if i == nil {
+		return
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if i.activeScans > 0 {
+		i.activeScans--
+	}
+	i.resetTimer()
+}
+
+// resetTimer stops the running timer and starts a new one if enabled and no scan is running. Must be called with
+// the mutex held.
+func (i *IdleLogout) resetTimer() {
+	if i.timer != nil {
+		i.timer.Stop()
+		i.timer = nil
+	}
+	i.generation++
+	timeout := config.CurrentConfig().IdleLogoutTimeout()
+	if timeout <= 0 || i.activeScans > 0 {
+		return
+	}
+	generation := i.generation
+	i.timer = i.clock.AfterFunc(timeout, func() { i.expire(generation, timeout) })
+}
+
+func (i *IdleLogout) expire(generation int, timeout time.Duration) {
+	i.mutex.Lock()
+	expired := generation == i.generation && i.activeScans == 0
+	if expired {
+		i.timer = nil
+	}
+	i.mutex.Unlock()
+	if !expired || !config.CurrentConfig().NonEmptyToken() {
+		return
+	}
+
+	log.Info().Str("method", "IdleLogout.expire").Msgf("no activity for %s, logging out", timeout)
+	i.authService.Logout(context.Background())
+	i.notifier.SendShowMessage(sglsp.Info, fmt.Sprintf("You were logged out of Vulnmap after %s of inactivity.", timeout))
+}
@@ -0,0 +1,99 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/ux"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/clock"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func setUpIdleLogout(t *testing.T, timeout time.Duration) (*vulnmap.IdleLogout, *clock.FakeClock, *notification.MockNotifier, *config.Config) {
+	t.Helper()
+	c := testutil.UnitTest(t)
+	c.SetToken("token")
+	c.SetIdleLogoutTimeout(timeout)
+	notifier := notification.NewMockNotifier()
+	authService := vulnmap.NewAuthenticationService(
+		vulnmap.NewFakeCliAuthenticationProvider(),
+		ux.NewTestAnalytics(),
+		error_reporting.NewTestErrorReporter(),
+		notifier,
+	)
+	idleLogout := vulnmap.NewIdleLogout(authService, notifier)
+	fakeClock := clock.NewFakeClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	idleLogout.SetClock(fakeClock)
+	return idleLogout, fakeClock, notifier, c
+}
+
+func Test_IdleLogout_ExpiryClearsToken(t *testing.T) {
+	idleLogout, fakeClock, notifier, c := setUpIdleLogout(t, 5*time.Minute)
+
+	idleLogout.Activity()
+	fakeClock.Advance(5 * time.Minute)
+
+	assert.Equal(t, 1, notifier.SendShowMessageCount())
+	assert.Empty(t, c.Token())
+	assert.Contains(t, notifier.SentMessages(), lsp.AuthenticationParams{Token: ""})
+}
+
+func Test_IdleLogout_ActivityResetsTimer(t *testing.T) {
+	idleLogout, fakeClock, _, c := setUpIdleLogout(t, 5*time.Minute)
+
+	idleLogout.Activity()
+	for i := 0; i < 3; i++ {
+		fakeClock.Advance(3 * time.Minute)
+		idleLogout.Activity()
+	}
+
+	require.Equal(t, "token", c.Token(), "activity within the timeout must prevent the logout")
+	fakeClock.Advance(5 * time.Minute)
+	assert.Empty(t, c.Token())
+}
+
+func Test_IdleLogout_DoesNotExpireDuringScan(t *testing.T) {
+	idleLogout, fakeClock, _, c := setUpIdleLogout(t, 5*time.Minute)
+
+	idleLogout.Activity()
+	idleLogout.ScanStarted()
+	fakeClock.Advance(time.Hour)
+	require.Equal(t, "token", c.Token(), "the timeout must not expire while a scan is running")
+	idleLogout.ScanFinished()
+
+	fakeClock.Advance(5 * time.Minute)
+	assert.Empty(t, c.Token())
+}
+
+func Test_IdleLogout_ZeroTimeoutDisablesLogout(t *testing.T) {
+	idleLogout, fakeClock, notifier, _ := setUpIdleLogout(t, 0)
+
+	idleLogout.Activity()
+	fakeClock.Advance(24 * time.Hour)
+
+	assert.Zero(t, notifier.SendShowMessageCount())
+}
@@ -18,10 +18,19 @@ package vulnmap
 
 import "fmt"
 
+// InlineValueKind tags the kind of annotation an inline value shows, so that users can choose the kinds they see
+type InlineValueKind string
+
+const (
+	// InlineValueKindVulnerabilityCount summarizes the vulnerabilities of a dependency by severity
+	InlineValueKindVulnerabilityCount InlineValueKind = "vulnerabilityCount"
+)
+
 type InlineValue interface {
 	Path() string
 	Range() Range
 	Text() string
+	Kind() InlineValueKind
 	fmt.Stringer
 }
 
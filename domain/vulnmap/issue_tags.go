@@ -0,0 +1,119 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IssueTags contains the labels users attached to issues for their own triage, e.g. "needs-review" or "jira-1234"
+type IssueTags struct {
+	// Entries are the sorted tags keyed by the fingerprint of the tagged issue
+	Entries map[string][]string `json:"entries"`
+}
+
+func NewIssueTags() *IssueTags {
+	return &IssueTags{Entries: make(map[string][]string)}
+}
+
+// LoadIssueTags reads the issue tags from the given JSON file
+func LoadIssueTags(path string) (*IssueTags, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read issue tags")
+	}
+	var issueTags IssueTags
+	err = json.Unmarshal(bytes, &issueTags)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't parse issue tags")
+	}
+	if issueTags.Entries == nil {
+		issueTags.Entries = make(map[string][]string)
+	}
+	return &issueTags, nil
+}
+
+// Save writes the issue tags to the given path, creating its directory if needed
+func (t *IssueTags) Save(path string) error {
+	bytes, err := json.Marshal(t)
+	if err != nil {
+		return errors.Wrap(err, "couldn't marshal issue tags")
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrap(err, "couldn't create issue tags directory")
+	}
+	return errors.Wrap(os.WriteFile(path, bytes, 0600), "couldn't write issue tags")
+}
+
+// Tag attaches the tag to the issue with the fingerprint and returns its tags. Surrounding whitespace is removed and
+// tagging an issue again with the same tag has no effect.
+func (t *IssueTags) Tag(fingerprint string, tag string) ([]string, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return nil, errors.New("tag must not be empty")
+	}
+	tags := t.Entries[fingerprint]
+	for _, existing := range tags {
+		if existing == tag {
+			return t.Tags(fingerprint), nil
+		}
+	}
+	tags = append(tags, tag)
+	sort.Strings(tags)
+	t.Entries[fingerprint] = tags
+	return t.Tags(fingerprint), nil
+}
+
+// Untag removes the tag from the issue with the fingerprint and returns its remaining tags
+func (t *IssueTags) Untag(fingerprint string, tag string) []string {
+	tag = strings.TrimSpace(tag)
+	remaining := make([]string, 0, len(t.Entries[fingerprint]))
+	for _, existing := range t.Entries[fingerprint] {
+		if existing != tag {
+			remaining = append(remaining, existing)
+		}
+	}
+	if len(remaining) == 0 {
+		delete(t.Entries, fingerprint)
+	} else {
+		t.Entries[fingerprint] = remaining
+	}
+	return remaining
+}
+
+// Tags returns a copy of the tags of the issue with the fingerprint, or nil if it isn't tagged
+func (t *IssueTags) Tags(fingerprint string) []string {
+	if t == nil || len(t.Entries[fingerprint]) == 0 {
+		return nil
+	}
+	return append([]string{}, t.Entries[fingerprint]...)
+}
+
+// IssueTagsLoader loads the issue tags of the folders and reloads them only when their files changed
+type IssueTagsLoader struct {
+	*cachedFileLoader[IssueTags]
+}
+
+func NewIssueTagsLoader() *IssueTagsLoader {
+	return &IssueTagsLoader{newCachedFileLoader("issue tags", LoadIssueTags)}
+}
@@ -0,0 +1,65 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IssueTags_TagAndUntag(t *testing.T) {
+	issueTags := NewIssueTags()
+
+	_, err := issueTags.Tag("fingerprint", " needs-review ")
+	require.NoError(t, err)
+	_, err = issueTags.Tag("fingerprint", "jira-1234")
+	require.NoError(t, err)
+	tags, err := issueTags.Tag("fingerprint", "needs-review")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"jira-1234", "needs-review"}, tags)
+	assert.Equal(t, []string{"needs-review"}, issueTags.Untag("fingerprint", "jira-1234"))
+	assert.Empty(t, issueTags.Untag("fingerprint", "needs-review"))
+	assert.NotContains(t, issueTags.Entries, "fingerprint")
+}
+
+func Test_IssueTags_RejectsEmptyTag(t *testing.T) {
+	issueTags := NewIssueTags()
+
+	_, err := issueTags.Tag("fingerprint", "  ")
+
+	assert.Error(t, err)
+	assert.Empty(t, issueTags.Entries)
+}
+
+func Test_IssueTagsLoader_ReloadsSavedTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "issue_tags.json")
+	loader := NewIssueTagsLoader()
+	assert.Nil(t, loader.Load(path))
+	assert.Empty(t, loader.Load(path).Tags("fingerprint"))
+
+	issueTags := NewIssueTags()
+	_, err := issueTags.Tag("fingerprint", "needs-review")
+	require.NoError(t, err)
+	require.NoError(t, issueTags.Save(path))
+	loader.Reset()
+
+	assert.Equal(t, []string{"needs-review"}, loader.Load(path).Tags("fingerprint"))
+}
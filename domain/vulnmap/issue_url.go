@@ -0,0 +1,42 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// IssueURL returns the web page of the issue with the id, built from the issue URL template of the product.
+// ok is false if there is no template for the product or the resulting URL is invalid.
+func IssueURL(p product.Product, id string) (issueURL *url.URL, ok bool) {
+	template := config.CurrentConfig().IssueURLTemplate(p)
+	if template == "" {
+		return nil, false
+	}
+	issueURL, err := url.Parse(strings.ReplaceAll(template, config.IssueURLIdPlaceholder, url.PathEscape(id)))
+	if err != nil {
+		log.Err(err).Str("method", "IssueURL").Str("product", string(p)).Msg("Unable to create issue link for issue: " + id)
+		return nil, false
+	}
+	return issueURL, true
+}
@@ -17,8 +17,13 @@
 package vulnmap
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
@@ -33,7 +38,9 @@ type Reference struct {
 // Issue models a problem, vulnerability, or situation within your code that requires your attention
 type Issue struct {
 	// ID uniquely identifies the issue, it is intended to be human-readable
-	ID        string
+	ID string
+	// DisplayID is the id shown to users if it differs from ID, e.g. the primary CVE of the issue
+	DisplayID string
 	Severity  Severity
 	IssueType Type
 	// Range identifies the location of this issue in its source of origin (e.g. line & character start & end)
@@ -42,6 +49,8 @@ type Issue struct {
 	Message string
 	// todo [jc] this contains a formatted longest message for hovers, this needs to be pushed up and rendered in presentation. [bd] shouldn't the content and formatting be decided by the product?
 	FormattedMessage string
+	// FormattedMessages contains the formatted message in further output formats, e.g. for exports, keyed by format
+	FormattedMessages map[string]string
 	// AffectedFilePath is the file path to the file where the issue was found
 	AffectedFilePath string
 	// Product is the Vulnmap product, e.g. Vulnmap Open Source
@@ -50,6 +59,8 @@ type Issue struct {
 	References []Reference
 	// IssueDescriptionURL contains a Uri to display more information
 	IssueDescriptionURL *url.URL
+	// LessonURL links to the Vulnmap Learn lesson about the issue, nil if there is none
+	LessonURL *url.URL
 	// CodeActions can contain workspace edits or commands to be executed
 	CodeActions []CodeAction
 	// CodelensCommands that can be executed via a codelens
@@ -62,6 +73,17 @@ type Issue struct {
 	CVEs []string
 	// AdditionalData contains data that can be passed by the product (e.g. for presentation)
 	AdditionalData any
+	// RelatedLocations contains the other occurrences of the issue, e.g. the same vulnerability in other manifests
+	RelatedLocations []RelatedLocation
+	// Tags are the labels users attached to the issue for their own triage, they are set when the issue is filtered
+	Tags []string
+}
+
+// RelatedLocation is a location that is related to an issue, with a short label describing the relation
+type RelatedLocation struct {
+	FilePath string
+	Range    Range
+	Message  string
 }
 
 type CodeIssueData struct {
@@ -80,6 +102,24 @@ type CodeIssueData struct {
 	Rows               CodePoint          `json:"rows"`
 	IsSecurityType     bool               `json:"isSecurityType"`
 	IsAutofixable      bool               `json:"isAutofixable"`
+	// DataFlow contains the steps of the flow from the source to the sink of the issue, ordered by position
+	DataFlow []DataFlowElement `json:"dataFlow,omitempty"`
+	// Owner is the last commit of the affected line, nil if unknown
+	Owner *LineOwner `json:"owner,omitempty"`
+	// ContextHash identifies the affected code independent of its position in the file, so that the issue keeps its
+	// fingerprint when unrelated code is edited. Empty if the code couldn't be read.
+	ContextHash string `json:"contextHash,omitempty"`
+}
+
+// DataFlowElement is a step of the data flow of a Vulnmap Code issue
+type DataFlowElement struct {
+	Position  int    `json:"position"`
+	FilePath  string `json:"filePath"`
+	FlowRange Range  `json:"flowRange"`
+	// Content is the line of code of the step
+	Content string `json:"content"`
+	// Message describes the step, empty if the backend didn't provide a description
+	Message string `json:"message,omitempty"`
 }
 
 type ExampleCommitFix struct {
@@ -129,6 +169,18 @@ type OssIssueData struct {
 	DisplayTargetFile string      `json:"displayTargetFile"`
 	Language          string      `json:"language"`
 	Details           string      `json:"details"`
+	// Owner is the last commit of the affected line, nil if unknown
+	Owner *LineOwner `json:"owner,omitempty"`
+	// EpssScore is the probability of exploitation in the next 30 days between 0 and 1, nil if unknown
+	EpssScore *float64 `json:"epssScore,omitempty"`
+	// DisclosureTime is when the vulnerability was disclosed, nil if unknown
+	DisclosureTime *time.Time `json:"disclosureTime,omitempty"`
+	// ModificationTime is when the advisory was last revised, nil if unknown
+	ModificationTime *time.Time `json:"modificationTime,omitempty"`
+	// IsDevDependency is true if the package is only introduced by development dependencies of the project
+	IsDevDependency bool `json:"isDevDependency,omitempty"`
+	// FeedSeverity is the severity reported by the vulnerability feed, before it was escalated or downgraded
+	FeedSeverity string `json:"feedSeverity,omitempty"`
 }
 
 type IaCIssueData struct {
@@ -152,6 +204,8 @@ type IaCIssueData struct {
 	Path []string `json:"path"`
 	// References: List of reference URLs
 	References []string `json:"references,omitempty"`
+	// Owner is the last commit of the affected line, nil if unknown
+	Owner *LineOwner `json:"owner,omitempty"`
 }
 
 func (i Issue) GetFilterableIssueType() product.FilterableIssueType {
@@ -177,6 +231,49 @@ func (i Issue) GetFilterableIssueType() product.FilterableIssueType {
 	}
 }
 
+// FormattedMessageIn returns the formatted message in the given output format. Issues that weren't rendered in the
+// format, e.g. because their product renders all formats alike, return their formatted message.
+func (i Issue) FormattedMessageIn(format string) string {
+	if message, ok := i.FormattedMessages[format]; ok {
+		return message
+	}
+	return i.FormattedMessage
+}
+
+// Fingerprint returns a stable identifier of the issue, that stays the same across scans as long as the issue
+// is not fixed. Open source issues are identified by the affected package, Code issues by the affected code if it
+// is known, and all others by their location.
+func (i Issue) Fingerprint() string {
+	path := i.AffectedFilePath
+	location := i.Range.String()
+	switch data := i.AdditionalData.(type) {
+	case OssIssueData:
+		location = data.PackageName + "@" + data.Version
+	case CodeIssueData:
+		path = filepath.ToSlash(filepath.Clean(path))
+		location = data.ContextHash
+		if location == "" {
+			// without the code context, the rule and message identify the issue independently of its position
+			location = data.RuleId + "|" + data.Message
+		}
+	case IaCIssueData:
+		// the path of the misconfigured attribute is independent of the position of the resource in the file
+		if len(data.Path) > 0 {
+			location = strings.Join(data.Path, ".")
+		}
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s", i.ID, i.Product, path, location)))
+	return hex.EncodeToString(hash[:])
+}
+
+// DisplayedID returns the id that is shown to users, which is the display id if set and the id otherwise
+func (i Issue) DisplayedID() string {
+	if i.DisplayID != "" {
+		return i.DisplayID
+	}
+	return i.ID
+}
+
 func (i Issue) String() string {
 	return fmt.Sprintf("%s, ID: %s, Range: %s", i.AffectedFilePath, i.ID, i.Range)
 }
@@ -191,6 +288,8 @@ const (
 	High
 	Medium
 	Low
+	// Unknown is used for severities that are not recognized
+	Unknown
 )
 
 func (s Severity) String() string {
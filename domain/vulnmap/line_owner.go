@@ -0,0 +1,41 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+// LineOwner is the last commit of the line an issue affects, e.g. to route the issue to the developer
+type LineOwner struct {
+	Author    string `json:"author"`
+	CommitSha string `json:"commitSha"`
+}
+
+// WithLineOwner returns the additional data of an issue annotated with the owner of the affected line. Additional
+// data of unknown types is returned unchanged.
+func WithLineOwner(additionalData any, owner *LineOwner) any {
+	switch data := additionalData.(type) {
+	case OssIssueData:
+		data.Owner = owner
+		return data
+	case CodeIssueData:
+		data.Owner = owner
+		return data
+	case IaCIssueData:
+		data.Owner = owner
+		return data
+	default:
+		return additionalData
+	}
+}
@@ -0,0 +1,42 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"fmt"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// ScanError is returned by a product scanner if its output could not be used, e.g. because it was truncated or
+// otherwise invalid. In contrast to an empty result, it signals that the previous results must not be replaced.
+type ScanError struct {
+	Product   product.Product
+	Truncated bool
+	Err       error
+}
+
+func (e *ScanError) Error() string {
+	if e.Truncated {
+		return fmt.Sprintf("%s scan returned truncated output: %v", e.Product, e.Err)
+	}
+	return fmt.Sprintf("%s scan returned invalid output: %v", e.Product, e.Err)
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
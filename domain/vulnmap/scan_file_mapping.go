@@ -0,0 +1,80 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// FileSupporter is implemented by product scanners that know which files they can find issues in. Files are always
+// scanned by product scanners that don't implement it.
+type FileSupporter interface {
+	// SupportsFile returns true if the product can find issues in the given file
+	SupportsFile(ctx context.Context, path string) bool
+}
+
+// ProductsForFile returns the products the given mapping of file names and extension patterns (e.g. "*.tf") maps the
+// file to, in addition to the files the products support themselves. It returns nil if the path is not an existing
+// file (e.g. a folder), meaning that all products apply.
+func ProductsForFile(path string, mapping map[string][]product.Product) []product.Product {
+	stat, err := os.Stat(path)
+	if err != nil || stat.IsDir() {
+		return nil
+	}
+
+	fileName := filepath.Base(path)
+	extensionPattern := "*" + strings.ToLower(filepath.Ext(fileName))
+	products := appendUnique([]product.Product{}, mapping[fileName]...)
+	if extensionPattern != "*" {
+		products = appendUnique(products, mapping[extensionPattern]...)
+	}
+	return products
+}
+
+func appendUnique(products []product.Product, additional ...product.Product) []product.Product {
+	for _, p := range additional {
+		isDuplicate := false
+		for _, existing := range products {
+			isDuplicate = isDuplicate || existing == p
+		}
+		if !isDuplicate {
+			products = append(products, p)
+		}
+	}
+	return products
+}
+
+// isApplicableProduct returns true if the product scanner can find issues in the file, because the file is mapped to
+// its product or because the scanner supports the file. Nil file products are returned for folders and apply to all
+// products.
+func isApplicableProduct(ctx context.Context, s ProductScanner, path string, fileProducts []product.Product) bool {
+	if fileProducts == nil {
+		return true
+	}
+	for _, candidate := range fileProducts {
+		if candidate == s.Product() {
+			return true
+		}
+	}
+	supporter, ok := s.(FileSupporter)
+	return !ok || supporter.SupportsFile(ctx, path)
+}
@@ -0,0 +1,64 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+func TestProductsForFile(t *testing.T) {
+	mapping := map[string][]product.Product{
+		"README.md": {product.ProductCode},
+		"*.tf":      {product.ProductCode, product.ProductInfrastructureAsCode},
+	}
+	tests := []struct {
+		name     string
+		expected []product.Product
+	}{
+		{name: "logo.png", expected: []product.Product{}},
+		{name: "README.md", expected: []product.Product{product.ProductCode}},
+		{name: "Main.TF", expected: []product.Product{product.ProductCode, product.ProductInfrastructureAsCode}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ProductsForFile(createFile(t, test.name), mapping))
+		})
+	}
+}
+
+func TestProductsForFile_FolderAppliesToAllProducts(t *testing.T) {
+	assert.Nil(t, ProductsForFile(t.TempDir(), nil))
+}
+
+func TestIsApplicableProduct(t *testing.T) {
+	ossScanner := NewTestProductScanner(product.ProductOpenSource, true)
+	ossScanner.SetSupportedFiles("go.mod")
+	path := createFile(t, "main.hcl")
+
+	assert.True(t, isApplicableProduct(context.Background(), ossScanner, path, nil), "folders apply to all products")
+	assert.False(t, isApplicableProduct(context.Background(), ossScanner, path, []product.Product{}))
+	assert.True(t, isApplicableProduct(context.Background(), ossScanner, createFile(t, "go.mod"), []product.Product{}))
+	assert.True(t, isApplicableProduct(context.Background(), ossScanner, path,
+		[]product.Product{product.ProductOpenSource}), "mapped files apply to the product")
+	assert.True(t, isApplicableProduct(context.Background(), NewTestProductScanner(product.ProductCode, true), path,
+		[]product.Product{}), "scanners without supported files apply to all files")
+}
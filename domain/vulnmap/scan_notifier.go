@@ -3,8 +3,9 @@ package vulnmap
 import "github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 
 type ScanNotifier interface {
-	SendInProgress(folderPath string)
-	SendSuccess(product product.Product, folderPath string, issues []Issue)
-	SendSuccessForAllProducts(folderPath string, issues []Issue)
-	SendError(product product.Product, folderPath string)
+	SendInProgress(folderPath string, scanId string)
+	SendPending(folderPath string)
+	SendSuccess(product product.Product, folderPath string, scanId string, issues []Issue)
+	SendSuccessForAllProducts(folderPath string, scanIds map[product.Product]string, issues []Issue)
+	SendError(product product.Product, folderPath string, scanId string)
 }
@@ -1,43 +1,94 @@
 package vulnmap
 
 import (
+	"sync"
+
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 )
 
 var _ ScanNotifier = &MockScanNotifier{}
 
 type MockScanNotifier struct {
+	mutex           sync.Mutex
 	inProgressCalls []string
+	pendingCalls    []string
 	successCalls    []string
+	successIssues   [][]Issue
 	errorCalls      []string
+	scanIds         []string
 }
 
 func NewMockScanNotifier() *MockScanNotifier { return &MockScanNotifier{} }
 
-func (m *MockScanNotifier) SendInProgress(folderPath string) {
+func (m *MockScanNotifier) SendInProgress(folderPath string, scanId string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.inProgressCalls = append(m.inProgressCalls, folderPath)
+	m.scanIds = append(m.scanIds, scanId)
+}
+
+func (m *MockScanNotifier) SendPending(folderPath string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.pendingCalls = append(m.pendingCalls, folderPath)
 }
 
-func (m *MockScanNotifier) SendSuccessForAllProducts(folderPath string, issues []Issue) {
+func (m *MockScanNotifier) SendSuccessForAllProducts(folderPath string, _ map[product.Product]string, issues []Issue) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.successCalls = append(m.successCalls, folderPath)
+	m.successIssues = append(m.successIssues, issues)
 }
 
-func (m *MockScanNotifier) SendSuccess(product product.Product, folderPath string, issues []Issue) {
+func (m *MockScanNotifier) SendSuccess(product product.Product, folderPath string, scanId string, issues []Issue) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.successCalls = append(m.successCalls, folderPath)
+	m.successIssues = append(m.successIssues, issues)
+	m.scanIds = append(m.scanIds, scanId)
 }
 
-func (m *MockScanNotifier) SendError(product product.Product, folderPath string) {
+func (m *MockScanNotifier) SendError(product product.Product, folderPath string, scanId string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.errorCalls = append(m.errorCalls, folderPath)
+	m.scanIds = append(m.scanIds, scanId)
 }
 
 func (m *MockScanNotifier) InProgressCalls() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	return m.inProgressCalls
 }
 
+func (m *MockScanNotifier) PendingCalls() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.pendingCalls
+}
+
 func (m *MockScanNotifier) SuccessCalls() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	return m.successCalls
 }
 
+// SuccessIssues returns the issues sent with each success call
+func (m *MockScanNotifier) SuccessIssues() [][]Issue {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.successIssues
+}
+
 func (m *MockScanNotifier) ErrorCalls() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	return m.errorCalls
 }
+
+// ScanIds returns the scan ids sent with the in progress, success and error calls of single products
+func (m *MockScanNotifier) ScanIds() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.scanIds
+}
@@ -23,6 +23,8 @@ import (
 )
 
 type ScanData struct {
+	// ScanId is the id of the scan the results belong to, empty if the scan has no id
+	ScanId            string
 	Product           product.Product
 	Issues            []Issue
 	Err               error
@@ -33,6 +35,8 @@ type ScanData struct {
 	Medium            int
 	Low               int
 	SeverityCount     map[product.Product]SeverityCount
+	// Warnings contains the non-fatal problems the scanner reported, they don't fail the scan
+	Warnings []string
 }
 
 type SeverityCount struct {
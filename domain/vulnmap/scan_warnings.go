@@ -0,0 +1,59 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"context"
+	"sync"
+)
+
+type scanWarningsKey struct{}
+
+// scanWarnings collects the warnings reported by a product scanner during a scan
+type scanWarnings struct {
+	mutex    sync.Mutex
+	warnings []string
+}
+
+func (w *scanWarnings) add(warning string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.warnings = append(w.warnings, warning)
+}
+
+func (w *scanWarnings) list() []string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return append([]string(nil), w.warnings...)
+}
+
+// contextWithScanWarnings returns a context that collects the warnings added with AddScanWarning
+func contextWithScanWarnings(ctx context.Context) (context.Context, *scanWarnings) {
+	warnings := &scanWarnings{}
+	return context.WithValue(ctx, scanWarningsKey{}, warnings), warnings
+}
+
+// AddScanWarning reports a non-fatal problem of the scan the context belongs to, e.g. a project the CLI skipped.
+// Warnings are passed to the result processor with the issues and don't fail the scan. Outside of scans the
+// warning is dropped.
+func AddScanWarning(ctx context.Context, warning string) {
+	warnings, ok := ctx.Value(scanWarningsKey{}).(*scanWarnings)
+	if !ok || warning == "" {
+		return
+	}
+	warnings.add(warning)
+}
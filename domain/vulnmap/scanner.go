@@ -18,8 +18,9 @@ package vulnmap
 
 import (
 	"context"
+	"fmt"
+	"runtime/debug"
 	"sync"
-	"time"
 
 	"github.com/rs/zerolog/log"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
 	ux2 "github.com/khulnasoft-lab/vulnmap-ls/domain/observability/ux"
 	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/vulnmap_api"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/clock"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 )
@@ -64,6 +66,10 @@ type DelegatingConcurrentScanner struct {
 	vulnmapApiClient vulnmap_api.VulnmapApiClient
 	authService   AuthenticationService
 	notifier      notification.Notifier
+	// idleLogout is paused while scans are running, nil if idle logout isn't tracked
+	idleLogout *IdleLogout
+	// clock provides the finish timestamps of the scans
+	clock clock.Clock
 }
 
 func (sc *DelegatingConcurrentScanner) ScanPackages(ctx context.Context, config *config.Config, path string, content string) {
@@ -93,9 +99,20 @@ func NewDelegatingScanner(
 		scanners:      scanners,
 		authService:   authService,
 		notifier:      notifier,
+		clock:         clock.New(),
 	}
 }
 
+// SetClock sets the clock that provides the finish timestamps of the scans, e.g. a fake clock in tests
+func (sc *DelegatingConcurrentScanner) SetClock(clock clock.Clock) {
+	sc.clock = clock
+}
+
+// SetIdleLogout sets the idle logout that is paused while scans are running
+func (sc *DelegatingConcurrentScanner) SetIdleLogout(idleLogout *IdleLogout) {
+	sc.idleLogout = idleLogout
+}
+
 func (sc *DelegatingConcurrentScanner) ClearInlineValues(path string) {
 	for _, scanner := range sc.scanners {
 		if s, ok := scanner.(InlineValueProvider); ok {
@@ -119,6 +136,25 @@ func (sc *DelegatingConcurrentScanner) GetInlineValues(path string, myRange Rang
 	return values, err
 }
 
+// scannersInOrder returns the scanners sorted by the given product order. Scanners of products that are not part of
+// the order are appended in alphabetical order of their products.
+func scannersInOrder(scanners []ProductScanner, order []product.Product) []ProductScanner {
+	var products []product.Product
+	scannersByProduct := map[product.Product][]ProductScanner{}
+	for _, scanner := range scanners {
+		if _, exists := scannersByProduct[scanner.Product()]; !exists {
+			products = append(products, scanner.Product())
+		}
+		scannersByProduct[scanner.Product()] = append(scannersByProduct[scanner.Product()], scanner)
+	}
+
+	ordered := make([]ProductScanner, 0, len(scanners))
+	for _, p := range product.SortByDisplayOrder(products, order) {
+		ordered = append(ordered, scannersByProduct[p]...)
+	}
+	return ordered
+}
+
 func (sc *DelegatingConcurrentScanner) Init() error {
 	err := sc.initializer.Init()
 	if err != nil {
@@ -136,15 +172,18 @@ func (sc *DelegatingConcurrentScanner) Scan(
 ) {
 	method := "ide.workspace.folder.DelegatingConcurrentScanner.ScanFile"
 	c := config.CurrentConfig()
+	logger := c.SubsystemLogger(config.LogSubsystemScan)
 
 	authenticated, err := sc.authService.IsAuthenticated()
 	if err != nil {
-		log.Error().Err(err).Msg("Error checking authentication status")
+		logger.Error().Err(err).Msg("Error checking authentication status")
 	}
 
 	if !authenticated {
 		return
 	}
+	sc.idleLogout.ScanStarted()
+	defer sc.idleLogout.ScanFinished()
 
 	tokenChangeChannel := c.TokenChangesChannel()
 	done := make(chan bool)
@@ -156,7 +195,7 @@ func (sc *DelegatingConcurrentScanner) Scan(
 	go func() { // This goroutine will listen to token changes and cancel the scans using a context
 		select {
 		case <-tokenChangeChannel:
-			log.Info().Msg("Token was changed, cancelling scan")
+			logger.Info().Msg("Token was changed, cancelling scan")
 			cancelFunc()
 			return
 		case <-done: // The done channel prevents the goroutine from leaking after the scan is finished
@@ -165,7 +204,19 @@ func (sc *DelegatingConcurrentScanner) Scan(
 	}()
 
 	if ctx.Err() != nil {
-		log.Info().Msg("Scan was cancelled")
+		logger.Info().Msg("Scan was cancelled")
+		return
+	}
+
+	folderConfig := FolderConfigFromContext(ctx)
+	fileProducts := ProductsForFile(path, c.ScanFileMappings())
+	isScanned := func(s ProductScanner) bool {
+		return folderConfig.IsProductEnabled(s.Product(), s.IsEnabled()) &&
+			isRequestedProduct(ctx, s.Product()) &&
+			isApplicableProduct(ctx, s, path, fileProducts)
+	}
+	if !sc.anyScanner(isScanned) {
+		logger.Debug().Str("method", method).Str("path", path).Msg("skipping scan, no enabled product applies to the file")
 		return
 	}
 
@@ -177,47 +228,123 @@ func (sc *DelegatingConcurrentScanner) Scan(
 				TriggeredByUser: false,
 			},
 		)
-		sc.scanNotifier.SendInProgress(folderPath)
+		sc.scanNotifier.SendInProgress(folderPath, ScanIdFromContext(ctx))
+	}
+
+	scanProduct := func(s ProductScanner) {
+		span := sc.instrumentor.NewTransaction(context.WithValue(ctx, s.Product(), s), string(s.Product()), method)
+		defer sc.instrumentor.Finish(span)
+		logger.Info().Msgf("Scanning %s with %T: STARTED", path, s)
+		// TODO change interface of scan to pass a func (processResults), which would enable products to stream
+
+		scanSpan := sc.instrumentor.StartSpan(span.Context(), "scan")
+		scanCtx, warnings := contextWithScanWarnings(scanSpan.Context())
+		foundIssues, err := scanRecovering(scanCtx, s, path, folderPath)
+		sc.instrumentor.Finish(scanSpan)
+
+		// now process
+		data := ScanData{
+			ScanId:            ScanIdFromContext(ctx),
+			Product:           s.Product(),
+			Issues:            foundIssues,
+			Err:               err,
+			DurationMs:        scanSpan.GetDurationMs(),
+			TimestampFinished: sc.clock.Now().UTC(),
+			Warnings:          warnings.list(),
+		}
+		processResults(data)
+		logger.Info().Msgf("Scanning %s with %T: COMPLETE found %v issues", path, s, len(foundIssues))
 	}
 
+	sequential := c.IsSequentialProductScans()
 	waitGroup := &sync.WaitGroup{}
-	for _, scanner := range sc.scanners {
-		if scanner.IsEnabled() {
-			waitGroup.Add(1)
-			go func(s ProductScanner) {
-				defer waitGroup.Done()
-				span := sc.instrumentor.NewTransaction(context.WithValue(ctx, s.Product(), s), string(s.Product()), method)
-				defer sc.instrumentor.Finish(span)
-				log.Info().Msgf("Scanning %s with %T: STARTED", path, s)
-				// TODO change interface of scan to pass a func (processResults), which would enable products to stream
-
-				scanSpan := sc.instrumentor.StartSpan(span.Context(), "scan")
-				foundIssues, err := s.Scan(scanSpan.Context(), path, folderPath)
-				sc.instrumentor.Finish(scanSpan)
-
-				// now process
-				data := ScanData{
-					Product:           s.Product(),
-					Issues:            foundIssues,
-					Err:               err,
-					DurationMs:        scanSpan.GetDurationMs(),
-					TimestampFinished: time.Now().UTC(),
-				}
-				processResults(data)
-				log.Info().Msgf("Scanning %s with %T: COMPLETE found %v issues", path, s, len(foundIssues))
-			}(scanner)
-		} else {
-			log.Debug().Msgf("Skipping scan with %T because it is not enabled", scanner)
+	for _, scanner := range scannersInOrder(sc.scanners, c.ProductScanOrder()) {
+		if !isScanned(scanner) {
+			logger.Debug().Msgf("Skipping scan with %T because it is not enabled", scanner)
+			continue
 		}
+		if sequential {
+			scanProduct(scanner)
+			continue
+		}
+		waitGroup.Add(1)
+		go func(s ProductScanner) {
+			defer waitGroup.Done()
+			scanProduct(s)
+		}(scanner)
 	}
-	log.Debug().Msgf("All product scanners started for %s", path)
+	logger.Debug().Msgf("All product scanners started for %s", path)
 	waitGroup.Wait()
-	log.Debug().Msgf("All product scanners finished for %s", path)
+	logger.Debug().Msgf("All product scanners finished for %s", path)
 	sc.notifier.Send(lsp.InlineValueRefresh{})
 	sc.notifier.Send(lsp.CodeLensRefresh{})
 	// TODO: handle learn actions centrally instead of in each scanner
 }
 
+// scanRecovering scans with the product scanner and turns a panic of the scanner into an error, so that it is
+// reported to the result processor like any other failed scan instead of crashing the server.
+func scanRecovering(ctx context.Context, s ProductScanner, path string, folderPath string) (issues []Issue, err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		err = fmt.Errorf("panic during %s scan of %s: %v", s.Product(), path, r)
+		log.Error().Err(err).Str("method", "scanRecovering").Msgf("recovered from panic\n%s", debug.Stack())
+	}()
+	return s.Scan(ctx, path, folderPath)
+}
+
+func (sc *DelegatingConcurrentScanner) anyScanner(predicate func(s ProductScanner) bool) bool {
+	for _, scanner := range sc.scanners {
+		if predicate(scanner) {
+			return true
+		}
+	}
+	return false
+}
+
+type scanProductsKey struct{}
+
+type scanIdKey struct{}
+
+// ContextWithScanId marks the scans started with the returned context as part of the scan with the given id, so that
+// their scan notifications can be correlated with it
+func ContextWithScanId(ctx context.Context, scanId string) context.Context {
+	return context.WithValue(ctx, scanIdKey{}, scanId)
+}
+
+// ScanIdFromContext returns the id of the scan the context belongs to, empty if the scan has no id
+func ScanIdFromContext(ctx context.Context) string {
+	scanId, _ := ctx.Value(scanIdKey{}).(string)
+	return scanId
+}
+
+// ContextWithProducts restricts the scans started with the returned context to the given products
+func ContextWithProducts(ctx context.Context, products []product.Product) context.Context {
+	return context.WithValue(ctx, scanProductsKey{}, products)
+}
+
+// RequestedProducts returns the products the context restricts scans to, ok is false if it doesn't restrict them
+func RequestedProducts(ctx context.Context) (products []product.Product, ok bool) {
+	products, ok = ctx.Value(scanProductsKey{}).([]product.Product)
+	return products, ok
+}
+
+// isRequestedProduct returns false if the context restricts the scan to other products
+func isRequestedProduct(ctx context.Context, p product.Product) bool {
+	products, ok := RequestedProducts(ctx)
+	if !ok {
+		return true
+	}
+	for _, requested := range products {
+		if requested == p {
+			return true
+		}
+	}
+	return false
+}
+
 func getEnabledAnalysisTypes(productScanners []ProductScanner) (analysisTypes []ux2.AnalysisType) {
 	for _, ps := range productScanners {
 		if !ps.IsEnabled() {
@@ -18,11 +18,16 @@ package vulnmap
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/initialize"
@@ -30,6 +35,7 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/performance"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/ux"
 	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/vulnmap_api"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/clock"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
@@ -79,6 +85,272 @@ func setupScanner(testProductScanners ...ProductScanner) (
 	return scanner, analytics, scanNotifier
 }
 
+func TestScan_FolderConfigDisablesProductOnlyForItsFolder(t *testing.T) {
+	testutil.UnitTest(t)
+	codeScanner := NewTestProductScanner(product.ProductCode, true)
+	ossScanner := NewTestProductScanner(product.ProductOpenSource, true)
+	scanner, _, _ := setupScanner(codeScanner, ossScanner)
+	folderConfig := &FolderConfig{EnabledProducts: map[string]bool{"oss": false}}
+
+	scanner.Scan(ContextWithFolderConfig(context.Background(), folderConfig), "a", NoopResultProcessor, "a")
+	scanner.Scan(context.Background(), "b", NoopResultProcessor, "b")
+
+	assert.Equal(t, 2, codeScanner.Scans())
+	assert.Equal(t, 1, ossScanner.Scans())
+}
+
+func TestScan_ContextWithProductsScansOnlyRequestedProducts(t *testing.T) {
+	testutil.UnitTest(t)
+	codeScanner := NewTestProductScanner(product.ProductCode, true)
+	ossScanner := NewTestProductScanner(product.ProductOpenSource, true)
+	scanner, _, _ := setupScanner(codeScanner, ossScanner)
+
+	scanner.Scan(ContextWithProducts(context.Background(), []product.Product{product.ProductOpenSource}), "a", NoopResultProcessor, "a")
+
+	assert.Equal(t, 0, codeScanner.Scans())
+	assert.Equal(t, 1, ossScanner.Scans())
+}
+
+// issueReturningScanner returns the given number of issues after the scan duration
+type issueReturningScanner struct {
+	*TestProductScanner
+	issueCount int
+}
+
+func newIssueReturningScanner(p product.Product, issueCount int, scanDuration time.Duration) *issueReturningScanner {
+	scanner := &issueReturningScanner{TestProductScanner: NewTestProductScanner(p, true), issueCount: issueCount}
+	scanner.SetScanDuration(scanDuration)
+	return scanner
+}
+
+func (s *issueReturningScanner) Scan(ctx context.Context, path string, folderPath string) ([]Issue, error) {
+	_, _ = s.TestProductScanner.Scan(ctx, path, folderPath)
+	issues := make([]Issue, s.issueCount)
+	for i := range issues {
+		issues[i] = Issue{ID: fmt.Sprintf("%s-%d", s.Product(), i), Product: s.Product()}
+	}
+	return issues, nil
+}
+
+// recordingResultProcessor records the scan data in the order it is processed
+type recordingResultProcessor struct {
+	mutex sync.Mutex
+	data  []ScanData
+}
+
+func (r *recordingResultProcessor) process(data ScanData) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.data = append(r.data, data)
+}
+
+func TestScan_SequentialProductScansRunInConfiguredOrder(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetSequentialProductScans(true)
+	c.SetProductScanOrder([]product.Product{product.ProductInfrastructureAsCode, product.ProductOpenSource, product.ProductCode})
+	// the first product in the order is the slowest, so that running in parallel would publish it last
+	codeScanner := newIssueReturningScanner(product.ProductCode, 1, 0)
+	ossScanner := newIssueReturningScanner(product.ProductOpenSource, 1, 10*time.Millisecond)
+	iacScanner := newIssueReturningScanner(product.ProductInfrastructureAsCode, 1, 50*time.Millisecond)
+	scanner, _, _ := setupScanner(codeScanner, ossScanner, iacScanner)
+	processor := &recordingResultProcessor{}
+
+	scanner.Scan(context.Background(), "", processor.process, "")
+
+	var processedProducts []product.Product
+	for _, data := range processor.data {
+		processedProducts = append(processedProducts, data.Product)
+	}
+	assert.Equal(t,
+		[]product.Product{product.ProductInfrastructureAsCode, product.ProductOpenSource, product.ProductCode},
+		processedProducts)
+}
+
+func TestScan_ParallelProductScansProcessAllIssues(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetSequentialProductScans(false)
+	codeScanner := newIssueReturningScanner(product.ProductCode, 1, 20*time.Millisecond)
+	ossScanner := newIssueReturningScanner(product.ProductOpenSource, 2, 10*time.Millisecond)
+	iacScanner := newIssueReturningScanner(product.ProductInfrastructureAsCode, 3, 0)
+	scanner, _, _ := setupScanner(codeScanner, ossScanner, iacScanner)
+	processor := &recordingResultProcessor{}
+
+	scanner.Scan(context.Background(), "", processor.process, "")
+
+	issueCounts := map[product.Product]int{}
+	for _, data := range processor.data {
+		issueCounts[data.Product] += len(data.Issues)
+	}
+	assert.Equal(t, map[product.Product]int{
+		product.ProductCode:                 1,
+		product.ProductOpenSource:           2,
+		product.ProductInfrastructureAsCode: 3,
+	}, issueCounts)
+}
+
+// panickingScanner panics during its scans
+type panickingScanner struct {
+	*TestProductScanner
+}
+
+func (s *panickingScanner) Scan(context.Context, string, string) ([]Issue, error) {
+	panic("scanner failure")
+}
+
+func TestScan_PanicInParallelProductScanIsReportedAsError(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetSequentialProductScans(false)
+	codeScanner := &panickingScanner{TestProductScanner: NewTestProductScanner(product.ProductCode, true)}
+	ossScanner := newIssueReturningScanner(product.ProductOpenSource, 2, 0)
+	scanner, _, _ := setupScanner(codeScanner, ossScanner)
+	processor := &recordingResultProcessor{}
+
+	assert.NotPanics(t, func() { scanner.Scan(context.Background(), "", processor.process, "") })
+
+	results := map[product.Product]ScanData{}
+	for _, data := range processor.data {
+		results[data.Product] = data
+	}
+	require.Len(t, results, 2)
+	assert.ErrorContains(t, results[product.ProductCode].Err, "scanner failure")
+	assert.NoError(t, results[product.ProductOpenSource].Err)
+	assert.Len(t, results[product.ProductOpenSource].Issues, 2)
+}
+
+func TestScan_TimestampFinishedIsTakenFromClock(t *testing.T) {
+	testutil.UnitTest(t)
+	finished := time.Date(2024, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	scanner, _, _ := setupScanner(newIssueReturningScanner(product.ProductOpenSource, 1, 0))
+	scanner.(*DelegatingConcurrentScanner).SetClock(clock.NewFakeClock(finished))
+	processor := &recordingResultProcessor{}
+
+	scanner.Scan(context.Background(), "", processor.process, "")
+
+	require.Len(t, processor.data, 1)
+	assert.Equal(t, finished.UTC(), processor.data[0].TimestampFinished)
+}
+
+// warningScanner reports the warnings during its scans
+type warningScanner struct {
+	*TestProductScanner
+	warnings []string
+}
+
+func (s *warningScanner) Scan(ctx context.Context, path string, folderPath string) ([]Issue, error) {
+	for _, warning := range s.warnings {
+		AddScanWarning(ctx, warning)
+	}
+	return s.TestProductScanner.Scan(ctx, path, folderPath)
+}
+
+func TestScan_WarningsArePassedToResultProcessor(t *testing.T) {
+	testutil.UnitTest(t)
+	ossScanner := &warningScanner{
+		TestProductScanner: NewTestProductScanner(product.ProductOpenSource, true),
+		warnings:           []string{"could not resolve project a", "could not resolve project b"},
+	}
+	codeScanner := NewTestProductScanner(product.ProductCode, true)
+	scanner, _, _ := setupScanner(ossScanner, codeScanner)
+	processor := &recordingResultProcessor{}
+
+	scanner.Scan(context.Background(), "", processor.process, "")
+
+	warnings := map[product.Product][]string{}
+	for _, data := range processor.data {
+		assert.NoError(t, data.Err)
+		warnings[data.Product] = data.Warnings
+	}
+	assert.Equal(t, []string{"could not resolve project a", "could not resolve project b"}, warnings[product.ProductOpenSource])
+	assert.Empty(t, warnings[product.ProductCode])
+}
+
+func TestAddScanWarning_OutsideOfScanIsIgnored(t *testing.T) {
+	assert.NotPanics(t, func() { AddScanWarning(context.Background(), "warning") })
+}
+
+func Test_scannersInOrder(t *testing.T) {
+	codeScanner := NewTestProductScanner(product.ProductCode, true)
+	ossScanner := NewTestProductScanner(product.ProductOpenSource, true)
+	iacScanner := NewTestProductScanner(product.ProductInfrastructureAsCode, true)
+
+	ordered := scannersInOrder([]ProductScanner{codeScanner, ossScanner, iacScanner}, []product.Product{product.ProductOpenSource})
+
+	assert.Equal(t, []ProductScanner{ossScanner, codeScanner, iacScanner}, ordered)
+}
+
+func createFile(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte{}, 0600))
+	return path
+}
+
+func TestScan_FileWithoutApplicableProductIsNotScanned(t *testing.T) {
+	testutil.UnitTest(t)
+	codeScanner := NewTestProductScanner(product.ProductCode, true)
+	codeScanner.SetSupportedFiles("*.java")
+	ossScanner := NewTestProductScanner(product.ProductOpenSource, true)
+	ossScanner.SetSupportedFiles("go.mod")
+	iacScanner := NewTestProductScanner(product.ProductInfrastructureAsCode, true)
+	iacScanner.SetSupportedFiles("*.tf")
+	scanner, analytics, _ := setupScanner(codeScanner, ossScanner, iacScanner)
+	path := createFile(t, "logo.png")
+
+	scanner.Scan(context.Background(), path, NoopResultProcessor, filepath.Dir(path))
+
+	assert.Equal(t, 0, codeScanner.Scans()+ossScanner.Scans()+iacScanner.Scans())
+	assert.Empty(t, analytics.GetAnalytics())
+}
+
+func TestScan_FileIsScannedByApplicableProductsOnly(t *testing.T) {
+	testutil.UnitTest(t)
+	codeScanner := NewTestProductScanner(product.ProductCode, true)
+	codeScanner.SetSupportedFiles("*.java")
+	ossScanner := NewTestProductScanner(product.ProductOpenSource, true)
+	ossScanner.SetSupportedFiles("go.mod")
+	iacScanner := NewTestProductScanner(product.ProductInfrastructureAsCode, true)
+	iacScanner.SetSupportedFiles("*.tf")
+	scanner, _, _ := setupScanner(codeScanner, ossScanner, iacScanner)
+	path := createFile(t, "go.mod")
+
+	scanner.Scan(context.Background(), path, NoopResultProcessor, filepath.Dir(path))
+
+	assert.Equal(t, 1, ossScanner.Scans())
+	assert.Equal(t, 0, codeScanner.Scans())
+	assert.Equal(t, 0, iacScanner.Scans())
+}
+
+func TestScan_FileWithApplicableButDisabledProductIsNotScanned(t *testing.T) {
+	testutil.UnitTest(t)
+	codeScanner := NewTestProductScanner(product.ProductCode, true)
+	codeScanner.SetSupportedFiles("*.java")
+	ossScanner := NewTestProductScanner(product.ProductOpenSource, false)
+	ossScanner.SetSupportedFiles("go.mod")
+	scanner, analytics, _ := setupScanner(codeScanner, ossScanner)
+	path := createFile(t, "go.mod")
+
+	scanner.Scan(context.Background(), path, NoopResultProcessor, filepath.Dir(path))
+
+	assert.Equal(t, 0, codeScanner.Scans()+ossScanner.Scans())
+	assert.Empty(t, analytics.GetAnalytics())
+}
+
+func TestScan_ConfiguredScanFileMappingIsRespected(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetScanFileMappings(map[string][]product.Product{"*.hcl": {product.ProductInfrastructureAsCode}})
+	codeScanner := NewTestProductScanner(product.ProductCode, true)
+	codeScanner.SetSupportedFiles("*.java")
+	iacScanner := NewTestProductScanner(product.ProductInfrastructureAsCode, true)
+	iacScanner.SetSupportedFiles("*.tf")
+	scanner, _, _ := setupScanner(codeScanner, iacScanner)
+	path := createFile(t, "main.hcl")
+
+	scanner.Scan(context.Background(), path, NoopResultProcessor, filepath.Dir(path))
+
+	assert.Equal(t, 1, iacScanner.Scans())
+	assert.Equal(t, 0, codeScanner.Scans())
+}
+
 func TestScan_whenProductScannerEnabled_SendsAnalysisTriggered(t *testing.T) {
 	testutil.UnitTest(t)
 	config.CurrentConfig().SetVulnmapCodeEnabled(true)
@@ -153,3 +425,16 @@ func TestScan_whenProductScannerEnabled_SendsInProgress(t *testing.T) {
 
 	assert.NotEmpty(t, mockScanNotifier.InProgressCalls())
 }
+
+func TestScan_ContextWithScanIdReportsTheScanId(t *testing.T) {
+	testutil.UnitTest(t)
+	config.CurrentConfig().SetVulnmapCodeEnabled(true)
+	scanner, _, scanNotifier := setupScanner(NewTestProductScanner(product.ProductCode, true))
+	var scanIds []string
+	processResults := func(scanData ScanData) { scanIds = append(scanIds, scanData.ScanId) }
+
+	scanner.Scan(ContextWithScanId(context.Background(), "scan-id"), "", processResults, "")
+
+	assert.Equal(t, []string{"scan-id"}, scanNotifier.(*MockScanNotifier).ScanIds())
+	assert.Equal(t, []string{"scan-id"}, scanIds)
+}
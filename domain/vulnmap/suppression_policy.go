@@ -0,0 +1,204 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const suppressionExpiryLayout = "2006-01-02"
+
+// SuppressionPolicy is a centrally maintained list of suppressed vulnerabilities
+type SuppressionPolicy struct {
+	Suppressions []Suppression `json:"suppressions"`
+}
+
+// Suppression suppresses all issues that match any of the given CVE, package name or CWE pattern.
+// CWE patterns support glob syntax, e.g. "CWE-7*". Suppressions with an expiry date stop applying after that day.
+type Suppression struct {
+	CVE     string `json:"cve,omitempty"`
+	Package string `json:"package,omitempty"`
+	CWE     string `json:"cwe,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	// Expires is a date in the format YYYY-MM-DD
+	Expires string `json:"expires,omitempty"`
+}
+
+// LoadSuppressionPolicy reads the suppression policy from the given JSON file
+func LoadSuppressionPolicy(path string) (*SuppressionPolicy, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read suppression policy")
+	}
+	var policy SuppressionPolicy
+	err = json.Unmarshal(bytes, &policy)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't parse suppression policy")
+	}
+	for _, suppression := range policy.Suppressions {
+		if _, err = suppression.expiry(); err != nil {
+			return nil, errors.Wrapf(err, "invalid expiry date %s", suppression.Expires)
+		}
+	}
+	return &policy, nil
+}
+
+// Suppression returns the first suppression that applies to the issue at the given time. Matching suppressions
+// that are expired are ignored, they are logged once by LogSuppressionPolicyProblems when the policy is configured.
+func (p *SuppressionPolicy) Suppression(issue Issue, now time.Time) (Suppression, bool) {
+	if p == nil {
+		return Suppression{}, false
+	}
+	for _, suppression := range p.Suppressions {
+		if !suppression.matches(issue) {
+			continue
+		}
+		if suppression.isExpired(now) {
+			continue
+		}
+		return suppression, true
+	}
+	return Suppression{}, false
+}
+
+// ExpiredSuppressions returns the suppressions that no longer apply at the given time
+func (p *SuppressionPolicy) ExpiredSuppressions(now time.Time) (expired []Suppression) {
+	if p == nil {
+		return nil
+	}
+	for _, suppression := range p.Suppressions {
+		if suppression.isExpired(now) {
+			expired = append(expired, suppression)
+		}
+	}
+	return expired
+}
+
+// LogSuppressionPolicyProblems logs why the policy at the path can't be applied and which of its suppressions are
+// expired. It is called when the policy is configured, so that filtering issues doesn't log on every pass.
+func LogSuppressionPolicyProblems(path string, now time.Time) {
+	logger := log.With().Str("method", "LogSuppressionPolicyProblems").Str("path", path).Logger()
+	policy, err := LoadSuppressionPolicy(path)
+	if err != nil {
+		logger.Warn().Err(err).Msg("ignoring suppression policy")
+		return
+	}
+	for _, suppression := range policy.ExpiredSuppressions(now) {
+		logger.Warn().Str("cve", suppression.CVE).Str("package", suppression.Package).Str("cwe", suppression.CWE).
+			Msgf("suppression expired on %s and no longer applies", suppression.Expires)
+	}
+}
+
+func (s Suppression) matches(issue Issue) bool {
+	if s.CVE != "" && containsFold(issue.CVEs, s.CVE) {
+		return true
+	}
+	if s.Package != "" {
+		if data, ok := issue.AdditionalData.(OssIssueData); ok && strings.EqualFold(data.PackageName, s.Package) {
+			return true
+		}
+	}
+	if s.CWE != "" {
+		for _, cwe := range issue.CWEs {
+			if matched, _ := filepath.Match(strings.ToUpper(s.CWE), strings.ToUpper(cwe)); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s Suppression) expiry() (time.Time, error) {
+	return parseExpiry(s.Expires)
+}
+
+func (s Suppression) isExpired(now time.Time) bool {
+	return isExpired(s.Expires, now)
+}
+
+// parseExpiry parses an expiry date in the format YYYY-MM-DD, an empty date never expires
+func parseExpiry(expires string) (time.Time, error) {
+	if expires == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(suppressionExpiryLayout, expires)
+}
+
+func isExpired(expires string, now time.Time) bool {
+	expiry, err := parseExpiry(expires)
+	if err != nil || expiry.IsZero() {
+		return false
+	}
+	// applies until the end of the expiry day
+	return !now.Before(expiry.AddDate(0, 0, 1))
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// SuppressionPolicyLoader loads the suppression policy and reloads it only when the file changed
+type SuppressionPolicyLoader struct {
+	mutex   sync.Mutex
+	path    string
+	modTime time.Time
+	policy  *SuppressionPolicy
+}
+
+func NewSuppressionPolicyLoader() *SuppressionPolicyLoader {
+	return &SuppressionPolicyLoader{}
+}
+
+// Load returns the policy at the path, or nil if no path is given or the policy can't be loaded
+func (l *SuppressionPolicyLoader) Load(path string) *SuppressionPolicy {
+	if path == "" {
+		return nil
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Debug().Err(err).Str("method", "SuppressionPolicyLoader.Load").Msg("couldn't access suppression policy")
+		return nil
+	}
+	if path == l.path && info.ModTime().Equal(l.modTime) {
+		return l.policy
+	}
+
+	policy, err := LoadSuppressionPolicy(path)
+	if err != nil {
+		log.Debug().Err(err).Str("method", "SuppressionPolicyLoader.Load").Msg("ignoring suppression policy")
+	}
+	l.path = path
+	l.modTime = info.ModTime()
+	l.policy = policy
+	return policy
+}
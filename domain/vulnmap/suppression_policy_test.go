@@ -0,0 +1,124 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSuppressionPolicy(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func Test_SuppressionPolicy_Suppression(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	policy := &SuppressionPolicy{Suppressions: []Suppression{
+		{CVE: "CVE-2021-44228", Reason: "not exploitable"},
+		{Package: "lodash", Reason: "accepted risk"},
+		{CWE: "CWE-7*", Reason: "handled by framework"},
+		{CVE: "CVE-2020-0001", Reason: "temporary", Expires: "2024-05-31"},
+	}}
+
+	t.Run("by CVE", func(t *testing.T) {
+		suppression, suppressed := policy.Suppression(Issue{ID: "1", CVEs: []string{"cve-2021-44228"}}, now)
+
+		assert.True(t, suppressed)
+		assert.Equal(t, "not exploitable", suppression.Reason)
+	})
+
+	t.Run("by package", func(t *testing.T) {
+		issue := Issue{ID: "2", AdditionalData: OssIssueData{PackageName: "lodash"}}
+
+		suppression, suppressed := policy.Suppression(issue, now)
+
+		assert.True(t, suppressed)
+		assert.Equal(t, "accepted risk", suppression.Reason)
+	})
+
+	t.Run("by CWE pattern", func(t *testing.T) {
+		_, suppressed := policy.Suppression(Issue{ID: "3", CWEs: []string{"CWE-79"}}, now)
+
+		assert.True(t, suppressed)
+	})
+
+	t.Run("expired entry no longer suppresses", func(t *testing.T) {
+		_, suppressed := policy.Suppression(Issue{ID: "4", CVEs: []string{"CVE-2020-0001"}}, now)
+
+		assert.False(t, suppressed)
+	})
+
+	t.Run("entry applies until the end of the expiry day", func(t *testing.T) {
+		expiryDay := time.Date(2024, 5, 31, 23, 0, 0, 0, time.UTC)
+
+		_, suppressed := policy.Suppression(Issue{ID: "4", CVEs: []string{"CVE-2020-0001"}}, expiryDay)
+
+		assert.True(t, suppressed)
+	})
+
+	t.Run("unmatched issue", func(t *testing.T) {
+		_, suppressed := policy.Suppression(Issue{ID: "5", CVEs: []string{"CVE-2022-1234"}}, now)
+
+		assert.False(t, suppressed)
+	})
+
+	t.Run("expired entries", func(t *testing.T) {
+		expired := policy.ExpiredSuppressions(now)
+
+		require.Len(t, expired, 1)
+		assert.Equal(t, "CVE-2020-0001", expired[0].CVE)
+	})
+}
+
+func Test_LoadSuppressionPolicy(t *testing.T) {
+	t.Run("valid policy", func(t *testing.T) {
+		path := writeSuppressionPolicy(t, `{"suppressions": [{"cve": "CVE-2021-44228", "expires": "2030-01-01"}]}`)
+
+		policy, err := LoadSuppressionPolicy(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Suppression{{CVE: "CVE-2021-44228", Expires: "2030-01-01"}}, policy.Suppressions)
+	})
+
+	t.Run("invalid expiry date", func(t *testing.T) {
+		path := writeSuppressionPolicy(t, `{"suppressions": [{"cve": "CVE-2021-44228", "expires": "tomorrow"}]}`)
+
+		_, err := LoadSuppressionPolicy(path)
+
+		assert.Error(t, err)
+	})
+}
+
+func Test_SuppressionPolicyLoader_ReloadsChangedPolicy(t *testing.T) {
+	path := writeSuppressionPolicy(t, `{"suppressions": [{"cve": "CVE-1"}]}`)
+	loader := NewSuppressionPolicyLoader()
+	assert.Equal(t, "CVE-1", loader.Load(path).Suppressions[0].CVE)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"suppressions": [{"cve": "CVE-2"}]}`), 0600))
+	require.NoError(t, os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+	assert.Equal(t, "CVE-2", loader.Load(path).Suppressions[0].CVE)
+	assert.Nil(t, loader.Load(""))
+}
@@ -18,6 +18,7 @@ package vulnmap
 
 import (
 	"context"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -41,6 +42,9 @@ type TestProductScanner struct {
 	scans        int
 	mutex        sync.Mutex
 	scanDuration time.Duration
+	// supportedFiles are the file names and extension patterns (e.g. "*.tf") of the supported files, nil supports
+	// all files
+	supportedFiles []string
 }
 
 func (t *TestProductScanner) GetInlineValues(_ string, _ Range) ([]InlineValue, error) {
@@ -88,3 +92,20 @@ func (t *TestProductScanner) Product() product.Product {
 }
 
 func (t *TestProductScanner) SetScanDuration(duration time.Duration) { t.scanDuration = duration }
+
+func (t *TestProductScanner) SetSupportedFiles(supportedFiles ...string) {
+	t.supportedFiles = supportedFiles
+}
+
+func (t *TestProductScanner) SupportsFile(_ context.Context, path string) bool {
+	if t.supportedFiles == nil {
+		return true
+	}
+	fileName := filepath.Base(path)
+	for _, supportedFile := range t.supportedFiles {
+		if supportedFile == fileName || supportedFile == "*"+filepath.Ext(fileName) {
+			return true
+		}
+	}
+	return false
+}
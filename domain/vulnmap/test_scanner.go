@@ -25,9 +25,10 @@ import (
 )
 
 type TestScanner struct {
-	mutex  sync.Mutex
-	calls  int
-	Issues []Issue
+	mutex    sync.Mutex
+	calls    int
+	Issues   []Issue
+	Warnings []string
 }
 
 func NewTestScanner() *TestScanner {
@@ -50,7 +51,7 @@ func (s *TestScanner) Product() product.Product {
 }
 
 func (s *TestScanner) Scan(
-	_ context.Context,
+	ctx context.Context,
 	_ string,
 	processResults ScanResultProcessor,
 	_ string,
@@ -58,10 +59,12 @@ func (s *TestScanner) Scan(
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	data := ScanData{
+		ScanId:            ScanIdFromContext(ctx),
 		Product:           product.ProductOpenSource,
 		Issues:            s.Issues,
 		DurationMs:        1234,
 		TimestampFinished: time.Now().UTC(),
+		Warnings:          s.Warnings,
 	}
 	processResults(data)
 	s.calls++
@@ -76,3 +79,14 @@ func (s *TestScanner) Calls() int {
 func (s *TestScanner) AddTestIssue(issue Issue) {
 	s.Issues = append(s.Issues, issue)
 }
+
+// NewTestOssIssue returns an open source issue of a package named after the issue id
+func NewTestOssIssue(id string, path string, severity Severity) Issue {
+	return Issue{
+		ID:               id,
+		AffectedFilePath: path,
+		Product:          product.ProductOpenSource,
+		Severity:         severity,
+		AdditionalData:   OssIssueData{Key: id, PackageName: id, Version: "1.0.0"},
+	}
+}
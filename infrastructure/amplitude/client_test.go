@@ -181,6 +181,17 @@ func Test_AnalyticEventsNotSentForFedramp(t *testing.T) {
 	assert.Equal(t, 0, len(fakeSegmentClient.trackedEvents))
 }
 
+func Test_AnalyticEventsNotSentForDoNotTrack(t *testing.T) {
+	s, fakeSegmentClient, c := setupUnitTest(t)
+	c.SetTelemetryEnabled(true)
+	t.Setenv(config.DoNotTrack, "1")
+
+	s.PluginIsInstalled(ux.PluginIsInstalledProperties{})
+	s.Identify()
+
+	assert.Equal(t, 0, len(fakeSegmentClient.trackedEvents))
+}
+
 func setupUnitTest(t *testing.T) (*Client, *FakeSegmentClient, *config.Config) {
 	c := testutil.UnitTest(t)
 	authFunc := func() (string, error) { return "fakeUser", nil }
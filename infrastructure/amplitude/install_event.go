@@ -31,6 +31,9 @@ const (
 )
 
 func (c *Client) captureInstalledEvent() {
+	if config.CurrentConfig().IsDoNotTrack() {
+		return
+	}
 	installFile := filepath.Join(config.CurrentConfig().CliSettings().DefaultBinaryInstallPath(), installFilename)
 	_, err := os.Stat(installFile)
 	if err == nil {
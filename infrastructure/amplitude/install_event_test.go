@@ -17,15 +17,22 @@
 package amplitude
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	segment "github.com/segmentio/analytics-go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/ampli"
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
 )
 
 var installEventFile = filepath.Join(config.CurrentConfig().CliSettings().DefaultBinaryInstallPath(), ".installed_event_sent")
@@ -66,6 +73,33 @@ func Test_NewInstallationSendsInstallEvent(t *testing.T) {
 	}, fakeSegmentClient.trackedEvents[0])
 }
 
+func Test_NewInstallationSendsInstallEventToConfiguredAnalyticsEndpoint(t *testing.T) {
+	c := testutil.UnitTest(t)
+	var receivedPaths []string
+	var receivedBodies []string
+	mutex := sync.Mutex{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mutex.Lock()
+		defer mutex.Unlock()
+		receivedPaths = append(receivedPaths, r.URL.Path)
+		receivedBodies = append(receivedBodies, string(body))
+	}))
+	defer server.Close()
+	c.SetAnalyticsEndpoint(server.URL + "/telemetry")
+	s := NewAmplitudeClient(func() (string, error) { return "fakeUser", nil }, error_reporting.NewTestErrorReporter()).(*Client)
+	cleanupInstallEventFile(t)
+
+	s.captureInstalledEvent()
+	require.NoError(t, s.Shutdown())
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Len(t, receivedPaths, 1)
+	assert.Equal(t, "/telemetry/v1/batch", receivedPaths[0])
+	assert.Contains(t, receivedBodies[0], "Plugin Is Installed")
+}
+
 func Test_ExistingInstallationDoesntSendInstallEvent(t *testing.T) {
 	s, fakeSegmentClient, _ := setupUnitTest(t)
 	cleanupInstallEventFile(t)
@@ -82,6 +116,19 @@ func Test_ExistingInstallationDoesntSendInstallEvent(t *testing.T) {
 	assert.Len(t, fakeSegmentClient.trackedEvents, 0)
 }
 
+func Test_DoNotTrackDoesntSendInstallEvent(t *testing.T) {
+	s, fakeSegmentClient, conf := setupUnitTest(t)
+	conf.SetTelemetryEnabled(true)
+	t.Setenv(config.DoNotTrack, "1")
+	cleanupInstallEventFile(t)
+
+	s.captureInstalledEvent()
+
+	assert.Len(t, fakeSegmentClient.trackedEvents, 0)
+	_, err := os.Stat(installEventFile)
+	assert.True(t, os.IsNotExist(err), "the install marker must not be written")
+}
+
 func cleanupInstallEventFile(t *testing.T) {
 	err := os.Remove(installEventFile)
 	if err != nil && !os.IsNotExist(err) {
@@ -1,6 +1,8 @@
 package amplitude
 
 import (
+	"sync"
+
 	"github.com/rs/zerolog/log"
 	segment "github.com/segmentio/analytics-go"
 
@@ -12,7 +14,10 @@ import (
 
 // Segment plugin allows events delivery to Segment via Amplitude Data.
 type SegmentPlugin struct {
+	mutex  sync.Mutex
 	client segment.Client
+	// endpoint is the endpoint the client sends to, the client is recreated when the configured endpoint changes
+	endpoint string
 }
 
 func NewSegmentPlugin() *SegmentPlugin {
@@ -21,19 +26,49 @@ func NewSegmentPlugin() *SegmentPlugin {
 
 // Setup is called on plugin installation
 func (plugin *SegmentPlugin) Setup(config amplitude.Config) {
-	client, err := segment.NewWithConfig(getSegmentPublicKey(), segment.Config{Logger: &segmentLogger{}})
+	plugin.mutex.Lock()
+	defer plugin.mutex.Unlock()
+	plugin.endpoint = segmentEndpoint()
+	plugin.client = newSegmentClient(plugin.endpoint)
+}
+
+func newSegmentClient(endpoint string) segment.Client {
+	client, err := segment.NewWithConfig(getSegmentPublicKey(), segment.Config{
+		Endpoint: endpoint,
+		Logger:   &segmentLogger{},
+	})
 	if err != nil {
 		log.Error().Str("method", "NewSegmentClient").Err(err).Msg("Error creating Segment client")
 	}
+	return client
+}
 
-	plugin.client = client
+// currentClient returns the Segment client for the configured endpoint. The settings are applied after the plugin
+// was set up, so the client is recreated when the endpoint changed.
+func (plugin *SegmentPlugin) currentClient() segment.Client {
+	plugin.mutex.Lock()
+	defer plugin.mutex.Unlock()
+	endpoint := segmentEndpoint()
+	if plugin.client != nil && endpoint == plugin.endpoint {
+		return plugin.client
+	}
+	if plugin.client != nil {
+		// sends the events that were enqueued for the previous endpoint
+		if err := plugin.client.Close(); err != nil {
+			log.Warn().Err(err).Str("method", "currentClient").Msg("Couldn't close Segment client")
+		}
+	}
+	log.Debug().Str("method", "currentClient").Str("endpoint", endpoint).Msg("Creating Segment client")
+	plugin.endpoint = endpoint
+	plugin.client = newSegmentClient(endpoint)
+	return plugin.client
 }
 
-func (plugin SegmentPlugin) Name() string {
+func (plugin *SegmentPlugin) Name() string {
 	return "SegmentPlugin"
 }
 
-func (plugin SegmentPlugin) Type() amplitude.PluginType {
+func (plugin *SegmentPlugin) Type() amplitude.PluginType {
 	return amplitude.PluginTypeDestination
 }
 
@@ -54,7 +89,7 @@ func (plugin *SegmentPlugin) Execute(event *amplitude.Event) {
 }
 
 func (plugin *SegmentPlugin) track(userId string, event *types.Event, method string) {
-	err := plugin.client.Enqueue(segment.Track{
+	err := plugin.currentClient().Enqueue(segment.Track{
 		UserId:      userId,
 		Event:       event.EventType,
 		Properties:  event.EventProperties,
@@ -66,7 +101,7 @@ func (plugin *SegmentPlugin) track(userId string, event *types.Event, method str
 }
 
 func (plugin *SegmentPlugin) identify(userId string, method string) {
-	err := plugin.client.Enqueue(segment.Identify{
+	err := plugin.currentClient().Enqueue(segment.Identify{
 		AnonymousId: config.CurrentConfig().DeviceID(),
 		UserId:      userId,
 	})
@@ -76,9 +111,19 @@ func (plugin *SegmentPlugin) identify(userId string, method string) {
 }
 
 func (plugin *SegmentPlugin) Shutdown() error {
+	plugin.mutex.Lock()
+	defer plugin.mutex.Unlock()
 	return plugin.client.Close()
 }
 
+// segmentEndpoint returns the configured analytics endpoint, or the default Segment endpoint if none is configured
+func segmentEndpoint() string {
+	if endpoint := config.CurrentConfig().AnalyticsEndpoint(); endpoint != "" {
+		return endpoint
+	}
+	return segment.DefaultEndpoint
+}
+
 func getSegmentPublicKey() string {
 	if config.IsDevelopment() {
 		log.Info().Str("method", "getSegmentPublicKey").Msg("Configured segment client with dev key")
@@ -1,6 +1,8 @@
 package amplitude
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 
@@ -8,8 +10,10 @@ import (
 	"github.com/segmentio/analytics-go"
 	segment "github.com/segmentio/analytics-go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
 )
 
 func TestExecute_CapturesUserId(t *testing.T) {
@@ -47,9 +51,28 @@ func TestExecute_CallsIdentify(t *testing.T) {
 	assert.True(t, equal)
 }
 
+func TestExecute_SendsToTheAnalyticsEndpointConfiguredAfterSetup(t *testing.T) {
+	c := testutil.UnitTest(t)
+	receivedPaths := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPaths <- r.URL.Path
+	}))
+	defer server.Close()
+	plugin := NewSegmentPlugin()
+	plugin.Setup(amplitude.Config{})
+
+	c.SetAnalyticsEndpoint(server.URL + "/telemetry")
+	plugin.Execute(&amplitude.Event{UserID: "myUserId", EventType: "Analysis Is Triggered"})
+	require.NoError(t, plugin.Shutdown())
+
+	require.Len(t, receivedPaths, 1)
+	assert.Equal(t, "/telemetry/v1/batch", <-receivedPaths)
+}
+
 func setupPlugin() (*SegmentPlugin, *FakeSegmentClient) {
 	plugin := NewSegmentPlugin()
 	fakeSegmentClient := &FakeSegmentClient{mutex: &sync.Mutex{}}
 	plugin.client = fakeSegmentClient
+	plugin.endpoint = segmentEndpoint()
 	return plugin, fakeSegmentClient
 }
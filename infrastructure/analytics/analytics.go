@@ -17,6 +17,7 @@
 package analytics
 
 import (
+	"github.com/khulnasoft-lab/go-application-framework/pkg/configuration"
 	localworkflows "github.com/khulnasoft-lab/go-application-framework/pkg/local_workflows"
 	"github.com/khulnasoft-lab/go-application-framework/pkg/workflow"
 
@@ -43,7 +44,7 @@ func SendAnalyticsToAPI(c *config.Config, payload []byte) error {
 	_, err := engine.InvokeWithInputAndConfig(
 		localworkflows.WORKFLOWID_REPORT_ANALYTICS,
 		[]workflow.Data{inputData},
-		engine.GetConfiguration(),
+		analyticsConfiguration(c),
 	)
 
 	if err != nil {
@@ -52,3 +53,16 @@ func SendAnalyticsToAPI(c *config.Config, payload []byte) error {
 	}
 	return nil
 }
+
+// analyticsConfiguration returns the engine configuration, with the API URL replaced by the configured analytics
+// endpoint if there is one, so that scan analytics are sent to the same endpoint as the other telemetry
+func analyticsConfiguration(c *config.Config) configuration.Configuration {
+	conf := c.Engine().GetConfiguration()
+	endpoint := c.AnalyticsEndpoint()
+	if endpoint == "" {
+		return conf
+	}
+	conf = conf.Clone()
+	conf.Set(configuration.API_URL, endpoint)
+	return conf
+}
@@ -0,0 +1,95 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analytics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-application-framework/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_SendAnalyticsToAPI_SendsCustomHttpHeaders(t *testing.T) {
+	c := testutil.UnitTest(t)
+	var receivedHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = r.Header.Clone()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	c.SetToken("token")
+	c.SetOrganization("54125374-3f93-402e-b693-e0724794d71f")
+	c.UpdateApiEndpoints(server.URL)
+	c.SetAnalyticsEnabled(true)
+	c.SetCustomHttpHeaders(map[string]string{"x-gateway-route": "vulnmap", "X-Gateway-Auth": "secret"})
+	payload, err := json.Marshal(getExpectedBodyRequest())
+	require.NoError(t, err)
+
+	err = SendAnalyticsToAPI(c, payload)
+
+	require.NoError(t, err)
+	assert.Equal(t, "vulnmap", receivedHeaders.Get("X-Gateway-Route"))
+	assert.Equal(t, "secret", receivedHeaders.Get("X-Gateway-Auth"))
+}
+
+func Test_SendAnalyticsToAPI_UsesConfiguredAnalyticsEndpoint(t *testing.T) {
+	c := testutil.UnitTest(t)
+	var receivedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	c.SetToken("token")
+	c.SetOrganization("54125374-3f93-402e-b693-e0724794d71f")
+	c.UpdateApiEndpoints("https://api.vulnmap.io")
+	c.SetAnalyticsEnabled(true)
+	c.SetAnalyticsEndpoint(server.URL + "/telemetry/")
+	payload, err := json.Marshal(getExpectedBodyRequest())
+	require.NoError(t, err)
+
+	err = SendAnalyticsToAPI(c, payload)
+
+	require.NoError(t, err)
+	assert.Equal(t, "/telemetry/rest/api/orgs/54125374-3f93-402e-b693-e0724794d71f/analytics", receivedPath)
+	assert.Equal(t, "https://api.vulnmap.io", c.Engine().GetConfiguration().GetString(configuration.API_URL),
+		"the API URL of the engine must not change")
+}
+
+func Test_SendAnalyticsToAPI_RejectsInvalidPayload(t *testing.T) {
+	c := testutil.UnitTest(t)
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	c.SetOrganization("54125374-3f93-402e-b693-e0724794d71f")
+	c.UpdateApiEndpoints(server.URL)
+	c.SetAnalyticsEnabled(true)
+
+	err := SendAnalyticsToAPI(c, []byte(`{"data": {}}`))
+
+	assert.Error(t, err)
+	assert.False(t, requested)
+}
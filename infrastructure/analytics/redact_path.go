@@ -0,0 +1,59 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analytics
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/util"
+)
+
+// RedactPath returns the path as it may be included in outbound telemetry, e.g. analytics events or webhooks,
+// according to the configured path privacy level. Features sending paths must use it instead of the raw path.
+func RedactPath(c *config.Config, path string, folderPath string) string {
+	return redactPath(c.PathPrivacy(), path, folderPath)
+}
+
+func redactPath(level config.PathPrivacy, path string, folderPath string) string {
+	if path == "" {
+		return ""
+	}
+	switch level {
+	case config.PathPrivacyFull:
+		return path
+	case config.PathPrivacyRelative:
+		return relativePath(path, folderPath)
+	case config.PathPrivacyHashed:
+		return util.Hash([]byte(filepath.ToSlash(filepath.Clean(path))))
+	default:
+		return ""
+	}
+}
+
+// relativePath returns the slash-separated path relative to the folder. Paths outside the folder are reduced to their
+// file name, so that no directories outside the workspace are revealed.
+func relativePath(path string, folderPath string) string {
+	if folderPath != "" {
+		relative, err := filepath.Rel(folderPath, path)
+		if err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(relative)
+		}
+	}
+	return filepath.Base(path)
+}
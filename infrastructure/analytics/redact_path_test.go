@@ -0,0 +1,70 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analytics
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/util"
+)
+
+func Test_redactPath(t *testing.T) {
+	folderPath := filepath.Join(string(filepath.Separator)+"home", "user", "project")
+	path := filepath.Join(folderPath, "src", "app.js")
+
+	t.Run("full", func(t *testing.T) {
+		assert.Equal(t, path, redactPath(config.PathPrivacyFull, path, folderPath))
+	})
+
+	t.Run("relative", func(t *testing.T) {
+		assert.Equal(t, "src/app.js", redactPath(config.PathPrivacyRelative, path, folderPath))
+	})
+
+	t.Run("relative path outside of the folder is reduced to the file name", func(t *testing.T) {
+		outsidePath := filepath.Join(string(filepath.Separator)+"home", "user", "other", "app.js")
+
+		assert.Equal(t, "app.js", redactPath(config.PathPrivacyRelative, outsidePath, folderPath))
+	})
+
+	t.Run("hashed", func(t *testing.T) {
+		redacted := redactPath(config.PathPrivacyHashed, path, folderPath)
+
+		assert.Equal(t, util.Hash([]byte(filepath.ToSlash(path))), redacted)
+		assert.NotContains(t, redacted, "app.js")
+	})
+
+	t.Run("omitted", func(t *testing.T) {
+		assert.Empty(t, redactPath(config.PathPrivacyOmitted, path, folderPath))
+	})
+
+	t.Run("unknown level omits the path", func(t *testing.T) {
+		assert.Empty(t, redactPath("", path, folderPath))
+	})
+}
+
+func Test_RedactPath_UsesConfiguredLevel(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetPathPrivacy(config.PathPrivacyRelative)
+	folderPath := string(filepath.Separator) + "project"
+
+	assert.Equal(t, "package.json", RedactPath(c, filepath.Join(folderPath, "package.json"), folderPath))
+}
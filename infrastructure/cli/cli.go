@@ -17,15 +17,15 @@
 package cli
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/rs/zerolog/log"
-
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	noti "github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
@@ -44,6 +44,8 @@ type VulnmapCli struct {
 
 var Mutex = &sync.Mutex{}
 
+const orgParam = "--org="
+
 func NewExecutor(
 	authenticationService vulnmap.AuthenticationService,
 	errorReporter error_reporting.ErrorReporter,
@@ -68,8 +70,11 @@ type Executor interface {
 }
 
 func (c VulnmapCli) Execute(ctx context.Context, cmd []string, workingDir string) (resp []byte, err error) {
+	logger := config.CurrentConfig().SubsystemLogger(config.LogSubsystemCli)
 	method := "VulnmapCli.Execute"
-	log.Debug().Str("method", method).Interface("cmd", cmd).Str("workingDir", workingDir).Msg("calling Vulnmap CLI")
+	logger.Debug().Str("method", method).Interface("cmd", cmd).Str("workingDir", workingDir).Msg("calling Vulnmap CLI")
+
+	clearScanOutput(workingDir)
 
 	// set deadline to handle CLI hanging when obtaining semaphore
 	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(c.cliTimeout))
@@ -84,24 +89,35 @@ func (c VulnmapCli) Execute(ctx context.Context, cmd []string, workingDir string
 	}
 
 	output, err := c.doExecute(ctx, cmd, workingDir)
-	log.Trace().Str("method", method).Str("response", string(output))
+	retainScanOutput(workingDir, cmd, output, err)
+	logger.Trace().Str("method", method).Str("response", string(output))
 	return output, err
 }
 
 func (c VulnmapCli) doExecute(ctx context.Context, cmd []string, workingDir string) ([]byte, error) {
 	command := c.getCommand(cmd, workingDir, ctx)
+	var stderr bytes.Buffer
+	command.Stderr = &stderr
 	output, err := command.Output()
-	return output, err
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		// Output only captures stderr in the error if it isn't redirected
+		exitError.Stderr = stderr.Bytes()
+	}
+	reportStderrWarnings(ctx, stderr.Bytes(), err)
+	return output, toOutOfMemoryError(ctx, err, stderr.Bytes())
 }
 
 func (c VulnmapCli) getCommand(cmd []string, workingDir string, ctx context.Context) *exec.Cmd {
+	logger := config.CurrentConfig().SubsystemLogger(config.LogSubsystemCli)
 	command := exec.CommandContext(ctx, cmd[0], cmd[1:]...)
 	command.Dir = workingDir
 	cliEnv := AppendCliEnvironmentVariables(os.Environ(), true)
-	command.Env = cliEnv
-	log.Trace().Str("method", "getCommand").Interface("command.Args", command.Args).Send()
-	log.Trace().Str("method", "getCommand").Interface("command.Env", command.Env).Send()
-	log.Trace().Str("method", "getCommand").Interface("command.Dir", command.Dir).Send()
+	folderEnv := vulnmap.EnvFromContext(ctx)
+	command.Env = appendFolderEnvironment(appendResourceLimits(appendRegistryCertificates(cliEnv)), folderEnv)
+	logger.Trace().Str("method", "getCommand").Interface("command.Args", command.Args).Send()
+	logger.Trace().Str("method", "getCommand").Interface("command.Env", redactFolderEnvironment(command.Env, folderEnv)).Send()
+	logger.Trace().Str("method", "getCommand").Interface("command.Dir", command.Dir).Send()
 	return command
 }
 
@@ -116,12 +132,28 @@ func expandParametersFromConfig(base []string) []string {
 
 	org := conf.Organization()
 	if org != "" {
-		expandedParams = append(expandedParams, "--org="+org)
+		expandedParams = append(expandedParams, orgParam+org)
 	}
 
 	return expandedParams
 }
 
+// WithOrganization replaces the organization of the command with the given one, e.g. the organization override of
+// a workspace folder. The command is returned unchanged if no organization is given.
+func WithOrganization(cmd []string, org string) []string {
+	if org == "" {
+		return cmd
+	}
+	withOrg := make([]string, 0, len(cmd)+1)
+	for _, param := range cmd {
+		if strings.HasPrefix(param, orgParam) {
+			continue
+		}
+		withOrg = append(withOrg, param)
+	}
+	return append(withOrg, orgParam+org)
+}
+
 // ExpandParametersFromConfig adds configuration parameters to the base command
 // todo no need to export that, we could have a simpler interface that looks more like an actual CLI
 func (c VulnmapCli) ExpandParametersFromConfig(base []string) []string {
@@ -129,10 +161,11 @@ func (c VulnmapCli) ExpandParametersFromConfig(base []string) []string {
 }
 
 func (c VulnmapCli) CliVersion() string {
+	logger := config.CurrentConfig().SubsystemLogger(config.LogSubsystemCli)
 	cmd := []string{"version"}
 	output, err := c.Execute(context.Background(), cmd, "")
 	if err != nil {
-		log.Error().Err(err).Msg("failed to run version command")
+		logger.Error().Err(err).Msg("failed to run version command")
 		return ""
 	}
 
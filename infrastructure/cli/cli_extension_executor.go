@@ -47,6 +47,8 @@ func (c ExtensionExecutor) Execute(ctx context.Context, cmd []string, workingDir
 	method := "ExtensionExecutor.Execute"
 	log.Debug().Str("method", method).Interface("cmd", cmd[1:]).Str("workingDir", workingDir).Msg("calling legacycli extension")
 
+	clearScanOutput(workingDir)
+
 	// set deadline to handle CLI hanging when obtaining semaphore
 	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(c.cliTimeout))
 	defer cancel()
@@ -60,6 +62,7 @@ func (c ExtensionExecutor) Execute(ctx context.Context, cmd []string, workingDir
 	}
 
 	output, err := c.doExecute(ctx, cmd, workingDir)
+	retainScanOutput(workingDir, cmd, output, err)
 	log.Trace().Str("method", method).Str("response", string(output))
 	return output, err
 }
@@ -18,6 +18,7 @@ package cli
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/adrg/xdg"
@@ -25,6 +26,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
 )
 
@@ -47,6 +49,28 @@ func Test_ExpandParametersFromConfig(t *testing.T) {
 	assert.Contains(t, cmd, "--org="+testOrg.String())
 }
 
+func Test_WithOrganization_FolderOverrideReplacesGlobalOrg(t *testing.T) {
+	testutil.UnitTest(t)
+	globalOrg, teamOrg := uuid.NewString(), uuid.NewString()
+	config.CurrentConfig().SetOrganization(globalOrg)
+	folderWithOverride := vulnmap.ContextWithFolderConfig(context.Background(), &vulnmap.FolderConfig{Organization: teamOrg})
+	folderWithoutOverride := vulnmap.ContextWithFolderConfig(context.Background(), &vulnmap.FolderConfig{})
+
+	overridden := WithOrganization(VulnmapCli{}.ExpandParametersFromConfig([]string{"a"}), vulnmap.OrganizationFromContext(folderWithOverride))
+	global := WithOrganization(VulnmapCli{}.ExpandParametersFromConfig([]string{"a"}), vulnmap.OrganizationFromContext(folderWithoutOverride))
+
+	assert.Equal(t, []string{"a", "--org=" + teamOrg}, overridden)
+	assert.Equal(t, []string{"a", "--org=" + globalOrg}, global)
+}
+
+func Test_WithOrganization_AddsOrgWithoutGlobalOrg(t *testing.T) {
+	testutil.UnitTest(t)
+
+	cmd := WithOrganization(VulnmapCli{}.ExpandParametersFromConfig([]string{"a"}), "team-org")
+
+	assert.Equal(t, []string{"a", "--org=team-org"}, cmd)
+}
+
 func TestGetCommand_AddsToEnvironmentAndSetsDir(t *testing.T) {
 	testutil.UnitTest(t)
 	config.CurrentConfig().SetTelemetryEnabled(false)
@@ -56,3 +80,69 @@ func TestGetCommand_AddsToEnvironmentAndSetsDir(t *testing.T) {
 	assert.Equal(t, xdg.DataHome, cmd.Dir)
 	assert.Contains(t, cmd.Env, DisableAnalyticsEnvVar+"=1")
 }
+
+func TestGetCommand_AddsRegistryCertificatesToEnvironment(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetRegistryCertificates(map[string]string{
+		"npm":    "/certs/npm-registry.pem",
+		"pip":    "/certs/pypi-mirror.pem",
+		"cargo":  "/certs/crates.pem",
+		"Gradle": "/certs/gradle.pem",
+	})
+
+	cmd := VulnmapCli{}.getCommand([]string{"executable", "test"}, t.TempDir(), context.Background())
+
+	assert.Contains(t, cmd.Env, "NPM_CONFIG_CAFILE=/certs/npm-registry.pem")
+	assert.Contains(t, cmd.Env, "PIP_CERT=/certs/pypi-mirror.pem")
+	assert.NotContains(t, cmd.Env, "YARN_CA_FILE_PATH=/certs/npm-registry.pem")
+	for _, envVar := range cmd.Env {
+		assert.NotContains(t, envVar, "/certs/crates.pem")
+		assert.NotContains(t, envVar, "/certs/gradle.pem")
+	}
+	assert.Equal(t, []string{"executable", "test"}, cmd.Args, "certificates are passed via the environment only")
+}
+
+func TestGetCommand_WithoutRegistryCertificates(t *testing.T) {
+	testutil.UnitTest(t)
+	t.Setenv("PIP_CERT", "/etc/pip.pem")
+
+	cmd := VulnmapCli{}.getCommand([]string{"executable", "test"}, t.TempDir(), context.Background())
+
+	pipCerts := 0
+	for _, envVar := range cmd.Env {
+		if strings.HasPrefix(envVar, "PIP_CERT=") {
+			pipCerts++
+		}
+	}
+	assert.Equal(t, 1, pipCerts)
+	assert.Contains(t, cmd.Env, "PIP_CERT=/etc/pip.pem")
+}
+
+func TestGetCommand_AddsFolderEnvironmentOnlyForThatFolder(t *testing.T) {
+	testutil.UnitTest(t)
+	t.Setenv("NPM_TOKEN", "global-token")
+	folderCtx := vulnmap.ContextWithFolderConfig(context.Background(), &vulnmap.FolderConfig{
+		Env: map[string]string{"NPM_TOKEN": "folder-token", "PRIVATE_REGISTRY": "https://registry.example.com"},
+	})
+
+	folderCmd := VulnmapCli{}.getCommand([]string{"executable", "test"}, t.TempDir(), folderCtx)
+	otherCmd := VulnmapCli{}.getCommand([]string{"executable", "test"}, t.TempDir(), context.Background())
+
+	assert.Contains(t, folderCmd.Env, "NPM_TOKEN=folder-token")
+	assert.NotContains(t, folderCmd.Env, "NPM_TOKEN=global-token", "the folder value takes precedence")
+	assert.Contains(t, folderCmd.Env, "PRIVATE_REGISTRY=https://registry.example.com")
+	assert.Contains(t, otherCmd.Env, "NPM_TOKEN=global-token")
+	for _, envVar := range otherCmd.Env {
+		assert.NotContains(t, envVar, "folder-token")
+		assert.False(t, strings.HasPrefix(envVar, "PRIVATE_REGISTRY="))
+	}
+}
+
+func Test_redactFolderEnvironment(t *testing.T) {
+	env := []string{"PATH=/usr/bin", "NPM_TOKEN=folder-token"}
+
+	redacted := redactFolderEnvironment(env, map[string]string{"NPM_TOKEN": "folder-token"})
+
+	assert.Equal(t, []string{"PATH=/usr/bin", "NPM_TOKEN=***"}, redacted)
+	assert.Equal(t, "NPM_TOKEN=folder-token", env[1], "the command environment is unchanged")
+}
@@ -0,0 +1,72 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"sort"
+	"strings"
+)
+
+const redactedEnvValue = "***"
+
+// appendFolderEnvironment adds the environment variables of the scanned folder's config. Variables with the same
+// name are removed from the environment, so that the folder's values take precedence over the global ones.
+func appendFolderEnvironment(env []string, folderEnv map[string]string) []string {
+	if len(folderEnv) == 0 {
+		return env
+	}
+	merged := make([]string, 0, len(env)+len(folderEnv))
+	for _, envVar := range env {
+		if _, overridden := folderEnv[envVarName(envVar)]; overridden {
+			continue
+		}
+		merged = append(merged, envVar)
+	}
+
+	names := make([]string, 0, len(folderEnv))
+	for name := range folderEnv {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		merged = append(merged, name+"="+folderEnv[name])
+	}
+	return merged
+}
+
+// redactFolderEnvironment returns a copy of the environment in which the values of the folder's environment
+// variables are redacted, as they may contain secrets such as registry tokens
+func redactFolderEnvironment(env []string, folderEnv map[string]string) []string {
+	if len(folderEnv) == 0 {
+		return env
+	}
+	redacted := make([]string, len(env))
+	for i, envVar := range env {
+		name := envVarName(envVar)
+		if _, ok := folderEnv[name]; ok {
+			redacted[i] = name + "=" + redactedEnvValue
+			continue
+		}
+		redacted[i] = envVar
+	}
+	return redacted
+}
+
+func envVarName(envVar string) string {
+	name, _, _ := strings.Cut(envVar, "=")
+	return name
+}
@@ -19,11 +19,13 @@ package cli
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-version"
 	"github.com/rs/zerolog/log"
 	sglsp "github.com/sourcegraph/go-lsp"
 
@@ -34,6 +36,9 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
 )
 
+// SupportedCliVersions is the range of CLI versions whose output the language server is known to parse
+const SupportedCliVersions = ">= 1.1100.0, < 2.0.0"
+
 type Initializer struct {
 	errorReporter error_reporting.ErrorReporter
 	installer     install.Installer
@@ -189,12 +194,49 @@ func (i *Initializer) isOutdatedCli() bool {
 // logCliVersion runs the cli with `--version` and returns the version
 func (i *Initializer) logCliVersion(cliPath string) {
 	output, err := i.cli.Execute(context.Background(), []string{cliPath, "--version"}, "")
-	version := "unknown version"
+	cliVersion := "unknown version"
 	if err == nil && len(output) > 0 {
-		version = string(output)
-		version = strings.Trim(version, "\n")
+		cliVersion = string(output)
+		cliVersion = strings.Trim(cliVersion, "\n")
+		config.CurrentConfig().CliSettings().SetVersion(cliVersion)
+		i.checkCliVersion(cliVersion)
+	}
+	log.Info().Msg("vulnmap-cli: " + cliVersion + " (" + cliPath + ")")
+}
+
+// checkCliVersion warns the user if the installed CLI is outside the supported version range, as its output
+// might not be parsed correctly
+func (i *Initializer) checkCliVersion(cliVersion string) {
+	supported, err := IsSupportedCliVersion(cliVersion, SupportedCliVersions)
+	if err != nil {
+		log.Warn().Err(err).Str("method", "checkCliVersion").Msg("couldn't determine CLI version compatibility")
+		return
+	}
+	if !supported {
+		log.Warn().Str("method", "checkCliVersion").Msgf("CLI version %s is not supported (%s)", cliVersion,
+			SupportedCliVersions)
+		i.notifier.SendShowMessage(sglsp.Warning, fmt.Sprintf(
+			"The installed Vulnmap CLI version %s is not supported by this Vulnmap Language Server (supported: %s). "+
+				"Scan results may be incomplete.", cliVersion, SupportedCliVersions))
+	}
+}
+
+// IsSupportedCliVersion returns whether the version printed by `vulnmap --version`, e.g. "1.1234.0 (standalone)",
+// satisfies the given version constraints
+func IsSupportedCliVersion(cliVersion string, constraints string) (bool, error) {
+	fields := strings.Fields(cliVersion)
+	if len(fields) == 0 {
+		return false, errors.New("empty CLI version")
+	}
+	parsedVersion, err := version.NewVersion(fields[0])
+	if err != nil {
+		return false, err
+	}
+	parsedConstraints, err := version.NewConstraint(constraints)
+	if err != nil {
+		return false, err
 	}
-	log.Info().Msg("vulnmap-cli: " + version + " (" + cliPath + ")")
+	return parsedConstraints.Check(parsedVersion), nil
 }
 
 // cliPath is a single source of truth for the CLI path
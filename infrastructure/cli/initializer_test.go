@@ -231,6 +231,59 @@ func TestInitializer_whenBinaryUpdatesAllowed_Updates(t *testing.T) {
 	}, time.Second, time.Millisecond)
 }
 
+func Test_IsSupportedCliVersion(t *testing.T) {
+	testCases := []struct {
+		cliVersion string
+		supported  bool
+	}{
+		{cliVersion: "1.1100.0", supported: true},
+		{cliVersion: "1.1234.0 (standalone)", supported: true},
+		{cliVersion: "1.1099.9", supported: false},
+		{cliVersion: "2.0.0", supported: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.cliVersion, func(t *testing.T) {
+			supported, err := IsSupportedCliVersion(tc.cliVersion, SupportedCliVersions)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.supported, supported)
+		})
+	}
+}
+
+func Test_IsSupportedCliVersion_UnparsableVersionReturnsError(t *testing.T) {
+	_, err := IsSupportedCliVersion("unknown", SupportedCliVersions)
+	assert.Error(t, err)
+
+	_, err = IsSupportedCliVersion("", SupportedCliVersions)
+	assert.Error(t, err)
+}
+
+func Test_logCliVersion_WarnsAboutUnsupportedCliVersion(t *testing.T) {
+	testutil.UnitTest(t)
+	notifier := notification.NewMockNotifier()
+	initializer := NewInitializer(error_reporting.NewTestErrorReporter(),
+		install.NewFakeInstaller(),
+		notifier,
+		NewTestExecutorWithResponse("3.0.0\n"))
+
+	initializer.logCliVersion("vulnmap")
+
+	assert.Equal(t, 1, notifier.SendShowMessageCount())
+}
+
+func Test_logCliVersion_StoresCliVersion(t *testing.T) {
+	c := testutil.UnitTest(t)
+	initializer := NewInitializer(error_reporting.NewTestErrorReporter(),
+		install.NewFakeInstaller(),
+		notification.NewMockNotifier(),
+		NewTestExecutorWithResponse("1.1234.0 (standalone)\n"))
+
+	initializer.logCliVersion("vulnmap")
+
+	assert.Equal(t, "1.1234.0 (standalone)", c.CliSettings().Version())
+}
+
 func createDummyCliBinaryWithCreatedDate(t *testing.T, binaryCreationDate time.Time) {
 	// prepare user directory with OS specific dummy CLI binary
 	temp := t.TempDir()
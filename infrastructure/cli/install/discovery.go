@@ -21,6 +21,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/adrg/xdg"
 
@@ -30,27 +31,65 @@ import (
 
 const userDirName = "vulnmap-ls"
 
-type Discovery struct{}
+// CliNotFoundError is returned when the Vulnmap CLI executable cannot be found in any of the searched locations
+type CliNotFoundError struct {
+	SearchedLocations []string
+}
 
-// LookPath searches for the Vulnmap CLI executable in the directories named by the PATH environment variable.
-func (d *Discovery) LookPath() (string, error) {
-	path, err := exec.LookPath(filename.ExecutableName)
-	if err != nil {
-		return "", fmt.Errorf("unable to find %s in PATH: %s", filename.ExecutableName, err)
+func (e *CliNotFoundError) Error() string {
+	return fmt.Sprintf("unable to find %s, searched: %s", filename.ExecutableName, strings.Join(e.SearchedLocations, ", "))
+}
+
+// CliPathCandidates are the locations ResolveCliPath searches in addition to the PATH
+type CliPathCandidates struct {
+	// ConfiguredPath is the CLI path explicitly set in the configuration
+	ConfiguredPath string
+	// BundledDir is the directory the language server downloads the CLI to
+	BundledDir string
+}
+
+// DefaultCliPathCandidates returns the configured CLI path and the default download directory
+func DefaultCliPathCandidates() CliPathCandidates {
+	return CliPathCandidates{
+		ConfiguredPath: config.CurrentConfig().CliSettings().Path(),
+		BundledDir:     filepath.Join(xdg.DataHome, userDirName),
 	}
-	return path, nil
 }
 
-// LookUserDir searches for the Vulnmap CLI executable in the  XDG_DATA_HOME/vulnmap-ls directory.
-func (d *Discovery) LookUserDir() (string, error) {
-	folder := userDirName
-	path := filepath.Join(xdg.DataHome, folder, filename.ExecutableName)
-	if _, err := os.Stat(path); err == nil {
+// ResolveCliPath returns the path to the Vulnmap CLI executable. The configured path takes precedence over the
+// bundled CLI, which takes precedence over a CLI in the PATH. A *CliNotFoundError is returned if none exist.
+func ResolveCliPath(candidates CliPathCandidates) (string, error) {
+	var searched []string
+	if candidates.ConfiguredPath != "" {
+		if isFile(candidates.ConfiguredPath) {
+			return candidates.ConfiguredPath, nil
+		}
+		searched = append(searched, candidates.ConfiguredPath)
+	}
+
+	if candidates.BundledDir != "" {
+		bundledPath := filepath.Join(candidates.BundledDir, filename.ExecutableName)
+		if isFile(bundledPath) {
+			return bundledPath, nil
+		}
+		searched = append(searched, bundledPath)
+	}
+
+	path, err := exec.LookPath(filename.ExecutableName)
+	if err == nil {
 		return path, nil
 	}
-	return "", fmt.Errorf("unable to find %s in user directory", filename.ExecutableName)
+	searched = append(searched, "PATH")
+	return "", &CliNotFoundError{SearchedLocations: searched}
+}
+
+func isFile(path string) bool {
+	stat, err := os.Stat(path)
+	return err == nil && !stat.IsDir()
 }
 
+type Discovery struct{}
+
 // ExecutableName returns OS specific filename for Vulnmap CLI.
 func (d *Discovery) ExecutableName(isUpdate bool) string {
 	if isUpdate {
@@ -82,14 +121,3 @@ func (d *Discovery) ChecksumInfo(r *Release) (string, error) {
 	}
 	return r.checksumInfo(), nil
 }
-
-func (d *Discovery) LookConfigPath() (string, error) {
-	cliPath := config.CurrentConfig().CliSettings().Path()
-	if file, err := os.Stat(cliPath); err == nil {
-		if !file.IsDir() {
-			return cliPath, nil
-		}
-	}
-
-	return "", fmt.Errorf("unable to find CLI in %s", cliPath)
-}
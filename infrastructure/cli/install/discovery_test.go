@@ -17,9 +17,15 @@
 package install
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/cli/filename"
 )
 
 func TestDiscovery_DownloadURL(t *testing.T) {
@@ -98,3 +104,68 @@ func TestDiscovery_ChecksumURL_nilRelease(t *testing.T) {
 	assert.Error(t, err)
 	assert.Empty(t, url)
 }
+
+func createFakeExecutable(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, filename.ExecutableName)
+	require.NoError(t, os.WriteFile(path, []byte("fake cli"), 0700))
+	return path
+}
+
+func TestResolveCliPath(t *testing.T) {
+	t.Run("prefers configured path", func(t *testing.T) {
+		configuredPath := createFakeExecutable(t, t.TempDir())
+		bundledDir := t.TempDir()
+		createFakeExecutable(t, bundledDir)
+		pathDir := t.TempDir()
+		createFakeExecutable(t, pathDir)
+		t.Setenv("PATH", pathDir)
+
+		path, err := ResolveCliPath(CliPathCandidates{ConfiguredPath: configuredPath, BundledDir: bundledDir})
+
+		assert.NoError(t, err)
+		assert.Equal(t, configuredPath, path)
+	})
+
+	t.Run("falls back to bundled CLI", func(t *testing.T) {
+		bundledDir := t.TempDir()
+		bundledPath := createFakeExecutable(t, bundledDir)
+		pathDir := t.TempDir()
+		createFakeExecutable(t, pathDir)
+		t.Setenv("PATH", pathDir)
+
+		path, err := ResolveCliPath(CliPathCandidates{
+			ConfiguredPath: filepath.Join(t.TempDir(), "missing"),
+			BundledDir:     bundledDir,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, bundledPath, path)
+	})
+
+	t.Run("falls back to PATH", func(t *testing.T) {
+		pathDir := t.TempDir()
+		pathCli := createFakeExecutable(t, pathDir)
+		t.Setenv("PATH", pathDir)
+
+		path, err := ResolveCliPath(CliPathCandidates{
+			ConfiguredPath: filepath.Join(t.TempDir(), "missing"),
+			BundledDir:     t.TempDir(),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, pathCli, path)
+	})
+
+	t.Run("returns CliNotFoundError if no CLI exists", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		configuredPath := filepath.Join(t.TempDir(), "missing")
+
+		_, err := ResolveCliPath(CliPathCandidates{ConfiguredPath: configuredPath, BundledDir: t.TempDir()})
+
+		var notFoundError *CliNotFoundError
+		require.True(t, errors.As(err, &notFoundError))
+		assert.Contains(t, notFoundError.SearchedLocations, configuredPath)
+		assert.Contains(t, notFoundError.SearchedLocations, "PATH")
+	})
+}
@@ -53,20 +53,7 @@ func NewInstaller(errorReporter error_reporting.ErrorReporter, client func() *ht
 }
 
 func (i *Install) Find() (string, error) {
-	d := &Discovery{}
-	execPath, _ := d.LookConfigPath()
-	if execPath != "" {
-		return execPath, nil
-	}
-	execPath, _ = d.LookUserDir()
-	if execPath != "" {
-		return execPath, nil
-	}
-	execPath, err := d.LookPath()
-	if err != nil {
-		return "", err
-	}
-	return execPath, nil
+	return ResolveCliPath(DefaultCliPathCandidates())
 }
 
 func (i *Install) Install(ctx context.Context) (string, error) {
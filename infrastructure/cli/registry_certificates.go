@@ -0,0 +1,58 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"sort"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+)
+
+// registryCertificateEnvVars are the environment variables that make the package managers invoked by the CLI
+// trust an additional CA certificate file
+var registryCertificateEnvVars = map[string][]string{
+	"npm":  {"NPM_CONFIG_CAFILE"},
+	"yarn": {"YARN_CA_FILE_PATH", "NPM_CONFIG_CAFILE"},
+	"pip":  {"PIP_CERT"},
+}
+
+// appendRegistryCertificates adds the environment variables for the configured registry certificates, so that
+// dependencies can be resolved from private registries with self-signed certificates without disabling TLS
+// verification. As the variables are appended, they take precedence over the ones set in the environment.
+func appendRegistryCertificates(env []string) []string {
+	certificates := config.CurrentConfig().RegistryCertificates()
+	packageManagers := make([]string, 0, len(certificates))
+	for packageManager := range certificates {
+		packageManagers = append(packageManagers, packageManager)
+	}
+	sort.Strings(packageManagers)
+
+	for _, packageManager := range packageManagers {
+		envVars, ok := registryCertificateEnvVars[packageManager]
+		if !ok {
+			log.Debug().Str("method", "appendRegistryCertificates").
+				Msgf("ignoring registry certificate of unsupported package manager %s", packageManager)
+			continue
+		}
+		for _, envVar := range envVars {
+			env = append(env, envVar+"="+certificates[packageManager])
+		}
+	}
+	return env
+}
@@ -0,0 +1,101 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+)
+
+const (
+	nodeOptionsEnvVar   = "NODE_OPTIONS"
+	maxOldSpaceSizeFlag = "--max-old-space-size"
+	// killedExitCode is the exit code of a process killed with SIGKILL as reported by a shell, which is how the OOM
+	// killer ends processes
+	killedExitCode = 128 + 9
+	// nodeHeapExhaustedMessage is printed by Node.js when the heap limit is reached
+	nodeHeapExhaustedMessage = "JavaScript heap out of memory"
+)
+
+// OutOfMemoryError is returned when the CLI was killed or aborted because it ran out of memory
+type OutOfMemoryError struct {
+	// MaxMemoryMB is the configured heap limit of the CLI, 0 if the CLI's default was used
+	MaxMemoryMB int
+	Err         error
+}
+
+func (e *OutOfMemoryError) Error() string {
+	if e.MaxMemoryMB > 0 {
+		return fmt.Sprintf("the Vulnmap CLI ran out of memory with a limit of %d MB. "+
+			"Raise the CLI max memory setting to scan this project", e.MaxMemoryMB)
+	}
+	return "the Vulnmap CLI ran out of memory. Set a higher CLI max memory setting to scan this project"
+}
+
+func (e *OutOfMemoryError) Unwrap() error {
+	return e.Err
+}
+
+// appendResourceLimits sets the configured heap limit of the CLI in NODE_OPTIONS. Other options that are already
+// set in the environment are kept, a heap limit in them is replaced.
+func appendResourceLimits(env []string) []string {
+	maxMemoryMB := config.CurrentConfig().CliMaxMemoryMB()
+	if maxMemoryMB <= 0 {
+		return env
+	}
+	var nodeOptions []string
+	limited := make([]string, 0, len(env)+1)
+	for _, envVar := range env {
+		if envVarName(envVar) != nodeOptionsEnvVar {
+			limited = append(limited, envVar)
+			continue
+		}
+		_, value, _ := strings.Cut(envVar, "=")
+		for _, option := range strings.Fields(value) {
+			if option == maxOldSpaceSizeFlag || strings.HasPrefix(option, maxOldSpaceSizeFlag+"=") {
+				continue
+			}
+			nodeOptions = append(nodeOptions, option)
+		}
+	}
+	nodeOptions = append(nodeOptions, maxOldSpaceSizeFlag+"="+strconv.Itoa(maxMemoryMB))
+	return append(limited, nodeOptionsEnvVar+"="+strings.Join(nodeOptions, " "))
+}
+
+// toOutOfMemoryError returns an OutOfMemoryError if the CLI was killed by the OOM killer or exhausted its heap, and
+// the unchanged error otherwise. A CLI killed because the context was cancelled is not out of memory.
+func toOutOfMemoryError(ctx context.Context, err error, stderr []byte) error {
+	var exitError *exec.ExitError
+	if !errors.As(err, &exitError) || ctx.Err() != nil {
+		return err
+	}
+	killed := exitError.ExitCode() == killedExitCode
+	if status, ok := exitError.Sys().(syscall.WaitStatus); ok && status.Signaled() && status.Signal() == syscall.SIGKILL {
+		killed = true
+	}
+	if !killed && !strings.Contains(string(stderr), nodeHeapExhaustedMessage) {
+		return err
+	}
+	return &OutOfMemoryError{MaxMemoryMB: config.CurrentConfig().CliMaxMemoryMB(), Err: err}
+}
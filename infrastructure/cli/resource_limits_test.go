@@ -0,0 +1,123 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func nodeOptions(env []string) []string {
+	var options []string
+	for _, envVar := range env {
+		if strings.HasPrefix(envVar, nodeOptionsEnvVar+"=") {
+			options = append(options, envVar)
+		}
+	}
+	return options
+}
+
+func TestGetCommand_SetsCliMaxMemoryInNodeOptions(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetCliMaxMemoryMB(8192)
+	t.Setenv(nodeOptionsEnvVar, "--trace-warnings --max-old-space-size=1024")
+
+	cmd := VulnmapCli{}.getCommand([]string{"executable", "test"}, t.TempDir(), context.Background())
+
+	assert.Equal(t, []string{"NODE_OPTIONS=--trace-warnings --max-old-space-size=8192"}, nodeOptions(cmd.Env))
+}
+
+func TestGetCommand_WithoutCliMaxMemoryKeepsNodeOptions(t *testing.T) {
+	testutil.UnitTest(t)
+	t.Setenv(nodeOptionsEnvVar, "--max-old-space-size=1024")
+
+	cmd := VulnmapCli{}.getCommand([]string{"executable", "test"}, t.TempDir(), context.Background())
+
+	assert.Equal(t, []string{"NODE_OPTIONS=--max-old-space-size=1024"}, nodeOptions(cmd.Env))
+}
+
+func runShell(t *testing.T, script string) ([]byte, error) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	cmd := exec.Command("sh", "-c", script)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return []byte(stderr.String()), err
+}
+
+func Test_toOutOfMemoryError(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetCliMaxMemoryMB(4096)
+
+	t.Run("killed by the OOM killer", func(t *testing.T) {
+		stderr, err := runShell(t, "kill -9 $$")
+
+		var oomError *OutOfMemoryError
+		require.ErrorAs(t, toOutOfMemoryError(context.Background(), err, stderr), &oomError)
+		assert.Equal(t, 4096, oomError.MaxMemoryMB)
+		assert.Contains(t, oomError.Error(), "Raise the CLI max memory setting")
+		var exitError *exec.ExitError
+		assert.ErrorAs(t, oomError, &exitError, "the exit error is still available")
+	})
+
+	t.Run("exit code of a killed child process", func(t *testing.T) {
+		stderr, err := runShell(t, "exit 137")
+
+		var oomError *OutOfMemoryError
+		assert.ErrorAs(t, toOutOfMemoryError(context.Background(), err, stderr), &oomError)
+	})
+
+	t.Run("Node.js heap exhausted", func(t *testing.T) {
+		stderr, err := runShell(t, "echo 'FATAL ERROR: Reached heap limit Allocation failed - JavaScript heap out of memory' >&2; exit 134")
+
+		var oomError *OutOfMemoryError
+		assert.ErrorAs(t, toOutOfMemoryError(context.Background(), err, stderr), &oomError)
+	})
+
+	t.Run("killed because the context was cancelled", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("requires a POSIX shell")
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cmd := exec.CommandContext(ctx, "sh", "-c", "sleep 10")
+		require.NoError(t, cmd.Start())
+		cancel()
+		err := cmd.Wait()
+
+		assert.Same(t, err, toOutOfMemoryError(ctx, err, nil))
+	})
+
+	t.Run("other failures are unchanged", func(t *testing.T) {
+		stderr, err := runShell(t, "echo 'no supported projects' >&2; exit 3")
+
+		assert.Same(t, err, toOutOfMemoryError(context.Background(), err, stderr))
+		otherErr := errors.New("not started")
+		assert.Same(t, otherErr, toOutOfMemoryError(context.Background(), otherErr, nil))
+	})
+}
@@ -0,0 +1,142 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+const (
+	// MaxRetainedOutputSize is the maximum number of bytes retained per output stream of a CLI run
+	MaxRetainedOutputSize = 1024 * 1024
+	// maxRetainedOutputs is the maximum number of working directories whose last CLI output is retained
+	maxRetainedOutputs = 20
+	redactedValue      = "***"
+)
+
+// secretPattern matches the values of sensitive keys in JSON or key=value output, e.g. "token": "abc"
+var secretPattern = regexp.MustCompile(`(?i)("?(?:token|password|secret|api[_-]?key|authorization)"?\s*[:=]\s*"?)([^"\s,}]+)`)
+
+// ScanOutput is the raw output of the last CLI run in a working directory, retained for bug reports
+type ScanOutput struct {
+	WorkingDir string    `json:"workingDir"`
+	Command    []string  `json:"command"`
+	Stdout     string    `json:"stdout"`
+	Stderr     string    `json:"stderr"`
+	Truncated  bool      `json:"truncated"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+var (
+	scanOutputsMutex sync.Mutex
+	scanOutputs      = map[string]ScanOutput{}
+)
+
+// clearScanOutput drops the retained output of the working directory when a new CLI run starts
+func clearScanOutput(workingDir string) {
+	scanOutputsMutex.Lock()
+	defer scanOutputsMutex.Unlock()
+	delete(scanOutputs, workingDir)
+}
+
+// retainScanOutput stores the redacted and size-capped output of a CLI run. Runs without a working directory, e.g.
+// version checks, are not scans and are not retained.
+func retainScanOutput(workingDir string, cmd []string, stdout []byte, err error) {
+	if workingDir == "" {
+		return
+	}
+	var stderr []byte
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		stderr = exitError.Stderr
+	}
+	stdoutText, stdoutTruncated := capOutput(stdout)
+	stderrText, stderrTruncated := capOutput(stderr)
+	output := ScanOutput{
+		WorkingDir: workingDir,
+		Command:    redactCommand(cmd),
+		Stdout:     redactSecrets(stdoutText),
+		Stderr:     redactSecrets(stderrText),
+		Truncated:  stdoutTruncated || stderrTruncated,
+		Timestamp:  time.Now(),
+	}
+
+	scanOutputsMutex.Lock()
+	defer scanOutputsMutex.Unlock()
+	scanOutputs[workingDir] = output
+	if len(scanOutputs) > maxRetainedOutputs {
+		evictOldestScanOutput()
+	}
+}
+
+func evictOldestScanOutput() {
+	oldest := ""
+	for workingDir, output := range scanOutputs {
+		if oldest == "" || output.Timestamp.Before(scanOutputs[oldest].Timestamp) {
+			oldest = workingDir
+		}
+	}
+	delete(scanOutputs, oldest)
+}
+
+// LastScanOutput returns the most recent retained output of a CLI run in the folder or one of its subdirectories
+func LastScanOutput(folderPath string) (ScanOutput, bool) {
+	scanOutputsMutex.Lock()
+	defer scanOutputsMutex.Unlock()
+	var last ScanOutput
+	found := false
+	for workingDir, output := range scanOutputs {
+		if !uri.FolderContains(folderPath, workingDir) {
+			continue
+		}
+		if !found || output.Timestamp.After(last.Timestamp) {
+			last = output
+			found = true
+		}
+	}
+	return last, found
+}
+
+func capOutput(output []byte) (string, bool) {
+	if len(output) > MaxRetainedOutputSize {
+		return string(output[:MaxRetainedOutputSize]), true
+	}
+	return string(output), false
+}
+
+func redactSecrets(text string) string {
+	if token := config.CurrentConfig().Token(); token != "" {
+		text = strings.ReplaceAll(text, token, redactedValue)
+	}
+	return secretPattern.ReplaceAllString(text, "${1}"+redactedValue)
+}
+
+func redactCommand(cmd []string) []string {
+	redacted := make([]string, len(cmd))
+	for i, param := range cmd {
+		redacted[i] = redactSecrets(param)
+	}
+	return redacted
+}
@@ -0,0 +1,100 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func resetScanOutputs(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		scanOutputsMutex.Lock()
+		defer scanOutputsMutex.Unlock()
+		scanOutputs = map[string]ScanOutput{}
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func Test_LastScanOutput_ReturnsRetainedOutputOfFolder(t *testing.T) {
+	testutil.UnitTest(t)
+	resetScanOutputs(t)
+	folder := t.TempDir()
+	retainScanOutput(filepath.Join(folder, "backend"), []string{"vulnmap", "test"}, []byte(`{"ok": true}`), nil)
+
+	output, found := LastScanOutput(folder)
+
+	require.True(t, found)
+	assert.Equal(t, `{"ok": true}`, output.Stdout)
+	assert.Equal(t, []string{"vulnmap", "test"}, output.Command)
+	assert.False(t, output.Truncated)
+	_, found = LastScanOutput(t.TempDir())
+	assert.False(t, found)
+}
+
+func Test_LastScanOutput_IsCapped(t *testing.T) {
+	testutil.UnitTest(t)
+	resetScanOutputs(t)
+	folder := t.TempDir()
+	retainScanOutput(folder, []string{"vulnmap", "test"}, []byte(strings.Repeat("a", MaxRetainedOutputSize+1)), nil)
+
+	output, found := LastScanOutput(folder)
+
+	require.True(t, found)
+	assert.Len(t, output.Stdout, MaxRetainedOutputSize)
+	assert.True(t, output.Truncated)
+}
+
+func Test_LastScanOutput_IsRedacted(t *testing.T) {
+	c := testutil.UnitTest(t)
+	resetScanOutputs(t)
+	c.SetToken("secret-token-value")
+	folder := t.TempDir()
+	retainScanOutput(folder, []string{"vulnmap", "test", "--token=secret-token-value"},
+		[]byte(`{"apiKey": "abc123", "used": "secret-token-value"}`), nil)
+
+	output, _ := LastScanOutput(folder)
+
+	assert.NotContains(t, output.Stdout, "abc123")
+	assert.NotContains(t, output.Stdout, "secret-token-value")
+	assert.NotContains(t, strings.Join(output.Command, " "), "secret-token-value")
+}
+
+func Test_LastScanOutput_IsClearedOnNewRunAndBounded(t *testing.T) {
+	testutil.UnitTest(t)
+	resetScanOutputs(t)
+	folder := t.TempDir()
+	retainScanOutput(folder, []string{"vulnmap", "test"}, []byte("output"), nil)
+
+	clearScanOutput(folder)
+
+	_, found := LastScanOutput(folder)
+	assert.False(t, found)
+
+	for i := 0; i < maxRetainedOutputs+5; i++ {
+		retainScanOutput(t.TempDir(), []string{"vulnmap", "test"}, []byte("output"), nil)
+	}
+	assert.Len(t, scanOutputs, maxRetainedOutputs)
+}
@@ -0,0 +1,54 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// maxStderrWarnings is the maximum number of stderr lines reported as warnings per CLI run
+const maxStderrWarnings = 50
+
+// reportStderrWarnings reports the redacted stderr lines of a successful CLI run as scan warnings, e.g. projects that
+// couldn't be resolved during an all projects scan. Exit code 1 means that issues were found and is a success, too.
+// The stderr of failed runs is part of their error and isn't reported again.
+func reportStderrWarnings(ctx context.Context, stderr []byte, err error) {
+	if err != nil {
+		var exitError *exec.ExitError
+		if !errors.As(err, &exitError) || exitError.ExitCode() != 1 {
+			return
+		}
+	}
+	reported := 0
+	for _, line := range strings.Split(string(stderr), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if reported == maxStderrWarnings {
+			vulnmap.AddScanWarning(ctx, "further warnings were omitted")
+			return
+		}
+		vulnmap.AddScanWarning(ctx, redactSecrets(line))
+		reported++
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -201,7 +202,7 @@ func (b *Bundle) addIssueActions(ctx context.Context, issues []vulnmap.Issue) {
 		}
 
 		if learnEnabled {
-			action := b.createOpenVulnmapLearnCodeAction(issues[i])
+			action := b.createOpenVulnmapLearnCodeAction(ctx, &issues[i])
 			if action != nil {
 				issues[i].CodeActions = append(issues[i].CodeActions, *action)
 			}
@@ -349,9 +350,11 @@ func (b *Bundle) createDeferredAutofixCodeAction(ctx context.Context, issue vuln
 	return &action
 }
 
-func (b *Bundle) createOpenVulnmapLearnCodeAction(issue vulnmap.Issue) (ca *vulnmap.CodeAction) {
-	title := fmt.Sprintf("Learn more about %s (Vulnmap)", issueTitle(issue))
-	lesson, err := b.learnService.GetLesson(issue.Ecosystem, issue.ID, issue.CWEs, issue.CVEs, issue.IssueType)
+// createOpenVulnmapLearnCodeAction returns an action opening the lesson about the issue and sets the lesson url of
+// the issue, nil if there is no lesson
+func (b *Bundle) createOpenVulnmapLearnCodeAction(ctx context.Context, issue *vulnmap.Issue) (ca *vulnmap.CodeAction) {
+	title := fmt.Sprintf("Learn more about %s (Vulnmap)", issueTitle(*issue))
+	lesson, err := b.learnService.GetLesson(ctx, issue.Ecosystem, issue.ID, issue.CWEs, issue.CVEs, issue.IssueType)
 	if err != nil {
 		log.Err(err).Msg("failed to get lesson")
 		b.errorReporter.CaptureError(err)
@@ -367,6 +370,7 @@ func (b *Bundle) createOpenVulnmapLearnCodeAction(issue vulnmap.Issue) (ca *vuln
 				Arguments: []any{lesson.Url},
 			},
 		}
+		issue.LessonURL, _ = url.Parse(lesson.Url)
 	}
 	return ca
 }
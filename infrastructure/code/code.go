@@ -111,6 +111,13 @@ func (sc *Scanner) Product() product.Product {
 	return product.ProductCode
 }
 
+// SupportsFile returns true if the file has an extension or is a config file supported by the Vulnmap Code backend.
+// Files are considered supported if the supported files can't be determined, so that the scan reports the error.
+func (sc *Scanner) SupportsFile(ctx context.Context, path string) bool {
+	supported, err := sc.BundleUploader.isSupported(ctx, path)
+	return err != nil || supported
+}
+
 func (sc *Scanner) SupportedCommands() []vulnmap.CommandName {
 	return []vulnmap.CommandName{vulnmap.NavigateToRangeCommand}
 }
@@ -0,0 +1,92 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package code
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// addContextHashes sets the context hashes of the Code issues, which identify the affected code independent of its
+// position. Identical code violating the same rule several times in a file is told apart by its order of occurrence.
+// Issues in files that can't be read keep an empty context hash.
+func addContextHashes(issues []vulnmap.Issue) {
+	order := make([]int, len(issues))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return issues[order[a]].Range.Start.Line < issues[order[b]].Range.Start.Line
+	})
+
+	linesByFile := map[string][]string{}
+	occurrences := map[string]int{}
+	for _, i := range order {
+		issue := issues[i]
+		data, isCode := issue.AdditionalData.(vulnmap.CodeIssueData)
+		if !isCode {
+			continue
+		}
+		lines, read := linesByFile[issue.AffectedFilePath]
+		if !read {
+			content, err := os.ReadFile(issue.AffectedFilePath)
+			if err != nil {
+				log.Debug().Err(err).Str("method", "addContextHashes").Msg("can't read affected file")
+			}
+			lines = strings.Split(string(content), "\n")
+			if err != nil {
+				lines = nil
+			}
+			linesByFile[issue.AffectedFilePath] = lines
+		}
+		snippet, ok := affectedCode(lines, issue.Range)
+		if !ok {
+			continue
+		}
+		occurrenceKey := issue.ID + "|" + issue.AffectedFilePath + "|" + snippet
+		occurrence := occurrences[occurrenceKey]
+		occurrences[occurrenceKey]++
+
+		hash := sha256.Sum256([]byte(snippet + "|" + strconv.Itoa(occurrence)))
+		data.ContextHash = hex.EncodeToString(hash[:16])
+		issues[i].AdditionalData = data
+	}
+}
+
+// affectedCode returns the lines of the range with normalized whitespace, so that reformatting doesn't change it
+func affectedCode(lines []string, r vulnmap.Range) (string, bool) {
+	if r.Start.Line < 0 || r.End.Line >= len(lines) || r.Start.Line > r.End.Line {
+		return "", false
+	}
+	normalized := make([]string, 0, r.End.Line-r.Start.Line+1)
+	for _, line := range lines[r.Start.Line : r.End.Line+1] {
+		normalized = append(normalized, strings.Join(strings.Fields(line), " "))
+	}
+	snippet := strings.Join(normalized, "\n")
+	if strings.TrimSpace(snippet) == "" {
+		return "", false
+	}
+	return snippet, true
+}
@@ -44,6 +44,17 @@ func createRuleLink() (u *url.URL) {
 	return u
 }
 
+// newOpenRuleCodeAction returns a code action that opens the web page of the rule of the issue
+func newOpenRuleCodeAction(issueTitle string, ruleURL *url.URL) vulnmap.CodeAction {
+	title := fmt.Sprintf("Open description of '%s' in browser (Vulnmap)", issueTitle)
+	action, _ := vulnmap.NewCodeAction(title, nil, &vulnmap.CommandData{
+		Title:     title,
+		CommandId: vulnmap.OpenBrowserCommand,
+		Arguments: []any{ruleURL.String()},
+	})
+	return action
+}
+
 func (r *rule) getReferences() (references []vulnmap.Reference) {
 	for _, commit := range r.getExampleCommits() {
 		references = append(references, commit.toReference())
@@ -128,6 +139,8 @@ func (r *result) getCodeFlow(baseDir string) (dataflow []dataflowElement) {
 						position:  len(dataflow),
 						filePath:  filepath.Join(baseDir, path),
 						flowRange: myRange,
+						message:   tFlowLocation.Location.Message.Text,
+						baseDir:   baseDir,
 					}
 					log.Debug().Str("method", method).Str("dataflowElement", d.String()).Send()
 					dataflow = append(dataflow, d)
@@ -165,7 +178,7 @@ func (r *rule) detailsOrEmpty() string {
 	return ""
 }
 
-func (r *result) formattedMessage(rule rule, baseDir string) string {
+func (r *result) formattedMessage(rule rule, dataflow []dataflowElement) string {
 	const separator = "\n\n\n\n"
 	var builder strings.Builder
 	builder.Grow(500)
@@ -183,8 +196,8 @@ func (r *result) formattedMessage(rule rule, baseDir string) string {
 	builder.WriteString(rule.detailsOrEmpty())
 	builder.WriteString(separator)
 	builder.WriteString("### Data Flow\n\n")
-	for _, elem := range r.getCodeFlow(baseDir) {
-		builder.WriteString(elem.toMarkDown())
+	for i := range dataflow {
+		builder.WriteString(dataflow[i].toMarkDown())
 	}
 	builder.WriteString(separator)
 	builder.WriteString("### Example Commit Fixes\n\n")
@@ -300,7 +313,12 @@ func (s *SarifResponse) toIssues(baseDir string) (issues []vulnmap.Issue, err er
 
 			rule := r.getRule(result.RuleID)
 			message := result.getMessage(rule)
-			formattedMessage := result.formattedMessage(rule, baseDir)
+			codeFlow := result.getCodeFlow(baseDir)
+			formattedMessage := result.formattedMessage(rule, codeFlow)
+			dataFlow := make([]vulnmap.DataFlowElement, 0, len(codeFlow))
+			for i := range codeFlow {
+				dataFlow = append(dataFlow, codeFlow[i].toDataFlowElement())
+			}
 
 			exampleCommits := rule.getExampleCommits()
 			exampleFixes := make([]vulnmap.ExampleCommitFix, 0, len(exampleCommits))
@@ -350,6 +368,13 @@ func (s *SarifResponse) toIssues(baseDir string) (issues []vulnmap.Issue, err er
 				Rows:               [2]int{startLine, endLine},
 				IsSecurityType:     isSecurityType,
 				IsAutofixable:      result.Properties.IsAutofixable,
+				DataFlow:           dataFlow,
+			}
+
+			issueURL, codeActions := ruleLink, []vulnmap.CodeAction(nil)
+			if ruleURL, ok := vulnmap.IssueURL(product.ProductCode, result.RuleID); ok {
+				issueURL = ruleURL
+				codeActions = append(codeActions, newOpenRuleCodeAction(title, ruleURL))
 			}
 
 			d := vulnmap.Issue{
@@ -361,15 +386,17 @@ func (s *SarifResponse) toIssues(baseDir string) (issues []vulnmap.Issue, err er
 				IssueType:           issueType,
 				AffectedFilePath:    absPath,
 				Product:             product.ProductCode,
-				IssueDescriptionURL: ruleLink,
+				IssueDescriptionURL: issueURL,
 				References:          rule.getReferences(),
 				AdditionalData:      additionalData,
 				CWEs:                rule.Properties.Cwe,
+				CodeActions:         codeActions,
 			}
 
 			issues = append(issues, d)
 		}
 	}
+	addContextHashes(issues)
 	return issues, errs
 }
 
@@ -26,6 +26,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
@@ -643,6 +644,23 @@ func TestVulnmapCodeBackendService_convert_shouldConvertIssues(t *testing.T) {
 	assert.Equal(t, resp.Sarif.Runs[0].Tool.Driver.Rules[0].Properties.Cwe, issue.CWEs)
 }
 
+func TestVulnmapCodeBackendService_convert_shouldUseRuleUrlTemplate(t *testing.T) {
+	path, issues, resp := setupConversionTests(t, true, true)
+	assert.Empty(t, issues[0].CodeActions, "without template, code issues have no page to open")
+	config.CurrentConfig().SetIssueURLTemplates(map[product.Product]string{
+		product.ProductCode: "https://app.vulnmap.khulnasoft.com/code/rules/{id}",
+	})
+
+	issues, err := resp.toIssues(filepath.Dir(path))
+
+	require.NoError(t, err)
+	expectedURL := "https://app.vulnmap.khulnasoft.com/code/rules/" + url.PathEscape(issues[0].ID)
+	assert.Equal(t, expectedURL, issues[0].IssueDescriptionURL.String())
+	require.Len(t, issues[0].CodeActions, 1)
+	assert.Equal(t, vulnmap.OpenBrowserCommand, issues[0].CodeActions[0].Command.CommandId)
+	assert.Equal(t, []any{expectedURL}, issues[0].CodeActions[0].Command.Arguments)
+}
+
 func referencesForSampleSarifResponse() []vulnmap.Reference {
 
 	exampleCommitFix1, _ := url.Parse("https://github.com/apache/flink/commit/5d7c5620804eddd59206b24c87ffc89c12fd1184?diff=split#diff-86ec3e3884662ba3b5f4bb5050221fd6L94")
@@ -705,7 +723,7 @@ func Test_getFormattedMessage(t *testing.T) {
 	run := sarifResponse.Sarif.Runs[0]
 	result := run.Results[0]
 
-	msg := result.formattedMessage(run.getRule("1"), filepath.Dir(p))
+	msg := result.formattedMessage(run.getRule("1"), result.getCodeFlow(filepath.Dir(p)))
 
 	assert.Contains(t, msg, "Example Commit Fixes")
 	assert.Contains(t, msg, "Data Flow")
@@ -899,3 +917,120 @@ func Test_Result_getMarkers_basic(t *testing.T) {
 	assert.Len(t, marker, 3)
 
 }
+
+func Test_toIssues_CarriesDataFlowSteps(t *testing.T) {
+	testutil.UnitTest(t)
+	baseDir := t.TempDir()
+	testutil.CreateFileOrFail(t, filepath.Join(baseDir, "app.js"),
+		[]byte("const name = req.query.name;\nconst query = 'SELECT ' + name;\ndb.query(query);\n"))
+	flowLocation := func(line int, message string) threadFlowLocation {
+		return threadFlowLocation{Location: location{
+			PhysicalLocation: physicalLocation{
+				ArtifactLocation: artifactLocation{URI: "app.js"},
+				Region:           region{StartLine: line, EndLine: line, StartColumn: 1, EndColumn: 5},
+			},
+			Message: resultMessage{Text: message},
+		}}
+	}
+	resp := SarifResponse{}
+	resp.Sarif.Runs = []run{{
+		Tool: tool{Driver: driver{Rules: []rule{{ID: "javascript/Sqli"}}}},
+		Results: []result{{
+			RuleID: "javascript/Sqli",
+			Level:  "error",
+			Locations: []location{{PhysicalLocation: physicalLocation{
+				ArtifactLocation: artifactLocation{URI: "app.js"},
+				Region:           region{StartLine: 3, EndLine: 3, StartColumn: 1, EndColumn: 5},
+			}}},
+			CodeFlows: []codeFlow{{ThreadFlows: []threadFlow{{Locations: []threadFlowLocation{
+				flowLocation(1, "source"),
+				flowLocation(2, "concatenation"),
+				flowLocation(3, "sink"),
+			}}}}},
+		}},
+	}}
+
+	issues, err := resp.toIssues(baseDir)
+
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	dataFlow := issues[0].AdditionalData.(vulnmap.CodeIssueData).DataFlow
+	require.Len(t, dataFlow, 3)
+	expectedSteps := []struct {
+		content string
+		message string
+	}{
+		{"const name = req.query.name;", "source"},
+		{"const query = 'SELECT ' + name;", "concatenation"},
+		{"db.query(query);", "sink"},
+	}
+	for i, expected := range expectedSteps {
+		assert.Equal(t, i, dataFlow[i].Position)
+		assert.Equal(t, filepath.Join(baseDir, "app.js"), dataFlow[i].FilePath)
+		assert.Equal(t, i, dataFlow[i].FlowRange.Start.Line)
+		assert.Equal(t, expected.content, dataFlow[i].Content)
+		assert.Equal(t, expected.message, dataFlow[i].Message)
+	}
+	formattedMessage := issues[0].FormattedMessage
+	assert.Less(t, strings.Index(formattedMessage, "source"), strings.Index(formattedMessage, "concatenation"))
+	assert.Less(t, strings.Index(formattedMessage, "concatenation"), strings.Index(formattedMessage, "sink"))
+}
+
+func sqliSarifResponse(lines ...int) *SarifResponse {
+	resp := &SarifResponse{}
+	var locations []location
+	for _, line := range lines {
+		locations = append(locations, location{PhysicalLocation: physicalLocation{
+			ArtifactLocation: artifactLocation{URI: "app.js"},
+			Region:           region{StartLine: line, EndLine: line, StartColumn: 1, EndColumn: 16},
+		}})
+	}
+	resp.Sarif.Runs = []run{{
+		Tool:    tool{Driver: driver{Rules: []rule{{ID: "javascript/Sqli"}}}},
+		Results: []result{{RuleID: "javascript/Sqli", Level: "error", Locations: locations}},
+	}}
+	return resp
+}
+
+func Test_toIssues_FingerprintSurvivesUnrelatedEdits(t *testing.T) {
+	testutil.UnitTest(t)
+	baseDir := t.TempDir()
+	filePath := filepath.Join(baseDir, "app.js")
+	testutil.CreateFileOrFail(t, filePath, []byte("const name = req.query.name;\ndb.query(name);\n"))
+	firstScan, err := sqliSarifResponse(2).toIssues(baseDir)
+	require.NoError(t, err)
+
+	// unrelated lines are added above the issue and its line is re-indented
+	testutil.CreateFileOrFail(t, filePath,
+		[]byte("// load the user\nconst express = require('express');\nconst name = req.query.name;\nif (name) {\n  db.query(name);\n}\n"))
+	secondScan, err := sqliSarifResponse(5).toIssues(baseDir)
+	require.NoError(t, err)
+
+	require.Len(t, firstScan, 1)
+	require.Len(t, secondScan, 1)
+	assert.NotEqual(t, firstScan[0].Range, secondScan[0].Range)
+	assert.NotEmpty(t, firstScan[0].AdditionalData.(vulnmap.CodeIssueData).ContextHash)
+	assert.Equal(t, firstScan[0].Fingerprint(), secondScan[0].Fingerprint())
+}
+
+func Test_toIssues_IdenticalCodeViolatingTheSameRuleHasDistinctFingerprints(t *testing.T) {
+	testutil.UnitTest(t)
+	baseDir := t.TempDir()
+	testutil.CreateFileOrFail(t, filepath.Join(baseDir, "app.js"), []byte("db.query(name);\nlog(name);\ndb.query(name);\n"))
+
+	issues, err := sqliSarifResponse(3, 1).toIssues(baseDir)
+
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.NotEqual(t, issues[0].Fingerprint(), issues[1].Fingerprint())
+}
+
+func Test_toIssues_UnreadableFileFallsBackToLocationFingerprint(t *testing.T) {
+	testutil.UnitTest(t)
+
+	issues, err := sqliSarifResponse(2).toIssues(t.TempDir())
+
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Empty(t, issues[0].AdditionalData.(vulnmap.CodeIssueData).ContextHash)
+}
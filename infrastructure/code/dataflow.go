@@ -22,6 +22,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
 	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/filesystem"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
@@ -32,14 +33,30 @@ type dataflowElement struct {
 	filePath  string
 	flowRange vulnmap.Range
 	content   string
+	message   string
+	// baseDir is the root of the scanned folder, file paths in messages are shown relative to it if configured
+	baseDir string
 }
 
 func (d *dataflowElement) String() string {
-	return fmt.Sprintf("pos=%d, filePath=%s, flowRange %s, content=%s", d.position, d.filePath, d.flowRange.String(), d.content)
+	return fmt.Sprintf("pos=%d, filePath=%s, flowRange %s, content=%s, message=%s", d.position, d.filePath, d.flowRange.String(), d.content, d.message)
+}
+
+func (d *dataflowElement) toDataFlowElement() vulnmap.DataFlowElement {
+	return vulnmap.DataFlowElement{
+		Position:  d.position,
+		FilePath:  d.filePath,
+		FlowRange: d.flowRange,
+		Content:   d.content,
+		Message:   d.message,
+	}
 }
 
 func (d *dataflowElement) toMarkDown() (markdown string) {
 	fileName := filepath.Base(d.filePath)
+	if config.CurrentConfig().IsRelativeFilePathDisplay() {
+		fileName = vulnmap.DisplayPath(d.filePath, d.baseDir)
+	}
 	fileURI := uri.PathToUri(d.filePath)
 	line := d.flowRange.Start.Line + 1 // range is 0-based
 	fileUtil := filesystem.New()
@@ -50,8 +67,12 @@ func (d *dataflowElement) toMarkDown() (markdown string) {
 			log.Warn().Str("method", "code.dataflow.toMarkdown").Err(err).Msg("cannot load line content from file")
 		}
 	}
+	message := ""
+	if d.message != "" {
+		message = " " + d.message
+	}
 	markdown = fmt.Sprintf(
-		"%d. [%s:%d](%s) `%s`\n\n",
+		"%d. [%s:%d](%s) `%s`%s\n\n",
 		d.position,
 		fileName,
 		line,
@@ -62,6 +83,7 @@ func (d *dataflowElement) toMarkDown() (markdown string) {
 			EndChar:   d.flowRange.End.Character,
 		}),
 		d.content,
+		message,
 	)
 	return markdown
 }
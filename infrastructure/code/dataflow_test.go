@@ -0,0 +1,57 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package code
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
+)
+
+func Test_dataflowElement_toMarkDown_ShowsRelativePathAndKeepsAbsoluteUri(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetRelativeFilePathDisplay(true)
+	baseDir := t.TempDir()
+	filePath := filepath.Join(baseDir, "src", "app.js")
+	d := dataflowElement{
+		position:  0,
+		filePath:  filePath,
+		flowRange: vulnmap.Range{Start: vulnmap.Position{Line: 4}, End: vulnmap.Position{Line: 4, Character: 10}},
+		content:   "res.send(input)",
+		baseDir:   baseDir,
+	}
+
+	markdown := d.toMarkDown()
+
+	assert.Contains(t, markdown, "[src/app.js:5]("+uri.PathToUri(filePath))
+	assert.NotContains(t, markdown, "["+filePath)
+}
+
+func Test_dataflowElement_toMarkDown_ShowsFileNameByDefault(t *testing.T) {
+	testutil.UnitTest(t)
+	baseDir := t.TempDir()
+	d := dataflowElement{filePath: filepath.Join(baseDir, "src", "app.js"), content: "res.send(input)", baseDir: baseDir}
+
+	markdown := d.toMarkDown()
+
+	assert.Contains(t, markdown, "[app.js:1](")
+}
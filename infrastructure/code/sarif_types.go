@@ -57,6 +57,7 @@ type physicalLocation struct {
 type location struct {
 	ID               int              `json:"id"`
 	PhysicalLocation physicalLocation `json:"physicalLocation"`
+	Message          resultMessage    `json:"message"`
 }
 
 type threadFlowLocation struct {
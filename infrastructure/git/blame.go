@@ -0,0 +1,92 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package git
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// notCommittedSha is the commit git blame reports for lines that are not committed yet
+const notCommittedSha = "0000000000000000000000000000000000000000"
+
+// LineCommit is the last commit that changed a line
+type LineCommit struct {
+	Author    string
+	CommitSha string
+}
+
+// BlameProvider determines the last commit of every line of a file
+type BlameProvider interface {
+	// Blame returns the last commit of every line of the file, indexed by the 0-based line number. Lines that are not
+	// committed yet have an empty LineCommit.
+	Blame(filePath string) ([]LineCommit, error)
+}
+
+type cliBlameProvider struct{}
+
+func NewBlameProvider() BlameProvider {
+	return &cliBlameProvider{}
+}
+
+func (p *cliBlameProvider) Blame(filePath string) ([]LineCommit, error) {
+	// --line-porcelain repeats the commit information for every line, so that lines can be parsed independently.
+	// Boundary commits of shallow clones are reported like any other commit.
+	output, err := gitRawOutput(filepath.Dir(filePath), "blame", "--line-porcelain", "--", filepath.Base(filePath))
+	if err != nil {
+		return nil, err
+	}
+	return parseLinePorcelain(output), nil
+}
+
+// parseLinePorcelain parses the output of git blame --line-porcelain
+func parseLinePorcelain(output string) []LineCommit {
+	var lineCommits []LineCommit
+	var current LineCommit
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// the content of the line ends its block
+			lineCommits = append(lineCommits, current)
+			current = LineCommit{}
+		case strings.HasPrefix(line, "author "):
+			current.Author = strings.TrimPrefix(line, "author ")
+		case current.CommitSha == "" && isCommitHeader(line):
+			current.CommitSha = strings.Fields(line)[0]
+		}
+	}
+	for i := range lineCommits {
+		if lineCommits[i].CommitSha == notCommittedSha {
+			lineCommits[i] = LineCommit{}
+		}
+	}
+	return lineCommits
+}
+
+// isCommitHeader returns true for the first line of a block, "<sha> <original line> <final line> [<group size>]"
+func isCommitHeader(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || len(fields[0]) != len(notCommittedSha) {
+		return false
+	}
+	for _, r := range fields[0] {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
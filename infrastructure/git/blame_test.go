@@ -0,0 +1,61 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Blame_ReturnsLastCommitOfCommittedLines(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init")
+	file := filepath.Join(dir, "package.json")
+	require.NoError(t, os.WriteFile(file, []byte("{\n}\n"), 0600))
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "initial")
+	require.NoError(t, os.WriteFile(file, []byte("{\n}\n\n"), 0600))
+
+	lines, err := NewBlameProvider().Blame(file)
+
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "test", lines[0].Author)
+	assert.Len(t, lines[0].CommitSha, len(notCommittedSha))
+	assert.Equal(t, lines[0], lines[1])
+	assert.Equal(t, LineCommit{}, lines[2], "uncommitted lines have no commit")
+}
+
+func Test_Blame_NonGitFolderReturnsError(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	file := filepath.Join(t.TempDir(), "package.json")
+	require.NoError(t, os.WriteFile(file, []byte("{}"), 0600))
+
+	_, err := NewBlameProvider().Blame(file)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package git determines version control information of workspace folders by shelling out to git.
+package git
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const DefaultBaseRef = "HEAD"
+
+// ChangedFilesProvider determines the files within a folder that differ from a base ref
+type ChangedFilesProvider interface {
+	// ChangedFiles returns the absolute paths of modified and untracked files in folderPath relative to baseRef
+	ChangedFiles(folderPath string, baseRef string) ([]string, error)
+}
+
+type cliChangedFilesProvider struct{}
+
+func NewChangedFilesProvider() ChangedFilesProvider {
+	return &cliChangedFilesProvider{}
+}
+
+func (p *cliChangedFilesProvider) ChangedFiles(folderPath string, baseRef string) ([]string, error) {
+	if baseRef == "" {
+		baseRef = DefaultBaseRef
+	}
+
+	modified, err := gitOutput(folderPath, "diff", "--name-only", "--relative", baseRef, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := gitOutput(folderPath, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	var changedFiles []string
+	for _, relativePath := range append(modified, untracked...) {
+		changedFiles = append(changedFiles, filepath.Join(folderPath, filepath.FromSlash(relativePath)))
+	}
+	return changedFiles, nil
+}
+
+// gitOutput returns the trimmed, non-empty lines of the output of the git command
+func gitOutput(folderPath string, args ...string) ([]string, error) {
+	output, err := gitRawOutput(folderPath, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+func gitRawOutput(folderPath string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", folderPath}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "git %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
@@ -0,0 +1,63 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func Test_ChangedFiles_ReturnsModifiedAndUntrackedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unchanged.txt"), []byte("a"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "modified.txt"), []byte("a"), 0600))
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "initial")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "modified.txt"), []byte("b"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "untracked.txt"), []byte("a"), 0600))
+
+	changedFiles, err := NewChangedFilesProvider().ChangedFiles(dir, "")
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{filepath.Join(dir, "modified.txt"), filepath.Join(dir, "untracked.txt")}, changedFiles)
+}
+
+func Test_ChangedFiles_NonGitFolderReturnsError(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	_, err := NewChangedFilesProvider().ChangedFiles(t.TempDir(), "")
+
+	assert.Error(t, err)
+}
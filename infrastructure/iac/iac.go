@@ -48,7 +48,10 @@ import (
 )
 
 var scanCount = 1
-var _ vulnmap.ProductScanner = (*Scanner)(nil)
+var (
+	_ vulnmap.ProductScanner = (*Scanner)(nil)
+	_ vulnmap.FileSupporter  = (*Scanner)(nil)
+)
 
 var (
 	issueSeverities = map[string]vulnmap.Severity{
@@ -185,6 +188,11 @@ func (iac *Scanner) retrieveIssues(scanResults []iacScanResult,
 	return issues, nil
 }
 
+// SupportsFile returns true if the file is a supported infrastructure as code file
+func (iac *Scanner) SupportsFile(_ context.Context, path string) bool {
+	return iac.isSupported(uri.PathToUri(path))
+}
+
 func (iac *Scanner) isSupported(documentURI sglsp.DocumentURI) bool {
 	ext := filepath.Ext(uri.PathFromUri(documentURI))
 	return uri.IsUriDirectory(documentURI) || extensions[ext]
@@ -203,6 +211,8 @@ func (iac *Scanner) doScan(ctx context.Context,
 	defer iac.mutex.Unlock()
 
 	cmd := iac.cliCmd(documentURI)
+	cmd = cli.WithOrganization(cmd, vulnmap.OrganizationFromContext(ctx))
+	cmd = append(cmd, vulnmap.AdditionalCliArgsFromContext(ctx)...)
 	res, err := iac.cli.Execute(ctx, cmd, workspacePath)
 
 	if ctx.Err() != nil {
@@ -322,13 +332,13 @@ func (iac *Scanner) trackResult(success bool) {
 }
 
 // todo this needs to be pushed up to presentation
-func (iac *Scanner) getExtendedMessage(issue iacIssue) string {
+func (iac *Scanner) getExtendedMessage(issue iacIssue, format string) string {
 	title := issue.Title
 	description := issue.IacDescription.Issue
 	impact := issue.IacDescription.Impact
 	resolve := issue.IacDescription.Resolve
 
-	if config.CurrentConfig().Format() == config.FormatHtml {
+	if format == config.FormatHtml {
 		title = string(markdown.ToHTML([]byte(title), nil, nil))
 		description = string(markdown.ToHTML([]byte(description), nil, nil))
 		impact = string(markdown.ToHTML([]byte(impact), nil, nil))
@@ -346,7 +356,7 @@ func (iac *Scanner) toIssue(affectedFilePath string, issue iacIssue, fileContent
 	const defaultRangeStart = 0
 	const defaultRangeEnd = 80
 	title := issue.IacDescription.Issue
-	if config.CurrentConfig().Format() == config.FormatHtml {
+	if config.CurrentConfig().FormatFor(config.RenderContextDiagnostic) == config.FormatHtml {
 		title = string(markdown.ToHTML([]byte(title), nil, nil))
 	}
 	codeActionTitle := fmt.Sprintf("Open description of '%s' in browser (Vulnmap)", issue.Title)
@@ -378,6 +388,8 @@ func (iac *Scanner) toIssue(affectedFilePath string, issue iacIssue, fileContent
 		return vulnmap.Issue{}, errors.Wrap(err, "unable to create IaC issue additional data")
 	}
 
+	formattedMessage, formattedMessages := vulnmap.RenderFormattedMessages(config.CurrentConfig(),
+		func(format string) string { return iac.getExtendedMessage(issue, format) })
 	return vulnmap.Issue{
 		ID: issue.PublicID,
 		Range: vulnmap.Range{
@@ -385,7 +397,8 @@ func (iac *Scanner) toIssue(affectedFilePath string, issue iacIssue, fileContent
 			End:   vulnmap.Position{Line: issue.LineNumber, Character: rangeEnd},
 		},
 		Message:             fmt.Sprintf("%s (Vulnmap)", title),
-		FormattedMessage:    iac.getExtendedMessage(issue),
+		FormattedMessage:    formattedMessage,
+		FormattedMessages:   formattedMessages,
 		Severity:            iac.toIssueSeverity(issue.Severity),
 		AffectedFilePath:    affectedFilePath,
 		Product:             product.ProductInfrastructureAsCode,
@@ -447,11 +460,12 @@ func newIacCommand(codeActionTitle string, issueURL *url.URL) *vulnmap.CommandDa
 }
 
 func (iac *Scanner) createIssueURL(id string) *url.URL {
-	parse, err := url.Parse("https://security.vulnmap.khulnasoft.com/rules/cloud/" + id)
-	if err != nil {
-		iac.errorReporter.CaptureError(errors.Wrap(err, "unable to create issue link for iac issue "+id))
+	issueURL, ok := vulnmap.IssueURL(product.ProductInfrastructureAsCode, id)
+	if !ok {
+		iac.errorReporter.CaptureError(errors.New("unable to create issue link for iac issue " + id))
+		return &url.URL{}
 	}
-	return parse
+	return issueURL
 }
 
 func (iac *Scanner) toIssueSeverity(vulnmapSeverity string) vulnmap.Severity {
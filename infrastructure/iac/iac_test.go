@@ -52,6 +52,15 @@ func Test_Scan_IsInstrumented(t *testing.T) {
 	}
 }
 
+func Test_SupportsFile(t *testing.T) {
+	testutil.UnitTest(t)
+	scanner := New(performance.NewInstrumentor(), error_reporting.NewTestErrorReporter(), ux2.NewTestAnalytics(), cli.NewTestExecutor())
+
+	assert.True(t, scanner.SupportsFile(context.Background(), "main.tf"))
+	assert.True(t, scanner.SupportsFile(context.Background(), "deployment.yaml"))
+	assert.False(t, scanner.SupportsFile(context.Background(), "main.go"))
+}
+
 func Test_SuccessfulScanFile_TracksAnalytics(t *testing.T) {
 	testutil.UnitTest(t)
 	analytics := ux2.NewTestAnalytics()
@@ -91,7 +100,7 @@ func Test_toHover_asHTML(t *testing.T) {
 	scanner := New(performance.NewInstrumentor(), error_reporting.NewTestErrorReporter(), ux2.NewTestAnalytics(), cli.NewTestExecutor())
 	config.CurrentConfig().SetFormat(config.FormatHtml)
 
-	h := scanner.getExtendedMessage(sampleIssue())
+	h := scanner.getExtendedMessage(sampleIssue(), config.FormatHtml)
 
 	assert.Equal(
 		t,
@@ -105,7 +114,7 @@ func Test_toHover_asMD(t *testing.T) {
 	scanner := New(performance.NewInstrumentor(), error_reporting.NewTestErrorReporter(), ux2.NewTestAnalytics(), cli.NewTestExecutor())
 	config.CurrentConfig().SetFormat(config.FormatMd)
 
-	h := scanner.getExtendedMessage(sampleIssue())
+	h := scanner.getExtendedMessage(sampleIssue(), config.FormatMd)
 
 	assert.Equal(
 		t,
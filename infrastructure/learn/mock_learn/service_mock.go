@@ -5,6 +5,7 @@
 package mock_learn
 
 import (
+	"context"
 	"reflect"
 
 	"github.com/golang/mock/gomock"
@@ -38,33 +39,33 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // GetAllLessons mocks base method.
-func (m *MockService) GetAllLessons() ([]learn.Lesson, error) {
+func (m *MockService) GetAllLessons(arg0 context.Context) ([]learn.Lesson, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllLessons")
+	ret := m.ctrl.Call(m, "GetAllLessons", arg0)
 	ret0, _ := ret[0].([]learn.Lesson)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllLessons indicates an expected call of GetAllLessons.
-func (mr *MockServiceMockRecorder) GetAllLessons() *gomock.Call {
+func (mr *MockServiceMockRecorder) GetAllLessons(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllLessons", reflect.TypeOf((*MockService)(nil).GetAllLessons))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllLessons", reflect.TypeOf((*MockService)(nil).GetAllLessons), arg0)
 }
 
 // GetLesson mocks base method.
-func (m *MockService) GetLesson(arg0, arg1 string, arg2, arg3 []string, arg4 vulnmap.Type) (*learn.Lesson, error) {
+func (m *MockService) GetLesson(arg0 context.Context, arg1, arg2 string, arg3, arg4 []string, arg5 vulnmap.Type) (*learn.Lesson, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLesson", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "GetLesson", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(*learn.Lesson)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLesson indicates an expected call of GetLesson.
-func (mr *MockServiceMockRecorder) GetLesson(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetLesson(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLesson", reflect.TypeOf((*MockService)(nil).GetLesson), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLesson", reflect.TypeOf((*MockService)(nil).GetLesson), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // LearnEndpoint mocks base method.
@@ -17,6 +17,7 @@
 package learn
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -87,7 +88,7 @@ func TestVulnmapLearnServicePact(t *testing.T) { // nolint:gocognit // this is a
 
 			cut := New(c, httpClientFunc, errorreporting.NewTestErrorReporter()).(*serviceImpl)
 
-			_, err = cut.GetAllLessons()
+			_, err = cut.GetAllLessons(context.Background())
 
 			assert.NoError(t, err)
 			assert.True(t, cut.lessonsByEcosystemCache.Len() > 0, "didn't save the lessons in the cache")
@@ -17,11 +17,13 @@
 package learn
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/erni27/imcache"
@@ -37,8 +39,10 @@ import (
 
 type Service interface {
 	LearnEndpoint(conf *config.Config) (learnEndpoint string, err error)
-	GetLesson(ecosystem string, rule string, cwes []string, cves []string, issueType vulnmap.Type) (lesson *Lesson, err error)
-	GetAllLessons() (lessons []Lesson, err error)
+	// GetLesson looks up the lesson about an issue. The lookup is aborted with the context's error once it is cancelled.
+	GetLesson(ctx context.Context, ecosystem string, rule string, cwes []string, cves []string, issueType vulnmap.Type) (lesson *Lesson, err error)
+	// GetAllLessons fetches all lessons and caches them. The request is aborted once the context is cancelled.
+	GetAllLessons(ctx context.Context) (lessons []Lesson, err error)
 }
 
 type Lesson struct {
@@ -106,13 +110,22 @@ var ecosystemAliases = map[string]string{
 	"elixir":   "elixir",
 }
 
+// Ecosystem returns the ecosystem of lessons for a package manager or language, or "" if there are no lessons for it
+func Ecosystem(packageManager string) string {
+	return ecosystemAliases[strings.ToLower(packageManager)]
+}
+
 type serviceImpl struct {
 	logger                  zerolog.Logger
 	lessonsByRuleCache      *imcache.Cache[string, []Lesson]
 	lessonsByEcosystemCache *imcache.Cache[string, []Lesson]
-	conf                    *config.Config
-	httpClient              func() *http.Client
-	er                      error_reporting.ErrorReporter
+	// lessonsByLookupCache contains the results of lesson lookups, nil if no lesson was found
+	lessonsByLookupCache *imcache.Cache[string, *Lesson]
+	conf                 *config.Config
+	httpClient           func() *http.Client
+	er                   error_reporting.ErrorReporter
+	// refreshMutex serializes fetching the lessons after the lessons cache expired
+	refreshMutex sync.Mutex
 }
 
 func New(c *config.Config, httpClientFunc func() *http.Client, er error_reporting.ErrorReporter) Service {
@@ -127,10 +140,13 @@ func New(c *config.Config, httpClientFunc func() *http.Client, er error_reportin
 		lessonsByEcosystemCache: imcache.New[string, []Lesson](
 			imcache.WithDefaultExpirationOption[string, []Lesson](cacheExpiry),
 		),
+		lessonsByLookupCache: imcache.New[string, *Lesson](
+			imcache.WithDefaultExpirationOption[string, *Lesson](cacheExpiry),
+		),
 	}
 
 	// initialize cache
-	_, err := s.GetAllLessons()
+	_, err := s.GetAllLessons(context.Background())
 	if err != nil {
 		s.er.CaptureError(errors.WithMessage(err, "Error initializing lessons cache"))
 	}
@@ -144,7 +160,7 @@ func (s *serviceImpl) maintainCache() func() {
 	return func() {
 		for {
 			if s.lessonsByEcosystemCache.Len() == 0 {
-				_, err := s.GetAllLessons()
+				_, err := s.GetAllLessons(context.Background())
 				if err != nil {
 					s.er.CaptureError(errors.WithMessage(err, "Error updating lessons cache"))
 					return
@@ -156,7 +172,7 @@ func (s *serviceImpl) maintainCache() func() {
 	}
 }
 
-func (s *serviceImpl) GetAllLessons() (lessons []Lesson, err error) {
+func (s *serviceImpl) GetAllLessons(ctx context.Context) (lessons []Lesson, err error) {
 	logger := s.logger.With().Str("method", "GetAllLessons").Logger()
 	learnEndpoint, err := s.LearnEndpoint(s.conf)
 	if err != nil {
@@ -166,7 +182,11 @@ func (s *serviceImpl) GetAllLessons() (lessons []Lesson, err error) {
 	learnEndpoint = learnEndpoint + "/lessons"
 	logger.Debug().Str("LearnEndpoint", learnEndpoint).Msg("learn endpoint")
 
-	resp, err := s.httpClient().Get(learnEndpoint)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, learnEndpoint, nil)
+	if err != nil {
+		return lessons, err
+	}
+	resp, err := s.httpClient().Do(request)
 	if err != nil {
 		logger.Err(err).Msg("failed to retrieve lessons")
 		return lessons, err
@@ -192,6 +212,7 @@ func (s *serviceImpl) updateCaches(lessons []Lesson) {
 	expiration := imcache.WithDefaultExpiration()
 	s.lessonsByEcosystemCache.RemoveAll()
 	s.lessonsByRuleCache.RemoveAll()
+	s.lessonsByLookupCache.RemoveAll()
 
 	for _, lesson := range lessons {
 		if !lesson.Published {
@@ -215,7 +236,30 @@ func (s *serviceImpl) updateCaches(lessons []Lesson) {
 	}
 }
 
-func (s *serviceImpl) GetLesson(ecosystem string, rule string, cwes []string, cves []string, issueType vulnmap.Type) (lesson *Lesson, err error) {
+func (s *serviceImpl) GetLesson(
+	ctx context.Context,
+	ecosystem string,
+	rule string,
+	cwes []string,
+	cves []string,
+	issueType vulnmap.Type,
+) (*Lesson, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return s.lookupLesson(ctx, ecosystem, rule, cwes, cves, issueType)
+}
+
+// lookupLesson returns a copy of the lesson about an issue. If the lessons cache expired, the lessons are fetched again
+// with the context.
+func (s *serviceImpl) lookupLesson(
+	ctx context.Context,
+	ecosystem string,
+	rule string,
+	cwes []string,
+	cves []string,
+	issueType vulnmap.Type,
+) (lesson *Lesson, err error) {
 	logger := s.logger.With().Str("method", "GetLesson").Logger()
 
 	params := s.lessonsLookupParams(ecosystem, rule, cwes, cves, issueType)
@@ -223,6 +267,19 @@ func (s *serviceImpl) GetLesson(ecosystem string, rule string, cwes []string, cv
 		return
 	}
 
+	lookupKey := params.cacheKey()
+	if cachedLesson, found := s.lessonsByLookupCache.Get(lookupKey); found {
+		return copyLesson(cachedLesson), nil
+	}
+	if err = s.refreshExpiredLessons(ctx); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil && s.lessonsByEcosystemCache.Len() > 0 {
+			s.lessonsByLookupCache.Set(lookupKey, copyLesson(lesson), imcache.WithDefaultExpiration())
+		}
+	}()
+
 	lessons, exist := s.lessonsByRuleCache.Get(params.Rule)
 
 	if !exist || len(lessons) == 0 {
@@ -243,13 +300,44 @@ func (s *serviceImpl) GetLesson(ecosystem string, rule string, cwes []string, cv
 	lessons = s.filterLessons(lessons, params)
 
 	if len(lessons) >= 1 {
-		lesson = &lessons[0]
-		lesson.Url += "?loc=ide"
+		// copy the lesson, as lessons may be shared with the cache and looked up concurrently
+		foundLesson := lessons[0]
+		foundLesson.Url += "?loc=ide"
+		lesson = &foundLesson
 		logger.Debug().Msgf("found lesson %v", lesson)
 	}
 	return lesson, err
 }
 
+// refreshExpiredLessons fetches the lessons again once the lessons cache expired. Concurrent lookups wait for a
+// single fetch.
+func (s *serviceImpl) refreshExpiredLessons(ctx context.Context) error {
+	if s.lessonsByEcosystemCache.Len() > 0 {
+		return nil
+	}
+	s.refreshMutex.Lock()
+	defer s.refreshMutex.Unlock()
+	if s.lessonsByEcosystemCache.Len() > 0 {
+		return nil
+	}
+	_, err := s.GetAllLessons(ctx)
+	return err
+}
+
+// cacheKey identifies the lookup in the lessons by lookup cache
+func (p *LessonLookupParams) cacheKey() string {
+	return strings.Join([]string{p.Ecosystem, p.Rule, strings.Join(p.CWEs, ","), strings.Join(p.CVEs, ",")}, "|")
+}
+
+// copyLesson returns a copy of the lesson, so that callers can't modify cached lessons
+func copyLesson(lesson *Lesson) *Lesson {
+	if lesson == nil {
+		return nil
+	}
+	lessonCopy := *lesson
+	return &lessonCopy
+}
+
 func (s *serviceImpl) getLessonsByEcosystem(params *LessonLookupParams) (ecoLessons []Lesson) {
 	ecoLessons, _ = s.lessonsByEcosystemCache.Get(ecosystemAliases[strings.ToLower(params.Ecosystem)])
 	return
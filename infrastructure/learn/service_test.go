@@ -17,10 +17,15 @@
 package learn
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	errorreporting "github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
@@ -28,6 +33,49 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
 )
 
+func lessonsServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	requests := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`[{"ecosystem": "npm", "cwes": ["CWE-1321"], "published": true, "url": "https://learn.example/lesson"}]`))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func Test_GetLesson_FetchesLessonsAgainOnceCacheExpired(t *testing.T) {
+	c := testutil.UnitTest(t)
+	server, requests := lessonsServer(t)
+	c.UpdateApiEndpoints(strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+	cut := New(c, c.Engine().GetNetworkAccess().GetUnauthorizedHttpClient, errorreporting.NewTestErrorReporter()).(*serviceImpl)
+	require.Equal(t, int32(1), requests.Load())
+	cut.updateCaches(nil)
+	params := getRealOSSLookupParams()
+
+	lesson, err := cut.GetLesson(context.Background(), params.Ecosystem, params.Rule, params.CWEs, params.CVEs, vulnmap.DependencyVulnerability)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, "https://learn.example/lesson?loc=ide", lesson.Url)
+}
+
+func Test_GetLesson_AbortsFetchWhenContextIsCancelled(t *testing.T) {
+	c := testutil.UnitTest(t)
+	server, requests := lessonsServer(t)
+	c.UpdateApiEndpoints(strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+	cut := New(c, c.Engine().GetNetworkAccess().GetUnauthorizedHttpClient, errorreporting.NewTestErrorReporter()).(*serviceImpl)
+	cut.updateCaches(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	params := getRealOSSLookupParams()
+
+	_, err := cut.lookupLesson(ctx, params.Ecosystem, params.Rule, params.CWEs, params.CVEs, vulnmap.DependencyVulnerability)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), requests.Load(), "the cancelled fetch must not reach the server")
+}
+
 func Test_GetLearnEndpoint(t *testing.T) {
 	testutil.UnitTest(t)
 	c := config.CurrentConfig()
@@ -40,6 +88,26 @@ func Test_GetLearnEndpoint(t *testing.T) {
 	assert.Equal(t, "https://api.vulnmap.khulnasoft.com/v1/learn", endpoint)
 }
 
+func Test_GetAllLessons_SendsCustomHttpHeaders(t *testing.T) {
+	c := testutil.UnitTest(t)
+	headers := make(chan http.Header, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Clone()
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+	c.UpdateApiEndpoints(strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+	c.SetCustomHttpHeaders(map[string]string{"X-Gateway-Route": "vulnmap"})
+	cut := New(c, c.WithCustomHttpHeaders(c.Engine().GetNetworkAccess().GetUnauthorizedHttpClient), errorreporting.NewTestErrorReporter())
+
+	_, err := cut.GetAllLessons(context.Background())
+
+	assert.NoError(t, err)
+	// the first request is sent when the service is created
+	assert.Equal(t, "vulnmap", (<-headers).Get("X-Gateway-Route"))
+	assert.Equal(t, "vulnmap", (<-headers).Get("X-Gateway-Route"))
+}
+
 func getRealOSSLookupParams() *LessonLookupParams {
 	params := &LessonLookupParams{
 		CWEs:      []string{"CWE-1321"},
@@ -65,7 +133,7 @@ func Test_GetLesson(t *testing.T) {
 	t.Run("OSS vulnerability - lesson returned", func(t *testing.T) {
 		params := getRealOSSLookupParams()
 
-		lesson, err := cut.GetLesson(params.Ecosystem, params.Rule, params.CWEs, params.CVEs, vulnmap.DependencyVulnerability)
+		lesson, err := cut.GetLesson(context.Background(), params.Ecosystem, params.Rule, params.CWEs, params.CVEs, vulnmap.DependencyVulnerability)
 
 		assert.NoError(t, err)
 		assert.NotEmpty(t, lesson)
@@ -76,7 +144,7 @@ func Test_GetLesson(t *testing.T) {
 		testutil.SmokeTest(t)
 		params := getRealOSSLookupParams()
 
-		lesson, err := cut.GetLesson(params.Ecosystem, params.Rule, params.CWEs, params.CVEs, vulnmap.LicenceIssue)
+		lesson, err := cut.GetLesson(context.Background(), params.Ecosystem, params.Rule, params.CWEs, params.CVEs, vulnmap.LicenceIssue)
 
 		assert.NoError(t, err)
 		assert.Empty(t, lesson)
@@ -84,7 +152,7 @@ func Test_GetLesson(t *testing.T) {
 	t.Run("Code security - lesson returned", func(t *testing.T) {
 		params := getRealCodeLookupParams()
 
-		lesson, err := cut.GetLesson(params.Ecosystem, params.Rule, params.CWEs, params.CVEs, vulnmap.CodeSecurityVulnerability)
+		lesson, err := cut.GetLesson(context.Background(), params.Ecosystem, params.Rule, params.CWEs, params.CVEs, vulnmap.CodeSecurityVulnerability)
 
 		assert.NoError(t, err)
 		assert.NotEmpty(t, lesson)
@@ -94,9 +162,21 @@ func Test_GetLesson(t *testing.T) {
 	t.Run("Code quality - no lessons returned", func(t *testing.T) {
 		params := getRealCodeLookupParams()
 
-		lesson, err := cut.GetLesson(params.Ecosystem, params.Rule, params.CWEs, params.CVEs, vulnmap.CodeQualityIssue)
+		lesson, err := cut.GetLesson(context.Background(), params.Ecosystem, params.Rule, params.CWEs, params.CVEs, vulnmap.CodeQualityIssue)
 
 		assert.NoError(t, err)
 		assert.Empty(t, lesson)
 	})
 }
+
+func Test_GetLesson_ReturnsErrorForCancelledContext(t *testing.T) {
+	cut := &serviceImpl{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	params := getRealOSSLookupParams()
+
+	lesson, err := cut.GetLesson(ctx, params.Ecosystem, params.Rule, params.CWEs, params.CVEs, vulnmap.DependencyVulnerability)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, lesson)
+}
@@ -0,0 +1,54 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// advisoryDateFormat is how the advisory dates are rendered in the hover
+const advisoryDateFormat = "2006-01-02"
+
+// parseAdvisoryTime parses a timestamp of the feed, nil if it is missing or invalid
+func parseAdvisoryTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+// advisoryDatesMarkdown renders the disclosure and modification dates of the advisory, empty if both are unknown.
+// Provisional advisories are often revised, so a recent modification is a reason to recheck the issue.
+func advisoryDatesMarkdown(disclosed *time.Time, modified *time.Time) string {
+	var dates []string
+	if disclosed != nil {
+		dates = append(dates, fmt.Sprintf("Disclosed: %s", disclosed.UTC().Format(advisoryDateFormat)))
+	}
+	if modified != nil {
+		dates = append(dates, fmt.Sprintf("Last modified: %s", modified.UTC().Format(advisoryDateFormat)))
+	}
+	if len(dates) == 0 {
+		return ""
+	}
+	return "\n" + strings.Join(dates, " | ") + "\n"
+}
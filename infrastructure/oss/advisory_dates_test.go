@@ -0,0 +1,77 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_toAdditionalData_ParsesAdvisoryDates(t *testing.T) {
+	testutil.UnitTest(t)
+	var issue ossIssue
+	require.NoError(t, json.Unmarshal([]byte(
+		`{"disclosureTime": "2023-03-01T10:00:00Z", "modificationTime": "2024-05-20T08:30:00.123456Z"}`), &issue))
+
+	additionalData := issue.toAdditionalData("package.json", &scanResult{})
+
+	require.NotNil(t, additionalData.DisclosureTime)
+	assert.True(t, time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC).Equal(*additionalData.DisclosureTime))
+	require.NotNil(t, additionalData.ModificationTime)
+	assert.True(t, time.Date(2024, 5, 20, 8, 30, 0, 123456000, time.UTC).Equal(*additionalData.ModificationTime))
+}
+
+func Test_toAdditionalData_MissingOrInvalidAdvisoryDatesAreNil(t *testing.T) {
+	testutil.UnitTest(t)
+	issue := ossIssue{DisclosureTime: "yesterday"}
+
+	additionalData := issue.toAdditionalData("package.json", &scanResult{})
+
+	assert.Nil(t, additionalData.DisclosureTime)
+	assert.Nil(t, additionalData.ModificationTime)
+}
+
+func Test_GetExtendedMessage_RendersAdvisoryDates(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetFormat(config.FormatMd)
+	issue := sampleIssue()
+	issue.DisclosureTime = "2023-03-01T10:00:00Z"
+	issue.ModificationTime = "2024-05-20T08:30:00Z"
+
+	message := issue.GetExtendedMessage(issue, config.FormatMd)
+
+	assert.Contains(t, message, "Disclosed: 2023-03-01 | Last modified: 2024-05-20")
+}
+
+func Test_GetExtendedMessage_OmitsUnknownAdvisoryDates(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetFormat(config.FormatMd)
+	issue := sampleIssue()
+	issue.ModificationTime = "2024-05-20T08:30:00Z"
+
+	message := issue.GetExtendedMessage(issue, config.FormatMd)
+
+	assert.NotContains(t, message, "Disclosed")
+	assert.Contains(t, message, "Last modified: 2024-05-20")
+}
@@ -21,14 +21,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	sglsp "github.com/sourcegraph/go-lsp"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	noti "github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
@@ -44,6 +47,19 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/uri"
 )
 
+const (
+	// outputVersionFlag pins the schema of the CLI's JSON output, so that CLI updates don't break result parsing
+	outputVersionFlag = "--json-output-version"
+	// outputVersionFlagCliVersions are the CLI versions that accept the output version flag
+	outputVersionFlagCliVersions = ">= 1.1300.0"
+)
+
+const (
+	allProjectsFlag    = "--all-projects"
+	detectionDepthFlag = "--detection-depth"
+	excludeFlag        = "--exclude"
+)
+
 var (
 	lockFilesToManifestMap = map[string]string{
 		"Gemfile.lock":      "Gemfile",
@@ -55,9 +71,38 @@ var (
 		"Podfile.lock":      "Podfile",
 		"poetry.lock":       "pyproject.toml",
 	}
+	// supportedFiles contains the file names of the manifests the CLI can scan, mapped to the package manager the CLI
+	// reports for them
+	supportedFiles = map[string]string{
+		"yarn.lock":               "yarn",
+		"package-lock.json":       "npm",
+		"package.json":            "npm",
+		"Gemfile":                 "rubygems",
+		"Gemfile.lock":            "rubygems",
+		"pom.xml":                 "maven",
+		"build.gradle":            "gradle",
+		"build.gradle.kts":        "gradle",
+		"build.sbt":               "sbt",
+		"Pipfile":                 "pipenv",
+		"requirements.txt":        "pip",
+		"Gopkg.lock":              "golangdep",
+		"go.mod":                  "gomodules",
+		"vendor/vendor.json":      "govendor",
+		"obj/project.assets.json": "nuget",
+		"project.assets.json":     "nuget",
+		"packages.config":         "nuget",
+		"paket.dependencies":      "paket",
+		"composer.lock":           "composer",
+		"Podfile":                 "cocoapods",
+		"Podfile.lock":            "cocoapods",
+		"poetry.lock":             "poetry",
+		"mix.exs":                 "hex",
+		"mix.lock":                "hex",
+	}
 	// Make sure CLIScanner implements the desired interfaces
 	_ vulnmap.ProductScanner      = (*CLIScanner)(nil)
 	_ vulnmap.InlineValueProvider = (*CLIScanner)(nil)
+	_ vulnmap.FileSupporter       = (*CLIScanner)(nil)
 )
 
 type CLIScanner struct {
@@ -75,9 +120,10 @@ type CLIScanner struct {
 	learnService            learn.Service
 	notifier                noti.Notifier
 	inlineValues            inlineValueMap
-	supportedFiles          map[string]bool
 	packageIssueCache       map[string][]vulnmap.Issue
 	config                  *config.Config
+	// truncatedPaths contains the scanned paths whose last scan exceeded the maximum issues per scan
+	truncatedPaths map[string]bool
 }
 
 func NewCLIScanner(instrumentor performance.Instrumentor,
@@ -102,34 +148,9 @@ func NewCLIScanner(instrumentor performance.Instrumentor,
 		learnService:            learnService,
 		notifier:                notifier,
 		inlineValues:            make(inlineValueMap),
+		truncatedPaths:          map[string]bool{},
 		packageIssueCache:       make(map[string][]vulnmap.Issue),
 		config:                  c,
-		supportedFiles: map[string]bool{
-			"yarn.lock":               true,
-			"package-lock.json":       true,
-			"package.json":            true,
-			"Gemfile":                 true,
-			"Gemfile.lock":            true,
-			"pom.xml":                 true,
-			"build.gradle":            true,
-			"build.gradle.kts":        true,
-			"build.sbt":               true,
-			"Pipfile":                 true,
-			"requirements.txt":        true,
-			"Gopkg.lock":              true,
-			"go.mod":                  true,
-			"vendor/vendor.json":      true,
-			"obj/project.assets.json": true,
-			"project.assets.json":     true,
-			"packages.config":         true,
-			"paket.dependencies":      true,
-			"composer.lock":           true,
-			"Podfile":                 true,
-			"Podfile.lock":            true,
-			"poetry.lock":             true,
-			"mix.exs":                 true,
-			"mix.lock":                true,
-		},
 	}
 	return &scanner
 }
@@ -201,6 +222,8 @@ func (cliScanner *CLIScanner) scanInternal(
 	cliScanner.mutex.Unlock()
 
 	cmd := commandFunc([]string{workDir})
+	cmd = cli.WithOrganization(cmd, vulnmap.OrganizationFromContext(ctx))
+	cmd = append(cmd, vulnmap.AdditionalCliArgsFromContext(ctx)...)
 	res, err := cliScanner.cli.Execute(ctx, cmd, workDir)
 	noCancellation := ctx.Err() == nil
 	if err != nil {
@@ -213,14 +236,18 @@ func (cliScanner *CLIScanner) scanInternal(
 		}
 	}
 
-	issues = cliScanner.unmarshallAndRetrieveAnalysis(ctx, res, workDir, path)
-	cliScanner.trackResult(true)
+	issues, omittedIssueCount, err := cliScanner.unmarshallAndRetrieveAnalysis(ctx, res, workDir, path)
+	cliScanner.trackResult(err == nil, omittedIssueCount)
 
 	cliScanner.mutex.Lock()
 	log.Debug().Msgf("Scan %v is done", i)
 	newScan.SetDone()
 	cliScanner.mutex.Unlock()
 
+	if err != nil {
+		return nil, err
+	}
+
 	if issues != nil {
 		cliScanner.scheduleRefreshScan(context.Background(), path)
 	}
@@ -234,7 +261,9 @@ func (cliScanner *CLIScanner) prepareScanCommand(args []string) []string {
 	})
 	cmd = append(cmd, args...)
 	cmd = append(cmd, "--json")
+	cmd = append(cmd, outputVersionParameters()...)
 	additionalParams := config.CurrentConfig().CliSettings().AdditionalOssParameters
+	cmd = append(cmd, allProjectsParameters(additionalParams)...)
 	for _, parameter := range additionalParams {
 		if parameter == "" {
 			continue
@@ -244,55 +273,144 @@ func (cliScanner *CLIScanner) prepareScanCommand(args []string) []string {
 	return cmd
 }
 
+// outputVersionParameters returns the output version parameter if an output version is configured and the installed
+// CLI supports it. Unknown flags make the CLI fail, so the version isn't pinned for older or unknown CLI versions.
+func outputVersionParameters() []string {
+	c := config.CurrentConfig()
+	outputVersion := c.OssOutputVersion()
+	if outputVersion == "" {
+		return nil
+	}
+	cliVersion := c.CliSettings().Version()
+	supported, err := cli.IsSupportedCliVersion(cliVersion, outputVersionFlagCliVersions)
+	if err != nil || !supported {
+		log.Debug().Err(err).Str("method", "outputVersionParameters").Str("cliVersion", cliVersion).
+			Msg("CLI doesn't support pinning the output version")
+		return nil
+	}
+	return []string{outputVersionFlag + "=" + outputVersion}
+}
+
+// allProjectsParameters returns the project discovery parameters if all projects should be scanned. Parameters
+// that are already part of the additional parameters are not repeated.
+func allProjectsParameters(additionalParams []string) []string {
+	c := config.CurrentConfig()
+	if !c.IsAllProjectsEnabled() {
+		return nil
+	}
+	isAdditional := func(flag string) bool {
+		for _, parameter := range additionalParams {
+			if parameter == flag || strings.HasPrefix(parameter, flag+"=") {
+				return true
+			}
+		}
+		return false
+	}
+
+	var params []string
+	if !isAdditional(allProjectsFlag) {
+		params = append(params, allProjectsFlag)
+	}
+	if depth := c.AllProjectsDetectionDepth(); depth > 0 && !isAdditional(detectionDepthFlag) {
+		params = append(params, detectionDepthFlag+"="+strconv.Itoa(depth))
+	}
+	if exclude := c.AllProjectsExclude(); len(exclude) > 0 && !isAdditional(excludeFlag) {
+		params = append(params, excludeFlag+"="+strings.Join(exclude, ","))
+	}
+	return params
+}
+
+// SupportsFile returns true if the file is a manifest that can be scanned on its own
+func (cliScanner *CLIScanner) SupportsFile(_ context.Context, path string) bool {
+	return cliScanner.isSupported(path)
+}
+
 func (cliScanner *CLIScanner) isSupported(path string) bool {
-	return uri.IsDirectory(path) || cliScanner.supportedFiles[filepath.Base(path)]
+	return uri.IsDirectory(path) || IsSupportedManifest(path)
+}
+
+// IsSupportedManifest returns true if the given file is a manifest that can be scanned on its own
+func IsSupportedManifest(path string) bool {
+	_, ok := supportedFiles[filepath.Base(path)]
+	return ok
 }
 
 func (cliScanner *CLIScanner) unmarshallAndRetrieveAnalysis(ctx context.Context,
 	res []byte,
 	workDir string,
 	path string,
-) (issues []vulnmap.Issue) {
+) (issues []vulnmap.Issue, omittedIssueCount int, err error) {
 	if ctx.Err() != nil {
-		return nil
+		return nil, 0, nil
 	}
 
 	scanResults, err := cliScanner.unmarshallOssJson(res)
 	if err != nil {
 		cliScanner.errorReporter.CaptureErrorAndReportAsIssue(path, err)
-		return nil
+		return nil, 0, err
 	}
 
+	maxIssues := cliScanner.config.MaxIssuesPerScan()
+	omittedIssueCount = limitScanResults(cliScanner.config, scanResults, maxIssues)
+	if omittedIssueCount > 0 {
+		log.Warn().Str("method", "cliScanner.unmarshallAndRetrieveAnalysis").
+			Msgf("scan of %s exceeded the maximum of %d issues, omitted %d", path, maxIssues, omittedIssueCount)
+		omittedMessage := fmt.Sprintf(
+			"Vulnmap Open Source found more than %d issues in %s. Only the %d most severe issues are shown, %d were omitted.",
+			maxIssues, path, maxIssues, omittedIssueCount)
+		if cliScanner.updateTruncated(path, true) {
+			cliScanner.notifier.SendShowMessage(sglsp.Warning, omittedMessage)
+		}
+		vulnmap.AddScanWarning(ctx, omittedMessage)
+	} else {
+		cliScanner.updateTruncated(path, false)
+	}
+
+	results := make([]targetFileIssues, 0, len(scanResults))
 	for _, scanResult := range scanResults {
 		targetFilePath := path
-		targetFile := cliScanner.determineTargetFile(scanResult.DisplayTargetFile)
-		if targetFile != "" {
-			targetFilePath = filepath.Join(workDir, targetFile)
+		if scanResult.DisplayTargetFile != "" {
+			targetFilePath = filepath.Join(workDir, scanResult.DisplayTargetFile)
 		}
-		fileContent, err := os.ReadFile(targetFilePath)
-		if err != nil {
-			// don't fail the scan if we can't read the file. No annotations with ranges, though.
-			fileContent = []byte{}
+		targetFile := affectedFile{path: targetFilePath, content: readFileContent(targetFilePath)}
+
+		// issues found via a lockfile are reported on the manifest, if they are declared there
+		var manifest *affectedFile
+		manifestFile := cliScanner.determineTargetFile(scanResult.DisplayTargetFile)
+		if manifestFile != scanResult.DisplayTargetFile && cliScanner.config.IsLockfileIssueRemappingEnabled() {
+			manifestFilePath := filepath.Join(workDir, manifestFile)
+			manifest = &affectedFile{path: manifestFilePath, content: readFileContent(manifestFilePath)}
 		}
-		issues = append(issues, cliScanner.retrieveIssues(&scanResult, targetFilePath, fileContent)...)
+		results = append(results, targetFileIssues{
+			displayTargetFile: scanResult.DisplayTargetFile,
+			issues:            cliScanner.retrieveIssues(ctx, &scanResult, targetFile, manifest),
+		})
 	}
+	if ctx.Err() != nil {
+		return nil, 0, nil
+	}
+	issues = mergeTargetFileIssues(results, cliScanner.config.ManifestLockfileDuplicates())
+	linkMatchingIssues(issues)
 
-	return issues
+	return issues, omittedIssueCount, nil
 }
 
+// unmarshallOssJson parses the CLI output. Truncated or otherwise invalid output results in a vulnmap.ScanError,
+// so that it can be told apart from a valid result without issues.
 func (cliScanner *CLIScanner) unmarshallOssJson(res []byte) (scanResults []scanResult, err error) {
-	output := string(res)
+	output := strings.TrimSpace(string(res))
+	if output == "" {
+		return nil, &vulnmap.ScanError{Product: product.ProductOpenSource, Truncated: true, Err: io.ErrUnexpectedEOF}
+	}
 	if strings.HasPrefix(output, "[") {
-		err = json.Unmarshal(res, &scanResults)
+		err = decodeOssJson(output, &scanResults)
 		if err != nil {
-			err = errors.Join(err, fmt.Errorf("Couldn't unmarshal CLI response. Input: %s", output))
 			return nil, err
 		}
 	} else {
 		var result scanResult
-		err = json.Unmarshal(res, &result)
+		err = decodeOssJson(output, &result)
 		if err != nil {
-			err = errors.Join(err, fmt.Errorf("Couldn't unmarshal CLI response. Input: %s", output))
 			return nil, err
 		}
 		scanResults = append(scanResults, result)
@@ -300,6 +418,23 @@ func (cliScanner *CLIScanner) unmarshallOssJson(res []byte) (scanResults []scanR
 	return scanResults, err
 }
 
+func decodeOssJson(output string, v any) error {
+	decoder := json.NewDecoder(strings.NewReader(output))
+	err := decoder.Decode(v)
+	if err == nil && decoder.More() {
+		err = errors.New("unexpected content after JSON value")
+	}
+	if err != nil {
+		err = errors.Join(err, fmt.Errorf("Couldn't unmarshal CLI response. Input: %s", output))
+		return &vulnmap.ScanError{
+			Product:   product.ProductOpenSource,
+			Truncated: errors.Is(err, io.ErrUnexpectedEOF),
+			Err:       err,
+		}
+	}
+	return nil
+}
+
 // Returns true if CLI run failed, false otherwise
 func (cliScanner *CLIScanner) handleError(path string, err error, res []byte, cmd []string) bool {
 	var errorType *exec.ExitError
@@ -349,15 +484,28 @@ func (cliScanner *CLIScanner) determineTargetFile(displayTargetFile string) stri
 	return targetFile
 }
 
+// readFileContent returns the content of the given file. Unreadable files result in empty content, so that the
+// scan doesn't fail. No annotations with ranges, though.
+func readFileContent(path string) []byte {
+	fileContent, err := os.ReadFile(path)
+	if err != nil {
+		return []byte{}
+	}
+	return fileContent
+}
+
 func (cliScanner *CLIScanner) retrieveIssues(
+	ctx context.Context,
 	res *scanResult,
-	path string,
-	fileContent []byte,
+	targetFile affectedFile,
+	manifest *affectedFile,
 ) []vulnmap.Issue {
 	issues := convertScanResultToIssues(
+		ctx,
+		cliScanner.config,
 		res,
-		path,
-		fileContent,
+		targetFile,
+		manifest,
 		cliScanner.learnService,
 		cliScanner.errorReporter,
 		cliScanner.packageIssueCache,
@@ -369,7 +517,21 @@ func (cliScanner *CLIScanner) retrieveIssues(
 	return issues
 }
 
-func (cliScanner *CLIScanner) trackResult(success bool) {
+// updateTruncated records whether the scan of the path exceeded the maximum issues per scan and returns true if the
+// path wasn't truncated before, so that the user is only notified when the truncation starts, not on every scan
+func (cliScanner *CLIScanner) updateTruncated(path string, truncated bool) (changed bool) {
+	cliScanner.mutex.Lock()
+	defer cliScanner.mutex.Unlock()
+	changed = truncated && !cliScanner.truncatedPaths[path]
+	if truncated {
+		cliScanner.truncatedPaths[path] = true
+	} else {
+		delete(cliScanner.truncatedPaths, path)
+	}
+	return changed
+}
+
+func (cliScanner *CLIScanner) trackResult(success bool, omittedIssueCount int) {
 	var result ux2.Result
 	if success {
 		result = ux2.Success
@@ -377,8 +539,9 @@ func (cliScanner *CLIScanner) trackResult(success bool) {
 		result = ux2.Error
 	}
 	cliScanner.analytics.AnalysisIsReady(ux2.AnalysisIsReadyProperties{
-		AnalysisType: ux2.OpenSource,
-		Result:       result,
+		AnalysisType:      ux2.OpenSource,
+		Result:            result,
+		OmittedIssueCount: omittedIssueCount,
 	})
 }
 
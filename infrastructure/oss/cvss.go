@@ -0,0 +1,108 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"fmt"
+	"strings"
+)
+
+type cvssMetric struct {
+	name   string
+	values map[string]string
+}
+
+// cvssV3Metrics are the base and temporal metrics of CVSS v3 vectors in the order of the specification
+var cvssV3Metrics = []struct {
+	key string
+	cvssMetric
+}{
+	{"AV", cvssMetric{"Attack Vector", map[string]string{"N": "Network", "A": "Adjacent Network", "L": "Local", "P": "Physical"}}},
+	{"AC", cvssMetric{"Attack Complexity", map[string]string{"L": "Low", "H": "High"}}},
+	{"PR", cvssMetric{"Privileges Required", map[string]string{"N": "None", "L": "Low", "H": "High"}}},
+	{"UI", cvssMetric{"User Interaction", map[string]string{"N": "None", "R": "Required"}}},
+	{"S", cvssMetric{"Scope", map[string]string{"U": "Unchanged", "C": "Changed"}}},
+	{"C", cvssMetric{"Confidentiality", map[string]string{"H": "High", "L": "Low", "N": "None"}}},
+	{"I", cvssMetric{"Integrity", map[string]string{"H": "High", "L": "Low", "N": "None"}}},
+	{"A", cvssMetric{"Availability", map[string]string{"H": "High", "L": "Low", "N": "None"}}},
+	{"E", cvssMetric{"Exploit Code Maturity", map[string]string{
+		"X": "Not Defined", "H": "High", "F": "Functional", "P": "Proof-of-Concept", "U": "Unproven"}}},
+	{"RL", cvssMetric{"Remediation Level", map[string]string{
+		"X": "Not Defined", "U": "Unavailable", "W": "Workaround", "T": "Temporary Fix", "O": "Official Fix"}}},
+	{"RC", cvssMetric{"Report Confidence", map[string]string{
+		"X": "Not Defined", "C": "Confirmed", "R": "Reasonable", "U": "Unknown"}}},
+}
+
+const cvssV3BaseMetricCount = 8
+
+// cvssComponent is a decoded metric of a CVSS vector, e.g. Attack Vector: Network
+type cvssComponent struct {
+	Name  string
+	Value string
+}
+
+// parseCvssV3Vector decodes a CVSS v3 vector string (e.g. CVSS:3.1/AV:N/AC:L/...) into its base and temporal
+// metrics. Environmental metrics are ignored. It returns false if the vector is absent or malformed.
+func parseCvssV3Vector(vector string) ([]cvssComponent, bool) {
+	parts := strings.Split(strings.TrimSpace(vector), "/")
+	if parts[0] != "CVSS:3.0" && parts[0] != "CVSS:3.1" {
+		return nil, false
+	}
+
+	values := map[string]string{}
+	for _, part := range parts[1:] {
+		key, value, found := strings.Cut(part, ":")
+		if !found || value == "" {
+			return nil, false
+		}
+		if _, duplicate := values[key]; duplicate {
+			return nil, false
+		}
+		values[key] = value
+	}
+
+	var components []cvssComponent
+	for i, metric := range cvssV3Metrics {
+		value, present := values[metric.key]
+		if !present {
+			if i < cvssV3BaseMetricCount {
+				return nil, false
+			}
+			continue
+		}
+		label, known := metric.values[value]
+		if !known {
+			return nil, false
+		}
+		components = append(components, cvssComponent{Name: metric.name, Value: label})
+	}
+	return components, true
+}
+
+// cvssVectorMarkdown renders the decoded CVSS vector as markdown list, or an empty string if it can't be decoded
+func cvssVectorMarkdown(vector string) string {
+	components, ok := parseCvssV3Vector(vector)
+	if !ok {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString("\n**CVSS vector:**\n")
+	for _, component := range components {
+		builder.WriteString(fmt.Sprintf("- %s: %s\n", component.Name, component.Value))
+	}
+	return builder.String()
+}
@@ -0,0 +1,91 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_parseCvssV3Vector(t *testing.T) {
+	components, ok := parseCvssV3Vector("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:L/I:H/A:H/E:H/RL:O/RC:C")
+
+	assert.True(t, ok)
+	assert.Equal(t, []cvssComponent{
+		{Name: "Attack Vector", Value: "Network"},
+		{Name: "Attack Complexity", Value: "Low"},
+		{Name: "Privileges Required", Value: "None"},
+		{Name: "User Interaction", Value: "None"},
+		{Name: "Scope", Value: "Unchanged"},
+		{Name: "Confidentiality", Value: "Low"},
+		{Name: "Integrity", Value: "High"},
+		{Name: "Availability", Value: "High"},
+		{Name: "Exploit Code Maturity", Value: "High"},
+		{Name: "Remediation Level", Value: "Official Fix"},
+		{Name: "Report Confidence", Value: "Confirmed"},
+	}, components)
+}
+
+func Test_parseCvssV3Vector_IgnoresEnvironmentalMetrics(t *testing.T) {
+	components, ok := parseCvssV3Vector("CVSS:3.0/AV:P/AC:H/PR:H/UI:R/S:C/C:N/I:N/A:N/CR:H/MAV:N")
+
+	assert.True(t, ok)
+	assert.Len(t, components, 8)
+	assert.Equal(t, cvssComponent{Name: "Attack Vector", Value: "Physical"}, components[0])
+}
+
+func Test_parseCvssV3Vector_MalformedOrAbsentVectors(t *testing.T) {
+	for _, vector := range []string{
+		"",
+		"OSS CVSSv3",
+		"CVSS:2.0/AV:N/AC:L/Au:N/C:P/I:P/A:P",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:L/I:H",
+		"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:L/I:H/A:H",
+		"CVSS:3.1/AV:N/AV:L/AC:L/PR:N/UI:N/S:U/C:L/I:H/A:H",
+		"CVSS:3.1/AV/AC:L/PR:N/UI:N/S:U/C:L/I:H/A:H",
+	} {
+		_, ok := parseCvssV3Vector(vector)
+		assert.False(t, ok, vector)
+	}
+}
+
+func Test_GetExtendedMessage_RendersCvssVector(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetFormat(config.FormatMd)
+	issue := sampleIssue()
+	issue.CVSSv3 = "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:H/A:N"
+
+	message := issue.GetExtendedMessage(issue, config.FormatMd)
+
+	assert.Contains(t, message, "**CVSS vector:**\n- Attack Vector: Network\n- Attack Complexity: High\n")
+	assert.Contains(t, message, "- Availability: None\nGetting into Moria is an issue!")
+}
+
+func Test_GetExtendedMessage_OmitsMalformedCvssVector(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetFormat(config.FormatMd)
+	issue := sampleIssue()
+	issue.CVSSv3 = "CVSS:3.1/AV:N"
+
+	message := issue.GetExtendedMessage(issue, config.FormatMd)
+
+	assert.NotContains(t, message, "CVSS vector")
+}
@@ -0,0 +1,93 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// devDependencies returns the names of the packages the npm manifest of the project declares as development
+// dependencies only. The scan results don't contain the scope of dependencies, so it is read from the manifest.
+// Projects without an npm manifest have no known development dependencies.
+func devDependencies(targetFile affectedFile, manifest *affectedFile) map[string]bool {
+	var content []byte
+	switch {
+	case manifest != nil && filepath.Base(manifest.path) == "package.json":
+		content = manifest.content
+	case filepath.Base(targetFile.path) == "package.json":
+		content = targetFile.content
+	case lockFilesToManifestMap[filepath.Base(targetFile.path)] == "package.json":
+		content = readFileContent(filepath.Join(filepath.Dir(targetFile.path), "package.json"))
+	default:
+		return nil
+	}
+
+	var dependencies npmManifest
+	if json.Unmarshal(content, &dependencies) != nil {
+		return nil
+	}
+	devDependencies := map[string]bool{}
+	for name := range dependencies.DevDependencies {
+		_, isDependency := dependencies.Dependencies[name]
+		_, isOptional := dependencies.OptionalDependencies[name]
+		_, isPeer := dependencies.PeerDependencies[name]
+		if !isDependency && !isOptional && !isPeer {
+			devDependencies[name] = true
+		}
+	}
+	return devDependencies
+}
+
+// isDevDependency returns true if all dependency paths of the issue start with a development dependency, so that
+// the vulnerable package doesn't end up in production.
+func isDevDependency(issue ossIssue, res *scanResult, devDependencies map[string]bool) bool {
+	if len(devDependencies) == 0 {
+		return false
+	}
+	found := false
+	for _, vulnerability := range res.Vulnerabilities {
+		if vulnerability.Id != issue.Id || vulnerability.PackageName != issue.PackageName {
+			continue
+		}
+		if len(vulnerability.From) < 2 || !devDependencies[packageName(vulnerability.From[1])] {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// packageName strips the version from a package of a dependency path, e.g. "@babel/core@7.0.0" becomes
+// "@babel/core"
+func packageName(pkg string) string {
+	if i := strings.LastIndex(pkg, "@"); i > 0 {
+		return pkg[:i]
+	}
+	return pkg
+}
+
+// downgradeSeverity lowers the severity by one level, low and unknown severities are kept
+func downgradeSeverity(severity vulnmap.Severity) vulnmap.Severity {
+	if severity < vulnmap.Low {
+		return severity + 1
+	}
+	return severity
+}
@@ -0,0 +1,120 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/learn/mock_learn"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+const devDependenciesManifest = `{
+  "dependencies": {"express": "^4.0.0", "shared": "^1.0.0"},
+  "devDependencies": {"jest": "^29.0.0", "shared": "^1.0.0"}
+}`
+
+func convertDevDependencyScanResult(t *testing.T, vulnerabilities ...ossIssue) []vulnmap.Issue {
+	t.Helper()
+	config.CurrentConfig().SetVulnmapLearnCodeActionsEnabled(false)
+	return convertScanResultToIssues(
+		context.Background(),
+		config.CurrentConfig(),
+		&scanResult{Vulnerabilities: vulnerabilities},
+		affectedFile{path: filepath.Join(t.TempDir(), "package.json"), content: []byte(devDependenciesManifest)},
+		nil,
+		mock_learn.NewMockService(gomock.NewController(t)),
+		error_reporting.NewTestErrorReporter(),
+		map[string][]vulnmap.Issue{},
+	)
+}
+
+func devDependencyTestIssue(id string, from ...string) ossIssue {
+	return ossIssue{
+		Id:             id,
+		Title:          "Prototype Pollution",
+		Severity:       "high",
+		PackageManager: "npm",
+		PackageName:    id,
+		Version:        "1.0.0",
+		From:           from,
+	}
+}
+
+func Test_convertScanResultToIssues_DetectsDevDependenciesFromManifest(t *testing.T) {
+	testutil.UnitTest(t)
+
+	issues := convertDevDependencyScanResult(t,
+		devDependencyTestIssue("dev-only", "app@1.0.0", "jest@29.0.0", "dev-only@1.0.0"),
+		devDependencyTestIssue("prod", "app@1.0.0", "express@4.0.0", "prod@1.0.0"),
+		devDependencyTestIssue("dev-and-prod", "app@1.0.0", "jest@29.0.0", "dev-and-prod@1.0.0"),
+		devDependencyTestIssue("dev-and-prod", "app@1.0.0", "express@4.0.0", "dev-and-prod@1.0.0"),
+		devDependencyTestIssue("declared-twice", "app@1.0.0", "shared@1.0.0", "declared-twice@1.0.0"),
+	)
+
+	require.Len(t, issues, 4)
+	isDev := map[string]bool{}
+	for _, issue := range issues {
+		isDev[issue.ID] = issue.IsDevDependency()
+	}
+	assert.Equal(t, map[string]bool{"dev-only": true, "prod": false, "dev-and-prod": false, "declared-twice": false}, isDev)
+}
+
+func Test_convertScanResultToIssues_DevDependencyIssues(t *testing.T) {
+	tests := []struct {
+		treatment        config.DevDependencyIssues
+		expectedSeverity vulnmap.Severity
+		expectedTag      bool
+	}{
+		{config.DevDependencyIssuesShow, vulnmap.High, false},
+		{config.DevDependencyIssuesTag, vulnmap.High, true},
+		{config.DevDependencyIssuesDowngrade, vulnmap.Medium, false},
+	}
+	for _, test := range tests {
+		t.Run(string(test.treatment), func(t *testing.T) {
+			c := testutil.UnitTest(t)
+			c.SetDevDependencyIssues(test.treatment)
+
+			issues := convertDevDependencyScanResult(t,
+				devDependencyTestIssue("dev-only", "app@1.0.0", "jest@29.0.0", "dev-only@1.0.0"),
+				devDependencyTestIssue("prod", "app@1.0.0", "express@4.0.0", "prod@1.0.0"),
+			)
+
+			require.Len(t, issues, 2)
+			assert.Equal(t, test.expectedSeverity, issues[0].Severity)
+			assert.Equal(t, test.expectedTag, strings.HasPrefix(issues[0].Message, "[dev dependency] "))
+			assert.Equal(t, vulnmap.High, issues[1].Severity)
+			assert.False(t, strings.HasPrefix(issues[1].Message, "[dev dependency] "))
+		})
+	}
+}
+
+func Test_packageName(t *testing.T) {
+	assert.Equal(t, "lodash", packageName("lodash@4.17.20"))
+	assert.Equal(t, "@babel/core", packageName("@babel/core@7.0.0"))
+	assert.Equal(t, "@babel/core", packageName("@babel/core"))
+}
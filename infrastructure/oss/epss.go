@@ -0,0 +1,62 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// epssDetails contains the Exploit Prediction Scoring System (EPSS) data of a vulnerability. The CLI reports the
+// values as strings, older versions as numbers.
+type epssDetails struct {
+	Probability  any    `json:"probability,omitempty"`
+	Percentile   any    `json:"percentile,omitempty"`
+	ModelVersion string `json:"modelVersion,omitempty"`
+}
+
+// epssScore returns the probability of exploitation of the issue, nil if the feed has no valid EPSS score for it
+func (o ossIssue) epssScore() *float64 {
+	if o.EpssDetails == nil {
+		return nil
+	}
+	var score float64
+	switch probability := o.EpssDetails.Probability.(type) {
+	case float64:
+		score = probability
+	case string:
+		parsed, err := strconv.ParseFloat(probability, 64)
+		if err != nil {
+			return nil
+		}
+		score = parsed
+	default:
+		return nil
+	}
+	if score < 0 || score > 1 {
+		return nil
+	}
+	return &score
+}
+
+// epssMarkdown renders the EPSS score as a percentage, empty if the score is unknown
+func epssMarkdown(score *float64) string {
+	if score == nil {
+		return ""
+	}
+	return fmt.Sprintf(" | EPSS: %s%%", strconv.FormatFloat(*score*100, 'g', 3, 64))
+}
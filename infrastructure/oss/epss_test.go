@@ -0,0 +1,78 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func Test_epssScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected *float64
+	}{
+		{"string probability", `{"epssDetails": {"probability": "0.00045", "percentile": "0.12"}}`, ptr(0.00045)},
+		{"number probability", `{"epssDetails": {"probability": 0.5}}`, ptr(0.5)},
+		{"no EPSS details", `{}`, nil},
+		{"missing probability", `{"epssDetails": {"percentile": "0.12"}}`, nil},
+		{"invalid probability", `{"epssDetails": {"probability": "high"}}`, nil},
+		{"probability out of range", `{"epssDetails": {"probability": "1.5"}}`, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var issue ossIssue
+			require.NoError(t, json.Unmarshal([]byte(test.json), &issue))
+
+			assert.Equal(t, test.expected, issue.epssScore())
+		})
+	}
+}
+
+func Test_GetExtendedMessage_RendersEpssScoreAlongsideExploitMaturity(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetFormat(config.FormatMd)
+	issue := sampleIssue()
+	issue.EpssDetails = &epssDetails{Probability: "0.2505"}
+
+	message := issue.GetExtendedMessage(issue, config.FormatMd)
+
+	assert.Contains(t, message, "**Fixed in: Not Fixed | Exploit maturity: LOW | EPSS: 25.1%**")
+	assert.Equal(t, ptr(0.2505), issue.toAdditionalData("package.json", &scanResult{}).EpssScore)
+}
+
+func Test_GetExtendedMessage_OmitsUnknownEpssScore(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetFormat(config.FormatMd)
+	issue := sampleIssue()
+
+	message := issue.GetExtendedMessage(issue, config.FormatMd)
+
+	assert.NotContains(t, message, "EPSS")
+	assert.Nil(t, issue.toAdditionalData("package.json", &scanResult{}).EpssScore)
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
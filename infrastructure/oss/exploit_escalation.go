@@ -0,0 +1,40 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"strings"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// matureExploit is the exploit maturity of the feed that indicates a working public exploit. The other values are
+// "Proof of Concept", "No Known Exploit" and "Not Defined".
+const matureExploit = "Mature"
+
+// hasMatureExploit returns true if the exploit maturity indicates a mature public exploit
+func hasMatureExploit(exploit string) bool {
+	return strings.EqualFold(strings.TrimSpace(exploit), matureExploit)
+}
+
+// escalateSeverity raises the severity by one level, critical and unknown severities are kept
+func escalateSeverity(severity vulnmap.Severity) vulnmap.Severity {
+	if severity > vulnmap.Critical && severity <= vulnmap.Low {
+		return severity - 1
+	}
+	return severity
+}
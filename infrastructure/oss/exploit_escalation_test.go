@@ -0,0 +1,77 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func exploitTestIssue(id string, exploit string) ossIssue {
+	issue := devDependencyTestIssue(id, "app@1.0.0", "express@4.0.0", id+"@1.0.0")
+	issue.Severity = "medium"
+	issue.Exploit = exploit
+	return issue
+}
+
+func Test_convertScanResultToIssues_EscalatesSeverityOfMatureExploits(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetExploitSeverityEscalation(true)
+
+	issues := convertDevDependencyScanResult(t,
+		exploitTestIssue("mature", "Mature"),
+		exploitTestIssue("proof-of-concept", "Proof of Concept"),
+		exploitTestIssue("no-exploit", ""),
+	)
+
+	require.Len(t, issues, 3)
+	assert.Equal(t, vulnmap.High, issues[0].Severity)
+	assert.Equal(t, "medium", issues[0].AdditionalData.(vulnmap.OssIssueData).FeedSeverity)
+	assert.Equal(t, vulnmap.Medium, issues[1].Severity)
+	assert.Equal(t, vulnmap.Medium, issues[2].Severity)
+	assert.Equal(t, "medium", issues[2].AdditionalData.(vulnmap.OssIssueData).FeedSeverity)
+}
+
+func Test_convertScanResultToIssues_KeepsSeverityOfMatureExploitsByDefault(t *testing.T) {
+	testutil.UnitTest(t)
+
+	issues := convertDevDependencyScanResult(t, exploitTestIssue("mature", "Mature"))
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, vulnmap.Medium, issues[0].Severity)
+}
+
+func Test_escalateSeverity(t *testing.T) {
+	assert.Equal(t, vulnmap.Critical, escalateSeverity(vulnmap.Critical))
+	assert.Equal(t, vulnmap.Critical, escalateSeverity(vulnmap.High))
+	assert.Equal(t, vulnmap.High, escalateSeverity(vulnmap.Medium))
+	assert.Equal(t, vulnmap.Medium, escalateSeverity(vulnmap.Low))
+	assert.Equal(t, vulnmap.Unknown, escalateSeverity(vulnmap.Unknown))
+}
+
+func Test_hasMatureExploit(t *testing.T) {
+	assert.True(t, hasMatureExploit("Mature"))
+	assert.False(t, hasMatureExploit("Proof of Concept"))
+	assert.False(t, hasMatureExploit("No Known Exploit"))
+	assert.False(t, hasMatureExploit("Not Defined"))
+	assert.False(t, hasMatureExploit(""))
+}
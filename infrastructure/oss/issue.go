@@ -17,6 +17,7 @@
 package oss
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"net/url"
@@ -33,14 +34,7 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 )
 
-var issuesSeverity = map[string]vulnmap.Severity{
-	"critical": vulnmap.Critical,
-	"high":     vulnmap.High,
-	"low":      vulnmap.Low,
-	"medium":   vulnmap.Medium,
-}
-
-func (i *ossIssue) AddCodeActions(learnService learn.Service, ep error_reporting.ErrorReporter) (actions []vulnmap.
+func (i *ossIssue) AddCodeActions(ctx context.Context, learnService learn.Service, ep error_reporting.ErrorReporter) (actions []vulnmap.
 	CodeAction) {
 	title := fmt.Sprintf("Open description of '%s affecting package %s' in browser (Vulnmap)", i.Title, i.PackageName)
 	command := &vulnmap.CommandData{
@@ -52,17 +46,18 @@ func (i *ossIssue) AddCodeActions(learnService learn.Service, ep error_reporting
 	action, _ := vulnmap.NewCodeAction(title, nil, command)
 	actions = append(actions, action)
 
-	codeAction := i.AddVulnmapLearnAction(learnService, ep)
+	codeAction := i.AddVulnmapLearnAction(ctx, learnService, ep)
 	if codeAction != nil {
 		actions = append(actions, *codeAction)
 	}
 	return actions
 }
 
-func (i *ossIssue) AddVulnmapLearnAction(learnService learn.Service, ep error_reporting.ErrorReporter) (action *vulnmap.
+func (i *ossIssue) AddVulnmapLearnAction(ctx context.Context, learnService learn.Service, ep error_reporting.ErrorReporter) (action *vulnmap.
 	CodeAction) {
 	if config.CurrentConfig().IsVulnmapLearnCodeActionsEnabled() {
-		lesson, err := learnService.GetLesson(i.PackageManager, i.Id, i.Identifiers.CWE, i.Identifiers.CVE, vulnmap.DependencyVulnerability)
+		lesson, err := learnService.GetLesson(ctx, i.PackageManager, i.Id, i.Identifiers.CWE, i.Identifiers.CVE,
+			vulnmap.DependencyVulnerability)
 		if err != nil {
 			msg := "failed to get lesson"
 			log.Err(err).Msg(msg)
@@ -87,91 +82,196 @@ func (i *ossIssue) AddVulnmapLearnAction(learnService learn.Service, ep error_re
 	return action
 }
 
-func (i *ossIssue) GetExtendedMessage(issue ossIssue) string {
+// GetExtendedMessage renders the details of the issue shown e.g. in hovers in the given output format
+func (i *ossIssue) GetExtendedMessage(issue ossIssue, format string) string {
 	title := issue.Title
 	description := issue.Description
+	cvssVector := cvssVectorMarkdown(issue.CVSSv3)
+	introducedBy := introducedByMarkdown(issue.From)
+	advisoryDates := advisoryDatesMarkdown(parseAdvisoryTime(issue.DisclosureTime), parseAdvisoryTime(issue.ModificationTime))
 
-	if config.CurrentConfig().Format() == config.FormatHtml {
+	if format == config.FormatHtml {
 		title = string(markdown.ToHTML([]byte(title), nil, nil))
 		description = string(markdown.ToHTML([]byte(description), nil, nil))
+		if cvssVector != "" {
+			cvssVector = string(markdown.ToHTML([]byte(cvssVector), nil, nil))
+		}
+		if introducedBy != "" {
+			introducedBy = string(markdown.ToHTML([]byte(introducedBy), nil, nil))
+		}
+		if advisoryDates != "" {
+			advisoryDates = string(markdown.ToHTML([]byte(advisoryDates), nil, nil))
+		}
 	}
-	summary := fmt.Sprintf("### Vulnerability %s %s %s \n **Fixed in: %s | Exploit maturity: %s**",
-		issue.createCveLink(),
+	summary := fmt.Sprintf("### Vulnerability %s %s %s \n **Fixed in: %s | Exploit maturity: %s%s**",
+		issue.createAdvisoryLinks(),
 		issue.createCweLink(),
 		issue.createIssueUrlMarkdown(),
 		issue.createFixedIn(),
 		strings.ToUpper(issue.Severity),
+		epssMarkdown(issue.epssScore()),
 	)
 
-	return fmt.Sprintf("\n### %s: %s affecting %s package \n%s \n%s",
+	return fmt.Sprintf("\n### %s: %s affecting %s package \n%s \n%s%s%s%s",
 		issue.Id,
 		title,
 		issue.PackageName,
 		summary,
+		advisoryDates,
+		introducedBy,
+		cvssVector,
 		description)
 }
 
-func (i *ossIssue) createCveLink() string {
-	var formattedCve string
-	for _, c := range i.Identifiers.CVE {
-		formattedCve += fmt.Sprintf("| [%s](https://cve.mitre.org/cgi-bin/cvename.cgi?name=%s)", c, c)
+// introducedByMarkdown renders the dependency path of a transitive vulnerable package from the project to the
+// package and names the direct dependency that needs to be upgraded. The first element of the path is the project
+// itself, so nothing is rendered for direct dependencies as the path wouldn't add any information.
+func introducedByMarkdown(from []string) string {
+	if len(from) <= 2 {
+		return ""
 	}
-	return formattedCve
+	return fmt.Sprintf("\n**Introduced through:** %s\n\n**Direct dependency:** %s\n",
+		strings.Join(from, " → "),
+		from[1])
+}
+
+// advisoryLink is a CVE or GHSA identifier with the web page of its advisory
+type advisoryLink struct {
+	id  string
+	url string
+}
+
+// advisoryLinks returns the advisory identifiers of the issue in the order of the configured preference
+func (i *ossIssue) advisoryLinks() []advisoryLink {
+	var cves, ghsas []advisoryLink
+	for _, id := range i.Identifiers.CVE {
+		cves = append(cves, advisoryLink{id: id, url: "https://cve.mitre.org/cgi-bin/cvename.cgi?name=" + id})
+	}
+	for _, id := range i.Identifiers.GHSA {
+		ghsas = append(ghsas, advisoryLink{id: id, url: "https://github.com/advisories/" + id})
+	}
+
+	switch config.CurrentConfig().AdvisoryIdentifierPreference() {
+	case config.AdvisoryIdentifierPreferenceAll:
+		return append(cves, ghsas...)
+	case config.AdvisoryIdentifierPreferenceGhsa:
+		if len(ghsas) > 0 {
+			return ghsas
+		}
+		return cves
+	default:
+		if len(cves) > 0 {
+			return cves
+		}
+		return ghsas
+	}
+}
+
+func (i *ossIssue) createAdvisoryLinks() string {
+	var formattedLinks string
+	for _, link := range i.advisoryLinks() {
+		formattedLinks += "| " + formatLink(link.id, link.url)
+	}
+	return formattedLinks
 }
 
 func (i *ossIssue) createIssueUrlMarkdown() string {
-	return fmt.Sprintf("| [%s](%s)", i.Id, i.CreateIssueURL().String())
+	return "| " + formatLink(i.Id, i.CreateIssueURL().String())
+}
+
+// formatLink renders a link in the configured link style
+func formatLink(text string, linkUrl string) string {
+	switch config.CurrentConfig().LinkStyle() {
+	case config.LinkStyleUrl:
+		return fmt.Sprintf("%s: %s", text, linkUrl)
+	case config.LinkStyleText:
+		return text
+	default:
+		return fmt.Sprintf("[%s](%s)", text, linkUrl)
+	}
 }
 
 func (i *ossIssue) CreateIssueURL() *url.URL {
-	parse, err := url.Parse("https://vulnmap.khulnasoft.com/vuln/" + i.Id)
-	if err != nil {
-		log.Err(err).Msg("Unable to create issue link for issue:" + i.Id)
+	issueURL, ok := vulnmap.IssueURL(product.ProductOpenSource, i.Id)
+	if !ok {
+		return &url.URL{}
 	}
-	return parse
+	return issueURL
 }
 
 func (i *ossIssue) createFixedIn() string {
-	var f string
 	if len(i.FixedIn) < 1 {
-		f += "Not Fixed"
-	} else {
-		f += "@" + i.FixedIn[0]
-		for _, version := range i.FixedIn[1:] {
-			f += fmt.Sprintf(", %s", version)
+		return "Not Fixed"
+	}
+
+	// range expressions are rendered by their lower bound, which is the first version containing the fix
+	fixedIn := make([]string, 0, len(i.FixedIn))
+	for _, version := range i.FixedIn {
+		constraints := strings.Fields(version)
+		version = strings.Join(constraints, " ")
+		if isVersionRange(version) {
+			for _, constraint := range constraints {
+				if strings.HasPrefix(constraint, ">") {
+					version = constraint
+					break
+				}
+			}
 		}
+		fixedIn = append(fixedIn, version)
+	}
+	f := strings.Join(fixedIn, ", ")
+	if !isVersionRange(i.FixedIn[0]) {
+		f = "@" + f
 	}
 	return f
 }
 
+// isVersionRange returns true if the fixed-in entry is a range expression (e.g. ">=1.2.3 <2.0.0")
+// instead of a single version
+func isVersionRange(version string) bool {
+	return strings.ContainsAny(strings.TrimSpace(version), "<>=")
+}
+
 func (i *ossIssue) createCweLink() string {
 	var formattedCwe string
 	for _, c := range i.Identifiers.CWE {
 		id := strings.Replace(c, "CWE-", "", -1)
-		formattedCwe += fmt.Sprintf("| [%s](https://cwe.mitre.org/data/definitions/%s.html)", c, id)
+		formattedCwe += "| " + formatLink(c, fmt.Sprintf("https://cwe.mitre.org/data/definitions/%s.html", id))
 	}
 	return formattedCwe
 }
 
-func (i *ossIssue) ToIssueSeverity() vulnmap.Severity {
-	sev, ok := issuesSeverity[i.Severity]
-	if !ok {
-		return vulnmap.Low
+// ToIssueSeverity returns the severity of the issue. The severity of license issues can be overridden per license
+// in the configuration.
+func (i *ossIssue) ToIssueSeverity(c *config.Config) vulnmap.Severity {
+	if i.isLicenseIssue() {
+		if severity, ok := c.LicenseSeverity(i.License); ok {
+			return ToSeverity(c, severity)
+		}
 	}
-	return sev
+	return ToSeverity(c, i.Severity)
+}
+
+func (i *ossIssue) isLicenseIssue() bool {
+	return i.Type == "license"
 }
 
+// toIssue converts the vulnerability to an issue with its message in the given output format. The formatted message
+// is rendered in the formats of the rendering contexts.
 func toIssue(
+	ctx context.Context,
+	c *config.Config,
 	affectedFilePath string,
 	issue ossIssue,
 	scanResult *scanResult,
 	issueRange vulnmap.Range,
+	format string,
 	learnService learn.Service,
 	ep error_reporting.ErrorReporter,
 ) vulnmap.Issue {
 	title := issue.Title
 
-	if config.CurrentConfig().Format() == config.FormatHtml {
+	if format == config.FormatHtml {
 		title = string(markdown.ToHTML([]byte(title), nil, nil))
 	}
 	var action = "No fix available."
@@ -202,22 +302,51 @@ func toIssue(
 		action,
 		resolution,
 	)
-	return vulnmap.Issue{
+	severity := issue.ToIssueSeverity(c)
+	if issue.isDevDependency {
+		switch c.DevDependencyIssues() {
+		case config.DevDependencyIssuesTag:
+			message = "[dev dependency] " + message
+		case config.DevDependencyIssuesDowngrade:
+			severity = downgradeSeverity(severity)
+		default:
+		}
+	}
+	if c.IsExploitSeverityEscalationEnabled() && hasMatureExploit(issue.Exploit) {
+		severity = escalateSeverity(severity)
+	}
+	vulnmapIssue := vulnmap.Issue{
 		ID:                  issue.Id,
+		DisplayID:           issue.displayId(),
 		Message:             message,
-		FormattedMessage:    issue.GetExtendedMessage(issue),
 		Range:               issueRange,
-		Severity:            issue.ToIssueSeverity(),
+		Severity:            severity,
 		AffectedFilePath:    affectedFilePath,
 		Product:             product.ProductOpenSource,
 		IssueDescriptionURL: issue.CreateIssueURL(),
 		IssueType:           vulnmap.DependencyVulnerability,
-		CodeActions:         issue.AddCodeActions(learnService, ep),
+		CodeActions:         issue.AddCodeActions(ctx, learnService, ep),
 		Ecosystem:           issue.PackageManager,
 		CWEs:                issue.Identifiers.CWE,
 		CVEs:                issue.Identifiers.CVE,
 		AdditionalData:      issue.toAdditionalData(affectedFilePath, scanResult),
 	}
+	vulnmapIssue.FormattedMessage, vulnmapIssue.FormattedMessages = vulnmap.RenderFormattedMessages(c,
+		func(format string) string { return issue.GetExtendedMessage(issue, format) })
+	// the lesson is looked up while adding the code actions
+	if issue.lesson != nil {
+		vulnmapIssue.LessonURL, _ = url.Parse(issue.lesson.Url)
+	}
+	return vulnmapIssue
+}
+
+// displayId returns the id shown to users, which is the first CVE if configured. Otherwise, it's empty and the Vulnmap
+// id is shown. The issue is always identified by the Vulnmap id, as a CVE can be shared by distinct vulnerabilities.
+func (o ossIssue) displayId() string {
+	if config.CurrentConfig().IssueIdentifier() == config.IssueIdentifierCve && len(o.Identifiers.CVE) > 0 {
+		return o.Identifiers.CVE[0]
+	}
+	return ""
 }
 
 func (o ossIssue) toAdditionalData(filepath string, scanResult *scanResult) vulnmap.OssIssueData {
@@ -239,11 +368,16 @@ func (o ossIssue) toAdditionalData(filepath string, scanResult *scanResult) vuln
 	additionalData.CVSSv3 = o.CVSSv3
 	additionalData.CvssScore = o.CvssScore
 	additionalData.Exploit = o.Exploit
+	additionalData.FeedSeverity = o.Severity
+	additionalData.EpssScore = o.epssScore()
+	additionalData.DisclosureTime = parseAdvisoryTime(o.DisclosureTime)
+	additionalData.ModificationTime = parseAdvisoryTime(o.ModificationTime)
 	additionalData.IsPatchable = o.IsPatchable
 	additionalData.ProjectName = scanResult.ProjectName
 	additionalData.DisplayTargetFile = scanResult.DisplayTargetFile
 	additionalData.Language = o.Language
 	additionalData.Details = getDetailsHtml(&o)
+	additionalData.IsDevDependency = o.isDevDependency
 
 	return additionalData
 }
@@ -261,16 +395,57 @@ func (r reference) toReference() vulnmap.Reference {
 	if err != nil {
 		log.Err(err).Msg("Unable to parse reference url: " + string(r.Url))
 	}
+	title := r.Title
+	if title == "" {
+		title = referenceTitleFromUrl(url, string(r.Url))
+	}
 	return vulnmap.Reference{
 		Url:   url,
-		Title: r.Title,
+		Title: title,
+	}
+}
+
+// knownReferenceHosts maps hosts of commonly referenced sites to a readable label
+var knownReferenceHosts = map[string]string{
+	"nvd.nist.gov":           "NVD",
+	"cve.mitre.org":          "MITRE",
+	"cwe.mitre.org":          "CWE",
+	"vulnmap.khulnasoft.com": "Vulnmap",
+}
+
+// referenceTitleFromUrl derives a title for references without one, either from a known host label
+// or from the host and the last path segment of the url
+func referenceTitleFromUrl(u *url.URL, rawUrl string) string {
+	if u == nil || u.Host == "" {
+		return rawUrl
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if label, ok := knownReferenceHosts[host]; ok {
+		return label
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	lastSegment := segments[len(segments)-1]
+	if lastSegment == "" {
+		return host
 	}
+	return host + " " + lastSegment
+}
+
+// affectedFile is a file issues are reported on, along with its content to determine issue ranges
+type affectedFile struct {
+	path    string
+	content []byte
 }
 
+// convertScanResultToIssues converts the vulnerabilities of a scan result to issues on the target file. If a
+// manifest is given, issues of direct dependencies declared in it are reported on the manifest instead. If the
+// context is cancelled during the conversion, pending lesson lookups are aborted and no issues are returned.
 func convertScanResultToIssues(
+	ctx context.Context,
+	c *config.Config,
 	res *scanResult,
-	path string,
-	fileContent []byte,
+	targetFile affectedFile,
+	manifest *affectedFile,
 	ls learn.Service,
 	ep error_reporting.ErrorReporter,
 	packageIssueCache map[string][]vulnmap.Issue,
@@ -278,18 +453,90 @@ func convertScanResultToIssues(
 	var issues []vulnmap.Issue
 
 	duplicateCheckMap := map[string]bool{}
-
+	var uniqueIssues []ossIssue
 	for _, issue := range res.Vulnerabilities {
-		packageKey := issue.PackageName + "@" + issue.Version
 		duplicateKey := issue.Id + "|" + issue.PackageName
 		if duplicateCheckMap[duplicateKey] {
 			continue
 		}
-		issueRange := findRange(issue, path, fileContent)
-		vulnmapIssue := toIssue(path, issue, res, issueRange, ls, ep)
+		uniqueIssues = append(uniqueIssues, issue)
+		duplicateCheckMap[duplicateKey] = true
+	}
+
+	if c.IsVulnmapLearnCodeActionsEnabled() {
+		ls = prefetchLessons(ctx, ls, uniqueIssues, c.LearnLessonLookupConcurrency())
+	}
+
+	devPackages := devDependencies(targetFile, manifest)
+	for _, issue := range uniqueIssues {
+		if ctx.Err() != nil {
+			return nil
+		}
+		issue.isDevDependency = isDevDependency(issue, res, devPackages)
+		packageKey := issue.PackageName + "@" + issue.Version
+		path, issueRange := locateIssue(issue, res, targetFile, manifest)
+		vulnmapIssue := toIssue(ctx, c, path, issue, res, issueRange, c.FormatFor(config.RenderContextDiagnostic), ls, ep)
 		packageIssueCache[packageKey] = append(packageIssueCache[packageKey], vulnmapIssue)
 		issues = append(issues, vulnmapIssue)
-		duplicateCheckMap[duplicateKey] = true
 	}
 	return issues
 }
+
+// linkMatchingIssues adds the other occurrences of the same vulnerability, e.g. in other manifests or packages, to
+// the related locations of each issue, so that users can navigate between them. Issues that occur only once are
+// left untouched.
+func linkMatchingIssues(issues []vulnmap.Issue) {
+	indexesByID := map[string][]int{}
+	for i, issue := range issues {
+		indexesByID[issue.ID] = append(indexesByID[issue.ID], i)
+	}
+	for _, indexes := range indexesByID {
+		if len(indexes) < 2 {
+			continue
+		}
+		for _, i := range indexes {
+			relatedLocations := make([]vulnmap.RelatedLocation, 0, len(indexes)-1)
+			for _, j := range indexes {
+				if i == j {
+					continue
+				}
+				relatedLocations = append(relatedLocations, vulnmap.RelatedLocation{
+					FilePath: issues[j].AffectedFilePath,
+					Range:    issues[j].Range,
+					Message:  matchingIssueLabel(issues[j]),
+				})
+			}
+			issues[i].RelatedLocations = relatedLocations
+		}
+	}
+}
+
+func matchingIssueLabel(issue vulnmap.Issue) string {
+	if data, ok := issue.AdditionalData.(vulnmap.OssIssueData); ok && data.PackageName != "" {
+		return fmt.Sprintf("%s also affects %s@%s", issue.ID, data.PackageName, data.Version)
+	}
+	return fmt.Sprintf("%s also occurs here", issue.ID)
+}
+
+// locateIssue returns the file path and range an issue is reported on. Direct dependencies are located on the
+// manifest if one is given and declares the dependency, everything else falls back to the target file.
+func locateIssue(issue ossIssue, res *scanResult, targetFile affectedFile, manifest *affectedFile) (string, vulnmap.Range) {
+	if manifest != nil && isDirectDependency(issue, res) {
+		issueRange := findRange(issue, manifest.path, manifest.content)
+		if issueRange != (vulnmap.Range{}) {
+			return manifest.path, issueRange
+		}
+	}
+	return targetFile.path, findRange(issue, targetFile.path, targetFile.content)
+}
+
+// isDirectDependency returns true if the vulnerable package is introduced directly by the project in any of the
+// dependency paths of the issue.
+func isDirectDependency(issue ossIssue, res *scanResult) bool {
+	for _, vulnerability := range res.Vulnerabilities {
+		if vulnerability.Id == issue.Id && vulnerability.PackageName == issue.PackageName && len(vulnerability.From) <= 2 {
+			return true
+		}
+	}
+	return false
+}
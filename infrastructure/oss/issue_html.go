@@ -40,9 +40,8 @@ func getIdentifiers(issue *ossIssue) string {
 		issueTypeString = "License"
 	}
 
-	for _, id := range issue.Identifiers.CVE {
-		url := "https://cve.mitre.org/cgi-bin/cvename.cgi?name=" + id
-		htmlAnchor := fmt.Sprintf("<a href='%s'>%s</a>", url, id)
+	for _, link := range issue.advisoryLinks() {
+		htmlAnchor := fmt.Sprintf("<a href='%s'>%s</a>", link.url, link.id)
 		identifierList = append(identifierList, htmlAnchor)
 	}
 
@@ -106,8 +105,7 @@ func getFixedIn(issue *ossIssue) string {
 		return "Not fixed"
 	}
 
-	result := "%s@%v"
-	return fmt.Sprintf(result, issue.Name, strings.Join(issue.FixedIn, ", "))
+	return issue.Name + issue.createFixedIn()
 }
 
 func getOutdatedDependencyMessage(issue *ossIssue) string {
@@ -25,6 +25,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/learn"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
 )
@@ -98,3 +99,20 @@ func Test_OssDetailsPanel_html_withLearn(t *testing.T) {
 
 	assert.True(t, strings.Contains(issueDetailsPanelHtml, "Learn about this vulnerability"))
 }
+
+func Test_OssDetailsPanel_html_advisoryIdentifiers(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetAdvisoryIdentifierPreference(config.AdvisoryIdentifierPreferenceGhsa)
+	issue := ossIssue{
+		Title:       "myTitle",
+		Severity:    "high",
+		Id:          "randomId",
+		Identifiers: identifiers{CVE: []string{"CVE-2021-23337"}, GHSA: []string{"GHSA-35jh-r3h4-6jhm"}},
+	}
+	issue.matchingIssues = append(issue.matchingIssues, issue)
+
+	issueDetailsPanelHtml := getDetailsHtml(&issue)
+
+	assert.Contains(t, issueDetailsPanelHtml, "<a href='https://github.com/advisories/GHSA-35jh-r3h4-6jhm'>GHSA-35jh-r3h4-6jhm</a>")
+	assert.NotContains(t, issueDetailsPanelHtml, "CVE-2021-23337")
+}
@@ -0,0 +1,124 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"context"
+	"sync"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/learn"
+)
+
+// lessonKey identifies a lesson lookup. The learn service only considers the first CWE and CVE of an issue, and
+// the rule (the issue ID) as its cache key.
+type lessonKey struct {
+	ecosystem string
+	rule      string
+	cwe       string
+	cve       string
+}
+
+func newLessonKey(issue ossIssue) lessonKey {
+	key := lessonKey{ecosystem: issue.PackageManager, rule: issue.Id}
+	if len(issue.Identifiers.CWE) > 0 {
+		key.cwe = issue.Identifiers.CWE[0]
+	}
+	if len(issue.Identifiers.CVE) > 0 {
+		key.cve = issue.Identifiers.CVE[0]
+	}
+	return key
+}
+
+type lessonResult struct {
+	lesson *learn.Lesson
+	err    error
+}
+
+// prefetchedLessonService serves the lessons that were looked up in advance and delegates all other calls to the
+// wrapped learn service.
+type prefetchedLessonService struct {
+	learn.Service
+	lessons map[lessonKey]lessonResult
+}
+
+func (s *prefetchedLessonService) GetLesson(
+	ctx context.Context,
+	ecosystem string,
+	rule string,
+	cwes []string,
+	cves []string,
+	issueType vulnmap.Type,
+) (*learn.Lesson, error) {
+	key := newLessonKey(ossIssue{PackageManager: ecosystem, Id: rule, Identifiers: identifiers{CWE: cwes, CVE: cves}})
+	if result, ok := s.lessons[key]; ok && issueType == vulnmap.DependencyVulnerability {
+		return result.lesson, result.err
+	}
+	return s.Service.GetLesson(ctx, ecosystem, rule, cwes, cves, issueType)
+}
+
+// prefetchLessons looks up the lessons of the given issues with at most concurrency parallel lookups.
+// Issues with the same lookup key share a single lookup. The returned service serves the prefetched lessons.
+// Once the context is cancelled no further lookups are started, and pending lookups are aborted by the learn service.
+func prefetchLessons(
+	ctx context.Context,
+	learnService learn.Service,
+	issues []ossIssue,
+	concurrency int,
+) learn.Service {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	lessonsByKey := map[lessonKey]lessonResult{}
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	semaphore := make(chan struct{}, concurrency)
+	requested := map[lessonKey]bool{}
+	for _, issue := range issues {
+		key := newLessonKey(issue)
+		if requested[key] {
+			continue
+		}
+		requested[key] = true
+
+		select {
+		case semaphore <- struct{}{}: // Acquire semaphore
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(issue ossIssue) {
+			defer wg.Done()
+			defer func() { <-semaphore }() // Release semaphore
+			lesson, err := learnService.GetLesson(
+				ctx,
+				issue.PackageManager,
+				issue.Id,
+				issue.Identifiers.CWE,
+				issue.Identifiers.CVE,
+				vulnmap.DependencyVulnerability,
+			)
+			mutex.Lock()
+			lessonsByKey[key] = lessonResult{lesson: lesson, err: err}
+			mutex.Unlock()
+		}(issue)
+	}
+	wg.Wait()
+	return &prefetchedLessonService{Service: learnService, lessons: lessonsByKey}
+}
@@ -0,0 +1,152 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/learn"
+	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/learn/mock_learn"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func scanResultWithIdenticalLessonKeys(n int) *scanResult {
+	res := &scanResult{}
+	for i := 0; i < n; i++ {
+		res.Vulnerabilities = append(res.Vulnerabilities, ossIssue{
+			Id:             "VULNMAP-JS-MINIMIST-559764",
+			Title:          "Prototype Pollution",
+			PackageManager: "npm",
+			PackageName:    fmt.Sprintf("package-%d", i),
+			Version:        "1.0.0",
+			Identifiers:    identifiers{CWE: []string{"CWE-1321"}, CVE: []string{"CVE-2020-7598"}},
+		})
+	}
+	return res
+}
+
+func Test_convertScanResultToIssues_IdenticalLessonKeysShareOneLookup(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetVulnmapLearnCodeActionsEnabled(true)
+	learnMock := mock_learn.NewMockService(gomock.NewController(t))
+	learnMock.
+		EXPECT().
+		GetLesson(gomock.Any(), "npm", "VULNMAP-JS-MINIMIST-559764", []string{"CWE-1321"}, []string{"CVE-2020-7598"}, vulnmap.DependencyVulnerability).
+		Return(&learn.Lesson{Url: "https://learn.vulnmap.khulnasoft.com/lesson"}, nil).
+		Times(1)
+
+	issues := convertScanResultToIssues(
+		context.Background(),
+		config.CurrentConfig(),
+		scanResultWithIdenticalLessonKeys(50),
+		affectedFile{path: "package.json"},
+		nil,
+		learnMock,
+		error_reporting.NewTestErrorReporter(),
+		map[string][]vulnmap.Issue{},
+	)
+
+	assert.Len(t, issues, 50)
+	for _, issue := range issues {
+		assert.Len(t, issue.CodeActions, 2)
+		assert.Equal(t, "https://learn.vulnmap.khulnasoft.com/lesson", issue.CodeActions[1].Command.Arguments[0])
+	}
+}
+
+func Test_convertScanResultToIssues_CancellationAbortsPendingLessonLookups(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetVulnmapLearnCodeActionsEnabled(true)
+	c.SetLearnLessonLookupConcurrency(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	started := make(chan struct{}, 10)
+	observedCancellations := atomic.Int32{}
+	learnMock := mock_learn.NewMockService(gomock.NewController(t))
+	learnMock.EXPECT().GetLesson(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _, _ string, _, _ []string, _ vulnmap.Type) (*learn.Lesson, error) {
+			started <- struct{}{}
+			<-ctx.Done()
+			observedCancellations.Add(1)
+			return nil, ctx.Err()
+		}).
+		MinTimes(1).
+		MaxTimes(2)
+	res := &scanResult{}
+	for i := 0; i < 10; i++ {
+		res.Vulnerabilities = append(res.Vulnerabilities, ossIssue{
+			Id:             fmt.Sprintf("VULNMAP-JS-PACKAGE-%d", i),
+			PackageManager: "npm",
+			PackageName:    fmt.Sprintf("package-%d", i),
+		})
+	}
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	issues := convertScanResultToIssues(
+		ctx,
+		config.CurrentConfig(),
+		res,
+		affectedFile{path: "package.json"},
+		nil,
+		learnMock,
+		error_reporting.NewTestErrorReporter(),
+		map[string][]vulnmap.Issue{},
+	)
+
+	assert.Empty(t, issues, "partially converted issues must be discarded")
+	assert.Equal(t, int32(len(started)+1), observedCancellations.Load(), "all pending lookups must observe the cancellation")
+}
+
+func Test_prefetchLessons_DifferentKeysAreLookedUpSeparately(t *testing.T) {
+	learnMock := mock_learn.NewMockService(gomock.NewController(t))
+	learnMock.EXPECT().GetLesson(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&learn.Lesson{}, nil).
+		Times(2)
+	issues := []ossIssue{
+		{Id: "VULNMAP-JS-LODASH-567746", PackageManager: "npm"},
+		{Id: "VULNMAP-JS-LODASH-567746", PackageManager: "npm"},
+		{Id: "VULNMAP-JS-MINIMIST-559764", PackageManager: "npm"},
+	}
+
+	prefetchLessons(context.Background(), learnMock, issues, 2)
+}
+
+func Benchmark_convertScanResultToIssues_IdenticalLessonKeys(b *testing.B) {
+	learnMock := mock_learn.NewMockService(gomock.NewController(b))
+	learnMock.EXPECT().GetLesson(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&learn.Lesson{Url: "https://learn.vulnmap.khulnasoft.com/lesson"}, nil).
+		AnyTimes()
+	res := scanResultWithIdenticalLessonKeys(100)
+	errorReporter := error_reporting.NewTestErrorReporter()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		convertScanResultToIssues(context.Background(), config.CurrentConfig(), res, affectedFile{path: "package.json"}, nil, learnMock, errorReporter,
+			map[string][]vulnmap.Issue{})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"path/filepath"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// targetFileIssues are the issues of the scan result of one target file, e.g. a manifest or a lockfile
+type targetFileIssues struct {
+	displayTargetFile string
+	issues            []vulnmap.Issue
+}
+
+// mergeTargetFileIssues concatenates the issues of all target files. When duplicates are collapsed, issues of a
+// lockfile are dropped if the same vulnerability of the same package version is also reported for its manifest.
+func mergeTargetFileIssues(results []targetFileIssues, duplicates config.ManifestLockfileDuplicates) []vulnmap.Issue {
+	manifestIssueKeys := map[string]bool{}
+	if duplicates == config.ManifestLockfileDuplicatesCollapse {
+		for _, result := range results {
+			if isLockfile(result.displayTargetFile) {
+				continue
+			}
+			for _, issue := range result.issues {
+				manifestIssueKeys[duplicateIssueKey(result.displayTargetFile, issue)] = true
+			}
+		}
+	}
+
+	var issues []vulnmap.Issue
+	for _, result := range results {
+		if len(manifestIssueKeys) == 0 || !isLockfile(result.displayTargetFile) {
+			issues = append(issues, result.issues...)
+			continue
+		}
+		manifestFile := lockfileManifest(result.displayTargetFile)
+		for _, issue := range result.issues {
+			if manifestIssueKeys[duplicateIssueKey(manifestFile, issue)] {
+				continue
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// duplicateIssueKey identifies a vulnerability of a package version reported for a target file
+func duplicateIssueKey(displayTargetFile string, issue vulnmap.Issue) string {
+	key := filepath.Clean(displayTargetFile) + "|" + issue.ID
+	if data, ok := issue.AdditionalData.(vulnmap.OssIssueData); ok {
+		key += "|" + data.PackageName + "@" + data.Version
+	}
+	return key
+}
+
+// lockfileManifest returns the manifest next to the lockfile, e.g. sub/package.json for sub/package-lock.json
+func lockfileManifest(lockfile string) string {
+	return filepath.Join(filepath.Dir(lockfile), lockFilesToManifestMap[filepath.Base(lockfile)])
+}
+
+func isLockfile(displayTargetFile string) bool {
+	_, ok := lockFilesToManifestMap[filepath.Base(displayTargetFile)]
+	return ok
+}
@@ -0,0 +1,222 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// ManifestWarning is a structural problem of a manifest or lock file
+type ManifestWarning struct {
+	Message string
+	Range   vulnmap.Range
+}
+
+type npmManifest struct {
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+type npmLockFile struct {
+	LockfileVersion int                        `json:"lockfileVersion"`
+	Packages        map[string]npmManifest     `json:"packages"`
+	Dependencies    map[string]json.RawMessage `json:"dependencies"`
+}
+
+// ValidateManifest checks a manifest or lock file for structural problems without scanning it: unparseable JSON,
+// a missing lock file or manifest and, for npm, dependencies that are out of sync between package.json and
+// package-lock.json. An error is returned if the file can't be read or isn't a supported manifest.
+func ValidateManifest(filePath string) ([]ManifestWarning, error) {
+	fileName := filepath.Base(filePath)
+	lockFiles := lockFilesOfManifest(fileName)
+	manifest, isLockFile := lockFilesToManifestMap[fileName]
+	if len(lockFiles) == 0 && !isLockFile {
+		return nil, errors.Errorf("%s is not a supported manifest or lock file", fileName)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read manifest")
+	}
+
+	warnings := []ManifestWarning{}
+	if strings.HasSuffix(fileName, ".json") || fileName == "composer.lock" {
+		var parsed any
+		if err = json.Unmarshal(content, &parsed); err != nil {
+			return append(warnings, jsonSyntaxWarning(fileName, content, err)), nil
+		}
+	}
+
+	dir := filepath.Dir(filePath)
+	if isLockFile {
+		if !fileExists(filepath.Join(dir, manifest)) {
+			warnings = append(warnings, ManifestWarning{Message: fmt.Sprintf("%s has no %s next to it", fileName, manifest)})
+		}
+	} else if !anyFileExists(dir, lockFiles) {
+		warnings = append(warnings, ManifestWarning{
+			Message: fmt.Sprintf("no lock file found next to %s (expected %s)", fileName, strings.Join(lockFiles, " or ")),
+		})
+	}
+
+	if fileName == "package.json" || fileName == "package-lock.json" {
+		warnings = append(warnings, npmConsistencyWarnings(dir, fileName, content)...)
+	}
+	return warnings, nil
+}
+
+// lockFilesOfManifest returns the names of the lock files that belong to a manifest, sorted by name
+func lockFilesOfManifest(manifest string) []string {
+	var lockFiles []string
+	for lockFile, m := range lockFilesToManifestMap {
+		if m == manifest {
+			lockFiles = append(lockFiles, lockFile)
+		}
+	}
+	sort.Strings(lockFiles)
+	return lockFiles
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func anyFileExists(dir string, fileNames []string) bool {
+	for _, fileName := range fileNames {
+		if fileExists(filepath.Join(dir, fileName)) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonSyntaxWarning(fileName string, content []byte, err error) ManifestWarning {
+	warning := ManifestWarning{Message: fmt.Sprintf("%s is not valid JSON: %v", fileName, err)}
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) {
+		position := offsetToPosition(content, int(syntaxError.Offset))
+		warning.Range = vulnmap.Range{Start: position, End: position}
+	}
+	return warning
+}
+
+// npmConsistencyWarnings reports dependencies that are declared differently in package.json and package-lock.json.
+// The ranges of the warnings refer to the validated file.
+func npmConsistencyWarnings(dir string, fileName string, content []byte) []ManifestWarning {
+	manifestContent := content
+	lockFileContent := content
+	var err error
+	if fileName == "package.json" {
+		lockFileContent, err = os.ReadFile(filepath.Join(dir, "package-lock.json"))
+	} else {
+		manifestContent, err = os.ReadFile(filepath.Join(dir, "package.json"))
+	}
+	if err != nil {
+		return nil
+	}
+	var manifest npmManifest
+	var lockFile npmLockFile
+	if json.Unmarshal(manifestContent, &manifest) != nil {
+		return nil
+	}
+	if json.Unmarshal(lockFileContent, &lockFile) != nil {
+		return []ManifestWarning{{Message: "package-lock.json is not valid JSON"}}
+	}
+
+	declared := manifest.allDependencies()
+	locked, hasRootPackage := lockFile.Packages[""]
+	lockedDependencies := locked.allDependencies()
+
+	var warnings []ManifestWarning
+	warn := func(packageName string, message string) {
+		warnings = append(warnings, ManifestWarning{Message: message, Range: jsonKeyRange(content, packageName)})
+	}
+	for _, packageName := range sortedKeys(declared) {
+		declaredVersion := declared[packageName]
+		if !hasRootPackage {
+			// lock files before version 2 only contain the resolved dependencies
+			if _, ok := lockFile.Dependencies[packageName]; !ok {
+				warn(packageName, fmt.Sprintf("%s is declared in package.json but missing from package-lock.json", packageName))
+			}
+			continue
+		}
+		lockedVersion, ok := lockedDependencies[packageName]
+		if !ok {
+			warn(packageName, fmt.Sprintf("%s is declared in package.json but missing from package-lock.json", packageName))
+		} else if lockedVersion != declaredVersion {
+			warn(packageName, fmt.Sprintf("%s is declared as %s in package.json but as %s in package-lock.json",
+				packageName, declaredVersion, lockedVersion))
+		}
+	}
+	for _, packageName := range sortedKeys(lockedDependencies) {
+		if _, ok := declared[packageName]; !ok {
+			warn(packageName, fmt.Sprintf("%s is in package-lock.json but not declared in package.json", packageName))
+		}
+	}
+	return warnings
+}
+
+func (m npmManifest) allDependencies() map[string]string {
+	dependencies := map[string]string{}
+	for _, d := range []map[string]string{m.Dependencies, m.DevDependencies, m.OptionalDependencies} {
+		for name, version := range d {
+			dependencies[name] = version
+		}
+	}
+	return dependencies
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonKeyRange returns the range of the first occurrence of the quoted key in the content, or an empty range
+func jsonKeyRange(content []byte, key string) vulnmap.Range {
+	quotedKey := `"` + key + `"`
+	offset := strings.Index(string(content), quotedKey)
+	if offset < 0 {
+		return vulnmap.Range{}
+	}
+	start := offsetToPosition(content, offset)
+	end := start
+	end.Character += len(quotedKey)
+	return vulnmap.Range{Start: start, End: end}
+}
+
+func offsetToPosition(content []byte, offset int) vulnmap.Position {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	before := string(content[:offset])
+	line := strings.Count(before, "\n")
+	return vulnmap.Position{Line: line, Character: offset - (strings.LastIndex(before, "\n") + 1)}
+}
@@ -0,0 +1,134 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+const validPackageJson = `{
+  "name": "goof",
+  "dependencies": {
+    "lodash": "^4.17.4"
+  }
+}`
+
+const validPackageLockJson = `{
+  "name": "goof",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {
+      "name": "goof",
+      "dependencies": {
+        "lodash": "^4.17.4"
+      }
+    },
+    "node_modules/lodash": {
+      "version": "4.17.4"
+    }
+  }
+}`
+
+func Test_ValidateManifest_ValidPackageJsonHasNoWarnings(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "package.json")
+	testutil.CreateFileOrFail(t, manifestPath, []byte(validPackageJson))
+	testutil.CreateFileOrFail(t, filepath.Join(dir, "package-lock.json"), []byte(validPackageLockJson))
+
+	warnings, err := ValidateManifest(manifestPath)
+
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func Test_ValidateManifest_MalformedPackageJsonReportsSyntaxError(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "package.json")
+	testutil.CreateFileOrFail(t, manifestPath, []byte("{\n  \"dependencies\": {\n    \"lodash\": \"^4.17.4\",\n  }\n}"))
+	testutil.CreateFileOrFail(t, filepath.Join(dir, "package-lock.json"), []byte(validPackageLockJson))
+
+	warnings, err := ValidateManifest(manifestPath)
+
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0].Message, "package.json is not valid JSON")
+	assert.Equal(t, 3, warnings[0].Range.Start.Line)
+}
+
+func Test_ValidateManifest_MissingLockFile(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "package.json")
+	testutil.CreateFileOrFail(t, manifestPath, []byte(validPackageJson))
+
+	warnings, err := ValidateManifest(manifestPath)
+
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "no lock file found next to package.json (expected package-lock.json or yarn.lock)", warnings[0].Message)
+}
+
+func Test_ValidateManifest_InconsistentVersions(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "package.json")
+	testutil.CreateFileOrFail(t, manifestPath, []byte(`{
+  "dependencies": {
+    "lodash": "^4.17.21",
+    "express": "^4.18.2"
+  }
+}`))
+	testutil.CreateFileOrFail(t, filepath.Join(dir, "package-lock.json"), []byte(validPackageLockJson))
+
+	warnings, err := ValidateManifest(manifestPath)
+
+	require.NoError(t, err)
+	assert.Equal(t, []ManifestWarning{
+		{
+			Message: "express is declared in package.json but missing from package-lock.json",
+			Range:   vulnmap.Range{Start: vulnmap.Position{Line: 3, Character: 4}, End: vulnmap.Position{Line: 3, Character: 13}},
+		},
+		{
+			Message: "lodash is declared as ^4.17.21 in package.json but as ^4.17.4 in package-lock.json",
+			Range:   vulnmap.Range{Start: vulnmap.Position{Line: 2, Character: 4}, End: vulnmap.Position{Line: 2, Character: 12}},
+		},
+	}, warnings)
+}
+
+func Test_ValidateManifest_LockFileWithoutManifest(t *testing.T) {
+	lockFilePath := filepath.Join(t.TempDir(), "go.sum")
+	testutil.CreateFileOrFail(t, lockFilePath, []byte("github.com/pkg/errors v0.9.1 h1:abc=\n"))
+
+	warnings, err := ValidateManifest(lockFilePath)
+
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "go.sum has no go.mod next to it", warnings[0].Message)
+}
+
+func Test_ValidateManifest_UnsupportedFileReturnsError(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "main.go")
+	testutil.CreateFileOrFail(t, filePath, []byte("package main"))
+
+	_, err := ValidateManifest(filePath)
+
+	assert.Error(t, err)
+}
@@ -21,12 +21,15 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	sglsp "github.com/sourcegraph/go-lsp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
 	"github.com/khulnasoft-lab/vulnmap-ls/domain/observability/error_reporting"
@@ -37,6 +40,7 @@ import (
 	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/learn"
 	"github.com/khulnasoft-lab/vulnmap-ls/infrastructure/learn/mock_learn"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/notification"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
 	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
 )
 
@@ -45,15 +49,139 @@ import (
 func Test_toIssueSeverity(t *testing.T) {
 	testutil.UnitTest(t)
 	issue := ossIssue{Severity: "critical"}
-	assert.Equal(t, vulnmap.Critical, issue.ToIssueSeverity())
+	assert.Equal(t, vulnmap.Critical, issue.ToIssueSeverity(config.CurrentConfig()))
 	issue = ossIssue{Severity: "high"}
-	assert.Equal(t, vulnmap.High, issue.ToIssueSeverity())
+	assert.Equal(t, vulnmap.High, issue.ToIssueSeverity(config.CurrentConfig()))
 	issue = ossIssue{Severity: "medium"}
-	assert.Equal(t, vulnmap.Medium, issue.ToIssueSeverity())
+	assert.Equal(t, vulnmap.Medium, issue.ToIssueSeverity(config.CurrentConfig()))
 	issue = ossIssue{Severity: "info"}
-	assert.Equal(t, vulnmap.Low, issue.ToIssueSeverity())
+	assert.Equal(t, vulnmap.Low, issue.ToIssueSeverity(config.CurrentConfig()))
 	issue = ossIssue{Severity: "asdf"}
-	assert.Equal(t, vulnmap.Low, issue.ToIssueSeverity())
+	assert.Equal(t, vulnmap.Low, issue.ToIssueSeverity(config.CurrentConfig()))
+}
+
+func Test_toIssueSeverity_CustomMapping(t *testing.T) {
+	c := testutil.UnitTest(t)
+
+	c.SetSeverityMapping(map[string]string{
+		"Critical": "critical",
+		"moderate": "medium",
+		"info":     "low",
+	})
+
+	assert.Equal(t, vulnmap.Critical, (&ossIssue{Severity: "critical"}).ToIssueSeverity(c))
+	assert.Equal(t, vulnmap.Medium, (&ossIssue{Severity: "Moderate"}).ToIssueSeverity(c))
+	assert.Equal(t, vulnmap.Low, (&ossIssue{Severity: "info"}).ToIssueSeverity(c))
+	assert.Equal(t, vulnmap.Low, (&ossIssue{Severity: "high"}).ToIssueSeverity(c))
+}
+
+func Test_toIssueSeverity_UnknownSeverityPolicies(t *testing.T) {
+	c := testutil.UnitTest(t)
+	issue := ossIssue{Severity: "asdf"}
+
+	tests := []struct {
+		policy   config.UnknownSeverityPolicy
+		expected vulnmap.Severity
+	}{
+		{policy: config.UnknownSeverityAsLow, expected: vulnmap.Low},
+		{policy: config.UnknownSeverityAsMedium, expected: vulnmap.Medium},
+		{policy: config.UnknownSeverityAsIs, expected: vulnmap.Unknown},
+	}
+	for _, test := range tests {
+		c.SetUnknownSeverityPolicy(test.policy)
+		assert.Equal(t, test.expected, issue.ToIssueSeverity(c))
+	}
+}
+
+func Test_toIssueSeverity_ReadsTheGivenConfig(t *testing.T) {
+	testutil.UnitTest(t)
+	scannerConfig := config.New()
+	scannerConfig.SetSeverityMapping(map[string]string{"moderate": "high"})
+
+	assert.Equal(t, vulnmap.High, (&ossIssue{Severity: "moderate"}).ToIssueSeverity(scannerConfig))
+	assert.Equal(t, vulnmap.Low, (&ossIssue{Severity: "moderate"}).ToIssueSeverity(config.CurrentConfig()))
+}
+
+func Test_ParseSeverity(t *testing.T) {
+	severity, ok := ParseSeverity(" High ")
+	assert.True(t, ok)
+	assert.Equal(t, vulnmap.High, severity)
+
+	_, ok = ParseSeverity("moderate")
+	assert.False(t, ok)
+}
+
+func Test_toIssueSeverity_LicenseSeverities(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetLicenseSeverities(map[string]string{"GPL-3.0": "critical"})
+
+	licenseIssue := ossIssue{Type: "license", License: "gpl-3.0", Severity: "medium"}
+	otherLicenseIssue := ossIssue{Type: "license", License: "MIT", Severity: "low"}
+	vulnerability := ossIssue{License: "GPL-3.0", Severity: "medium"}
+
+	assert.Equal(t, vulnmap.Critical, licenseIssue.ToIssueSeverity(c))
+	assert.Equal(t, vulnmap.Low, otherLicenseIssue.ToIssueSeverity(c))
+	assert.Equal(t, vulnmap.Medium, vulnerability.ToIssueSeverity(c))
+}
+
+func Test_toIssue_LicenseSeverities(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetLicenseSeverities(map[string]string{"GPL-2.0": "critical"})
+	licenseIssue := sampleIssue()
+	licenseIssue.Type = "license"
+	licenseIssue.License = "GPL-2.0"
+	licenseIssue.Severity = "high"
+
+	issue := toIssue(context.Background(), config.CurrentConfig(), "testPath", licenseIssue, &scanResult{}, vulnmap.Range{}, config.FormatMd, getLearnMock(t), nil)
+
+	assert.Equal(t, vulnmap.Critical, issue.Severity)
+}
+
+func Test_toIssue_IssueIdentifier(t *testing.T) {
+	withCve := sampleIssue()
+	withCve.Identifiers.CVE = []string{"CVE-2021-1234", "CVE-2021-5678"}
+	withoutCve := sampleIssue()
+	withoutCve.Identifiers.CVE = nil
+	tests := []struct {
+		name       string
+		identifier config.IssueIdentifier
+		issue      ossIssue
+		expectedId string
+	}{
+		{"vulnmap id preferred, with CVE", config.IssueIdentifierVulnmap, withCve, "testIssue"},
+		{"vulnmap id preferred, without CVE", config.IssueIdentifierVulnmap, withoutCve, "testIssue"},
+		{"CVE preferred, with CVE", config.IssueIdentifierCve, withCve, "CVE-2021-1234"},
+		{"CVE preferred, without CVE", config.IssueIdentifierCve, withoutCve, "testIssue"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := testutil.UnitTest(t)
+			c.SetIssueIdentifier(test.identifier)
+
+			issue := toIssue(context.Background(), config.CurrentConfig(), "testPath", test.issue, &scanResult{}, vulnmap.Range{}, config.FormatMd, getLearnMock(t), nil)
+
+			assert.Equal(t, test.expectedId, issue.DisplayedID())
+			assert.Equal(t, "testIssue", issue.ID, "the issue is identified by the Vulnmap id")
+			assert.Equal(t, "testIssue", issue.AdditionalData.(vulnmap.OssIssueData).Key)
+		})
+	}
+}
+
+func Test_AddCodeActions_OpensIssueUrlFromTemplate(t *testing.T) {
+	c := testutil.UnitTest(t)
+	issue := sampleIssue()
+
+	actions := issue.AddCodeActions(context.Background(), getLearnMock(t), nil)
+
+	require.NotEmpty(t, actions)
+	assert.Equal(t, vulnmap.OpenBrowserCommand, actions[0].Command.CommandId)
+	assert.Equal(t, []any{"https://vulnmap.khulnasoft.com/vuln/testIssue"}, actions[0].Command.Arguments)
+
+	c.SetIssueURLTemplates(map[product.Product]string{product.ProductOpenSource: "https://vulns.example.com/{id}/details"})
+
+	actions = issue.AddCodeActions(context.Background(), getLearnMock(t), nil)
+
+	assert.Equal(t, []any{"https://vulns.example.com/testIssue/details"}, actions[0].Command.Arguments)
 }
 
 func Test_determineTargetFile(t *testing.T) {
@@ -132,7 +260,7 @@ func Test_toIssue_LearnParameterConversion(t *testing.T) {
 		learnService: getLearnMock(t),
 	}
 
-	issue := toIssue("testPath", ossIssue, &scanResult{}, vulnmap.Range{}, scanner.learnService, scanner.errorReporter)
+	issue := toIssue(context.Background(), config.CurrentConfig(), "testPath", ossIssue, &scanResult{}, vulnmap.Range{}, config.FormatMd, scanner.learnService, scanner.errorReporter)
 
 	assert.Equal(t, ossIssue.Id, issue.ID)
 	assert.Equal(t, ossIssue.Identifiers.CWE, issue.CWEs)
@@ -140,6 +268,39 @@ func Test_toIssue_LearnParameterConversion(t *testing.T) {
 	assert.Equal(t, ossIssue.PackageManager, issue.Ecosystem)
 }
 
+func Test_toIssue_SetsLessonUrl(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetVulnmapLearnCodeActionsEnabled(true)
+	learnMock := mock_learn.NewMockService(gomock.NewController(t))
+	learnMock.EXPECT().
+		GetLesson(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&learn.Lesson{Url: "https://learn.vulnmap.khulnasoft.com/lesson/prototype-pollution"}, nil).
+		AnyTimes()
+
+	issue := toIssue(context.Background(), config.CurrentConfig(), "testPath", sampleIssue(), &scanResult{}, vulnmap.Range{}, config.FormatMd, learnMock, error_reporting.NewTestErrorReporter())
+
+	require.NotNil(t, issue.LessonURL)
+	assert.Equal(t, "https://learn.vulnmap.khulnasoft.com/lesson/prototype-pollution", issue.LessonURL.String())
+}
+
+func Test_toIssue_RendersHoverAndExportInTheirOwnFormats(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetVulnmapLearnCodeActionsEnabled(false)
+	c.SetFormatFor(config.RenderContextHover, config.FormatMd)
+	c.SetFormatFor(config.RenderContextExport, config.FormatHtml)
+
+	issue := toIssue(context.Background(), config.CurrentConfig(), "testPath", sampleIssue(), &scanResult{}, vulnmap.Range{},
+		c.FormatFor(config.RenderContextDiagnostic), getLearnMock(t), error_reporting.NewTestErrorReporter())
+
+	hover := issue.FormattedMessageIn(c.FormatFor(config.RenderContextHover))
+	export := issue.FormattedMessageIn(c.FormatFor(config.RenderContextExport))
+	assert.Equal(t, issue.FormattedMessage, hover)
+	assert.Contains(t, hover, "### testIssue: THOU SHALL NOT PASS affecting")
+	assert.Contains(t, export, "### testIssue: <p>THOU SHALL NOT PASS</p>")
+	assert.Contains(t, export, "<p>Getting into Moria is an issue!</p>")
+	assert.NotContains(t, issue.Message, "<p>")
+}
+
 func Test_introducingPackageAndVersionJava(t *testing.T) {
 	issue := mavenTestIssue()
 
@@ -256,12 +417,70 @@ func TestUnmarshalOssErroneousJson(t *testing.T) {
 	assert.Nil(t, scanResults)
 }
 
+func TestUnmarshalOssJson_TruncatedJsonReturnsScanError(t *testing.T) {
+	c := testutil.UnitTest(t)
+	scanner := NewCLIScanner(performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		ux2.NewTestAnalytics(),
+		cli.NewTestExecutor(),
+		getLearnMock(t),
+		notification.NewNotifier(),
+		c).(*CLIScanner)
+
+	scanResults, err := scanner.unmarshallOssJson([]byte(`[{"vulnerabilities": [{"id": "VULNMAP-JS-1"`))
+
+	var scanError *vulnmap.ScanError
+	assert.ErrorAs(t, err, &scanError)
+	assert.True(t, scanError.Truncated)
+	assert.Nil(t, scanResults)
+}
+
+func TestUnmarshalOssJson_EmptyButValidJsonReturnsNoError(t *testing.T) {
+	c := testutil.UnitTest(t)
+	scanner := NewCLIScanner(performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		ux2.NewTestAnalytics(),
+		cli.NewTestExecutor(),
+		getLearnMock(t),
+		notification.NewNotifier(),
+		c).(*CLIScanner)
+
+	scanResults, err := scanner.unmarshallOssJson([]byte("[]"))
+
+	assert.NoError(t, err)
+	assert.Empty(t, scanResults)
+}
+
+func Test_Scan_TruncatedOutputReturnsScanError(t *testing.T) {
+	c := testutil.UnitTest(t)
+	analytics := ux2.NewTestAnalytics()
+	workingDir, _ := os.Getwd()
+	executor := cli.NewTestExecutor()
+	executor.ExecuteResponse = []byte(`{"vulnerabilities": [`)
+	p, _ := filepath.Abs(workingDir + "/testdata/package.json")
+	scanner := NewCLIScanner(
+		performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		analytics,
+		executor,
+		getLearnMock(t),
+		notification.NewNotifier(), c,
+	)
+
+	issues, err := scanner.Scan(context.Background(), p, "")
+
+	var scanError *vulnmap.ScanError
+	assert.ErrorAs(t, err, &scanError)
+	assert.Nil(t, issues)
+	assert.Equal(t, ux2.Error, analytics.GetAnalytics()[0].(ux2.AnalysisIsReadyProperties).Result)
+}
+
 func Test_toHover_asHTML(t *testing.T) {
 	c := testutil.UnitTest(t)
 	c.SetFormat(config.FormatHtml)
 
 	var issue = sampleIssue()
-	h := issue.GetExtendedMessage(issue)
+	h := issue.GetExtendedMessage(issue, config.FormatHtml)
 
 	assert.Equal(
 		t,
@@ -275,7 +494,7 @@ func Test_toHover_asMarkdown(t *testing.T) {
 	c.SetFormat(config.FormatMd)
 
 	var issue = sampleIssue()
-	h := issue.GetExtendedMessage(issue)
+	h := issue.GetExtendedMessage(issue, config.FormatMd)
 
 	assert.Equal(
 		t,
@@ -284,6 +503,79 @@ func Test_toHover_asMarkdown(t *testing.T) {
 	)
 }
 
+func Test_GetExtendedMessage_RendersDependencyPathOfTransitiveDependency(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetFormat(config.FormatMd)
+	issue := sampleIssue()
+	issue.From = []string{"goof@1.0.1", "express-fileupload@0.0.5", "busboy@0.2.14", "dicer@0.2.5"}
+
+	message := issue.GetExtendedMessage(issue, config.FormatMd)
+
+	assert.Contains(t, message, "**Introduced through:** goof@1.0.1 → express-fileupload@0.0.5 → busboy@0.2.14 → dicer@0.2.5\n")
+	assert.Contains(t, message, "**Direct dependency:** express-fileupload@0.0.5\n")
+}
+
+func Test_GetExtendedMessage_OmitsDependencyPathOfDirectDependency(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetFormat(config.FormatMd)
+	issue := sampleIssue()
+
+	for _, from := range [][]string{{"goof@1.0.1", "lodash@4.17.4"}, {"lodash@4.17.4"}} {
+		issue.From = from
+
+		message := issue.GetExtendedMessage(issue, config.FormatMd)
+
+		assert.NotContains(t, message, "Introduced through")
+		assert.NotContains(t, message, "Direct dependency")
+	}
+}
+
+func Test_GetExtendedMessage_LinkStyles(t *testing.T) {
+	tests := []struct {
+		style         config.LinkStyle
+		expectedCve   string
+		expectedCwe   string
+		expectedIssue string
+	}{
+		{
+			style:         config.LinkStyleMarkdown,
+			expectedCve:   "| [CVE-2021-23337](https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2021-23337)",
+			expectedCwe:   "| [CWE-123](https://cwe.mitre.org/data/definitions/123.html)",
+			expectedIssue: "| [testIssue](https://vulnmap.khulnasoft.com/vuln/testIssue)",
+		},
+		{
+			style:         config.LinkStyleUrl,
+			expectedCve:   "| CVE-2021-23337: https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2021-23337",
+			expectedCwe:   "| CWE-123: https://cwe.mitre.org/data/definitions/123.html",
+			expectedIssue: "| testIssue: https://vulnmap.khulnasoft.com/vuln/testIssue",
+		},
+		{
+			style:         config.LinkStyleText,
+			expectedCve:   "| CVE-2021-23337",
+			expectedCwe:   "| CWE-123",
+			expectedIssue: "| testIssue",
+		},
+	}
+	for _, test := range tests {
+		t.Run(string(test.style), func(t *testing.T) {
+			c := testutil.UnitTest(t)
+			c.SetFormat(config.FormatMd)
+			c.SetLinkStyle(test.style)
+			issue := sampleIssue()
+			issue.Identifiers.CVE = []string{"CVE-2021-23337"}
+
+			assert.Equal(t, test.expectedCve, issue.createAdvisoryLinks())
+			assert.Equal(t, test.expectedCwe, issue.createCweLink())
+			assert.Equal(t, test.expectedIssue, issue.createIssueUrlMarkdown())
+			message := issue.GetExtendedMessage(issue, config.FormatMd)
+			assert.Contains(t, message, test.expectedCve+" "+test.expectedCwe+" "+test.expectedIssue)
+			if test.style != config.LinkStyleMarkdown {
+				assert.NotContains(t, message, "](")
+			}
+		})
+	}
+}
+
 func Test_SeveralScansOnSameFolder_DoNotRunAtOnce(t *testing.T) {
 	c := testutil.UnitTest(t)
 	// Arrange
@@ -320,6 +612,44 @@ func Test_SeveralScansOnSameFolder_DoNotRunAtOnce(t *testing.T) {
 	assert.Equal(t, 1, fakeCli.GetFinishedScans())
 }
 
+func Test_GetExtendedMessage_AdvisoryIdentifierPreferences(t *testing.T) {
+	cveLink := "| [CVE-2021-23337](https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2021-23337)"
+	ghsaLink := "| [GHSA-35jh-r3h4-6jhm](https://github.com/advisories/GHSA-35jh-r3h4-6jhm)"
+	tests := []struct {
+		preference config.AdvisoryIdentifierPreference
+		expected   string
+	}{
+		{preference: config.AdvisoryIdentifierPreferenceCve, expected: cveLink},
+		{preference: config.AdvisoryIdentifierPreferenceGhsa, expected: ghsaLink},
+		{preference: config.AdvisoryIdentifierPreferenceAll, expected: cveLink + ghsaLink},
+	}
+	for _, test := range tests {
+		t.Run(string(test.preference), func(t *testing.T) {
+			c := testutil.UnitTest(t)
+			c.SetAdvisoryIdentifierPreference(test.preference)
+			issue := sampleIssue()
+			issue.Identifiers.CVE = []string{"CVE-2021-23337"}
+			issue.Identifiers.GHSA = []string{"GHSA-35jh-r3h4-6jhm"}
+
+			assert.Equal(t, test.expected, issue.createAdvisoryLinks())
+			assert.Contains(t, issue.GetExtendedMessage(issue, config.FormatMd), test.expected+" ")
+		})
+	}
+}
+
+func Test_GetExtendedMessage_AdvisoryIdentifierPreferenceFallsBack(t *testing.T) {
+	c := testutil.UnitTest(t)
+	issue := sampleIssue()
+	issue.Identifiers.GHSA = []string{"GHSA-35jh-r3h4-6jhm"}
+
+	c.SetAdvisoryIdentifierPreference(config.AdvisoryIdentifierPreferenceCve)
+	assert.Equal(t, "| [GHSA-35jh-r3h4-6jhm](https://github.com/advisories/GHSA-35jh-r3h4-6jhm)", issue.createAdvisoryLinks())
+
+	issue.Identifiers = identifiers{CVE: []string{"CVE-2021-23337"}}
+	c.SetAdvisoryIdentifierPreference(config.AdvisoryIdentifierPreferenceGhsa)
+	assert.Equal(t, "| [CVE-2021-23337](https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2021-23337)", issue.createAdvisoryLinks())
+}
+
 func sampleIssue() ossIssue {
 	return ossIssue{
 		Id:             "testIssue",
@@ -357,6 +687,46 @@ func Test_prepareScanCommand_ExpandsAdditionalParameters(t *testing.T) {
 	assert.Contains(t, cmd, "-d")
 }
 
+func Test_prepareScanCommand_PinsOutputVersion(t *testing.T) {
+	c := testutil.UnitTest(t)
+	scanner := NewCLIScanner(performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		ux2.NewTestAnalytics(),
+		cli.NewTestExecutor(),
+		getLearnMock(t),
+		notification.NewNotifier(),
+		c).(*CLIScanner)
+
+	c.CliSettings().SetVersion("1.1300.0")
+
+	cmd := scanner.prepareScanCommand([]string{"a"})
+	assert.NotContains(t, strings.Join(cmd, " "), "--json-output-version", "no version is pinned by default")
+
+	c.SetOssOutputVersion("2")
+	cmd = scanner.prepareScanCommand([]string{"a"})
+	assert.Contains(t, cmd, "--json-output-version=2")
+}
+
+func Test_prepareScanCommand_DoesNotPinOutputVersionForUnsupportedCli(t *testing.T) {
+	c := testutil.UnitTest(t)
+	scanner := NewCLIScanner(performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		ux2.NewTestAnalytics(),
+		cli.NewTestExecutor(),
+		getLearnMock(t),
+		notification.NewNotifier(),
+		c).(*CLIScanner)
+	c.SetOssOutputVersion("2")
+
+	for _, cliVersion := range []string{"1.1299.0", "", "unknown"} {
+		c.CliSettings().SetVersion(cliVersion)
+
+		cmd := scanner.prepareScanCommand([]string{"a"})
+
+		assert.NotContains(t, strings.Join(cmd, " "), "--json-output-version", cliVersion)
+	}
+}
+
 func Test_Scan_SchedulesNewScan(t *testing.T) {
 	c := testutil.UnitTest(t)
 	// Arrange
@@ -544,11 +914,381 @@ func Test_Scan_missingDisplayTargetFileDoesNotBreakAnalysis(t *testing.T) {
 	assert.Len(t, analysis, 87)
 }
 
+func Test_Scan_IssuesAboveMaximumAreTruncated(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetMaxIssuesPerScan(20)
+	workingDir, _ := os.Getwd()
+	fakeCli := cli.NewTestExecutorWithResponseFromFile(path.Join(workingDir,
+		"testdata/oss-result-without-targetFile.json"))
+	analytics := ux2.NewTestAnalytics()
+	notifier := notification.NewMockNotifier()
+	scanner := NewCLIScanner(
+		performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		analytics,
+		fakeCli,
+		getLearnMock(t),
+		notifier,
+		c,
+	)
+	filePath, _ := filepath.Abs(workingDir + "/testdata/package.json")
+
+	issues, err := scanner.Scan(context.Background(), filePath, "")
+
+	assert.NoError(t, err)
+	assert.Len(t, issues, 20)
+	for _, issue := range issues {
+		assert.LessOrEqual(t, issue.Severity, vulnmap.High, issue.ID)
+	}
+	assert.Equal(t, 1, notifier.SendShowMessageCount())
+	warning := notifier.SentMessages()[0].(sglsp.ShowMessageParams)
+	assert.Equal(t, sglsp.MessageType(sglsp.Warning), warning.Type)
+	assert.Contains(t, warning.Message, "67 were omitted")
+	assert.Contains(t, analytics.GetAnalytics(), ux2.AnalysisIsReadyProperties{
+		AnalysisType:      ux2.OpenSource,
+		Result:            ux2.Success,
+		OmittedIssueCount: 67,
+	})
+}
+
+func Test_Scan_TruncationIsNotifiedOncePerCondition(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetMaxIssuesPerScan(20)
+	workingDir, _ := os.Getwd()
+	fakeCli := cli.NewTestExecutorWithResponseFromFile(path.Join(workingDir,
+		"testdata/oss-result-without-targetFile.json"))
+	notifier := notification.NewMockNotifier()
+	scanner := NewCLIScanner(
+		performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		ux2.NewTestAnalytics(),
+		fakeCli,
+		getLearnMock(t),
+		notifier,
+		c,
+	)
+	filePath, _ := filepath.Abs(workingDir + "/testdata/package.json")
+
+	_, _ = scanner.Scan(context.Background(), filePath, "")
+	_, _ = scanner.Scan(context.Background(), filePath, "")
+	assert.Equal(t, 1, notifier.SendShowMessageCount(), "repeated truncation is not notified again")
+
+	c.SetMaxIssuesPerScan(0)
+	_, _ = scanner.Scan(context.Background(), filePath, "")
+	c.SetMaxIssuesPerScan(20)
+	issues, _ := scanner.Scan(context.Background(), filePath, "")
+
+	assert.Len(t, issues, 20)
+	assert.Equal(t, 2, notifier.SendShowMessageCount(), "truncation is notified again after a complete scan")
+}
+
+func Test_limitScanResults_UnlimitedKeepsAllIssues(t *testing.T) {
+	results := []scanResult{{Vulnerabilities: []ossIssue{{Id: "1", Severity: "low"}, {Id: "2", Severity: "high"}}}}
+
+	omitted := limitScanResults(config.CurrentConfig(), results, 0)
+
+	assert.Equal(t, 0, omitted)
+	assert.Len(t, results[0].Vulnerabilities, 2)
+}
+
+func Test_limitScanResults_KeepsAllPathsOfKeptIssues(t *testing.T) {
+	results := []scanResult{{Vulnerabilities: []ossIssue{
+		{Id: "1", PackageName: "a", Severity: "low"},
+		{Id: "2", PackageName: "b", Severity: "critical", From: []string{"p", "b"}},
+		{Id: "2", PackageName: "b", Severity: "critical", From: []string{"p", "c", "b"}},
+	}}}
+
+	omitted := limitScanResults(config.CurrentConfig(), results, 1)
+
+	assert.Equal(t, 1, omitted)
+	assert.Len(t, results[0].Vulnerabilities, 2)
+	assert.Equal(t, "2", results[0].Vulnerabilities[0].Id)
+}
+
+const lockfileScanResult = `{
+	"vulnerabilities": [
+		{"id": "VULNMAP-JS-LODASH-1", "packageName": "lodash", "version": "4.17.4", "packageManager": "npm",
+			"severity": "high", "title": "Prototype Pollution", "from": ["goof@1.0.1", "lodash@4.17.4"]},
+		{"id": "VULNMAP-JS-DEBUG-1", "packageName": "debug", "version": "2.6.8", "packageManager": "npm",
+			"severity": "low", "title": "Regular Expression Denial of Service (ReDoS)",
+			"from": ["goof@1.0.1", "@angular/cli@1.0.0", "debug@2.6.8"]}
+	],
+	"packageManager": "npm",
+	"displayTargetFile": "package-lock.json"
+}`
+
+func analyzeLockfileScanResult(t *testing.T, c *config.Config) map[string]vulnmap.Issue {
+	t.Helper()
+	workingDir, _ := os.Getwd()
+	workDir := filepath.Join(workingDir, "testdata")
+	scanner := NewCLIScanner(
+		performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		ux2.NewTestAnalytics(),
+		cli.NewTestExecutor(),
+		getLearnMock(t),
+		notification.NewNotifier(),
+		c,
+	).(*CLIScanner)
+
+	issues, _, err := scanner.unmarshallAndRetrieveAnalysis(context.Background(), []byte(lockfileScanResult), workDir, workDir)
+
+	assert.NoError(t, err)
+	issuesByPackage := map[string]vulnmap.Issue{}
+	for _, issue := range issues {
+		issuesByPackage[issue.AdditionalData.(vulnmap.OssIssueData).PackageName] = issue
+	}
+	return issuesByPackage
+}
+
+func Test_unmarshallAndRetrieveAnalysis_DirectDependencyIsRemappedToManifest(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetLockfileIssueRemapping(true)
+
+	issue := analyzeLockfileScanResult(t, c)["lodash"]
+
+	workingDir, _ := os.Getwd()
+	assert.Equal(t, filepath.Join(workingDir, "testdata", "package.json"), issue.AffectedFilePath)
+	assert.Equal(t, 17, issue.Range.Start.Line)
+}
+
+func Test_unmarshallAndRetrieveAnalysis_TransitiveDependencyStaysOnLockfile(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetLockfileIssueRemapping(true)
+
+	issue := analyzeLockfileScanResult(t, c)["debug"]
+
+	workingDir, _ := os.Getwd()
+	assert.Equal(t, filepath.Join(workingDir, "testdata", "package-lock.json"), issue.AffectedFilePath)
+}
+
+func Test_unmarshallAndRetrieveAnalysis_RemappingDisabledReportsOnLockfile(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetLockfileIssueRemapping(false)
+
+	issue := analyzeLockfileScanResult(t, c)["lodash"]
+
+	workingDir, _ := os.Getwd()
+	assert.Equal(t, filepath.Join(workingDir, "testdata", "package-lock.json"), issue.AffectedFilePath)
+}
+
+func Test_unmarshallAndRetrieveAnalysis_LinksSameVulnerabilityInOtherFiles(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetLockfileIssueRemapping(false)
+	vulnerability := `{"id": "VULNMAP-JS-LODASH-1", "packageName": "lodash", "version": "4.17.4", "packageManager": "npm",
+		"severity": "high", "title": "Prototype Pollution", "from": ["goof@1.0.1", "lodash@4.17.4"],
+		"identifiers": {"CVE": ["CVE-2019-10744"]}}`
+	output := `[
+		{"vulnerabilities": [` + vulnerability + `], "packageManager": "npm", "displayTargetFile": "package-lock.json"},
+		{"vulnerabilities": [` + vulnerability + `], "packageManager": "npm", "displayTargetFile": "sub/package-lock.json"}
+	]`
+	workDir := t.TempDir()
+	scanner := NewCLIScanner(
+		performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		ux2.NewTestAnalytics(),
+		cli.NewTestExecutor(),
+		getLearnMock(t),
+		notification.NewNotifier(),
+		c,
+	).(*CLIScanner)
+
+	issues, _, err := scanner.unmarshallAndRetrieveAnalysis(context.Background(), []byte(output), workDir, workDir)
+
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	for i, other := range []vulnmap.Issue{issues[1], issues[0]} {
+		require.Len(t, issues[i].RelatedLocations, 1)
+		related := issues[i].RelatedLocations[0]
+		assert.Equal(t, other.AffectedFilePath, related.FilePath)
+		assert.Equal(t, other.Range, related.Range)
+		assert.Equal(t, "VULNMAP-JS-LODASH-1 also affects lodash@4.17.4", related.Message)
+	}
+}
+
+func analyzeManifestAndLockfileScanResults(t *testing.T, c *config.Config, lockfile string) []vulnmap.Issue {
+	t.Helper()
+	vulnerability := `{"id": "VULNMAP-JS-LODASH-1", "packageName": "lodash", "version": "4.17.4", "packageManager": "npm",
+		"severity": "high", "title": "Prototype Pollution", "from": ["goof@1.0.1", "lodash@4.17.4"],
+		"identifiers": {"CVE": ["CVE-2019-10744"]}}`
+	output := `[
+		{"vulnerabilities": [` + vulnerability + `], "packageManager": "npm", "displayTargetFile": "package.json"},
+		{"vulnerabilities": [` + vulnerability + `], "packageManager": "npm", "displayTargetFile": "` + lockfile + `"}
+	]`
+	workDir := t.TempDir()
+	scanner := NewCLIScanner(
+		performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		ux2.NewTestAnalytics(),
+		cli.NewTestExecutor(),
+		getLearnMock(t),
+		notification.NewNotifier(),
+		c,
+	).(*CLIScanner)
+
+	issues, _, err := scanner.unmarshallAndRetrieveAnalysis(context.Background(), []byte(output), workDir, workDir)
+
+	require.NoError(t, err)
+	return issues
+}
+
+func Test_unmarshallAndRetrieveAnalysis_KeepsManifestAndLockfileDuplicates(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetManifestLockfileDuplicates(config.ManifestLockfileDuplicatesKeep)
+
+	issues := analyzeManifestAndLockfileScanResults(t, c, "package-lock.json")
+
+	require.Len(t, issues, 2)
+	assert.Equal(t, "package.json", filepath.Base(issues[0].AffectedFilePath))
+	assert.Equal(t, "package-lock.json", filepath.Base(issues[1].AffectedFilePath))
+	assert.Len(t, issues[0].RelatedLocations, 1)
+}
+
+func Test_unmarshallAndRetrieveAnalysis_CollapsesManifestAndLockfileDuplicates(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetManifestLockfileDuplicates(config.ManifestLockfileDuplicatesCollapse)
+
+	issues := analyzeManifestAndLockfileScanResults(t, c, "package-lock.json")
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, "package.json", filepath.Base(issues[0].AffectedFilePath))
+	assert.Equal(t, []string{"CVE-2019-10744"}, issues[0].CVEs)
+	assert.Empty(t, issues[0].RelatedLocations)
+}
+
+func Test_unmarshallAndRetrieveAnalysis_CollapseKeepsLockfileIssuesOfOtherProjects(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetManifestLockfileDuplicates(config.ManifestLockfileDuplicatesCollapse)
+
+	issues := analyzeManifestAndLockfileScanResults(t, c, "sub/package-lock.json")
+
+	require.Len(t, issues, 2)
+	assert.Equal(t, filepath.Join("sub", "package-lock.json"),
+		filepath.Join(filepath.Base(filepath.Dir(issues[1].AffectedFilePath)), filepath.Base(issues[1].AffectedFilePath)))
+}
+
+func Test_linkMatchingIssues_SingleOccurrenceHasNoRelatedLocations(t *testing.T) {
+	issues := []vulnmap.Issue{{ID: "1", AffectedFilePath: "a"}, {ID: "2", AffectedFilePath: "b"}}
+
+	linkMatchingIssues(issues)
+
+	assert.Empty(t, issues[0].RelatedLocations)
+	assert.Empty(t, issues[1].RelatedLocations)
+}
+
 func getLearnMock(t *testing.T) learn.Service {
 	learnMock := mock_learn.NewMockService(gomock.NewController(t))
 	learnMock.
 		EXPECT().
-		GetLesson(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		GetLesson(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(&learn.Lesson{}, nil).AnyTimes()
 	return learnMock
 }
+
+func Test_createFixedIn(t *testing.T) {
+	t.Run("single version", func(t *testing.T) {
+		issue := ossIssue{FixedIn: []string{"1.2.3"}}
+		assert.Equal(t, "@1.2.3", issue.createFixedIn())
+	})
+
+	t.Run("multiple versions", func(t *testing.T) {
+		issue := ossIssue{FixedIn: []string{"1.2.3", "2.0.1"}}
+		assert.Equal(t, "@1.2.3, 2.0.1", issue.createFixedIn())
+	})
+
+	t.Run("range expression", func(t *testing.T) {
+		issue := ossIssue{FixedIn: []string{">=1.2.3 <2.0.0"}}
+		assert.Equal(t, ">=1.2.3", issue.createFixedIn())
+	})
+
+	t.Run("range expressions mixed with versions", func(t *testing.T) {
+		issue := ossIssue{FixedIn: []string{"1.2.3", " >=2.1.0 <3.0.0 "}}
+		assert.Equal(t, "@1.2.3, >=2.1.0", issue.createFixedIn())
+	})
+
+	t.Run("range without lower bound", func(t *testing.T) {
+		issue := ossIssue{FixedIn: []string{"<2.0.0"}}
+		assert.Equal(t, "<2.0.0", issue.createFixedIn())
+	})
+
+	t.Run("not fixed", func(t *testing.T) {
+		issue := ossIssue{}
+		assert.Equal(t, "Not Fixed", issue.createFixedIn())
+	})
+
+	t.Run("details panel renders the same versions", func(t *testing.T) {
+		issue := ossIssue{Name: "lodash", FixedIn: []string{">=1.2.3 <2.0.0", "2.0.1"}}
+		assert.Equal(t, "lodash"+issue.createFixedIn(), getFixedIn(&issue))
+		assert.Equal(t, "lodash>=1.2.3, 2.0.1", getFixedIn(&issue))
+	})
+}
+
+func Test_toReference_DerivesTitleFromUrlIfEmpty(t *testing.T) {
+	t.Run("known host", func(t *testing.T) {
+		ref := reference{Url: "https://nvd.nist.gov/vuln/detail/CVE-2021-44228"}
+
+		assert.Equal(t, "NVD", ref.toReference().Title)
+	})
+
+	t.Run("unknown host", func(t *testing.T) {
+		ref := reference{Url: "https://github.com/advisories/GHSA-jfh8-c2jp-5v3q"}
+
+		assert.Equal(t, "github.com GHSA-jfh8-c2jp-5v3q", ref.toReference().Title)
+	})
+
+	t.Run("existing title is kept", func(t *testing.T) {
+		ref := reference{Url: "https://nvd.nist.gov/vuln/detail/CVE-2021-44228", Title: "CVE-2021-44228"}
+
+		assert.Equal(t, "CVE-2021-44228", ref.toReference().Title)
+	})
+}
+
+func Test_prepareScanCommand_AddsAllProjectsParametersOnlyWhenEnabled(t *testing.T) {
+	c := testutil.UnitTest(t)
+	scanner := NewCLIScanner(performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		ux2.NewTestAnalytics(),
+		cli.NewTestExecutor(),
+		getLearnMock(t),
+		notification.NewNotifier(),
+		c).(*CLIScanner)
+	c.SetAllProjectsDetectionDepth(3)
+	c.SetAllProjectsExclude([]string{"node_modules", "fixtures"})
+
+	cmd := scanner.prepareScanCommand([]string{"a"})
+	assert.NotContains(t, cmd, "--all-projects")
+	assert.NotContains(t, cmd, "--detection-depth=3")
+	assert.NotContains(t, cmd, "--exclude=node_modules,fixtures")
+
+	c.SetAllProjectsEnabled(true)
+	cmd = scanner.prepareScanCommand([]string{"a"})
+	assert.Contains(t, cmd, "--all-projects")
+	assert.Contains(t, cmd, "--detection-depth=3")
+	assert.Contains(t, cmd, "--exclude=node_modules,fixtures")
+}
+
+func Test_prepareScanCommand_DoesNotRepeatAdditionalAllProjectsParameters(t *testing.T) {
+	c := testutil.UnitTest(t)
+	scanner := NewCLIScanner(performance.NewInstrumentor(),
+		error_reporting.NewTestErrorReporter(),
+		ux2.NewTestAnalytics(),
+		cli.NewTestExecutor(),
+		getLearnMock(t),
+		notification.NewNotifier(),
+		c).(*CLIScanner)
+	c.SetCliSettings(&config.CliSettings{AdditionalOssParameters: []string{"--all-projects", "--detection-depth=5"}})
+	c.SetAllProjectsEnabled(true)
+	c.SetAllProjectsDetectionDepth(3)
+
+	cmd := scanner.prepareScanCommand([]string{"a"})
+
+	count := 0
+	for _, param := range cmd {
+		if param == "--all-projects" {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+	assert.Contains(t, cmd, "--detection-depth=5")
+	assert.NotContains(t, cmd, "--detection-depth=3")
+}
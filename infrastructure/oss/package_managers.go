@@ -0,0 +1,64 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import "sort"
+
+// packageManagerDisplayNames labels the package managers of the supported files, package managers without an entry
+// are displayed with their CLI name
+var packageManagerDisplayNames = map[string]string{
+	"yarn":      "Yarn",
+	"maven":     "Maven",
+	"gradle":    "Gradle",
+	"poetry":    "Poetry",
+	"pipenv":    "Pipenv",
+	"nuget":     "NuGet",
+	"paket":     "Paket",
+	"golangdep": "Go dep",
+	"govendor":  "Go vendor",
+	"gomodules": "Go Modules",
+	"composer":  "Composer",
+	"rubygems":  "RubyGems",
+	"cocoapods": "CocoaPods",
+	"hex":       "Hex",
+}
+
+type PackageManager struct {
+	Name        string
+	DisplayName string
+}
+
+// SupportedPackageManagers returns the package managers of the manifests and lock files the CLI can scan, sorted by
+// name
+func SupportedPackageManagers() []PackageManager {
+	names := map[string]bool{}
+	for _, name := range supportedFiles {
+		names[name] = true
+	}
+	packageManagers := make([]PackageManager, 0, len(names))
+	for name := range names {
+		displayName, ok := packageManagerDisplayNames[name]
+		if !ok {
+			displayName = name
+		}
+		packageManagers = append(packageManagers, PackageManager{Name: name, DisplayName: displayName})
+	}
+	sort.Slice(packageManagers, func(i, j int) bool {
+		return packageManagers[i].Name < packageManagers[j].Name
+	})
+	return packageManagers
+}
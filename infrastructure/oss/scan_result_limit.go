@@ -0,0 +1,73 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"sort"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+)
+
+// limitScanResults caps the unique issues of all scan results at maxIssues, keeping the ones with the highest
+// severity. The vulnerabilities of the scan results are filtered in place and the number of omitted unique issues is
+// returned. A maxIssues of 0 means unlimited.
+func limitScanResults(c *config.Config, scanResults []scanResult, maxIssues int) (omitted int) {
+	if maxIssues <= 0 {
+		return 0
+	}
+
+	type issueKey struct {
+		result int
+		key    string
+	}
+	var uniqueIssues []issueKey
+	severities := map[issueKey]int{}
+	for i := range scanResults {
+		for _, vulnerability := range scanResults[i].Vulnerabilities {
+			k := issueKey{result: i, key: vulnerability.Id + "|" + vulnerability.PackageName}
+			if _, exists := severities[k]; exists {
+				continue
+			}
+			// vulnmap.Severity is ordered from critical to unknown
+			severities[k] = int(vulnerability.ToIssueSeverity(c))
+			uniqueIssues = append(uniqueIssues, k)
+		}
+	}
+	if len(uniqueIssues) <= maxIssues {
+		return 0
+	}
+
+	sort.SliceStable(uniqueIssues, func(i, j int) bool {
+		return severities[uniqueIssues[i]] < severities[uniqueIssues[j]]
+	})
+	kept := map[issueKey]bool{}
+	for _, k := range uniqueIssues[:maxIssues] {
+		kept[k] = true
+	}
+
+	// all occurrences of a kept issue are retained, as they carry the different dependency paths
+	for i := range scanResults {
+		var vulnerabilities []ossIssue
+		for _, vulnerability := range scanResults[i].Vulnerabilities {
+			if kept[issueKey{result: i, key: vulnerability.Id + "|" + vulnerability.PackageName}] {
+				vulnerabilities = append(vulnerabilities, vulnerability)
+			}
+		}
+		scanResults[i].Vulnerabilities = vulnerabilities
+	}
+	return len(uniqueIssues) - maxIssues
+}
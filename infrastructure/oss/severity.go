@@ -0,0 +1,64 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+func defaultSeverityMapping() map[string]vulnmap.Severity {
+	return map[string]vulnmap.Severity{
+		"critical": vulnmap.Critical,
+		"high":     vulnmap.High,
+		"low":      vulnmap.Low,
+		"medium":   vulnmap.Medium,
+	}
+}
+
+// ParseSeverity returns the issue severity of a severity name ("critical", "high", "medium" or "low")
+func ParseSeverity(severity string) (vulnmap.Severity, bool) {
+	issueSeverity, ok := defaultSeverityMapping()[strings.ToLower(strings.TrimSpace(severity))]
+	return issueSeverity, ok
+}
+
+// ToSeverity returns the issue severity of a severity reported by the CLI, using the severity mapping and the unknown
+// severity policy of the configuration
+func ToSeverity(c *config.Config, severity string) vulnmap.Severity {
+	reportedSeverity := strings.ToLower(severity)
+	if mapping := c.SeverityMapping(); mapping != nil {
+		if issueSeverity, ok := ParseSeverity(mapping[reportedSeverity]); ok {
+			return issueSeverity
+		}
+	} else if issueSeverity, ok := defaultSeverityMapping()[reportedSeverity]; ok {
+		return issueSeverity
+	}
+
+	switch c.UnknownSeverityPolicy() {
+	case config.UnknownSeverityAsMedium:
+		return vulnmap.Medium
+	case config.UnknownSeverityAsIs:
+		log.Warn().Str("method", "ToSeverity").Str("severity", severity).Msg("unrecognized severity")
+		return vulnmap.Unknown
+	default:
+		return vulnmap.Low
+	}
+}
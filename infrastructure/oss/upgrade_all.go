@@ -0,0 +1,250 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+// PackageUpgrade is a version bump of a direct dependency in a manifest
+type PackageUpgrade struct {
+	PackageName string
+	FromVersion string
+	ToVersion   string
+	IssueIds    []string
+}
+
+// SkippedUpgrade is an issue that is left untouched by an upgrade plan, with the reason why
+type SkippedUpgrade struct {
+	IssueId     string
+	PackageName string
+	Reason      string
+}
+
+// UpgradePlan contains the edit that applies all upgrades of a manifest and the issues that couldn't be upgraded
+type UpgradePlan struct {
+	Edit     *vulnmap.WorkspaceEdit
+	Upgrades []PackageUpgrade
+	Skipped  []SkippedUpgrade
+}
+
+// ManifestUpgradePlan is the upgrade plan of one manifest of a folder
+type ManifestUpgradePlan struct {
+	FilePath string
+	UpgradePlan
+}
+
+// FolderUpgradePlan combines the upgrade plans of the manifests of a folder into one edit spanning all of them
+type FolderUpgradePlan struct {
+	Edit      *vulnmap.WorkspaceEdit
+	Manifests []ManifestUpgradePlan
+}
+
+type upgradeCandidate struct {
+	target     string
+	version    *version.Version
+	issueIds   []string
+	conflicted bool
+}
+
+// PlanUpgradesInFile computes the version bumps that fix all upgradable open source issues of a manifest. If several
+// issues upgrade the same package, the highest target version is used. Only npm package.json files are supported.
+func PlanUpgradesInFile(filePath string, fileContent []byte, issues []vulnmap.Issue) UpgradePlan {
+	plan := UpgradePlan{}
+	supportedManifest := filepath.Base(filePath) == "package.json"
+	candidates := map[string]*upgradeCandidate{}
+
+	for _, issue := range issues {
+		if issue.Product != product.ProductOpenSource {
+			continue
+		}
+		data, ok := issue.AdditionalData.(vulnmap.OssIssueData)
+		if !ok {
+			continue
+		}
+		if !supportedManifest {
+			plan.skip(issue.ID, data.PackageName, "unsupported manifest "+filepath.Base(filePath))
+			continue
+		}
+		packageName, target, ok := directUpgrade(data)
+		if !ok {
+			plan.skip(issue.ID, data.PackageName, "no upgrade path available")
+			continue
+		}
+		candidates[packageName] = addUpgradeTarget(candidates[packageName], issue.ID, target)
+	}
+
+	packageNames := make([]string, 0, len(candidates))
+	for packageName := range candidates {
+		packageNames = append(packageNames, packageName)
+	}
+	sort.Strings(packageNames)
+
+	var edits []vulnmap.TextEdit
+	for _, packageName := range packageNames {
+		candidate := candidates[packageName]
+		if candidate.conflicted {
+			for _, issueId := range candidate.issueIds {
+				plan.skip(issueId, packageName, "conflicting upgrade targets")
+			}
+			continue
+		}
+		edit, currentVersion, found := npmVersionEdit(fileContent, packageName, candidate.target)
+		if !found {
+			for _, issueId := range candidate.issueIds {
+				plan.skip(issueId, packageName, "package not found in manifest")
+			}
+			continue
+		}
+		edits = append(edits, edit)
+		plan.Upgrades = append(plan.Upgrades, PackageUpgrade{
+			PackageName: packageName,
+			FromVersion: currentVersion,
+			ToVersion:   candidate.target,
+			IssueIds:    candidate.issueIds,
+		})
+	}
+
+	if len(edits) > 0 {
+		plan.Edit = &vulnmap.WorkspaceEdit{Changes: map[string][]vulnmap.TextEdit{filePath: edits}}
+	}
+	return plan
+}
+
+// PlanUpgradesInFolder computes the upgrades of all manifests the open source issues are reported on. Upgrades to a
+// new major version may break the project, so they are only planned if includeMajorUpgrades is true. Manifests that
+// can't be read are skipped as a whole.
+func PlanUpgradesInFolder(issues []vulnmap.Issue, includeMajorUpgrades bool) FolderUpgradePlan {
+	issuesByFile := map[string][]vulnmap.Issue{}
+	for _, issue := range issues {
+		if issue.Product == product.ProductOpenSource {
+			issuesByFile[issue.AffectedFilePath] = append(issuesByFile[issue.AffectedFilePath], issue)
+		}
+	}
+	filePaths := make([]string, 0, len(issuesByFile))
+	for filePath := range issuesByFile {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	folderPlan := FolderUpgradePlan{}
+	for _, filePath := range filePaths {
+		plan := UpgradePlan{}
+		var upgradable []vulnmap.Issue
+		for _, issue := range issuesByFile[filePath] {
+			if advice := issue.FixAdvice(); !includeMajorUpgrades && advice.UpgradeRisk == vulnmap.UpgradeRiskMajor {
+				plan.skip(issue.ID, advice.PackageName, "major version upgrade")
+				continue
+			}
+			upgradable = append(upgradable, issue)
+		}
+
+		fileContent, err := os.ReadFile(filePath)
+		if err != nil {
+			for _, issue := range upgradable {
+				plan.skip(issue.ID, issue.FixAdvice().PackageName, "manifest couldn't be read")
+			}
+		} else {
+			filePlan := PlanUpgradesInFile(filePath, fileContent, upgradable)
+			plan.Edit = filePlan.Edit
+			plan.Upgrades = filePlan.Upgrades
+			plan.Skipped = append(plan.Skipped, filePlan.Skipped...)
+		}
+
+		if plan.Edit != nil {
+			if folderPlan.Edit == nil {
+				folderPlan.Edit = &vulnmap.WorkspaceEdit{Changes: map[string][]vulnmap.TextEdit{}}
+			}
+			folderPlan.Edit.Changes[filePath] = plan.Edit.Changes[filePath]
+		}
+		folderPlan.Manifests = append(folderPlan.Manifests, ManifestUpgradePlan{FilePath: filePath, UpgradePlan: plan})
+	}
+	return folderPlan
+}
+
+func (p *UpgradePlan) skip(issueId string, packageName string, reason string) {
+	p.Skipped = append(p.Skipped, SkippedUpgrade{IssueId: issueId, PackageName: packageName, Reason: reason})
+}
+
+// directUpgrade returns the direct dependency and its target version from the upgrade path of the issue
+func directUpgrade(data vulnmap.OssIssueData) (packageName string, target string, ok bool) {
+	if !data.IsUpgradable || len(data.UpgradePath) < 2 {
+		return "", "", false
+	}
+	upgrade, isString := data.UpgradePath[1].(string)
+	if !isString {
+		return "", "", false
+	}
+	separator := strings.LastIndex(upgrade, "@")
+	if separator <= 0 || separator == len(upgrade)-1 {
+		return "", "", false
+	}
+	return upgrade[:separator], upgrade[separator+1:], true
+}
+
+func addUpgradeTarget(candidate *upgradeCandidate, issueId string, target string) *upgradeCandidate {
+	targetVersion, err := version.NewVersion(target)
+	if candidate == nil {
+		return &upgradeCandidate{target: target, version: targetVersion, issueIds: []string{issueId}, conflicted: err != nil}
+	}
+	candidate.issueIds = append(candidate.issueIds, issueId)
+	if candidate.conflicted || target == candidate.target {
+		return candidate
+	}
+	if err != nil || candidate.version == nil {
+		// targets that can't be compared can't be resolved to a single upgrade
+		candidate.conflicted = true
+		return candidate
+	}
+	if targetVersion.GreaterThan(candidate.version) {
+		candidate.target = target
+		candidate.version = targetVersion
+	}
+	return candidate
+}
+
+// npmVersionEdit replaces the version of the dependency in a package.json, keeping range operators like ^ or ~
+func npmVersionEdit(fileContent []byte, packageName string, target string) (vulnmap.TextEdit, string, bool) {
+	dependencyRegex := regexp.MustCompile(`"` + regexp.QuoteMeta(packageName) + `"\s*:\s*"([^"]*)"`)
+	lines := strings.Split(strings.ReplaceAll(string(fileContent), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		match := dependencyRegex.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+		currentVersion := line[match[2]:match[3]]
+		prefix := currentVersion[:len(currentVersion)-len(strings.TrimLeft(currentVersion, "^~>=<"))]
+		return vulnmap.TextEdit{
+			Range: vulnmap.Range{
+				Start: vulnmap.Position{Line: i, Character: match[2]},
+				End:   vulnmap.Position{Line: i, Character: match[3]},
+			},
+			NewText: fmt.Sprintf("%s%s", prefix, target),
+		}, currentVersion, true
+	}
+	return vulnmap.TextEdit{}, "", false
+}
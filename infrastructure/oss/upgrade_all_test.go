@@ -0,0 +1,168 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oss
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+const upgradeTestPackageJson = `{
+  "name": "goof",
+  "dependencies": {
+    "lodash": "^4.17.4",
+    "express": "4.12.4",
+    "tap": "5.8.0"
+  }
+}`
+
+func upgradableIssue(id string, packageName string, upgrade string) vulnmap.Issue {
+	return vulnmap.Issue{
+		ID:      id,
+		Product: product.ProductOpenSource,
+		AdditionalData: vulnmap.OssIssueData{
+			PackageName:  packageName,
+			IsUpgradable: upgrade != "",
+			UpgradePath:  []any{false, upgrade},
+		},
+	}
+}
+
+func Test_PlanUpgradesInFile_NpmWithTwoUpgradableDependencies(t *testing.T) {
+	manifest := "/goof/package.json"
+	issues := []vulnmap.Issue{
+		upgradableIssue("VULNMAP-JS-LODASH-1", "lodash", "lodash@4.17.11"),
+		upgradableIssue("VULNMAP-JS-LODASH-2", "lodash", "lodash@4.17.21"),
+		upgradableIssue("VULNMAP-JS-EXPRESS-1", "express", "express@4.19.2"),
+		upgradableIssue("VULNMAP-JS-TAP-1", "tap", ""),
+	}
+
+	plan := PlanUpgradesInFile(manifest, []byte(upgradeTestPackageJson), issues)
+
+	require.NotNil(t, plan.Edit)
+	assert.Equal(t, []vulnmap.TextEdit{
+		{
+			Range:   vulnmap.Range{Start: vulnmap.Position{Line: 4, Character: 16}, End: vulnmap.Position{Line: 4, Character: 22}},
+			NewText: "4.19.2",
+		},
+		{
+			Range:   vulnmap.Range{Start: vulnmap.Position{Line: 3, Character: 15}, End: vulnmap.Position{Line: 3, Character: 22}},
+			NewText: "^4.17.21",
+		},
+	}, plan.Edit.Changes[manifest])
+	assert.Equal(t, []PackageUpgrade{
+		{PackageName: "express", FromVersion: "4.12.4", ToVersion: "4.19.2", IssueIds: []string{"VULNMAP-JS-EXPRESS-1"}},
+		{PackageName: "lodash", FromVersion: "^4.17.4", ToVersion: "4.17.21", IssueIds: []string{"VULNMAP-JS-LODASH-1", "VULNMAP-JS-LODASH-2"}},
+	}, plan.Upgrades)
+	assert.Equal(t, []SkippedUpgrade{{IssueId: "VULNMAP-JS-TAP-1", PackageName: "tap", Reason: "no upgrade path available"}}, plan.Skipped)
+}
+
+func Test_PlanUpgradesInFile_ConflictingTargetsAreSkipped(t *testing.T) {
+	issues := []vulnmap.Issue{
+		upgradableIssue("VULNMAP-JS-LODASH-1", "lodash", "lodash@4.17.21"),
+		upgradableIssue("VULNMAP-JS-LODASH-2", "lodash", "lodash@latest"),
+	}
+
+	plan := PlanUpgradesInFile("/goof/package.json", []byte(upgradeTestPackageJson), issues)
+
+	assert.Nil(t, plan.Edit)
+	assert.Empty(t, plan.Upgrades)
+	assert.Equal(t, []SkippedUpgrade{
+		{IssueId: "VULNMAP-JS-LODASH-1", PackageName: "lodash", Reason: "conflicting upgrade targets"},
+		{IssueId: "VULNMAP-JS-LODASH-2", PackageName: "lodash", Reason: "conflicting upgrade targets"},
+	}, plan.Skipped)
+}
+
+func Test_PlanUpgradesInFile_UnsupportedManifest(t *testing.T) {
+	issues := []vulnmap.Issue{upgradableIssue("VULNMAP-JAVA-1", "org.example:lib", "org.example:lib@2.0.0")}
+
+	plan := PlanUpgradesInFile("/goof/pom.xml", []byte("<project/>"), issues)
+
+	assert.Nil(t, plan.Edit)
+	assert.Len(t, plan.Skipped, 1)
+}
+
+func Test_PlanUpgradesInFolder_CombinesEditsOfAllManifests(t *testing.T) {
+	dir := t.TempDir()
+	frontend := filepath.Join(dir, "frontend", "package.json")
+	backend := filepath.Join(dir, "backend", "package.json")
+	testutil.CreateFileOrFail(t, frontend, []byte(upgradeTestPackageJson))
+	testutil.CreateFileOrFail(t, backend, []byte(upgradeTestPackageJson))
+	inFile := func(issue vulnmap.Issue, path string) vulnmap.Issue {
+		issue.AffectedFilePath = path
+		return issue
+	}
+	issues := []vulnmap.Issue{
+		inFile(upgradableIssue("VULNMAP-JS-LODASH-1", "lodash", "lodash@4.17.21"), frontend),
+		inFile(upgradableIssue("VULNMAP-JS-LODASH-1", "lodash", "lodash@4.17.21"), backend),
+		inFile(upgradableIssue("VULNMAP-JS-EXPRESS-1", "express", "express@4.19.2"), backend),
+		inFile(upgradableIssue("VULNMAP-JS-TAP-1", "tap", ""), backend),
+	}
+
+	plan := PlanUpgradesInFolder(issues, false)
+
+	require.NotNil(t, plan.Edit)
+	assert.Len(t, plan.Edit.Changes, 2)
+	assert.Len(t, plan.Edit.Changes[frontend], 1)
+	assert.Len(t, plan.Edit.Changes[backend], 2)
+	require.Len(t, plan.Manifests, 2)
+	assert.Equal(t, backend, plan.Manifests[0].FilePath)
+	assert.Len(t, plan.Manifests[0].Upgrades, 2)
+	assert.Equal(t, []SkippedUpgrade{{IssueId: "VULNMAP-JS-TAP-1", PackageName: "tap", Reason: "no upgrade path available"}},
+		plan.Manifests[0].Skipped)
+	assert.Equal(t, frontend, plan.Manifests[1].FilePath)
+	assert.Len(t, plan.Manifests[1].Upgrades, 1)
+}
+
+func Test_PlanUpgradesInFolder_SkipsMajorUpgradesUnlessIncluded(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), "package.json")
+	testutil.CreateFileOrFail(t, manifest, []byte(upgradeTestPackageJson))
+	issue := upgradableIssue("VULNMAP-JS-TAP-1", "tap", "tap@15.0.0")
+	issue.AffectedFilePath = manifest
+	data := issue.AdditionalData.(vulnmap.OssIssueData)
+	data.From = []string{"goof@1.0.0", "tap@5.8.0"}
+	issue.AdditionalData = data
+
+	plan := PlanUpgradesInFolder([]vulnmap.Issue{issue}, false)
+
+	assert.Nil(t, plan.Edit)
+	assert.Equal(t, []SkippedUpgrade{{IssueId: "VULNMAP-JS-TAP-1", PackageName: "tap", Reason: "major version upgrade"}},
+		plan.Manifests[0].Skipped)
+
+	plan = PlanUpgradesInFolder([]vulnmap.Issue{issue}, true)
+
+	require.NotNil(t, plan.Edit)
+	assert.Equal(t, "15.0.0", plan.Edit.Changes[manifest][0].NewText)
+}
+
+func Test_PlanUpgradesInFolder_UnreadableManifestIsSkipped(t *testing.T) {
+	issue := upgradableIssue("VULNMAP-JS-LODASH-1", "lodash", "lodash@4.17.21")
+	issue.AffectedFilePath = filepath.Join(t.TempDir(), "package.json")
+
+	plan := PlanUpgradesInFolder([]vulnmap.Issue{issue}, false)
+
+	assert.Nil(t, plan.Edit)
+	require.Len(t, plan.Manifests, 1)
+	assert.Equal(t, "manifest couldn't be read", plan.Manifests[0].Skipped[0].Reason)
+}
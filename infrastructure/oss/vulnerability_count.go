@@ -145,6 +145,10 @@ func (cliScanner *CLIScanner) removeVulnerabilityCountsFromCache(issues []vulnma
 	}
 }
 
+func (v *VulnerabilityCountInformation) Kind() vulnmap.InlineValueKind {
+	return vulnmap.InlineValueKindVulnerabilityCount
+}
+
 func (v *VulnerabilityCountInformation) Text() string {
 	text := fmt.Sprintf(
 		"Vulnerabilities: %d | Critical: %d, High: %d, Medium: %d, Low: %d | Most Severe: %s",
@@ -0,0 +1,301 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package persistence stores scan results on disk, so that they survive a restart of the language server.
+package persistence
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/adrg/xdg"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+// CacheVersion is the version of the on-disk format. Caches written with another version are discarded.
+const CacheVersion = 3
+
+// DefaultMaxCacheSize is the maximum size in bytes of all cache files
+const DefaultMaxCacheSize int64 = 50 * 1024 * 1024
+
+// lockFiles are hashed together with a file in the same directory, as they influence the results of a manifest
+var lockFiles = []string{
+	"Cargo.lock",
+	"Gemfile.lock",
+	"Pipfile.lock",
+	"composer.lock",
+	"go.sum",
+	"package-lock.json",
+	"packages.lock.json",
+	"pnpm-lock.yaml",
+	"poetry.lock",
+	"yarn.lock",
+}
+
+// IssueCache persists the issues of a folder. Cached issues of a file are only returned as long as the file
+// and the lock files next to it are unchanged.
+type IssueCache interface {
+	// Get returns the cached issues of the given file and true, if they are still valid
+	Get(folderPath string, filePath string) ([]vulnmap.Issue, bool)
+	// GetAll returns all valid cached issues of the folder by file and true, if all cached entries are still valid
+	GetAll(folderPath string) (map[string][]vulnmap.Issue, bool)
+	// Store replaces the cached issues of the folder
+	Store(folderPath string, issuesByFile map[string][]vulnmap.Issue) error
+	// Remove deletes the cached issues of the given file
+	Remove(folderPath string, filePath string) error
+	// Clear deletes all cached issues of the folder
+	Clear(folderPath string) error
+}
+
+type cacheEntry struct {
+	Hash   string           `json:"hash"`
+	Issues []persistedIssue `json:"issues"`
+}
+
+type cacheFile struct {
+	Version    int                    `json:"version"`
+	FolderPath string                 `json:"folderPath"`
+	Entries    map[string]*cacheEntry `json:"entries"`
+}
+
+type fileIssueCache struct {
+	dir          string
+	maxSizeBytes int64
+	folders      map[string]*cacheFile
+	mutex        sync.Mutex
+}
+
+// DefaultCacheDir returns the directory where issue caches are stored by default
+func DefaultCacheDir() string {
+	return filepath.Join(xdg.CacheHome, "vulnmap-ls", "issues")
+}
+
+// NewFileIssueCache returns an IssueCache that writes one file per folder into dir. If the cache files exceed
+// maxSizeBytes in total, the least recently written ones are evicted.
+func NewFileIssueCache(dir string, maxSizeBytes int64) IssueCache {
+	return &fileIssueCache{
+		dir:          dir,
+		maxSizeBytes: maxSizeBytes,
+		folders:      map[string]*cacheFile{},
+	}
+}
+
+func (c *fileIssueCache) Get(folderPath string, filePath string) ([]vulnmap.Issue, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.load(folderPath).Entries[filePath]
+	if !ok || !isValid(filePath, entry) {
+		return nil, false
+	}
+	return toIssues(entry.Issues), true
+}
+
+func (c *fileIssueCache) GetAll(folderPath string) (map[string][]vulnmap.Issue, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	folder := c.load(folderPath)
+	issuesByFile := make(map[string][]vulnmap.Issue, len(folder.Entries))
+	allValid := len(folder.Entries) > 0
+	for filePath, entry := range folder.Entries {
+		if !isValid(filePath, entry) {
+			allValid = false
+			continue
+		}
+		issuesByFile[filePath] = toIssues(entry.Issues)
+	}
+	return issuesByFile, allValid
+}
+
+func (c *fileIssueCache) Store(folderPath string, issuesByFile map[string][]vulnmap.Issue) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	folder := &cacheFile{Version: CacheVersion, FolderPath: folderPath, Entries: map[string]*cacheEntry{}}
+	for filePath, issues := range issuesByFile {
+		hash, err := ContentHash(filePath)
+		if err != nil {
+			log.Debug().Err(err).Str("method", "fileIssueCache.Store").Str("file", filePath).Msg("not caching issues")
+			continue
+		}
+		folder.Entries[filePath] = &cacheEntry{Hash: hash, Issues: toPersistedIssues(issues)}
+	}
+	c.folders[folderPath] = folder
+	return c.write(folder)
+}
+
+func (c *fileIssueCache) Remove(folderPath string, filePath string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	folder := c.load(folderPath)
+	if _, ok := folder.Entries[filePath]; !ok {
+		return nil
+	}
+	delete(folder.Entries, filePath)
+	return c.write(folder)
+}
+
+func (c *fileIssueCache) Clear(folderPath string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.folders, folderPath)
+	err := os.Remove(c.cacheFilePath(folderPath))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "couldn't remove issue cache")
+	}
+	return nil
+}
+
+// load returns the cache of the folder, reading it from disk if necessary. Unreadable caches or caches with
+// another version are discarded.
+func (c *fileIssueCache) load(folderPath string) *cacheFile {
+	if folder, ok := c.folders[folderPath]; ok {
+		return folder
+	}
+	logger := log.With().Str("method", "fileIssueCache.load").Str("folder", folderPath).Logger()
+	folder := &cacheFile{Version: CacheVersion, FolderPath: folderPath, Entries: map[string]*cacheEntry{}}
+	c.folders[folderPath] = folder
+
+	cacheFilePath := c.cacheFilePath(folderPath)
+	bytes, err := os.ReadFile(cacheFilePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn().Err(err).Msg("couldn't read issue cache")
+		}
+		return folder
+	}
+
+	var persisted cacheFile
+	err = json.Unmarshal(bytes, &persisted)
+	if err != nil || persisted.Version != CacheVersion || persisted.FolderPath != folderPath {
+		logger.Debug().Err(err).Int("version", persisted.Version).Msg("discarding incompatible issue cache")
+		_ = os.Remove(cacheFilePath)
+		return folder
+	}
+	if persisted.Entries != nil {
+		folder.Entries = persisted.Entries
+	}
+	return folder
+}
+
+func (c *fileIssueCache) write(folder *cacheFile) error {
+	bytes, err := json.Marshal(folder)
+	if err != nil {
+		return errors.Wrap(err, "couldn't serialize issue cache")
+	}
+	err = os.MkdirAll(c.dir, 0700)
+	if err != nil {
+		return errors.Wrap(err, "couldn't create issue cache directory")
+	}
+	cacheFilePath := c.cacheFilePath(folder.FolderPath)
+	tmpFile := cacheFilePath + ".tmp"
+	err = os.WriteFile(tmpFile, bytes, 0600)
+	if err != nil {
+		return errors.Wrap(err, "couldn't write issue cache")
+	}
+	err = os.Rename(tmpFile, cacheFilePath)
+	if err != nil {
+		return errors.Wrap(err, "couldn't write issue cache")
+	}
+	c.evict()
+	return nil
+}
+
+// evict removes the least recently written cache files until the total size is within the limit
+func (c *fileIssueCache) evict() {
+	cacheFiles, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return
+	}
+	infos := make([]os.FileInfo, 0, len(cacheFiles))
+	var totalSize int64
+	for _, cacheFile := range cacheFiles {
+		info, statErr := os.Stat(cacheFile)
+		if statErr != nil {
+			continue
+		}
+		infos = append(infos, info)
+		totalSize += info.Size()
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+	for _, info := range infos {
+		if totalSize <= c.maxSizeBytes {
+			return
+		}
+		err = os.Remove(filepath.Join(c.dir, info.Name()))
+		if err != nil {
+			log.Warn().Err(err).Str("method", "fileIssueCache.evict").Str("file", info.Name()).Msg("couldn't evict issue cache")
+			continue
+		}
+		totalSize -= info.Size()
+		for folderPath := range c.folders {
+			if c.cacheFilePath(folderPath) == filepath.Join(c.dir, info.Name()) {
+				delete(c.folders, folderPath)
+			}
+		}
+	}
+}
+
+func (c *fileIssueCache) cacheFilePath(folderPath string) string {
+	hash := sha256.Sum256([]byte(folderPath))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+func isValid(filePath string, entry *cacheEntry) bool {
+	hash, err := ContentHash(filePath)
+	return err == nil && hash == entry.Hash
+}
+
+// ContentHash hashes the content of the file and of the lock files in the same directory
+func ContentHash(filePath string) (string, error) {
+	hash := sha256.New()
+	err := hashFile(hash, filePath)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Dir(filePath)
+	for _, lockFile := range lockFiles {
+		lockFilePath := filepath.Join(dir, lockFile)
+		if lockFilePath == filePath {
+			continue
+		}
+		err = hashFile(hash, lockFilePath)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func hashFile(hash io.Writer, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) { _ = file.Close() }(file)
+	_, _ = hash.Write([]byte(filepath.Base(filePath)))
+	_, err = io.Copy(hash, file)
+	return err
+}
@@ -0,0 +1,161 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package persistence
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func ossIssue(path string) vulnmap.Issue {
+	issueUrl, _ := url.Parse("https://vulnmap.khulnasoft.com/vuln/id1")
+	return vulnmap.Issue{
+		ID:                  "id1",
+		Severity:            vulnmap.High,
+		AffectedFilePath:    path,
+		Product:             product.ProductOpenSource,
+		IssueDescriptionURL: issueUrl,
+		CVEs:                []string{"CVE-2023-1234"},
+		AdditionalData:      vulnmap.OssIssueData{Key: "key", PackageName: "lodash", FixedIn: []string{"4.17.21"}},
+	}
+}
+
+func Test_IssueCache_StoreAndGetFromNewInstance(t *testing.T) {
+	folder := t.TempDir()
+	cacheDir := t.TempDir()
+	manifest := filepath.Join(folder, "package.json")
+	writeFile(t, manifest, "{}")
+	issue := ossIssue(manifest)
+
+	err := NewFileIssueCache(cacheDir, DefaultMaxCacheSize).Store(folder, map[string][]vulnmap.Issue{manifest: {issue}})
+	require.NoError(t, err)
+
+	issues, ok := NewFileIssueCache(cacheDir, DefaultMaxCacheSize).Get(folder, manifest)
+	require.True(t, ok)
+	require.Len(t, issues, 1)
+	assert.Equal(t, issue, issues[0])
+}
+
+func Test_IssueCache_InvalidatedWhenFileChanges(t *testing.T) {
+	folder := t.TempDir()
+	manifest := filepath.Join(folder, "package.json")
+	writeFile(t, manifest, "{}")
+	cache := NewFileIssueCache(t.TempDir(), DefaultMaxCacheSize)
+	require.NoError(t, cache.Store(folder, map[string][]vulnmap.Issue{manifest: {ossIssue(manifest)}}))
+
+	writeFile(t, manifest, `{"name": "changed"}`)
+
+	_, ok := cache.Get(folder, manifest)
+	assert.False(t, ok)
+	_, allValid := cache.GetAll(folder)
+	assert.False(t, allValid)
+}
+
+func Test_IssueCache_InvalidatedWhenLockFileChanges(t *testing.T) {
+	folder := t.TempDir()
+	manifest := filepath.Join(folder, "package.json")
+	writeFile(t, manifest, "{}")
+	writeFile(t, filepath.Join(folder, "package-lock.json"), "{}")
+	cache := NewFileIssueCache(t.TempDir(), DefaultMaxCacheSize)
+	require.NoError(t, cache.Store(folder, map[string][]vulnmap.Issue{manifest: {ossIssue(manifest)}}))
+	_, ok := cache.Get(folder, manifest)
+	require.True(t, ok)
+
+	writeFile(t, filepath.Join(folder, "package-lock.json"), `{"lockfileVersion": 3}`)
+
+	_, ok = cache.Get(folder, manifest)
+	assert.False(t, ok)
+}
+
+func Test_IssueCache_DiscardsOtherVersions(t *testing.T) {
+	folder := t.TempDir()
+	cacheDir := t.TempDir()
+	manifest := filepath.Join(folder, "package.json")
+	writeFile(t, manifest, "{}")
+	cache := NewFileIssueCache(cacheDir, DefaultMaxCacheSize).(*fileIssueCache)
+	require.NoError(t, cache.Store(folder, map[string][]vulnmap.Issue{manifest: {ossIssue(manifest)}}))
+	cacheFilePath := cache.cacheFilePath(folder)
+	content, err := os.ReadFile(cacheFilePath)
+	require.NoError(t, err)
+	writeFile(t, cacheFilePath, string(content[:len(content)-1])+`,"version":0}`)
+
+	_, ok := NewFileIssueCache(cacheDir, DefaultMaxCacheSize).Get(folder, manifest)
+
+	assert.False(t, ok)
+	assert.NoFileExists(t, cacheFilePath)
+}
+
+func Test_IssueCache_EvictsLeastRecentlyWrittenFolders(t *testing.T) {
+	cacheDir := t.TempDir()
+	folder1 := t.TempDir()
+	folder2 := t.TempDir()
+	manifest1 := filepath.Join(folder1, "package.json")
+	manifest2 := filepath.Join(folder2, "package.json")
+	writeFile(t, manifest1, "{}")
+	writeFile(t, manifest2, "{}")
+	cache := NewFileIssueCache(cacheDir, DefaultMaxCacheSize).(*fileIssueCache)
+	require.NoError(t, cache.Store(folder1, map[string][]vulnmap.Issue{manifest1: {ossIssue(manifest1)}}))
+	info, err := os.Stat(cache.cacheFilePath(folder1))
+	require.NoError(t, err)
+	older := info.ModTime().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(cache.cacheFilePath(folder1), older, older))
+	cache.maxSizeBytes = info.Size() + info.Size()/2
+
+	require.NoError(t, cache.Store(folder2, map[string][]vulnmap.Issue{manifest2: {ossIssue(manifest2)}}))
+
+	assert.NoFileExists(t, cache.cacheFilePath(folder1))
+	assert.FileExists(t, cache.cacheFilePath(folder2))
+	_, ok := cache.Get(folder1, manifest1)
+	assert.False(t, ok)
+}
+
+func Test_IssueCache_RemoveAndClear(t *testing.T) {
+	folder := t.TempDir()
+	manifest := filepath.Join(folder, "package.json")
+	otherManifest := filepath.Join(folder, "pom.xml")
+	writeFile(t, manifest, "{}")
+	writeFile(t, otherManifest, "<project/>")
+	cache := NewFileIssueCache(t.TempDir(), DefaultMaxCacheSize)
+	require.NoError(t, cache.Store(folder, map[string][]vulnmap.Issue{
+		manifest:      {ossIssue(manifest)},
+		otherManifest: {ossIssue(otherManifest)},
+	}))
+
+	require.NoError(t, cache.Remove(folder, manifest))
+	_, ok := cache.Get(folder, manifest)
+	assert.False(t, ok)
+	_, ok = cache.Get(folder, otherManifest)
+	assert.True(t, ok)
+
+	require.NoError(t, cache.Clear(folder))
+	_, ok = cache.Get(folder, otherManifest)
+	assert.False(t, ok)
+}
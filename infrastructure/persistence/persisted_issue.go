@@ -0,0 +1,193 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package persistence
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/product"
+)
+
+type persistedReference struct {
+	Title string `json:"title"`
+	Url   string `json:"url"`
+}
+
+type persistedRelatedLocation struct {
+	FilePath string        `json:"filePath"`
+	Range    vulnmap.Range `json:"range"`
+	Message  string        `json:"message,omitempty"`
+}
+
+// persistedIssue is the serializable form of an issue. Code actions are not persisted, as they can contain
+// deferred functions.
+type persistedIssue struct {
+	ID                  string                     `json:"id"`
+	DisplayID           string                     `json:"displayId,omitempty"`
+	Severity            vulnmap.Severity           `json:"severity"`
+	IssueType           vulnmap.Type               `json:"issueType"`
+	Range               vulnmap.Range              `json:"range"`
+	Message             string                     `json:"message"`
+	FormattedMessage    string                     `json:"formattedMessage"`
+	FormattedMessages   map[string]string          `json:"formattedMessages,omitempty"`
+	AffectedFilePath    string                     `json:"affectedFilePath"`
+	Product             product.Product            `json:"product"`
+	References          []persistedReference       `json:"references,omitempty"`
+	IssueDescriptionURL string                     `json:"issueDescriptionUrl,omitempty"`
+	LessonURL           string                     `json:"lessonUrl,omitempty"`
+	CodelensCommands    []vulnmap.CommandData      `json:"codelensCommands,omitempty"`
+	Ecosystem           string                     `json:"ecosystem,omitempty"`
+	CWEs                []string                   `json:"cwes,omitempty"`
+	CVEs                []string                   `json:"cves,omitempty"`
+	RelatedLocations    []persistedRelatedLocation `json:"relatedLocations,omitempty"`
+	AdditionalData      json.RawMessage            `json:"additionalData,omitempty"`
+}
+
+func toPersistedIssues(issues []vulnmap.Issue) []persistedIssue {
+	persistedIssues := make([]persistedIssue, 0, len(issues))
+	for _, issue := range issues {
+		persistedIssues = append(persistedIssues, toPersistedIssue(issue))
+	}
+	return persistedIssues
+}
+
+func toPersistedIssue(issue vulnmap.Issue) persistedIssue {
+	p := persistedIssue{
+		ID:                issue.ID,
+		DisplayID:         issue.DisplayID,
+		Severity:          issue.Severity,
+		IssueType:         issue.IssueType,
+		Range:             issue.Range,
+		Message:           issue.Message,
+		FormattedMessage:  issue.FormattedMessage,
+		FormattedMessages: issue.FormattedMessages,
+		AffectedFilePath:  issue.AffectedFilePath,
+		Product:           issue.Product,
+		CodelensCommands:  issue.CodelensCommands,
+		Ecosystem:         issue.Ecosystem,
+		CWEs:              issue.CWEs,
+		CVEs:              issue.CVEs,
+	}
+	for _, reference := range issue.References {
+		p.References = append(p.References, persistedReference{Title: reference.Title, Url: urlString(reference.Url)})
+	}
+	for _, location := range issue.RelatedLocations {
+		p.RelatedLocations = append(p.RelatedLocations,
+			persistedRelatedLocation{FilePath: location.FilePath, Range: location.Range, Message: location.Message})
+	}
+	p.IssueDescriptionURL = urlString(issue.IssueDescriptionURL)
+	p.LessonURL = urlString(issue.LessonURL)
+	if issue.AdditionalData != nil {
+		additionalData, err := json.Marshal(issue.AdditionalData)
+		if err != nil {
+			log.Debug().Err(err).Str("method", "toPersistedIssue").Str("issue", issue.ID).Msg("couldn't persist additional data")
+		} else {
+			p.AdditionalData = additionalData
+		}
+	}
+	return p
+}
+
+func toIssues(persistedIssues []persistedIssue) []vulnmap.Issue {
+	issues := make([]vulnmap.Issue, 0, len(persistedIssues))
+	for _, p := range persistedIssues {
+		issues = append(issues, p.toIssue())
+	}
+	return issues
+}
+
+func (p persistedIssue) toIssue() vulnmap.Issue {
+	issue := vulnmap.Issue{
+		ID:                  p.ID,
+		DisplayID:           p.DisplayID,
+		Severity:            p.Severity,
+		IssueType:           p.IssueType,
+		Range:               p.Range,
+		Message:             p.Message,
+		FormattedMessage:    p.FormattedMessage,
+		FormattedMessages:   p.FormattedMessages,
+		AffectedFilePath:    p.AffectedFilePath,
+		Product:             p.Product,
+		IssueDescriptionURL: parseUrl(p.IssueDescriptionURL),
+		LessonURL:           parseUrl(p.LessonURL),
+		CodelensCommands:    p.CodelensCommands,
+		Ecosystem:           p.Ecosystem,
+		CWEs:                p.CWEs,
+		CVEs:                p.CVEs,
+	}
+	for _, reference := range p.References {
+		issue.References = append(issue.References, vulnmap.Reference{Title: reference.Title, Url: parseUrl(reference.Url)})
+	}
+	for _, location := range p.RelatedLocations {
+		issue.RelatedLocations = append(issue.RelatedLocations,
+			vulnmap.RelatedLocation{FilePath: location.FilePath, Range: location.Range, Message: location.Message})
+	}
+	issue.AdditionalData = p.additionalData()
+	return issue
+}
+
+// additionalData restores the product specific additional data of the issue
+func (p persistedIssue) additionalData() any {
+	if len(p.AdditionalData) == 0 {
+		return nil
+	}
+	var err error
+	var additionalData any
+	switch p.Product {
+	case product.ProductCode:
+		var data vulnmap.CodeIssueData
+		err = json.Unmarshal(p.AdditionalData, &data)
+		additionalData = data
+	case product.ProductOpenSource:
+		var data vulnmap.OssIssueData
+		err = json.Unmarshal(p.AdditionalData, &data)
+		additionalData = data
+	case product.ProductInfrastructureAsCode:
+		var data vulnmap.IaCIssueData
+		err = json.Unmarshal(p.AdditionalData, &data)
+		additionalData = data
+	default:
+		return nil
+	}
+	if err != nil {
+		log.Debug().Err(err).Str("method", "additionalData").Str("issue", p.ID).Msg("couldn't restore additional data")
+		return nil
+	}
+	return additionalData
+}
+
+func urlString(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	return u.String()
+}
+
+func parseUrl(rawUrl string) *url.URL {
+	if rawUrl == "" {
+		return nil
+	}
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil
+	}
+	return u
+}
@@ -0,0 +1,45 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package persistence
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/vulnmap"
+)
+
+func Test_persistedIssue_RoundTrip(t *testing.T) {
+	issue := ossIssue("/folder/package.json")
+	issue.FormattedMessage = "lodash is vulnerable"
+	issue.FormattedMessages = map[string]string{"html": "<p>lodash is vulnerable</p>", "md": "**lodash** is vulnerable"}
+	issue.RelatedLocations = []vulnmap.RelatedLocation{{
+		FilePath: "/folder/packages/api/package.json",
+		Range:    vulnmap.Range{Start: vulnmap.Position{Line: 3, Character: 4}, End: vulnmap.Position{Line: 3, Character: 20}},
+		Message:  "lodash@4.17.20 is also introduced here",
+	}}
+
+	persisted, err := json.Marshal(toPersistedIssues([]vulnmap.Issue{issue}))
+	require.NoError(t, err)
+	var restored []persistedIssue
+	require.NoError(t, json.Unmarshal(persisted, &restored))
+
+	assert.Equal(t, []vulnmap.Issue{issue}, toIssues(restored))
+}
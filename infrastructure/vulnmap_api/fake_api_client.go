@@ -19,7 +19,8 @@ package vulnmap_api
 import "sync"
 
 const (
-	SastEnabledOperation = "sastEnabled"
+	SastEnabledOperation  = "sastEnabled"
+	EntitlementsOperation = "entitlements"
 )
 
 type FakeApiClient struct {
@@ -28,6 +29,10 @@ type FakeApiClient struct {
 	LocalCodeEngine LocalCodeEngine
 	AutofixEnabled  bool
 	ApiError        *VulnmapApiError
+	// EntitlementsByOrg contains the entitlements returned for each organization
+	EntitlementsByOrg map[string]map[string]bool
+	// EntitlementsError is returned by Entitlements if set
+	EntitlementsError *VulnmapApiError
 }
 
 var (
@@ -89,3 +94,11 @@ func (f *FakeApiClient) SastSettings() (SastResponse, error) {
 		AutofixEnabled: f.AutofixEnabled,
 	}, nil
 }
+
+func (f *FakeApiClient) Entitlements(organization string) (map[string]bool, error) {
+	f.addCall([]any{organization}, EntitlementsOperation)
+	if f.EntitlementsError != nil {
+		return nil, f.EntitlementsError
+	}
+	return f.EntitlementsByOrg[organization], nil
+}
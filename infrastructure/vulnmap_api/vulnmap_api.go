@@ -50,6 +50,8 @@ type SastResponse struct {
 
 type VulnmapApiClient interface {
 	SastSettings() (SastResponse, error)
+	// Entitlements returns the entitlements of the given organization by name, e.g. "infrastructureAsCode"
+	Entitlements(organization string) (map[string]bool, error)
 }
 
 type VulnmapApiError struct {
@@ -102,6 +104,24 @@ func (s *VulnmapApiClientImpl) SastSettings() (SastResponse, error) {
 	return response, nil
 }
 
+func (s *VulnmapApiClientImpl) Entitlements(organization string) (map[string]bool, error) {
+	method := "Entitlements"
+	log.Debug().Str("method", method).Msg("API: Getting entitlements")
+	responseBody, err := s.doCall("GET", "/org/"+url.PathEscape(organization)+"/entitlements", nil)
+	if err != nil {
+		log.Err(err).Str("method", method).Msg("error when calling entitlements endpoint")
+		return nil, err
+	}
+
+	var entitlements map[string]bool
+	if unmarshalErr := json.Unmarshal(responseBody, &entitlements); unmarshalErr != nil {
+		log.Err(unmarshalErr).Str("method", method).Msg("couldn't unmarshal entitlements")
+		return nil, unmarshalErr
+	}
+	log.Debug().Str("method", method).Msg("API: Done")
+	return entitlements, nil
+}
+
 func (s *VulnmapApiClientImpl) doCall(method string,
 	path string,
 	requestBody []byte,
@@ -0,0 +1,96 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/pflag"
+
+	"github.com/khulnasoft-lab/go-application-framework/pkg/configuration"
+	localworkflows "github.com/khulnasoft-lab/go-application-framework/pkg/local_workflows"
+	"github.com/khulnasoft-lab/go-application-framework/pkg/workflow"
+)
+
+const (
+	whoamiUserMeEndpoint = "/v1/user/me"
+	whoamiExperimental   = "experimental"
+	whoamiJson           = "json"
+)
+
+type userMe struct {
+	UserName *string `json:"username"`
+}
+
+// RegisterWhoamiWorkflow replaces the whoami workflow of the engine with one that returns a *VulnmapApiError with the
+// HTTP status code of failed requests, so that callers can tell authentication failures from transient server errors.
+// The output of the workflow is unchanged.
+func RegisterWhoamiWorkflow(engine workflow.Engine) error {
+	flags := pflag.NewFlagSet("whoami", pflag.ExitOnError)
+	flags.Bool(whoamiExperimental, false, "enable experimental whoAmI command")
+	flags.Bool(whoamiJson, false, "output in json format")
+	_, err := engine.Register(localworkflows.WORKFLOWID_WHOAMI, workflow.ConfigurationOptionsFromFlagset(flags), whoamiEntrypoint)
+	return err
+}
+
+func whoamiEntrypoint(invocationCtx workflow.InvocationContext, _ []workflow.Data) ([]workflow.Data, error) {
+	conf := invocationCtx.GetConfiguration()
+	if !conf.GetBool(whoamiExperimental) {
+		return nil, fmt.Errorf("set `--experimental` flag to enable whoAmI command")
+	}
+
+	url := conf.GetString(configuration.API_URL) + whoamiUserMeEndpoint
+	payload, err := fetchUserMe(invocationCtx.GetNetworkAccess().GetHttpClient(), url)
+	if err != nil {
+		return nil, err
+	}
+	var user userMe
+	if err = json.Unmarshal(payload, &user); err != nil {
+		return nil, fmt.Errorf("error while extracting user: %w", err)
+	}
+	if user.UserName == nil {
+		return nil, fmt.Errorf("error while extracting user: missing property 'username'")
+	}
+
+	typeId := workflow.NewTypeIdentifier(localworkflows.WORKFLOWID_WHOAMI, "whoami")
+	if conf.GetBool(whoamiJson) {
+		return []workflow.Data{workflow.NewData(typeId, "application/json", payload)}, nil
+	}
+	return []workflow.Data{workflow.NewData(typeId, "text/plain", *user.UserName)}, nil
+}
+
+func fetchUserMe(client *http.Client, url string) ([]byte, error) {
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching user: %w", err)
+	}
+	defer func(Body io.ReadCloser) { _ = Body.Close() }(response.Body)
+
+	if response.StatusCode == http.StatusUnauthorized {
+		return nil, NewVulnmapApiError(fmt.Sprintf("invalid API key (status %d)", response.StatusCode), response.StatusCode)
+	} else if response.StatusCode != http.StatusOK {
+		return nil, NewVulnmapApiError(fmt.Sprintf("request failed (status %d)", response.StatusCode), response.StatusCode)
+	}
+	payload, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading response body: %w", err)
+	}
+	return payload, nil
+}
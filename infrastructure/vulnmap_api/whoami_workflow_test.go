@@ -0,0 +1,54 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vulnmap_api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fetchUserMe_ReturnsTypedStatusErrors(t *testing.T) {
+	for _, statusCode := range []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusServiceUnavailable} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(statusCode)
+		}))
+
+		_, err := fetchUserMe(server.Client(), server.URL+whoamiUserMeEndpoint)
+		server.Close()
+
+		var apiError *VulnmapApiError
+		require.ErrorAs(t, err, &apiError)
+		assert.Equal(t, statusCode, apiError.StatusCode())
+	}
+}
+
+func Test_fetchUserMe_ReturnsPayload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, whoamiUserMeEndpoint, r.URL.Path)
+		_, _ = w.Write([]byte(`{"username":"user"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	payload, err := fetchUserMe(server.Client(), server.URL+whoamiUserMeEndpoint)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"username":"user"}`, string(payload))
+}
@@ -0,0 +1,129 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package clock abstracts reading the current time, so that time-dependent logic can be tested deterministically
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock returns the current time and runs functions after a duration has elapsed
+type Clock interface {
+	Now() time.Time
+	// AfterFunc calls f once the duration has elapsed on the clock
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a pending call started by AfterFunc
+type Timer interface {
+	// Stop prevents the call, it returns false if the call already happened or the timer was already stopped
+	Stop() bool
+}
+
+type realClock struct{}
+
+// New returns the system clock
+func New() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc calls f in its own goroutine once the duration has elapsed
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// FakeClock is a Clock for tests that only moves when it is set or advanced. Timers that are due fire synchronously
+// when the clock is moved, so that tests don't need to wait for them.
+type FakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	f        func()
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (f *FakeClock) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.now
+}
+
+// AfterFunc calls f once the clock was moved by at least the duration
+func (f *FakeClock) AfterFunc(d time.Duration, fn func()) Timer {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	timer := &fakeTimer{clock: f, deadline: f.now.Add(d), f: fn}
+	f.timers = append(f.timers, timer)
+	return timer
+}
+
+// Set sets the current time of the clock and fires the timers that are due
+func (f *FakeClock) Set(now time.Time) {
+	f.mutex.Lock()
+	f.now = now
+	due := f.removeDueTimers()
+	f.mutex.Unlock()
+	for _, fn := range due {
+		fn()
+	}
+}
+
+// Advance moves the current time of the clock forward by the duration and fires the timers that are due
+func (f *FakeClock) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// removeDueTimers removes the timers whose deadline has passed and returns their functions. Must be called with the
+// mutex held.
+func (f *FakeClock) removeDueTimers() []func() {
+	var due []func()
+	pending := f.timers[:0]
+	for _, timer := range f.timers {
+		if timer.deadline.After(f.now) {
+			pending = append(pending, timer)
+		} else {
+			due = append(due, timer.f)
+		}
+	}
+	f.timers = pending
+	return due
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
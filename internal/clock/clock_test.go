@@ -0,0 +1,71 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClock_OnlyMovesWhenSetOrAdvanced(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := NewFakeClock(start)
+	assert.Equal(t, start, fakeClock.Now())
+
+	fakeClock.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Minute), fakeClock.Now())
+
+	fakeClock.Set(start)
+	assert.Equal(t, start, fakeClock.Now())
+}
+
+func TestNew_ReturnsSystemTime(t *testing.T) {
+	before := time.Now()
+	now := New().Now()
+
+	assert.False(t, now.Before(before))
+	assert.False(t, now.After(time.Now()))
+}
+
+func TestFakeClock_FiresTimersOnceDue(t *testing.T) {
+	fakeClock := NewFakeClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	fired := 0
+	fakeClock.AfterFunc(time.Minute, func() { fired++ })
+
+	fakeClock.Advance(59 * time.Second)
+	assert.Equal(t, 0, fired)
+
+	fakeClock.Advance(time.Second)
+	assert.Equal(t, 1, fired)
+
+	fakeClock.Advance(time.Hour)
+	assert.Equal(t, 1, fired, "timers must only fire once")
+}
+
+func TestFakeClock_StoppedTimersDontFire(t *testing.T) {
+	fakeClock := NewFakeClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	fired := false
+	timer := fakeClock.AfterFunc(time.Minute, func() { fired = true })
+
+	assert.True(t, timer.Stop())
+	fakeClock.Advance(time.Hour)
+
+	assert.False(t, fired)
+	assert.False(t, timer.Stop())
+}
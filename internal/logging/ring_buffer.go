@@ -0,0 +1,61 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logging
+
+import (
+	"strings"
+	"sync"
+)
+
+// RingBuffer is a writer that retains the most recent log lines in memory. It is used when logs aren't written to a
+// file, so that users can still retrieve them.
+type RingBuffer struct {
+	m     sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+func NewRingBuffer(size int) *RingBuffer {
+	return &RingBuffer{lines: make([]string, size)}
+}
+
+func (r *RingBuffer) Write(p []byte) (n int, err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if len(r.lines) == 0 {
+		return len(p), nil
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		r.lines[r.next] = line
+		r.next = (r.next + 1) % len(r.lines)
+		if r.next == 0 {
+			r.full = true
+		}
+	}
+	return len(p), nil
+}
+
+// Lines returns the retained lines, oldest first
+func (r *RingBuffer) Lines() []string {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if !r.full {
+		return append([]string{}, r.lines[:r.next]...)
+	}
+	return append(append([]string{}, r.lines[r.next:]...), r.lines[:r.next]...)
+}
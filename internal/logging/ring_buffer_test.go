@@ -0,0 +1,41 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingBuffer_RetainsMostRecentLines(t *testing.T) {
+	buffer := NewRingBuffer(3)
+
+	_, _ = buffer.Write([]byte("line 1\n"))
+	_, _ = buffer.Write([]byte("line 2\nline 3\n"))
+	assert.Equal(t, []string{"line 1", "line 2", "line 3"}, buffer.Lines())
+
+	n, err := buffer.Write([]byte("line 4\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, len("line 4\n"), n)
+	assert.Equal(t, []string{"line 2", "line 3", "line 4"}, buffer.Lines())
+}
+
+func TestRingBuffer_EmptyBufferReturnsNoLines(t *testing.T) {
+	assert.Empty(t, NewRingBuffer(3).Lines())
+}
@@ -17,6 +17,8 @@
 package lsp
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	sglsp "github.com/sourcegraph/go-lsp"
 )
@@ -101,7 +103,7 @@ type Diagnostic struct {
 	*
 	* @since 3.15.0
 	 */
-	Tags []DiagnosticTag `json:"diagnosticTag,omitempty"`
+	Tags []DiagnosticTag `json:"tags,omitempty"`
 
 	/**
 	* An array of related diagnostic information, e.g. when symbol-names within
@@ -119,6 +121,13 @@ type Diagnostic struct {
 	Data any `json:"data,omitempty"`
 }
 
+// DiagnosticData is sent in the data field of a diagnostic if issue fingerprints are enabled
+type DiagnosticData struct {
+	Fingerprint string `json:"fingerprint"`
+	Product     string `json:"product"`
+	Severity    string `json:"severity"`
+}
+
 type DiagnosticTag int
 
 //goland:noinspection GoCommentStart
@@ -137,6 +146,10 @@ const (
 	* Clients are allowed to rendered diagnostics with this tag strike through.
 	 */
 	Deprecated DiagnosticTag = 2
+
+	// Upgradable is a Vulnmap specific tag for issues that are fixed in an available upgrade.
+	// It is outside the range defined by the protocol, so clients that don't know it ignore it.
+	Upgradable DiagnosticTag = 100
 )
 
 type DiagnosticRelatedInformation struct {
@@ -548,6 +561,150 @@ type Settings struct {
 	VulnmapCodeApi                 string               `json:"vulnmapCodeApi,omitempty"`
 	EnableVulnmapLearnCodeActions  string               `json:"enableVulnmapLearnCodeActions,omitempty"`
 	EnableAnalytics             bool                 `json:"enableAnalytics,omitempty"`
+	ScanChangedFilesOnly        string               `json:"scanChangedFilesOnly,omitempty"`
+	ChangedFilesBaseRef         string               `json:"changedFilesBaseRef,omitempty"`
+	AnonymizeAnalytics          string               `json:"anonymizeAnalytics,omitempty"`
+	PersistIssueCache           string               `json:"persistIssueCache,omitempty"`
+	// ProductDisplayOrder contains product codenames (oss, code, iac) in the order results should be displayed
+	ProductDisplayOrder []string `json:"productDisplayOrder,omitempty"`
+	// SuppressionPolicyPath is the path of a policy file with suppressed CVEs, packages and CWEs
+	SuppressionPolicyPath string `json:"suppressionPolicyPath,omitempty"`
+	// RemapLockfileIssuesToManifest reports issues of direct dependencies found via a lockfile on the manifest
+	RemapLockfileIssuesToManifest string `json:"remapLockfileIssuesToManifest,omitempty"`
+	// LearnLessonLookupConcurrency is the number of parallel learn lesson lookups when converting scan results
+	LearnLessonLookupConcurrency string `json:"learnLessonLookupConcurrency,omitempty"`
+	// IncludeIssueFingerprints adds a stable issue fingerprint to the data field of published diagnostics
+	IncludeIssueFingerprints string `json:"includeIssueFingerprints,omitempty"`
+	// ScanFileMappings maps file names and extension patterns (e.g. "*.tf") to product codenames (oss, code, iac)
+	// that should scan such files, in addition to the files supported by the products
+	ScanFileMappings map[string][]string `json:"scanFileMappings,omitempty"`
+	// OssOutputVersion pins the version of the Open Source JSON output requested from the CLI. It is only passed to CLI
+	// versions that support it, empty uses the CLI's default output
+	OssOutputVersion string `json:"ossOutputVersion,omitempty"`
+	// NotificationLevel determines which messages are shown to the user (silent, errors, warnings or all)
+	NotificationLevel string `json:"notificationLevel,omitempty"`
+	// CustomHttpHeaders are added to outbound requests, e.g. analytics and learn lookups
+	CustomHttpHeaders map[string]string `json:"customHttpHeaders,omitempty"`
+	// MaxIssuesPerScan caps the number of issues reported by a single scan, 0 means unlimited
+	MaxIssuesPerScan string `json:"maxIssuesPerScan,omitempty"`
+	// ShowOnlyFixable hides issues that can't be fixed by an upgrade, a patch or an automatic fix
+	ShowOnlyFixable string `json:"showOnlyFixable,omitempty"`
+	// IncludeMajorUpgradeFixes considers issues fixable that require a major version upgrade, defaults to true
+	IncludeMajorUpgradeFixes string `json:"includeMajorUpgradeFixes,omitempty"`
+	// EnableFileDeletionWatcher watches trusted folders to clear the diagnostics of files deleted outside the IDE
+	EnableFileDeletionWatcher string `json:"enableFileDeletionWatcher,omitempty"`
+	// IdleLogoutTimeout is the duration without activity after which the user is logged out, e.g. "30m". "0" disables it
+	IdleLogoutTimeout string `json:"idleLogoutTimeout,omitempty"`
+	// InlineValueKinds contains the kinds of inline values to show, e.g. "vulnerabilityCount". Empty shows all kinds
+	InlineValueKinds []string `json:"inlineValueKinds,omitempty"`
+	// EnableBaseline hides the issues accepted by the recorded baseline, so that only new issues are shown
+	EnableBaseline string `json:"enableBaseline,omitempty"`
+	// BaselinePath is the file the baseline of each workspace folder is persisted to, relative to the folder
+	BaselinePath string `json:"baselinePath,omitempty"`
+	// AcceptedIssuesPath is the file the accepted issues of each workspace folder are persisted to, relative to the
+	// folder
+	AcceptedIssuesPath string `json:"acceptedIssuesPath,omitempty"`
+	// IssueTagsPath is the file the issue tags of each workspace folder are persisted to, relative to the folder
+	IssueTagsPath string `json:"issueTagsPath,omitempty"`
+	// FilePathDisplay is "relative" to show file paths relative to the folder root in messages and reports, or
+	// "absolute", which is the default
+	FilePathDisplay string `json:"filePathDisplay,omitempty"`
+	// LicenseSeverities maps license identifiers, e.g. "GPL-3.0", to the severity of license issues with that
+	// license ("critical", "high", "medium" or "low"), overriding the severity reported by Vulnmap
+	LicenseSeverities map[string]string `json:"licenseSeverities,omitempty"`
+	// RegistryCertificates maps package managers ("npm", "yarn" or "pip") to the CA certificate file of their
+	// private registry, which is trusted when the CLI resolves dependencies
+	RegistryCertificates map[string]string `json:"registryCertificates,omitempty"`
+	// LinkStyle determines how links are rendered in issue messages ("markdown", "url" or "text")
+	LinkStyle string `json:"linkStyle,omitempty"`
+	// DoNotTrack opts out of all telemetry and analytics regardless of the other settings, like DO_NOT_TRACK=1
+	DoNotTrack string `json:"doNotTrack,omitempty"`
+	// MaxMessageLength caps the number of characters of diagnostic messages, the hover keeps the full message.
+	// 0 means no truncation
+	MaxMessageLength string `json:"maxMessageLength,omitempty"`
+	// ProjectIssuesTargetFile is the file, absolute or relative to the folder, that issues without a file (e.g.
+	// project-level or base image findings) are reported on. Defaults to the primary manifest of the folder
+	ProjectIssuesTargetFile string `json:"projectIssuesTargetFile,omitempty"`
+	// IssueIdentifier is the displayed id of open source issues ("vulnmap" or "cve"). With "cve", the first CVE of the
+	// vulnerability is displayed if it has one, the issues are still identified by their Vulnmap id
+	IssueIdentifier string `json:"issueIdentifier,omitempty"`
+	// IssueUrlTemplates maps products ("oss", "code" or "iac") to the web page of their issues, in which "{id}" is
+	// replaced by the issue id. The page is opened by the "open in browser" code actions
+	IssueUrlTemplates map[string]string `json:"issueUrlTemplates,omitempty"`
+	// SequentialProductScans runs the products of a scan one after another instead of in parallel
+	SequentialProductScans string `json:"sequentialProductScans,omitempty"`
+	// ProductScanOrder contains product codenames (oss, code, iac) in the order their scans are started
+	ProductScanOrder []string `json:"productScanOrder,omitempty"`
+	// IncludeLineOwners annotates the additional data of issues in git repositories with the author and commit that
+	// last changed the affected line
+	IncludeLineOwners string `json:"includeLineOwners,omitempty"`
+	// ScanDebounce is the time scans triggered by saving a file wait for further saves, e.g. "500ms". "0" disables it
+	ScanDebounce string `json:"scanDebounce,omitempty"`
+	// AllProjects lets Open Source scans discover all projects of a folder, e.g. sub-projects of a monorepo
+	AllProjects string `json:"allProjects,omitempty"`
+	// AllProjectsDetectionDepth limits how many directory levels below a folder projects are discovered
+	AllProjectsDetectionDepth string `json:"allProjectsDetectionDepth,omitempty"`
+	// AllProjectsExclude contains directory and file names that are skipped when discovering projects
+	AllProjectsExclude []string `json:"allProjectsExclude,omitempty"`
+	// EnableManifestSummary publishes a diagnostic counting the vulnerabilities at the top of each manifest
+	EnableManifestSummary string `json:"enableManifestSummary,omitempty"`
+	// AnalyticsPathPrivacy determines how file paths are included in telemetry ("full", "relative", "hashed" or "omitted")
+	AnalyticsPathPrivacy string `json:"analyticsPathPrivacy,omitempty"`
+	// UntrustedFolderBehavior determines what happens when a scan of an untrusted folder is skipped ("skip" or "prompt")
+	UntrustedFolderBehavior string `json:"untrustedFolderBehavior,omitempty"`
+	// MaxResultAge is the age after which results are marked as possibly outdated, e.g. "24h". "0" never marks them
+	MaxResultAge string `json:"maxResultAge,omitempty"`
+	// MinEpssScore hides Open Source issues with a lower EPSS score, between "0" and "1". "0" shows all issues
+	MinEpssScore string `json:"minEpssScore,omitempty"`
+	// AnalyticsEndpoint is the base URL all telemetry is sent to, e.g. a proxy. Empty uses the default endpoints
+	AnalyticsEndpoint string `json:"analyticsEndpoint,omitempty"`
+	// AnalyticsDeduplicationWindow is the time in which at most one scan done event per folder and product is sent,
+	// e.g. "30s". "0" sends an event for every scan
+	AnalyticsDeduplicationWindow string `json:"analyticsDeduplicationWindow,omitempty"`
+	// DiagnosticsPublishConcurrency is the number of files whose diagnostics are published in parallel, "1" publishes
+	// them sequentially
+	DiagnosticsPublishConcurrency string `json:"diagnosticsPublishConcurrency,omitempty"`
+	// CliMaxMemory is the maximum heap size of the CLI in megabytes, e.g. "8192". "0" uses the CLI's default
+	CliMaxMemory string `json:"cliMaxMemory,omitempty"`
+	// DiagnosticsCloseGracePeriod is the time the diagnostics of a closed file are kept, e.g. "5m". "0" clears them
+	// right away, "never" keeps them until the file is rescanned or deleted
+	DiagnosticsCloseGracePeriod string `json:"diagnosticsCloseGracePeriod,omitempty"`
+	// DevDependencyIssues determines how issues in development dependencies are treated ("show", "tag", "downgrade"
+	// or "hide"). Tagging and downgrading apply from the next scan
+	DevDependencyIssues string `json:"devDependencyIssues,omitempty"`
+	// OutputFormats contains the output formats ("md" or "html") by rendering context ("hover", "diagnostic" or
+	// "export"). Contexts that aren't contained use the global format
+	OutputFormats map[string]string `json:"outputFormats,omitempty"`
+	// UnknownProductCounting determines how issues without a product are counted in the severity counts of a scan
+	// ("keep", "drop" or "infer")
+	UnknownProductCounting string `json:"unknownProductCounting,omitempty"`
+	// AdvisoryIdentifierPreference determines which advisory identifiers of open source issues are rendered ("cve",
+	// "ghsa" or "all"). It applies from the next scan
+	AdvisoryIdentifierPreference string `json:"advisoryIdentifierPreference,omitempty"`
+	// DiagnosticsPublishThrottle is the minimum time between two diagnostics publishes of a folder, e.g. "1s".
+	// Publishes within it are coalesced. "0" disables it
+	DiagnosticsPublishThrottle string `json:"diagnosticsPublishThrottle,omitempty"`
+	// AttestationSigningKeyPath is the PEM file of the PKCS #8 private key (Ed25519, ECDSA or RSA) exported
+	// attestations are signed with
+	AttestationSigningKeyPath string `json:"attestationSigningKeyPath,omitempty"`
+	// ManifestLockfileDuplicates determines how issues found in both a manifest and its lockfile are reported ("keep"
+	// or "collapse" to the manifest). It applies from the next scan
+	ManifestLockfileDuplicates string `json:"manifestLockfileDuplicates,omitempty"`
+	// ScanOnStartup determines if workspace folders are scanned when they are added ("true", the default). If
+	// "false", they are scanned on demand or when a file of theirs is opened
+	ScanOnStartup string `json:"scanOnStartup,omitempty"`
+	// IssueTagFilter only shows issues that are tagged with at least one of the tags, an empty list shows all issues
+	IssueTagFilter []string `json:"issueTagFilter,omitempty"`
+	// EscalateExploitSeverity raises the severity of open source issues with a mature exploit by one
+	// level ("true" or "false", the default). It applies from the next scan
+	EscalateExploitSeverity string `json:"escalateExploitSeverity,omitempty"`
+	// SeverityMapping replaces the mapping of the severities reported for open source issues to issue severities
+	// ("critical", "high", "medium" or "low"), e.g. {"moderate": "medium"}. An empty map restores the default mapping.
+	// It applies from the next scan
+	SeverityMapping map[string]string `json:"severityMapping,omitempty"`
+	// UnknownSeverityPolicy determines the severity of open source issues whose severity isn't mapped ("low", the
+	// default, "medium" or "unknown"). It applies from the next scan
+	UnknownSeverityPolicy string `json:"unknownSeverityPolicy,omitempty"`
 }
 
 type AuthenticationMethod string
@@ -1010,6 +1167,8 @@ const (
 	InProgress  ScanStatus = "inProgress"
 	Success     ScanStatus = "success"
 	ErrorStatus ScanStatus = "error"
+	// Pending means that the folder was not scanned yet, as scanning on startup is disabled
+	Pending ScanStatus = "pending"
 )
 
 // VulnmapScanParams is the type for the $/vulnmap/scan message
@@ -1020,8 +1179,21 @@ type VulnmapScanParams struct {
 	Product string `json:"product"`
 	// FolderPath is the root-folder of the current scan
 	FolderPath string `json:"folderPath"`
+	// ScanId is the id of the scan ticket returned by the trigger scan command, empty for other scans
+	ScanId string `json:"scanId,omitempty"`
 	// Issues contain the scan results in the common issues model
 	Issues []ScanIssue `json:"issues"`
+	// SeverityCount counts the issues by severity after filtering, it is only sent with the Success status and is
+	// updated without a scan when the filters change
+	SeverityCount *ScanSeverityCount `json:"severityCount,omitempty"`
+}
+
+// ScanSeverityCount counts the issues of a scan by severity
+type ScanSeverityCount struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
 }
 
 type ScanIssue struct { // TODO - convert this to a generic type
@@ -1031,6 +1203,257 @@ type ScanIssue struct { // TODO - convert this to a generic type
 	Severity       string `json:"severity"`
 	FilePath       string `json:"filePath"`
 	AdditionalData any    `json:"additionalData,omitempty"`
+	// Tags are the labels users attached to the issue
+	Tags []string `json:"tags,omitempty"`
+}
+
+// IssueSearchResult is an issue returned by the search issues command
+type IssueSearchResult struct {
+	Id          string      `json:"id"`
+	Title       string      `json:"title"`
+	Severity    string      `json:"severity"`
+	Product     string      `json:"product"`
+	FilePath    string      `json:"filePath"`
+	Range       sglsp.Range `json:"range"`
+	PackageName string      `json:"packageName,omitempty"`
+	CVEs        []string    `json:"cves,omitempty"`
+	CWEs        []string    `json:"cwes,omitempty"`
+	Score       int         `json:"score"`
+	// AdvisoryUpdated is the RFC 3339 timestamp of the last advisory modification or disclosure, empty if unknown
+	AdvisoryUpdated string `json:"advisoryUpdated,omitempty"`
+}
+
+// ScanGateResult is returned by the scan and gate command
+type ScanGateResult struct {
+	Passed     bool           `json:"passed"`
+	ExitCode   int            `json:"exitCode"`
+	Counts     map[string]int `json:"counts"`
+	Violations []string       `json:"violations,omitempty"`
+	Summary    string         `json:"summary"`
+}
+
+// FolderTrustStatus is returned by the get folder trust status command for each open folder
+type FolderTrustStatus struct {
+	FolderPath    string `json:"folderPath"`
+	Trusted       bool   `json:"trusted"`
+	TrustedPrefix string `json:"trustedPrefix,omitempty"`
+	Note          string `json:"note,omitempty"`
+}
+
+// UpgradeAllResult is returned by the upgrade all in file command
+type UpgradeAllResult struct {
+	Edit     *sglsp.WorkspaceEdit `json:"edit,omitempty"`
+	Upgrades []PackageUpgrade     `json:"upgrades"`
+	Skipped  []SkippedUpgrade     `json:"skipped"`
+}
+
+type PackageUpgrade struct {
+	PackageName string   `json:"packageName"`
+	FromVersion string   `json:"fromVersion"`
+	ToVersion   string   `json:"toVersion"`
+	IssueIds    []string `json:"issueIds"`
+}
+
+type SkippedUpgrade struct {
+	IssueId     string `json:"issueId"`
+	PackageName string `json:"packageName"`
+	Reason      string `json:"reason"`
+}
+
+// ApplyAllFixesResult is returned by the apply all fixes command, the edit spans all manifests of the folder
+type ApplyAllFixesResult struct {
+	Edit      *sglsp.WorkspaceEdit `json:"edit,omitempty"`
+	Manifests []ManifestUpgrades   `json:"manifests"`
+}
+
+// FilterPreviewResult is returned by the preview filter command. Hidden are the currently visible issues that the
+// previewed filter would hide, Shown the currently hidden issues it would show.
+type FilterPreviewResult struct {
+	HiddenCount int                 `json:"hiddenCount"`
+	ShownCount  int                 `json:"shownCount"`
+	Hidden      []IssueSearchResult `json:"hidden"`
+	Shown       []IssueSearchResult `json:"shown"`
+}
+
+// ManifestUpgrades are the upgrades and skipped issues of one manifest of an apply all fixes result
+type ManifestUpgrades struct {
+	FilePath string           `json:"filePath"`
+	Upgrades []PackageUpgrade `json:"upgrades"`
+	Skipped  []SkippedUpgrade `json:"skipped"`
+}
+
+// ManifestValidationResult is returned by the validate manifest command
+type ManifestValidationResult struct {
+	FilePath string            `json:"filePath"`
+	Warnings []ManifestWarning `json:"warnings"`
+}
+
+// ManifestWarning is a structural problem of a manifest, e.g. unparseable content or an out-of-sync lock file
+type ManifestWarning struct {
+	Message string      `json:"message"`
+	Range   sglsp.Range `json:"range"`
+}
+
+// ScanSnapshot contains the issues of a scan of a folder, e.g. a checkout of a git ref
+type ScanSnapshot struct {
+	// Root is the scanned folder. File paths of issues are compared relative to it.
+	Root   string          `json:"root,omitempty"`
+	Issues []SnapshotIssue `json:"issues"`
+}
+
+type SnapshotIssue struct {
+	// Id is the Vulnmap issue ID, e.g. VULNMAP-JS-LODASH-567746
+	Id          string `json:"id"`
+	Title       string `json:"title,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	FilePath    string `json:"filePath"`
+	PackageName string `json:"packageName,omitempty"`
+	Version     string `json:"version,omitempty"`
+}
+
+// CWEIssueGroup is returned by the issues by CWE command for each CWE of the workspace issues
+type CWEIssueGroup struct {
+	CWE    string              `json:"cwe"`
+	Count  int                 `json:"count"`
+	Issues []IssueSearchResult `json:"issues"`
+}
+
+// CVEOccurrence is returned by the find by CVE command for each issue of the workspace that references the CVE
+type CVEOccurrence struct {
+	FolderPath  string      `json:"folderPath"`
+	FilePath    string      `json:"filePath"`
+	Range       sglsp.Range `json:"range"`
+	Id          string      `json:"id"`
+	Title       string      `json:"title"`
+	Severity    string      `json:"severity"`
+	Product     string      `json:"product"`
+	PackageName string      `json:"packageName,omitempty"`
+	Version     string      `json:"version,omitempty"`
+}
+
+// EcosystemIssueCount is returned by the issues by ecosystem command for each ecosystem of the workspace issues,
+// severities are keyed by severity name
+type EcosystemIssueCount struct {
+	Ecosystem  string         `json:"ecosystem"`
+	Count      int            `json:"count"`
+	Severities map[string]int `json:"severities"`
+}
+
+// TokenPermissions is returned by the check token permissions command and reports what the token can be used for
+type TokenPermissions struct {
+	Authenticated bool `json:"authenticated"`
+	// Error explains why the token isn't authenticated or has no access to the organization
+	Error              string `json:"error,omitempty"`
+	Organization       string `json:"organization,omitempty"`
+	OrganizationAccess bool   `json:"organizationAccess"`
+	// Products reports for each product whether the organization is entitled to use it
+	Products     []ProductPermission `json:"products,omitempty"`
+	Entitlements map[string]bool     `json:"entitlements,omitempty"`
+}
+
+// ProductPermission reports whether a product can be used with the token, the reason explains why it can't
+type ProductPermission struct {
+	Product  string `json:"product"`
+	Entitled bool   `json:"entitled"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// AcceptedIssue is returned by the get accepted issues command and records why and by whom an issue was accepted
+type AcceptedIssue struct {
+	Fingerprint   string    `json:"fingerprint"`
+	IssueId       string    `json:"issueId"`
+	Justification string    `json:"justification"`
+	AcceptedBy    string    `json:"acceptedBy,omitempty"`
+	AcceptedAt    time.Time `json:"acceptedAt"`
+	// Expires is a date in the format YYYY-MM-DD after which the issue is shown again
+	Expires string `json:"expires,omitempty"`
+	Expired bool   `json:"expired"`
+}
+
+// IssueTags is returned by the issue tag commands and contains the labels users attached to an issue
+type IssueTags struct {
+	Fingerprint string   `json:"fingerprint"`
+	Tags        []string `json:"tags"`
+}
+
+// LogsResult is returned by the open logs command. It contains the log file path if logging to a file, otherwise the
+// most recent log lines.
+type LogsResult struct {
+	LogPath     string   `json:"logPath,omitempty"`
+	RecentLines []string `json:"recentLines,omitempty"`
+}
+
+// DiffScansResult is returned by the diff scans command
+type DiffScansResult struct {
+	Added     IssueDiffGroup `json:"added"`
+	Removed   IssueDiffGroup `json:"removed"`
+	Unchanged IssueDiffGroup `json:"unchanged"`
+}
+
+type IssueDiffGroup struct {
+	Count  int             `json:"count"`
+	Issues []SnapshotIssue `json:"issues"`
+}
+
+// IssueHover is returned by the get issue hover command
+type IssueHover struct {
+	Fingerprint string `json:"fingerprint"`
+	IssueId     string `json:"issueId"`
+	FilePath    string `json:"filePath"`
+	Message     string `json:"message"`
+}
+
+// ScanTicket is returned by the trigger scan command for the scans it started
+type ScanTicket struct {
+	ScanId       string   `json:"scanId"`
+	FolderPaths  []string `json:"folderPaths"`
+	Products     []string `json:"products,omitempty"`
+	ForceRefresh bool     `json:"forceRefresh"`
+}
+
+// SupportedEcosystem is a package manager returned by the get supported ecosystems command
+type SupportedEcosystem struct {
+	PackageManager string `json:"packageManager"`
+	DisplayName    string `json:"displayName"`
+	Ecosystem      string `json:"ecosystem"`
+}
+
+// ScanWarnings is returned by the get scan warnings command for each folder and product whose last scan reported
+// warnings
+type ScanWarnings struct {
+	FolderPath string   `json:"folderPath"`
+	Product    string   `json:"product"`
+	Warnings   []string `json:"warnings"`
+}
+
+// DependencyTree is returned by the get dependency tree command for each folder with vulnerable dependencies
+type DependencyTree struct {
+	FolderPath   string           `json:"folderPath"`
+	Dependencies []DependencyNode `json:"dependencies"`
+}
+
+// DependencyNode is a package of a dependency tree, the severity is the highest severity of the vulnerabilities of
+// the package and its dependencies
+type DependencyNode struct {
+	Package         string                    `json:"package"`
+	Severity        string                    `json:"severity"`
+	Vulnerabilities []DependencyVulnerability `json:"vulnerabilities,omitempty"`
+	Dependencies    []DependencyNode          `json:"dependencies,omitempty"`
+}
+
+// DependencyVulnerability is a vulnerability affecting a package of a dependency tree
+type DependencyVulnerability struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Severity string `json:"severity"`
+}
+
+// IssueDelta is returned by the get issue delta command for each product, counts are keyed by severity
+type IssueDelta struct {
+	Product string         `json:"product"`
+	Added   map[string]int `json:"added"`
+	Removed map[string]int `json:"removed"`
+	Net     map[string]int `json:"net"`
 }
 
 // Vulnmap Open Source
@@ -1054,6 +1477,13 @@ type OssIssueData struct {
 	ProjectName       string         `json:"projectName"`
 	DisplayTargetFile string         `json:"displayTargetFile"`
 	Details           string         `json:"details,omitempty"`
+	Owner             *LineOwner     `json:"owner,omitempty"`
+	EpssScore         string         `json:"epssScore,omitempty"`
+	// DisclosureTime and ModificationTime are RFC 3339 timestamps of the advisory, empty if unknown
+	DisclosureTime   string `json:"disclosureTime,omitempty"`
+	ModificationTime string `json:"modificationTime,omitempty"`
+	// FeedSeverity is the severity reported by the vulnerability feed, before it was escalated or downgraded
+	FeedSeverity string `json:"feedSeverity,omitempty"`
 }
 
 type OssIdentifiers struct {
@@ -1074,6 +1504,17 @@ type CodeIssueData struct {
 	Cols               Point              `json:"cols"`
 	Rows               Point              `json:"rows"`
 	IsSecurityType     bool               `json:"isSecurityType"`
+	DataFlow           []DataflowElement  `json:"dataFlow,omitempty"`
+	Owner              *LineOwner         `json:"owner,omitempty"`
+}
+
+// DataflowElement is a step of the data flow of a Vulnmap Code issue, the steps are ordered by position
+type DataflowElement struct {
+	Position  int         `json:"position"`
+	FilePath  string      `json:"filePath"`
+	FlowRange sglsp.Range `json:"flowRange"`
+	Content   string      `json:"content"`
+	Message   string      `json:"message,omitempty"`
 }
 
 type Point = [2]int
@@ -1109,12 +1550,19 @@ type CodeActionOptions struct {
 }
 
 type IacIssueData struct {
-	PublicId      string   `json:"publicId"`
-	Documentation string   `json:"documentation"`
-	LineNumber    int      `json:"lineNumber"`
-	Issue         string   `json:"issue"`
-	Impact        string   `json:"impact"`
-	Resolve       string   `json:"resolve,omitempty"`
-	Path          []string `json:"path"`
-	References    []string `json:"references,omitempty"`
+	PublicId      string     `json:"publicId"`
+	Documentation string     `json:"documentation"`
+	LineNumber    int        `json:"lineNumber"`
+	Issue         string     `json:"issue"`
+	Impact        string     `json:"impact"`
+	Resolve       string     `json:"resolve,omitempty"`
+	Path          []string   `json:"path"`
+	References    []string   `json:"references,omitempty"`
+	Owner         *LineOwner `json:"owner,omitempty"`
+}
+
+// LineOwner is the last commit of the line an issue affects
+type LineOwner struct {
+	Author    string `json:"author"`
+	CommitSha string `json:"commitSha"`
 }
@@ -0,0 +1,98 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	sglsp "github.com/sourcegraph/go-lsp"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
+)
+
+const DefaultShowMessageDeduplicationWindow = 10 * time.Second
+
+type suppressedMessage struct {
+	params sglsp.ShowMessageParams
+	count  int
+	timer  *time.Timer
+}
+
+// deduplicatingNotifier wraps a Notifier and suppresses identical ShowMessage notifications (including errors)
+// within a window. The first message is shown immediately, if it was repeated within the window, a single message
+// with the number of occurrences is shown when the window ends. Distinct messages are not delayed.
+type deduplicatingNotifier struct {
+	notification.Notifier
+	window time.Duration
+	mutex  sync.Mutex
+	shown  map[sglsp.ShowMessageParams]*suppressedMessage
+}
+
+func NewDeduplicatingNotifier(delegate notification.Notifier, window time.Duration) notification.Notifier {
+	return &deduplicatingNotifier{
+		Notifier: delegate,
+		window:   window,
+		shown:    map[sglsp.ShowMessageParams]*suppressedMessage{},
+	}
+}
+
+func (n *deduplicatingNotifier) SendShowMessage(messageType sglsp.MessageType, message string) {
+	if n.isDuplicate(sglsp.ShowMessageParams{Type: messageType, Message: message}) {
+		return
+	}
+	n.Notifier.SendShowMessage(messageType, message)
+}
+
+func (n *deduplicatingNotifier) SendError(err error) {
+	params := sglsp.ShowMessageParams{Type: sglsp.MTError, Message: fmt.Sprintf("Vulnmap encountered an error: %v", err)}
+	if n.isDuplicate(params) {
+		return
+	}
+	n.Notifier.SendError(err)
+}
+
+// isDuplicate returns true if the message was already shown within the window, and counts the occurrence
+func (n *deduplicatingNotifier) isDuplicate(params sglsp.ShowMessageParams) bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if shown, exists := n.shown[params]; exists {
+		shown.count++
+		return true
+	}
+	shown := &suppressedMessage{params: params, count: 1}
+	shown.timer = time.AfterFunc(n.window, func() { n.expire(shown) })
+	n.shown[params] = shown
+	return false
+}
+
+func (n *deduplicatingNotifier) expire(shown *suppressedMessage) {
+	n.mutex.Lock()
+	if n.shown[shown.params] != shown {
+		n.mutex.Unlock()
+		return
+	}
+	delete(n.shown, shown.params)
+	count := shown.count
+	n.mutex.Unlock()
+
+	if count > 1 {
+		n.Notifier.SendShowMessage(shown.params.Type, fmt.Sprintf("%s (occurred %d times)", shown.params.Message, count))
+	}
+}
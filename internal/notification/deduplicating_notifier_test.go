@@ -0,0 +1,75 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sglsp "github.com/sourcegraph/go-lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicatingNotifier_SameErrorFromSeveralFoldersIsShownOnce(t *testing.T) {
+	mockNotifier := NewMockNotifier()
+	n := NewDeduplicatingNotifier(mockNotifier, time.Minute)
+
+	// every folder fails to authenticate
+	for range []string{"folder1", "folder2", "folder3"} {
+		n.SendError(errors.New("Authentication failed"))
+	}
+
+	assert.Equal(t, 1, mockNotifier.SendErrorCount())
+	assert.Len(t, mockNotifier.SentMessages(), 1)
+}
+
+func TestDeduplicatingNotifier_DistinctMessagesAreShownImmediately(t *testing.T) {
+	mockNotifier := NewMockNotifier()
+	n := NewDeduplicatingNotifier(mockNotifier, time.Minute)
+
+	n.SendShowMessage(sglsp.Info, "scan finished")
+	n.SendShowMessage(sglsp.Warning, "scan finished")
+	n.SendError(errors.New("Authentication failed"))
+
+	assert.Equal(t, 2, mockNotifier.SendShowMessageCount())
+	assert.Equal(t, 1, mockNotifier.SendErrorCount())
+}
+
+func TestDeduplicatingNotifier_RepeatedMessageIsShownWithCountAfterWindow(t *testing.T) {
+	n := NewDeduplicatingNotifier(NewNotifier(), 10*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		n.SendShowMessage(sglsp.MTError, "Authentication failed")
+	}
+
+	first, _ := n.Receive()
+	assert.Equal(t, sglsp.ShowMessageParams{Type: sglsp.MTError, Message: "Authentication failed"}, first)
+	aggregated, _ := n.Receive()
+	assert.Equal(t, sglsp.ShowMessageParams{Type: sglsp.MTError, Message: "Authentication failed (occurred 3 times)"}, aggregated)
+}
+
+func TestDeduplicatingNotifier_MessageIsShownAgainAfterWindow(t *testing.T) {
+	mockNotifier := NewMockNotifier()
+	n := NewDeduplicatingNotifier(mockNotifier, time.Millisecond)
+
+	n.SendShowMessage(sglsp.Info, "scan finished")
+	time.Sleep(50 * time.Millisecond)
+	n.SendShowMessage(sglsp.Info, "scan finished")
+
+	assert.Equal(t, 2, mockNotifier.SendShowMessageCount())
+}
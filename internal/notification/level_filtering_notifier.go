@@ -0,0 +1,70 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	sglsp "github.com/sourcegraph/go-lsp"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/domain/ide/notification"
+)
+
+// levelFilteringNotifier wraps a Notifier and drops ShowMessage notifications (including errors) that are below the
+// configured notification level. Other notifications, e.g. diagnostics, and message requests that need an answer
+// from the user are always sent.
+type levelFilteringNotifier struct {
+	notification.Notifier
+}
+
+func NewLevelFilteringNotifier(delegate notification.Notifier) notification.Notifier {
+	return &levelFilteringNotifier{Notifier: delegate}
+}
+
+func (n *levelFilteringNotifier) SendShowMessage(messageType sglsp.MessageType, message string) {
+	if !isShown(messageType) {
+		return
+	}
+	n.Notifier.SendShowMessage(messageType, message)
+}
+
+func (n *levelFilteringNotifier) SendError(err error) {
+	if !isShown(sglsp.MTError) {
+		return
+	}
+	n.Notifier.SendError(err)
+}
+
+func (n *levelFilteringNotifier) Send(msg any) {
+	if params, isShowMessage := msg.(sglsp.ShowMessageParams); isShowMessage && !isShown(params.Type) {
+		return
+	}
+	n.Notifier.Send(msg)
+}
+
+// isShown returns whether messages of the given type are shown with the configured notification level
+func isShown(messageType sglsp.MessageType) bool {
+	switch config.CurrentConfig().NotificationLevel() {
+	case config.NotificationLevelSilent:
+		return false
+	case config.NotificationLevelErrors:
+		return messageType == sglsp.MTError
+	case config.NotificationLevelWarnings:
+		return messageType == sglsp.MTError || messageType == sglsp.MTWarning
+	default:
+		return true
+	}
+}
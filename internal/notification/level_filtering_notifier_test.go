@@ -0,0 +1,79 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"errors"
+	"testing"
+
+	sglsp "github.com/sourcegraph/go-lsp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khulnasoft-lab/vulnmap-ls/application/config"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/lsp"
+	"github.com/khulnasoft-lab/vulnmap-ls/internal/testutil"
+)
+
+func sendAllMessageTypes(n *levelFilteringNotifier) {
+	n.SendShowMessage(sglsp.Info, "scan finished")
+	n.SendShowMessage(sglsp.Log, "scan started")
+	n.SendShowMessage(sglsp.Warning, "CLI is outdated")
+	n.SendShowMessage(sglsp.MTError, "scan failed")
+	n.SendError(errors.New("authentication failed"))
+	n.Send(lsp.PublishDiagnosticsParams{URI: "file:///package.json"})
+}
+
+func TestLevelFilteringNotifier(t *testing.T) {
+	testCases := []struct {
+		level            config.NotificationLevel
+		showMessageCount int
+		sendErrorCount   int
+		sentMessageCount int
+	}{
+		// diagnostics are always published
+		{level: config.NotificationLevelSilent, showMessageCount: 0, sendErrorCount: 0, sentMessageCount: 1},
+		{level: config.NotificationLevelErrors, showMessageCount: 1, sendErrorCount: 1, sentMessageCount: 3},
+		{level: config.NotificationLevelWarnings, showMessageCount: 2, sendErrorCount: 1, sentMessageCount: 4},
+		{level: config.NotificationLevelAll, showMessageCount: 4, sendErrorCount: 1, sentMessageCount: 6},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.level), func(t *testing.T) {
+			c := testutil.UnitTest(t)
+			c.SetNotificationLevel(tc.level)
+			mockNotifier := NewMockNotifier()
+			n := NewLevelFilteringNotifier(mockNotifier).(*levelFilteringNotifier)
+
+			sendAllMessageTypes(n)
+
+			assert.Equal(t, tc.showMessageCount, mockNotifier.SendShowMessageCount())
+			assert.Equal(t, tc.sendErrorCount, mockNotifier.SendErrorCount())
+			assert.Len(t, mockNotifier.SentMessages(), tc.sentMessageCount)
+		})
+	}
+}
+
+func TestLevelFilteringNotifier_FiltersShowMessagesSentDirectly(t *testing.T) {
+	c := testutil.UnitTest(t)
+	c.SetNotificationLevel(config.NotificationLevelErrors)
+	mockNotifier := NewMockNotifier()
+	n := NewLevelFilteringNotifier(mockNotifier)
+
+	n.Send(sglsp.ShowMessageParams{Type: sglsp.Info, Message: "scan finished"})
+	n.Send(sglsp.ShowMessageParams{Type: sglsp.MTError, Message: "scan failed"})
+
+	assert.Equal(t, []any{sglsp.ShowMessageParams{Type: sglsp.MTError, Message: "scan failed"}}, mockNotifier.SentMessages())
+}
@@ -2,6 +2,7 @@ package notification
 
 import (
 	"fmt"
+	"sync"
 
 	sglsp "github.com/sourcegraph/go-lsp"
 
@@ -13,6 +14,7 @@ import (
 var _ notification.Notifier = &MockNotifier{}
 
 type MockNotifier struct {
+	mutex                      sync.Mutex
 	sendShowMessageCounter     int
 	sendCounter                int
 	sendErrorCounter           int
@@ -38,6 +40,8 @@ func (m *MockNotifier) DisposeListener() {
 func NewMockNotifier() *MockNotifier { return &MockNotifier{} }
 
 func (m *MockNotifier) SendShowMessage(messageType sglsp.MessageType, message string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.sendShowMessageCounter++
 	m.sentMessages = append(
 		m.sentMessages, sglsp.ShowMessageParams{
@@ -48,11 +52,15 @@ func (m *MockNotifier) SendShowMessage(messageType sglsp.MessageType, message st
 }
 
 func (m *MockNotifier) Send(msg any) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.sendCounter++
 	m.sentMessages = append(m.sentMessages, msg)
 }
 
 func (m *MockNotifier) SendError(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.sendErrorCounter++
 	m.sentMessages = append(
 		m.sentMessages, sglsp.ShowMessageParams{
@@ -63,6 +71,8 @@ func (m *MockNotifier) SendError(err error) {
 }
 
 func (m *MockNotifier) SendErrorDiagnostic(path string, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.sendErrorDiagnosticCounter++
 	msg := lsp.PublishDiagnosticsParams{
 		URI: uri.PathToUri(path),
@@ -77,12 +87,32 @@ func (m *MockNotifier) SendErrorDiagnostic(path string, err error) {
 	m.sentMessages = append(m.sentMessages, msg)
 }
 
-func (m *MockNotifier) SendShowMessageCount() int { return m.sendShowMessageCounter }
+func (m *MockNotifier) SendShowMessageCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.sendShowMessageCounter
+}
 
-func (m *MockNotifier) SendCount() int { return m.sendCounter }
+func (m *MockNotifier) SendCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.sendCounter
+}
 
-func (m *MockNotifier) SendErrorCount() int { return m.sendErrorCounter }
+func (m *MockNotifier) SendErrorCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.sendErrorCounter
+}
 
-func (m *MockNotifier) SendErrorDiagnosticCount() int { return m.sendErrorDiagnosticCounter }
+func (m *MockNotifier) SendErrorDiagnosticCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.sendErrorDiagnosticCounter
+}
 
-func (m *MockNotifier) SentMessages() []any { return m.sentMessages }
+func (m *MockNotifier) SentMessages() []any {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.sentMessages
+}
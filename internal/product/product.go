@@ -16,6 +16,8 @@
 
 package product
 
+import "sort"
+
 type Product string
 type ProductAttributes map[string]any
 type FilterableIssueType string
@@ -36,6 +38,22 @@ const (
 	FilterableIssueTypeContainer            FilterableIssueType = "Container"
 )
 
+// ToFilterableIssueTypes returns the filterable issue types of the issues the product reports
+func ToFilterableIssueTypes(product Product) []FilterableIssueType {
+	switch product {
+	case ProductOpenSource:
+		return []FilterableIssueType{FilterableIssueTypeOpenSource}
+	case ProductCode:
+		return []FilterableIssueType{FilterableIssueTypeCodeQuality, FilterableIssueTypeCodeSecurity}
+	case ProductInfrastructureAsCode:
+		return []FilterableIssueType{FilterableIssueTypeInfrastructureAsCode}
+	case ProductContainer:
+		return []FilterableIssueType{FilterableIssueTypeContainer}
+	default:
+		return nil
+	}
+}
+
 func ToProductCodename(product Product) string {
 	switch product {
 	case ProductOpenSource:
@@ -48,3 +66,43 @@ func ToProductCodename(product Product) string {
 		return ""
 	}
 }
+
+func FromProductCodename(codename string) Product {
+	switch codename {
+	case "oss":
+		return ProductOpenSource
+	case "code":
+		return ProductCode
+	case "iac":
+		return ProductInfrastructureAsCode
+	default:
+		return ProductUnknown
+	}
+}
+
+// SortByDisplayOrder sorts the products in the given display order. Products that are not part of the display
+// order are appended in alphabetical order.
+func SortByDisplayOrder(products []Product, displayOrder []Product) []Product {
+	rank := make(map[Product]int, len(displayOrder))
+	for i, p := range displayOrder {
+		if _, exists := rank[p]; !exists {
+			rank[p] = i
+		}
+	}
+
+	sorted := make([]Product, len(products))
+	copy(sorted, products)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		rankI, listedI := rank[sorted[i]]
+		rankJ, listedJ := rank[sorted[j]]
+		switch {
+		case listedI && listedJ:
+			return rankI < rankJ
+		case listedI != listedJ:
+			return listedI
+		default:
+			return sorted[i] < sorted[j]
+		}
+	})
+	return sorted
+}
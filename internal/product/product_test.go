@@ -0,0 +1,38 @@
+/*
+ * © 2023 Khulnasoft Limited All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package product
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SortByDisplayOrder(t *testing.T) {
+	products := []Product{ProductInfrastructureAsCode, ProductContainer, ProductOpenSource, ProductCode}
+
+	sorted := SortByDisplayOrder(products, []Product{ProductCode, ProductInfrastructureAsCode})
+
+	assert.Equal(t, []Product{ProductCode, ProductInfrastructureAsCode, ProductContainer, ProductOpenSource}, sorted)
+}
+
+func Test_ToFilterableIssueTypes(t *testing.T) {
+	assert.Equal(t, []FilterableIssueType{FilterableIssueTypeCodeQuality, FilterableIssueTypeCodeSecurity},
+		ToFilterableIssueTypes(ProductCode))
+	assert.Equal(t, []FilterableIssueType{FilterableIssueTypeOpenSource}, ToFilterableIssueTypes(ProductOpenSource))
+	assert.Empty(t, ToFilterableIssueTypes(ProductUnknown))
+}
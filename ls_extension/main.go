@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 
@@ -44,6 +46,11 @@ func Init(engine workflow.Engine) error {
 	flags.BoolP("v", "v", false, "prints the version")
 	flags.StringP("logLevelFlag", "l", "info", "sets the log-level to <trace|debug|info|warn|error|fatal>")
 	flags.StringP("logPathFlag", "f", "", "sets the log file for the language server")
+	flags.String(
+		"subsystemLogLevels",
+		"",
+		"overrides the log-level of subsystems, e.g. cli=debug,auth=trace. Subsystems: "+
+			strings.Join([]string{config.LogSubsystemCli, config.LogSubsystemAuth, config.LogSubsystemScan}, ", "))
 	flags.StringP(
 		"formatFlag",
 		"o",
@@ -53,7 +60,8 @@ func Init(engine workflow.Engine) error {
 		"configfile",
 		"c",
 		"",
-		"provide the full path of a config file to use. format VARIABLENAME=VARIABLEVALUE")
+		"provide the full path of a config file to use. format VARIABLENAME=VARIABLEVALUE. "+
+			"Multiple files can be separated by the OS path list separator, later files override earlier ones")
 	flags.Bool(
 		"licenses",
 		false,
@@ -81,9 +89,12 @@ func lsWorkflow(
 
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	c := config.CurrentConfig()
-	c.SetConfigFile(extensionConfig.GetString("configfile"))
+	c.SetConfigFiles(filepath.SplitList(extensionConfig.GetString("configfile")))
 	c.Load()
 	c.SetLogLevel(extensionConfig.GetString("logLevelFlag"))
+	if err = c.SetSubsystemLogLevels(extensionConfig.GetString("subsystemLogLevels")); err != nil {
+		logger.Warn().Err(err).Msg("ignoring subsystem log levels")
+	}
 	c.SetLogPath(extensionConfig.GetString("logPathFlag"))
 	c.SetFormat(extensionConfig.GetString("formatFlag"))
 
@@ -91,6 +102,9 @@ func lsWorkflow(
 	defaultConfig.Set(cli_constants.EXECUTION_MODE_KEY, cli_constants.EXECUTION_MODE_VALUE_EXTENSION)
 	c.SetEngine(invocation.GetEngine())
 	c.Engine().SetConfiguration(defaultConfig)
+	if err = c.RegisterReportAnalyticsWorkflow(); err != nil {
+		logger.Err(err).Msg("unable to register reportAnalytics workflow")
+	}
 
 	if extensionConfig.GetBool("v") {
 		fmt.Println(config.Version)
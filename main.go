@@ -21,6 +21,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/khulnasoft-lab/go-application-framework/pkg/utils"
 	"github.com/khulnasoft-lab/go-application-framework/pkg/workflow"
@@ -63,6 +65,11 @@ func parseFlags(args []string, c *config.Config) (string, error) {
 	versionFlag := flags.Bool("v", false, "prints the version")
 	logLevelFlag := flags.String("l", "info", "sets the log-level to <trace|debug|info|warn|error|fatal>")
 	logPathFlag := flags.String("f", "", "sets the log file for the language server")
+	subsystemLogLevelsFlag := flags.String(
+		"subsystemLogLevels",
+		"",
+		"overrides the log-level of subsystems, e.g. cli=debug,auth=trace. Subsystems: "+
+			strings.Join([]string{config.LogSubsystemCli, config.LogSubsystemAuth, config.LogSubsystemScan}, ", "))
 	formatFlag := flags.String(
 		"o",
 		config.FormatMd,
@@ -70,7 +77,8 @@ func parseFlags(args []string, c *config.Config) (string, error) {
 	configFlag := flags.String(
 		"c",
 		"",
-		"provide the full path of a config file to use. format VARIABLENAME=VARIABLEVALUE")
+		"provide the full path of a config file to use. format VARIABLENAME=VARIABLEVALUE. "+
+			"Multiple files can be separated by the OS path list separator, later files override earlier ones")
 	reportErrorsFlag := flags.Bool(
 		"reportErrors",
 		false,
@@ -97,9 +105,12 @@ func parseFlags(args []string, c *config.Config) (string, error) {
 		buf.Write([]byte(config.LicenseInformation))
 	}
 
-	c.SetConfigFile(*configFlag)
+	c.SetConfigFiles(filepath.SplitList(*configFlag))
 	c.Load()
 	c.SetLogLevel(*logLevelFlag)
+	if err = c.SetSubsystemLogLevels(*subsystemLogLevelsFlag); err != nil {
+		return buf.String(), err
+	}
 	c.SetLogPath(*logPathFlag)
 	c.SetFormat(*formatFlag)
 	if os.Getenv(config.SendErrorReportsKey) == "" {
@@ -38,6 +38,17 @@ func Test_shouldSetLogLevelViaFlag(t *testing.T) {
 	assert.Equal(t, zerolog.DebugLevel, zerolog.GlobalLevel())
 }
 
+func Test_shouldSetSubsystemLogLevelsViaFlag(t *testing.T) {
+	args := []string{"vulnmap-ls", "-l", "info", "-subsystemLogLevels", "cli=debug"}
+	c := config.New()
+
+	_, err := parseFlags(args, c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, zerolog.DebugLevel, c.SubsystemLogLevel(config.LogSubsystemCli))
+	assert.Equal(t, zerolog.InfoLevel, c.SubsystemLogLevel(config.LogSubsystemAuth))
+}
+
 func Test_shouldSetLogFileViaFlag(t *testing.T) {
 	args := []string{"vulnmap-ls", "-f", "a.txt"}
 	t.Cleanup(func() {